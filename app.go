@@ -0,0 +1,184 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/state"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+	"github.com/dancaldera/mirador/internal/views"
+)
+
+// Wrapper type to add methods to the imported Model
+type appModel struct {
+	models.Model
+	launch tea.Cmd // Connect to the connection named on the command line
+}
+
+func (m appModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, textarea.Blink, m.launch)
+}
+
+func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	// A dropped connection turns a failed result into an automatic reconnect
+	if updatedModel, lost := utils.DetectConnectionLoss(m.Model, msg); lost {
+		updatedModel, cmd := utils.ScheduleReconnect(updatedModel)
+		m.Model = updatedModel
+		return m, cmd
+	}
+
+	// Results of finished commands go to their handlers
+	if updated, cmd, ok := m.updateResult(msg); ok {
+		return updated, cmd
+	}
+
+	// Handle basic message types
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		h, _ := styles.DocStyle.GetFrameSize()
+
+		// Calculate proper list heights using ViewBuilder-aware function
+		dbTypeListHeight := utils.CalculateListViewportHeight(msg.Height, true, false)
+		m.DBTypeList.SetSize(msg.Width-h, dbTypeListHeight)
+
+		savedConnsListHeight := utils.CalculateListViewportHeight(msg.Height, true, m.IsConnecting || m.Err != nil || m.QueryResult != "")
+		m.SavedConnectionsList.SetSize(msg.Width-h, savedConnsListHeight)
+
+		tablesListHeight := utils.CalculateListViewportHeight(msg.Height, true, m.IsLoadingColumns)
+		m.TablesList.SetSize(msg.Width-h, tablesListHeight)
+		m.SchemasList.SetSize(msg.Width-h, tablesListHeight)
+
+		queryHistoryListHeight := utils.CalculateListViewportHeight(msg.Height, true, false)
+		m.QueryHistoryList.SetSize(msg.Width-h, queryHistoryListHeight)
+		// Resize RowDetailList when in RowDetailView state
+		if m.State == models.RowDetailView && len(m.RowDetailList.Items()) > 0 {
+			listHeight := utils.CalculateListViewportHeight(msg.Height, true, m.Err != nil || m.QueryResult != "")
+			m.RowDetailList.SetSize(msg.Width-h, listHeight)
+		}
+		m.TextInput.Width = msg.Width - h - 4
+		m.NameInput.Width = msg.Width - h - 4
+		m.ReplicaInput.Width = msg.Width - h - 4
+		m.TLSCAInput.Width = msg.Width - h - 4
+		m.TLSCertInput.Width = msg.Width - h - 4
+		m.TLSKeyInput.Width = msg.Width - h - 4
+		m.SQLCipherKeyInput.Width = msg.Width - h - 4
+		m.ServiceAccountKeyInput.Width = msg.Width - h - 4
+		m.CloudSQLInstanceInput.Width = msg.Width - h - 4
+		m.QueryInput.SetWidth(msg.Width - h - 4)
+		m.SearchInput.Width = msg.Width - h - 4
+
+		// Update textarea size for field editing
+		_, v := styles.DocStyle.GetFrameSize()
+		textareaWidth := utils.Max(msg.Width-h-4, 40)
+		textareaHeight := utils.Max(msg.Height-v-8, 5) // Reserve space for title and help text only
+		m.FieldTextarea.SetWidth(textareaWidth)
+		m.FieldTextarea.SetHeight(textareaHeight)
+
+		// Recompute data preview table to fill available space
+		if len(m.DataPreviewAllColumns) > 0 && len(m.DataPreviewAllRows) > 0 {
+			m.Model = utils.CreateDataPreviewTable(m.Model)
+		}
+
+	case tea.KeyMsg:
+		// A typed TRUNCATE/DROP confirmation captures every key except ctrl+c
+		if m.IsConfirmingDestructive && msg.String() != "ctrl+c" {
+			updatedModel, cmd := state.HandleTablesViewUpdate(m.Model, msg)
+			m.Model = updatedModel
+			return m, cmd
+		}
+
+		// Builder fields take free text, so a ? in a password or option is typed into them
+		if m.State == models.ConnectionView && m.ConnectionBuilderMode && msg.String() == "?" {
+			updatedModel, cmd := state.HandleConnectionViewUpdate(m.Model, msg)
+			m.Model = updatedModel
+			return m, cmd
+		}
+
+		// ? is a bind placeholder in the query editor and can be part of a bind value
+		if m.State == models.QueryView && (m.QueryInput.Focused() || m.IsBindingParams) && msg.String() == "?" {
+			updatedModel, cmd := state.HandleQueryViewUpdate(m.Model, msg)
+			m.Model = updatedModel
+			return m, cmd
+		}
+
+		// Prompts and searches take free text, so global keys like ? are typed into them
+		if s, ok := screens[m.State]; ok && s.typing != nil && s.typing(m.Model) && msg.String() != "ctrl+c" {
+			updatedModel, cmd := s.update(m.Model, msg)
+			m.Model = updatedModel
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			// A running query is cancelled first; ctrl+c again while the cancel is pending quits
+			if m.State == models.QueryView && m.IsExecutingQuery && !m.IsCancellingStatement {
+				updatedModel, cmd := state.HandleQueryViewUpdate(m.Model, msg)
+				m.Model = updatedModel
+				return m, cmd
+			}
+			m.Model = utils.SaveWorksheet(m.Model)
+			m.Model = utils.CloseDB(m.Model)
+			return m, tea.Quit
+
+		case "?":
+			// Toggle full help menu globally across all views
+			m.ShowFullHelp = !m.ShowFullHelp
+			return m, nil
+
+		case "ctrl+r":
+			// Reconnect after a dropped connection; otherwise views use ctrl+r to refresh
+			if m.ConnectionLost {
+				updatedModel, cmd := utils.ReconnectNow(m.Model)
+				m.Model = updatedModel
+				return m, cmd
+			}
+
+		case "ctrl+g":
+			// Toggle session safe mode globally across all views
+			m.SafeMode = !m.SafeMode
+			m.IsConfirmingSafeOverride = false
+			return m, nil
+
+		case "r":
+			// Navigate to QueryView from TablesView only
+			if m.State == models.TablesView {
+				m.Model = utils.PushView(m.Model, models.QueryView, models.NavParams{})
+				return m, nil
+			}
+		case "ctrl+h":
+			// Navigate to QueryHistoryView from TablesView and QueryView only
+			if m.State == models.TablesView || m.State == models.QueryView {
+				m.Model = utils.PushView(m.Model, models.QueryHistoryView, models.NavParams{})
+				return m, nil
+			}
+		}
+	}
+
+	// Update components according to state
+	if s, ok := screens[m.State]; ok {
+		updatedModel, cmd := s.update(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd
+	}
+
+	return m, cmd
+}
+
+func (m appModel) View() string {
+	return views.RenderStatusBanners(m.Model, m.renderState())
+}
+
+// renderState renders the view for the current state
+func (m appModel) renderState() string {
+	if s, ok := screens[m.State]; ok {
+		return s.view(m.Model)
+	}
+	return "View not implemented yet"
+}
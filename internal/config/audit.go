@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetAuditLogFile returns the path to the write audit log
+func GetAuditLogFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "audit.log"), nil
+}
+
+// AppendAuditEntry appends an entry to the audit log as a single JSON line.
+// The file is only ever appended to, never rewritten.
+func AppendAuditEntry(entry models.AuditEntry) error {
+	auditFile, err := GetAuditLogFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(auditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// LoadAuditEntries reads every entry from the audit log, oldest first
+func LoadAuditEntries() ([]models.AuditEntry, error) {
+	auditFile, err := GetAuditLogFile()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(auditFile)
+	if os.IsNotExist(err) {
+		return []models.AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []models.AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		// Skip lines that cannot be parsed instead of hiding the whole log
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
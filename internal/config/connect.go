@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultConnectRetries is how many times a connect that failed for a transient
+// reason is retried automatically
+const DefaultConnectRetries = 3
+
+// ConnectRetries returns the number of automatic connect retries. It can be set
+// with MIRADOR_CONNECT_RETRIES; 0 turns retries off.
+func ConnectRetries() int {
+	value, ok := os.LookupEnv("MIRADOR_CONNECT_RETRIES")
+	if !ok {
+		return DefaultConnectRetries
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return DefaultConnectRetries
+	}
+	return n
+}
+
+// DefaultHealthCheckInterval is how often a connected session is pinged to
+// show whether it is still alive
+const DefaultHealthCheckInterval = 15 * time.Second
+
+// HealthCheckInterval returns the time between connection health pings. It can
+// be set with MIRADOR_HEALTH_INTERVAL as a duration ("1m") or in seconds ("30");
+// 0 turns the pings off.
+func HealthCheckInterval() time.Duration {
+	value, ok := os.LookupEnv("MIRADOR_HEALTH_INTERVAL")
+	if !ok {
+		return DefaultHealthCheckInterval
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultHealthCheckInterval
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// GetFavoritesFile returns the path to the favorite tables file
+func GetFavoritesFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "favorites.json"), nil
+}
+
+func loadFavoritesStore() (map[string][]string, error) {
+	favoritesFile, err := GetFavoritesFile()
+	if err != nil {
+		return nil, err
+	}
+
+	store := map[string][]string{}
+	data, err := os.ReadFile(favoritesFile)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store); err != nil {
+		// Start over rather than failing on a corrupted store
+		return map[string][]string{}, nil
+	}
+	return store, nil
+}
+
+// LoadFavoriteTables returns the starred tables for a connection and schema.
+// Keys come from StoreKey.
+func LoadFavoriteTables(key string) ([]string, error) {
+	store, err := loadFavoritesStore()
+	if err != nil {
+		return nil, err
+	}
+	return store[key], nil
+}
+
+// SaveFavoriteTables replaces the starred tables for a connection and schema
+func SaveFavoriteTables(key string, tables []string) error {
+	store, err := loadFavoritesStore()
+	if err != nil {
+		return err
+	}
+
+	if len(tables) == 0 {
+		delete(store, key)
+	} else {
+		sorted := append([]string(nil), tables...)
+		sort.Strings(sorted)
+		store[key] = sorted
+	}
+
+	favoritesFile, err := GetFavoritesFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writePrivateFile(favoritesFile, data)
+}
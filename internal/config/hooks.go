@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Hooks are shell commands run on events, each given the event as JSON on
+// stdin. An empty command runs nothing.
+type Hooks struct {
+	OnConnect        string `json:"on_connect,omitempty"`
+	OnQueryError     string `json:"on_query_error,omitempty"`
+	OnExportComplete string `json:"on_export_complete,omitempty"`
+}
+
+// GetHooksFile returns the path to the hooks file
+func GetHooksFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "hooks.json"), nil
+}
+
+// LoadHooks reads the hooks file. It is written by hand, so unlike the other
+// stores a file that does not parse is reported rather than replaced.
+func LoadHooks() (Hooks, error) {
+	var hooks Hooks
+	hooksFile, err := GetHooksFile()
+	if err != nil {
+		return hooks, err
+	}
+
+	data, err := os.ReadFile(hooksFile)
+	if os.IsNotExist(err) {
+		return hooks, nil
+	}
+	if err != nil {
+		return hooks, err
+	}
+
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return Hooks{}, fmt.Errorf("invalid %s: %w", hooksFile, err)
+	}
+	return hooks, nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Saved connection strings are kept in the OS keychain when one is available,
+// and connections.json holds only keychain:<account> in their place, where the
+// account is the connection name, with " (replica)" for its read replica.
+// Each platform file provides keychainAvailable, keychainGet, keychainSet, and
+// keychainDelete; without a keychain the strings stay in the file.
+const (
+	keychainPrefix  = "keychain:"
+	keychainService = "mirador"
+)
+
+// ErrKeychainNotFound is returned when the keychain holds no secret for a connection
+var ErrKeychainNotFound = errors.New("secret not found in the keychain")
+
+// keychainCache remembers the secret last read from or written to the keychain
+// for each connection, so saves only write the ones that changed
+var keychainCache = struct {
+	sync.Mutex
+	secrets map[string]string
+}{secrets: map[string]string{}}
+
+// IsKeychainReference reports whether a stored connection string points into the keychain
+func IsKeychainReference(value string) bool {
+	return strings.HasPrefix(value, keychainPrefix)
+}
+
+// hasKeychain caches whether the platform keychain can be reached
+var hasKeychain = sync.OnceValue(keychainAvailable)
+
+// The platform functions the keychain is used through, swapped in tests
+var (
+	readKeychain   = keychainGet
+	writeKeychain  = keychainSet
+	deleteKeychain = keychainDelete
+)
+
+// KeychainEnabled reports whether connection strings are saved in the OS
+// keychain. It is on when a keychain is available and can be turned off with
+// MIRADOR_KEYCHAIN=0.
+func KeychainEnabled() bool {
+	if value, ok := os.LookupEnv("MIRADOR_KEYCHAIN"); ok && (value == "0" || strings.EqualFold(value, "false")) {
+		return false
+	}
+	return hasKeychain()
+}
+
+// resolveKeychainReferences replaces keychain references with the connection
+// strings they point to. A reference whose secret cannot be read is kept, and
+// connecting with it reports the keychain error.
+func resolveKeychainReferences(connections []models.SavedConnection) []models.SavedConnection {
+	resolved := append([]models.SavedConnection(nil), connections...)
+	for i := range resolved {
+		for _, field := range secretFields(&resolved[i]) {
+			if !IsKeychainReference(*field.value) {
+				continue
+			}
+			account := strings.TrimPrefix(*field.value, keychainPrefix)
+			secret, err := readKeychain(account)
+			if err != nil {
+				continue
+			}
+			rememberSecret(account, secret)
+			*field.value = secret
+		}
+	}
+	return resolved
+}
+
+// storeInKeychain moves connection strings into the keychain and returns the
+// connections with references in their place. A connection whose string cannot
+// be stored keeps it in the file.
+func storeInKeychain(connections []models.SavedConnection) []models.SavedConnection {
+	if !KeychainEnabled() {
+		return connections
+	}
+
+	stored := append([]models.SavedConnection(nil), connections...)
+	for i := range stored {
+		for _, field := range secretFields(&stored[i]) {
+			if *field.value == "" || IsKeychainReference(*field.value) {
+				continue
+			}
+			if cachedSecret(field.account) != *field.value {
+				if err := writeKeychain(field.account, *field.value); err != nil {
+					continue
+				}
+				rememberSecret(field.account, *field.value)
+			}
+			*field.value = keychainPrefix + field.account
+		}
+	}
+	return stored
+}
+
+// pruneKeychain deletes the keychain secrets of connections that were removed or renamed
+func pruneKeychain(previous, current []models.SavedConnection) {
+	kept := map[string]bool{}
+	for i := range current {
+		for _, field := range secretFields(&current[i]) {
+			if IsKeychainReference(*field.value) {
+				kept[strings.TrimPrefix(*field.value, keychainPrefix)] = true
+			}
+		}
+	}
+	for i := range previous {
+		for _, field := range secretFields(&previous[i]) {
+			account := strings.TrimPrefix(*field.value, keychainPrefix)
+			if IsKeychainReference(*field.value) && !kept[account] {
+				if err := deleteKeychain(account); err == nil || errors.Is(err, ErrKeychainNotFound) {
+					rememberSecret(account, "")
+				}
+			}
+		}
+	}
+}
+
+// cachedSecret returns the secret last seen in the keychain for an account
+func cachedSecret(account string) string {
+	keychainCache.Lock()
+	defer keychainCache.Unlock()
+	return keychainCache.secrets[account]
+}
+
+// rememberSecret records the keychain's secret for an account; "" forgets it
+func rememberSecret(account, secret string) {
+	keychainCache.Lock()
+	defer keychainCache.Unlock()
+	if secret == "" {
+		delete(keychainCache.secrets, account)
+		return
+	}
+	keychainCache.secrets[account] = secret
+}
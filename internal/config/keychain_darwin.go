@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// On macOS secrets are generic passwords in the login keychain, managed with
+// the security command. Secrets are written through its interactive mode,
+// which reads the command from stdin, so they never appear in a process's
+// arguments.
+
+func keychainAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func keychainGet(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return "", ErrKeychainNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func keychainSet(account, secret string) error {
+	if strings.ContainsAny(account+secret, "\n\r") {
+		return errors.New("keychain secrets cannot contain line breaks")
+	}
+	command := strings.Join([]string{
+		"add-generic-password", "-U",
+		"-s", securityQuote(keychainService),
+		"-a", securityQuote(account),
+		"-l", securityQuote("Mirador: " + account),
+		"-w", securityQuote(secret),
+	}, " ")
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command + "\n")
+	return cmd.Run()
+}
+
+// securityQuote quotes a value as one word of an interactive security command
+func securityQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+func keychainDelete(account string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", account).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return ErrKeychainNotFound
+	}
+	return err
+}
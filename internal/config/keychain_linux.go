@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// On Linux secrets go to the Secret Service (GNOME Keyring, KWallet) through
+// secret-tool, which reads the secret from stdin and needs a session bus.
+
+func keychainAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func keychainGet(account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", keychainService, "account", account).Output()
+	if err != nil {
+		// secret-tool exits 1 with no output when nothing matches
+		if len(out) == 0 {
+			return "", ErrKeychainNotFound
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func keychainSet(account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label=Mirador: "+account, "service", keychainService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return cmd.Run()
+}
+
+func keychainDelete(account string) error {
+	return exec.Command("secret-tool", "clear", "service", keychainService, "account", account).Run()
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !linux && !windows
+
+package config
+
+import "errors"
+
+// errNoKeychain is returned on platforms without a supported keychain
+var errNoKeychain = errors.New("no keychain on this platform")
+
+func keychainAvailable() bool { return false }
+
+func keychainGet(string) (string, error) { return "", errNoKeychain }
+
+func keychainSet(string, string) error { return errNoKeychain }
+
+func keychainDelete(string) error { return errNoKeychain }
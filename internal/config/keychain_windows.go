@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// On Windows secrets are generic credentials in the Credential Manager, named
+// mirador:<connection name>.
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential mirrors the Win32 CREDENTIALW structure
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func keychainAvailable() bool {
+	return procCredRead.Find() == nil
+}
+
+func credentialTarget(account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(keychainService + ":" + account)
+}
+
+func keychainGet(account string) (string, error) {
+	target, err := credentialTarget(account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", ErrKeychainNotFound
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func keychainSet(account, secret string) error {
+	target, err := credentialTarget(account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return err
+	}
+	return nil
+}
+
+func keychainDelete(account string) error {
+	target, err := credentialTarget(account)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return ErrKeychainNotFound
+		}
+		return err
+	}
+	return nil
+}
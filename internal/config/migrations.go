@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MigrationsDir is where recorded migration files are written, relative to the working directory
+const MigrationsDir = "migrations"
+
+// GenerateMigrationFilename creates a timestamped migration file path that sorts in recording order
+func GenerateMigrationFilename(now time.Time) string {
+	return filepath.Join(MigrationsDir, fmt.Sprintf("%s_mirador_session.up.sql", now.Format("20060102150405")))
+}
+
+// AppendMigrationStatement appends a statement to a migration file, creating the
+// file with a header comment on first use. Statements carry their bound values,
+// so the file is only readable by its owner.
+func AppendMigrationStatement(path, header, statement string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	_, statErr := os.Stat(path)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, privateFileMode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := restrictMode(path, privateFileMode); err != nil {
+		return err
+	}
+
+	if os.IsNotExist(statErr) {
+		if _, err := file.WriteString(header); err != nil {
+			return err
+		}
+	}
+	_, err = file.WriteString(statement)
+	return err
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetNotesFile returns the path to the table notes file
+func GetNotesFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "notes.json"), nil
+}
+
+func loadNotesStore() (map[string]map[string]string, error) {
+	notesFile, err := GetNotesFile()
+	if err != nil {
+		return nil, err
+	}
+
+	store := map[string]map[string]string{}
+	data, err := os.ReadFile(notesFile)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store); err != nil {
+		// Start over rather than failing on a corrupted store
+		return map[string]map[string]string{}, nil
+	}
+	return store, nil
+}
+
+// LoadTableNotes returns the notes of a connection and schema's tables by table name.
+// Keys come from StoreKey.
+func LoadTableNotes(key string) (map[string]string, error) {
+	store, err := loadNotesStore()
+	if err != nil {
+		return nil, err
+	}
+	return store[key], nil
+}
+
+// SaveTableNote sets the note of a table; an empty note removes it
+func SaveTableNote(key, table, note string) error {
+	store, err := loadNotesStore()
+	if err != nil {
+		return err
+	}
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(store[key], table)
+		if len(store[key]) == 0 {
+			delete(store, key)
+		}
+	} else {
+		if store[key] == nil {
+			store[key] = map[string]string{}
+		}
+		store[key][table] = note
+	}
+
+	notesFile, err := GetNotesFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writePrivateFile(notesFile, data)
+}
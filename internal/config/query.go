@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStatementTimeout is how long a statement may run before it is cancelled
+const DefaultStatementTimeout = 30 * time.Second
+
+// DefaultConnectTimeout is how long opening a connection may take
+const DefaultConnectTimeout = 10 * time.Second
+
+// StatementTimeout returns the default per-statement timeout of connections
+// without their own. It can be set with MIRADOR_STATEMENT_TIMEOUT as a duration
+// ("2m") or in seconds ("90"); 0 turns the timeout off.
+func StatementTimeout() time.Duration {
+	return envTimeout("MIRADOR_STATEMENT_TIMEOUT", DefaultStatementTimeout)
+}
+
+// ConnectTimeout returns the default connect timeout of connections without
+// their own. It can be set with MIRADOR_CONNECT_TIMEOUT like the statement
+// timeout; 0 waits for as long as the driver does.
+func ConnectTimeout() time.Duration {
+	return envTimeout("MIRADOR_CONNECT_TIMEOUT", DefaultConnectTimeout)
+}
+
+// ParseTimeout reads a timeout written as a duration ("2m") or in seconds ("90")
+func ParseTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid timeout %q: use a duration like 2m or a number of seconds", value)
+}
+
+// envTimeout reads a timeout from an environment variable
+func envTimeout(name string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	if d, err := ParseTimeout(value); err == nil {
+		return d
+	}
+	return fallback
+}
+
+// DefaultExplainRowLimit and DefaultExplainCostLimit are the planner estimates
+// above which the query runner's cost check asks before running a SELECT
+const (
+	DefaultExplainRowLimit  = 100000
+	DefaultExplainCostLimit = 100000
+)
+
+// ExplainLimits returns the estimated row count and planner cost above which the
+// cost check warns. They can be set with MIRADOR_EXPLAIN_ROWS and
+// MIRADOR_EXPLAIN_COST; 0 turns a limit off.
+func ExplainLimits() (rows, cost float64) {
+	return envLimit("MIRADOR_EXPLAIN_ROWS", DefaultExplainRowLimit), envLimit("MIRADOR_EXPLAIN_COST", DefaultExplainCostLimit)
+}
+
+// envLimit reads a non-negative number from an environment variable
+func envLimit(name string, fallback float64) float64 {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
+
+// DefaultEditorHeight is how many lines the query editor shows
+const DefaultEditorHeight = 5
+
+// MaxEditorHeight is the most lines the query editor shows, given to its text
+// area as its maximum height so the two always agree
+const MaxEditorHeight = 99
+
+// EditorHeight returns how many lines of a statement the query editor shows
+// before it scrolls. It can be set with MIRADOR_EDITOR_HEIGHT, up to
+// MaxEditorHeight.
+func EditorHeight() int {
+	if lines := int(envLimit("MIRADOR_EDITOR_HEIGHT", DefaultEditorHeight)); lines > 0 {
+		return min(lines, MaxEditorHeight)
+	}
+	return DefaultEditorHeight
+}
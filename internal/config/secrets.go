@@ -0,0 +1,238 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Saved connection strings can be encrypted at rest with a master passphrase. An
+// encrypted string is stored as enc:v1:<base64 of salt, nonce, and AES-GCM
+// ciphertext>, with the key derived from the passphrase by PBKDF2-SHA256.
+const (
+	encryptedPrefix = "enc:v1:"
+	saltSize        = 16
+	keySize         = 32
+	kdfIterations   = 600000
+)
+
+// ErrWrongPassphrase is returned when a passphrase does not decrypt the saved connections
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// session holds the passphrase of this run. While it is set, SaveConnections
+// encrypts connection strings and LoadSavedConnections decrypts them.
+var session struct {
+	sync.Mutex
+	passphrase string
+	salt       []byte
+	key        []byte
+}
+
+// IsEncrypted reports whether a stored connection string is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// HasEncryptedConnections reports whether any saved connection needs the passphrase to be read
+func HasEncryptedConnections(connections []models.SavedConnection) bool {
+	for i := range connections {
+		for _, field := range secretFields(&connections[i]) {
+			if IsEncrypted(*field.value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// secretField is a connection string of a saved connection and the keychain
+// account it is stored under
+type secretField struct {
+	account string
+	value   *string
+}
+
+// secretFields returns the connection strings of a saved connection, which hold passwords
+func secretFields(conn *models.SavedConnection) []secretField {
+	return []secretField{
+		{conn.Name, &conn.ConnectionStr},
+		{conn.Name + " (replica)", &conn.ReplicaConnectionStr},
+	}
+}
+
+// EncryptionEnabled reports whether saved connections are written encrypted
+func EncryptionEnabled() bool {
+	session.Lock()
+	defer session.Unlock()
+	return session.key != nil
+}
+
+// SetPassphrase sets the passphrase that saved connections are encrypted with
+// from the next save on. An empty passphrase stores them in plaintext again.
+func SetPassphrase(passphrase string) error {
+	session.Lock()
+	defer session.Unlock()
+	if passphrase == "" {
+		session.passphrase, session.salt, session.key = "", nil, nil
+		return nil
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	session.passphrase, session.salt, session.key = passphrase, salt, key
+	return nil
+}
+
+// UnlockConnections decrypts the connection strings of connections and keeps the
+// passphrase for later loads and saves. It returns ErrWrongPassphrase when the
+// passphrase does not match.
+func UnlockConnections(connections []models.SavedConnection, passphrase string) ([]models.SavedConnection, error) {
+	keys := map[string][]byte{}
+	decrypted, err := decryptConnections(connections, passphrase, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Later saves reuse the salt the file already has, so saving derives no new key
+	for salt, key := range keys {
+		session.Lock()
+		session.passphrase, session.salt, session.key = passphrase, []byte(salt), key
+		session.Unlock()
+		return decrypted, nil
+	}
+	if err := SetPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// decryptConnections returns a copy of connections with their connection strings
+// decrypted; keys caches the key derived for each salt
+func decryptConnections(connections []models.SavedConnection, passphrase string, keys map[string][]byte) ([]models.SavedConnection, error) {
+	decrypted := append([]models.SavedConnection(nil), connections...)
+	for i := range decrypted {
+		for _, field := range secretFields(&decrypted[i]) {
+			if !IsEncrypted(*field.value) {
+				continue
+			}
+			plaintext, err := decryptValue(*field.value, passphrase, keys)
+			if err != nil {
+				return nil, fmt.Errorf("connection '%s': %w", decrypted[i].Name, err)
+			}
+			*field.value = plaintext
+		}
+	}
+	return decrypted, nil
+}
+
+// sessionConnections decrypts connections read from disk with the session's
+// passphrase; they are returned unchanged while no passphrase is set
+func sessionConnections(connections []models.SavedConnection) ([]models.SavedConnection, error) {
+	session.Lock()
+	passphrase, salt, key := session.passphrase, session.salt, session.key
+	session.Unlock()
+	if passphrase == "" || !HasEncryptedConnections(connections) {
+		return connections, nil
+	}
+	return decryptConnections(connections, passphrase, map[string][]byte{string(salt): key})
+}
+
+// encryptConnections returns a copy of connections with their connection strings
+// encrypted under the session's key, or connections itself while no passphrase is set
+func encryptConnections(connections []models.SavedConnection) ([]models.SavedConnection, error) {
+	session.Lock()
+	salt, key := session.salt, session.key
+	session.Unlock()
+	if key == nil {
+		return connections, nil
+	}
+
+	encrypted := append([]models.SavedConnection(nil), connections...)
+	for i := range encrypted {
+		for _, field := range secretFields(&encrypted[i]) {
+			// Keychain references name a connection and hold no secret
+			if *field.value == "" || IsEncrypted(*field.value) || IsKeychainReference(*field.value) {
+				continue
+			}
+			value, err := encryptValue(*field.value, salt, key)
+			if err != nil {
+				return nil, err
+			}
+			*field.value = value
+		}
+	}
+	return encrypted, nil
+}
+
+// deriveKey stretches a passphrase into an AES-256 key
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, keySize)
+}
+
+// encryptValue seals plaintext with AES-GCM under key and a fresh nonce
+func encryptValue(plaintext string, salt, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := append(append(append([]byte(nil), salt...), nonce...), gcm.Seal(nil, nonce, []byte(plaintext), nil)...)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue opens a value written by encryptValue, deriving the key for its salt once
+func decryptValue(value, passphrase string, keys map[string][]byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < saltSize {
+		return "", errors.New("encrypted connection string is corrupted")
+	}
+	salt := string(sealed[:saltSize])
+	key, ok := keys[salt]
+	if !ok {
+		if key, err = deriveKey(passphrase, sealed[:saltSize]); err != nil {
+			return "", err
+		}
+		keys[salt] = key
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	rest := sealed[saltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return "", errors.New("encrypted connection string is corrupted")
+	}
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+// newGCM returns an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MaxSnapshotsPerConnection caps how many table snapshots are kept for each connection
+const MaxSnapshotsPerConnection = 60
+
+// GetTableSnapshotsFile returns the path to the table snapshots file
+func GetTableSnapshotsFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "table_snapshots.json"), nil
+}
+
+func loadSnapshotStore() (map[string][]models.TableSnapshot, error) {
+	snapshotsFile, err := GetTableSnapshotsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	store := map[string][]models.TableSnapshot{}
+	data, err := os.ReadFile(snapshotsFile)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store); err != nil {
+		// Start over rather than failing on a corrupted store
+		return map[string][]models.TableSnapshot{}, nil
+	}
+	return store, nil
+}
+
+// LoadTableSnapshots returns the stored snapshots for a connection, oldest first
+func LoadTableSnapshots(key string) ([]models.TableSnapshot, error) {
+	store, err := loadSnapshotStore()
+	if err != nil {
+		return nil, err
+	}
+	return store[key], nil
+}
+
+// AppendTableSnapshot stores a new snapshot for a connection, dropping the oldest beyond the cap
+func AppendTableSnapshot(key string, snapshot models.TableSnapshot) error {
+	store, err := loadSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	snapshots := append(store[key], snapshot)
+	if len(snapshots) > MaxSnapshotsPerConnection {
+		snapshots = snapshots[len(snapshots)-MaxSnapshotsPerConnection:]
+	}
+	store[key] = snapshots
+
+	snapshotsFile, err := GetTableSnapshotsFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writePrivateFile(snapshotsFile, data)
+}
@@ -2,24 +2,67 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/dancaldera/mirador/internal/database"
 	"github.com/dancaldera/mirador/internal/models"
 )
 
-// GetConfigDir returns the configuration directory for the application
-func GetConfigDir() (string, error) {
+// ConfigDirPath returns the path of the configuration directory without
+// creating it
+func ConfigDirPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
+	return filepath.Join(homeDir, ".mirador"), nil
+}
+
+// The configuration directory holds connection credentials, saved queries, and
+// recorded statement values, so it and its files are only open to their owner
+const (
+	privateDirMode  os.FileMode = 0700
+	privateFileMode os.FileMode = 0600
+)
+
+// GetConfigDir returns the configuration directory for the application,
+// creating it, or closing one an older version left open to other users
+func GetConfigDir() (string, error) {
+	configDir, err := ConfigDirPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(configDir, privateDirMode); err != nil {
+		return "", err
+	}
+	return configDir, restrictMode(configDir, privateDirMode)
+}
+
+// restrictMode takes away any permission beyond mode from an existing file or
+// directory
+func restrictMode(path string, mode os.FileMode) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&^mode != 0 {
+		return os.Chmod(path, info.Mode().Perm()&mode)
+	}
+	return nil
+}
 
-	configDir := filepath.Join(homeDir, ".mirador")
-	return configDir, os.MkdirAll(configDir, 0755)
+// writePrivateFile replaces a file with data only its owner can read, also when
+// an older version created it readable by others
+func writePrivateFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, privateFileMode); err != nil {
+		return err
+	}
+	return restrictMode(path, privateFileMode)
 }
 
 // GetConnectionsFile returns the path to the connections file
@@ -31,7 +74,9 @@ func GetConnectionsFile() (string, error) {
 	return filepath.Join(configDir, "connections.json"), nil
 }
 
-// LoadSavedConnections loads saved connections from the configuration file
+// LoadSavedConnections loads saved connections from the configuration file. The
+// connections are returned even when some of them could not be read, along
+// with the error naming them.
 func LoadSavedConnections() ([]models.SavedConnection, error) {
 	connectionsFile, err := GetConnectionsFile()
 	if err != nil {
@@ -48,14 +93,107 @@ func LoadSavedConnections() ([]models.SavedConnection, error) {
 		return nil, err
 	}
 
-	var connections []models.SavedConnection
-	if err := json.Unmarshal(data, &connections); err != nil {
+	connections, loadErr := UnmarshalConnections(data)
+	if connections == nil && loadErr != nil {
 		// If we can't parse the file, return empty slice instead of error
 		// This allows for graceful recovery from corrupted config files
 		return []models.SavedConnection{}, nil
 	}
 
-	return connections, nil
+	// Encrypted connection strings stay encrypted until the passphrase is entered
+	connections, err = sessionConnections(connections)
+	if err != nil {
+		return nil, err
+	}
+	// Connections whose fields could not be read are returned with the error
+	return resolveKeychainReferences(connections), loadErr
+}
+
+// readStoredConnections reads the connections file as stored, with keychain
+// references and encrypted strings left as they are
+func readStoredConnections(connectionsFile string) []models.SavedConnection {
+	data, err := os.ReadFile(connectionsFile)
+	if err != nil {
+		return nil
+	}
+	connections, _ := UnmarshalConnections(data)
+	return connections
+}
+
+// MarshalConnections writes connections in the format of connections.json. A
+// connection string still in the clear is stored as its fields when they build
+// the same string again; encrypted strings, keychain references, SQLite paths,
+// and strings in other forms are stored as they are. Fields that could not be
+// read back into a string are written as they were read.
+func MarshalConnections(connections []models.SavedConnection) ([]byte, error) {
+	stored := append([]models.SavedConnection(nil), connections...)
+	for i := range stored {
+		conn := &stored[i]
+		if conn.ConnectionStr != "" {
+			if conn.Connection = storedFields(conn.Driver, conn.ConnectionStr); conn.Connection != nil {
+				conn.ConnectionStr = ""
+			}
+		}
+		if conn.ReplicaConnectionStr != "" {
+			if conn.ReplicaConnection = storedFields(conn.Driver, conn.ReplicaConnectionStr); conn.ReplicaConnection != nil {
+				conn.ReplicaConnectionStr = ""
+			}
+		}
+	}
+	return json.MarshalIndent(stored, "", "  ")
+}
+
+// UnmarshalConnections reads connections in the format of connections.json,
+// building the connection strings of those stored as fields. Files written
+// before connections were stored as fields hold only strings and read the same.
+// A connection whose fields no longer build a string keeps them, with an empty
+// string, so saving writes them back; its error is returned along with the
+// connections. Only a file that is not valid JSON returns no connections.
+func UnmarshalConnections(data []byte) ([]models.SavedConnection, error) {
+	var connections []models.SavedConnection
+	if err := json.Unmarshal(data, &connections); err != nil {
+		return nil, err
+	}
+	var errs []error
+	for i := range connections {
+		conn := &connections[i]
+		if connectionStr, err := storedConnectionString(conn.Driver, conn.Connection, conn.ConnectionStr); err != nil {
+			errs = append(errs, fmt.Errorf("connection %q: %w", conn.Name, err))
+		} else {
+			conn.ConnectionStr, conn.Connection = connectionStr, nil
+		}
+		if connectionStr, err := storedConnectionString(conn.Driver, conn.ReplicaConnection, conn.ReplicaConnectionStr); err != nil {
+			errs = append(errs, fmt.Errorf("connection %q replica: %w", conn.Name, err))
+		} else {
+			conn.ReplicaConnectionStr, conn.ReplicaConnection = connectionStr, nil
+		}
+	}
+	return connections, errors.Join(errs...)
+}
+
+// storedConnectionString builds a connection string stored as fields, or
+// returns the one stored as a string
+func storedConnectionString(driver string, fields *models.ConnectionFields, connectionStr string) (string, error) {
+	if fields == nil {
+		return connectionStr, nil
+	}
+	return database.BuildConnectionString(driver, *fields)
+}
+
+// storedFields returns the fields a connection string is stored as, or nil
+// when it is stored as the string
+func storedFields(driver, connectionStr string) *models.ConnectionFields {
+	if connectionStr == "" || IsEncrypted(connectionStr) || IsKeychainReference(connectionStr) {
+		return nil
+	}
+	fields, err := database.ParseConnectionString(driver, connectionStr)
+	if err != nil {
+		return nil
+	}
+	if rebuilt, err := database.BuildConnectionString(driver, fields); err != nil || rebuilt != connectionStr {
+		return nil
+	}
+	return &fields
 }
 
 // SaveConnections saves connections to the configuration file
@@ -65,17 +203,22 @@ func SaveConnections(connections []models.SavedConnection) error {
 		return err
 	}
 
-	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(connectionsFile), 0755); err != nil {
+	previous := readStoredConnections(connectionsFile)
+	connections, err = encryptConnections(storeInKeychain(connections))
+	if err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(connections, "", "  ")
+	data, err := MarshalConnections(connections)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(connectionsFile, data, 0644)
+	if err := writePrivateFile(connectionsFile, data); err != nil {
+		return err
+	}
+	pruneKeychain(previous, connections)
+	return nil
 }
 
 // GetQueryHistoryFile returns the path to the query history file
@@ -120,79 +263,71 @@ func SaveQueryHistory(history []models.QueryHistoryEntry) error {
 		return err
 	}
 
-	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(historyFile), 0755); err != nil {
-		return err
-	}
-
 	data, err := json.MarshalIndent(history, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(historyFile, data, 0644)
+	return writePrivateFile(historyFile, data)
 }
 
-// ExportToCSV exports data to CSV format
-func ExportToCSV(columns []string, rows [][]string, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+// ExportToCSV exports data to CSV format. Cells marked in nulls are written as
+// an empty unquoted field and empty text as "", the way PostgreSQL's COPY does,
+// so the two stay apart.
+func ExportToCSV(columns []string, rows [][]string, nulls [][]bool, filename string) error {
+	var b strings.Builder
 
 	// Write header
-	header := ""
+	header := make([]string, len(columns))
 	for i, col := range columns {
-		if i > 0 {
-			header += ","
-		}
-		// Quote columns that contain commas or quotes
-		if strings.Contains(col, ",") || strings.Contains(col, "\"") {
-			col = fmt.Sprintf("\"%s\"", strings.ReplaceAll(col, "\"", "\"\""))
-		}
-		header += col
-	}
-	header += "\n"
-
-	if _, err := file.WriteString(header); err != nil {
-		return err
+		header[i] = csvField(col)
 	}
+	b.WriteString(strings.Join(header, ",") + "\n")
 
 	// Write data rows
-	for _, row := range rows {
-		line := ""
+	for r, row := range rows {
+		line := make([]string, len(row))
 		for i, cell := range row {
-			if i > 0 {
-				line += ","
-			}
-			// Quote cells that contain commas or quotes
-			if strings.Contains(cell, ",") || strings.Contains(cell, "\"") {
-				cell = fmt.Sprintf("\"%s\"", strings.ReplaceAll(cell, "\"", "\"\""))
+			if isNullCell(nulls, r, i) {
+				continue
 			}
-			line += cell
+			line[i] = csvField(cell)
 		}
-		line += "\n"
+		b.WriteString(strings.Join(line, ",") + "\n")
+	}
 
-		if _, err := file.WriteString(line); err != nil {
-			return err
-		}
+	return os.WriteFile(filename, []byte(b.String()), 0644)
+}
+
+// csvField quotes a CSV value that holds a comma, quote, or line break, or is
+// empty, doubling its quotes
+func csvField(value string) string {
+	if value != "" && !strings.ContainsAny(value, ",\"\r\n") {
+		return value
 	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
 
-	return nil
+// isNullCell reports whether nulls marks a cell as SQL NULL
+func isNullCell(nulls [][]bool, row, col int) bool {
+	return row < len(nulls) && col < len(nulls[row]) && nulls[row][col]
 }
 
-// ExportToJSON exports data to JSON format
-func ExportToJSON(columns []string, rows [][]string, filename string) error {
-	var jsonData []map[string]string
+// ExportToJSON exports data to JSON format, an array with one object per row.
+// Cells marked in nulls are written as null.
+func ExportToJSON(columns []string, rows [][]string, nulls [][]bool, filename string) error {
+	jsonData := make([]map[string]interface{}, 0, len(rows))
 
-	for _, row := range rows {
-		rowMap := make(map[string]string)
+	for r, row := range rows {
+		rowMap := make(map[string]interface{})
 		for i, col := range columns {
-			if i < len(row) {
-				rowMap[col] = row[i]
-			} else {
+			switch {
+			case i >= len(row):
 				rowMap[col] = ""
+			case isNullCell(nulls, r, i):
+				rowMap[col] = nil
+			default:
+				rowMap[col] = row[i]
 			}
 		}
 		jsonData = append(jsonData, rowMap)
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+)
+
+// StoreKey identifies a connection and schema in the per-connection stores:
+// favorites, notes, table snapshots, and worksheets. It is given the connection
+// string with its secrets removed, and hashes it so keys stay short.
+func StoreKey(driver, connectionStr, schema string) string {
+	sum := sha256.Sum256([]byte(driver + "\x00" + connectionStr + "\x00" + schema))
+	return hex.EncodeToString(sum[:])
+}
+
+// storeFiles returns the paths of the per-connection stores
+func storeFiles() ([]string, error) {
+	var files []string
+	for _, path := range []func() (string, error){GetFavoritesFile, GetNotesFile, GetTableSnapshotsFile, GetWorksheetsFile} {
+		file, err := path()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// RenameStoreKey moves the entries of every per-connection store from one key
+// to another. A store that already has entries under the new key keeps them
+// and drops the old ones.
+func RenameStoreKey(from, to string) error {
+	files, err := storeFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var store map[string]json.RawMessage
+		if err := json.Unmarshal(data, &store); err != nil {
+			// A corrupted store is started over when it is next saved
+			continue
+		}
+		entry, ok := store[from]
+		if !ok {
+			continue
+		}
+		if _, ok := store[to]; !ok {
+			store[to] = entry
+		}
+		delete(store, from)
+
+		data, err = json.MarshalIndent(store, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writePrivateFile(file, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultWorksheetInterval is how often the query editor is saved to disk
+const DefaultWorksheetInterval = 5 * time.Second
+
+// WorksheetInterval returns the time between autosaves of the query editor. It
+// can be set with MIRADOR_AUTOSAVE_INTERVAL like the statement timeout; 0
+// turns autosave off.
+func WorksheetInterval() time.Duration {
+	return envTimeout("MIRADOR_AUTOSAVE_INTERVAL", DefaultWorksheetInterval)
+}
+
+// Worksheet is the query editor's text as last autosaved for a connection
+type Worksheet struct {
+	Query   string    `json:"query"`
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// GetWorksheetsFile returns the path to the autosaved worksheets file
+func GetWorksheetsFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "worksheets.json"), nil
+}
+
+func loadWorksheetStore() (map[string]Worksheet, error) {
+	worksheetsFile, err := GetWorksheetsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	store := map[string]Worksheet{}
+	data, err := os.ReadFile(worksheetsFile)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store); err != nil {
+		// Start over rather than failing on a corrupted store
+		return map[string]Worksheet{}, nil
+	}
+	return store, nil
+}
+
+// LoadWorksheet returns the worksheet autosaved for a connection. Keys come from
+// StoreKey.
+func LoadWorksheet(key string) (Worksheet, error) {
+	store, err := loadWorksheetStore()
+	if err != nil {
+		return Worksheet{}, err
+	}
+	return store[key], nil
+}
+
+// SaveWorksheet sets the worksheet of a connection; a blank query removes it
+func SaveWorksheet(key, query string, now time.Time) error {
+	store, err := loadWorksheetStore()
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(query) == "" {
+		delete(store, key)
+	} else {
+		store[key] = Worksheet{Query: query, SavedAt: now}
+	}
+
+	worksheetsFile, err := GetWorksheetsFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written to a temporary file and renamed, so a crash mid-write keeps the last save
+	tmp := worksheetsFile + ".tmp"
+	if err := os.WriteFile(tmp, data, privateFileMode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, worksheetsFile)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// WorkspaceVersion is the format of workspace archives written by this version;
+// archives of a later format are refused rather than half imported
+const WorkspaceVersion = 1
+
+// Files of the configuration directory a workspace archive carries. The audit
+// log stays behind: it records what happened on this machine.
+const (
+	WorkspaceConnections    = "connections.json"
+	WorkspaceQueryHistory   = "query_history.json"
+	WorkspaceFavorites      = "favorites.json"
+	WorkspaceNotes          = "notes.json"
+	WorkspaceTableSnapshots = "table_snapshots.json"
+	WorkspaceHooks          = "hooks.json"
+)
+
+// WorkspaceFiles lists the files of a workspace archive in the order they are written
+var WorkspaceFiles = []string{
+	WorkspaceConnections, WorkspaceQueryHistory, WorkspaceFavorites,
+	WorkspaceNotes, WorkspaceTableSnapshots, WorkspaceHooks,
+}
+
+// workspaceManifest is the first entry of a workspace archive
+type workspaceManifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Files      []string  `json:"files"`
+}
+
+const workspaceManifestName = "manifest.json"
+
+// ReadWorkspaceFile returns a file of the configuration directory, or nil when
+// it does not exist
+func ReadWorkspaceFile(name string) ([]byte, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(configDir, name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// WriteWorkspaceFile replaces a file of the configuration directory
+func WriteWorkspaceFile(name string, data []byte) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(filepath.Join(configDir, name), data)
+}
+
+// WriteWorkspaceArchive writes files, by name, to a zip archive at path along
+// with a manifest
+func WriteWorkspaceArchive(path string, files map[string][]byte, now time.Time) error {
+	manifest := workspaceManifest{Version: WorkspaceVersion, ExportedAt: now}
+	for _, name := range WorkspaceFiles {
+		if _, ok := files[name]; ok {
+			manifest.Files = append(manifest.Files, name)
+		}
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	archive := zip.NewWriter(file)
+	write := func(name string, data []byte) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	err = write(workspaceManifestName, manifestData)
+	for _, name := range manifest.Files {
+		if err != nil {
+			break
+		}
+		err = write(name, files[name])
+	}
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadWorkspaceArchive reads the files of a workspace archive by name. Entries
+// that are not workspace files are ignored, so nothing outside the
+// configuration directory can be written from an archive.
+func ReadWorkspaceArchive(path string) (map[string][]byte, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("not a workspace archive: %w", err)
+	}
+	defer archive.Close()
+
+	files := map[string][]byte{}
+	for _, entry := range archive.File {
+		if entry.Name != workspaceManifestName && !slices.Contains(WorkspaceFiles, entry.Name) {
+			continue
+		}
+		r, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		files[entry.Name] = data
+	}
+
+	manifestData, ok := files[workspaceManifestName]
+	if !ok {
+		return nil, fmt.Errorf("not a workspace archive: %s has no %s", filepath.Base(path), workspaceManifestName)
+	}
+	var manifest workspaceManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", workspaceManifestName, err)
+	}
+	if manifest.Version > WorkspaceVersion {
+		return nil, fmt.Errorf("the archive is of workspace format %d; this version of Mirador reads up to %d", manifest.Version, WorkspaceVersion)
+	}
+	delete(files, workspaceManifestName)
+	return files, nil
+}
+
+// MergeJSONObjects adds the keys of imported that local lacks, for the stores
+// keyed by connection (favorites, notes, snapshots) and the hooks. Local keys
+// win, so an import never overwrites what is already here. It returns the
+// merged object and how many keys were added.
+func MergeJSONObjects(local, imported []byte) ([]byte, int, error) {
+	merged := map[string]json.RawMessage{}
+	if len(local) > 0 {
+		if err := json.Unmarshal(local, &merged); err != nil {
+			return nil, 0, fmt.Errorf("the local file is not a JSON object: %w", err)
+		}
+	}
+	var incoming map[string]json.RawMessage
+	if err := json.Unmarshal(imported, &incoming); err != nil {
+		return nil, 0, fmt.Errorf("the imported file is not a JSON object: %w", err)
+	}
+	added := 0
+	for key, value := range incoming {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+			added++
+		}
+	}
+	data, err := json.MarshalIndent(merged, "", "  ")
+	return data, added, err
+}
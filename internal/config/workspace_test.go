@@ -0,0 +1,140 @@
+package config
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceArchiveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workspace.zip")
+	files := map[string][]byte{
+		WorkspaceConnections: []byte(`[{"name":"local"}]`),
+		WorkspaceFavorites:   []byte(`{"local":["users"]}`),
+	}
+	if err := WriteWorkspaceArchive(path, files, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("WriteWorkspaceArchive: %v", err)
+	}
+	assertMode(t, path, 0600)
+
+	read, err := ReadWorkspaceArchive(path)
+	if err != nil {
+		t.Fatalf("ReadWorkspaceArchive: %v", err)
+	}
+	if len(read) != len(files) {
+		t.Fatalf("read %d files, want %d", len(read), len(files))
+	}
+	for name, data := range files {
+		if string(read[name]) != string(data) {
+			t.Errorf("%s = %s, want %s", name, read[name], data)
+		}
+	}
+}
+
+// writeZip writes entries to a zip archive at path
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	for name, data := range entries {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadWorkspaceArchiveIgnoresOtherEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workspace.zip")
+	writeZip(t, path, map[string]string{
+		workspaceManifestName: `{"version":1}`,
+		WorkspaceNotes:        `{}`,
+		"../../.bashrc":       "echo owned",
+		"audit.log":           "{}",
+	})
+
+	files, err := ReadWorkspaceArchive(path)
+	if err != nil {
+		t.Fatalf("ReadWorkspaceArchive: %v", err)
+	}
+	if len(files) != 1 || files[WorkspaceNotes] == nil {
+		t.Errorf("read %v, want only %s", files, WorkspaceNotes)
+	}
+}
+
+func TestReadWorkspaceArchiveRefusesInvalidArchives(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		entries map[string]string
+		want    string
+	}{
+		{"no manifest", map[string]string{WorkspaceNotes: `{}`}, "has no manifest.json"},
+		{"later format", map[string]string{workspaceManifestName: `{"version":2}`}, "workspace format 2"},
+		{"invalid manifest", map[string]string{workspaceManifestName: `not json`}, "invalid manifest.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".zip")
+			writeZip(t, path, tt.entries)
+			if _, err := ReadWorkspaceArchive(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadWorkspaceArchive: %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeJSONObjects(t *testing.T) {
+	merged, added, err := MergeJSONObjects([]byte(`{"a":["local"]}`), []byte(`{"a":["imported"],"b":["imported"]}`))
+	if err != nil {
+		t.Fatalf("MergeJSONObjects: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("added = %d, want 1", added)
+	}
+	var got map[string][]string
+	if err := json.Unmarshal(merged, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["a"][0] != "local" || got["b"][0] != "imported" {
+		t.Errorf("merged %v, want local keys kept and new keys added", got)
+	}
+
+	if _, _, err := MergeJSONObjects(nil, []byte(`[]`)); err == nil {
+		t.Errorf("merging a JSON array did not fail")
+	}
+}
+
+func TestWriteWorkspaceFileIsPrivate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := WriteWorkspaceFile(WorkspaceNotes, []byte(`{}`)); err != nil {
+		t.Fatalf("WriteWorkspaceFile: %v", err)
+	}
+	assertMode(t, filepath.Join(home, ".mirador", WorkspaceNotes), 0600)
+
+	data, err := ReadWorkspaceFile(WorkspaceNotes)
+	if err != nil || string(data) != `{}` {
+		t.Errorf("ReadWorkspaceFile = %q, %v", data, err)
+	}
+	if data, err := ReadWorkspaceFile(WorkspaceHooks); data != nil || err != nil {
+		t.Errorf("ReadWorkspaceFile of a missing file = %q, %v, want nil, nil", data, err)
+	}
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetColumnAggregate counts the non-NULL values of a column and computes their
+// sum, average, minimum, and maximum over the rows matching the preview filter,
+// or the whole table without one
+func GetColumnAggregate(db *sql.DB, driver, schema, table, column, filterValue string, columns []string, jsonColumns []models.JSONColumn) (models.ColumnAggregate, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	tableName, err := tableRef(driver, schema, table)
+	if err != nil {
+		return models.ColumnAggregate{}, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(%[1]s), SUM(%[1]s), AVG(%[1]s), MIN(%[1]s), MAX(%[1]s) FROM %[2]s", QuoteIdentifier(driver, column), tableName)
+	if filterValue != "" {
+		where, err := FilterWhereClause(driver, filterValue, columns, jsonColumns)
+		if err != nil {
+			return models.ColumnAggregate{}, err
+		}
+		query += " WHERE " + where
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return models.ColumnAggregate{}, err
+	}
+	defer rows.Close()
+	result, err := scanPreviewRows(rows)
+	if err != nil {
+		return models.ColumnAggregate{}, err
+	}
+	if len(result.Rows) != 1 || len(result.Rows[0]) != 5 {
+		return models.ColumnAggregate{}, fmt.Errorf("unexpected aggregate result for %s", column)
+	}
+
+	row := result.Rows[0]
+	count, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		return models.ColumnAggregate{}, fmt.Errorf("unexpected value count %q: %w", row[0], err)
+	}
+	// Without values the other aggregates are NULL
+	if count == 0 {
+		return models.ColumnAggregate{}, nil
+	}
+	return models.ColumnAggregate{Count: count, Sum: row[1], Avg: row[2], Min: row[3], Max: row[4]}, nil
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLite attaches databases per connection, but a connection pool opens and
+// closes connections as it goes. SQLite pools are therefore opened through a
+// connector that remembers the session's attached databases. New connections
+// attach them when opened, and pooled ones catch up before they are reused, so
+// an ATTACH holds for the whole session without closing connections that hold
+// temporary tables.
+
+// SQLiteAttachment is a database file attached to a SQLite session under a schema name
+type SQLiteAttachment struct {
+	Schema string
+	File   string
+}
+
+// sqliteConnector opens SQLite connections with the session's attached databases
+type sqliteConnector struct {
+	dsn    string
+	key    string // SQLCipher key, empty for a plain file
+	driver sqlite3.SQLiteDriver
+
+	mu       sync.Mutex
+	attached []SQLiteAttachment
+}
+
+// sqliteSessionConn is a SQLite connection that knows which databases it has attached
+type sqliteSessionConn struct {
+	*sqlite3.SQLiteConn
+	connector *sqliteConnector
+	attached  []SQLiteAttachment
+}
+
+// openSQLite opens a SQLite pool whose connections share the session's
+// attachments and are unlocked with the connection string's SQLCipher key
+func openSQLite(dsn string) *sql.DB {
+	dsn, key := SplitSQLCipherKey(dsn)
+	return sql.OpenDB(&sqliteConnector{dsn: dsn, key: key})
+}
+
+// Connect opens a connection, unlocks an encrypted file, and attaches the
+// session's databases to it
+func (c *sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	sessionConn := &sqliteSessionConn{SQLiteConn: conn.(*sqlite3.SQLiteConn), connector: c}
+	if c.key != "" {
+		if err := unlock(sessionConn.SQLiteConn, c.key); err != nil {
+			sessionConn.Close()
+			return nil, err
+		}
+	}
+	if err := sessionConn.sync(); err != nil {
+		sessionConn.Close()
+		return nil, err
+	}
+	return sessionConn, nil
+}
+
+// Driver returns a driver that opens connections through the connector
+func (c *sqliteConnector) Driver() driver.Driver {
+	return sqliteSessionDriver{c}
+}
+
+// wanted returns the session's attachments
+func (c *sqliteConnector) wanted() []SQLiteAttachment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]SQLiteAttachment(nil), c.attached...)
+}
+
+// ResetSession brings a pooled connection's attachments up to date before it is
+// reused; a connection that cannot catch up is discarded
+func (c *sqliteSessionConn) ResetSession(context.Context) error {
+	if err := c.sync(); err != nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// sync detaches the databases the session no longer has and attaches the ones
+// this connection is missing
+func (c *sqliteSessionConn) sync() error {
+	wanted := c.connector.wanted()
+	for _, a := range c.attached {
+		if !slices.Contains(wanted, a) {
+			if err := c.detach(a.Schema); err != nil {
+				return err
+			}
+		}
+	}
+	for _, a := range wanted {
+		if !slices.Contains(c.attached, a) {
+			if err := c.attach(a); err != nil {
+				return fmt.Errorf("failed to attach %s: %w", a.Schema, err)
+			}
+		}
+	}
+	return nil
+}
+
+// attach attaches a database file to this connection
+func (c *sqliteSessionConn) attach(a SQLiteAttachment) error {
+	stmt := "ATTACH DATABASE ? AS " + QuoteIdentifier("sqlite3", a.Schema)
+	if _, err := c.Exec(stmt, []driver.Value{a.File}); err != nil {
+		return err
+	}
+	c.attached = append(c.attached, a)
+	return nil
+}
+
+// detach detaches a database from this connection
+func (c *sqliteSessionConn) detach(schema string) error {
+	if _, err := c.Exec("DETACH DATABASE "+QuoteIdentifier("sqlite3", schema), nil); err != nil {
+		return err
+	}
+	c.attached = slices.DeleteFunc(c.attached, func(a SQLiteAttachment) bool { return a.Schema == schema })
+	return nil
+}
+
+// sqliteSessionDriver lets the pool and SQLite helpers reach the connector
+type sqliteSessionDriver struct {
+	connector *sqliteConnector
+}
+
+// Open opens a connection with the session's attachments; the name is ignored
+func (d sqliteSessionDriver) Open(string) (driver.Conn, error) {
+	return d.connector.Connect(context.Background())
+}
+
+// withSessionConn runs fn on one connection of a SQLite pool
+func withSessionConn(db *sql.DB, fn func(c *sqliteConnector, conn *sqliteSessionConn) error) error {
+	d, ok := db.Driver().(sqliteSessionDriver)
+	if !ok {
+		return fmt.Errorf("attached databases are only supported for SQLite")
+	}
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		return fn(d.connector, driverConn.(*sqliteSessionConn))
+	})
+}
+
+// AttachSQLite attaches a database file to a SQLite session under a schema name.
+// It is attached on one connection right away, so a missing file or a taken
+// name is reported, and on the others as they are used.
+func AttachSQLite(db *sql.DB, file, schema string) error {
+	if schema == "" || strings.ContainsRune(schema, 0) {
+		return fmt.Errorf("invalid schema name %q", schema)
+	}
+	return withSessionConn(db, func(c *sqliteConnector, conn *sqliteSessionConn) error {
+		if err := conn.sync(); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, a := range c.attached {
+			if strings.EqualFold(a.Schema, schema) {
+				return fmt.Errorf("a database is already attached as %s", schema)
+			}
+		}
+		a := SQLiteAttachment{Schema: schema, File: file}
+		if err := conn.attach(a); err != nil {
+			return err
+		}
+		c.attached = append(c.attached, a)
+		return nil
+	})
+}
+
+// DetachSQLite detaches a database attached with AttachSQLite from the session
+func DetachSQLite(db *sql.DB, schema string) error {
+	return withSessionConn(db, func(c *sqliteConnector, conn *sqliteSessionConn) error {
+		c.mu.Lock()
+		i := slices.IndexFunc(c.attached, func(a SQLiteAttachment) bool { return strings.EqualFold(a.Schema, schema) })
+		if i < 0 {
+			c.mu.Unlock()
+			return fmt.Errorf("no database is attached as %s", schema)
+		}
+		c.attached = slices.Delete(slices.Clone(c.attached), i, i+1)
+		c.mu.Unlock()
+		return conn.sync()
+	})
+}
+
+// AttachStatement is an ATTACH or DETACH statement typed in the query runner
+type AttachStatement struct {
+	Detach bool
+	File   string // the file to attach; empty for DETACH
+	Schema string
+}
+
+var (
+	attachPattern = regexp.MustCompile(`(?is)^ATTACH\s+(?:DATABASE\s+)?'((?:[^']|'')*)'\s+AS\s+(.+?)\s*;?$`)
+	detachPattern = regexp.MustCompile(`(?is)^DETACH\s+(?:DATABASE\s+)?(.+?)\s*;?$`)
+)
+
+// ParseAttachStatement recognizes "ATTACH [DATABASE] 'file' AS name" and
+// "DETACH [DATABASE] name". ok is false for any other statement; an ATTACH
+// whose file is not a quoted literal is an error, since it could not be
+// attached again on the pool's other connections.
+func ParseAttachStatement(query string) (stmt AttachStatement, ok bool, err error) {
+	query = strings.TrimSpace(query)
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return AttachStatement{}, false, nil
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "ATTACH":
+		match := attachPattern.FindStringSubmatch(query)
+		if match == nil {
+			return AttachStatement{}, true, fmt.Errorf("ATTACH takes a quoted file name: ATTACH DATABASE 'file.db' AS name")
+		}
+		schema, err := parseSchemaName(match[2])
+		if err != nil {
+			return AttachStatement{}, true, err
+		}
+		return AttachStatement{File: strings.ReplaceAll(match[1], "''", "'"), Schema: schema}, true, nil
+	case "DETACH":
+		match := detachPattern.FindStringSubmatch(query)
+		if match == nil {
+			return AttachStatement{}, true, fmt.Errorf("DETACH takes a schema name: DETACH DATABASE name")
+		}
+		schema, err := parseSchemaName(match[1])
+		if err != nil {
+			return AttachStatement{}, true, err
+		}
+		return AttachStatement{Detach: true, Schema: schema}, true, nil
+	}
+	return AttachStatement{}, false, nil
+}
+
+// parseSchemaName unquotes a schema name written bare or in "", “, [], or ”
+func parseSchemaName(s string) (string, error) {
+	if len(s) >= 2 {
+		switch open, end := s[0], s[len(s)-1]; {
+		case open == '"' && end == '"', open == '`' && end == '`', open == '\'' && end == '\'':
+			name := strings.ReplaceAll(s[1:len(s)-1], string(open)+string(open), string(open))
+			if name != "" {
+				return name, nil
+			}
+		case open == '[' && end == ']' && len(s) > 2:
+			return s[1 : len(s)-1], nil
+		}
+	}
+	for _, r := range s {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127) {
+			return "", fmt.Errorf("invalid schema name %s", s)
+		}
+	}
+	return s, nil
+}
+
+// sqliteSchemaPrefix qualifies SQLite metadata with an attached schema; the main
+// database needs no prefix
+func sqliteSchemaPrefix(schema string) string {
+	if schema == "" || strings.EqualFold(schema, "main") {
+		return ""
+	}
+	return QuoteIdentifier("sqlite3", schema) + "."
+}
+
+// sqlitePragma builds a table-valued PRAGMA such as table_info for a name in a schema
+func sqlitePragma(schema, pragma, name string) string {
+	return fmt.Sprintf("PRAGMA %s%s(%s)", sqliteSchemaPrefix(schema), pragma, QuoteIdentifier("sqlite3", name))
+}
+
+// sqliteMaster names the catalog table of a SQLite schema
+func sqliteMaster(schema string) string {
+	return sqliteSchemaPrefix(schema) + "sqlite_master"
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// BackendPID returns the server-side id of the session a connection is: the
+// PostgreSQL backend pid or the MySQL connection id. It is 0 for drivers whose
+// statements cannot be cancelled by session id.
+func BackendPID(ctx context.Context, conn *sql.Conn, driver string) (int64, error) {
+	var query string
+	switch driver {
+	case "postgres", "redshift":
+		query = "SELECT pg_backend_pid()"
+	case "mysql", "mariadb":
+		query = "SELECT CONNECTION_ID()"
+	default:
+		return 0, nil
+	}
+	var pid int64
+	if err := conn.QueryRowContext(ctx, query).Scan(&pid); err != nil {
+		return 0, err
+	}
+	return pid, nil
+}
+
+// CancelBackendStatement cancels the statement running in one session, found by
+// BackendPID. It only stops the statement: the session stays open, unlike
+// pg_terminate_backend or KILL.
+func CancelBackendStatement(ctx context.Context, db *sql.DB, driver string, pid int64) error {
+	switch driver {
+	case "postgres", "redshift":
+		var cancelled bool
+		if err := db.QueryRowContext(ctx, "SELECT pg_cancel_backend($1)", pid).Scan(&cancelled); err != nil {
+			return err
+		}
+		if !cancelled {
+			return fmt.Errorf("backend %d has no statement to cancel", pid)
+		}
+		return nil
+	case "mysql", "mariadb":
+		// KILL takes no placeholder; the id is a number read from the server
+		_, err := db.ExecContext(ctx, fmt.Sprintf("KILL QUERY %d", pid))
+		return err
+	}
+	return fmt.Errorf("cancelling a statement by session is not supported for %s", driver)
+}
@@ -0,0 +1,339 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/dancaldera/mirador/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// BigQuery is browsed read-only through the official Go client, wrapped here as
+// a database/sql driver. Datasets play the role of schemas. Connection strings
+// name the project and, optionally, the dataset to open, with the path of a
+// service-account key file; without one the application default credentials
+// are used:
+//
+//	bigquery://my-project/my_dataset?credentials=/path/to/key.json&location=EU
+//
+// Every statement is dry-run first and only run when BigQuery reports it as a
+// SELECT, so nothing can be written through a connection, whatever the
+// account is allowed to do.
+func init() {
+	sql.Register("bigquery", bigqueryDriver{})
+}
+
+// BigQueryCredentialsParam is the connection string parameter that holds the
+// path of a service-account key file
+const BigQueryCredentialsParam = "credentials"
+
+var (
+	// errBigQueryReadOnly is returned for any statement but a query
+	errBigQueryReadOnly = errors.New("bigquery: connections are read-only, only SELECT statements run")
+	// errBigQueryTransactions is returned when a transaction is started
+	errBigQueryTransactions = errors.New("bigquery: transactions are not supported")
+)
+
+// bigqueryConfig is a parsed BigQuery connection string
+type bigqueryConfig struct {
+	project     string
+	dataset     string
+	credentials string
+	location    string
+}
+
+// parseBigQueryDSN reads a bigquery:// connection string
+func parseBigQueryDSN(dsn string) (bigqueryConfig, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme != "bigquery" {
+		return bigqueryConfig{}, fmt.Errorf("BigQuery connection strings look like bigquery://project/dataset")
+	}
+	if u.Host == "" {
+		return bigqueryConfig{}, fmt.Errorf("the connection string has no project")
+	}
+	cfg := bigqueryConfig{project: u.Host, dataset: strings.Trim(u.Path, "/")}
+	if strings.Contains(cfg.dataset, "/") {
+		return bigqueryConfig{}, fmt.Errorf("the connection string names more than one dataset: %s", cfg.dataset)
+	}
+	for name, values := range u.Query() {
+		switch name {
+		case BigQueryCredentialsParam:
+			cfg.credentials = values[0]
+		case "location":
+			cfg.location = values[0]
+		default:
+			return bigqueryConfig{}, fmt.Errorf("unknown BigQuery parameter %q: use credentials or location", name)
+		}
+	}
+	return cfg, nil
+}
+
+// BigQueryDataset returns the dataset a BigQuery connection string opens, or ""
+func BigQueryDataset(dsn string) string {
+	cfg, err := parseBigQueryDSN(dsn)
+	if err != nil {
+		return ""
+	}
+	return cfg.dataset
+}
+
+// bigqueryTableName quotes a table as dataset.table
+func bigqueryTableName(schema, table string) string {
+	if schema == "" {
+		return QuoteIdentifier("bigquery", table)
+	}
+	return QuoteIdentifier("bigquery", schema) + "." + QuoteIdentifier("bigquery", table)
+}
+
+// bigqueryInformationSchema names a view of a dataset's INFORMATION_SCHEMA
+func bigqueryInformationSchema(dataset, view string) string {
+	return QuoteIdentifier("bigquery", dataset) + ".INFORMATION_SCHEMA." + view
+}
+
+// bigqueryDriver opens a client for the project of a connection string
+type bigqueryDriver struct{}
+
+func (bigqueryDriver) Open(dsn string) (driver.Conn, error) {
+	cfg, err := parseBigQueryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	var opts []option.ClientOption
+	if cfg.credentials != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.credentials))
+	}
+	client, err := bigquery.NewClient(context.Background(), cfg.project, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery: %w", err)
+	}
+	return &bigqueryConn{cfg: cfg, client: client}, nil
+}
+
+// bigqueryConn runs queries with the connection's dataset as the default one
+type bigqueryConn struct {
+	cfg    bigqueryConfig
+	client *bigquery.Client
+}
+
+var (
+	_ driver.QueryerContext = (*bigqueryConn)(nil)
+	_ driver.ExecerContext  = (*bigqueryConn)(nil)
+	_ driver.Pinger         = (*bigqueryConn)(nil)
+)
+
+func (c *bigqueryConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("bigquery: prepared statements are not supported")
+}
+
+func (c *bigqueryConn) Close() error { return c.client.Close() }
+
+func (c *bigqueryConn) Begin() (driver.Tx, error) { return nil, errBigQueryTransactions }
+
+// Ping lists the project's first dataset, which needs the same access as browsing
+func (c *bigqueryConn) Ping(ctx context.Context) error {
+	if _, err := c.client.Datasets(ctx).Next(); err != nil && err != iterator.Done {
+		return fmt.Errorf("bigquery: %w", err)
+	}
+	return nil
+}
+
+func (c *bigqueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q := c.client.Query(query)
+	q.Location = c.cfg.location
+	if c.cfg.dataset != "" {
+		q.DefaultProjectID, q.DefaultDatasetID = c.cfg.project, c.cfg.dataset
+	}
+	for _, arg := range args {
+		q.Parameters = append(q.Parameters, bigquery.QueryParameter{Name: arg.Name, Value: arg.Value})
+	}
+	if err := checkBigQueryReadOnly(ctx, q); err != nil {
+		return nil, err
+	}
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery: %w", err)
+	}
+	// The schema is only known once the first row has been read
+	rows := &bigqueryRows{it: it}
+	if err := rows.read(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (c *bigqueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	rows, err := c.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), rows.Close()
+}
+
+// checkBigQueryReadOnly dry-runs a query, which costs nothing, and refuses it
+// unless BigQuery reports it as a SELECT
+func checkBigQueryReadOnly(ctx context.Context, q *bigquery.Query) error {
+	q.DryRun = true
+	defer func() { q.DryRun = false }()
+	job, err := q.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("bigquery: %w", err)
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return errBigQueryReadOnly
+	}
+	stats, ok := status.Statistics.Details.(*bigquery.QueryStatistics)
+	if !ok || stats.StatementType != "SELECT" {
+		return errBigQueryReadOnly
+	}
+	return nil
+}
+
+// bigqueryRows reads query results a row ahead, so the schema is known before
+// the first call to Next
+type bigqueryRows struct {
+	it   *bigquery.RowIterator
+	next []bigquery.Value
+	done bool
+}
+
+// read fetches the row after the current one
+func (r *bigqueryRows) read() error {
+	r.next = nil
+	err := r.it.Next(&r.next)
+	if err == iterator.Done {
+		r.done = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("bigquery: %w", err)
+	}
+	return nil
+}
+
+func (r *bigqueryRows) Columns() []string {
+	columns := make([]string, len(r.it.Schema))
+	for i, field := range r.it.Schema {
+		columns[i] = field.Name
+	}
+	return columns
+}
+
+func (r *bigqueryRows) Close() error { return nil }
+
+func (r *bigqueryRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	for i := range dest {
+		dest[i] = nil
+		if i < len(r.next) {
+			dest[i] = bigqueryValue(r.next[i])
+		}
+	}
+	return r.read()
+}
+
+// ColumnTypeDatabaseTypeName returns the column's type, e.g. STRING or INTEGER
+func (r *bigqueryRows) ColumnTypeDatabaseTypeName(index int) string {
+	field := r.it.Schema[index]
+	if field.Repeated {
+		return "ARRAY<" + string(field.Type) + ">"
+	}
+	return string(field.Type)
+}
+
+// bigqueryValue converts a value read by the client to a driver value. Dates
+// and times without a zone and numerics are written out as text, and arrays and
+// records as JSON.
+func bigqueryValue(value bigquery.Value) driver.Value {
+	switch v := value.(type) {
+	case nil, int64, float64, bool, string, []byte, time.Time:
+		return v
+	case *big.Rat:
+		// NUMERIC and BIGNUMERIC values are exact decimals of at most 38 places
+		text := v.FloatString(38)
+		return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// bigquerySchemas lists the datasets of the connection's project, the one the
+// connection string names first
+func bigquerySchemas(ctx context.Context, db *sql.DB) ([]models.SchemaInfo, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var schemas []models.SchemaInfo
+	err = conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*bigqueryConn)
+		if !ok {
+			return fmt.Errorf("not a BigQuery connection")
+		}
+		datasets := c.client.Datasets(ctx)
+		for {
+			dataset, err := datasets.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("bigquery: %w", err)
+			}
+			schema := models.SchemaInfo{Name: dataset.DatasetID, Description: "Dataset"}
+			if dataset.DatasetID == c.cfg.dataset {
+				schema.Description = "Default dataset"
+				schemas = append([]models.SchemaInfo{schema}, schemas...)
+				continue
+			}
+			schemas = append(schemas, schema)
+		}
+	})
+	return schemas, err
+}
+
+// bigqueryTableInfos lists the tables and views of a dataset. Row counts are
+// left out, since they are only kept per region.
+func bigqueryTableInfos(ctx context.Context, db *sql.DB, schema string) ([]models.TableInfo, error) {
+	query := "SELECT table_name, table_type FROM " + bigqueryInformationSchema(schema, "TABLES") + " ORDER BY table_type, table_name"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tableInfos []models.TableInfo
+	for rows.Next() {
+		info := models.TableInfo{Schema: schema}
+		if err := rows.Scan(&info.Name, &info.TableType); err != nil {
+			return nil, fmt.Errorf("failed to read BigQuery table: %w", err)
+		}
+		info.Description = " Table"
+		if strings.HasSuffix(info.TableType, "VIEW") {
+			info.Description = "👁️ View"
+		}
+		tableInfos = append(tableInfos, info)
+	}
+	return tableInfos, rows.Err()
+}
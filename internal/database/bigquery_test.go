@@ -0,0 +1,67 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+func TestParseBigQueryDSN(t *testing.T) {
+	cfg, err := parseBigQueryDSN("bigquery://my-project/sales?credentials=/keys/reader.json&location=EU")
+	if err != nil {
+		t.Fatalf("parseBigQueryDSN() error = %v", err)
+	}
+	want := bigqueryConfig{project: "my-project", dataset: "sales", credentials: "/keys/reader.json", location: "EU"}
+	if cfg != want {
+		t.Errorf("parseBigQueryDSN() = %+v, want %+v", cfg, want)
+	}
+
+	if cfg, err := parseBigQueryDSN("bigquery://my-project"); err != nil || cfg.dataset != "" {
+		t.Errorf("parseBigQueryDSN(without a dataset) = %+v, %v", cfg, err)
+	}
+	for _, dsn := range []string{
+		"my-project/sales",                           // Not a URL
+		"bigquery:///sales",                          // No project
+		"bigquery://my-project/sales/orders",         // A table instead of a dataset
+		"bigquery://my-project/sales?credential=key", // A misspelled parameter
+	} {
+		if _, err := parseBigQueryDSN(dsn); err == nil {
+			t.Errorf("parseBigQueryDSN(%q) accepted it", dsn)
+		}
+	}
+}
+
+func TestBigQueryQuoting(t *testing.T) {
+	if got, want := QualifiedTableName("bigquery", "sales", "order`s"), "`sales`.`order\\`s`"; got != want {
+		t.Errorf("QualifiedTableName() = %s, want %s", got, want)
+	}
+	got, err := FilterWhereClause("bigquery", `it's \n`, []string{"name"}, nil)
+	if want := "(CAST(`name` AS STRING) LIKE '%it\\'s \\\\n%')"; err != nil || got != want {
+		t.Errorf("FilterWhereClause() = %s, %v, want %s", got, err, want)
+	}
+}
+
+func TestBigQueryValue(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value bigquery.Value
+		want  interface{}
+	}{
+		{nil, nil},
+		{int64(42), int64(42)},
+		{"text", "text"},
+		{at, at},
+		{civil.Date{Year: 2024, Month: 3, Day: 1}, "2024-03-01"},
+		{big.NewRat(3, 2), "1.5"},
+		{big.NewRat(7, 1), "7"},
+		{[]bigquery.Value{int64(1), "a"}, `[1,"a"]`},
+	}
+	for _, tt := range tests {
+		if got := bigqueryValue(tt.value); got != tt.want {
+			t.Errorf("bigqueryValue(%#v) = %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+}
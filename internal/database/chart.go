@@ -0,0 +1,110 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// bucketLayouts formats the start of each time bucket where the driver returns it as a time
+var bucketLayouts = map[string]string{
+	"hour":  "2006-01-02 15:00",
+	"day":   "2006-01-02",
+	"week":  "2006-01-02",
+	"month": "2006-01",
+	"year":  "2006",
+}
+
+// TimeBucketExpression truncates a time column to the start of its hour, day,
+// week (starting Monday), month, or year
+func TimeBucketExpression(driver, column, bucket string) (string, error) {
+	if _, ok := bucketLayouts[bucket]; !ok {
+		return "", fmt.Errorf("unknown time bucket: %s", bucket)
+	}
+	col := QuoteIdentifier(driver, column)
+
+	switch driver {
+	case "postgres", "cockroach", "redshift":
+		return fmt.Sprintf("date_trunc('%s', %s)", bucket, col), nil
+	case "mysql", "mariadb":
+		if bucket == "week" {
+			return fmt.Sprintf("DATE(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY))", col, col), nil
+		}
+		formats := map[string]string{"hour": "%Y-%m-%d %H:00", "day": "%Y-%m-%d", "month": "%Y-%m", "year": "%Y"}
+		return fmt.Sprintf("DATE_FORMAT(%s, '%s')", col, formats[bucket]), nil
+	case "clickhouse":
+		functions := map[string]string{"hour": "toStartOfHour", "day": "toDate", "week": "toMonday", "month": "toStartOfMonth", "year": "toStartOfYear"}
+		return fmt.Sprintf("%s(%s)", functions[bucket], col), nil
+	case "sqlite3":
+		if bucket == "week" {
+			return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", col), nil
+		}
+		formats := map[string]string{"hour": "%Y-%m-%d %H:00", "day": "%Y-%m-%d", "month": "%Y-%m", "year": "%Y"}
+		return fmt.Sprintf("strftime('%s', %s)", formats[bucket], col), nil
+	default:
+		return "", fmt.Errorf("time-series charts are not supported for %s", driver)
+	}
+}
+
+// GetTimeSeries aggregates a numeric column per time bucket of a time column and
+// returns the latest limit buckets, oldest first. Rows without a time are left
+// out; count counts the rows with a value.
+func GetTimeSeries(db *sql.DB, driver, schema, table, timeColumn, valueColumn, bucket, aggregate string, limit int) ([]models.ChartPoint, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	bucketExpr, err := TimeBucketExpression(driver, timeColumn, bucket)
+	if err != nil {
+		return nil, err
+	}
+	switch aggregate {
+	case "avg", "sum", "min", "max", "count":
+	default:
+		return nil, fmt.Errorf("unknown aggregate: %s", aggregate)
+	}
+	tableName, err := tableRef(driver, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s, %s(%s) FROM %s WHERE %s IS NOT NULL GROUP BY 1 ORDER BY 1 DESC LIMIT %d",
+		bucketExpr, strings.ToUpper(aggregate), QuoteIdentifier(driver, valueColumn), tableName, QuoteIdentifier(driver, timeColumn), limit)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.ChartPoint
+	for rows.Next() {
+		var start any
+		var value sql.NullFloat64
+		if err := rows.Scan(&start, &value); err != nil {
+			return nil, err
+		}
+		point := models.ChartPoint{Value: value.Float64, Null: !value.Valid}
+		switch v := start.(type) {
+		case time.Time:
+			point.Bucket = v.Format(bucketLayouts[bucket])
+		case []byte:
+			point.Bucket = string(v)
+		case nil:
+			continue
+		default:
+			point.Bucket = fmt.Sprint(v)
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The query reads the newest buckets first so the limit keeps the latest ones
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ClickHouse is reached through its MySQL wire protocol interface (port 9004 by
+// default), so the MySQL driver is registered again under its own name and the
+// "clickhouse" driver gets its own metadata queries.
+func init() {
+	sql.Register("clickhouse", clickhouseDriver{})
+}
+
+// clickhouseDriver opens MySQL protocol connections with ClickHouse-friendly settings
+type clickhouseDriver struct {
+	mysql.MySQLDriver
+}
+
+func (d clickhouseDriver) Open(dsn string) (driver.Conn, error) {
+	normalized, err := ClickHouseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return d.MySQLDriver.Open(normalized)
+}
+
+// OpenConnector is what database/sql uses, so the DSN is adjusted here as well
+func (d clickhouseDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	normalized, err := ClickHouseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return d.MySQLDriver.OpenConnector(normalized)
+}
+
+// ClickHouseDSN turns on client-side placeholder interpolation, since the
+// ClickHouse MySQL interface does not support server-side prepared statements
+func ClickHouseDSN(dsn string) (string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	cfg.InterpolateParams = true
+	return cfg.FormatDSN(), nil
+}
+
+// clickhouseSchemaFilter matches the selected database, or the connection's
+// current database when no database is selected. It takes the schema as its argument.
+const clickhouseSchemaFilter = "COALESCE(NULLIF(?, ''), currentDatabase())"
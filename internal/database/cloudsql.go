@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/cloudsqlconn"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// Cloud SQL instances can be reached through the Cloud SQL Go connector instead
+// of a host, so the auth proxy doesn't have to run alongside. A PostgreSQL or
+// MySQL connection string names the instance in a parameter, and may turn on
+// IAM database authentication, where the user is the IAM principal and no
+// password is needed:
+//
+//	postgres://me%40example.com@localhost/app?cloudsql-instance=project:region:instance&cloudsql-iam=true
+//	app:secret@tcp(localhost)/app?cloudsql-instance=project:region:instance
+//
+// The connector signs in with the application default credentials and encrypts
+// the connection itself, so the host, port, and TLS settings are ignored.
+
+const (
+	// CloudSQLInstanceParam is the connection string parameter that holds the
+	// instance connection name, project:region:instance
+	CloudSQLInstanceParam = "cloudsql-instance"
+	// CloudSQLIAMParam is the connection string parameter that turns on IAM
+	// database authentication
+	CloudSQLIAMParam = "cloudsql-iam"
+)
+
+// SupportsCloudSQL reports whether the driver can connect through the Cloud SQL connector
+func SupportsCloudSQL(driver string) bool {
+	return driver == "postgres" || driver == "mysql"
+}
+
+// cloudSQLTarget is the instance a connection string reaches through the connector
+type cloudSQLTarget struct {
+	instance string
+	iam      bool
+}
+
+// splitCloudSQL takes the Cloud SQL parameters off a connection string, which
+// the drivers would refuse or send to the server as settings. Each form of
+// string is read the way its driver reads it, so a ? in a MySQL password or a /
+// in a parameter such as sslrootcert is not taken for a separator. A string
+// without the parameters, or one that cannot be read, is returned unchanged.
+func splitCloudSQL(driver, dsn string) (string, cloudSQLTarget) {
+	switch {
+	case driver == "mysql" || driver == "mariadb" || driver == "clickhouse":
+		return splitCloudSQLMySQL(dsn)
+	case strings.Contains(dsn, "://"):
+		return splitCloudSQLURL(dsn)
+	default:
+		return splitCloudSQLKeywords(dsn)
+	}
+}
+
+// cloudSQLIAM reads the value of the IAM parameter
+func cloudSQLIAM(value string) bool {
+	return value == "true" || value == "1"
+}
+
+// splitCloudSQLURL takes the Cloud SQL parameters off the query of a URL
+func splitCloudSQLURL(dsn string) (string, cloudSQLTarget) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn, cloudSQLTarget{}
+	}
+	query := u.Query()
+	if !query.Has(CloudSQLInstanceParam) && !query.Has(CloudSQLIAMParam) {
+		return dsn, cloudSQLTarget{}
+	}
+	target := cloudSQLTarget{instance: query.Get(CloudSQLInstanceParam), iam: cloudSQLIAM(query.Get(CloudSQLIAMParam))}
+	query.Del(CloudSQLInstanceParam)
+	query.Del(CloudSQLIAMParam)
+	u.RawQuery = query.Encode()
+	return u.String(), target
+}
+
+// splitCloudSQLMySQL takes the Cloud SQL parameters off a MySQL DSN
+func splitCloudSQLMySQL(dsn string) (string, cloudSQLTarget) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return dsn, cloudSQLTarget{}
+	}
+	instance, hasInstance := cfg.Params[CloudSQLInstanceParam]
+	iam, hasIAM := cfg.Params[CloudSQLIAMParam]
+	if !hasInstance && !hasIAM {
+		return dsn, cloudSQLTarget{}
+	}
+	delete(cfg.Params, CloudSQLInstanceParam)
+	delete(cfg.Params, CloudSQLIAMParam)
+	return cfg.FormatDSN(), cloudSQLTarget{instance: instance, iam: cloudSQLIAM(iam)}
+}
+
+// splitCloudSQLKeywords takes the Cloud SQL parameters off a key=value string
+// such as "host=db user=app"
+func splitCloudSQLKeywords(dsn string) (string, cloudSQLTarget) {
+	var target cloudSQLTarget
+	var kept []string
+	found := false
+	for _, field := range KeywordFields(dsn) {
+		name, value, _ := strings.Cut(field, "=")
+		switch name {
+		case CloudSQLInstanceParam:
+			target.instance = keywordValue(value)
+		case CloudSQLIAMParam:
+			target.iam = cloudSQLIAM(keywordValue(value))
+		default:
+			kept = append(kept, field)
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return dsn, cloudSQLTarget{}
+	}
+	return strings.Join(kept, " "), target
+}
+
+// validateCloudSQL checks the Cloud SQL parameters of a connection string
+func validateCloudSQL(driver, dsn string) error {
+	_, target := splitCloudSQL(driver, dsn)
+	if target.instance == "" {
+		return nil
+	}
+	if !SupportsCloudSQL(driver) {
+		return fmt.Errorf("%s cannot connect through the Cloud SQL connector: only PostgreSQL and MySQL can", driver)
+	}
+	if parts := strings.Split(target.instance, ":"); len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("%s=%s is not an instance connection name: use project:region:instance", CloudSQLInstanceParam, target.instance)
+	}
+	return nil
+}
+
+var (
+	// cloudSQLDialers are created on first use, one for built-in users and one
+	// for IAM authentication, and shared so instance certificates are fetched once
+	cloudSQLDialers   = map[bool]*cloudsqlconn.Dialer{}
+	cloudSQLDialersMu sync.Mutex
+	// registerCloudSQLNets registers the MySQL networks that dial through them
+	registerCloudSQLNets sync.Once
+)
+
+// cloudSQLDialer returns the shared dialer with or without IAM authentication
+func cloudSQLDialer(iam bool) (*cloudsqlconn.Dialer, error) {
+	cloudSQLDialersMu.Lock()
+	defer cloudSQLDialersMu.Unlock()
+	if d, ok := cloudSQLDialers[iam]; ok {
+		return d, nil
+	}
+	var opts []cloudsqlconn.Option
+	if iam {
+		opts = append(opts, cloudsqlconn.WithIAMAuthN())
+	}
+	d, err := cloudsqlconn.NewDialer(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start the Cloud SQL connector: %w", err)
+	}
+	cloudSQLDialers[iam] = d
+	return d, nil
+}
+
+// cloudSQLNet names the MySQL network that dials through the connector
+func cloudSQLNet(iam bool) string {
+	if iam {
+		return "cloudsql-iam"
+	}
+	return "cloudsql"
+}
+
+// cloudSQLConnector opens connections of a PostgreSQL or MySQL connection string
+// to the target instance through the connector
+func cloudSQLConnector(driverName, dsn string, target cloudSQLTarget) (driver.Connector, error) {
+	d, err := cloudSQLDialer(target.iam)
+	if err != nil {
+		return nil, err
+	}
+	switch driverName {
+	case "postgres":
+		if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+			if dsn, err = pq.ParseURL(dsn); err != nil {
+				return nil, err
+			}
+		}
+		// The connector already encrypts the connection; later settings win
+		connector, err := pq.NewConnector(dsn + " sslmode=disable")
+		if err != nil {
+			return nil, err
+		}
+		connector.Dialer(pqCloudSQLDialer{dialer: d, instance: target.instance})
+		return connector, nil
+	case "mysql":
+		registerCloudSQLNets.Do(func() {
+			for _, iam := range []bool{false, true} {
+				mysql.RegisterDialContext(cloudSQLNet(iam), func(ctx context.Context, instance string) (net.Conn, error) {
+					d, err := cloudSQLDialer(iam)
+					if err != nil {
+						return nil, err
+					}
+					return d.Dial(ctx, instance)
+				})
+			}
+		})
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Net, cfg.Addr = cloudSQLNet(target.iam), target.instance
+		cfg.TLS, cfg.TLSConfig = nil, "false"
+		return mysql.NewConnector(cfg)
+	default:
+		return nil, fmt.Errorf("%s cannot connect through the Cloud SQL connector", driverName)
+	}
+}
+
+// pqCloudSQLDialer dials the instance through the connector for lib/pq, whatever
+// address the connection string names
+type pqCloudSQLDialer struct {
+	dialer   *cloudsqlconn.Dialer
+	instance string
+}
+
+func (p pqCloudSQLDialer) Dial(network, address string) (net.Conn, error) {
+	return p.DialContext(context.Background(), network, address)
+}
+
+func (p pqCloudSQLDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return p.DialContext(ctx, network, address)
+}
+
+func (p pqCloudSQLDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	return p.dialer.Dial(ctx, p.instance)
+}
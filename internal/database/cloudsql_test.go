@@ -0,0 +1,47 @@
+package database
+
+import "testing"
+
+func TestSplitCloudSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		dsn    string
+		want   string
+		target cloudSQLTarget
+	}{
+		{"no instance", "postgres", "postgres://app@db.example.com:5432/shop?sslmode=require", "postgres://app@db.example.com:5432/shop?sslmode=require", cloudSQLTarget{}},
+		{"postgres URL", "postgres", "postgres://app@localhost:5432/shop?cloudsql-instance=acme%3Aeurope-west1%3Ashop&sslmode=disable", "postgres://app@localhost:5432/shop?sslmode=disable", cloudSQLTarget{instance: "acme:europe-west1:shop"}},
+		{"postgres URL with IAM", "postgres", "postgres://me%40acme.com@localhost/shop?cloudsql-iam=true&cloudsql-instance=acme:us-east1:shop", "postgres://me%40acme.com@localhost/shop", cloudSQLTarget{instance: "acme:us-east1:shop", iam: true}},
+		{"postgres URL with a path parameter", "postgres", "postgres://app@localhost/shop?sslrootcert=/path/ca.pem&cloudsql-instance=acme:us-east1:shop&sslmode=verify-full", "postgres://app@localhost/shop?sslmode=verify-full&sslrootcert=%2Fpath%2Fca.pem", cloudSQLTarget{instance: "acme:us-east1:shop"}},
+		{"postgres URL without the parameters is kept as typed", "postgres", "postgres://app@localhost/shop?sslrootcert=/path/ca.pem", "postgres://app@localhost/shop?sslrootcert=/path/ca.pem", cloudSQLTarget{}},
+		{"postgres key=value", "postgres", "host=localhost dbname=shop cloudsql-instance=acme:us-east1:shop cloudsql-iam=1", "host=localhost dbname=shop", cloudSQLTarget{instance: "acme:us-east1:shop", iam: true}},
+		{"postgres key=value with quoted values", "postgres", "host=localhost sslrootcert='/path/my ca.pem' cloudsql-instance='acme:us-east1:shop'", "host=localhost sslrootcert='/path/my ca.pem'", cloudSQLTarget{instance: "acme:us-east1:shop"}},
+		{"mysql with a ? in the password", "mysql", "app:a?b@tcp(localhost:3306)/shop?parseTime=true&cloudsql-instance=acme:us-east1:shop", "app:a?b@tcp(localhost:3306)/shop?parseTime=true", cloudSQLTarget{instance: "acme:us-east1:shop"}},
+		{"mysql with a path parameter and IAM", "mysql", "app@tcp(localhost:3306)/shop?tls-ca=%2Fpath%2Fca.pem&cloudsql-iam=true&cloudsql-instance=acme%3Aus-east1%3Ashop", "app@tcp(localhost:3306)/shop?tls-ca=%2Fpath%2Fca.pem", cloudSQLTarget{instance: "acme:us-east1:shop", iam: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, target := splitCloudSQL(tt.driver, tt.dsn)
+			if got != tt.want || target != tt.target {
+				t.Errorf("splitCloudSQL(%s, %q) = %q, %+v, want %q, %+v", tt.driver, tt.dsn, got, target, tt.want, tt.target)
+			}
+		})
+	}
+}
+
+func TestValidateCloudSQL(t *testing.T) {
+	if err := ValidateConnectionString("postgres", "postgres://app@localhost/shop?cloudsql-instance=acme:us-east1:shop"); err != nil {
+		t.Errorf("ValidateConnectionString(postgres through Cloud SQL) error = %v", err)
+	}
+	for _, tt := range []struct{ driver, dsn string }{
+		{"postgres", "postgres://app@localhost/shop?cloudsql-instance=shop"},                   // Not project:region:instance
+		{"mysql", "app@tcp(localhost:3306)/shop?cloudsql-instance=acme::shop"},                 // No region
+		{"redshift", "postgres://app@localhost/dev?cloudsql-instance=acme:us-east1:warehouse"}, // Not hosted by Cloud SQL
+	} {
+		if err := ValidateConnectionString(tt.driver, tt.dsn); err == nil {
+			t.Errorf("ValidateConnectionString(%s, %q) accepted it", tt.driver, tt.dsn)
+		}
+	}
+}
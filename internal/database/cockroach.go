@@ -0,0 +1,32 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// CockroachDB speaks the PostgreSQL wire protocol, so lib/pq is registered again
+// under the "cockroach" name. Queries share the PostgreSQL dialect except where
+// CockroachDB's catalog differs: tables are listed with SHOW TABLES and the
+// pg_stat views, which CockroachDB does not implement, are not used.
+func init() {
+	sql.Register("cockroach", &pq.Driver{})
+}
+
+// cockroachSystemSchemas are the virtual schemas every CockroachDB database has
+const cockroachSystemSchemas = "'information_schema', 'pg_catalog', 'pg_extension', 'crdb_internal'"
+
+// cockroachShowTables lists the objects in a schema of the current database.
+// SHOW TABLES does not take placeholders, so the schema is quoted as an identifier.
+func cockroachShowTables(schema string) string {
+	if schema == "" {
+		schema = "public"
+	}
+	return "[SHOW TABLES FROM " + QuoteIdentifier("cockroach", schema) + "]"
+}
+
+// errCockroachStats is returned by the size and activity statistics, which are
+// read from pg_stat views and size functions that CockroachDB does not implement
+var errCockroachStats = fmt.Errorf("database statistics are not available for CockroachDB")
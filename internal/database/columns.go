@@ -8,34 +8,62 @@ import (
 
 // GetColumns retrieves column information for a specific table
 func GetColumns(db *sql.DB, driver, tableName, schema string) ([][]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var query string
 	switch driver {
-	case "postgres":
+	case "postgres", "cockroach", "redshift":
 		query = `SELECT column_name, data_type, is_nullable, column_default 
 				 FROM information_schema.columns 
 				 WHERE table_name = $1 AND table_schema = $2
 				 ORDER BY ordinal_position`
-	case "mysql":
-		query = `SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT 
+	case "mysql", "mariadb":
+		query = `SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT,
+					CHARACTER_SET_NAME, COLLATION_NAME
 				 FROM INFORMATION_SCHEMA.COLUMNS 
-				 WHERE TABLE_NAME = ? 
+				 WHERE TABLE_NAME = ? AND TABLE_SCHEMA = ` + mysqlSchemaFilter + `
 				 ORDER BY ORDINAL_POSITION`
 	case "sqlite3":
-		query = fmt.Sprintf("PRAGMA table_info(%s)", tableName)
+		query = sqlitePragma(schema, "table_info", tableName)
+	case "clickhouse":
+		query = `SELECT name, type,
+					if(startsWith(type, 'Nullable('), 'YES', 'NO'),
+					nullIf(default_expression, '')
+				 FROM system.columns
+				 WHERE table = ? AND database = ` + clickhouseSchemaFilter + `
+				 ORDER BY position`
+	case "trino":
+		catalog, name := SplitTrinoSchema(schema)
+		query = `SELECT column_name, data_type, is_nullable, column_default
+				 FROM ` + trinoInformationSchema(catalog) + `.columns
+				 WHERE table_name = ? AND table_schema = ` + trinoSchemaFilter + `
+				 ORDER BY ordinal_position`
+		schema = name
+	case "snowflake":
+		query = `SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT
+				 FROM INFORMATION_SCHEMA.COLUMNS
+				 WHERE TABLE_NAME = ? AND TABLE_SCHEMA = ` + snowflakeSchemaFilter + `
+				 ORDER BY ORDINAL_POSITION`
+	case "bigquery":
+		query = `SELECT column_name, data_type, is_nullable, column_default
+				 FROM ` + bigqueryInformationSchema(schema, "COLUMNS") + `
+				 WHERE table_name = ?
+				 ORDER BY ordinal_position`
 	}
 
 	var rows *sql.Rows
 	var err error
 
 	switch driver {
-	case "postgres":
-		rows, err = db.Query(query, tableName, schema)
-	case "mysql":
-		rows, err = db.Query(query, tableName)
+	case "postgres", "cockroach", "redshift":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
+	case "mysql", "mariadb", "clickhouse", "trino", "snowflake":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
 	case "sqlite3":
-		rows, err = db.Query(query)
+		rows, err = db.QueryContext(ctx, query)
 	default:
-		rows, err = db.Query(query, tableName)
+		rows, err = db.QueryContext(ctx, query, tableName)
 	}
 
 	if err != nil {
@@ -68,6 +96,27 @@ func GetColumns(db *sql.DB, driver, tableName, schema string) ([][]string, error
 			}
 
 			columns = append(columns, []string{name, dataType, nullable, def})
+		} else if driver == "mysql" || driver == "mariadb" {
+			var name, dataType, nullable string
+			var defaultValue, charset, collation sql.NullString
+
+			err := rows.Scan(&name, &dataType, &nullable, &defaultValue, &charset, &collation)
+			if err != nil {
+				return nil, err
+			}
+
+			def := ""
+			if defaultValue.Valid {
+				def = defaultValue.String
+			}
+
+			// Only character columns carry a charset and collation
+			encoding := ""
+			if charset.Valid {
+				encoding = charset.String + " / " + collation.String
+			}
+
+			columns = append(columns, []string{name, dataType, nullable, def, encoding})
 		} else {
 			var name, dataType, nullable string
 			var defaultValue sql.NullString
@@ -91,6 +140,9 @@ func GetColumns(db *sql.DB, driver, tableName, schema string) ([][]string, error
 
 // GetIndexes retrieves index information for a specific table
 func GetIndexes(db *sql.DB, driver, tableName, schema string) ([][]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var query string
 	switch driver {
 	case "postgres":
@@ -107,7 +159,40 @@ func GetIndexes(db *sql.DB, driver, tableName, schema string) ([][]string, error
 				JOIN pg_class ON pg_class.relname = i.indexname
 				WHERE i.tablename = $1 AND i.schemaname = $2
 				ORDER BY i.indexname`
-	case "mysql":
+	case "redshift":
+		// Redshift has no indexes; its sort key and distribution style play their role.
+		// svv_table_info only lists tables that hold data.
+		query = `SELECT 
+					'sortkey', 'SORTKEY (' || sortkey1 || ')', 'SORTKEY', sortkey1
+				FROM svv_table_info
+				WHERE "table" = $1 AND "schema" = $2 AND sortkey1 IS NOT NULL
+				UNION ALL
+				SELECT 
+					'diststyle', 'DISTSTYLE ' || diststyle, 'DISTSTYLE', diststyle
+				FROM svv_table_info
+				WHERE "table" = $1 AND "schema" = $2 AND diststyle IS NOT NULL`
+	case "cockroach":
+		// Index names are only unique per table in CockroachDB, so pg_indexes cannot
+		// be joined to pg_class by name; information_schema.statistics lists the key columns
+		query = `SELECT 
+					s.index_name,
+					'INDEX ON (' || string_agg(s.column_name, ', ' ORDER BY s.seq_in_index) || ')' as index_definition,
+					CASE 
+						WHEN bool_or(tc.constraint_type = 'PRIMARY KEY') THEN 'PRIMARY'
+						WHEN s.non_unique = 'NO' THEN 'UNIQUE'
+						ELSE 'INDEX'
+					END as index_type,
+					string_agg(s.column_name, ', ' ORDER BY s.seq_in_index) as columns
+				FROM information_schema.statistics s
+				LEFT JOIN information_schema.table_constraints tc
+					ON tc.table_schema = s.table_schema
+					AND tc.table_name = s.table_name
+					AND tc.constraint_name = s.index_name
+				WHERE s.table_name = $1 AND s.table_schema = $2
+					AND s.storing = 'NO' AND s.implicit = 'NO'
+				GROUP BY s.index_name, s.non_unique
+				ORDER BY s.index_name`
+	case "mysql", "mariadb":
 		query = `SELECT 
 					INDEX_NAME as index_name,
 					CONCAT('INDEX ON ', COLUMN_NAME) as index_definition,
@@ -118,25 +203,25 @@ func GetIndexes(db *sql.DB, driver, tableName, schema string) ([][]string, error
 					END as index_type,
 					GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX) as columns
 				FROM INFORMATION_SCHEMA.STATISTICS 
-				WHERE TABLE_NAME = ? AND TABLE_SCHEMA = DATABASE()
+				WHERE TABLE_NAME = ? AND TABLE_SCHEMA = ` + mysqlSchemaFilter + `
 				GROUP BY INDEX_NAME, NON_UNIQUE
 				ORDER BY INDEX_NAME`
 	case "sqlite3":
-		query = fmt.Sprintf("PRAGMA index_list(%s)", tableName)
+		query = sqlitePragma(schema, "index_list", tableName)
 	}
 
 	var rows *sql.Rows
 	var err error
 
 	switch driver {
-	case "postgres":
-		rows, err = db.Query(query, tableName, schema)
-	case "mysql":
-		rows, err = db.Query(query, tableName)
+	case "postgres", "cockroach", "redshift":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
+	case "mysql", "mariadb":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
 	case "sqlite3":
-		rows, err = db.Query(query)
+		rows, err = db.QueryContext(ctx, query)
 	default:
-		rows, err = db.Query(query, tableName)
+		rows, err = db.QueryContext(ctx, query, tableName)
 	}
 
 	if err != nil {
@@ -163,8 +248,8 @@ func GetIndexes(db *sql.DB, driver, tableName, schema string) ([][]string, error
 			}
 
 			// Get columns for this index
-			indexInfoQuery := fmt.Sprintf("PRAGMA index_info(%s)", name)
-			indexInfoRows, err := db.Query(indexInfoQuery)
+			indexInfoQuery := sqlitePragma(schema, "index_info", name)
+			indexInfoRows, err := db.QueryContext(ctx, indexInfoQuery)
 			if err != nil {
 				continue
 			}
@@ -200,9 +285,12 @@ func GetIndexes(db *sql.DB, driver, tableName, schema string) ([][]string, error
 
 // GetConstraints retrieves constraint information for a specific table
 func GetConstraints(db *sql.DB, driver, tableName, schema string) ([][]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var query string
 	switch driver {
-	case "postgres":
+	case "postgres", "cockroach", "redshift":
 		query = `SELECT 
 					tc.constraint_name,
 					tc.constraint_type,
@@ -217,7 +305,7 @@ func GetConstraints(db *sql.DB, driver, tableName, schema string) ([][]string, e
 					AND tc.table_schema = ccu.table_schema
 				WHERE tc.table_name = $1 AND tc.table_schema = $2
 				ORDER BY tc.constraint_name, kcu.ordinal_position`
-	case "mysql":
+	case "mysql", "mariadb":
 		query = `SELECT 
 					CONSTRAINT_NAME as constraint_name,
 					CONSTRAINT_TYPE as constraint_type,
@@ -227,24 +315,24 @@ func GetConstraints(db *sql.DB, driver, tableName, schema string) ([][]string, e
 				JOIN INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc 
 					ON kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME 
 					AND kcu.TABLE_SCHEMA = tc.TABLE_SCHEMA
-				WHERE kcu.TABLE_NAME = ? AND kcu.TABLE_SCHEMA = DATABASE()
+				WHERE kcu.TABLE_NAME = ? AND kcu.TABLE_SCHEMA = ` + mysqlSchemaFilter + `
 				ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`
 	case "sqlite3":
-		query = fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName)
+		query = sqlitePragma(schema, "foreign_key_list", tableName)
 	}
 
 	var rows *sql.Rows
 	var err error
 
 	switch driver {
-	case "postgres":
-		rows, err = db.Query(query, tableName, schema)
-	case "mysql":
-		rows, err = db.Query(query, tableName)
+	case "postgres", "cockroach", "redshift":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
+	case "mysql", "mariadb":
+		rows, err = db.QueryContext(ctx, query, tableName, schema)
 	case "sqlite3":
-		rows, err = db.Query(query)
+		rows, err = db.QueryContext(ctx, query)
 	default:
-		rows, err = db.Query(query, tableName)
+		rows, err = db.QueryContext(ctx, query, tableName)
 	}
 
 	if err != nil {
@@ -288,11 +376,14 @@ func GetConstraints(db *sql.DB, driver, tableName, schema string) ([][]string, e
 
 // GetForeignKeyRelationships retrieves all foreign key relationships in the database
 func GetForeignKeyRelationships(db *sql.DB, driver, schema string) ([][]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var query string
 	var args []interface{}
 
 	switch driver {
-	case "postgres":
+	case "postgres", "cockroach", "redshift":
 		query = `
 			SELECT 
 				tc.table_name as from_table,
@@ -313,7 +404,7 @@ func GetForeignKeyRelationships(db *sql.DB, driver, schema string) ([][]string,
 			ORDER BY tc.table_name, kcu.ordinal_position`
 		args = []interface{}{schema}
 
-	case "mysql":
+	case "mysql", "mariadb":
 		query = `
 			SELECT 
 				TABLE_NAME as from_table,
@@ -325,14 +416,14 @@ func GetForeignKeyRelationships(db *sql.DB, driver, schema string) ([][]string,
 				INFORMATION_SCHEMA.KEY_COLUMN_USAGE 
 			WHERE 
 				REFERENCED_TABLE_NAME IS NOT NULL
-				AND TABLE_SCHEMA = DATABASE()
+				AND TABLE_SCHEMA = ` + mysqlSchemaFilter + `
 			ORDER BY TABLE_NAME, ORDINAL_POSITION`
-		args = []interface{}{}
+		args = []interface{}{schema}
 
 	case "sqlite3":
 		// For SQLite, we need to get foreign keys from all tables
-		tableQuery := "SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%'"
-		tableRows, err := db.Query(tableQuery)
+		tableQuery := "SELECT name FROM " + sqliteMaster(schema) + " WHERE type='table' AND name NOT LIKE 'sqlite_%'"
+		tableRows, err := db.QueryContext(ctx, tableQuery)
 		if err != nil {
 			return nil, err
 		}
@@ -346,8 +437,8 @@ func GetForeignKeyRelationships(db *sql.DB, driver, schema string) ([][]string,
 			}
 
 			// Get foreign keys for this table
-			fkQuery := fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName)
-			fkRows, err := db.Query(fkQuery)
+			fkQuery := sqlitePragma(schema, "foreign_key_list", tableName)
+			fkRows, err := db.QueryContext(ctx, fkQuery)
 			if err != nil {
 				continue
 			}
@@ -373,7 +464,7 @@ func GetForeignKeyRelationships(db *sql.DB, driver, schema string) ([][]string,
 		return nil, fmt.Errorf("unsupported database driver: %s", driver)
 	}
 
-	rows, err := db.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
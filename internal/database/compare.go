@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetTableColumnNames returns a table's column names in declaration order
+func GetTableColumnNames(db *sql.DB, driver, schema, tableName string) ([]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SELECT * FROM "+QualifiedTableName(driver, schema, tableName)+" WHERE 1 = 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
+
+// GetKeyBounds returns the smallest and largest value of an integer key column.
+// ok is false when the table is empty.
+func GetKeyBounds(db *sql.DB, driver, schema, tableName, keyColumn string) (lo, hi int64, ok bool, err error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	key := QuoteIdentifier(driver, keyColumn)
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", key, key, QualifiedTableName(driver, schema, tableName))
+
+	var minKey, maxKey sql.NullInt64
+	if err := db.QueryRowContext(ctx, query).Scan(&minKey, &maxKey); err != nil {
+		return 0, 0, false, err
+	}
+	if !minKey.Valid || !maxKey.Valid {
+		return 0, 0, false, nil
+	}
+	return minKey.Int64, maxKey.Int64, true, nil
+}
+
+// KeyRange is the part of a table a comparison reads at a time. A single
+// integer key covers From through To; other keys cover the rows after After
+// through Through in key order, where a nil bound leaves that end open.
+type KeyRange struct {
+	Integer  bool
+	From, To int64
+	After    []interface{}
+	Through  []interface{}
+}
+
+// keyRangeCondition returns the WHERE clause selecting a key range and its
+// arguments. Keys are compared column by column rather than as row values, which
+// not every driver accepts.
+func keyRangeCondition(driver string, keys []string, r KeyRange) (string, []interface{}) {
+	numbered := models.DriverCapabilities(driver).NumberedPlaceholders
+	var args []interface{}
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		if numbered {
+			return fmt.Sprintf("$%d", len(args))
+		}
+		return "?"
+	}
+	quoted := QuoteIdentifiers(driver, keys)
+
+	var conditions []string
+	if r.Integer {
+		conditions = append(conditions, fmt.Sprintf("%s >= %s AND %s <= %s", quoted[0], bind(r.From), quoted[0], bind(r.To)))
+	}
+	// (k1, k2) > (a, b) is k1 > a OR (k1 = a AND k2 > b)
+	after := func(bound []interface{}) string {
+		var terms []string
+		for i := range bound {
+			var parts []string
+			for j := 0; j < i; j++ {
+				parts = append(parts, quoted[j]+" = "+bind(bound[j]))
+			}
+			parts = append(parts, quoted[i]+" > "+bind(bound[i]))
+			terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		}
+		return "(" + strings.Join(terms, " OR ") + ")"
+	}
+	if r.After != nil {
+		conditions = append(conditions, after(r.After))
+	}
+	if r.Through != nil {
+		conditions = append(conditions, "NOT "+after(r.Through))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// keyOrder orders rows by their key columns
+func keyOrder(driver string, keys []string) string {
+	return " ORDER BY " + strings.Join(QuoteIdentifiers(driver, keys), ", ")
+}
+
+// GetKeyBoundary returns the key of the size-th row after the key after, in key
+// order, to end a range of size rows there. ok is false when fewer rows are
+// left, so the range runs to the end of the table.
+func GetKeyBoundary(db *sql.DB, driver, schema, tableName string, keys []string, after []interface{}, size int) (boundary []interface{}, ok bool, err error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	where, args := keyRangeCondition(driver, keys, KeyRange{After: after})
+	query := fmt.Sprintf("SELECT %s FROM %s%s%s%s", strings.Join(QuoteIdentifiers(driver, keys), ", "),
+		QualifiedTableName(driver, schema, tableName), where, keyOrder(driver, keys), pageClause(driver, 1, size-1))
+
+	values := make([]interface{}, len(keys))
+	ptrs := make([]interface{}, len(keys))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	err = db.QueryRowContext(ctx, query, args...).Scan(ptrs...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	// Text keys come back as bytes, which some drivers would bind as binary
+	for i, v := range values {
+		if b, isBytes := v.([]byte); isBytes {
+			values[i] = string(b)
+		}
+	}
+	return values, true, nil
+}
+
+// rangeChecksumSQL builds the query that counts and checksums the rows of a key
+// range on the server. Each value is tagged so NULL and the empty string
+// differ. PostgreSQL-style drivers hash the row hashes in key order;
+// MySQL-style drivers sum the leading bits of each row hash, which needs no
+// ordering and cannot be cut short like GROUP_CONCAT.
+func rangeChecksumSQL(driver, schema, tableName string, columns, keys []string, r KeyRange) (string, []interface{}) {
+	quoted := QuoteIdentifiers(driver, columns)
+	values := make([]string, len(quoted))
+	var checksum string
+	switch driver {
+	case "mysql", "mariadb":
+		for i, col := range quoted {
+			values[i] = fmt.Sprintf("COALESCE(CONCAT('v', %s), 'n')", col)
+		}
+		checksum = fmt.Sprintf("SUM(CAST(CONV(SUBSTRING(MD5(CONCAT_WS(CHAR(31), %s)), 1, 15), 16, 10) AS UNSIGNED))", strings.Join(values, ", "))
+	default:
+		for i, col := range quoted {
+			values[i] = fmt.Sprintf("COALESCE('v' || %s::text, 'n')", col)
+		}
+		checksum = fmt.Sprintf("md5(string_agg(md5(concat_ws(chr(31), %s)), ''%s))", strings.Join(values, ", "), keyOrder(driver, keys))
+	}
+
+	where, args := keyRangeCondition(driver, keys, r)
+	return fmt.Sprintf("SELECT COUNT(*), %s FROM %s%s", checksum, QualifiedTableName(driver, schema, tableName), where), args
+}
+
+// GetRangeChecksum counts and checksums the rows of a key range on the server,
+// so a range that matches is never transferred. Checksums only compare equal
+// between tables on the same kind of database, and are only available where
+// the driver has RangeChecksums.
+func GetRangeChecksum(db *sql.DB, driver, schema, tableName string, columns, keys []string, r KeyRange) (rows int, checksum string, err error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	query, args := rangeChecksumSQL(driver, schema, tableName, columns, keys, r)
+	var sum sql.NullString
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&rows, &sum); err != nil {
+		return 0, "", err
+	}
+	return rows, sum.String, nil
+}
+
+// GetRowsInKeyRange reads the given columns for the rows of a key range
+func GetRowsInKeyRange(db *sql.DB, driver, schema, tableName string, columns, keys []string, r KeyRange) ([][]interface{}, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	where, args := keyRangeCondition(driver, keys, r)
+	query := fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(QuoteIdentifiers(driver, columns), ", "), QualifiedTableName(driver, schema, tableName), where)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		result = append(result, values)
+	}
+	return result, rows.Err()
+}
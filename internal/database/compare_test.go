@@ -0,0 +1,51 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyRangeCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		keys     []string
+		r        KeyRange
+		want     string
+		wantArgs []interface{}
+	}{
+		{"whole table", "postgres", []string{"id"}, KeyRange{}, "", nil},
+		{"integer range", "postgres", []string{"id"}, KeyRange{Integer: true, From: 1, To: 1000},
+			` WHERE "id" >= $1 AND "id" <= $2`, []interface{}{int64(1), int64(1000)}},
+		{"first keyset range", "mysql", []string{"code"}, KeyRange{Through: []interface{}{"m"}},
+			" WHERE NOT ((`code` > ?))", []interface{}{"m"}},
+		{"composite keyset range", "postgres", []string{"region", "id"}, KeyRange{After: []interface{}{"eu", 7}, Through: []interface{}{"us", 3}},
+			` WHERE (("region" > $1) OR ("region" = $2 AND "id" > $3)) AND NOT (("region" > $4) OR ("region" = $5 AND "id" > $6))`,
+			[]interface{}{"eu", "eu", 7, "us", "us", 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := keyRangeCondition(tt.driver, tt.keys, tt.r)
+			if got != tt.want || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("keyRangeCondition() = %q, %v, want %q, %v", got, args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRangeChecksumSQL(t *testing.T) {
+	r := KeyRange{Integer: true, From: 1, To: 1000}
+
+	got, _ := rangeChecksumSQL("postgres", "public", "users", []string{"id", "email"}, []string{"id"}, r)
+	want := `SELECT COUNT(*), md5(string_agg(md5(concat_ws(chr(31), COALESCE('v' || "id"::text, 'n'), COALESCE('v' || "email"::text, 'n'))), '' ORDER BY "id")) FROM "public"."users" WHERE "id" >= $1 AND "id" <= $2`
+	if got != want {
+		t.Errorf("rangeChecksumSQL(postgres) =\n%s\nwant\n%s", got, want)
+	}
+
+	got, _ = rangeChecksumSQL("mysql", "shop", "users", []string{"id", "email"}, []string{"id"}, r)
+	want = "SELECT COUNT(*), SUM(CAST(CONV(SUBSTRING(MD5(CONCAT_WS(CHAR(31), COALESCE(CONCAT('v', `id`), 'n'), COALESCE(CONCAT('v', `email`), 'n'))), 1, 15), 16, 10) AS UNSIGNED)) FROM `shop`.`users` WHERE `id` >= ? AND `id` <= ?"
+	if got != want {
+		t.Errorf("rangeChecksumSQL(mysql) =\n%s\nwant\n%s", got, want)
+	}
+}
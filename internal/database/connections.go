@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// getConnectionsByDatabase counts open server connections grouped by database.
+// Errors are not fatal for the overview, so an empty result is returned instead.
+func getConnectionsByDatabase(db *sql.DB, driver string) []models.DatabaseConnections {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var query string
+	switch driver {
+	case "postgres":
+		query = `
+			SELECT COALESCE(datname, '(background)'), COUNT(*)
+			FROM pg_stat_activity
+			GROUP BY datname
+			ORDER BY COUNT(*) DESC`
+	case "redshift":
+		query = `
+			SELECT COALESCE(TRIM(db_name), '(none)'), COUNT(*)
+			FROM stv_sessions
+			GROUP BY db_name
+			ORDER BY COUNT(*) DESC`
+	case "mysql", "mariadb":
+		query = `
+			SELECT COALESCE(DB, '(none)'), COUNT(*)
+			FROM INFORMATION_SCHEMA.PROCESSLIST
+			GROUP BY DB
+			ORDER BY COUNT(*) DESC`
+	default:
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var counts []models.DatabaseConnections
+	for rows.Next() {
+		var c models.DatabaseConnections
+		if err := rows.Scan(&c.Database, &c.Count); err != nil {
+			return nil
+		}
+		counts = append(counts, c)
+	}
+	return counts
+}
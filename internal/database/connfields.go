@@ -0,0 +1,170 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/go-sql-driver/mysql"
+)
+
+// DefaultPort returns the port a driver's server listens on by default
+func DefaultPort(driver string) string {
+	switch driver {
+	case "cockroach":
+		return "26257"
+	case "redshift":
+		return "5439"
+	case "mysql", "mariadb":
+		return "3306"
+	case "clickhouse":
+		return "9004" // The MySQL interface
+	case "snowflake":
+		return "443" // Reached through the account's HTTPS endpoint
+	default:
+		return "5432"
+	}
+}
+
+// BuildConnectionString assembles a connection string from the form's fields: a
+// postgres:// URL for PostgreSQL-style drivers and a user:password@tcp(host:port)/db
+// DSN for MySQL-style drivers. Host and port default to localhost and the
+// driver's port, and Options is a query string such as sslmode=disable.
+// Snowflake is reached by account identifier, which is given as the host.
+func BuildConnectionString(driver string, fields models.ConnectionFields) (string, error) {
+	if driver == "snowflake" {
+		return buildSnowflakeDSN(fields)
+	}
+	host := strings.TrimSpace(fields.Host)
+	if host == "" {
+		host = "localhost"
+	}
+	port := strings.TrimSpace(fields.Port)
+	if port == "" {
+		port = DefaultPort(driver)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("port must be a number from 1 to 65535, not %q", port)
+	}
+	options := strings.TrimPrefix(strings.TrimSpace(fields.Options), "?")
+	if _, err := url.ParseQuery(options); err != nil {
+		return "", fmt.Errorf("options must look like key=value&key=value: %w", err)
+	}
+	user := strings.TrimSpace(fields.User)
+	database := strings.TrimSpace(fields.Database)
+
+	switch driver {
+	case "mysql", "mariadb", "clickhouse":
+		cfg := mysql.NewConfig()
+		cfg.User = user
+		cfg.Passwd = fields.Password
+		cfg.Net = "tcp"
+		cfg.Addr = net.JoinHostPort(host, port)
+		cfg.DBName = database
+		dsn := cfg.FormatDSN()
+		if options != "" {
+			dsn += "?" + options
+		}
+		return dsn, nil
+
+	case "sqlite3":
+		return "", fmt.Errorf("SQLite connects to a file path, not a server")
+
+	case "trino":
+		return "", fmt.Errorf("Trino connects with an http:// or https:// URL")
+
+	case "bigquery":
+		return "", fmt.Errorf("BigQuery connects with a bigquery://project/dataset URL")
+
+	default:
+		u := url.URL{
+			Scheme:   "postgres",
+			Host:     net.JoinHostPort(host, port),
+			Path:     "/" + database,
+			RawQuery: options,
+		}
+		if driver == "cockroach" {
+			u.Scheme = "postgresql"
+		}
+		if user != "" {
+			u.User = url.User(user)
+			if fields.Password != "" {
+				u.User = url.UserPassword(user, fields.Password)
+			}
+		}
+		return u.String(), nil
+	}
+}
+
+// ParseConnectionString splits a connection string into the form's fields, so a
+// string typed in raw mode can be edited as fields. PostgreSQL-style strings must
+// be URLs; key=value strings and Unix sockets cannot be split.
+func ParseConnectionString(driver, connectionStr string) (models.ConnectionFields, error) {
+	connectionStr = strings.TrimSpace(connectionStr)
+	if connectionStr == "" {
+		return models.ConnectionFields{}, nil
+	}
+
+	switch driver {
+	case "mysql", "mariadb", "clickhouse":
+		cfg, err := mysql.ParseDSN(connectionStr)
+		if err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the connection string: %w", err)
+		}
+		if cfg.Net != "tcp" {
+			return models.ConnectionFields{}, fmt.Errorf("only tcp() addresses can be edited as fields")
+		}
+		host, port, err := net.SplitHostPort(cfg.Addr)
+		if err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the address %q: %w", cfg.Addr, err)
+		}
+		// The driver turns known parameters into config fields, so options are
+		// taken from the text after the database name as typed
+		_, options, _ := strings.Cut(connectionStr[strings.LastIndex(connectionStr, "/"):], "?")
+		return models.ConnectionFields{
+			Host:     host,
+			Port:     port,
+			User:     cfg.User,
+			Password: cfg.Passwd,
+			Database: cfg.DBName,
+			Options:  options,
+		}, nil
+
+	case "sqlite3":
+		return models.ConnectionFields{}, fmt.Errorf("SQLite connects to a file path, not a server")
+
+	case "trino":
+		return models.ConnectionFields{}, fmt.Errorf("Trino connects with an http:// or https:// URL")
+
+	case "bigquery":
+		return models.ConnectionFields{}, fmt.Errorf("BigQuery connects with a bigquery://project/dataset URL")
+
+	case "snowflake":
+		fields, err := parseSnowflakeDSN(connectionStr)
+		if err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the connection string: %w", err)
+		}
+		return fields, nil
+
+	default:
+		u, err := url.Parse(connectionStr)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			return models.ConnectionFields{}, fmt.Errorf("only postgres:// URLs can be edited as fields")
+		}
+		if strings.HasPrefix(u.Query().Get("host"), "/") || strings.HasPrefix(u.Hostname(), "/") {
+			return models.ConnectionFields{}, fmt.Errorf("Unix socket connections cannot be edited as fields")
+		}
+		password, _ := u.User.Password()
+		return models.ConnectionFields{
+			Host:     u.Hostname(),
+			Port:     u.Port(),
+			User:     u.User.Username(),
+			Password: password,
+			Database: strings.TrimPrefix(u.Path, "/"),
+			Options:  u.RawQuery,
+		}, nil
+	}
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CopyTableRows streams every row of a source table into a target table with the
+// same columns, inserting in batches of batchSize. Each batch is its own
+// transaction: a failing batch is rolled back, reported through onBatch, and the
+// copy continues. Errors that stop the copy entirely are returned.
+func CopyTableRows(src *sql.DB, srcDriver, srcSchema, srcTable string, dst *sql.DB, dstDriver, dstSchema, dstTable string, batchSize int, onBatch func(rows int, err error)) error {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	rows, err := src.Query("SELECT * FROM " + QualifiedTableName(srcDriver, srcSchema, srcTable))
+	if err != nil {
+		return fmt.Errorf("failed to read source table: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := CheckTargetColumns(dst, dstDriver, dstSchema, dstTable, columns); err != nil {
+		return err
+	}
+	insertSQL := BuildInsertSQL(dstDriver, dstSchema, dstTable, columns)
+
+	batch := make([][]interface{}, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		_, err := InsertRows(dst, insertSQL, batch)
+		onBatch(len(batch), err)
+		batch = batch[:0]
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return fmt.Errorf("failed to read source row: %w", err)
+		}
+		batch = append(batch, values)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read source table: %w", err)
+	}
+	flush()
+	return nil
+}
+
+// CheckTargetColumns verifies the target table has every source column
+func CheckTargetColumns(dst *sql.DB, driver, schema, table string, columns []string) error {
+	targetColumns, err := GetTableColumnNames(dst, driver, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to read target table: %w", err)
+	}
+
+	existing := make(map[string]bool, len(targetColumns))
+	for _, col := range targetColumns {
+		existing[col] = true
+	}
+	var missing []string
+	for _, col := range columns {
+		if !existing[col] {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("target table %s is missing columns: %s", table, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package database
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// PostgreSQL-family connections need nothing here: lib/pq already looks up a
+// missing password in $PGPASSFILE or ~/.pgpass, matching host, port, database,
+// and user like psql. The MySQL driver has no such lookup, so Open reads
+// ~/.my.cnf for MySQL and MariaDB connections itself.
+
+// MySQLOptionFile returns the path of the user's MySQL option file
+func MySQLOptionFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".my.cnf"), nil
+}
+
+// mysqlOptionGroups are the option file groups the native client of the driver
+// reads, in order; a later group overrides an earlier one
+func mysqlOptionGroups(driver string) []string {
+	if driver == "mariadb" {
+		return []string{"client", "client-server", "client-mariadb", "mysql", "mariadb-client"}
+	}
+	return []string{"client", "mysql"}
+}
+
+// ReadMySQLOptions reads the options of the given groups from a MySQL option
+// file. Names are lowercased with "_" written as "-", values lose their quotes,
+// and !include directives are skipped. A missing file has no options.
+func ReadMySQLOptions(path string, groups []string) (map[string]string, error) {
+	options := map[string]string{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return options, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rank := map[string]int{}
+	for i, group := range groups {
+		rank[group] = i + 1
+	}
+	ranks := map[string]int{}
+	current := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", line[0] == '#', line[0] == ';', line[0] == '!':
+			continue
+		case line[0] == '[' && strings.HasSuffix(line, "]"):
+			current = rank[strings.ToLower(strings.TrimSpace(line[1:len(line)-1]))]
+			continue
+		case current == 0:
+			continue
+		}
+
+		name, value, _ := strings.Cut(line, "=")
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+		if current >= ranks[name] {
+			options[name] = optionValue(value)
+			ranks[name] = current
+		}
+	}
+	return options, scanner.Err()
+}
+
+// optionValue unquotes an option file value or strips a trailing comment
+func optionValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// ApplyMySQLOptionFile fills in the user and password of a MySQL or MariaDB
+// connection string that has no password from ~/.my.cnf, the way the mysql
+// client does. Other connection strings are returned unchanged.
+func ApplyMySQLOptionFile(driver, dsn string) (string, error) {
+	if driver != "mysql" && driver != "mariadb" {
+		return dsn, nil
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil || cfg.Passwd != "" {
+		return dsn, err
+	}
+	path, err := MySQLOptionFile()
+	if err != nil {
+		return dsn, nil
+	}
+	options, err := ReadMySQLOptions(path, mysqlOptionGroups(driver))
+	if err != nil {
+		return "", err
+	}
+	return applyMySQLOptions(cfg, dsn, options), nil
+}
+
+// applyMySQLOptions sets the user and password options on a parsed
+// connection string that has no password
+func applyMySQLOptions(cfg *mysql.Config, dsn string, options map[string]string) string {
+	password, ok := options["password"]
+	if !ok {
+		return dsn
+	}
+	if cfg.User == "" {
+		cfg.User = options["user"]
+	}
+	cfg.Passwd = password
+	return cfg.FormatDSN()
+}
+
+// HasPassword reports whether a connection string carries a password
+func HasPassword(driver, dsn string) bool {
+	switch driver {
+	case "mysql", "mariadb", "clickhouse":
+		cfg, err := mysql.ParseDSN(dsn)
+		return err == nil && cfg.Passwd != ""
+	case "postgres", "cockroach", "redshift":
+		if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+			password, ok := u.User.Password()
+			return (ok && password != "") || u.Query().Get("password") != ""
+		}
+		for _, field := range strings.Fields(dsn) {
+			if strings.HasPrefix(field, "password=") && len(field) > len("password=") {
+				return true
+			}
+		}
+	case "snowflake":
+		fields, err := parseSnowflakeDSN(dsn)
+		return err == nil && fields.Password != ""
+	}
+	return false
+}
+
+// keyValuePassword matches the password of a key=value connection string,
+// quoted or not, with the space after it
+var keyValuePassword = regexp.MustCompile(`(?i)(^|\s)password\s*=\s*('(?:[^'\\]|\\.)*'|\S*)(\s*)`)
+
+// secretParams are the query parameters of a connection string that hold a
+// secret, in lower case
+var secretParams = map[string]bool{
+	"password": true, SQLCipherKeyParam: true, "_auth_pass": true,
+	"token": true, "passcode": true, "privatekey": true, // Snowflake's other authenticators
+}
+
+// RedactSecrets hides the secrets of a connection string, keeping everything
+// else: the password of a URL's user info or a MySQL or Snowflake DSN, the
+// password of a key=value string, and the password, SQLCipher key, and token
+// query parameters. Each is replaced by mask, or removed when mask is empty,
+// for strings that leave the machine.
+func RedactSecrets(driver, dsn, mask string) string {
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+			if _, ok := u.User.Password(); ok && mask == "" {
+				u.User = url.User(u.User.Username())
+			} else if ok {
+				u.User = url.UserPassword(u.User.Username(), mask)
+			}
+			u.RawQuery = redactParams(u.RawQuery, mask)
+			return u.String()
+		}
+	}
+
+	switch driver {
+	case "mysql", "mariadb", "clickhouse":
+		if cfg, err := mysql.ParseDSN(dsn); err == nil {
+			if cfg.Passwd != "" {
+				cfg.Passwd = mask
+				dsn = cfg.FormatDSN()
+			}
+		}
+	case "postgres", "cockroach", "redshift":
+		if !strings.Contains(dsn, "://") {
+			return strings.TrimSpace(keyValuePassword.ReplaceAllStringFunc(dsn, func(match string) string {
+				parts := keyValuePassword.FindStringSubmatch(match)
+				if mask == "" {
+					return parts[1]
+				}
+				return parts[1] + "password=" + mask + parts[3]
+			}))
+		}
+	case "snowflake":
+		if userInfo, rest, ok := snowflakeUserInfo(dsn); ok {
+			if user, _, hasPassword := strings.Cut(userInfo, ":"); hasPassword {
+				if mask != "" {
+					user += ":" + url.QueryEscape(mask)
+				}
+				dsn = user + "@" + rest
+			}
+		}
+	}
+
+	path, query, ok := strings.Cut(dsn, "?")
+	if !ok {
+		return dsn
+	}
+	if query = redactParams(query, mask); query == "" {
+		return path
+	}
+	return path + "?" + query
+}
+
+// redactParams hides the values of the secret parameters of a raw query
+// string, keeping the order of the others
+func redactParams(query, mask string) string {
+	if query == "" {
+		return query
+	}
+	parts := strings.Split(query, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		name, _, _ := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		switch {
+		case !secretParams[strings.ToLower(name)]:
+			kept = append(kept, part)
+		case mask != "":
+			kept = append(kept, name+"="+url.QueryEscape(mask))
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+// KeywordFields splits a key=value connection string such as "host=db user=app"
+// at spaces outside quoted values, the way lib/pq reads it
+func KeywordFields(connectionStr string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted, escaped := false, false
+	for _, r := range connectionStr {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '\'':
+			quoted = !quoted
+		case (r == ' ' || r == '\t' || r == '\n') && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteRune(r)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// keywordValue unquotes the value of a key=value field
+func keywordValue(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(value[1 : len(value)-1])
+	}
+	return value
+}
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// explainTimeout bounds the EXPLAIN run before a query; planning alone is quick
+const explainTimeout = 10 * time.Second
+
+// ExplainQuery returns the plan the database would use for a query without
+// running it: the plan text for PostgreSQL-style databases, one row per table
+// for MySQL
+func ExplainQuery(db *sql.DB, query string) (ResultSet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "EXPLAIN "+strings.TrimRight(strings.TrimSpace(query), "; \t\n"))
+	if err != nil {
+		return ResultSet{}, err
+	}
+	defer rows.Close()
+	return scanPreviewRows(rows)
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetGroupSummary counts the rows of each value of a column, most common first,
+// up to limit values, and returns them with the table's total row count. With
+// an aggregate column, each value also gets aggregate (sum, avg, min, or max)
+// of that column over its rows.
+func GetGroupSummary(db *sql.DB, driver, schema, table, column, aggregateColumn, aggregate string, limit int) ([]models.GroupCount, int64, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	tableName, err := tableRef(driver, schema, table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selected := QuoteIdentifier(driver, column) + ", COUNT(*)"
+	if aggregateColumn != "" {
+		switch aggregate {
+		case "sum", "avg", "min", "max":
+		default:
+			return nil, 0, fmt.Errorf("unknown aggregate: %s", aggregate)
+		}
+		selected += fmt.Sprintf(", %s(%s)", strings.ToUpper(aggregate), QuoteIdentifier(driver, aggregateColumn))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d", selected, tableName, limit)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	result, err := scanPreviewRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groups := make([]models.GroupCount, len(result.Rows))
+	for i, row := range result.Rows {
+		count, err := strconv.ParseInt(row[1], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unexpected row count %q: %w", row[1], err)
+		}
+		groups[i] = models.GroupCount{Value: row[0], IsNull: result.Nulls[i][0], Count: count}
+		if aggregateColumn != "" {
+			groups[i].Aggregate = row[2]
+		}
+	}
+
+	var total int64
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+tableName).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetSystemVersionColumns returns the row start and end columns of a MariaDB
+// system-versioned table, and whether they are invisible to SELECT *. start is
+// empty when the table is not system-versioned or the driver has no such tables.
+func GetSystemVersionColumns(db *sql.DB, driver, schema, table string) (start, end string, invisible bool, err error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	if driver != "mysql" && driver != "mariadb" {
+		return "", "", false, nil
+	}
+
+	// MySQL itself has no system versioning, so this finds nothing there
+	rows, err := db.QueryContext(ctx, `
+		SELECT COLUMN_NAME, UPPER(EXTRA)
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = `+mysqlSchemaFilter+`
+			AND TABLE_NAME = ?
+			AND (UPPER(EXTRA) LIKE '%ROW START%' OR UPPER(EXTRA) LIKE '%ROW END%')`, schema, table)
+	if err != nil {
+		return "", "", false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, extra string
+		if err := rows.Scan(&name, &extra); err != nil {
+			return "", "", false, err
+		}
+		if strings.Contains(extra, "ROW START") {
+			start = name
+		} else {
+			end = name
+		}
+		invisible = invisible || strings.Contains(extra, "INVISIBLE")
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", false, err
+	}
+	if start == "" || end == "" {
+		return "", "", false, nil
+	}
+	return start, end, invisible, nil
+}
+
+// GetSystemVersions reads up to limit versions of the row with the given key from
+// a system-versioned table, oldest first. The current version is the last one.
+func GetSystemVersions(db *sql.DB, driver, schema, table, start, end string, invisible bool, keyColumn, keyValue string, limit int) (ResultSet, error) {
+	selected := "*"
+	if invisible {
+		selected = "*, " + QuoteIdentifier(driver, start) + ", " + QuoteIdentifier(driver, end)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s FOR SYSTEM_TIME ALL WHERE %s = ? ORDER BY %s DESC LIMIT %d",
+		selected, QualifiedTableName(driver, schema, table), QuoteIdentifier(driver, keyColumn), QuoteIdentifier(driver, end), limit)
+	return queryVersions(db, query, keyValue)
+}
+
+// GetHistoryTableVersions reads up to limit rows with the given key from a
+// companion history table, oldest first by orderColumn. Without an order column
+// the rows come in the order the database returns them.
+func GetHistoryTableVersions(db *sql.DB, driver, schema, historyTable, keyColumn, keyValue, orderColumn string, limit int) (ResultSet, error) {
+	table, err := tableRef(driver, schema, historyTable)
+	if err != nil {
+		return ResultSet{}, err
+	}
+	placeholder := "?"
+	if models.DriverCapabilities(driver).NumberedPlaceholders {
+		placeholder = "$1"
+	}
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = %s", table, QuoteIdentifier(driver, keyColumn), placeholder)
+	if orderColumn != "" {
+		query += " ORDER BY " + QuoteIdentifier(driver, orderColumn) + " DESC"
+	}
+	query += fmt.Sprintf(" LIMIT %d", limit)
+	return queryVersions(db, query, keyValue)
+}
+
+// queryVersions runs a newest-first version query and returns the versions oldest first
+func queryVersions(db *sql.DB, query, keyValue string) (ResultSet, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, keyValue)
+	if err != nil {
+		return ResultSet{}, err
+	}
+	defer rows.Close()
+
+	rs, err := scanPreviewRows(rows)
+	if err != nil {
+		return ResultSet{}, err
+	}
+	for i, j := 0, len(rs.Rows)-1; i < j; i, j = i+1, j-1 {
+		rs.Rows[i], rs.Rows[j] = rs.Rows[j], rs.Rows[i]
+		rs.Nulls[i], rs.Nulls[j] = rs.Nulls[j], rs.Nulls[i]
+	}
+	return rs, nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Every schema, table, and column name spliced into generated SQL goes through
+// this file. Names are quoted for the driver with embedded quote characters
+// doubled, or escaped with a backslash for BigQuery, so a name can never end its
+// quoted identifier early.
+
+// usesBackticks reports whether the driver quotes identifiers with backticks
+func usesBackticks(driver string) bool {
+	return driver == "mysql" || driver == "mariadb" || driver == "clickhouse"
+}
+
+// QuoteIdentifier quotes a schema, table, or column name for the driver
+func QuoteIdentifier(driver, name string) string {
+	if driver == "bigquery" {
+		return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+	}
+	if usesBackticks(driver) {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteIdentifiers quotes each name for the driver
+func QuoteIdentifiers(driver string, names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdentifier(driver, name)
+	}
+	return quoted
+}
+
+// mysqlTableName quotes a MySQL or ClickHouse table, qualified by database when one is selected
+func mysqlTableName(schema, table string) string {
+	if schema == "" {
+		return QuoteIdentifier("mysql", table)
+	}
+	return QuoteIdentifier("mysql", schema) + "." + QuoteIdentifier("mysql", table)
+}
+
+// QualifiedTableName quotes a table for the driver, qualified by schema where it applies
+func QualifiedTableName(driver, schema, table string) string {
+	switch driver {
+	case "postgres", "cockroach", "redshift":
+		if schema == "" {
+			schema = "public"
+		}
+		return QuoteIdentifier(driver, schema) + "." + QuoteIdentifier(driver, table)
+	case "mysql", "mariadb", "clickhouse":
+		return mysqlTableName(schema, table)
+	case "sqlite3":
+		// Tables of attached databases are qualified by the name they were attached as
+		return sqliteSchemaPrefix(schema) + QuoteIdentifier(driver, table)
+	case "trino":
+		return trinoTableName(schema, table)
+	case "snowflake":
+		return snowflakeTableName(schema, table)
+	case "bigquery":
+		return bigqueryTableName(schema, table)
+	default:
+		return QuoteIdentifier(driver, table)
+	}
+}
+
+// tableRef quotes a table for one of the supported drivers, or fails for any other driver
+func tableRef(driver, schema, table string) (string, error) {
+	switch driver {
+	case "postgres", "cockroach", "redshift", "mysql", "mariadb", "clickhouse", "sqlite3", "trino", "snowflake", "bigquery":
+		return QualifiedTableName(driver, schema, table), nil
+	}
+	return "", fmt.Errorf("unsupported driver: %s", driver)
+}
+
+// ValidateIdentifier checks a name taken from user input or row data against the
+// names fetched from the database's metadata, so generated SQL only ever
+// references objects that exist. Empty names and names with NUL bytes, which no
+// driver can quote, are rejected as well.
+func ValidateIdentifier(kind, name string, known []string) error {
+	if name == "" || strings.ContainsRune(name, 0) {
+		return fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	for _, k := range known {
+		if k == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %s %q", kind, name)
+}
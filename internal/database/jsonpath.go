@@ -0,0 +1,208 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ParseJSONColumn reads a JSON path column written either the PostgreSQL way,
+// payload->'items'->0->>'id', or with dots, payload.items.0.id, optionally
+// followed by "as name". The column must be one of columns (ignoring case).
+// Without a name the column is shown as the dotted path.
+func ParseJSONColumn(spec string, columns []string) (models.JSONColumn, error) {
+	spec = strings.TrimSpace(spec)
+	var name string
+	if i := strings.LastIndex(strings.ToLower(spec), " as "); i >= 0 {
+		spec, name = strings.TrimSpace(spec[:i]), unquoteIdentifier(strings.TrimSpace(spec[i+4:]))
+	}
+
+	var column string
+	var path []models.JSONPathStep
+	var err error
+	if i := strings.Index(spec, "->"); i >= 0 {
+		column = spec[:i]
+		path, err = parseArrowPath(spec[i:])
+	} else {
+		column, path, err = parseDottedPath(spec)
+	}
+	if err != nil {
+		return models.JSONColumn{}, err
+	}
+	column = unquoteIdentifier(strings.TrimSpace(column))
+	if len(path) == 0 {
+		return models.JSONColumn{}, fmt.Errorf("no JSON path after %q: use column->>'key' or column.key", column)
+	}
+
+	found := false
+	for _, col := range columns {
+		if strings.EqualFold(col, column) {
+			column, found = col, true
+			break
+		}
+	}
+	if !found {
+		return models.JSONColumn{}, fmt.Errorf("unknown column %q", column)
+	}
+
+	if name == "" {
+		name = column + "." + FormatJSONPath(path)
+	}
+	return models.JSONColumn{Name: name, Column: column, Path: path}, nil
+}
+
+// parseArrowPath reads the ->'key' and ->0 steps of a PostgreSQL-style path;
+// ->> and -> are treated alike, since every value is shown as text
+func parseArrowPath(s string) ([]models.JSONPathStep, error) {
+	var path []models.JSONPathStep
+	for s != "" {
+		rest, ok := strings.CutPrefix(s, "->>")
+		if !ok {
+			if rest, ok = strings.CutPrefix(s, "->"); !ok {
+				return nil, fmt.Errorf("invalid JSON path near %q", s)
+			}
+		}
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, "'") {
+			// A quoted key ends at its closing quote, where '' is an escaped quote
+			end := 1
+			for end < len(rest) {
+				if strings.HasPrefix(rest[end:], "''") {
+					end += 2
+					continue
+				}
+				if rest[end] == '\'' {
+					break
+				}
+				end++
+			}
+			if end >= len(rest) {
+				return nil, fmt.Errorf("unterminated key in JSON path: %s", rest)
+			}
+			path = append(path, models.JSONPathStep{Key: strings.ReplaceAll(rest[1:end], "''", "'")})
+			s = strings.TrimSpace(rest[end+1:])
+			continue
+		}
+		step := rest
+		next := strings.Index(rest, "->")
+		if next >= 0 {
+			step = rest[:next]
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(step))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid JSON path step %q: quote keys as 'key' and write array indexes as numbers", strings.TrimSpace(step))
+		}
+		path = append(path, models.JSONPathStep{Index: index, Array: true})
+		s = ""
+		if next >= 0 {
+			s = rest[next:]
+		}
+	}
+	return path, nil
+}
+
+// parseDottedPath reads column.key.0.key, where steps made only of digits are
+// array indexes
+func parseDottedPath(s string) (string, []models.JSONPathStep, error) {
+	parts := strings.Split(s, ".")
+	var path []models.JSONPathStep
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return "", nil, fmt.Errorf("empty step in JSON path %q", s)
+		}
+		if index, err := strconv.Atoi(part); err == nil && index >= 0 {
+			path = append(path, models.JSONPathStep{Index: index, Array: true})
+			continue
+		}
+		path = append(path, models.JSONPathStep{Key: part})
+	}
+	return parts[0], path, nil
+}
+
+// unquoteIdentifier strips the double quotes or backticks around a name
+func unquoteIdentifier(name string) string {
+	if len(name) >= 2 && (name[0] == '"' && name[len(name)-1] == '"' || name[0] == '`' && name[len(name)-1] == '`') {
+		return name[1 : len(name)-1]
+	}
+	return name
+}
+
+// FormatJSONPath writes a path with dots, e.g. items.0.id
+func FormatJSONPath(path []models.JSONPathStep) string {
+	steps := make([]string, len(path))
+	for i, step := range path {
+		steps[i] = step.Key
+		if step.Array {
+			steps[i] = strconv.Itoa(step.Index)
+		}
+	}
+	return strings.Join(steps, ".")
+}
+
+// JSONPathExpr builds the expression reading a JSON path column as text. Keys
+// and indexes are written into string literals with quotes escaped.
+func JSONPathExpr(driver string, col models.JSONColumn) (string, error) {
+	literal := func(s string) string {
+		escaped := strings.ReplaceAll(s, "'", "''")
+		if usesBackticks(driver) {
+			escaped = strings.ReplaceAll(escaped, `\`, `\\`)
+		}
+		return "'" + escaped + "'"
+	}
+	quoted := QuoteIdentifier(driver, col.Column)
+
+	switch driver {
+	case "postgres", "cockroach":
+		// A text array path; the cast also reads JSON stored in text columns
+		steps := make([]string, len(col.Path))
+		for i, step := range col.Path {
+			if step.Array {
+				steps[i] = strconv.Itoa(step.Index)
+				continue
+			}
+			steps[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(step.Key) + `"`
+		}
+		return fmt.Sprintf("(%s::JSONB #>> %s)", quoted, literal("{"+strings.Join(steps, ",")+"}")), nil
+	case "redshift":
+		args := []string{quoted}
+		for _, step := range col.Path {
+			if step.Array {
+				return "", fmt.Errorf("array indexes in JSON paths are not supported for redshift")
+			}
+			args = append(args, literal(step.Key))
+		}
+		return fmt.Sprintf("JSON_EXTRACT_PATH_TEXT(%s)", strings.Join(args, ", ")), nil
+	case "mysql", "mariadb", "sqlite3":
+		var path strings.Builder
+		path.WriteString("$")
+		for _, step := range col.Path {
+			if step.Array {
+				fmt.Fprintf(&path, "[%d]", step.Index)
+				continue
+			}
+			path.WriteString(`."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(step.Key) + `"`)
+		}
+		if driver == "sqlite3" {
+			return fmt.Sprintf("json_extract(%s, %s)", quoted, literal(path.String())), nil
+		}
+		return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, %s))", quoted, literal(path.String())), nil
+	}
+	return "", fmt.Errorf("JSON path columns are not supported for %s", driver)
+}
+
+// previewSelectList is the preview's SELECT list: every table column, then each
+// JSON path column under its name
+func previewSelectList(driver string, jsonColumns []models.JSONColumn) (string, error) {
+	terms := []string{"*"}
+	for _, col := range jsonColumns {
+		expr, err := JSONPathExpr(driver, col)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, expr+" AS "+QuoteIdentifier(driver, col.Name))
+	}
+	return strings.Join(terms, ", "), nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// RunMaintenance executes a maintenance statement and summarizes any status rows it returns
+func RunMaintenance(db *sql.DB, query string) (string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	// MySQL returns (Table, Op, Msg_type, Msg_text) rows; other drivers return nothing
+	var messages []string
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", err
+		}
+		if len(values) >= 4 {
+			if values[2].String == "error" {
+				return "", fmt.Errorf("%s", values[3].String)
+			}
+			messages = append(messages, values[3].String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if len(messages) == 0 {
+		return "OK", nil
+	}
+	return strings.Join(messages, "; "), nil
+}
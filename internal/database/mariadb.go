@@ -0,0 +1,18 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MariaDB speaks the MySQL protocol, so the MySQL driver is registered again
+// under the "mariadb" name. Queries share the MySQL dialect except where
+// MariaDB's catalog differs, such as sequences and system-versioned tables.
+func init() {
+	sql.Register("mariadb", &mysql.MySQLDriver{})
+}
+
+// mariadbTableTypes are the INFORMATION_SCHEMA.TABLES types listed for MariaDB.
+// MariaDB reports system-versioned tables and sequences as their own types.
+const mariadbTableTypes = "'BASE TABLE', 'VIEW', 'SYSTEM VERSIONED', 'SEQUENCE'"
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/lib/pq"
+)
+
+// ListenForNotifications LISTENs on PostgreSQL channels over a connection of its
+// own until stop is closed, passing each notification and each change of the
+// connection to send. A lost connection is re-established, LISTENing again.
+func ListenForNotifications(connectionStr string, channels []string, stop <-chan struct{}, send func(models.NotificationMsg)) {
+	listener := pq.NewListener(connectionStr, time.Second, 30*time.Second, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnected, pq.ListenerEventReconnected:
+			send(models.NotificationMsg{Connected: true})
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			send(models.NotificationMsg{Err: err})
+		}
+	})
+	defer listener.Close()
+
+	// LISTEN blocks until the server answers, so it must not hold up stopping
+	go func() {
+		for _, channel := range channels {
+			if err := listener.Listen(channel); err != nil && err != pq.ErrChannelAlreadyOpen {
+				send(models.NotificationMsg{Err: err})
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case n := <-listener.Notify:
+			// A nil notification follows a reconnect, when some may have been missed
+			if n != nil {
+				send(models.NotificationMsg{Notification: &models.Notification{
+					Channel:    n.Channel,
+					Payload:    n.Extra,
+					PID:        n.BePid,
+					ReceivedAt: time.Now(),
+				}})
+			}
+		}
+	}
+}
@@ -1,36 +1,98 @@
 package database
 
 import (
-	"context"
-	"database/sql"
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/dancaldera/mirador/internal/models"
 )
 
-// ValidateConnectionString validates the format of connection strings
+// mysqlDSNAddress finds the network and address of a MySQL-style DSN
+var mysqlDSNAddress = regexp.MustCompile(`(?:^|@)(tcp|unix)\(([^)]*)\)`)
+
+// ValidateConnectionString validates the format of connection strings. Unix
+// sockets are accepted in the forms the drivers take them: a PostgreSQL URL or
+// key=value string whose host is the socket's directory, and a MySQL-style
+// unix(/path/to/socket) address.
 func ValidateConnectionString(driver, connectionStr string) error {
 	if connectionStr == "" {
 		return fmt.Errorf("connection string cannot be empty")
 	}
+	if err := validateCloudSQL(driver, connectionStr); err != nil {
+		return err
+	}
 
 	switch driver {
-	case "postgres":
-		if !strings.HasPrefix(connectionStr, "postgres://") && !strings.HasPrefix(connectionStr, "postgresql://") {
-			return fmt.Errorf("PostgreSQL connection string should start with 'postgres://' or 'postgresql://'")
-		}
-	case "mysql":
-		if strings.HasPrefix(connectionStr, "mysql://") {
-			return fmt.Errorf("MySQL connection string should not include 'mysql://' prefix. Use format: user:password@tcp(host:port)/dbname")
-		}
+	case "postgres", "cockroach", "redshift":
+		return validatePostgresConnection(connectionStr)
+	case "mysql", "mariadb", "clickhouse":
+		return validateMySQLConnection(connectionStr)
 	case "sqlite3":
 		return ValidateSQLiteConnection(connectionStr)
+	case "trino":
+		_, err := parseTrinoDSN(connectionStr)
+		return err
+	case "snowflake":
+		return validateSnowflakeConnection(connectionStr)
+	case "bigquery":
+		_, err := parseBigQueryDSN(connectionStr)
+		return err
 	default:
 		return fmt.Errorf("unsupported database driver: %s", driver)
 	}
+}
 
+// validatePostgresConnection checks a postgres:// URL or a key=value string
+func validatePostgresConnection(connectionStr string) error {
+	var host string
+	switch {
+	case strings.HasPrefix(connectionStr, "postgres://") || strings.HasPrefix(connectionStr, "postgresql://"):
+		u, err := url.Parse(connectionStr)
+		if err != nil {
+			return fmt.Errorf("cannot read the PostgreSQL URL: %w", err)
+		}
+		// A host parameter overrides the URL's host, and is how a socket is given
+		host = u.Query().Get("host")
+		if host == "" {
+			host = u.Hostname()
+		}
+	case !strings.Contains(connectionStr, "://") && strings.Contains(connectionStr, "="):
+		for _, field := range strings.Fields(connectionStr) {
+			if value, ok := strings.CutPrefix(field, "host="); ok {
+				host = strings.Trim(value, "'")
+			}
+		}
+	default:
+		return fmt.Errorf("PostgreSQL connection string should start with 'postgres://' or 'postgresql://', or be key=value settings such as 'host=/var/run/postgresql dbname=app'")
+	}
+
+	// The driver adds the socket's file name to the directory itself
+	if strings.HasPrefix(host, "/") && strings.HasPrefix(path.Base(host), ".s.PGSQL.") {
+		return fmt.Errorf("host %q is the socket file: use its directory, %q, and set the port for the file name", host, path.Dir(host))
+	}
+	return nil
+}
+
+// validateMySQLConnection checks a MySQL-style DSN
+func validateMySQLConnection(connectionStr string) error {
+	if strings.HasPrefix(connectionStr, "mysql://") {
+		return fmt.Errorf("MySQL connection string should not include 'mysql://' prefix. Use format: user:password@tcp(host:port)/dbname or user:password@unix(/path/to/mysqld.sock)/dbname")
+	}
+	loc := mysqlDSNAddress.FindStringSubmatchIndex(connectionStr)
+	if loc == nil {
+		return nil
+	}
+	network, address := connectionStr[loc[2]:loc[3]], connectionStr[loc[4]:loc[5]]
+	if network == "tcp" && strings.HasPrefix(address, "/") {
+		return fmt.Errorf("%q is a socket path: use unix(%s) instead of tcp()", address, address)
+	}
+	if !strings.HasPrefix(connectionStr[loc[1]:], "/") {
+		return fmt.Errorf("the %s() address must be followed by '/' and the database name, which may be empty", network)
+	}
 	return nil
 }
 
@@ -51,20 +113,20 @@ func ValidateSQLiteConnection(path string) error {
 	return nil
 }
 
-// TestConnectionWithTimeout tests a database connection with timeout
-func TestConnectionWithTimeout(driver, connectionStr string) models.TestConnectionResult {
-	timeout := 10 * time.Second
+// TestConnectionWithTimeout tests a database connection, giving up after
+// timeout; 0 waits for as long as the driver does
+func TestConnectionWithTimeout(driver, connectionStr string, timeout time.Duration) models.TestConnectionResult {
 	done := make(chan models.TestConnectionResult, 1)
 
 	go func() {
-		db, err := sql.Open(driver, connectionStr)
+		db, err := Open(driver, connectionStr)
 		if err != nil {
 			done <- models.TestConnectionResult{Success: false, Err: err}
 			return
 		}
 		defer db.Close()
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := ConnectContext(timeout)
 		defer cancel()
 
 		err = db.PingContext(ctx)
@@ -76,10 +138,15 @@ func TestConnectionWithTimeout(driver, connectionStr string) models.TestConnecti
 		done <- models.TestConnectionResult{Success: true, Err: nil}
 	}()
 
+	// A driver that ignores the context is given up on all the same
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
 	select {
 	case result := <-done:
 		return result
-	case <-time.After(timeout):
+	case <-expired:
 		return models.TestConnectionResult{
 			Success: false,
 			Err:     fmt.Errorf("connection timeout after %v", timeout),
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/lib/pq"
+)
+
+// slowQueryLimit caps how many statements are fetched for the slow query log
+const slowQueryLimit = 100
+
+// GetSlowQueries returns the most expensive statements recorded by the server,
+// ordered by total or mean execution time. On MySQL and MariaDB they are those
+// of the selected database, or of the connection's when schema is empty;
+// pg_stat_statements is filtered to the connected database.
+func GetSlowQueries(db *sql.DB, driver, schema string, byMean bool) ([]models.SlowQuery, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var queries []string
+	var args []any
+	switch driver {
+	case "postgres":
+		// pg_stat_statements renamed its timing columns in PostgreSQL 13
+		for _, cols := range [][2]string{{"total_exec_time", "mean_exec_time"}, {"total_time", "mean_time"}} {
+			order := cols[0]
+			if byMean {
+				order = cols[1]
+			}
+			queries = append(queries, fmt.Sprintf(`
+				SELECT query, calls, %s, %s, rows
+				FROM pg_stat_statements
+				WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+				ORDER BY %s DESC
+				LIMIT %d`, cols[0], cols[1], order, slowQueryLimit))
+		}
+	case "mysql", "mariadb":
+		order := "SUM_TIMER_WAIT"
+		if byMean {
+			order = "AVG_TIMER_WAIT"
+		}
+		// Timer columns are in picoseconds
+		queries = append(queries, fmt.Sprintf(`
+			SELECT DIGEST_TEXT, COUNT_STAR, SUM_TIMER_WAIT / 1000000000, AVG_TIMER_WAIT / 1000000000, SUM_ROWS_SENT
+			FROM performance_schema.events_statements_summary_by_digest
+			WHERE SCHEMA_NAME = %s AND DIGEST_TEXT IS NOT NULL
+			ORDER BY %s DESC
+			LIMIT %d`, mysqlSchemaFilter, order, slowQueryLimit))
+		args = append(args, schema)
+	default:
+		return nil, fmt.Errorf("slow query statistics are not available for %s", driver)
+	}
+
+	var rows *sql.Rows
+	var err error
+	for _, query := range queries {
+		rows, err = db.QueryContext(ctx, query, args...)
+		// Only a missing column moves on to the older column names, so other
+		// failures, such as permission denied, are reported as they are
+		if err == nil || !isUndefinedColumn(err) {
+			break
+		}
+	}
+	if err != nil {
+		if driver == "postgres" {
+			return nil, fmt.Errorf("pg_stat_statements is not available (CREATE EXTENSION pg_stat_statements): %w", err)
+		}
+		return nil, fmt.Errorf("performance_schema is not available: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.SlowQuery
+	for rows.Next() {
+		var q models.SlowQuery
+		if err := rows.Scan(&q.Query, &q.Calls, &q.TotalMs, &q.MeanMs, &q.Rows); err != nil {
+			return nil, err
+		}
+		result = append(result, q)
+	}
+	return result, rows.Err()
+}
+
+// isUndefinedColumn reports whether a PostgreSQL error is about a column that
+// does not exist
+func isUndefinedColumn(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "42703"
+}
@@ -0,0 +1,65 @@
+package database
+
+import "database/sql"
+
+// Table persistence kinds whose contents do not survive a crash or restart
+const (
+	PersistenceUnlogged  = "unlogged"
+	PersistenceTemporary = "temporary"
+	PersistenceInMemory  = "in-memory"
+)
+
+// GetTablePersistence maps the schema's tables whose data can be lost to their
+// persistence kind: unlogged and temporary tables on PostgreSQL, MEMORY engine
+// tables on MySQL and MariaDB, and Memory engine tables on ClickHouse. Durable
+// tables are left out.
+func GetTablePersistence(db *sql.DB, driver, schema string) (map[string]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var query string
+	switch driver {
+	case "postgres":
+		if schema == "" {
+			schema = "public"
+		}
+		query = `
+			SELECT c.relname,
+				CASE c.relpersistence WHEN 'u' THEN '` + PersistenceUnlogged + `' ELSE '` + PersistenceTemporary + `' END
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1
+				AND c.relkind IN ('r', 'p')
+				AND c.relpersistence IN ('u', 't')`
+	case "mysql", "mariadb":
+		query = `
+			SELECT TABLE_NAME, '` + PersistenceInMemory + `'
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_SCHEMA = ` + mysqlSchemaFilter + `
+				AND ENGINE = 'MEMORY'`
+	case "clickhouse":
+		query = `
+			SELECT name, '` + PersistenceInMemory + `'
+			FROM system.tables
+			WHERE database = ` + clickhouseSchemaFilter + `
+				AND engine = 'Memory'`
+	default:
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	persistence := make(map[string]string)
+	for rows.Next() {
+		var name, kind string
+		if err := rows.Scan(&name, &kind); err != nil {
+			return nil, err
+		}
+		persistence[name] = kind
+	}
+	return persistence, rows.Err()
+}
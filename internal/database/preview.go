@@ -1,33 +1,143 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
 )
 
+// ParseColumnFilter splits a filter written as "column = value", where column
+// names one of the columns (ignoring case), into that column and value. Other
+// filters, and a column with no value yet, are not column filters.
+func ParseColumnFilter(filterValue string, columns []string) (column, value string, ok bool) {
+	name, value, found := strings.Cut(filterValue, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !found || value == "" {
+		return "", "", false
+	}
+	for _, col := range columns {
+		if strings.EqualFold(col, name) {
+			return col, value, true
+		}
+	}
+	return "", "", false
+}
+
+// FilterWhereClause builds the preview filter condition: any column whose text
+// contains the filter value, or for "column = value" the rows whose column
+// equals the value exactly. JSON path columns are searched and compared through
+// their path expressions like the table's own. Quotes in the value are escaped.
+func FilterWhereClause(driver, filterValue string, columns []string, jsonColumns []models.JSONColumn) (string, error) {
+	escape := func(value string) string {
+		if driver == "bigquery" {
+			// BigQuery strings only take backslash escapes
+			return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value)
+		}
+		escaped := strings.ReplaceAll(value, "'", "''")
+		if driver == "mysql" || driver == "mariadb" || driver == "clickhouse" {
+			escaped = strings.ReplaceAll(escaped, `\`, `\\`)
+		}
+		return escaped
+	}
+
+	names := slices.Clone(columns)
+	exprs := make([]string, 0, len(columns)+len(jsonColumns))
+	for _, col := range columns {
+		exprs = append(exprs, QuoteIdentifier(driver, col))
+	}
+	for _, col := range jsonColumns {
+		expr, err := JSONPathExpr(driver, col)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, col.Name)
+		exprs = append(exprs, expr)
+	}
+
+	// Compare the column itself so an index on it can be used
+	if column, value, ok := ParseColumnFilter(filterValue, names); ok {
+		return fmt.Sprintf("%s = '%s'", exprs[slices.Index(names, column)], escape(value)), nil
+	}
+	escaped := escape(filterValue)
+
+	like := "LIKE"
+	if models.DriverCapabilities(driver).ILike {
+		like = "ILIKE"
+	}
+
+	whereConditions := make([]string, len(exprs))
+	for i, expr := range exprs {
+		var text string
+		switch driver {
+		case "postgres", "cockroach", "redshift":
+			text = expr + "::TEXT"
+		case "mysql", "mariadb":
+			text = fmt.Sprintf("CAST(%s AS CHAR)", expr)
+		case "clickhouse":
+			text = fmt.Sprintf("toString(%s)", expr)
+		case "trino", "snowflake":
+			text = fmt.Sprintf("CAST(%s AS VARCHAR)", expr)
+		case "bigquery":
+			text = fmt.Sprintf("CAST(%s AS STRING)", expr)
+		default:
+			text = fmt.Sprintf("CAST(%s AS TEXT)", expr)
+		}
+		whereConditions[i] = fmt.Sprintf("(%s %s '%%%s%%')", text, like, escaped)
+	}
+	return strings.Join(whereConditions, " OR "), nil
+}
+
+// OrderByClause builds an ORDER BY over the sort keys in priority order, quoting
+// each column for the driver. Keys that are off are skipped.
+func OrderByClause(driver string, keys []models.SortKey) string {
+	var terms []string
+	for _, key := range keys {
+		var direction string
+		switch key.Direction {
+		case models.SortAsc:
+			direction = "ASC"
+		case models.SortDesc:
+			direction = "DESC"
+		default:
+			continue
+		}
+		terms = append(terms, QuoteIdentifier(driver, key.Column)+" "+direction)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// pageClause limits a query to one page of rows. Trino only accepts OFFSET
+// before LIMIT, the other drivers LIMIT before OFFSET.
+func pageClause(driver string, limit, offset int) string {
+	if driver == "trino" {
+		return fmt.Sprintf(" OFFSET %d LIMIT %d", offset, limit)
+	}
+	return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+}
+
 // GetTablePreview returns first N rows from a table/view with column names
 func GetTablePreview(db *sql.DB, driver, tableName, schema string, limit int) ([]string, [][]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	if limit <= 0 {
 		limit = 10
 	}
 
-	var query string
-	switch driver {
-	case "postgres":
-		if schema == "" {
-			schema = "public"
-		}
-		query = fmt.Sprintf("SELECT * FROM \"%s\".\"%s\" LIMIT %d", schema, tableName, limit)
-	case "mysql":
-		query = fmt.Sprintf("SELECT * FROM `%s` LIMIT %d", tableName, limit)
-	case "sqlite3":
-		query = fmt.Sprintf("SELECT * FROM \"%s\" LIMIT %d", tableName, limit)
-	default:
-		return nil, nil, fmt.Errorf("unsupported driver: %s", driver)
+	table, err := tableRef(driver, schema, tableName)
+	if err != nil {
+		return nil, nil, err
 	}
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, limit)
 
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -50,14 +160,7 @@ func GetTablePreview(db *sql.DB, driver, tableName, schema string, limit int) ([
 		}
 		record := make([]string, len(cols))
 		for i, v := range values {
-			switch t := v.(type) {
-			case nil:
-				record[i] = "NULL"
-			case []byte:
-				record[i] = string(t)
-			default:
-				record[i] = fmt.Sprintf("%v", t)
-			}
+			record[i], _ = FormatValue(v)
 		}
 		result = append(result, record)
 	}
@@ -66,24 +169,17 @@ func GetTablePreview(db *sql.DB, driver, tableName, schema string, limit int) ([
 
 // GetTableRowCount returns the total number of rows in a table
 func GetTableRowCount(db *sql.DB, driver, tableName, schema string) (int, error) {
-	var query string
-	switch driver {
-	case "postgres":
-		if schema == "" {
-			schema = "public"
-		}
-		query = fmt.Sprintf("SELECT COUNT(*) FROM \"%s\".\"%s\"", schema, tableName)
-	case "mysql":
-		query = fmt.Sprintf("SELECT COUNT(*) FROM `%s`", tableName)
-	case "sqlite3":
-		query = fmt.Sprintf("SELECT COUNT(*) FROM \"%s\"", tableName)
-	default:
-		return 0, fmt.Errorf("unsupported driver: %s", driver)
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	table, err := tableRef(driver, schema, tableName)
+	if err != nil {
+		return 0, err
 	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
 
 	var count int
-	err := db.QueryRow(query).Scan(&count)
-	if err != nil {
+	if err := db.QueryRowContext(ctx, query).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -91,125 +187,67 @@ func GetTableRowCount(db *sql.DB, driver, tableName, schema string) (int, error)
 
 // GetTablePreviewPaginated returns paginated rows from a table/view with column names
 func GetTablePreviewPaginated(db *sql.DB, driver, tableName, schema string, limit, offset int) ([]string, [][]string, error) {
-	return GetTablePreviewPaginatedWithSort(db, driver, tableName, schema, limit, offset, "", "")
+	result, err := GetTablePreviewPaginatedWithSort(db, driver, tableName, schema, limit, offset, nil, nil)
+	return result.Columns, result.Rows, err
 }
 
-// GetTablePreviewPaginatedWithSort returns paginated rows from a table/view with column names and optional sorting
-func GetTablePreviewPaginatedWithSort(db *sql.DB, driver, tableName, schema string, limit, offset int, sortColumn, sortDirection string) ([]string, [][]string, error) {
+// GetTablePreviewPaginatedWithSort returns paginated rows from a table/view with
+// optional sorting, followed by any JSON path columns
+func GetTablePreviewPaginatedWithSort(db *sql.DB, driver, tableName, schema string, limit, offset int, sortKeys []models.SortKey, jsonColumns []models.JSONColumn) (ResultSet, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	if limit <= 0 {
 		limit = 10
 	}
 	offset = max(offset, 0)
 
-	var query string
-	var orderBy string
-	if sortColumn != "" && sortDirection != "" {
-		switch driver {
-		case "postgres":
-			orderBy = fmt.Sprintf(" ORDER BY \"%s\" %s", sortColumn, sortDirection)
-		case "mysql":
-			orderBy = fmt.Sprintf(" ORDER BY `%s` %s", sortColumn, sortDirection)
-		case "sqlite3":
-			orderBy = fmt.Sprintf(" ORDER BY \"%s\" %s", sortColumn, sortDirection)
-		}
-	}
+	orderBy := OrderByClause(driver, sortKeys)
 
-	switch driver {
-	case "postgres":
-		if schema == "" {
-			schema = "public"
-		}
-		query = fmt.Sprintf("SELECT * FROM \"%s\".\"%s\"%s LIMIT %d OFFSET %d", schema, tableName, orderBy, limit, offset)
-	case "mysql":
-		query = fmt.Sprintf("SELECT * FROM `%s`%s LIMIT %d OFFSET %d", tableName, orderBy, limit, offset)
-	case "sqlite3":
-		query = fmt.Sprintf("SELECT * FROM \"%s\"%s LIMIT %d OFFSET %d", tableName, orderBy, limit, offset)
-	default:
-		return nil, nil, fmt.Errorf("unsupported driver: %s", driver)
-	}
-
-	rows, err := db.Query(query)
+	table, err := tableRef(driver, schema, tableName)
 	if err != nil {
-		return nil, nil, err
+		return ResultSet{}, err
 	}
-	defer rows.Close()
-
-	cols, err := rows.Columns()
+	selectList, err := previewSelectList(driver, jsonColumns)
 	if err != nil {
-		return nil, nil, err
+		return ResultSet{}, err
 	}
+	query := fmt.Sprintf("SELECT %s FROM %s%s%s", selectList, table, orderBy, pageClause(driver, limit, offset))
 
-	var result [][]string
-	for rows.Next() {
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, nil, err
-		}
-
-		record := make([]string, len(cols))
-		for i, v := range values {
-			switch t := v.(type) {
-			case nil:
-				record[i] = "NULL"
-			case []byte:
-				record[i] = string(t)
-			default:
-				record[i] = fmt.Sprintf("%v", t)
-			}
-		}
-		result = append(result, record)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return ResultSet{}, err
 	}
+	defer rows.Close()
 
-	return cols, result, nil
+	return scanPreviewRows(rows)
 }
 
 // GetTableRowCountWithFilter returns the total number of rows in a table with filter applied
-func GetTableRowCountWithFilter(db *sql.DB, driver, tableName, schema, filterValue string, columns []string) (int, error) {
+func GetTableRowCountWithFilter(db *sql.DB, driver, tableName, schema, filterValue string, columns []string, jsonColumns []models.JSONColumn) (int, error) {
 	if filterValue == "" {
 		return GetTableRowCount(db, driver, tableName, schema)
 	}
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+	return GetTableRowCountWithFilterContext(ctx, db, driver, tableName, schema, filterValue, columns, jsonColumns)
+}
 
-	var query string
-	switch driver {
-	case "postgres":
-		if schema == "" {
-			schema = "public"
-		}
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(\"%s\"::TEXT ILIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT COUNT(*) FROM \"%s\".\"%s\" WHERE %s", schema, tableName, whereClause)
-	case "mysql":
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(CAST(`%s` AS CHAR) LIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE %s", tableName, whereClause)
-	case "sqlite3":
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(CAST(\"%s\" AS TEXT) LIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT COUNT(*) FROM \"%s\" WHERE %s", tableName, whereClause)
-	default:
-		return 0, fmt.Errorf("unsupported driver: %s", driver)
+// GetTableRowCountWithFilterContext counts the rows matching a non-empty filter,
+// giving up when ctx is done
+func GetTableRowCountWithFilterContext(ctx context.Context, db *sql.DB, driver, tableName, schema, filterValue string, columns []string, jsonColumns []models.JSONColumn) (int, error) {
+	table, err := tableRef(driver, schema, tableName)
+	if err != nil {
+		return 0, err
 	}
+	where, err := FilterWhereClause(driver, filterValue, columns, jsonColumns)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", table, where)
 
 	var count int
-	err := db.QueryRow(query).Scan(&count)
-	if err != nil {
+	if err := db.QueryRowContext(ctx, query).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -217,13 +255,19 @@ func GetTableRowCountWithFilter(db *sql.DB, driver, tableName, schema, filterVal
 
 // GetTablePreviewPaginatedWithFilter returns paginated rows from a table/view with filter applied
 func GetTablePreviewPaginatedWithFilter(db *sql.DB, driver, tableName, schema string, limit, offset int, filterValue string, columns []string) ([]string, [][]string, error) {
-	return GetTablePreviewPaginatedWithFilterAndSort(db, driver, tableName, schema, limit, offset, filterValue, columns, "", "")
+	result, err := GetTablePreviewPaginatedWithFilterAndSort(db, driver, tableName, schema, limit, offset, filterValue, columns, nil, nil)
+	return result.Columns, result.Rows, err
 }
 
-// GetTablePreviewPaginatedWithFilterAndSort returns paginated rows from a table/view with filter and sort applied
-func GetTablePreviewPaginatedWithFilterAndSort(db *sql.DB, driver, tableName, schema string, limit, offset int, filterValue string, columns []string, sortColumn, sortDirection string) ([]string, [][]string, error) {
+// GetTablePreviewPaginatedWithFilterAndSort returns paginated rows from a
+// table/view with filter and sort applied, followed by any JSON path columns.
+// The filter searches the table's columns and the JSON path columns.
+func GetTablePreviewPaginatedWithFilterAndSort(db *sql.DB, driver, tableName, schema string, limit, offset int, filterValue string, columns []string, sortKeys []models.SortKey, jsonColumns []models.JSONColumn) (ResultSet, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	if filterValue == "" {
-		return GetTablePreviewPaginatedWithSort(db, driver, tableName, schema, limit, offset, sortColumn, sortDirection)
+		return GetTablePreviewPaginatedWithSort(db, driver, tableName, schema, limit, offset, sortKeys, jsonColumns)
 	}
 
 	if limit <= 0 {
@@ -231,63 +275,48 @@ func GetTablePreviewPaginatedWithFilterAndSort(db *sql.DB, driver, tableName, sc
 	}
 	offset = max(offset, 0)
 
-	var orderBy string
-	if sortColumn != "" && sortDirection != "" {
-		switch driver {
-		case "postgres":
-			orderBy = fmt.Sprintf(" ORDER BY \"%s\" %s", sortColumn, sortDirection)
-		case "mysql":
-			orderBy = fmt.Sprintf(" ORDER BY `%s` %s", sortColumn, sortDirection)
-		case "sqlite3":
-			orderBy = fmt.Sprintf(" ORDER BY \"%s\" %s", sortColumn, sortDirection)
-		}
-	}
+	orderBy := OrderByClause(driver, sortKeys)
 
-	var query string
-	switch driver {
-	case "postgres":
-		if schema == "" {
-			schema = "public"
-		}
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(\"%s\"::TEXT ILIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT * FROM \"%s\".\"%s\" WHERE %s%s LIMIT %d OFFSET %d", schema, tableName, whereClause, orderBy, limit, offset)
-	case "mysql":
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(CAST(`%s` AS CHAR) LIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT * FROM `%s` WHERE %s%s LIMIT %d OFFSET %d", tableName, whereClause, orderBy, limit, offset)
-	case "sqlite3":
-		// Build WHERE clause with OR conditions for each column
-		whereConditions := make([]string, len(columns))
-		for i, col := range columns {
-			whereConditions[i] = fmt.Sprintf("(CAST(\"%s\" AS TEXT) LIKE '%%%s%%')", col, filterValue)
-		}
-		whereClause := strings.Join(whereConditions, " OR ")
-		query = fmt.Sprintf("SELECT * FROM \"%s\" WHERE %s%s LIMIT %d OFFSET %d", tableName, whereClause, orderBy, limit, offset)
-	default:
-		return nil, nil, fmt.Errorf("unsupported driver: %s", driver)
+	table, err := tableRef(driver, schema, tableName)
+	if err != nil {
+		return ResultSet{}, err
+	}
+	selectList, err := previewSelectList(driver, jsonColumns)
+	if err != nil {
+		return ResultSet{}, err
 	}
+	where, err := FilterWhereClause(driver, filterValue, columns, jsonColumns)
+	if err != nil {
+		return ResultSet{}, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s%s%s", selectList, table, where, orderBy, pageClause(driver, limit, offset))
 
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, err
+		return ResultSet{}, err
 	}
 	defer rows.Close()
 
+	return scanPreviewRows(rows)
+}
+
+// ResultSet is a page of rows read as display text. Nulls marks the cells that
+// were SQL NULL, which are shown as "NULL" but stay distinct from that text.
+type ResultSet struct {
+	Columns []string
+	Types   []string // Database type of each column, as reported by the driver
+	Rows    [][]string
+	Nulls   [][]bool
+}
+
+// scanPreviewRows reads every row as display text, keeping which cells were NULL
+func scanPreviewRows(rows *sql.Rows) (ResultSet, error) {
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, nil, err
+		return ResultSet{}, err
 	}
+	result := ResultSet{Columns: cols, Types: ColumnTypeNames(rows)}
 
-	var result [][]string
 	for rows.Next() {
 		values := make([]interface{}, len(cols))
 		valuePtrs := make([]interface{}, len(cols))
@@ -296,22 +325,31 @@ func GetTablePreviewPaginatedWithFilterAndSort(db *sql.DB, driver, tableName, sc
 		}
 
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, nil, err
+			return ResultSet{}, err
 		}
 
 		record := make([]string, len(cols))
+		nulls := make([]bool, len(cols))
 		for i, v := range values {
-			switch t := v.(type) {
-			case nil:
-				record[i] = "NULL"
-			case []byte:
-				record[i] = string(t)
-			default:
-				record[i] = fmt.Sprintf("%v", t)
-			}
+			record[i], nulls[i] = FormatValue(v)
 		}
-		result = append(result, record)
+		result.Rows = append(result.Rows, record)
+		result.Nulls = append(result.Nulls, nulls)
 	}
 
-	return cols, result, nil
+	return result, rows.Err()
+}
+
+// ColumnTypeNames returns the lowercased database type of each result column,
+// e.g. "int4" or "varchar". A type the driver does not report is left empty.
+func ColumnTypeNames(rows *sql.Rows) []string {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		names[i] = strings.ToLower(ct.DatabaseTypeName())
+	}
+	return names
 }
@@ -2,25 +2,58 @@ package database
 
 import (
 	"database/sql"
-	"fmt"
-	"strings"
 
 	"github.com/dancaldera/mirador/internal/models"
 )
 
-// GetTables retrieves all tables from the database
-func GetTables(db *sql.DB, driver string) ([]string, error) {
+// GetTables retrieves all tables from the given schema (PostgreSQL) or database (MySQL, ClickHouse).
+// An empty MySQL or ClickHouse schema means the connection's current database, and an
+// empty Snowflake schema the session's. BigQuery has no tables outside a dataset.
+func GetTables(db *sql.DB, driver, schema string) ([]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var query string
+	var args []interface{}
 	switch driver {
-	case "postgres":
-		query = "SELECT tablename FROM pg_tables WHERE schemaname = 'public'"
-	case "mysql":
-		query = "SHOW TABLES"
+	case "postgres", "redshift":
+		if schema == "" {
+			schema = "public"
+		}
+		query = "SELECT tablename FROM pg_tables WHERE schemaname = $1"
+		if driver == "postgres" {
+			// pg_tables leaves out foreign tables
+			query += ` UNION SELECT c.relname FROM pg_class c
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE c.relkind = 'f' AND n.nspname = $1`
+		}
+		args = []interface{}{schema}
+	case "cockroach":
+		query = "SELECT table_name FROM " + cockroachShowTables(schema) + " WHERE type = 'table' ORDER BY table_name"
+	case "mysql", "mariadb":
+		query = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = " + mysqlSchemaFilter
+		args = []interface{}{schema}
 	case "sqlite3":
-		query = "SELECT name FROM sqlite_master WHERE type='table'"
+		query = "SELECT name FROM " + sqliteMaster(schema) + " WHERE type='table'"
+	case "clickhouse":
+		query = "SELECT name FROM system.tables WHERE database = " + clickhouseSchemaFilter + " AND NOT is_temporary ORDER BY name"
+		args = []interface{}{schema}
+	case "trino":
+		catalog, name := SplitTrinoSchema(schema)
+		query = "SELECT table_name FROM " + trinoInformationSchema(catalog) + ".tables WHERE table_schema = " + trinoSchemaFilter + " ORDER BY table_name"
+		args = []interface{}{name}
+	case "snowflake":
+		query = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = " + snowflakeSchemaFilter + " ORDER BY TABLE_NAME"
+		args = []interface{}{schema}
+	case "bigquery":
+		if schema == "" {
+			// No dataset is open until one is picked in the schema view
+			return nil, nil
+		}
+		query = "SELECT table_name FROM " + bigqueryInformationSchema(schema, "TABLES") + " ORDER BY table_name"
 	}
 
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,27 +71,53 @@ func GetTables(db *sql.DB, driver string) ([]string, error) {
 	return tables, nil
 }
 
-// GetSchemas retrieves schema information for PostgreSQL
+// mysqlSchemaFilter matches the selected database, or the connection's current
+// database when no database is selected. It takes the schema as its argument.
+const mysqlSchemaFilter = "COALESCE(NULLIF(?, ''), DATABASE())"
+
+// GetCurrentDatabase returns the MySQL or ClickHouse connection's current database, or "" if none is selected
+func GetCurrentDatabase(db *sql.DB) (string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var name sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&name); err != nil {
+		return "", err
+	}
+	return name.String, nil
+}
+
+// GetSchemas retrieves schema information for PostgreSQL and databases for MySQL
 func GetSchemas(db *sql.DB, driver string) ([]models.SchemaInfo, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
 	var schemas []models.SchemaInfo
 
 	switch driver {
-	case "postgres":
+	case "postgres", "cockroach", "redshift":
+		systemSchemas := "'information_schema', 'pg_catalog', 'pg_toast'"
+		switch driver {
+		case "cockroach":
+			systemSchemas = cockroachSystemSchemas
+		case "redshift":
+			systemSchemas = redshiftSystemSchemas
+		}
 		query := `
 			SELECT
 				schema_name,
 				CASE
 					WHEN schema_name = 'public' THEN 'Default public schema'
-					WHEN schema_name IN ('information_schema', 'pg_catalog', 'pg_toast') THEN 'System schema'
+					WHEN schema_name IN (` + systemSchemas + `) THEN 'System schema'
 					ELSE 'User schema'
 				END as description
 			FROM information_schema.schemata
-			WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+			WHERE schema_name NOT IN (` + systemSchemas + `)
 			ORDER BY
 				CASE WHEN schema_name = 'public' THEN 0 ELSE 1 END,
 				schema_name`
 
-		rows, err := db.Query(query)
+		rows, err := db.QueryContext(ctx, query)
 		if err != nil {
 			// If schema query fails, return just the public schema
 			return []models.SchemaInfo{{Name: "public", Description: "Default public schema"}}, nil
@@ -79,220 +138,88 @@ func GetSchemas(db *sql.DB, driver string) ([]models.SchemaInfo, error) {
 			schemas = append(schemas, models.SchemaInfo{Name: "public", Description: "Default public schema"})
 		}
 
-	case "mysql", "sqlite3":
-		// MySQL and SQLite don't have schemas in the same way PostgreSQL does
-		return []models.SchemaInfo{}, nil
-	}
-
-	return schemas, nil
-}
-
-// GetTableInfos retrieves detailed table information including row counts
-func GetTableInfos(db *sql.DB, driver, schema string) ([]models.TableInfo, error) {
-	var tableInfos []models.TableInfo
-
-	switch driver {
-	case "postgres":
+	case "mysql", "mariadb":
+		// MySQL databases play the role of PostgreSQL schemas
 		query := `
-			SELECT
-				table_name,
-				table_schema as schema_name,
-				table_type,
-				CASE
-					WHEN table_type = 'BASE TABLE' THEN COALESCE(s.n_tup_ins + s.n_tup_upd - s.n_tup_del, 0)
-					ELSE 0
-				END as estimated_rows
-			FROM information_schema.tables t
-			LEFT JOIN pg_stat_user_tables s ON t.table_name = s.relname AND t.table_schema = s.schemaname
-			WHERE t.table_schema = $1
-				AND t.table_type IN ('BASE TABLE', 'VIEW')
-			ORDER BY t.table_type, t.table_name`
+			SELECT SCHEMA_NAME,
+				CASE WHEN SCHEMA_NAME = DATABASE() THEN 'Current database' ELSE 'Database' END
+			FROM INFORMATION_SCHEMA.SCHEMATA
+			WHERE SCHEMA_NAME NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+			ORDER BY CASE WHEN SCHEMA_NAME = DATABASE() THEN 0 ELSE 1 END, SCHEMA_NAME`
 
-		rows, err := db.Query(query, schema)
+		rows, err := db.QueryContext(ctx, query)
 		if err != nil {
-			// Fallback to simple table list if stats are not available
-			return GetSimpleTableInfos(db, driver, schema)
+			return nil, err
 		}
 		defer rows.Close()
 
 		for rows.Next() {
-			var info models.TableInfo
-			var estimatedRows sql.NullInt64
-			err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &estimatedRows)
-			if err != nil {
+			var schema models.SchemaInfo
+			if err := rows.Scan(&schema.Name, &schema.Description); err != nil {
 				continue
 			}
-
-			// Determine the object type display name
-			var objectType string
-			var emoji string
-			if info.TableType == "VIEW" {
-				objectType = "view"
-				emoji = "👁️"
-			} else {
-				objectType = "table"
-				emoji = ""
-			}
-
-			if info.TableType == "BASE TABLE" && estimatedRows.Valid && estimatedRows.Int64 > 0 {
-				info.RowCount = estimatedRows.Int64
-				info.RowCount = max(info.RowCount, 0)
-				if info.Schema != "" && info.Schema != "public" {
-					info.Description = fmt.Sprintf("%s %s.%s • ~%d rows", emoji, info.Schema, objectType, info.RowCount)
-				} else {
-					info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
-				}
-			} else {
-				if info.Schema != "" && info.Schema != "public" {
-					info.Description = fmt.Sprintf("%s %s.%s", emoji, info.Schema, objectType)
-				} else {
-					info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
-				}
-			}
-
-			tableInfos = append(tableInfos, info)
+			schemas = append(schemas, schema)
 		}
 
-	case "mysql":
+	case "clickhouse":
+		// ClickHouse databases play the role of PostgreSQL schemas, as in MySQL
 		query := `
-			SELECT
-				TABLE_NAME,
-				TABLE_SCHEMA,
-				TABLE_TYPE,
-				COALESCE(TABLE_ROWS, 0) as table_rows
-			FROM INFORMATION_SCHEMA.TABLES
-			WHERE TABLE_SCHEMA = DATABASE()
-				AND TABLE_TYPE IN ('BASE TABLE', 'VIEW')
-			ORDER BY TABLE_TYPE, TABLE_NAME`
+			SELECT name,
+				if(name = currentDatabase(), 'Current database', 'Database')
+			FROM system.databases
+			WHERE name NOT IN ('system', 'information_schema', 'INFORMATION_SCHEMA')
+			ORDER BY name != currentDatabase(), name`
 
-		rows, err := db.Query(query)
+		rows, err := db.QueryContext(ctx, query)
 		if err != nil {
-			return GetSimpleTableInfos(db, driver, schema)
+			return nil, err
 		}
 		defer rows.Close()
 
 		for rows.Next() {
-			var info models.TableInfo
-			var tableRows sql.NullInt64
-			err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &tableRows)
-			if err != nil {
+			var schema models.SchemaInfo
+			if err := rows.Scan(&schema.Name, &schema.Description); err != nil {
 				continue
 			}
+			schemas = append(schemas, schema)
+		}
 
-			// Determine the object type display name
-			var objectType string
-			var emoji string
-			if info.TableType == "VIEW" {
-				objectType = "view"
-				emoji = "👁️"
-			} else {
-				objectType = "table"
-				emoji = ""
-			}
+	case "trino":
+		return trinoSchemas(ctx, db)
 
-			if info.TableType == "BASE TABLE" && tableRows.Valid && tableRows.Int64 > 0 {
-				info.RowCount = tableRows.Int64
-				info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
-			} else {
-				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
-			}
+	case "snowflake":
+		return snowflakeSchemas(ctx, db)
 
-			tableInfos = append(tableInfos, info)
-		}
+	case "bigquery":
+		return bigquerySchemas(ctx, db)
 
 	case "sqlite3":
-		// SQLite: Get both tables and views from sqlite_master
-		query := `
-			SELECT name, type
-			FROM sqlite_master
-			WHERE type IN ('table', 'view')
-				AND name NOT LIKE 'sqlite_%'
-			ORDER BY type, name`
-
-		rows, err := db.Query(query)
+		// The main database and each attached database play the role of schemas
+		rows, err := db.QueryContext(ctx, "PRAGMA database_list")
 		if err != nil {
-			return GetSimpleTableInfos(db, driver, schema)
+			return nil, err
 		}
 		defer rows.Close()
 
 		for rows.Next() {
-			var name, objType string
-			err := rows.Scan(&name, &objType)
-			if err != nil {
+			var seq int
+			var name, file string
+			if err := rows.Scan(&seq, &name, &file); err != nil {
 				continue
 			}
-
-			info := models.TableInfo{
-				Name:   name,
-				Schema: "main", // SQLite uses "main" as the default schema
-			}
-
-			// Determine the object type display name
-			var objectType string
-			var emoji string
-			if objType == "view" {
-				info.TableType = "VIEW"
-				objectType = "view"
-				emoji = "👁️"
-			} else {
-				info.TableType = "BASE TABLE"
-				objectType = "table"
-				emoji = ""
+			if file == "" {
+				file = "in memory"
 			}
-
-			// Try to get row count for tables only (views don't have meaningful row counts)
-			if objType == "table" {
-				countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, name)
-				var count int64
-				err := db.QueryRow(countQuery).Scan(&count)
-				if err == nil {
-					info.RowCount = count
-					info.Description = fmt.Sprintf("%s %s • %d rows", emoji, strings.Title(objectType), count)
-				} else {
-					info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
-				}
-			} else {
-				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+			switch name {
+			case "temp":
+				continue
+			case "main":
+				schemas = append(schemas, models.SchemaInfo{Name: name, Description: "Main database • " + file})
+			default:
+				schemas = append(schemas, models.SchemaInfo{Name: name, Description: "Attached database • " + file})
 			}
-
-			tableInfos = append(tableInfos, info)
 		}
-
-	default:
-		return GetSimpleTableInfos(db, driver, schema)
 	}
 
-	return tableInfos, nil
-}
-
-// GetSimpleTableInfos provides a fallback for basic table information
-func GetSimpleTableInfos(db *sql.DB, driver, schema string) ([]models.TableInfo, error) {
-	tables, err := GetTables(db, driver)
-	if err != nil {
-		return nil, err
-	}
-
-	var tableInfos []models.TableInfo
-	for _, tableName := range tables {
-		var schemaName string
-		switch driver {
-		case "postgres":
-			schemaName = schema
-		case "mysql":
-			schemaName = "mysql" // Default schema name for MySQL
-		case "sqlite3":
-			schemaName = "main"
-		default:
-			schemaName = ""
-		}
-
-		tableInfos = append(tableInfos, models.TableInfo{
-			Name:        tableName,
-			Schema:      schemaName,
-			TableType:   "BASE TABLE",
-			Description: "Table",
-		})
-	}
-
-	return tableInfos, nil
+	return schemas, nil
 }
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SQLiteReadOnlyDSN rewrites a SQLite connection string to open the file
+// read-only (mode=ro), so no statement can write to it and no write lock is
+// taken. immutable also tells SQLite the file cannot change (immutable=1), for
+// files on read-only media: it then takes no locks at all, and must not be used
+// on a file another process writes. A plain path becomes a file: URI; the
+// driver's own parameters are kept.
+func SQLiteReadOnlyDSN(dsn string, immutable bool) string {
+	path, query := dsn, ""
+	if i := strings.IndexByte(dsn, '?'); i >= 0 {
+		path, query = dsn[:i], dsn[i+1:]
+	}
+	if !strings.HasPrefix(path, "file:") {
+		path = "file:" + strings.NewReplacer("%", "%25", "#", "%23").Replace(path)
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		params = url.Values{}
+	}
+	params.Set("mode", "ro")
+	if immutable {
+		params.Set("immutable", "1")
+	}
+	return path + "?" + params.Encode()
+}
+
+// ReadOnlySessionParam is added to the end of a PostgreSQL- or MySQL-family
+// connection string by ReadOnlySessionDSN. Open takes it out again and makes
+// every new connection's session read-only, so the server refuses any write,
+// including statements Mirador does not recognize as one.
+const ReadOnlySessionParam = "_read_only"
+
+// readOnlySessionStatements make the transactions of a new session read-only,
+// by driver
+var readOnlySessionStatements = map[string]string{
+	"postgres":  "SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY",
+	"cockroach": "SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY",
+	"redshift":  "SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY",
+	"mysql":     "SET SESSION TRANSACTION READ ONLY",
+	"mariadb":   "SET SESSION TRANSACTION READ ONLY",
+}
+
+// ReadOnlySessionDSN marks a connection string to be opened with read-only
+// sessions. Connection strings of drivers without a read-only session, such as
+// ClickHouse and Trino, are returned unchanged.
+func ReadOnlySessionDSN(driver, dsn string) string {
+	if _, ok := readOnlySessionStatements[driver]; !ok {
+		return dsn
+	}
+	switch {
+	case !strings.Contains(dsn, "://") && (driver == "postgres" || driver == "cockroach" || driver == "redshift"):
+		return strings.TrimSpace(dsn) + " " + ReadOnlySessionParam + "=1"
+	case strings.Contains(dsn, "?"):
+		return dsn + "&" + ReadOnlySessionParam + "=1"
+	}
+	return dsn + "?" + ReadOnlySessionParam + "=1"
+}
+
+// splitReadOnlySession takes the read-only session mark off a connection
+// string, reporting whether it was there
+func splitReadOnlySession(dsn string) (string, bool) {
+	for _, sep := range []string{" ", "&", "?"} {
+		if rest, ok := strings.CutSuffix(dsn, sep+ReadOnlySessionParam+"=1"); ok {
+			return rest, true
+		}
+	}
+	return dsn, false
+}
+
+// openReadOnly opens a pool whose connections run the driver's read-only
+// session statement before they are used
+func openReadOnly(driverName, dsn string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close()
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: drv}
+	if opener, ok := drv.(driver.DriverContext); ok {
+		if connector, err = opener.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(readOnlyConnector{Connector: connector, statement: readOnlySessionStatements[driverName]}), nil
+}
+
+// readOnlyConnector opens connections whose sessions are read-only
+type readOnlyConnector struct {
+	driver.Connector
+	statement string
+}
+
+// Connect opens a connection and makes its session read-only
+func (c readOnlyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := execSession(ctx, conn, c.statement); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to make the session read-only: %w", err)
+	}
+	return conn, nil
+}
+
+// execSession runs a statement without arguments on a driver connection
+func execSession(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		return err
+	}
+	stmt, err := conn.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// dsnConnector opens connections of a driver without its own connector
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
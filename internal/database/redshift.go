@@ -0,0 +1,21 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// Redshift speaks the PostgreSQL wire protocol, so lib/pq is registered again
+// under the "redshift" name. Queries share the PostgreSQL dialect except where
+// Redshift's catalog differs: sizes and row counts come from SVV_TABLE_INFO
+// because Redshift has no pg_stat_user_tables or pg_*_size functions.
+func init() {
+	sql.Register("redshift", &pq.Driver{})
+}
+
+// redshiftSystemSchemas are the catalog schemas hidden from the schema list
+const redshiftSystemSchemas = "'information_schema', 'pg_catalog', 'pg_internal', 'pg_automv', 'catalog_history'"
+
+// redshiftBlockBytes is the size of the 1 MB blocks SVV_TABLE_INFO counts in
+const redshiftBlockBytes = 1024 * 1024
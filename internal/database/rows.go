@@ -0,0 +1,57 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetRowByKey re-reads a single row identified by a key column value.
+// It returns a nil row when no record matches the key anymore, and marks which
+// values were NULL.
+func GetRowByKey(db *sql.DB, driver, tableName, schema, keyColumn, keyValue string) ([]string, []string, []bool, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	table, err := tableRef(driver, schema, tableName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	placeholder := "?"
+	if models.DriverCapabilities(driver).NumberedPlaceholders {
+		placeholder = "$1"
+	}
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = %s LIMIT 1",
+		table, QuoteIdentifier(driver, keyColumn), placeholder)
+
+	rows, err := db.QueryContext(ctx, query, keyValue)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !rows.Next() {
+		return cols, nil, nil, rows.Err()
+	}
+
+	values := make([]interface{}, len(cols))
+	valuePtrs := make([]interface{}, len(cols))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, nil, nil, err
+	}
+
+	record := make([]string, len(cols))
+	nulls := make([]bool, len(cols))
+	for i, v := range values {
+		record[i], nulls[i] = FormatValue(v)
+	}
+	return cols, record, nulls, nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// sqlitePragmaDefaults lists the queryable SQLite pragmas and their stock defaults
+var sqlitePragmaDefaults = []struct {
+	name, def, desc string
+}{
+	{"application_id", "0", "Application identifier stored in the database header"},
+	{"auto_vacuum", "0", "Automatic vacuum mode (0=none, 1=full, 2=incremental)"},
+	{"automatic_index", "1", "Automatic indexing of queries without a usable index"},
+	{"busy_timeout", "0", "Milliseconds to wait for a locked database"},
+	{"cache_size", "-2000", "Suggested page cache size (negative = KiB)"},
+	{"cell_size_check", "0", "Extra sanity checks on b-tree cells"},
+	{"encoding", "UTF-8", "Text encoding of the database"},
+	{"foreign_keys", "0", "Enforcement of foreign key constraints"},
+	{"journal_mode", "delete", "Rollback journal mode"},
+	{"journal_size_limit", "-1", "Maximum size of a retained journal file"},
+	{"locking_mode", "normal", "Database connection locking mode"},
+	{"mmap_size", "0", "Maximum bytes used for memory-mapped I/O"},
+	{"page_size", "4096", "Database page size in bytes"},
+	{"query_only", "0", "Prevent data changes on this connection"},
+	{"recursive_triggers", "0", "Recursive trigger firing"},
+	{"secure_delete", "0", "Overwrite deleted content with zeros"},
+	{"synchronous", "2", "Disk synchronization level (0=off, 1=normal, 2=full, 3=extra)"},
+	{"temp_store", "0", "Location of temporary tables (0=default, 1=file, 2=memory)"},
+	{"user_version", "0", "User version number stored in the database header"},
+	{"wal_autocheckpoint", "1000", "WAL auto-checkpoint interval in pages"},
+}
+
+// GetServerSettings returns the server configuration variables for the driver
+func GetServerSettings(db *sql.DB, driver string) ([]models.ServerSetting, error) {
+	switch driver {
+	case "postgres", "cockroach":
+		// CockroachDB implements pg_settings for its session variables
+		return getPostgresSettings(db)
+	case "mysql":
+		return getMySQLSettings(db)
+	case "mariadb":
+		return getMariaDBSettings(db)
+	case "sqlite3":
+		return getSQLiteSettings(db)
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", driver)
+	}
+}
+
+func getPostgresSettings(db *sql.DB) ([]models.ServerSetting, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT name, COALESCE(setting, ''), COALESCE(unit, ''), COALESCE(boot_val, ''),
+			setting IS DISTINCT FROM boot_val, COALESCE(short_desc, '')
+		FROM pg_settings
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pg_settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.ServerSetting
+	for rows.Next() {
+		var s models.ServerSetting
+		if err := rows.Scan(&s.Name, &s.Value, &s.Unit, &s.Default, &s.Modified, &s.Description); err != nil {
+			return nil, err
+		}
+		settings = append(settings, s)
+	}
+	return settings, rows.Err()
+}
+
+func getMySQLSettings(db *sql.DB) ([]models.ServerSetting, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	// MySQL 8 records where each variable came from; COMPILED means the built-in default
+	rows, err := db.QueryContext(ctx, `
+		SELECT v.VARIABLE_NAME, COALESCE(v.VARIABLE_VALUE, ''), COALESCE(i.VARIABLE_SOURCE, 'COMPILED')
+		FROM performance_schema.global_variables v
+		LEFT JOIN performance_schema.variables_info i ON i.VARIABLE_NAME = v.VARIABLE_NAME
+		ORDER BY v.VARIABLE_NAME`)
+	if err == nil {
+		defer rows.Close()
+		var settings []models.ServerSetting
+		for rows.Next() {
+			var s models.ServerSetting
+			var source string
+			if err := rows.Scan(&s.Name, &s.Value, &source); err != nil {
+				return nil, err
+			}
+			s.Modified = source != "COMPILED"
+			if s.Modified {
+				s.Description = "Set from " + source
+			}
+			settings = append(settings, s)
+		}
+		return settings, rows.Err()
+	}
+
+	return showVariables(db)
+}
+
+func getMariaDBSettings(db *sql.DB) ([]models.ServerSetting, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	// MariaDB describes its variables in information_schema rather than performance_schema
+	rows, err := db.QueryContext(ctx, `
+		SELECT LOWER(VARIABLE_NAME), COALESCE(GLOBAL_VALUE, ''), COALESCE(DEFAULT_VALUE, ''),
+			GLOBAL_VALUE_ORIGIN, COALESCE(VARIABLE_COMMENT, '')
+		FROM information_schema.SYSTEM_VARIABLES
+		ORDER BY VARIABLE_NAME`)
+	if err != nil {
+		return showVariables(db)
+	}
+	defer rows.Close()
+
+	var settings []models.ServerSetting
+	for rows.Next() {
+		var s models.ServerSetting
+		var origin string
+		if err := rows.Scan(&s.Name, &s.Value, &s.Default, &origin, &s.Description); err != nil {
+			return nil, err
+		}
+		s.Modified = origin != "COMPILE-TIME"
+		settings = append(settings, s)
+	}
+	return settings, rows.Err()
+}
+
+// showVariables lists current values only, for servers without variable metadata
+func showVariables(db *sql.DB) ([]models.ServerSetting, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SHOW VARIABLES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server variables: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.ServerSetting
+	for rows.Next() {
+		var s models.ServerSetting
+		var value sql.NullString
+		if err := rows.Scan(&s.Name, &value); err != nil {
+			return nil, err
+		}
+		s.Value = value.String
+		settings = append(settings, s)
+	}
+	return settings, rows.Err()
+}
+
+func getSQLiteSettings(db *sql.DB) ([]models.ServerSetting, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var settings []models.ServerSetting
+	for _, p := range sqlitePragmaDefaults {
+		var value sql.NullString
+		if err := db.QueryRowContext(ctx, fmt.Sprintf("PRAGMA %s", p.name)).Scan(&value); err != nil {
+			continue
+		}
+		settings = append(settings, models.ServerSetting{
+			Name:        p.name,
+			Value:       value.String,
+			Default:     p.def,
+			Modified:    value.String != p.def,
+			Description: p.desc,
+		})
+	}
+	return settings, nil
+}
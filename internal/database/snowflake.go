@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// Snowflake is reached through the gosnowflake driver, which registers itself as
+// "snowflake" and pages in large results in chunks as rows are read. Connection
+// strings name the account rather than a host, with the warehouse and role as
+// parameters:
+//
+//	user:password@orgname-account/database/schema?warehouse=COMPUTE_WH&role=ANALYST
+//
+// Tables and columns are read from the database's INFORMATION_SCHEMA, whose
+// schemas play the role of PostgreSQL schemas.
+
+// snowflakeSchemaFilter matches the selected schema, or the session's schema when
+// no schema is selected. It takes the schema as its argument.
+const snowflakeSchemaFilter = "COALESCE(NULLIF(?, ''), CURRENT_SCHEMA())"
+
+// snowflakeTableName quotes a table, qualified by schema when one is selected
+func snowflakeTableName(schema, table string) string {
+	if schema == "" {
+		return QuoteIdentifier("snowflake", table)
+	}
+	return QuoteIdentifier("snowflake", schema) + "." + QuoteIdentifier("snowflake", table)
+}
+
+// validateSnowflakeConnection checks a connection string the way the driver reads it
+func validateSnowflakeConnection(connectionStr string) error {
+	if _, err := gosnowflake.ParseDSN(connectionStr); err != nil {
+		return fmt.Errorf("cannot read the Snowflake connection string: %w", err)
+	}
+	return nil
+}
+
+// snowflakeUserInfo splits a connection string at the @ before its account into
+// the user info and the rest
+func snowflakeUserInfo(dsn string) (userInfo, rest string, ok bool) {
+	end := len(dsn)
+	if i := strings.IndexAny(dsn, "/?"); i >= 0 {
+		end = i
+	}
+	at := strings.LastIndex(dsn[:end], "@")
+	if at < 0 {
+		return "", dsn, false
+	}
+	return dsn[:at], dsn[at+1:], true
+}
+
+// buildSnowflakeDSN assembles a connection string from the form's fields: Host
+// is the account identifier, Database is a database optionally followed by
+// /schema, and the warehouse and role are written before the other options
+func buildSnowflakeDSN(fields models.ConnectionFields) (string, error) {
+	account := strings.TrimSpace(fields.Host)
+	if account == "" {
+		return "", fmt.Errorf("Snowflake needs an account identifier, such as orgname-account")
+	}
+	options := strings.TrimPrefix(strings.TrimSpace(fields.Options), "?")
+	if _, err := url.ParseQuery(options); err != nil {
+		return "", fmt.Errorf("options must look like key=value&key=value: %w", err)
+	}
+
+	var dsn strings.Builder
+	if user := strings.TrimSpace(fields.User); user != "" {
+		dsn.WriteString(url.QueryEscape(user))
+		if fields.Password != "" {
+			dsn.WriteString(":" + url.QueryEscape(fields.Password))
+		}
+		dsn.WriteString("@")
+	}
+	dsn.WriteString(account)
+	if database := strings.Trim(strings.TrimSpace(fields.Database), "/"); database != "" {
+		dsn.WriteString("/" + database)
+	}
+
+	var params []string
+	for _, param := range [][2]string{{"warehouse", fields.Warehouse}, {"role", fields.Role}} {
+		if value := strings.TrimSpace(param[1]); value != "" {
+			params = append(params, param[0]+"="+url.QueryEscape(value))
+		}
+	}
+	if options != "" {
+		params = append(params, options)
+	}
+	if len(params) > 0 {
+		dsn.WriteString("?" + strings.Join(params, "&"))
+	}
+	return dsn.String(), nil
+}
+
+// parseSnowflakeDSN splits a connection string into the form's fields, taking
+// the warehouse and role out of its parameters
+func parseSnowflakeDSN(dsn string) (models.ConnectionFields, error) {
+	var fields models.ConnectionFields
+	userInfo, rest, ok := snowflakeUserInfo(dsn)
+	if ok {
+		user, password, _ := strings.Cut(userInfo, ":")
+		var err error
+		if fields.User, err = url.QueryUnescape(user); err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the user: %w", err)
+		}
+		if fields.Password, err = url.QueryUnescape(password); err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the password: %w", err)
+		}
+	}
+
+	rest, query, _ := strings.Cut(rest, "?")
+	fields.Host, fields.Database, _ = strings.Cut(rest, "/")
+	if fields.Host == "" {
+		return models.ConnectionFields{}, fmt.Errorf("the connection string has no account")
+	}
+	if strings.Contains(fields.Host, ":") {
+		return models.ConnectionFields{}, fmt.Errorf("only account identifiers can be edited as fields, not host:port")
+	}
+
+	var options []string
+	for _, part := range strings.Split(query, "&") {
+		name, value, _ := strings.Cut(part, "=")
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return models.ConnectionFields{}, fmt.Errorf("cannot read the %s parameter: %w", name, err)
+		}
+		switch strings.ToLower(name) {
+		case "":
+		case "warehouse":
+			fields.Warehouse = unescaped
+		case "role":
+			fields.Role = unescaped
+		default:
+			options = append(options, part)
+		}
+	}
+	fields.Options = strings.Join(options, "&")
+	return fields, nil
+}
+
+// snowflakeSchemas lists the schemas of the connection's database, the
+// session's schema first
+func snowflakeSchemas(ctx context.Context, db *sql.DB) ([]models.SchemaInfo, error) {
+	query := `
+		SELECT SCHEMA_NAME,
+			CASE WHEN SCHEMA_NAME = CURRENT_SCHEMA() THEN 'Current schema' ELSE 'Schema' END
+		FROM INFORMATION_SCHEMA.SCHEMATA
+		WHERE SCHEMA_NAME <> 'INFORMATION_SCHEMA'
+		ORDER BY CASE WHEN SCHEMA_NAME = CURRENT_SCHEMA() THEN 0 ELSE 1 END, SCHEMA_NAME`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []models.SchemaInfo
+	for rows.Next() {
+		var schema models.SchemaInfo
+		if err := rows.Scan(&schema.Name, &schema.Description); err != nil {
+			return nil, fmt.Errorf("failed to read Snowflake schema: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
+
+// snowflakeTableInfos lists the tables and views of a schema with the row counts
+// Snowflake keeps for each table
+func snowflakeTableInfos(ctx context.Context, db *sql.DB, schema string) ([]models.TableInfo, error) {
+	query := `
+		SELECT TABLE_NAME, TABLE_SCHEMA, TABLE_TYPE, ROW_COUNT
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = ` + snowflakeSchemaFilter + `
+		ORDER BY TABLE_TYPE, TABLE_NAME`
+
+	rows, err := db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tableInfos []models.TableInfo
+	for rows.Next() {
+		var info models.TableInfo
+		var rowCount sql.NullInt64
+		if err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &rowCount); err != nil {
+			return nil, fmt.Errorf("failed to read Snowflake table: %w", err)
+		}
+
+		switch {
+		case strings.HasSuffix(info.TableType, "VIEW"):
+			info.Description = "👁️ View"
+		case rowCount.Valid && rowCount.Int64 > 0:
+			info.RowCount = rowCount.Int64
+			info.Description = fmt.Sprintf(" Table • %d rows", info.RowCount)
+		default:
+			info.Description = " Table"
+		}
+		tableInfos = append(tableInfos, info)
+	}
+	return tableInfos, rows.Err()
+}
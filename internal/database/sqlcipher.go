@@ -0,0 +1,86 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLCipherKeyParam is the SQLite connection string parameter holding the key of
+// a SQLCipher-encrypted file. It is taken out of the string before the file is
+// opened and given to SQLCipher with PRAGMA key on every new connection.
+const SQLCipherKeyParam = "_key"
+
+// Errors opening a SQLite file with a SQLCipher key
+var (
+	ErrNoSQLCipher  = errors.New("this build's SQLite has no SQLCipher support, so the key cannot be used")
+	ErrSQLCipherKey = errors.New("the SQLCipher key is wrong, or the file is not a SQLCipher database")
+	errNoRows       = errors.New("no rows")
+)
+
+// SplitSQLCipherKey takes the SQLCipher key out of a SQLite connection string,
+// returning the string without it and the key, empty when there is none
+func SplitSQLCipherKey(dsn string) (string, string) {
+	path, query, ok := strings.Cut(dsn, "?")
+	if !ok {
+		return dsn, ""
+	}
+	var key string
+	var kept []string
+	for _, part := range strings.Split(query, "&") {
+		name, value, _ := strings.Cut(part, "=")
+		if name != SQLCipherKeyParam {
+			kept = append(kept, part)
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			key = unescaped
+		} else {
+			key = value
+		}
+	}
+	if len(kept) == 0 {
+		return path, key
+	}
+	return path + "?" + strings.Join(kept, "&"), key
+}
+
+// unlock gives a new connection the key of its SQLCipher file. PRAGMA key must
+// be the first statement on the connection; the file is only decrypted on the
+// next read, so the schema is read to find a wrong key before the connection is used.
+func unlock(conn *sqlite3.SQLiteConn, key string) error {
+	if _, err := conn.Exec("PRAGMA key = '"+strings.ReplaceAll(key, "'", "''")+"'", nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrSQLCipherKey, err)
+	}
+	// SQLite without SQLCipher ignores the key pragma and has no cipher_version
+	if err := queryOne(conn, "PRAGMA cipher_version"); errors.Is(err, errNoRows) {
+		return ErrNoSQLCipher
+	} else if err != nil {
+		return err
+	}
+	if err := queryOne(conn, "SELECT count(*) FROM sqlite_master"); err != nil {
+		return fmt.Errorf("%w: %v", ErrSQLCipherKey, err)
+	}
+	return nil
+}
+
+// queryOne runs a query on a connection and reads its first row
+func queryOne(conn *sqlite3.SQLiteConn, query string) error {
+	rows, err := conn.Query(query, nil)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	values := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(values); err == io.EOF {
+		return errNoRows
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
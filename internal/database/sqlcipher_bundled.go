@@ -0,0 +1,7 @@
+//go:build !libsqlite3
+
+package database
+
+// SQLCipherAvailable reports whether SQLite can be SQLCipher in this build. The
+// SQLite that go-sqlite3 bundles has no SQLCipher support, so a key is refused.
+const SQLCipherAvailable = false
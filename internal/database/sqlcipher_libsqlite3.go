@@ -0,0 +1,8 @@
+//go:build libsqlite3
+
+package database
+
+// SQLCipherAvailable reports whether SQLite can be SQLCipher in this build. With
+// the libsqlite3 tag go-sqlite3 links the system SQLite, which may be SQLCipher;
+// a key given to one that is not is still refused when the file is opened.
+const SQLCipherAvailable = true
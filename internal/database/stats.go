@@ -0,0 +1,330 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// overviewTableLimit caps how many tables are listed in the overview dashboard
+const overviewTableLimit = 20
+
+// GetDatabaseOverview collects size and connection statistics for the current database
+func GetDatabaseOverview(db *sql.DB, driver, schema string) (models.DatabaseOverview, error) {
+	var overview models.DatabaseOverview
+	var err error
+
+	switch driver {
+	case "postgres":
+		overview, err = getPostgresOverview(db, schema)
+	case "mysql", "mariadb":
+		overview, err = getMySQLOverview(db, schema)
+	case "sqlite3":
+		overview, err = getSQLiteOverview(db)
+	case "redshift":
+		overview, err = getRedshiftOverview(db, schema)
+	case "cockroach":
+		return models.DatabaseOverview{}, errCockroachStats
+	default:
+		return models.DatabaseOverview{}, fmt.Errorf("database statistics are not available for the %s driver", driver)
+	}
+	if err != nil {
+		return overview, err
+	}
+
+	overview.ConnectionsByDatabase = getConnectionsByDatabase(db, driver)
+
+	// SQLite has no catalog totals, so they are derived from the sizes of every
+	// table, read once and cut to the largest for the list
+	limit := overviewTableLimit
+	if driver == "sqlite3" {
+		limit = 0
+	}
+	overview.Tables, err = GetTableSizes(db, driver, schema, limit)
+	if err != nil {
+		return overview, fmt.Errorf("failed to get table sizes: %w", err)
+	}
+	if driver == "sqlite3" {
+		for _, t := range overview.Tables {
+			overview.DataBytes += t.DataBytes
+			overview.IndexBytes += t.IndexBytes
+		}
+		overview.Tables = overview.Tables[:min(len(overview.Tables), overviewTableLimit)]
+	}
+
+	return overview, nil
+}
+
+// GetTableSizes returns row counts and sizes for the tables in a schema, largest first.
+// A limit of zero returns every table.
+func GetTableSizes(db *sql.DB, driver, schema string, limit int) ([]models.TableSize, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	switch driver {
+	case "postgres":
+		if schema == "" {
+			schema = "public"
+		}
+		query := `
+			SELECT c.relname, n.nspname, COALESCE(s.n_live_tup, c.reltuples::bigint, 0),
+				pg_table_size(c.oid), pg_indexes_size(c.oid)
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+			WHERE c.relkind IN ('r', 'p', 'm') AND n.nspname = $1
+			ORDER BY pg_total_relation_size(c.oid) DESC`
+		if limit > 0 {
+			query += fmt.Sprintf(" LIMIT %d", limit)
+		}
+		return scanTableSizes(db.QueryContext(ctx, query, schema))
+	case "mysql", "mariadb":
+		// MariaDB reports system-versioned tables as their own type
+		tableTypes := "'BASE TABLE'"
+		if driver == "mariadb" {
+			tableTypes = "'BASE TABLE', 'SYSTEM VERSIONED'"
+		}
+		query := `
+			SELECT TABLE_NAME, TABLE_SCHEMA, COALESCE(TABLE_ROWS, 0),
+				COALESCE(DATA_LENGTH, 0), COALESCE(INDEX_LENGTH, 0)
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_SCHEMA = ` + mysqlSchemaFilter + ` AND TABLE_TYPE IN (` + tableTypes + `)
+			ORDER BY COALESCE(DATA_LENGTH, 0) + COALESCE(INDEX_LENGTH, 0) DESC`
+		if limit > 0 {
+			query += fmt.Sprintf(" LIMIT %d", limit)
+		}
+		return scanTableSizes(db.QueryContext(ctx, query, schema))
+	case "sqlite3":
+		return getSQLiteTableSizes(db, limit)
+	case "redshift":
+		if schema == "" {
+			schema = "public"
+		}
+		// size is counted in 1 MB blocks; Redshift has no indexes
+		query := fmt.Sprintf(`
+			SELECT "table", "schema", COALESCE(tbl_rows, 0)::bigint,
+				COALESCE(size, 0)::bigint * %d, 0
+			FROM svv_table_info
+			WHERE "schema" = $1
+			ORDER BY size DESC`, redshiftBlockBytes)
+		if limit > 0 {
+			query += fmt.Sprintf(" LIMIT %d", limit)
+		}
+		return scanTableSizes(db.QueryContext(ctx, query, schema))
+	case "cockroach":
+		return nil, errCockroachStats
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", driver)
+	}
+}
+
+// scanTableSizes reads (name, schema, rows, data bytes, index bytes) rows
+func scanTableSizes(rows *sql.Rows, err error) ([]models.TableSize, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []models.TableSize
+	for rows.Next() {
+		var t models.TableSize
+		if err := rows.Scan(&t.Name, &t.Schema, &t.RowCount, &t.DataBytes, &t.IndexBytes); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+func getPostgresOverview(db *sql.DB, schema string) (models.DatabaseOverview, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	if schema == "" {
+		schema = "public"
+	}
+	overview := models.DatabaseOverview{Connections: -1, MaxConnections: -1}
+
+	err := db.QueryRowContext(ctx, "SELECT current_database(), pg_database_size(current_database())").
+		Scan(&overview.Database, &overview.TotalBytes)
+	if err != nil {
+		return overview, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	err = db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(pg_table_size(c.oid)), 0), COALESCE(SUM(pg_indexes_size(c.oid)), 0)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p', 'm') AND n.nspname = $1`, schema).
+		Scan(&overview.DataBytes, &overview.IndexBytes)
+	if err != nil {
+		return overview, fmt.Errorf("failed to get schema size: %w", err)
+	}
+
+	// Connection statistics may be restricted for unprivileged roles.
+	// Count server-wide so the figure compares against max_connections.
+	var conns int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pg_stat_activity").Scan(&conns); err == nil {
+		overview.Connections = conns
+	}
+	var maxConns string
+	if err := db.QueryRowContext(ctx, "SHOW max_connections").Scan(&maxConns); err == nil {
+		if n, err := strconv.Atoi(maxConns); err == nil {
+			overview.MaxConnections = n
+		}
+	}
+
+	return overview, nil
+}
+
+func getRedshiftOverview(db *sql.DB, schema string) (models.DatabaseOverview, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	if schema == "" {
+		schema = "public"
+	}
+	overview := models.DatabaseOverview{Connections: -1, MaxConnections: -1}
+
+	// SVV_TABLE_INFO covers the current database and counts 1 MB blocks
+	var totalBlocks, schemaBlocks int64
+	err := db.QueryRowContext(ctx, `
+		SELECT current_database(), COALESCE(SUM(size), 0)::bigint,
+			COALESCE(SUM(CASE WHEN "schema" = $1 THEN size ELSE 0 END), 0)::bigint
+		FROM svv_table_info`, schema).
+		Scan(&overview.Database, &totalBlocks, &schemaBlocks)
+	if err != nil {
+		return overview, fmt.Errorf("failed to get database size: %w", err)
+	}
+	overview.TotalBytes = totalBlocks * redshiftBlockBytes
+	overview.DataBytes = schemaBlocks * redshiftBlockBytes
+
+	// Session counts need access to STV_SESSIONS, which may be restricted
+	var conns int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stv_sessions").Scan(&conns); err == nil {
+		overview.Connections = conns
+	}
+
+	return overview, nil
+}
+
+func getMySQLOverview(db *sql.DB, schema string) (models.DatabaseOverview, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	overview := models.DatabaseOverview{Connections: -1, MaxConnections: -1}
+
+	err := db.QueryRowContext(ctx, `
+		SELECT COALESCE(`+mysqlSchemaFilter+`, ''), COALESCE(SUM(DATA_LENGTH), 0), COALESCE(SUM(INDEX_LENGTH), 0)
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = `+mysqlSchemaFilter, schema, schema).
+		Scan(&overview.Database, &overview.DataBytes, &overview.IndexBytes)
+	if err != nil {
+		return overview, fmt.Errorf("failed to get database size: %w", err)
+	}
+	overview.TotalBytes = overview.DataBytes + overview.IndexBytes
+
+	var name, value string
+	if err := db.QueryRowContext(ctx, "SHOW STATUS LIKE 'Threads_connected'").Scan(&name, &value); err == nil {
+		if n, err := strconv.Atoi(value); err == nil {
+			overview.Connections = n
+		}
+	}
+	if err := db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'max_connections'").Scan(&name, &value); err == nil {
+		if n, err := strconv.Atoi(value); err == nil {
+			overview.MaxConnections = n
+		}
+	}
+
+	return overview, nil
+}
+
+func getSQLiteOverview(db *sql.DB) (models.DatabaseOverview, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	// SQLite is an embedded file, so server connection counts do not apply
+	overview := models.DatabaseOverview{Database: "main", Connections: -1, MaxConnections: -1}
+
+	var pageCount, pageSize int64
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return overview, fmt.Errorf("failed to get page count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return overview, fmt.Errorf("failed to get page size: %w", err)
+	}
+	overview.TotalBytes = pageCount * pageSize
+
+	return overview, nil
+}
+
+func getSQLiteTableSizes(db *sql.DB, limit int) ([]models.TableSize, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	// The dbstat virtual table is optional; sizes stay at zero when it is missing
+	sizes := map[string]int64{}
+	indexOwner := map[string]string{}
+	if statRows, err := db.QueryContext(ctx, "SELECT name, SUM(pgsize) FROM dbstat GROUP BY name"); err == nil {
+		for statRows.Next() {
+			var name string
+			var size int64
+			if statRows.Scan(&name, &size) == nil {
+				sizes[name] = size
+			}
+		}
+		statRows.Close()
+
+		if idxRows, err := db.QueryContext(ctx, "SELECT name, tbl_name FROM sqlite_master WHERE type = 'index'"); err == nil {
+			for idxRows.Next() {
+				var name, table string
+				if idxRows.Scan(&name, &table) == nil {
+					indexOwner[name] = table
+				}
+			}
+			idxRows.Close()
+		}
+	}
+
+	tables := make([]models.TableSize, 0, len(names))
+	for _, name := range names {
+		t := models.TableSize{Name: name, Schema: "main", DataBytes: sizes[name]}
+		for idx, owner := range indexOwner {
+			if owner == name {
+				t.IndexBytes += sizes[idx]
+			}
+		}
+		db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+QuoteIdentifier("sqlite3", name)).Scan(&t.RowCount)
+		tables = append(tables, t)
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].TotalBytes() != tables[j].TotalBytes() {
+			return tables[i].TotalBytes() > tables[j].TotalBytes()
+		}
+		return tables[i].RowCount > tables[j].RowCount
+	})
+	if limit > 0 && len(tables) > limit {
+		tables = tables[:limit]
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,391 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetTableInfos retrieves detailed table information including row counts
+func GetTableInfos(db *sql.DB, driver, schema string) ([]models.TableInfo, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	var tableInfos []models.TableInfo
+
+	switch driver {
+	case "postgres":
+		query := `
+			SELECT
+				table_name,
+				table_schema as schema_name,
+				table_type,
+				CASE
+					WHEN table_type = 'BASE TABLE' THEN COALESCE(s.n_tup_ins + s.n_tup_upd - s.n_tup_del, 0)
+					ELSE 0
+				END as estimated_rows
+			FROM information_schema.tables t
+			LEFT JOIN pg_stat_user_tables s ON t.table_name = s.relname AND t.table_schema = s.schemaname
+			WHERE t.table_schema = $1
+				AND t.table_type IN ('BASE TABLE', 'VIEW', 'FOREIGN')
+			ORDER BY t.table_type, t.table_name`
+
+		rows, err := db.QueryContext(ctx, query, schema)
+		if err != nil {
+			// Fallback to simple table list if stats are not available
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var info models.TableInfo
+			var estimatedRows sql.NullInt64
+			err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &estimatedRows)
+			if err != nil {
+				continue
+			}
+
+			// Determine the object type display name
+			var objectType string
+			var emoji string
+			if info.TableType == "VIEW" {
+				objectType = "view"
+				emoji = "👁️"
+			} else {
+				objectType = "table"
+				emoji = ""
+			}
+
+			if info.TableType == "BASE TABLE" && estimatedRows.Valid && estimatedRows.Int64 > 0 {
+				info.RowCount = estimatedRows.Int64
+				info.RowCount = max(info.RowCount, 0)
+				if info.Schema != "" && info.Schema != "public" {
+					info.Description = fmt.Sprintf("%s %s.%s • ~%d rows", emoji, info.Schema, objectType, info.RowCount)
+				} else {
+					info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
+				}
+			} else {
+				if info.Schema != "" && info.Schema != "public" {
+					info.Description = fmt.Sprintf("%s %s.%s", emoji, info.Schema, objectType)
+				} else {
+					info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+				}
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "mysql", "mariadb":
+		tableTypes := "'BASE TABLE', 'VIEW'"
+		if driver == "mariadb" {
+			tableTypes = mariadbTableTypes
+		}
+		query := `
+			SELECT
+				TABLE_NAME,
+				TABLE_SCHEMA,
+				TABLE_TYPE,
+				COALESCE(TABLE_ROWS, 0) as table_rows
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_SCHEMA = ` + mysqlSchemaFilter + `
+				AND TABLE_TYPE IN (` + tableTypes + `)
+			ORDER BY TABLE_TYPE, TABLE_NAME`
+
+		rows, err := db.QueryContext(ctx, query, schema)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var info models.TableInfo
+			var tableRows sql.NullInt64
+			err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &tableRows)
+			if err != nil {
+				continue
+			}
+
+			// Determine the object type display name
+			var objectType string
+			var emoji string
+			switch info.TableType {
+			case "VIEW":
+				objectType = "view"
+				emoji = "👁️"
+			case "SYSTEM VERSIONED":
+				objectType = "system-versioned table"
+				emoji = "🕰️"
+			case "SEQUENCE":
+				objectType = "sequence"
+				emoji = "🔢"
+			default:
+				objectType = "table"
+				emoji = ""
+			}
+
+			hasRows := info.TableType == "BASE TABLE" || info.TableType == "SYSTEM VERSIONED"
+			if hasRows && tableRows.Valid && tableRows.Int64 > 0 {
+				info.RowCount = tableRows.Int64
+				info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
+			} else {
+				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "redshift":
+		// Row counts come from SVV_TABLE_INFO, which only lists tables that hold data
+		query := `
+			SELECT t.table_name, t.table_schema, t.table_type, COALESCE(i.tbl_rows, 0)
+			FROM information_schema.tables t
+			LEFT JOIN svv_table_info i ON i."schema" = t.table_schema AND i."table" = t.table_name
+			WHERE t.table_schema = $1
+				AND t.table_type IN ('BASE TABLE', 'VIEW')
+			ORDER BY t.table_type, t.table_name`
+
+		rows, err := db.QueryContext(ctx, query, schema)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var info models.TableInfo
+			var tableRows sql.NullInt64
+			if err := rows.Scan(&info.Name, &info.Schema, &info.TableType, &tableRows); err != nil {
+				continue
+			}
+
+			objectType, emoji := "table", ""
+			if info.TableType == "VIEW" {
+				objectType, emoji = "view", "👁️"
+			}
+			if info.TableType == "BASE TABLE" && tableRows.Valid && tableRows.Int64 > 0 {
+				info.RowCount = tableRows.Int64
+				info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
+			} else {
+				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "cockroach":
+		// SHOW TABLES carries the row estimate that pg_stat_user_tables would give on PostgreSQL
+		query := "SELECT table_name, schema_name, type, COALESCE(estimated_row_count, 0) FROM " +
+			cockroachShowTables(schema) + " ORDER BY type != 'table', table_name"
+
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var info models.TableInfo
+			var objectType string
+			var estimatedRows sql.NullInt64
+			if err := rows.Scan(&info.Name, &info.Schema, &objectType, &estimatedRows); err != nil {
+				continue
+			}
+
+			var emoji string
+			switch objectType {
+			case "table":
+				info.TableType = "BASE TABLE"
+			case "sequence":
+				info.TableType = "SEQUENCE"
+				emoji = "🔢"
+			default: // view, materialized view
+				info.TableType = "VIEW"
+				emoji = "👁️"
+			}
+
+			if info.TableType == "BASE TABLE" && estimatedRows.Valid && estimatedRows.Int64 > 0 {
+				info.RowCount = estimatedRows.Int64
+				info.Description = fmt.Sprintf("%s %s • ~%d rows", emoji, strings.Title(objectType), info.RowCount)
+			} else {
+				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "clickhouse":
+		// total_rows is only known for engines that track it, such as MergeTree
+		query := `
+			SELECT name, database, engine, total_rows
+			FROM system.tables
+			WHERE database = ` + clickhouseSchemaFilter + `
+				AND NOT is_temporary
+			ORDER BY engine IN ('View', 'MaterializedView', 'LiveView'), name`
+
+		rows, err := db.QueryContext(ctx, query, schema)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var info models.TableInfo
+			var engine string
+			var totalRows sql.NullInt64
+			if err := rows.Scan(&info.Name, &info.Schema, &engine, &totalRows); err != nil {
+				continue
+			}
+
+			if strings.HasSuffix(engine, "View") {
+				info.TableType = "VIEW"
+				info.Description = fmt.Sprintf("👁️ View • %s", engine)
+			} else {
+				info.TableType = "BASE TABLE"
+				info.Description = fmt.Sprintf(" Table • %s", engine)
+				if totalRows.Valid && totalRows.Int64 > 0 {
+					info.RowCount = totalRows.Int64
+					info.Description = fmt.Sprintf(" Table • %s • %d rows", engine, info.RowCount)
+				}
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "trino":
+		// Row counts would scan the underlying sources, so only the type is shown
+		catalog, name := SplitTrinoSchema(schema)
+		query := `
+			SELECT table_name, table_type
+			FROM ` + trinoInformationSchema(catalog) + `.tables
+			WHERE table_schema = ` + trinoSchemaFilter + `
+			ORDER BY table_type, table_name`
+
+		rows, err := db.QueryContext(ctx, query, name)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			info := models.TableInfo{Schema: schema}
+			if err := rows.Scan(&info.Name, &info.TableType); err != nil {
+				continue
+			}
+			info.Description = " Table"
+			if info.TableType == "VIEW" {
+				info.Description = "👁️ View"
+			}
+			tableInfos = append(tableInfos, info)
+		}
+
+	case "snowflake":
+		infos, err := snowflakeTableInfos(ctx, db, schema)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		tableInfos = infos
+
+	case "bigquery":
+		infos, err := bigqueryTableInfos(ctx, db, schema)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		tableInfos = infos
+
+	case "sqlite3":
+		// SQLite: Get both tables and views from sqlite_master
+		query := `
+			SELECT name, type
+			FROM ` + sqliteMaster(schema) + `
+			WHERE type IN ('table', 'view')
+				AND name NOT LIKE 'sqlite_%'
+			ORDER BY type, name`
+
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return GetSimpleTableInfos(db, driver, schema)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name, objType string
+			err := rows.Scan(&name, &objType)
+			if err != nil {
+				continue
+			}
+
+			info := models.TableInfo{
+				Name:   name,
+				Schema: "main", // SQLite uses "main" as the default schema
+			}
+
+			// Determine the object type display name
+			var objectType string
+			var emoji string
+			if objType == "view" {
+				info.TableType = "VIEW"
+				objectType = "view"
+				emoji = "👁️"
+			} else {
+				info.TableType = "BASE TABLE"
+				objectType = "table"
+				emoji = ""
+			}
+
+			// Try to get row count for tables only (views don't have meaningful row counts)
+			if objType == "table" {
+				countQuery := "SELECT COUNT(*) FROM " + QuoteIdentifier(driver, name)
+				var count int64
+				err := db.QueryRowContext(ctx, countQuery).Scan(&count)
+				if err == nil {
+					info.RowCount = count
+					info.Description = fmt.Sprintf("%s %s • %d rows", emoji, strings.Title(objectType), count)
+				} else {
+					info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+				}
+			} else {
+				info.Description = fmt.Sprintf("%s %s", emoji, strings.Title(objectType))
+			}
+
+			tableInfos = append(tableInfos, info)
+		}
+
+	default:
+		return GetSimpleTableInfos(db, driver, schema)
+	}
+
+	return tableInfos, nil
+}
+
+// GetSimpleTableInfos provides a fallback for basic table information
+func GetSimpleTableInfos(db *sql.DB, driver, schema string) ([]models.TableInfo, error) {
+	tables, err := GetTables(db, driver, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableInfos []models.TableInfo
+	for _, tableName := range tables {
+		var schemaName string
+		switch driver {
+		case "postgres", "cockroach", "redshift":
+			schemaName = schema
+		case "mysql", "mariadb", "clickhouse", "trino", "snowflake", "bigquery":
+			schemaName = schema
+		case "sqlite3":
+			schemaName = "main"
+		default:
+			schemaName = ""
+		}
+
+		tableInfos = append(tableInfos, models.TableInfo{
+			Name:        tableName,
+			Schema:      schemaName,
+			TableType:   "BASE TABLE",
+			Description: "Table",
+		})
+	}
+
+	return tableInfos, nil
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TempTableSchema returns the schema a temporary table is read from. PostgreSQL
+// keeps temporary tables in the session's pg_temp schema; other drivers use the
+// schema being browsed.
+func TempTableSchema(driver, schema string) string {
+	if driver == "postgres" || driver == "cockroach" {
+		return "pg_temp"
+	}
+	return schema
+}
+
+// CreateTempTableAs materializes the rows of a SELECT into a new temporary table.
+// The table only exists on the connection that ran this statement.
+func CreateTempTableAs(db *sql.DB, driver, schema, table, query string) error {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+
+	var stmt string
+	switch driver {
+	case "postgres", "cockroach":
+		stmt = fmt.Sprintf("CREATE TEMP TABLE %s AS %s", QuoteIdentifier(driver, table), query)
+	case "mysql", "mariadb":
+		stmt = fmt.Sprintf("CREATE TEMPORARY TABLE %s AS %s", mysqlTableName(schema, table), query)
+	case "sqlite3":
+		stmt = fmt.Sprintf("CREATE TEMP TABLE %s AS %s", QuoteIdentifier(driver, table), query)
+	default:
+		return fmt.Errorf("unsupported driver: %s", driver)
+	}
+
+	_, err := db.ExecContext(ctx, stmt)
+	return err
+}
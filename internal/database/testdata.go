@@ -0,0 +1,417 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// GetColumnSpecs describes each column of a table for test data generation:
+// type, length limit, nullability, defaults, enum values, and key constraints
+func GetColumnSpecs(db *sql.DB, driver, tableName, schema string) ([]models.ColumnSpec, error) {
+	var specs []models.ColumnSpec
+	var err error
+
+	switch driver {
+	case "postgres", "cockroach":
+		specs, err = getPostgresColumnSpecs(db, tableName, schema)
+	case "mysql", "mariadb":
+		specs, err = getMySQLColumnSpecs(db, tableName, schema)
+	case "sqlite3":
+		specs, err = getSQLiteColumnSpecs(db, tableName, schema)
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKeys, foreignKeys, err := getKeyColumns(db, driver, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key columns: %w", err)
+	}
+	for i := range specs {
+		if primaryKeys[specs[i].Name] {
+			specs[i].IsPrimaryKey = true
+		}
+		if ref, ok := foreignKeys[specs[i].Name]; ok {
+			specs[i].References = &ref
+		}
+	}
+	return specs, nil
+}
+
+func getPostgresColumnSpecs(db *sql.DB, tableName, schema string) ([]models.ColumnSpec, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	if schema == "" {
+		schema = "public"
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name, data_type, udt_name, COALESCE(character_maximum_length, 0),
+			is_nullable, column_default IS NOT NULL, is_identity, is_generated,
+			COALESCE(column_default, '') LIKE 'nextval(%'
+		FROM information_schema.columns
+		WHERE table_name = $1 AND table_schema = $2
+		ORDER BY ordinal_position`, tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var specs []models.ColumnSpec
+	var enumTypes []string
+	for rows.Next() {
+		var spec models.ColumnSpec
+		var dataType, udtName, nullable, identity, generated string
+		var serial bool
+		if err := rows.Scan(&spec.Name, &dataType, &udtName, &spec.MaxLength, &nullable,
+			&spec.HasDefault, &identity, &generated, &serial); err != nil {
+			return nil, err
+		}
+		spec.DataType = dataType
+		if dataType == "USER-DEFINED" {
+			spec.DataType = udtName
+			enumTypes = append(enumTypes, udtName)
+		}
+		spec.Nullable = nullable == "YES"
+		spec.Generated = identity == "YES" || generated != "NEVER" || serial
+		specs = append(specs, spec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// User-defined types that are enums get their labels; others stay unsupported
+	for i := range specs {
+		for _, enumType := range enumTypes {
+			if specs[i].DataType != enumType {
+				continue
+			}
+			labels, err := getPostgresEnumLabels(db, enumType)
+			if err != nil {
+				return nil, err
+			}
+			specs[i].EnumValues = labels
+		}
+	}
+	return specs, nil
+}
+
+func getPostgresEnumLabels(db *sql.DB, typeName string) ([]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT e.enumlabel
+		FROM pg_enum e
+		JOIN pg_type t ON t.oid = e.enumtypid
+		WHERE t.typname = $1
+		ORDER BY e.enumsortorder`, typeName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+func getMySQLColumnSpecs(db *sql.DB, tableName, schema string) ([]models.ColumnSpec, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT COLUMN_NAME, COLUMN_TYPE, COALESCE(CHARACTER_MAXIMUM_LENGTH, 0),
+			IS_NULLABLE, COLUMN_DEFAULT IS NOT NULL, EXTRA
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_NAME = ? AND TABLE_SCHEMA = `+mysqlSchemaFilter+`
+		ORDER BY ORDINAL_POSITION`, tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var specs []models.ColumnSpec
+	for rows.Next() {
+		var spec models.ColumnSpec
+		var nullable, extra string
+		var maxLength int64
+		if err := rows.Scan(&spec.Name, &spec.DataType, &maxLength, &nullable, &spec.HasDefault, &extra); err != nil {
+			return nil, err
+		}
+		// LONGTEXT and friends report limits far beyond anything generated
+		if maxLength > 0 && maxLength < 1<<20 {
+			spec.MaxLength = int(maxLength)
+		}
+		spec.Nullable = nullable == "YES"
+		extra = strings.ToLower(extra)
+		spec.Generated = strings.Contains(extra, "auto_increment") || strings.Contains(extra, "generated")
+		spec.EnumValues = parseMySQLEnumValues(spec.DataType)
+		specs = append(specs, spec)
+	}
+	return specs, rows.Err()
+}
+
+// parseMySQLEnumValues extracts the labels from a column type like enum('a','b')
+func parseMySQLEnumValues(columnType string) []string {
+	lower := strings.ToLower(columnType)
+	if !strings.HasPrefix(lower, "enum(") && !strings.HasPrefix(lower, "set(") {
+		return nil
+	}
+	inner := columnType[strings.Index(columnType, "(")+1 : strings.LastIndex(columnType, ")")]
+	var values []string
+	for _, part := range strings.Split(inner, "','") {
+		part = strings.Trim(part, "'")
+		values = append(values, strings.ReplaceAll(part, "''", "'"))
+	}
+	return values
+}
+
+func getSQLiteColumnSpecs(db *sql.DB, tableName, schema string) ([]models.ColumnSpec, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, sqlitePragma(schema, "table_info", tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var specs []models.ColumnSpec
+	keyColumns := 0
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, dataType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		if pk > 0 {
+			keyColumns++
+		}
+		specs = append(specs, models.ColumnSpec{
+			Name:         name,
+			DataType:     dataType,
+			MaxLength:    parseTypeLength(dataType),
+			Nullable:     notNull == 0 && pk == 0,
+			HasDefault:   defaultValue.Valid,
+			IsPrimaryKey: pk > 0,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A single INTEGER PRIMARY KEY aliases the rowid and is assigned automatically
+	if keyColumns == 1 {
+		for i := range specs {
+			if specs[i].IsPrimaryKey && strings.EqualFold(specs[i].DataType, "INTEGER") {
+				specs[i].Generated = true
+			}
+		}
+	}
+	return specs, nil
+}
+
+// parseTypeLength reads the length from a declared type like VARCHAR(50)
+func parseTypeLength(dataType string) int {
+	open := strings.Index(dataType, "(")
+	end := strings.Index(dataType, ")")
+	if open < 0 || end < open {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(dataType[open+1 : end]))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// getKeyColumns returns the primary key columns and foreign key references of a table
+func getKeyColumns(db *sql.DB, driver, tableName, schema string) (map[string]bool, map[string]models.ForeignKeyRef, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	primaryKeys := map[string]bool{}
+	foreignKeys := map[string]models.ForeignKeyRef{}
+
+	switch driver {
+	case "postgres", "cockroach":
+		if schema == "" {
+			schema = "public"
+		}
+		rows, err := db.QueryContext(ctx, `
+			SELECT tc.constraint_type, kcu.column_name,
+				COALESCE(ccu.table_name, ''), COALESCE(ccu.column_name, '')
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON tc.constraint_name = kcu.constraint_name
+				AND tc.table_schema = kcu.table_schema
+				AND tc.table_name = kcu.table_name
+			LEFT JOIN information_schema.constraint_column_usage ccu
+				ON tc.constraint_type = 'FOREIGN KEY'
+				AND tc.constraint_name = ccu.constraint_name
+				AND tc.table_schema = ccu.table_schema
+			WHERE tc.table_name = $1 AND tc.table_schema = $2
+				AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')`, tableName, schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var constraintType, column, refTable, refColumn string
+			if err := rows.Scan(&constraintType, &column, &refTable, &refColumn); err != nil {
+				return nil, nil, err
+			}
+			if constraintType == "PRIMARY KEY" {
+				primaryKeys[column] = true
+			} else if _, seen := foreignKeys[column]; !seen && refTable != "" {
+				foreignKeys[column] = models.ForeignKeyRef{Table: refTable, Column: refColumn}
+			}
+		}
+		return primaryKeys, foreignKeys, rows.Err()
+
+	case "mysql", "mariadb":
+		rows, err := db.QueryContext(ctx, `
+			SELECT CONSTRAINT_NAME, COLUMN_NAME,
+				COALESCE(REFERENCED_TABLE_NAME, ''), COALESCE(REFERENCED_COLUMN_NAME, '')
+			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+			WHERE TABLE_NAME = ? AND TABLE_SCHEMA = `+mysqlSchemaFilter, tableName, schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var constraintName, column, refTable, refColumn string
+			if err := rows.Scan(&constraintName, &column, &refTable, &refColumn); err != nil {
+				return nil, nil, err
+			}
+			if constraintName == "PRIMARY" {
+				primaryKeys[column] = true
+			} else if refTable != "" {
+				foreignKeys[column] = models.ForeignKeyRef{Table: refTable, Column: refColumn}
+			}
+		}
+		return primaryKeys, foreignKeys, rows.Err()
+
+	case "sqlite3":
+		// Primary keys come from PRAGMA table_info in getSQLiteColumnSpecs
+		rows, err := db.QueryContext(ctx, sqlitePragma(schema, "foreign_key_list", tableName))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id, seq int
+			var table, from string
+			var to, onUpdate, onDelete, match sql.NullString
+			if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+				return nil, nil, err
+			}
+			// A foreign key without a target column references the parent's rowid
+			refColumn := to.String
+			if !to.Valid || refColumn == "" {
+				refColumn = "rowid"
+			}
+			foreignKeys[from] = models.ForeignKeyRef{Table: table, Column: refColumn}
+		}
+		return primaryKeys, foreignKeys, rows.Err()
+	}
+
+	return primaryKeys, foreignKeys, nil
+}
+
+// GetParentKeys samples existing values of a referenced column so generated
+// foreign keys point at real rows
+func GetParentKeys(db *sql.DB, driver, schema string, ref models.ForeignKeyRef, limit int) ([]string, error) {
+	ctx, cancel := StatementContext(db)
+	defer cancel()
+
+	column := QuoteIdentifier(driver, ref.Column)
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL LIMIT %d",
+		column, QualifiedTableName(driver, schema, ref.Table), column, limit)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var value interface{}
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		switch t := value.(type) {
+		case []byte:
+			keys = append(keys, string(t))
+		default:
+			keys = append(keys, fmt.Sprintf("%v", t))
+		}
+	}
+	return keys, rows.Err()
+}
+
+// BuildInsertSQL builds a parameterized INSERT for the given columns
+func BuildInsertSQL(driver, schema, tableName string, columns []string) string {
+	numbered := models.DriverCapabilities(driver).NumberedPlaceholders
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		if numbered {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		} else {
+			placeholders[i] = "?"
+		}
+	}
+
+	if len(columns) == 0 {
+		if driver == "mysql" || driver == "mariadb" {
+			return fmt.Sprintf("INSERT INTO %s () VALUES ()", QualifiedTableName(driver, schema, tableName))
+		}
+		return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", QualifiedTableName(driver, schema, tableName))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", QualifiedTableName(driver, schema, tableName),
+		strings.Join(QuoteIdentifiers(driver, columns), ", "), strings.Join(placeholders, ", "))
+}
+
+// InsertRows inserts every row in a single transaction; nothing is kept if any row fails
+func InsertRows(db *sql.DB, insertSQL string, rows [][]interface{}) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(insertSQL)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var inserted int64
+	for i, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		inserted++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
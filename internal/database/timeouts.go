@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// statementTimeouts holds the statement timeout of each open pool, so every
+// query the package runs on a pool is bounded by its connection's setting.
+// Close removes a pool's entry.
+var statementTimeouts sync.Map // *sql.DB → time.Duration
+
+// ConnectContext returns a context for opening a connection, bounded by
+// timeout unless it is 0
+func ConnectContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// SetStatementTimeout bounds each statement run on db; 0 means no limit
+func SetStatementTimeout(db *sql.DB, timeout time.Duration) {
+	statementTimeouts.Store(db, timeout)
+}
+
+// StatementTimeout returns the statement timeout of db, 0 when it has none
+func StatementTimeout(db *sql.DB) time.Duration {
+	if timeout, ok := statementTimeouts.Load(db); ok {
+		return timeout.(time.Duration)
+	}
+	return 0
+}
+
+// Close closes a pool and forgets its statement timeout
+func Close(db *sql.DB) error {
+	statementTimeouts.Delete(db)
+	return db.Close()
+}
+
+// StatementContext returns a context bounded by the statement timeout of db.
+// Callers cancel it once they have read all rows.
+func StatementContext(db *sql.DB) (context.Context, context.CancelFunc) {
+	if timeout := StatementTimeout(db); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// The MySQL driver only takes certificates through a tls.Config registered in
+// code, so MySQL-style connection strings carry them in these parameters and
+// the config is registered when the connection is opened.
+const (
+	mysqlTLSVerifyParam = "tls-verify" // full (default), ca, or none
+	mysqlTLSCAParam     = "tls-ca"
+	mysqlTLSCertParam   = "tls-cert"
+	mysqlTLSKeyParam    = "tls-key"
+)
+
+// Open opens a connection pool for the driver, first registering the TLS
+// certificates a MySQL-style connection string names and filling in a missing
+// MySQL password from ~/.my.cnf. SQLite pools keep the databases attached
+// during the session on every connection, a connection string marked by
+// ReadOnlySessionDSN opens read-only sessions, and one naming a Cloud SQL
+// instance connects through the Cloud SQL connector.
+func Open(driver, dsn string) (*sql.DB, error) {
+	if driver == "sqlite3" {
+		return openSQLite(dsn), nil
+	}
+	dsn, readOnly := splitReadOnlySession(dsn)
+	dsn, cloudSQL := splitCloudSQL(driver, dsn)
+	dsn, err := PrepareTLS(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	dsn, err = ApplyMySQLOptionFile(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cloudSQL.instance != "" {
+		connector, err := cloudSQLConnector(driver, dsn, cloudSQL)
+		if err != nil {
+			return nil, err
+		}
+		if readOnly {
+			connector = readOnlyConnector{Connector: connector, statement: readOnlySessionStatements[driver]}
+		}
+		return sql.OpenDB(connector), nil
+	}
+	if readOnly {
+		return openReadOnly(driver, dsn)
+	}
+	return sql.Open(driver, dsn)
+}
+
+// PrepareTLS registers a tls.Config for the tls-verify, tls-ca, tls-cert, and
+// tls-key parameters of a MySQL, MariaDB, or ClickHouse connection string and
+// replaces them with tls=<registered name>. Other connection strings, and ones
+// without those parameters, are returned unchanged.
+func PrepareTLS(driver, dsn string) (string, error) {
+	if !usesBackticks(driver) {
+		return dsn, nil
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	verify, ca, cert, key := cfg.Params[mysqlTLSVerifyParam], cfg.Params[mysqlTLSCAParam], cfg.Params[mysqlTLSCertParam], cfg.Params[mysqlTLSKeyParam]
+	if verify == "" && ca == "" && cert == "" && key == "" {
+		return dsn, nil
+	}
+
+	tlsConfig, err := buildTLSConfig(verify, ca, cert, key)
+	if err != nil {
+		return "", err
+	}
+	// The name depends only on the settings, so reconnecting re-reads the files under the same name
+	sum := sha256.Sum256([]byte(verify + "\x00" + ca + "\x00" + cert + "\x00" + key))
+	name := "mirador-" + hex.EncodeToString(sum[:6])
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+
+	for _, param := range []string{mysqlTLSVerifyParam, mysqlTLSCAParam, mysqlTLSCertParam, mysqlTLSKeyParam} {
+		delete(cfg.Params, param)
+	}
+	cfg.TLSConfig = name
+	return cfg.FormatDSN(), nil
+}
+
+// buildTLSConfig loads the CA and client certificate files for a connection
+func buildTLSConfig(verify, caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	switch verify {
+	case "", "full":
+		// Chain and host name are verified; the driver sets the server name from the host
+	case "ca":
+		// The chain is verified but not the host name, like sslmode=verify-ca
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyChain(tlsConfig.RootCAs)
+	case "none":
+		tlsConfig.InsecureSkipVerify = true
+	default:
+		return nil, fmt.Errorf("unknown %s value %q (use full, ca, or none)", mysqlTLSVerifyParam, verify)
+	}
+	return tlsConfig, nil
+}
+
+// verifyChain checks the server's certificate chain against roots (the system
+// roots when nil) without checking the host name
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server sent no certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err
+	}
+}
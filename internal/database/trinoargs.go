@@ -0,0 +1,90 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// interpolateTrinoArgs replaces each ? placeholder with its argument as a SQL
+// literal. Question marks in strings, quoted identifiers, and comments are left alone.
+func interpolateTrinoArgs(query string, args []driver.NamedValue) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(query); i++ {
+		if n := skippedLength(query[i:]); n > 0 {
+			b.WriteString(query[i : i+n])
+			i += n - 1
+			continue
+		}
+		if query[i] != '?' {
+			b.WriteByte(query[i])
+			continue
+		}
+		if next >= len(args) {
+			return "", fmt.Errorf("trino: the query has more placeholders than the %d arguments", len(args))
+		}
+		literal, err := trinoLiteral(args[next].Value)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(literal)
+		next++
+	}
+	if next != len(args) {
+		return "", fmt.Errorf("trino: the query has %d placeholders for %d arguments", next, len(args))
+	}
+	return b.String(), nil
+}
+
+// skippedLength returns the length of the quoted name, string, or comment the
+// text starts with, running to the end of the text when it is not closed, or 0
+func skippedLength(text string) int {
+	var start int
+	var closing string
+	switch {
+	case text[0] == '\'' || text[0] == '"':
+		start, closing = 1, text[:1]
+	case strings.HasPrefix(text, "--"):
+		start, closing = 2, "\n"
+	case strings.HasPrefix(text, "/*"):
+		start, closing = 2, "*/"
+	default:
+		return 0
+	}
+	end := strings.Index(text[start:], closing)
+	if end < 0 {
+		return len(text)
+	}
+	return start + end + len(closing)
+}
+
+// trinoLiteral writes a driver value as a Trino SQL literal
+func trinoLiteral(value driver.Value) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return fmt.Sprintf("DOUBLE '%s'", strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		return "TIMESTAMP '" + v.Format("2006-01-02 15:04:05.000000") + "'", nil
+	}
+	return "", fmt.Errorf("trino: cannot send a %T argument", value)
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FormatValue renders a scanned value as display text and reports whether it
+// was SQL NULL. NULL is shown as "NULL", empty text stays empty, and drivers
+// that return text as bytes are read as strings rather than byte lists.
+// Floats are never shown in scientific notation, and unsigned integers keep
+// every digit.
+func FormatValue(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case nil:
+		return "NULL", true
+	case []byte:
+		return string(t), false
+	case string:
+		return t, false
+	case bool:
+		return strconv.FormatBool(t), false
+	case int64:
+		return strconv.FormatInt(t, 10), false
+	case uint64:
+		return strconv.FormatUint(t, 10), false
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), false
+	case float32:
+		return strconv.FormatFloat(float64(t), 'f', -1, 32), false
+	default:
+		return fmt.Sprintf("%v", t), false
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// ColumnAggregate summarizes the numeric values of one column
+type ColumnAggregate struct {
+	Count int64 // Non-NULL values
+	Sum   string
+	Avg   string
+	Min   string
+	Max   string
+}
+
+// ColumnAggregateResult is returned when a column's aggregate over the whole
+// filtered table finishes loading
+type ColumnAggregateResult struct {
+	Key       string // Table, column, and filter the aggregate covers
+	Aggregate ColumnAggregate
+	Err       error
+}
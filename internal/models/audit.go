@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// AuditEntry records a single write statement executed against a database
+type AuditEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Driver       string    `json:"driver"`
+	Connection   string    `json:"connection"`
+	Kind         string    `json:"kind"`
+	Statement    string    `json:"statement"`
+	Args         []string  `json:"args,omitempty"`
+	RowsAffected int64     `json:"rows_affected"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// AuditLogResult is returned when the audit log finishes loading
+type AuditLogResult struct {
+	Entries []AuditEntry
+	Err     error
+}
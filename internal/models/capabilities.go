@@ -0,0 +1,78 @@
+package models
+
+// Capabilities records what a database driver can do. Query builders consult it
+// to pick syntax, and views and key handlers consult it so features a driver
+// lacks are shown dimmed and skipped instead of failing on the server.
+type Capabilities struct {
+	Schemas              bool // tables live in schemas (or MySQL databases) that can be switched
+	ILike                bool // case-insensitive ILIKE is available
+	Returning            bool // INSERT, UPDATE, and DELETE accept a RETURNING clause
+	TransactionalDDL     bool // schema changes can be rolled back inside a transaction
+	NumberedPlaceholders bool // bind parameters are $1, $2 rather than ?
+	Truncate             bool // TRUNCATE TABLE exists; otherwise an unqualified DELETE empties a table
+	TableDDL             bool // tables can be truncated and dropped from the tables list
+	TempTables           bool // CREATE TEMP TABLE ... AS is available on the session
+	SizeStats            bool // table and database sizes can be read, for the overview and growth
+	SlowQueries          bool // per-statement timing statistics can be read
+	ServerSettings       bool // the server configuration can be listed
+	TestData             bool // column specs for generating synthetic rows can be read
+	ExplainEstimates     bool // EXPLAIN reports row estimates the query runner's cost check can read
+	JSONPaths            bool // values inside JSON columns can be selected by path
+	ListenNotify         bool // channels can be LISTENed on for NOTIFY messages
+	RangeChecksums       bool // rows can be checksummed on the server, for table comparison
+}
+
+// driverCapabilities lists the capabilities of each supported driver
+var driverCapabilities = map[string]Capabilities{
+	"postgres": {
+		Schemas: true, ILike: true, Returning: true, TransactionalDDL: true, NumberedPlaceholders: true,
+		Truncate: true, TableDDL: true, TempTables: true, SizeStats: true, SlowQueries: true,
+		ServerSettings: true, TestData: true, ExplainEstimates: true, JSONPaths: true,
+		ListenNotify: true, RangeChecksums: true,
+	},
+	// CockroachDB has no size or activity statistics compatible with PostgreSQL's
+	"cockroach": {
+		Schemas: true, ILike: true, Returning: true, TransactionalDDL: true, NumberedPlaceholders: true,
+		Truncate: true, TableDDL: true, TempTables: true, ServerSettings: true, TestData: true,
+		ExplainEstimates: true, JSONPaths: true, RangeChecksums: true,
+	},
+	"redshift": {
+		Schemas: true, ILike: true, TransactionalDDL: true, NumberedPlaceholders: true,
+		Truncate: true, TableDDL: true, SizeStats: true, ExplainEstimates: true, JSONPaths: true,
+	},
+	// MySQL commits implicitly before and after every schema change
+	"mysql": {
+		Schemas: true, Truncate: true, TableDDL: true, TempTables: true, SizeStats: true,
+		SlowQueries: true, ServerSettings: true, TestData: true, ExplainEstimates: true, JSONPaths: true,
+		RangeChecksums: true,
+	},
+	"mariadb": {
+		Schemas: true, Truncate: true, TableDDL: true, TempTables: true, SizeStats: true,
+		SlowQueries: true, ServerSettings: true, TestData: true, ExplainEstimates: true, JSONPaths: true,
+		RangeChecksums: true,
+	},
+	"sqlite3": {
+		Schemas: true, Returning: true, TransactionalDDL: true, TableDDL: true, TempTables: true, SizeStats: true,
+		ServerSettings: true, TestData: true, JSONPaths: true,
+	},
+	// ClickHouse is reached through its MySQL interface, so it binds with ?
+	"clickhouse": {
+		Schemas: true, ILike: true,
+	},
+	// Trino's schemas are listed across catalogs; its connectors decide what else works
+	"trino": {
+		Schemas: true,
+	},
+	// BigQuery connections are read-only, so only browsing applies
+	"bigquery": {
+		Schemas: true,
+	},
+	"snowflake": {
+		Schemas: true, ILike: true, Truncate: true, TableDDL: true,
+	},
+}
+
+// DriverCapabilities returns what the driver can do; unknown drivers can do nothing optional
+func DriverCapabilities(driver string) Capabilities {
+	return driverCapabilities[driver]
+}
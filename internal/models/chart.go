@@ -0,0 +1,14 @@
+package models
+
+// ChartPoint is one time bucket of a time-series chart
+type ChartPoint struct {
+	Bucket string  // Start of the bucket, e.g. 2024-05-01
+	Value  float64 // Aggregate of the bucket's values
+	Null   bool    // The bucket has rows but no values to aggregate
+}
+
+// TimeSeriesResult is returned when the buckets of a time-series chart finish loading
+type TimeSeriesResult struct {
+	Points []ChartPoint
+	Err    error
+}
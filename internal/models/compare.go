@@ -0,0 +1,34 @@
+package models
+
+// CompareStep tracks where the table comparison flow is
+type CompareStep int
+
+const (
+	CompareChooseTarget CompareStep = iota // Pick the connection holding the other table
+	CompareEnterTable                      // Name the other table
+	CompareReport                          // Comparison running or finished
+)
+
+// TableComparison is the outcome of checksumming two tables by primary key
+type TableComparison struct {
+	SourceTable     string
+	TargetTable     string
+	TargetName      string   // Connection the target table lives on
+	KeyColumns      []string // Primary key the rows are matched by
+	Ranges          int      // Key ranges checksummed
+	DifferingRanges int      // Ranges whose checksums did not match
+	SourceRows      int
+	TargetRows      int
+	MissingInTarget []string // Keys only in the source table
+	MissingInSource []string // Keys only in the target table
+	Differing       []string // Keys present in both with different values
+	MissingTargetN  int      // Full counts; the key lists above are capped
+	MissingSourceN  int
+	DifferingN      int
+}
+
+// CompareResult is returned when a table comparison finishes
+type CompareResult struct {
+	Comparison TableComparison
+	Err        error
+}
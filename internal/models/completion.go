@@ -0,0 +1,10 @@
+package models
+
+// CompletionColumnsResult carries the column names of a table fetched for the
+// query editor's completion
+type CompletionColumnsResult struct {
+	Schema  string
+	Table   string
+	Columns []string
+	Err     error
+}
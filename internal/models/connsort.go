@@ -0,0 +1,11 @@
+package models
+
+// Orders of the saved connections list
+const (
+	ConnectionSortSaved  = ""       // The order they were saved in
+	ConnectionSortRecent = "recent" // Most recently used first
+	ConnectionSortName   = "name"   // Alphabetical
+)
+
+// ConnectionSorts lists the orders in the order o cycles through them
+var ConnectionSorts = []string{ConnectionSortSaved, ConnectionSortRecent, ConnectionSortName}
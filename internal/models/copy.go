@@ -0,0 +1,20 @@
+package models
+
+// CopyStep tracks where the copy table data flow is
+type CopyStep int
+
+const (
+	CopyChooseTarget CopyStep = iota // Pick the saved connection to copy into
+	CopyEnterTable                   // Name the target table
+	CopyRunning                      // Rows are streaming (or finished)
+)
+
+// CopyProgressMsg reports progress of a running table copy
+type CopyProgressMsg struct {
+	Copied int64
+	Failed int64
+	Total  int64    // Source row count, or -1 when unknown
+	Errors []string // One entry per failed batch
+	Done   bool
+	Err    error // Fatal error that stopped the copy
+}
@@ -3,6 +3,13 @@ package models
 // SupportedDatabaseTypes represents the supported database types in DBX
 var SupportedDatabaseTypes = []DBType{
 	{Name: "PostgreSQL", Driver: "postgres"},
+	{Name: "CockroachDB", Driver: "cockroach"},
+	{Name: "Amazon Redshift", Driver: "redshift"},
 	{Name: "MySQL", Driver: "mysql"},
+	{Name: "MariaDB", Driver: "mariadb"},
 	{Name: "SQLite", Driver: "sqlite3"},
+	{Name: "ClickHouse", Driver: "clickhouse"},
+	{Name: "Trino", Driver: "trino"},
+	{Name: "Snowflake", Driver: "snowflake"},
+	{Name: "BigQuery", Driver: "bigquery"},
 }
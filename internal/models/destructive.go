@@ -0,0 +1,9 @@
+package models
+
+// DestructiveResult is returned when a TRUNCATE or DROP TABLE action completes
+type DestructiveResult struct {
+	Action string
+	Table  string
+	Schema string
+	Err    error
+}
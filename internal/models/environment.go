@@ -0,0 +1,12 @@
+package models
+
+// Environments a saved connection can be labelled with
+const (
+	EnvironmentNone        = ""
+	EnvironmentProduction  = "production"
+	EnvironmentStaging     = "staging"
+	EnvironmentDevelopment = "dev"
+)
+
+// Environments lists the labels in the order e cycles through them
+var Environments = []string{EnvironmentNone, EnvironmentProduction, EnvironmentStaging, EnvironmentDevelopment}
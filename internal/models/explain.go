@@ -0,0 +1,12 @@
+package models
+
+// QueryEstimateResult is returned when the planner's estimate for a query is read
+// before the query runs
+type QueryEstimateResult struct {
+	Query   string  // The query that was explained, run once the estimate is accepted
+	Rows    float64 // Estimated rows read
+	Cost    float64 // Estimated total planner cost, when HasCost
+	HasCost bool    // Whether the database reports a planner cost (PostgreSQL and Redshift)
+	Found   bool    // Whether the plan held an estimate at all
+	Err     error
+}
@@ -0,0 +1,16 @@
+package models
+
+// GroupCount is one value of a column in a group-by summary
+type GroupCount struct {
+	Value     string
+	IsNull    bool
+	Count     int64
+	Aggregate string // Second aggregate over the group's rows, when one is chosen
+}
+
+// GroupSummaryResult is returned when a group-by summary finishes loading
+type GroupSummaryResult struct {
+	Groups []GroupCount // Most common values first
+	Total  int64        // Rows in the table, for percentages
+	Err    error
+}
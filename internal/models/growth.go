@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// SnapshotTable records the size of a single table at snapshot time
+type SnapshotTable struct {
+	Name     string `json:"name"`
+	RowCount int64  `json:"row_count"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// TableSnapshot is a point-in-time record of table sizes for one connection
+type TableSnapshot struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Tables    []SnapshotTable `json:"tables"`
+}
+
+// TableGrowth describes how a table changed since the baseline snapshot
+type TableGrowth struct {
+	Name      string
+	RowCount  int64
+	RowDelta  int64
+	Bytes     int64
+	ByteDelta int64
+	IsNew     bool
+}
+
+// TableGrowthResult is returned when table growth statistics finish loading
+type TableGrowthResult struct {
+	Growth      []TableGrowth
+	Since       time.Time
+	HasBaseline bool
+	Err         error
+}
+
+// TableSnapshotTickMsg triggers a periodic background snapshot for a connection session
+type TableSnapshotTickMsg struct {
+	Session int
+}
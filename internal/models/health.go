@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ConnectionHealth is the outcome of pinging one saved connection
+type ConnectionHealth struct {
+	Name    string
+	Driver  string
+	Latency time.Duration
+	Err     error
+}
+
+// ConnectionHealthResult is returned when every saved connection has been pinged
+type ConnectionHealthResult struct {
+	Health    []ConnectionHealth
+	CheckedAt time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+// JSONColumn is a virtual preview column holding the value at a path inside a
+// JSON column, e.g. payload->>'status'. It is read by the preview's SELECT and
+// is never written back.
+type JSONColumn struct {
+	Name   string         // Header of the column in the preview
+	Column string         // JSON column the value is read from
+	Path   []JSONPathStep // Steps from the outside in
+}
+
+// JSONPathStep is one step of a JSON path: an object key or an array index
+type JSONPathStep struct {
+	Key   string
+	Index int
+	Array bool // The step is Index into an array rather than Key
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Message types for Bubble Tea
+type ConnectResult struct {
+	DB          *sql.DB
+	Driver      string
+	Err         error
+	Tables      []string
+	Foreign     map[string]string // Foreign table name to its server
+	Persistence map[string]string // Non-durable table name to its persistence kind
+	Schema      string
+}
+
+// ReplicaConnectResult is returned when the read replica of a saved connection is connected
+type ReplicaConnectResult struct {
+	DB  *sql.DB
+	Err error
+}
+
+type TestConnectionResult struct {
+	Success bool
+	Err     error
+}
+
+type SchemasResult struct {
+	Schemas []SchemaInfo
+	Err     error
+}
+
+type TablesResult struct {
+	Tables      []string
+	Foreign     map[string]string // Foreign table name to its server
+	Persistence map[string]string // Non-durable table name to its persistence kind
+	Schema      string
+	Err         error
+}
+
+type ColumnsResult struct {
+	Columns [][]string
+	Err     error
+}
+
+type QueryResult struct {
+	Columns  []string
+	Rows     [][]string
+	Err      error
+	RowCount int
+}
+
+// FilterCountTickMsg fires once typing in the filter prompt pauses
+type FilterCountTickMsg struct {
+	Seq int
+}
+
+// FilterCountResult carries the number of rows matching a filter being typed
+type FilterCountResult struct {
+	Seq    int
+	Filter string
+	Count  int
+	Err    error
+}
+
+type DataPreviewResult struct {
+	Columns        []string
+	ColumnTypes    []string // Database type of each column, as reported by the driver
+	Rows           [][]string
+	Nulls          [][]bool // Which cells in Rows were NULL
+	Err            error
+	TotalRows      int
+	TableRows      int  // Rows in the whole table, ignoring any filter
+	TableRowsKnown bool // Whether TableRows was counted for this result
+}
+
+type IndexesResult struct {
+	Indexes [][]string
+	Err     error
+}
+
+type RelationshipsResult struct {
+	Relationships [][]string
+	Err           error
+}
+
+type QueryResultMsg struct {
+	Query       string // Text that was run, for the query history
+	Result      string
+	Columns     []string
+	ColumnTypes []string // Database type of each column, as reported by the driver
+	Rows        [][]string
+	Nulls       [][]bool // Which cells of Rows were SQL NULL rather than the text "NULL"
+	Partial     bool     // The statement timed out; Rows holds what arrived before it was cancelled
+	Err         error
+
+	RowsAffected int64             // Rows changed by a statement that returns no rows
+	Statements   []StatementResult // Every statement's result when a script had more than one
+}
+
+// StatementResult is the outcome of one statement of a multi-statement script.
+// Statements after a failing one are not run and are marked Skipped.
+type StatementResult struct {
+	Query    string
+	Duration time.Duration
+	Result   QueryResultMsg
+	Skipped  bool
+}
+
+// StatementCancelResult reports a request to cancel the query runner's running statement
+type StatementCancelResult struct {
+	Err error
+}
+
+// HookResult reports a hook command that ran for an event
+type HookResult struct {
+	Event string
+	Err   error
+}
+
+// TempResultMsg reports a query result materialized into a temporary table
+type TempResultMsg struct {
+	Table  string
+	Schema string
+	Err    error
+}
+
+type ClearResultMsg struct{}
+
+type ClearErrorMsg struct{}
+
+type ErrorTimeoutMsg struct{}
+
+type ExportResult struct {
+	Success  bool
+	Err      error
+	Filename string
+	Format   string
+	Rows     int
+}
+
+type TestAndSaveResult struct {
+	Success bool
+	Err     error
+	DB      *sql.DB
+	Driver  string
+	Tables  []string
+	Schema  string
+}
+
+type FieldValueResult struct {
+	Value string
+	Err   error
+}
+
+type ClipboardResult struct {
+	Success bool
+	Err     error
+}
+
+type RowRefreshResult struct {
+	Columns []string
+	Row     []string // nil when the row no longer exists
+	Nulls   []bool   // Which values in Row were NULL
+	Err     error
+}
+
+type FieldUpdateResult struct {
+	Success  bool
+	Err      error
+	ExitEdit bool
+	NewValue string
+}
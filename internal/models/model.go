@@ -1,11 +1,498 @@
 package models
 
 import (
+	"database/sql"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// Main model
+type Model struct {
+	Version              string
+	State                ViewState
+	DBTypeList           list.Model
+	SavedConnectionsList list.Model
+	TextInput            textinput.Model
+	NameInput            textinput.Model
+	QueryInput           textarea.Model
+	TablesList           list.Model
+	ColumnsTable         table.Model
+	QueryResultsTable    table.Model
+	DataPreviewTable     table.Model
+	IndexesTable         table.Model
+	RelationshipsTable   table.Model
+	SelectedDB           DBType
+	ConnectionStr        string
+	DB                   *sql.DB
+	Err                  error
+	ErrorTimeout         *time.Time // When to clear the error (nil means no timeout)
+	Tables               []string
+	TableInfos           []TableInfo
+	SelectedTable        string
+	Schemas              []SchemaInfo
+	SelectedSchema       string
+	SchemasList          list.Model
+	IsLoadingSchemas     bool
+	SavedConnections     []SavedConnection
+	EditingConnectionIdx int
+	QueryResult          string
+	Width                int
+	Height               int
+
+	// Loading states
+	IsTestingConnection bool
+	IsConnecting        bool
+	IsSavingConnection  bool
+	IsLoadingTables     bool
+	IsLoadingColumns    bool
+	IsExecutingQuery    bool
+	IsLoadingPreview    bool
+
+	// Export states
+	IsExporting        bool
+	LastQueryColumns   []string
+	LastQueryRows      [][]string
+	LastQueryNulls     [][]bool
+	LastPreviewColumns []string
+	LastPreviewRows    [][]string
+
+	// Spinner for animations
+	Spinner spinner.Model
+
+	// Search functionality
+	SearchInput        textinput.Model
+	IsSearchingTables  bool
+	IsSearchingColumns bool
+	IsSearchingFields  bool // Row detail field search, by name or value
+	OriginalTableItems []list.Item
+	OriginalTableRows  []table.Row
+	SearchTerm         string
+
+	// Query history functionality
+	QueryHistory     []QueryHistoryEntry
+	QueryHistoryList list.Model
+	QueryRecallDepth int    // History entry recalled in the editor with ctrl+↑, 0 while editing the draft
+	QueryRecallDraft string // Editor text from before recall started
+
+	// Completion popup of the query editor: candidates for the word before the
+	// cursor, and the column names fetched for it by schema-qualified table,
+	// kept until the next connection (nil while a fetch is running)
+	CompletionActive  bool
+	Completions       []string
+	CompletionIndex   int
+	CompletionColumns map[string][]string
+	IsViewingHistory  bool
+
+	// Row detail functionality
+	SelectedRowData        []string
+	SelectedRowNulls       []bool // Which values in SelectedRowData were NULL
+	SelectedRowIndex       int
+	RowDetailList          list.Model
+	RowDetailPaginator     paginator.Model
+	SelectedFieldForDetail string
+	IsViewingFieldDetail   bool
+	IsRefreshingRow        bool
+	RowRefreshedAt         time.Time // When the selected row was last re-fetched
+	RowDetailPageStep      int       // Page being loaded by n/p in row detail: 1 next, -1 previous
+
+	// Full text view pagination
+	FullTextCurrentPage   int
+	FullTextItemsPerPage  int
+	FullTextSelectedField int
+
+	// Individual field detail view
+	SelectedFieldName           string
+	SelectedFieldValue          string
+	SelectedFieldIndex          int
+	FieldDetailScrollOffset     int
+	FieldDetailHorizontalOffset int
+	FieldDetailLinesPerPage     int
+	FieldDetailCharsPerLine     int
+
+	// Field editing
+	FieldTextarea      textarea.Model
+	IsEditingField     bool
+	OriginalFieldValue string
+	EditingFieldName   string
+	EditingFieldIndex  int
+
+	// Index detail view
+	SelectedIndexName       string
+	SelectedIndexType       string
+	SelectedIndexColumns    string
+	SelectedIndexDefinition string
+
+	// Data preview pagination
+	DataPreviewCurrentPage  int
+	DataPreviewItemsPerPage int
+	DataPreviewTotalRows    int
+
+	// Data preview horizontal scrolling
+	DataPreviewScrollOffset int        // Current column offset
+	DataPreviewVisibleCols  int        // Number of columns visible at once
+	DataPreviewAllColumns   []string   // Store all column names
+	DataPreviewColumnTypes  []string   // Database type of each column, parallel to DataPreviewAllColumns
+	DataPreviewAllRows      [][]string // Store all row data
+	DataPreviewNulls        [][]bool   // Which cells in DataPreviewAllRows were NULL
+
+	// Data preview filtering
+	DataPreviewFilterActive bool            // Whether filter mode is active
+	DataPreviewFilterValue  string          // Current filter text
+	DataPreviewFilterInput  textinput.Model // Filter input field
+
+	// Live match count shown while the filter is typed
+	FilterCountSeq   int    // Bumped per keystroke so only the latest debounce tick counts
+	FilterCountValue string // Filter text the count belongs to
+	FilterCount      int
+	FilterCountErr   error
+	IsCountingFilter bool
+
+	// Data preview sorting
+	DataPreviewSortColumn    string        // Column to sort by
+	DataPreviewSortDirection SortDirection // Current sort direction
+	DataPreviewSortMode      bool          // Whether in column selection mode for sorting
+	DataPreviewThenBy        []SortKey     // Secondary sort keys applied after the sort column
+
+	// JSON path columns added to the data preview's SELECT
+	JSONColumns      map[string][]JSONColumn // By table, kept until disconnect
+	JSONColumnActive bool                    // Whether the JSON path prompt is open
+	JSONColumnInput  textinput.Model
+
+	// Data preview columns shown as humanized durations ("2h 13m"), by table and
+	// then column, each with the unit its values are read in; kept until disconnect
+	DurationColumns map[string]map[string]string
+
+	// Database overview dashboard
+	DatabaseOverview  DatabaseOverview
+	OverviewTable     table.Model
+	IsLoadingOverview bool
+
+	// Table maintenance actions (VACUUM/ANALYZE/OPTIMIZE)
+	MaintenanceAction       string    // Selected action, e.g. VACUUM
+	MaintenanceTable        string    // Table the action targets
+	MaintenanceSchema       string    // Schema of the targeted table
+	IsConfirmingMaintenance bool      // Whether the action awaits y/n confirmation
+	IsRunningMaintenance    bool      // Whether an action is in progress
+	MaintenanceStartedAt    time.Time // When the running action started
+
+	// Slow query log browser
+	SlowQueries          []SlowQuery
+	SlowQueryTable       table.Model
+	SlowQuerySortByMean  bool // Order by mean time instead of total time
+	IsLoadingSlowQueries bool
+
+	// Server configuration browser
+	ServerSettings            []ServerSetting
+	FilteredServerSettings    []ServerSetting
+	ServerSettingsTable       table.Model
+	ServerSettingsOnlyChanged bool // Show only settings that differ from defaults
+	IsSearchingSettings       bool
+	IsLoadingSettings         bool
+
+	// Table growth tracking
+	TableGrowth       []TableGrowth
+	TableGrowthTable  table.Model
+	GrowthSince       time.Time
+	HasGrowthBaseline bool
+	IsLoadingGrowth   bool
+	SnapshotSession   int
+
+	// Write audit log
+	AuditEntries   []AuditEntry
+	AuditTable     table.Model
+	IsLoadingAudit bool
+
+	// Session safe mode: writes need an explicit per-statement override
+	SafeMode                 bool
+	IsConfirmingSafeOverride bool
+
+	// Read-only mode, set from the connected saved connection: writes are blocked, not just confirmed.
+	// A read-only SQLite file is also opened with mode=ro, and with immutable=1 when Immutable is set.
+	ReadOnly  bool
+	Immutable bool
+
+	// Environment label of the connected saved connection; production writes are confirmed
+	Environment string
+
+	// Saved connection being connected or connected to, whose use is recorded on
+	// connect; "" for a connection typed in the form without a name
+	ConnectionName string
+
+	// Dropped connection recovery
+	ConnectionLost   bool
+	IsReconnecting   bool
+	PendingRetry     RetryOperation
+	ConnectionError  error
+	ReconnectAttempt int       // Automatic reconnects tried since the connection dropped
+	ReconnectAt      time.Time // When the next automatic reconnect starts; zero when none is scheduled
+	ReconnectSession int       // Bumped when a countdown is replaced so its ticks are ignored
+
+	// Liveness of the open connection from periodic pings, shown in the tables header
+	SessionHealth SessionHealth
+	PingLatency   time.Duration // Round trip of the last successful ping
+	PingError     error         // Why the last ping failed
+	PingedAt      time.Time
+	PingSeq       int // Bumped when pings restart so ticks of earlier connections are ignored
+	IsPinging     bool
+
+	// Autosave of the query editor, restored when the connection is opened again
+	WorksheetSession  int       // Bumped on connect so ticks of earlier connections are ignored
+	WorksheetSaved    string    // Editor text as last saved or restored
+	WorksheetRestored time.Time // When the restored worksheet was saved; zero once it is edited
+
+	// Session display timezone for timestamp values (nil shows values as stored)
+	DisplayTimezone *time.Location
+
+	// Session toggle for thousands separators in numeric columns
+	GroupNumberDigits bool
+
+	// Session toggle for the NULL and distinct counts of the loaded page above the preview's headers
+	DataPreviewShowSummary bool
+
+	// Sum, average, minimum, and maximum of the selected numeric column over the
+	// whole filtered table, computed on request
+	FullAggregate      ColumnAggregate
+	FullAggregateKey   string // Table, column, and filter FullAggregate covers
+	IsLoadingAggregate bool
+
+	// Bulk UPDATE drafted from the preview filter
+	HasDraftedUpdate  bool
+	DraftedUpdateRows int // Rows matched by the filter when the draft was made
+
+	// Generated test data inserter
+	TestDataPlan        TestDataPlan
+	TestDataCountInput  textinput.Model
+	IsLoadingTestData   bool
+	IsInsertingTestData bool
+
+	// Guarded TRUNCATE/DROP TABLE that require typing the table name
+	DestructiveAction       string // TRUNCATE or DROP
+	DestructiveTable        string
+	DestructiveConfirmInput textinput.Model
+	IsConfirmingDestructive bool
+	IsRunningDestructive    bool
+
+	// Copy table data into a table on another saved connection
+	CopySourceTable  string
+	CopyStep         CopyStep
+	CopyTargetIndex  int
+	CopyTableInput   textinput.Model
+	CopyProgress     CopyProgressMsg
+	CopyProgressChan <-chan CopyProgressMsg
+	IsCopyingData    bool
+
+	// Checksum comparison of two tables by primary key
+	CompareSourceTable string
+	CompareStep        CompareStep
+	CompareTargetIndex int // 0 is the current connection, then saved connections
+	CompareTableInput  textinput.Model
+	Comparison         TableComparison
+	IsComparingTables  bool
+
+	// Recording of executed writes into a migration file; empty when not recording
+	MigrationFile string
+
+	// Starred tables of the current connection and schema, listed first
+	FavoriteTables map[string]bool
+
+	// Free-text notes on tables (by name, for the current connection and schema) and saved connections
+	TableNotes         map[string]string
+	NoteInput          textinput.Model
+	NoteTable          string // Table being annotated; empty when editing a connection note
+	NoteConnectionName string
+
+	// Rows in the previewed table ignoring the filter, as last counted
+	DataPreviewTableRows int
+
+	// ColumnsView definitions, searched with SearchInput and sorted by one field
+	ColumnDefinitions    [][]string
+	FilteredColumnCount  int
+	ColumnsSortField     int
+	ColumnsSortDirection SortDirection
+
+	// Query results materialized into temporary tables for the data preview
+	TempResultTable        string // Temporary table being previewed, empty otherwise
+	TempResultCount        int
+	TempResultReturnSchema string // Schema browsed before the preview switched to the temporary table
+	IsMaterializingResult  bool
+
+	// Cost check: SELECTs are explained first and large estimates asked about before running
+	CostCheckEnabled bool
+	IsCheckingCost   bool
+	IsConfirmingCost bool
+	CostWarning      string // The estimate that needs confirming, e.g. "Estimated to read 2,500,000 rows"
+	CostCheckQuery   string // The query waiting on the confirmation
+
+	// Automatic connect retries after transient failures
+	ConnectAttempt      int       // Retries made for the current connect
+	ConnectRetryAt      time.Time // When the next retry starts; zero when none is scheduled
+	ConnectRetrySession int       // Bumped on cancel so stale countdown ticks are ignored
+	IsWakingDatabase    bool      // Polling a paused serverless database until it resumes
+	WakeStartedAt       time.Time
+
+	// Query runner results
+	QueryResultPartial     bool              // The last SELECT timed out; the results hold the rows received before it was cancelled
+	QueryResultColumnTypes []string          // Database type of each result column
+	QueryStatements        []StatementResult // Per-statement results of the last multi-statement script
+	QueryStatementIndex    int               // Statement whose result is shown
+	RunningQuery           *RunningQuery     // The query in flight, cancelled with esc or ctrl+c
+	IsCancellingStatement  bool              // A cancel of the running statement was sent
+	QueryResultTransposed  bool              // Result columns are shown as rows
+	QueryResultColumn      int               // Result column the footer aggregates
+
+	// Values of the query's bind placeholders, asked for one at a time before it runs
+	IsBindingParams bool
+	BindParams      []string // Placeholders of the query being bound: $1, ?1, or :name
+	BindIndex       int      // Placeholder whose value is being typed
+	BindInput       textinput.Model
+	BindValues      map[string]string // Last value typed for each placeholder, offered again next time
+
+	// Foreign table whose preview waits for a second enter, since it queries the remote server
+	ForeignPreviewTable string
+
+	// Reachability board of all saved connections
+	ConnectionHealth      []ConnectionHealth
+	ConnectionHealthTable table.Model
+	HealthCheckedAt       time.Time
+	IsCheckingHealth      bool
+
+	// LISTEN/NOTIFY monitor of the active PostgreSQL connection
+	NotifyChannelInput textinput.Model
+	NotifyChannels     []string
+	Notifications      []Notification
+	NotifyTable        table.Model
+	NotifyStream       <-chan NotificationMsg
+	NotifyStop         chan struct{} // Closed to stop the running monitor
+	NotifySeq          int           // Bumped when the monitor stops so its pending messages are ignored
+	NotifyErr          error         // Last connection error of the monitor, cleared on reconnect
+	IsListening        bool
+
+	// Prior versions of the row open in the row detail view, shown side by side
+	RowHistory          RowHistoryResult
+	RowHistoryTable     table.Model
+	RowHistoryOffset    int // First version column shown when not all versions fit
+	IsLoadingRowHistory bool
+
+	// TLS options of the connection form, written into the connection string on connect
+	TLSMode      string
+	TLSCAInput   textinput.Model
+	TLSCertInput textinput.Model
+	TLSKeyInput  textinput.Model
+
+	// SQLCipher key of the connection form for encrypted SQLite files, written
+	// into the connection string on connect
+	SQLCipherKeyInput textinput.Model
+
+	// Service-account key file of the connection form for BigQuery, written into
+	// the connection string on connect
+	ServiceAccountKeyInput textinput.Model
+
+	// Cloud SQL instance connection name of the connection form and whether to
+	// use IAM database authentication, written into the connection string on connect
+	CloudSQLInstanceInput textinput.Model
+	CloudSQLIAM           bool
+
+	// Builder mode of the connection form assembles the connection string from
+	// separate fields; without it the string is typed raw into TextInput
+	ConnectionBuilderMode bool
+	BuilderHostInput      textinput.Model
+	BuilderPortInput      textinput.Model
+	BuilderUserInput      textinput.Model
+	BuilderPasswordInput  textinput.Model
+	BuilderDatabaseInput  textinput.Model
+	BuilderOptionsInput   textinput.Model
+	BuilderWarehouseInput textinput.Model // Snowflake only
+	BuilderRoleInput      textinput.Model // Snowflake only
+
+	// Master passphrase prompt: unlocks encrypted saved connections at startup or sets a new passphrase
+	PassphraseInput        textinput.Model
+	PassphraseConfirmInput textinput.Model
+	IsSettingPassphrase    bool              // Setting a new passphrase rather than unlocking
+	IsApplyingPassphrase   bool              // Deriving the key and decrypting or rewriting connections
+	LockedConnections      []SavedConnection // Saved connections as read from disk, still encrypted
+	LaunchConnection       string            // Saved connection named on the command line, connected once unlocked
+
+	// Read replica of a saved connection. Previews and single SELECTs are sent to
+	// it while it is connected; LastEndpoint names the one that served the last read.
+	ReplicaConnectionStr string
+	ReplicaDB            *sql.DB
+	ReplicaInput         textinput.Model // Replica connection string of the save connection form
+	LastEndpoint         string          // EndpointPrimary or EndpointReplica
+
+	// Time-series chart of a numeric column of the selected table, aggregated per
+	// bucket of one of the table's date or time columns
+	ChartValueColumn string
+	ChartTimeColumns []string // Date and time columns the chart can be drawn over
+	ChartTimeIndex   int      // Index into ChartTimeColumns of the column in use
+	ChartBucket      string   // hour, day, week, month, or year
+	ChartAggregate   string   // avg, sum, min, max, or count
+	ChartPoints      []ChartPoint
+	IsLoadingChart   bool
+
+	// Group-by summary of a column of the selected table: row count and share of
+	// each value, with an optional aggregate of a numeric column per value
+	GroupColumn          string
+	GroupAggregateColumn string // "" when no second aggregate is shown
+	GroupAggregate       string // sum, avg, min, or max
+	GroupSummary         []GroupCount
+	GroupTotal           int64
+	GroupSummaryTable    table.Model
+	IsLoadingGroups      bool
+
+	// Saved connections list: folded groups, the one group shown alone, the
+	// order, and the search over names, groups, and tags
+	CollapsedGroups        map[string]bool
+	ConnectionGroupFilter  string // "" shows every group
+	ConnectionSort         string // One of ConnectionSorts
+	ConnectionSearchInput  textinput.Model
+	IsSearchingConnections bool
+
+	// Group and tags prompt of a saved connection
+	GroupEditConnection  string // Name of the connection being edited
+	ConnectionGroupInput textinput.Model
+	ConnectionTagsInput  textinput.Model
+
+	// Defaults of the open connection and the prompt that edits a saved connection's
+	DefaultSchema          string    // "" opens the driver's default schema
+	DefaultSortKeys        []SortKey // Sort applied to each table the preview opens
+	Timeouts               Timeouts  // Timeouts of the open connection
+	DefaultsEditConnection string    // Name of the connection being edited
+	DefaultSchemaInput     textinput.Model
+	DefaultPageSizeInput   textinput.Model
+	DefaultSortInput       textinput.Model
+	ConnectTimeoutInput    textinput.Model
+	StatementTimeoutInput  textinput.Model
+
+	// Row diff: a row marked in the data preview compared field by field with another
+	RowDiffMarked      RowDiffSide // Marked row; an empty Label means none is marked
+	RowDiffLeft        RowDiffSide
+	RowDiffRight       RowDiffSide
+	RowDiffTable       table.Model
+	RowDiffOnlyChanges bool // Hide the fields both rows agree on
+
+	// Watchlist of pinned rows, refreshed on demand or on an interval
+	Watchlist             []WatchedRow
+	WatchlistTable        table.Model
+	WatchlistInterval     time.Duration // 0 refreshes on demand only
+	WatchlistSeq          int           // Bumped to cancel pending auto-refresh ticks
+	IsRefreshingWatchlist bool
+
+	// Navigation stack: the screens esc returns through, and what the current
+	// screen was opened on
+	NavStack  []NavEntry
+	NavParams NavParams
+
+	// Help menu toggle
+	ShowFullHelp bool // Whether to show full help menu or compact version
+}
+
 // Init initializes the Bubble Tea program
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, textarea.Blink, m.Spinner.Tick)
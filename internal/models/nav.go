@@ -0,0 +1,14 @@
+package models
+
+// NavParams are what a screen was opened on. They are recorded with the screen
+// on the navigation stack and name it in the breadcrumbs.
+type NavParams struct {
+	Schema string
+	Table  string
+}
+
+// NavEntry is a screen below the current one on the navigation stack
+type NavEntry struct {
+	State  ViewState
+	Params NavParams
+}
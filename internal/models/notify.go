@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Notification is one NOTIFY received by the LISTEN/NOTIFY monitor
+type Notification struct {
+	Channel    string
+	Payload    string
+	PID        int // Server process that sent the notification
+	ReceivedAt time.Time
+}
+
+// NotificationMsg carries a notification, or a change of the monitor's
+// connection, to the update loop. Seq tells messages of an earlier monitor
+// from those of the running one.
+type NotificationMsg struct {
+	Seq          int
+	Notification *Notification
+	Connected    bool  // The connection was (re-)established and the channels are listened on
+	Err          error // The connection was lost or a LISTEN failed
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// TableSize holds size statistics for a single table
+type TableSize struct {
+	Name       string
+	Schema     string
+	RowCount   int64
+	DataBytes  int64
+	IndexBytes int64
+}
+
+// TotalBytes returns the combined data and index size of the table
+func (t TableSize) TotalBytes() int64 {
+	return t.DataBytes + t.IndexBytes
+}
+
+// DatabaseOverview summarizes size and connection statistics for a database.
+// Negative values mean the statistic is not available for the driver.
+type DatabaseOverview struct {
+	Database       string
+	TotalBytes     int64
+	DataBytes      int64
+	IndexBytes     int64
+	Tables         []TableSize
+	Connections    int
+	MaxConnections int
+	// ConnectionsByDatabase breaks server connections down per database
+	ConnectionsByDatabase []DatabaseConnections
+}
+
+// DatabaseConnections counts the open server connections for one database
+type DatabaseConnections struct {
+	Database string
+	Count    int
+}
+
+// DatabaseOverviewResult is returned when the overview dashboard finishes loading
+type DatabaseOverviewResult struct {
+	Overview DatabaseOverview
+	Err      error
+}
+
+// MaintenanceResult is returned when a table maintenance action completes
+type MaintenanceResult struct {
+	Table    string
+	Action   string
+	Message  string
+	Duration time.Duration
+	Err      error
+}
+
+// MaintenanceTickMsg drives the elapsed-time indicator while maintenance runs
+type MaintenanceTickMsg struct{}
+
+// SlowQuery holds aggregated execution statistics for a normalized statement
+type SlowQuery struct {
+	Query   string
+	Calls   int64
+	TotalMs float64
+	MeanMs  float64
+	Rows    int64
+}
+
+// SlowQueriesResult is returned when the slow query log finishes loading
+type SlowQueriesResult struct {
+	Queries []SlowQuery
+	Err     error
+}
@@ -0,0 +1,13 @@
+package models
+
+// UnlockConnectionsResult is returned when the passphrase has been checked against the saved connections
+type UnlockConnectionsResult struct {
+	Connections []SavedConnection // The saved connections with their connection strings decrypted
+	Err         error
+}
+
+// PassphraseSetResult is returned when the saved connections have been rewritten under a new passphrase
+type PassphraseSetResult struct {
+	Encrypted bool // False when encryption was turned off
+	Err       error
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// SessionHealth is what the last ping said about the open connection
+type SessionHealth int
+
+const (
+	SessionHealthUnknown      SessionHealth = iota // Not pinged yet
+	SessionHealthConnected                         // The ping answered promptly
+	SessionHealthDegraded                          // The ping answered slowly, timed out, or failed without dropping the connection
+	SessionHealthDisconnected                      // The connection is gone
+)
+
+// PingTickMsg starts the next ping of the open connection
+type PingTickMsg struct {
+	Seq int
+}
+
+// PingResult is the outcome of one ping of the open connection
+type PingResult struct {
+	Seq     int
+	Latency time.Duration
+	Err     error
+	At      time.Time
+}
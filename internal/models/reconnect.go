@@ -0,0 +1,36 @@
+package models
+
+import "database/sql"
+
+// RetryOperation names a read operation that can be replayed after reconnecting
+type RetryOperation int
+
+const (
+	RetryNone RetryOperation = iota
+	RetryTables
+	RetryPreview
+	RetryColumns
+	RetryRelationships
+	RetryOverview
+	RetrySlowQueries
+	RetrySettings
+	RetryGrowth
+	RetrySchemas
+	RetryQuery
+)
+
+// ReconnectResult is returned when a lost connection has been re-established
+type ReconnectResult struct {
+	DB  *sql.DB
+	Err error
+}
+
+// ReconnectTickMsg advances the countdown to an automatic reconnect
+type ReconnectTickMsg struct {
+	Session int
+}
+
+// ConnectRetryTickMsg advances the countdown to an automatic connect retry
+type ConnectRetryTickMsg struct {
+	Session int
+}
@@ -0,0 +1,10 @@
+package models
+
+// RowDiffSide is one of the two rows a row diff compares
+type RowDiffSide struct {
+	Label   string   // How the row is named on screen, e.g. "id=42" or "row 7"
+	Table   string   // Table the row was read from
+	Columns []string // Columns of the row when it was read
+	Values  []string
+	Nulls   []bool
+}
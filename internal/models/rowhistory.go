@@ -0,0 +1,10 @@
+package models
+
+// RowHistoryResult is returned when the prior versions of a row finish loading
+type RowHistoryResult struct {
+	Source   string     // Where the versions came from, e.g. "system versioning" or "orders_history"
+	Columns  []string   // Columns of the versions, which may add period or audit columns to the table's
+	Versions [][]string // Versions of the row, oldest first
+	Labels   []string   // Heading for each version, such as when it became current
+	Err      error
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// RunningQuery is the query runner's query in flight, kept on the model while it
+// runs. Its statements run under Context, which a cancel ends; the server
+// session of the statement running now is recorded so the cancel can reach it
+// on the server first. The runner runs one query at a time, so a cancel can
+// only ever target our own session.
+type RunningQuery struct {
+	Context context.Context
+	Cancel  context.CancelFunc
+
+	mu        sync.Mutex
+	db        *sql.DB
+	driver    string
+	pid       int64 // 0 when the driver has no session id to cancel by
+	cancelled bool
+}
+
+// NewRunningQuery returns a running query with its own cancellable context
+func NewRunningQuery() *RunningQuery {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RunningQuery{Context: ctx, Cancel: cancel}
+}
+
+// SetSession records the pool, driver, and server session of the statement
+// that is starting; a pid of 0 clears it once the statement is done
+func (q *RunningQuery) SetSession(db *sql.DB, driver string, pid int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.db, q.driver, q.pid = db, driver, pid
+}
+
+// Session returns the pool, driver, and server session of the running statement
+func (q *RunningQuery) Session() (*sql.DB, string, int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.db, q.driver, q.pid
+}
+
+// MarkCancelled records that the user cancelled the query
+func (q *RunningQuery) MarkCancelled() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cancelled = true
+}
+
+// Cancelled reports whether the user cancelled the query
+func (q *RunningQuery) Cancelled() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.cancelled
+}
@@ -0,0 +1,17 @@
+package models
+
+// ServerSetting describes a single server configuration variable
+type ServerSetting struct {
+	Name        string
+	Value       string
+	Unit        string
+	Default     string
+	Modified    bool // Whether the value differs from the compiled-in default
+	Description string
+}
+
+// ServerSettingsResult is returned when the server configuration finishes loading
+type ServerSettingsResult struct {
+	Settings []ServerSetting
+	Err      error
+}
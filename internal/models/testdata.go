@@ -0,0 +1,41 @@
+package models
+
+// ForeignKeyRef points at the parent column a foreign key value must exist in
+type ForeignKeyRef struct {
+	Table  string
+	Column string
+}
+
+// ColumnSpec describes the constraints a generated value must satisfy for one column
+type ColumnSpec struct {
+	Name         string
+	DataType     string // Declared type, e.g. varchar(50), integer, timestamp
+	MaxLength    int    // Character limit, 0 when unbounded
+	Nullable     bool
+	HasDefault   bool
+	Generated    bool // Auto-increment, identity, serial or computed: left to the database
+	IsPrimaryKey bool
+	EnumValues   []string
+	References   *ForeignKeyRef
+}
+
+// TestDataPlan is everything needed to generate rows for a table
+type TestDataPlan struct {
+	Table      string
+	Schema     string
+	Columns    []ColumnSpec
+	ParentKeys map[string][]string // Existing parent keys by foreign key column name
+}
+
+// TestDataPlanResult is returned when a table's column specs finish loading
+type TestDataPlanResult struct {
+	Plan TestDataPlan
+	Err  error
+}
+
+// TestDataInsertResult is returned when generated rows have been inserted
+type TestDataInsertResult struct {
+	Table    string
+	Inserted int64
+	Err      error
+}
@@ -1,15 +1,8 @@
 package models
 
 import (
-	"database/sql"
+	"fmt"
 	"time"
-
-	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/paginator"
-	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/table"
-	"github.com/charmbracelet/bubbles/textarea"
-	"github.com/charmbracelet/bubbles/textinput"
 )
 
 // Application states
@@ -32,6 +25,25 @@ const (
 	IndexesView
 	IndexDetailView
 	RelationshipsView
+	DatabaseOverviewView
+	SlowQueryView
+	ServerSettingsView
+	TableGrowthView
+	AuditLogView
+	GenerateDataView
+	CopyDataView
+	CompareTablesView
+	NoteEditView
+	ConnectionHealthView
+	RowHistoryView
+	PassphraseView
+	ChartView
+	GroupSummaryView
+	ConnectionGroupView
+	RowDiffView
+	WatchlistView
+	ConnectionDefaultsView
+	NotifyView
 )
 
 // Sort directions
@@ -43,6 +55,12 @@ const (
 	SortDesc
 )
 
+// SortKey is one column of a multi-column sort
+type SortKey struct {
+	Column    string
+	Direction SortDirection
+}
+
 // Database types
 type DBType struct {
 	Name   string
@@ -51,9 +69,59 @@ type DBType struct {
 
 // Saved connection
 type SavedConnection struct {
-	Name          string `json:"name"`
-	Driver        string `json:"driver"`
-	ConnectionStr string `json:"connection_str"`
+	Name                 string    `json:"name"`
+	Driver               string    `json:"driver"`
+	ConnectionStr        string    `json:"connection_str,omitempty"`
+	ReplicaConnectionStr string    `json:"replica_connection_str,omitempty"` // Read replica for previews and single SELECTs
+	Notes                string    `json:"notes,omitempty"`
+	Group                string    `json:"group,omitempty"` // Folder in the saved connections list, e.g. prod
+	Tags                 []string  `json:"tags,omitempty"`
+	ReadOnly             bool      `json:"read_only,omitempty"`         // Block writes, DDL, and field edits
+	Immutable            bool      `json:"immutable,omitempty"`         // Read-only SQLite file on read-only media, opened without locks
+	DefaultSchema        string    `json:"default_schema,omitempty"`    // Schema to open on connect instead of the driver's
+	PageSize             int       `json:"page_size,omitempty"`         // Data preview rows per page; 0 for the default
+	DefaultSort          string    `json:"default_sort,omitempty"`      // Preview sort of every table, e.g. "created_at desc, id"
+	Environment          string    `json:"environment,omitempty"`       // production, staging, or dev
+	ConnectTimeout       string    `json:"connect_timeout,omitempty"`   // e.g. "5s"; "" for the default, "0s" for none
+	StatementTimeout     string    `json:"statement_timeout,omitempty"` // e.g. "2m"; "" for the default, "0s" for none
+	LastUsedAt           time.Time `json:"last_used_at,omitzero"`       // Last successful connect
+	UseCount             int       `json:"use_count,omitempty"`         // Successful connects
+
+	// The connection strings as fields, the form connections.json stores them in
+	// when they rebuild exactly; loading turns them back into the strings
+	Connection        *ConnectionFields `json:"connection,omitempty"`
+	ReplicaConnection *ConnectionFields `json:"replica_connection,omitempty"`
+}
+
+// Timeouts bound opening a connection and each statement run on it; 0 means
+// no limit
+type Timeouts struct {
+	Connect   time.Duration
+	Statement time.Duration
+}
+
+// TLSOptions are the TLS settings of the connection form. They are written into
+// the connection string, so a saved connection carries them without extra fields.
+type TLSOptions struct {
+	Mode     string // "", disable, require, verify-ca, or verify-full
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// ConnectionFields are the parts of a connection string that the connection
+// form's builder mode asks for separately
+type ConnectionFields struct {
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	Database string `json:"database,omitempty"`
+	Options  string `json:"options,omitempty"` // Query string of driver parameters, e.g. sslmode=disable
+
+	// Snowflake session settings, written as parameters of the connection string
+	Warehouse string `json:"warehouse,omitempty"`
+	Role      string `json:"role,omitempty"`
 }
 
 // Query history entry
@@ -78,6 +146,7 @@ type TableInfo struct {
 	TableType   string
 	RowCount    int64
 	Description string
+	Persistence string // unlogged, temporary or in-memory; empty for durable tables
 }
 
 // List item
@@ -89,17 +158,47 @@ func (i Item) Title() string       { return i.ItemTitle }
 func (i Item) Description() string { return i.ItemDesc }
 func (i Item) FilterValue() string { return i.ItemTitle }
 
+// GroupItem heads a group of saved connections in the saved connections list
+type GroupItem struct {
+	Name      string // "" for connections without a group
+	Count     int
+	Collapsed bool
+}
+
+func (g GroupItem) Title() string {
+	name := g.Name
+	if name == "" {
+		name = "Ungrouped"
+	}
+	arrow := "▾"
+	if g.Collapsed {
+		arrow = "▸"
+	}
+	return fmt.Sprintf("%s 📁 %s (%d)", arrow, name, g.Count)
+}
+func (g GroupItem) Description() string { return "" }
+func (g GroupItem) FilterValue() string { return g.Name }
+
 // Field item for row details
 type FieldItem struct {
-	Name  string
-	Value string
+	Name    string
+	Value   string
+	Display string // Value converted for display, e.g. into the session timezone
+	Type    string // Database type of the column, when the driver reports it
+	IsNull  bool   // The value was SQL NULL rather than the text "NULL"
 }
 
 func (f FieldItem) Title() string { return f.Name }
 func (f FieldItem) Description() string {
-	if f.Value == "NULL" {
+	if f.Display != "" {
+		return f.Display
+	}
+	if f.IsNull {
 		return "(NULL)"
 	}
+	if f.Value == "" {
+		return "(empty)"
+	}
 	// Truncate long values for list display
 	if len(f.Value) > 80 {
 		return f.Value[:77] + "..."
@@ -107,215 +206,3 @@ func (f FieldItem) Description() string {
 	return f.Value
 }
 func (f FieldItem) FilterValue() string { return f.Name }
-
-// Main model
-type Model struct {
-	Version              string
-	State                ViewState
-	DBTypeList           list.Model
-	SavedConnectionsList list.Model
-	TextInput            textinput.Model
-	NameInput            textinput.Model
-	QueryInput           textinput.Model
-	TablesList           list.Model
-	ColumnsTable         table.Model
-	QueryResultsTable    table.Model
-	DataPreviewTable     table.Model
-	IndexesTable         table.Model
-	RelationshipsTable   table.Model
-	SelectedDB           DBType
-	ConnectionStr        string
-	DB                   *sql.DB
-	Err                  error
-	ErrorTimeout         *time.Time // When to clear the error (nil means no timeout)
-	Tables               []string
-	TableInfos           []TableInfo
-	SelectedTable        string
-	Schemas              []SchemaInfo
-	SelectedSchema       string
-	SchemasList          list.Model
-	IsLoadingSchemas     bool
-	SavedConnections     []SavedConnection
-	EditingConnectionIdx int
-	QueryResult          string
-	Width                int
-	Height               int
-
-	// Loading states
-	IsTestingConnection bool
-	IsConnecting        bool
-	IsSavingConnection  bool
-	IsLoadingTables     bool
-	IsLoadingColumns    bool
-	IsExecutingQuery    bool
-	IsLoadingPreview    bool
-
-	// Export states
-	IsExporting        bool
-	LastQueryColumns   []string
-	LastQueryRows      [][]string
-	LastPreviewColumns []string
-	LastPreviewRows    [][]string
-
-	// Spinner for animations
-	Spinner spinner.Model
-
-	// Search functionality
-	SearchInput        textinput.Model
-	IsSearchingTables  bool
-	IsSearchingColumns bool
-	OriginalTableItems []list.Item
-	OriginalTableRows  []table.Row
-	SearchTerm         string
-
-	// Query history functionality
-	QueryHistory     []QueryHistoryEntry
-	QueryHistoryList list.Model
-	IsViewingHistory bool
-
-	// Row detail functionality
-	SelectedRowData        []string
-	SelectedRowIndex       int
-	RowDetailList          list.Model
-	RowDetailPaginator     paginator.Model
-	SelectedFieldForDetail string
-	IsViewingFieldDetail   bool
-
-	// Full text view pagination
-	FullTextCurrentPage   int
-	FullTextItemsPerPage  int
-	FullTextSelectedField int
-
-	// Individual field detail view
-	SelectedFieldName           string
-	SelectedFieldValue          string
-	SelectedFieldIndex          int
-	FieldDetailScrollOffset     int
-	FieldDetailHorizontalOffset int
-	FieldDetailLinesPerPage     int
-	FieldDetailCharsPerLine     int
-
-	// Field editing
-	FieldTextarea      textarea.Model
-	IsEditingField     bool
-	OriginalFieldValue string
-	EditingFieldName   string
-	EditingFieldIndex  int
-
-	// Index detail view
-	SelectedIndexName       string
-	SelectedIndexType       string
-	SelectedIndexColumns    string
-	SelectedIndexDefinition string
-
-	// Data preview pagination
-	DataPreviewCurrentPage  int
-	DataPreviewItemsPerPage int
-	DataPreviewTotalRows    int
-
-	// Data preview horizontal scrolling
-	DataPreviewScrollOffset int        // Current column offset
-	DataPreviewVisibleCols  int        // Number of columns visible at once
-	DataPreviewAllColumns   []string   // Store all column names
-	DataPreviewAllRows      [][]string // Store all row data
-
-	// Data preview filtering
-	DataPreviewFilterActive bool            // Whether filter mode is active
-	DataPreviewFilterValue  string          // Current filter text
-	DataPreviewFilterInput  textinput.Model // Filter input field
-
-	// Data preview sorting
-	DataPreviewSortColumn    string        // Column to sort by
-	DataPreviewSortDirection SortDirection // Current sort direction
-	DataPreviewSortMode      bool          // Whether in column selection mode for sorting
-
-	// Help menu toggle
-	ShowFullHelp bool // Whether to show full help menu or compact version
-}
-
-// Message types for Bubble Tea
-type ConnectResult struct {
-	DB     *sql.DB
-	Driver string
-	Err    error
-	Tables []string
-	Schema string
-}
-
-type TestConnectionResult struct {
-	Success bool
-	Err     error
-}
-
-type ColumnsResult struct {
-	Columns [][]string
-	Err     error
-}
-
-type QueryResult struct {
-	Columns  []string
-	Rows     [][]string
-	Err      error
-	RowCount int
-}
-
-type DataPreviewResult struct {
-	Columns   []string
-	Rows      [][]string
-	Err       error
-	TotalRows int
-}
-
-type IndexesResult struct {
-	Indexes [][]string
-	Err     error
-}
-
-type RelationshipsResult struct {
-	Relationships [][]string
-	Err           error
-}
-
-type QueryResultMsg struct {
-	Result  string
-	Columns []string
-	Rows    [][]string
-	Err     error
-}
-
-type ClearResultMsg struct{}
-type ClearErrorMsg struct{}
-type ErrorTimeoutMsg struct{}
-
-type ExportResult struct {
-	Success  bool
-	Err      error
-	Filename string
-	Format   string
-}
-
-type TestAndSaveResult struct {
-	Success bool
-	Err     error
-	DB      *sql.DB
-	Driver  string
-	Tables  []string
-	Schema  string
-}
-
-type FieldValueResult struct {
-	Value string
-	Err   error
-}
-
-type ClipboardResult struct {
-	Success bool
-	Err     error
-}
-
-type FieldUpdateResult struct {
-	Success  bool
-	Err      error
-	ExitEdit bool
-	NewValue string
-}
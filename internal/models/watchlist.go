@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// WatchedRow is a row pinned to the watchlist, found again by its primary key
+type WatchedRow struct {
+	Table       string
+	Schema      string
+	KeyColumn   string
+	KeyValue    string
+	Columns     []string
+	Values      []string // Values as of the last refresh
+	Nulls       []bool
+	Changed     []FieldChange // Fields that changed at the last refresh
+	Missing     bool          // No row matched the key at the last refresh
+	Err         error
+	RefreshedAt time.Time
+}
+
+// FieldChange is a field whose value changed between two refreshes of a watched row
+type FieldChange struct {
+	Column string
+	Before string
+	After  string
+}
+
+// WatchedRowValues are the values of one watched row read by a refresh
+type WatchedRowValues struct {
+	Columns []string
+	Values  []string // nil when no row matches the key anymore
+	Nulls   []bool
+	Err     error
+}
+
+// WatchlistRefreshResult is returned when every watched row has been re-read,
+// in watchlist order
+type WatchlistRefreshResult struct {
+	Rows []WatchedRowValues
+	At   time.Time
+}
+
+// WatchlistTickMsg fires when the watchlist's auto-refresh interval elapses
+type WatchlistTickMsg struct {
+	Seq int
+}
@@ -0,0 +1,6 @@
+package models
+
+// WorksheetTickMsg autosaves the query editor of a connection session
+type WorksheetTickMsg struct {
+	Session int
+}
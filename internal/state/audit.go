@@ -0,0 +1,60 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleAuditLogViewUpdate handles all updates for the AuditLogView state.
+func HandleAuditLogViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "ctrl+r":
+			// Reload the log from disk
+			if !m.IsLoadingAudit {
+				m.IsLoadingAudit = true
+				m.Err = nil
+				return m, utils.LoadAuditLog()
+			}
+			return m, nil
+
+		case "c":
+			// Copy the selected statement to the clipboard
+			if entry, ok := selectedAuditEntry(m); ok {
+				if err := clipboard.WriteAll(entry.Statement); err != nil {
+					m.Err = fmt.Errorf("failed to copy to clipboard: %w", err)
+					return m, nil
+				}
+				m.QueryResult = "✅ Copied statement to clipboard"
+				return m, utils.ClearResultAfterTimeout()
+			}
+			return m, nil
+		}
+	}
+
+	// Let the table handle navigation keys
+	m.AuditTable, cmd = m.AuditTable.Update(msg)
+	return m, cmd
+}
+
+// selectedAuditEntry returns the entry under the table cursor; rows are shown newest first
+func selectedAuditEntry(m models.Model) (models.AuditEntry, bool) {
+	idx := len(m.AuditEntries) - 1 - m.AuditTable.Cursor()
+	if idx < 0 || idx >= len(m.AuditEntries) {
+		return models.AuditEntry{}, false
+	}
+	return m.AuditEntries[idx], true
+}
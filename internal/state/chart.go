@@ -0,0 +1,47 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleChartViewUpdate handles all updates for the ChartView state.
+func HandleChartViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the columns view
+			m = utils.PopView(m, models.ColumnsView)
+			m.Err = nil
+			return m, nil
+		}
+
+		// Options reload the chart, so they wait for the current load
+		if m.IsLoadingChart {
+			return m, nil
+		}
+
+		switch keyMsg.String() {
+		case "t":
+			// Chart over the table's next date or time column
+			m.ChartTimeIndex = (m.ChartTimeIndex + 1) % len(m.ChartTimeColumns)
+			return utils.ReloadChart(m)
+
+		case "b":
+			// Group by the next bucket size
+			m.ChartBucket = utils.NextChartOption(utils.ChartBuckets, m.ChartBucket)
+			return utils.ReloadChart(m)
+
+		case "a":
+			// Aggregate with the next function
+			m.ChartAggregate = utils.NextChartOption(utils.ChartAggregates, m.ChartAggregate)
+			return utils.ReloadChart(m)
+
+		case "ctrl+r":
+			// Load the buckets again
+			return utils.ReloadChart(m)
+		}
+	}
+	return m, nil
+}
@@ -3,6 +3,7 @@ package state
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
 )
 
 // HandleColumnsViewUpdate handles all updates for the ColumnsView state.
@@ -11,17 +12,74 @@ func HandleColumnsViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd
 
 	// Handle key messages
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// The search input captures typing while active and filters as you type
+		if m.IsSearchingColumns {
+			switch keyMsg.String() {
+			case "enter":
+				m.IsSearchingColumns = false
+				m.SearchInput.Blur()
+				return m, nil
+			case "esc":
+				m.IsSearchingColumns = false
+				m.SearchInput.Blur()
+				m.SearchInput.SetValue("")
+				return utils.RefreshColumnsTable(m), nil
+			default:
+				m.SearchInput, cmd = m.SearchInput.Update(msg)
+				return utils.RefreshColumnsTable(m), cmd
+			}
+		}
+
 		switch keyMsg.String() {
 		case "esc":
 			// Go back to the tables view
-			m.State = models.TablesView
+			m = utils.PopView(m, models.TablesView)
 			m.Err = nil
+			m.SearchInput.SetValue("")
+			return m, nil
+
+		case "/":
+			// Search columns by name or type
+			m.IsSearchingColumns = true
+			m.SearchInput.Focus()
+			return m, nil
+
+		case "o":
+			// Sort by the next field, then back to declaration order
+			fields := len(m.ColumnsTable.Columns())
+			m.ColumnsSortField, m.ColumnsSortDirection = utils.NextColumnsSort(m.ColumnsSortField, m.ColumnsSortDirection, fields)
+			return utils.RefreshColumnsTable(m), nil
+
+		case "O":
+			// Reverse the current sort
+			switch m.ColumnsSortDirection {
+			case models.SortAsc:
+				m.ColumnsSortDirection = models.SortDesc
+			case models.SortDesc:
+				m.ColumnsSortDirection = models.SortAsc
+			default:
+				return m, nil
+			}
+			return utils.RefreshColumnsTable(m), nil
+
+		case "c":
+			// Chart the selected numeric column over a date or time column
+			if row := m.ColumnsTable.SelectedRow(); len(row) > 1 && m.DB != nil {
+				return utils.StartChart(m, row[0], row[1])
+			}
+			return m, nil
+
+		case "g":
+			// Count the rows of each value of the selected column
+			if row := m.ColumnsTable.SelectedRow(); len(row) > 0 && m.DB != nil {
+				return utils.StartGroupSummary(m, row[0])
+			}
 			return m, nil
 
 		case "s":
 			// Allow saving the current connection from this view
 			if m.ConnectionStr != "" {
-				m.State = models.SaveConnectionView
+				m = utils.PushView(m, models.SaveConnectionView, models.NavParams{})
 				m.NameInput.SetValue("")
 				m.NameInput.Focus()
 				return m, nil
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleCompareTablesViewUpdate handles all updates for the CompareTablesView state.
+func HandleCompareTablesViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.CompareStep == models.CompareEnterTable {
+			m.CompareTableInput, cmd = m.CompareTableInput.Update(msg)
+		}
+		return m, cmd
+	}
+
+	switch m.CompareStep {
+	case models.CompareChooseTarget:
+		switch keyMsg.String() {
+		case "esc":
+			return leaveCompareView(m), nil
+		case "up", "k":
+			if m.CompareTargetIndex > 0 {
+				m.CompareTargetIndex--
+			}
+		case "down", "j":
+			if m.CompareTargetIndex < len(m.SavedConnections) {
+				m.CompareTargetIndex++
+			}
+		case "enter":
+			m.CompareStep = models.CompareEnterTable
+			m.CompareTableInput.SetValue(m.CompareSourceTable)
+			m.CompareTableInput.CursorEnd()
+			m.CompareTableInput.Focus()
+		}
+		return m, nil
+
+	case models.CompareEnterTable:
+		switch keyMsg.String() {
+		case "esc":
+			m.CompareStep = models.CompareChooseTarget
+			m.CompareTableInput.Blur()
+			m.Err = nil
+			return m, nil
+		case "enter":
+			return startTableComparison(m)
+		}
+		m.CompareTableInput, cmd = m.CompareTableInput.Update(msg)
+		return m, cmd
+
+	default: // CompareReport
+		switch keyMsg.String() {
+		case "esc":
+			if !m.IsComparingTables {
+				return leaveCompareView(m), nil
+			}
+		case "ctrl+r":
+			// Compare again, e.g. after fixing the differences
+			if !m.IsComparingTables {
+				return startTableComparison(m)
+			}
+		}
+		return m, nil
+	}
+}
+
+// leaveCompareView returns to the tables view
+func leaveCompareView(m models.Model) models.Model {
+	m = utils.PopView(m, models.TablesView)
+	m.CompareTableInput.Blur()
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
+
+// startTableComparison checksums the source table against the chosen table
+func startTableComparison(m models.Model) (models.Model, tea.Cmd) {
+	input := strings.TrimSpace(m.CompareTableInput.Value())
+	if input == "" {
+		return utils.SetErrorWithTimeout(m, fmt.Errorf("enter the table to compare with"), 3*time.Second)
+	}
+
+	var target *models.SavedConnection
+	driver := m.SelectedDB.Driver
+	if m.CompareTargetIndex > 0 && m.CompareTargetIndex <= len(m.SavedConnections) {
+		conn := m.SavedConnections[m.CompareTargetIndex-1]
+		target = &conn
+		driver = conn.Driver
+	}
+	schema, table := utils.ParseTargetTable(input, driver)
+	if target == nil && !strings.Contains(input, ".") {
+		// A bare name on the current connection refers to the schema being browsed
+		schema = m.SelectedSchema
+	}
+	if target == nil && table == m.CompareSourceTable && schema == m.SelectedSchema {
+		return utils.SetErrorWithTimeout(m, fmt.Errorf("choose a different table to compare with"), 3*time.Second)
+	}
+
+	m.CompareStep = models.CompareReport
+	m.CompareTableInput.Blur()
+	m.Comparison = models.TableComparison{}
+	m.IsComparingTables = true
+	m.Err = nil
+	m.QueryResult = ""
+	return m, utils.RunTableComparison(m.DB, m.SelectedDB, m.SelectedSchema, m.CompareSourceTable, target, schema, table)
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleConnectionDefaultsViewUpdate handles all updates for the ConnectionDefaultsView state.
+func HandleConnectionDefaultsViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Leave without saving
+			m = utils.PopView(m, models.SavedConnectionsView)
+			m = blurDefaultsInputs(m)
+			m.Err = nil
+			return m, nil
+
+		case "tab", "down":
+			return focusDefaultsInput(m, 1), nil
+
+		case "shift+tab", "up":
+			return focusDefaultsInput(m, -1), nil
+
+		case "enter":
+			pageSize, err := utils.ParsePageSize(m.DefaultPageSizeInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			sortKeys, err := utils.ParseSortSpec(m.DefaultSortInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			connectTimeout, err := utils.ParseTimeoutSetting(m.ConnectTimeoutInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("connect timeout: %w", err), 3*time.Second)
+			}
+			statementTimeout, err := utils.ParseTimeoutSetting(m.StatementTimeoutInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("statement timeout: %w", err), 3*time.Second)
+			}
+			updated, err := utils.SetConnectionDefaults(m, m.DefaultsEditConnection, m.DefaultSchemaInput.Value(), pageSize, sortKeys, connectTimeout, statementTimeout)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			updated = utils.PopView(updated, models.SavedConnectionsView)
+			updated = blurDefaultsInputs(updated)
+			updated.Err = nil
+			updated.QueryResult = fmt.Sprintf("⚙️ Saved the defaults of '%s'; they apply on the next connect", m.DefaultsEditConnection)
+			return updated, utils.ClearResultAfterTimeout()
+		}
+	}
+
+	for _, input := range defaultsInputs(&m) {
+		if input.Focused() {
+			*input, cmd = input.Update(msg)
+			break
+		}
+	}
+	return m, cmd
+}
+
+// startDefaultsEdit opens the defaults prompt for a saved connection
+func startDefaultsEdit(m models.Model, conn models.SavedConnection) models.Model {
+	m.DefaultsEditConnection = conn.Name
+	m.DefaultSchemaInput.SetValue(conn.DefaultSchema)
+	m.DefaultPageSizeInput.SetValue("")
+	if conn.PageSize > 0 {
+		m.DefaultPageSizeInput.SetValue(strconv.Itoa(conn.PageSize))
+	}
+	m.DefaultSortInput.SetValue(conn.DefaultSort)
+	m.ConnectTimeoutInput.SetValue(conn.ConnectTimeout)
+	m.StatementTimeoutInput.SetValue(conn.StatementTimeout)
+	m.DefaultSchemaInput.CursorEnd()
+	m = blurDefaultsInputs(m)
+	m.DefaultSchemaInput.Focus()
+	m = utils.PushView(m, models.ConnectionDefaultsView, models.NavParams{})
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
+
+// defaultsInputs lists the fields of the defaults prompt in tab order
+func defaultsInputs(m *models.Model) []*textinput.Model {
+	return []*textinput.Model{
+		&m.DefaultSchemaInput,
+		&m.DefaultPageSizeInput,
+		&m.DefaultSortInput,
+		&m.ConnectTimeoutInput,
+		&m.StatementTimeoutInput,
+	}
+}
+
+// focusDefaultsInput moves the focus of the defaults prompt by step, wrapping around
+func focusDefaultsInput(m models.Model, step int) models.Model {
+	focused := 0
+	for i, input := range defaultsInputs(&m) {
+		if input.Focused() {
+			focused = i
+		}
+	}
+	m = blurDefaultsInputs(m)
+	inputs := defaultsInputs(&m)
+	inputs[(focused+step+len(inputs))%len(inputs)].Focus()
+	return m
+}
+
+// blurDefaultsInputs blurs every input of the defaults prompt
+func blurDefaultsInputs(m models.Model) models.Model {
+	for _, input := range defaultsInputs(&m) {
+		input.Blur()
+	}
+	return m
+}
@@ -1,8 +1,11 @@
 package state
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/config"
 	"github.com/dancaldera/mirador/internal/models"
@@ -18,13 +21,25 @@ func HandleConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.
 		switch keyMsg.String() {
 		case "esc":
 			// Go back to the DB type selection view
-			m.State = models.DBTypeView
+			m = utils.PopView(m, models.DBTypeView)
 			m.Err = nil
 			return m, nil
 
 		case "f1":
 			// Test the connection
 			if !m.IsTestingConnection {
+				updated, err := applyConnectionFields(m)
+				if err == nil {
+					updated, err = applyConnectionTLS(updated)
+				}
+				updated = applyConnectionKey(updated)
+				if err == nil && updated.TextInput.Value() != "" {
+					err = utils.CheckConnectionString(m.SelectedDB.Driver, updated.TextInput.Value())
+				}
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				m = updated
 				m.ConnectionStr = m.TextInput.Value()
 				if m.ConnectionStr != "" {
 					m.IsTestingConnection = true
@@ -38,20 +53,59 @@ func HandleConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.
 		case "enter":
 			// Connect to the database
 			if !m.IsConnecting && !m.IsTestingConnection {
+				updated, err := applyConnectionFields(m)
+				if err == nil {
+					updated, err = applyConnectionTLS(updated)
+				}
+				updated = applyConnectionKey(updated)
+				if err == nil && updated.TextInput.Value() != "" {
+					err = utils.CheckConnectionString(m.SelectedDB.Driver, updated.TextInput.Value())
+				}
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				m = updated
 				m.ConnectionStr = m.TextInput.Value()
+				m.ReplicaConnectionStr = ""
+				if utils.SupportsReadReplica(m.SelectedDB.Driver) {
+					m.ReplicaConnectionStr = strings.TrimSpace(m.ReplicaInput.Value())
+				}
 				if m.ConnectionStr != "" {
+					m.ReadOnly, m.Immutable = false, false
+					m.Environment = models.EnvironmentNone
+					m.ConnectionName = strings.TrimSpace(m.NameInput.Value())
+					m = utils.ApplyConnectionDefaults(m, models.SavedConnection{})
 					// Save connection if a name is provided
 					connectionName := strings.TrimSpace(m.NameInput.Value())
+					lintMessage := ""
 					if connectionName != "" {
+						// New and changed strings are stored normalized, with warnings about
+						// likely mistakes; an unchanged one keeps its saved form, which
+						// favorites, notes, and snapshots are filed under
+						saved := models.SavedConnection{Driver: m.SelectedDB.Driver, ConnectionStr: m.ConnectionStr, ReplicaConnectionStr: m.ReplicaConnectionStr}
+						for _, conn := range m.SavedConnections {
+							if conn.Name == connectionName && conn.ConnectionStr == m.ConnectionStr && conn.ReplicaConnectionStr == m.ReplicaConnectionStr {
+								saved = conn
+							}
+						}
+						if saved.Name == "" {
+							var warnings []string
+							saved, warnings = utils.NormalizeSavedConnection(saved)
+							lintMessage = utils.ConnectionWarningsMessage(connectionName, warnings)
+						}
+						m.ConnectionStr, m.ReplicaConnectionStr = saved.ConnectionStr, saved.ReplicaConnectionStr
 						nameExists := false
 						for i, conn := range m.SavedConnections {
 							if conn.Name == connectionName {
-								// Update existing connection
-								m.SavedConnections[i] = models.SavedConnection{
-									Name:          connectionName,
-									Driver:        m.SelectedDB.Driver,
-									ConnectionStr: m.ConnectionStr,
-								}
+								// Update existing connection, keeping its notes, group, tags, read-only mode, defaults, timeouts, and environment
+								updated := conn
+								updated.Driver = m.SelectedDB.Driver
+								updated.ConnectionStr = m.ConnectionStr
+								updated.ReplicaConnectionStr = m.ReplicaConnectionStr
+								m.SavedConnections[i] = updated
+								m.ReadOnly, m.Immutable = conn.ReadOnly, conn.Immutable
+								m.Environment = conn.Environment
+								m = utils.ApplyConnectionDefaults(m, conn)
 								nameExists = true
 								break
 							}
@@ -59,9 +113,10 @@ func HandleConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.
 						// Add new connection if name doesn't exist
 						if !nameExists {
 							newConnection := models.SavedConnection{
-								Name:          connectionName,
-								Driver:        m.SelectedDB.Driver,
-								ConnectionStr: m.ConnectionStr,
+								Name:                 connectionName,
+								Driver:               m.SelectedDB.Driver,
+								ConnectionStr:        m.ConnectionStr,
+								ReplicaConnectionStr: m.ReplicaConnectionStr,
 							}
 							m.SavedConnections = append(m.SavedConnections, newConnection)
 						}
@@ -69,33 +124,201 @@ func HandleConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.
 					}
 
 					// Connect to database
+					m = utils.CancelConnectRetry(m) // A new connect starts its own retries
 					m.IsConnecting = true
 					m.Err = nil
-					m.QueryResult = ""
-					return m, utils.ConnectToDB(m.SelectedDB, m.ConnectionStr)
+					m.QueryResult = lintMessage
+					return m, utils.ConnectToDB(m.SelectedDB, utils.ConnectionDSN(m), m.DefaultSchema, m.Timeouts)
 				}
 			}
 			return m, nil // Do nothing if already connecting/testing
 
-		case "tab":
-			// Switch focus between name and connection string inputs
-			if m.NameInput.Focused() {
-				m.NameInput.Blur()
-				m.TextInput.Focus()
-			} else {
-				m.TextInput.Blur()
-				m.NameInput.Focus()
+		case "tab", "shift+tab":
+			// Move focus through the name, connection string or builder, and certificate inputs
+			inputs := connectionFormInputs(&m)
+			focused := 0
+			for i, input := range inputs {
+				if input.Focused() {
+					focused = i
+				}
+				input.Blur()
+			}
+			step := 1
+			if keyMsg.String() == "shift+tab" {
+				step = len(inputs) - 1
+			}
+			inputs[(focused+step)%len(inputs)].Focus()
+			return m, nil
+
+		case "f2":
+			// Switch between the builder fields and the raw connection string
+			if utils.SupportsConnectionFields(m.SelectedDB.Driver) {
+				updated, err := toggleConnectionBuilder(m)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				return updated, nil
+			}
+			return m, nil
+
+		case "ctrl+t":
+			// Cycle the TLS mode
+			if utils.SupportsTLSOptions(m.SelectedDB.Driver) {
+				m.TLSMode = utils.NextTLSMode(m.TLSMode)
+			}
+			return m, nil
+
+		case "ctrl+o":
+			// Toggle IAM database authentication for Cloud SQL
+			if utils.SupportsCloudSQL(m.SelectedDB.Driver) {
+				m.CloudSQLIAM = !m.CloudSQLIAM
 			}
 			return m, nil
 		}
 	}
 
 	// Update the focused text input
-	if m.NameInput.Focused() {
-		m.NameInput, cmd = m.NameInput.Update(msg)
-	} else {
-		m.TextInput, cmd = m.TextInput.Update(msg)
+	for _, input := range connectionFormInputs(&m) {
+		if input.Focused() {
+			*input, cmd = input.Update(msg)
+			break
+		}
 	}
 
 	return m, cmd
 }
+
+// connectionFormInputs returns the form's inputs in focus order. The replica and
+// certificate inputs are left out for drivers that do not connect over the
+// network, the SQLCipher key for drivers that do not open files, the
+// service-account key for drivers that do not sign in with one, and the Cloud
+// SQL instance for drivers Cloud SQL does not host.
+func connectionFormInputs(m *models.Model) []*textinput.Model {
+	inputs := []*textinput.Model{&m.NameInput}
+	if m.ConnectionBuilderMode {
+		inputs = append(inputs, builderInputs(m)...)
+	} else {
+		inputs = append(inputs, &m.TextInput)
+	}
+	if utils.SupportsReadReplica(m.SelectedDB.Driver) {
+		inputs = append(inputs, &m.ReplicaInput)
+	}
+	if utils.SupportsTLSOptions(m.SelectedDB.Driver) {
+		inputs = append(inputs, &m.TLSCAInput, &m.TLSCertInput, &m.TLSKeyInput)
+	}
+	if utils.SupportsSQLCipher(m.SelectedDB.Driver) {
+		inputs = append(inputs, &m.SQLCipherKeyInput)
+	}
+	if utils.SupportsServiceAccountKey(m.SelectedDB.Driver) {
+		inputs = append(inputs, &m.ServiceAccountKeyInput)
+	}
+	if utils.SupportsCloudSQL(m.SelectedDB.Driver) {
+		inputs = append(inputs, &m.CloudSQLInstanceInput)
+	}
+	return inputs
+}
+
+// applyConnectionKey writes the form's SQLCipher key, service-account key file,
+// or Cloud SQL instance into the connection string input, like the TLS options
+func applyConnectionKey(m models.Model) models.Model {
+	if utils.SupportsSQLCipher(m.SelectedDB.Driver) {
+		m.TextInput.SetValue(utils.ApplySQLCipherKey(m.TextInput.Value(), m.SQLCipherKeyInput.Value()))
+	}
+	if utils.SupportsServiceAccountKey(m.SelectedDB.Driver) {
+		m.TextInput.SetValue(utils.ApplyServiceAccountKey(m.TextInput.Value(), m.ServiceAccountKeyInput.Value()))
+	}
+	if utils.SupportsCloudSQL(m.SelectedDB.Driver) {
+		m.TextInput.SetValue(utils.ApplyCloudSQL(m.SelectedDB.Driver, m.TextInput.Value(), m.CloudSQLInstanceInput.Value(), m.CloudSQLIAM))
+	}
+	return m
+}
+
+// applyConnectionTLS writes the form's TLS options into the connection string
+// input, so the string that is tested, saved, and connected is the one shown
+func applyConnectionTLS(m models.Model) (models.Model, error) {
+	opts := models.TLSOptions{
+		Mode:     m.TLSMode,
+		CAFile:   strings.TrimSpace(m.TLSCAInput.Value()),
+		CertFile: strings.TrimSpace(m.TLSCertInput.Value()),
+		KeyFile:  strings.TrimSpace(m.TLSKeyInput.Value()),
+	}
+	if m.TextInput.Value() == "" {
+		return m, nil
+	}
+	connectionStr, err := utils.ApplyTLSOptions(m.SelectedDB.Driver, m.TextInput.Value(), opts)
+	if err != nil {
+		return m, err
+	}
+	m.TextInput.SetValue(connectionStr)
+	return m, nil
+}
+
+// builderInputs returns the builder fields of the connection form in focus
+// order. Snowflake is reached by account, so it has a warehouse and role in
+// place of a port.
+func builderInputs(m *models.Model) []*textinput.Model {
+	if m.SelectedDB.Driver == "snowflake" {
+		return []*textinput.Model{
+			&m.BuilderHostInput, &m.BuilderWarehouseInput, &m.BuilderUserInput,
+			&m.BuilderPasswordInput, &m.BuilderDatabaseInput, &m.BuilderRoleInput, &m.BuilderOptionsInput,
+		}
+	}
+	return []*textinput.Model{
+		&m.BuilderHostInput, &m.BuilderPortInput, &m.BuilderUserInput,
+		&m.BuilderPasswordInput, &m.BuilderDatabaseInput, &m.BuilderOptionsInput,
+	}
+}
+
+// applyConnectionFields assembles the connection string from the builder fields
+// into the connection string input; in raw mode the input is used as typed, and
+// empty fields leave it empty
+func applyConnectionFields(m models.Model) (models.Model, error) {
+	if !m.ConnectionBuilderMode {
+		return m, nil
+	}
+	fields := utils.FormConnectionFields(m)
+	if fields == (models.ConnectionFields{}) {
+		m.TextInput.SetValue("")
+		return m, nil
+	}
+	connectionStr, err := utils.BuildConnectionString(m.SelectedDB.Driver, fields)
+	if err != nil {
+		return m, err
+	}
+	m.TextInput.SetValue(connectionStr)
+	return m, nil
+}
+
+// toggleConnectionBuilder switches the form between the builder fields and the
+// raw connection string, carrying the connection over. A raw string that cannot
+// be split into fields keeps the form in raw mode.
+func toggleConnectionBuilder(m models.Model) (models.Model, error) {
+	nameFocused := m.NameInput.Focused()
+	for _, input := range connectionFormInputs(&m) {
+		input.Blur()
+	}
+
+	if m.ConnectionBuilderMode {
+		updated, err := applyConnectionFields(m)
+		if err != nil {
+			return m, err
+		}
+		m = updated
+		m.ConnectionBuilderMode = false
+	} else {
+		fields, err := utils.ParseConnectionString(m.SelectedDB.Driver, m.TextInput.Value())
+		if err != nil {
+			return m, fmt.Errorf("%w; fix it or clear it to use the fields", err)
+		}
+		m = utils.SetFormConnectionFields(m, fields)
+		m.ConnectionBuilderMode = true
+	}
+
+	if nameFocused {
+		m.NameInput.Focus()
+	} else {
+		connectionFormInputs(&m)[1].Focus()
+	}
+	m.Err = nil
+	return m, nil
+}
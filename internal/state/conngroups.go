@@ -0,0 +1,76 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleConnectionGroupViewUpdate handles all updates for the ConnectionGroupView state.
+func HandleConnectionGroupViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Leave without saving
+			m = utils.PopView(m, models.SavedConnectionsView)
+			m.ConnectionGroupInput.Blur()
+			m.ConnectionTagsInput.Blur()
+			m.Err = nil
+			return m, nil
+
+		case "tab", "shift+tab":
+			// Switch between the group and tags inputs
+			if m.ConnectionGroupInput.Focused() {
+				m.ConnectionGroupInput.Blur()
+				m.ConnectionTagsInput.Focus()
+			} else {
+				m.ConnectionTagsInput.Blur()
+				m.ConnectionGroupInput.Focus()
+			}
+			return m, nil
+
+		case "enter":
+			group := strings.TrimSpace(m.ConnectionGroupInput.Value())
+			updated, err := utils.SetConnectionGroup(m, m.GroupEditConnection, group, utils.ParseTags(m.ConnectionTagsInput.Value()))
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			updated = utils.PopView(updated, models.SavedConnectionsView)
+			updated.ConnectionGroupInput.Blur()
+			updated.ConnectionTagsInput.Blur()
+			updated.Err = nil
+			updated.QueryResult = fmt.Sprintf("📁 Filed '%s' under %s", m.GroupEditConnection, group)
+			if group == "" {
+				updated.QueryResult = fmt.Sprintf("📁 '%s' is no longer in a group", m.GroupEditConnection)
+			}
+			return updated, utils.ClearResultAfterTimeout()
+		}
+	}
+
+	if m.ConnectionTagsInput.Focused() {
+		m.ConnectionTagsInput, cmd = m.ConnectionTagsInput.Update(msg)
+	} else {
+		m.ConnectionGroupInput, cmd = m.ConnectionGroupInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// startGroupEdit opens the group and tags prompt for a saved connection
+func startGroupEdit(m models.Model, conn models.SavedConnection) models.Model {
+	m.GroupEditConnection = conn.Name
+	m.ConnectionGroupInput.SetValue(conn.Group)
+	m.ConnectionGroupInput.CursorEnd()
+	m.ConnectionGroupInput.Focus()
+	m.ConnectionTagsInput.SetValue(strings.Join(conn.Tags, ", "))
+	m.ConnectionTagsInput.Blur()
+	m = utils.PushView(m, models.ConnectionGroupView, models.NavParams{})
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
@@ -0,0 +1,118 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleCopyDataViewUpdate handles all updates for the CopyDataView state.
+func HandleCopyDataViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.CopyStep == models.CopyEnterTable {
+			m.CopyTableInput, cmd = m.CopyTableInput.Update(msg)
+		}
+		return m, cmd
+	}
+
+	// In safe mode and into a production target writing rows needs an explicit override
+	if m.IsConfirmingSafeOverride {
+		m.IsConfirmingSafeOverride = false
+		if keyMsg.String() == "y" {
+			return startTableCopy(m)
+		}
+		m.QueryResult = fmt.Sprintf("Copy cancelled (%s)", utils.CopyConfirmLabel(m))
+		return m, utils.ClearResultAfterTimeout()
+	}
+
+	switch m.CopyStep {
+	case models.CopyChooseTarget:
+		switch keyMsg.String() {
+		case "esc":
+			return leaveCopyView(m), nil
+		case "up", "k":
+			if m.CopyTargetIndex > 0 {
+				m.CopyTargetIndex--
+			}
+		case "down", "j":
+			if m.CopyTargetIndex < len(m.SavedConnections)-1 {
+				m.CopyTargetIndex++
+			}
+		case "enter":
+			if target := m.SavedConnections[m.CopyTargetIndex]; target.ReadOnly {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("'%s' is read-only: choose another connection to copy into", target.Name), 3*time.Second)
+			}
+			m.CopyStep = models.CopyEnterTable
+			m.CopyTableInput.SetValue(m.CopySourceTable)
+			m.CopyTableInput.CursorEnd()
+			m.CopyTableInput.Focus()
+		}
+		return m, nil
+
+	case models.CopyEnterTable:
+		switch keyMsg.String() {
+		case "esc":
+			m.CopyStep = models.CopyChooseTarget
+			m.CopyTableInput.Blur()
+			m.Err = nil
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(m.CopyTableInput.Value()) == "" {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("enter the target table name"), 3*time.Second)
+			}
+			if utils.CopyConfirmsWrites(m) {
+				m.IsConfirmingSafeOverride = true
+				m.Err = nil
+				m.QueryResult = ""
+				return m, nil
+			}
+			return startTableCopy(m)
+		}
+		m.CopyTableInput, cmd = m.CopyTableInput.Update(msg)
+		return m, cmd
+
+	default: // CopyRunning
+		if keyMsg.String() == "esc" && !m.IsCopyingData {
+			return leaveCopyView(m), nil
+		}
+		return m, nil
+	}
+}
+
+// leaveCopyView returns to the tables view
+func leaveCopyView(m models.Model) models.Model {
+	m = utils.PopView(m, models.TablesView)
+	m.CopyTableInput.Blur()
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
+
+// startTableCopy begins streaming rows into the chosen target table
+func startTableCopy(m models.Model) (models.Model, tea.Cmd) {
+	if m.CopyTargetIndex < 0 || m.CopyTargetIndex >= len(m.SavedConnections) {
+		return utils.SetErrorWithTimeout(m, fmt.Errorf("choose a target connection"), 3*time.Second)
+	}
+	target := m.SavedConnections[m.CopyTargetIndex]
+	schema, table := utils.ParseTargetTable(m.CopyTableInput.Value(), target.Driver)
+	if target.ConnectionStr == m.ConnectionStr && table == m.CopySourceTable && schema == m.SelectedSchema {
+		return utils.SetErrorWithTimeout(m, fmt.Errorf("the target table is the source table"), 3*time.Second)
+	}
+
+	ch, cmd := utils.StartTableCopy(m.DB, m.SelectedDB, m.SelectedSchema, m.CopySourceTable, target, schema, table)
+	m.CopyProgressChan = ch
+	m.CopyProgress = models.CopyProgressMsg{Total: -1}
+	m.CopyStep = models.CopyRunning
+	m.CopyTableInput.Blur()
+	m.IsCopyingData = true
+	m.Err = nil
+	m.QueryResult = ""
+	return m, cmd
+}
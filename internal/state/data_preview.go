@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,7 +21,7 @@ var (
 )
 
 // HandleDataPreviewViewUpdate handles all updates for the DataPreviewView state.
-// Note: The 'enter' key to switch to RowDetailView is handled in main.go due to a dependency on the FieldItemDelegate.
+// Note: The 'enter' key to switch to RowDetailView is handled in screens.go due to a dependency on the FieldItemDelegate.
 func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -32,19 +34,63 @@ func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea
 				m.DataPreviewFilterValue = m.DataPreviewFilterInput.Value()
 				m.DataPreviewFilterActive = false
 				m.DataPreviewFilterInput.Blur()
+				m = utils.ResetFilterCount(m)
 				m.DataPreviewCurrentPage = 0 // Reset to first page
-				return m, utils.LoadDataPreviewWithFilter(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewFilterValue, m.DataPreviewAllColumns, m.DataPreviewSortDirection, m.DataPreviewSortColumn)
+				routed, db := utils.RouteRead(m)
+				return routed, utils.LoadDataPreviewWithFilter(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewFilterValue, utils.PreviewTableColumns(m), utils.PreviewSortKeys(m), utils.PreviewJSONColumns(m))
 			case "esc":
 				// Cancel filter
 				m.DataPreviewFilterActive = false
 				m.DataPreviewFilterInput.Blur()
 				m.DataPreviewFilterInput.SetValue("")
+				m = utils.ResetFilterCount(m)
 				return m, nil
 			default:
-				// Update filter input
+				// Update filter input, counting its matches once typing pauses
+				before := m.DataPreviewFilterInput.Value()
 				m.DataPreviewFilterInput, cmd = m.DataPreviewFilterInput.Update(msg)
-				return m, cmd
+				if m.DataPreviewFilterInput.Value() == before {
+					return m, cmd
+				}
+				var countCmd tea.Cmd
+				m, countCmd = utils.ScheduleFilterCount(m)
+				return m, tea.Batch(cmd, countCmd)
+			}
+		}
+
+		// The JSON path prompt captures input while open
+		if m.JSONColumnActive {
+			switch keyMsg.String() {
+			case "enter":
+				// Add the path as a column, or with nothing typed remove the table's path columns
+				m.JSONColumnActive = false
+				m.JSONColumnInput.Blur()
+				tableColumns := utils.PreviewTableColumns(m)
+				if strings.TrimSpace(m.JSONColumnInput.Value()) == "" {
+					if len(utils.PreviewJSONColumns(m)) == 0 {
+						return m, nil
+					}
+					m = utils.ClearJSONColumns(m)
+					m.QueryResult = "Removed the JSON path columns of " + m.SelectedTable
+					routed, loadCmd := utils.ReloadPreviewColumns(m, tableColumns)
+					return routed, tea.Batch(loadCmd, utils.ClearResultAfterTimeout())
+				}
+				updated, col, err := utils.AddJSONColumn(m, m.JSONColumnInput.Value())
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				m = updated
+				m.JSONColumnInput.SetValue("")
+				m.QueryResult = fmt.Sprintf("{} Added %s, read from %s", col.Name, col.Column)
+				routed, loadCmd := utils.ReloadPreviewColumns(m, tableColumns)
+				return routed, tea.Batch(loadCmd, utils.ClearResultAfterTimeout())
+			case "esc":
+				m.JSONColumnActive = false
+				m.JSONColumnInput.Blur()
+				return m, nil
 			}
+			m.JSONColumnInput, cmd = m.JSONColumnInput.Update(msg)
+			return m, cmd
 		}
 
 		// Handle sort mode if not in filter mode
@@ -94,9 +140,11 @@ func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea
 					m.DataPreviewSortDirection = models.SortOff
 					m.DataPreviewSortColumn = ""
 				}
+				m = utils.SetPreviewSortKeys(m, utils.PreviewSortKeys(m))
 				m.DataPreviewSortMode = false
 				m.DataPreviewCurrentPage = 0 // Reset page when sorting changes
-				return m, utils.LoadDataPreviewWithSort(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn, m.DataPreviewFilterValue, m.DataPreviewAllColumns, m.DataPreviewTotalRows)
+				routed, db := utils.RouteRead(m)
+				return routed, utils.LoadDataPreviewWithSort(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, utils.PreviewSortKeys(m), m.DataPreviewFilterValue, utils.PreviewTableColumns(m), m.DataPreviewTotalRows, utils.PreviewJSONColumns(m))
 			case "esc":
 				// Exit sort mode
 				m.DataPreviewSortMode = false
@@ -109,13 +157,55 @@ func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea
 		switch keyMsg.String() {
 		case "esc":
 			// Go back to the tables view
-			m.State = models.TablesView
+			if m.TempResultTable != "" {
+				m.QueryResult = fmt.Sprintf("Temporary table %s stays queryable until you disconnect", m.TempResultTable)
+				m = utils.LeaveTempResult(m)
+				m = utils.PopView(m, models.TablesView)
+				return m, utils.ClearResultAfterTimeout()
+			}
+			m = utils.PopView(m, models.TablesView)
 			return m, nil
 		case "/":
 			// Start filter mode
 			m.DataPreviewFilterActive = true
 			m.DataPreviewFilterInput.Focus()
 			return m, nil
+		case "J":
+			// Add a column read from a path inside a JSON column
+			if !models.DriverCapabilities(m.SelectedDB.Driver).JSONPaths {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("JSON path columns are not supported for %s", m.SelectedDB.Driver), 3*time.Second)
+			}
+			m.JSONColumnActive = true
+			m.JSONColumnInput.Focus()
+			return m, nil
+		case "c":
+			// Clear the applied filter and reload every row
+			if m.DataPreviewFilterValue == "" {
+				return m, nil
+			}
+			m.DataPreviewFilterValue = ""
+			m.DataPreviewFilterInput.SetValue("")
+			m.DataPreviewCurrentPage = 0
+			routed, db := utils.RouteRead(m)
+			return routed, utils.LoadDataPreview(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, utils.PreviewSortKeys(m), utils.PreviewJSONColumns(m))
+		case "p":
+			// Filter the first visible column to exactly the value on the clipboard
+			column := utils.PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)
+			if column == "" {
+				return m, nil
+			}
+			if utils.IsJSONColumn(m, column) {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("%s is a JSON path column; filter on its table column with /", column), 3*time.Second)
+			}
+			text, err := clipboard.ReadAll()
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("failed to read the clipboard: %w", err), 3*time.Second)
+			}
+			value, err := utils.PastedFilterValue(text)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			return utils.ApplyColumnFilter(m, column, value)
 		case "s":
 			// Start sort mode
 			if len(m.DataPreviewAllColumns) == 0 {
@@ -125,14 +215,109 @@ func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea
 			// Don't auto-select a column if nothing is currently sorted
 			// This makes the initial state clearer for navigation
 			return m, nil
+		case "o":
+			// Sort by the first visible column in one step, cycling asc → desc → off
+			column := utils.PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)
+			if column == "" {
+				return m, nil
+			}
+			m.DataPreviewSortColumn, m.DataPreviewSortDirection = utils.CycleColumnSort(m.DataPreviewSortColumn, m.DataPreviewSortDirection, column)
+			m = utils.SetPreviewSortKeys(m, utils.PreviewSortKeys(m))
+			m.DataPreviewCurrentPage = 0 // Reset page when sorting changes
+			routed, db := utils.RouteRead(m)
+			return routed, utils.LoadDataPreviewWithSort(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, utils.PreviewSortKeys(m), m.DataPreviewFilterValue, utils.PreviewTableColumns(m), m.DataPreviewTotalRows, utils.PreviewJSONColumns(m))
+		case "O":
+			// Add the first visible column as the next sort key, cycling asc → desc → removed
+			column := utils.PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)
+			if column == "" {
+				return m, nil
+			}
+			m = utils.SetPreviewSortKeys(m, utils.CycleSortKey(utils.PreviewSortKeys(m), column))
+			m.DataPreviewCurrentPage = 0 // Reset page when sorting changes
+			routed, db := utils.RouteRead(m)
+			return routed, utils.LoadDataPreviewWithSort(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, utils.PreviewSortKeys(m), m.DataPreviewFilterValue, utils.PreviewTableColumns(m), m.DataPreviewTotalRows, utils.PreviewJSONColumns(m))
+		case "U":
+			// Draft an UPDATE limited to the rows the active filter matches
+			if m.ReadOnly {
+				return utils.SetErrorWithTimeout(m, utils.ReadOnlyBlocked("drafting an UPDATE"), 3*time.Second)
+			}
+			if m.DataPreviewFilterValue == "" {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("apply a filter (/) before drafting a bulk UPDATE"), 3*time.Second)
+			}
+			column := utils.DraftUpdateColumn(utils.PreviewTableColumns(m), m.DataPreviewScrollOffset)
+			draft, err := utils.DraftFilteredUpdate(m.SelectedDB.Driver, m.SelectedSchema, m.SelectedTable, m.DataPreviewFilterValue, utils.PreviewTableColumns(m), utils.PreviewJSONColumns(m), column)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			m.QueryInput.SetValue(draft)
+			m.QueryInput.CursorEnd()
+			m.QueryInput.Focus()
+			m.HasDraftedUpdate = true
+			m.DraftedUpdateRows = m.DataPreviewTotalRows
+			m.QueryResult = ""
+			m.Err = nil
+			m = utils.PushView(m, models.QueryView, models.NavParams{})
+			return m, nil
+		case "Z":
+			// Cycle the session display timezone for timestamp values
+			m.DisplayTimezone = utils.NextDisplayTimezone(m.DisplayTimezone)
+			m = utils.CreateDataPreviewTable(m)
+			return m, nil
+		case "H":
+			// Humanize the first visible column as durations, cycling its units
+			column := utils.PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)
+			if column == "" {
+				return m, nil
+			}
+			updated, err := utils.ToggleDurationColumn(m, column)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			updated = utils.CreateDataPreviewTable(updated)
+			return updated, nil
+		case ",":
+			// Toggle thousands separators in numeric columns
+			m.GroupNumberDigits = !m.GroupNumberDigits
+			m = utils.CreateDataPreviewTable(m)
+			return m, nil
+		case "A":
+			// Aggregate the first visible numeric column over every row the filter matches
+			return utils.StartFullAggregate(m)
+		case "N":
+			// Toggle the NULL and distinct counts of the loaded page
+			m.DataPreviewShowSummary = !m.DataPreviewShowSummary
+			m = utils.CreateDataPreviewTable(m)
+			return m, nil
+		case "w":
+			// Pin the row under the cursor to the watchlist, or unpin it
+			cursor := m.DataPreviewTable.Cursor()
+			if cursor < 0 || cursor >= len(m.DataPreviewAllRows) {
+				return m, nil
+			}
+			var nulls []bool
+			if cursor < len(m.DataPreviewNulls) {
+				nulls = m.DataPreviewNulls[cursor]
+			}
+			return utils.ToggleWatchedRow(m, m.DataPreviewAllColumns, m.DataPreviewAllRows[cursor], nulls)
+		case "W":
+			// Open the watchlist of pinned rows
+			return utils.OpenWatchlist(m)
+		case "m":
+			// Mark the row under the cursor to diff against another row
+			return utils.ToggleDiffMark(m)
+		case "=":
+			// Diff the marked row against the row under the cursor
+			return utils.StartRowDiff(m)
 		case "ctrl+r":
 			// Reload/refresh data preview
-			return m, utils.LoadDataPreview(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn)
+			routed, db := utils.RouteRead(m)
+			return routed, utils.LoadDataPreview(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, utils.PreviewSortKeys(m), utils.PreviewJSONColumns(m))
 		case "left":
 			// Previous page
 			if m.DataPreviewCurrentPage > 0 {
 				m.DataPreviewCurrentPage--
-				return m, utils.LoadDataPreviewWithPagination(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn, m.DataPreviewFilterValue, m.DataPreviewAllColumns, m.DataPreviewTotalRows)
+				routed, db := utils.RouteRead(m)
+				return routed, utils.LoadDataPreviewWithPagination(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, utils.PreviewSortKeys(m), m.DataPreviewFilterValue, utils.PreviewTableColumns(m), m.DataPreviewTotalRows, utils.PreviewJSONColumns(m))
 			}
 			return m, nil
 		case "right":
@@ -140,7 +325,8 @@ func HandleDataPreviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea
 			totalPages := utils.CalculateTotalPages(m.DataPreviewTotalRows, m.DataPreviewItemsPerPage)
 			if m.DataPreviewCurrentPage < totalPages-1 {
 				m.DataPreviewCurrentPage++
-				return m, utils.LoadDataPreviewWithPagination(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn, m.DataPreviewFilterValue, m.DataPreviewAllColumns, m.DataPreviewTotalRows)
+				routed, db := utils.RouteRead(m)
+				return routed, utils.LoadDataPreviewWithPagination(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, utils.PreviewSortKeys(m), m.DataPreviewFilterValue, utils.PreviewTableColumns(m), m.DataPreviewTotalRows, utils.PreviewJSONColumns(m))
 			}
 			return m, nil
 		case "h":
@@ -184,18 +370,35 @@ func (d FieldItemDelegate) Render(w io.Writer, m list.Model, index int, it list.
 		return
 	}
 
-	// Determine type
-	t := utils.InferFieldType(fi.Value)
+	// Determine type, preferring the type the database reported
+	t := utils.FieldTypeLabel(fi.Value, fi.Type, fi.IsNull)
+	value := fi.Value
+	if fi.Display != "" {
+		value = fi.Display
+	}
+	// Keep NULL and empty text apart from the text "NULL" and a blank value
+	if fi.IsNull {
+		value = "(NULL)"
+	} else if value == "" {
+		value = `""`
+	}
 
 	// Compose the display string: Name: value [Type]
 	namePart := fi.Name + ": "
 	badge := styles.TypeBadgeStyle.Render("[" + t + "]")
-	single := utils.SanitizeValueForDisplay(fi.Value)
+	if utils.HasInvalidUTF8(value) {
+		badge = styles.WarningStyle.Render("["+utils.InvalidUTF8Badge+"]") + " " + badge
+	}
+	single := utils.SanitizeValueForDisplay(utils.SafeDisplayText(value))
 
 	// Calculate budget for value to fit within width
 	budget := width - lipgloss.Width(namePart) - 1 - lipgloss.Width(badge)
 	budget = utils.Max(budget, 0)
 	val := utils.TruncateWithEllipsis(single, budget, "...")
+	// UUIDs and ULIDs stand out from other text; the selected row keeps its own style
+	if _, ok := utils.DetectIdentifier(fi.Value); ok && !fi.IsNull && index != m.Index() {
+		val = styles.IdentifierStyle.Render(val)
+	}
 
 	str := namePart + val + " " + badge
 
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleEditConnectionViewUpdate handles all updates for the EditConnectionView state.
+func HandleEditConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Leave without saving
+			m = utils.PopView(m, models.SavedConnectionsView)
+			m = endConnectionEdit(m)
+			m.Err = nil
+			return m, nil
+
+		case "tab", "shift+tab":
+			if m.NameInput.Focused() {
+				m.NameInput.Blur()
+				m.TextInput.Focus()
+			} else {
+				m.TextInput.Blur()
+				m.NameInput.Focus()
+			}
+			return m, nil
+
+		case "enter":
+			updated, warnings, err := utils.EditSavedConnection(m, m.EditingConnectionIdx, m.NameInput.Value(), m.TextInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			name := updated.SavedConnections[m.EditingConnectionIdx].Name
+			updated = utils.PopView(updated, models.SavedConnectionsView)
+			updated = endConnectionEdit(updated)
+			updated.Err = nil
+			updated.QueryResult = fmt.Sprintf("✅ Saved changes to '%s'", name)
+			if message := utils.ConnectionWarningsMessage(name, warnings); message != "" {
+				updated.QueryResult = message
+			}
+			return updated, utils.ClearResultAfterTimeout()
+		}
+	}
+
+	if m.NameInput.Focused() {
+		m.NameInput, cmd = m.NameInput.Update(msg)
+	} else {
+		m.TextInput, cmd = m.TextInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// startConnectionEdit opens the edit form on a saved connection. A connection
+// string that is still encrypted or in an unreachable keychain can't be edited.
+func startConnectionEdit(m models.Model, name string) (models.Model, error) {
+	for i, conn := range m.SavedConnections {
+		if conn.Name != name {
+			continue
+		}
+		if config.IsEncrypted(conn.ConnectionStr) {
+			return m, fmt.Errorf("the connection string of '%s' is encrypted: enter the master passphrase with p first", conn.Name)
+		}
+		connectionStr, err := utils.SavedConnectionString(conn)
+		if err != nil {
+			return m, err
+		}
+		for _, db := range models.SupportedDatabaseTypes {
+			if db.Driver == conn.Driver {
+				m.SelectedDB = db
+				break
+			}
+		}
+		m.EditingConnectionIdx = i
+		m.NameInput.SetValue(conn.Name)
+		m.NameInput.CursorEnd()
+		m.NameInput.Focus()
+		m.TextInput.SetValue(connectionStr)
+		m.TextInput.CursorEnd()
+		m.TextInput.Blur()
+		m = utils.PushView(m, models.EditConnectionView, models.NavParams{})
+		m.Err = nil
+		m.QueryResult = ""
+		return m, nil
+	}
+	return m, fmt.Errorf("connection '%s' not found", name)
+}
+
+// endConnectionEdit clears the edit form so no connection string is left behind
+func endConnectionEdit(m models.Model) models.Model {
+	m.EditingConnectionIdx = -1
+	m.NameInput.SetValue("")
+	m.NameInput.Blur()
+	m.TextInput.SetValue("")
+	m.TextInput.Blur()
+	return m
+}
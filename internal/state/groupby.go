@@ -0,0 +1,55 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleGroupSummaryViewUpdate handles all updates for the GroupSummaryView state.
+func HandleGroupSummaryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the columns view
+			m = utils.PopView(m, models.ColumnsView)
+			m.Err = nil
+			return m, nil
+
+		case "a":
+			// Aggregate the next numeric column per value, then none
+			if m.IsLoadingGroups {
+				return m, nil
+			}
+			numeric := utils.NumericColumns(m.ColumnDefinitions)
+			if len(numeric) == 0 {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("%s has no numeric column to aggregate", m.SelectedTable), 3*time.Second)
+			}
+			m.GroupAggregateColumn = utils.NextAggregateColumn(numeric, m.GroupAggregateColumn)
+			return utils.ReloadGroupSummary(m)
+
+		case "f":
+			// Switch the aggregate function
+			if !m.IsLoadingGroups && m.GroupAggregateColumn != "" {
+				m.GroupAggregate = utils.NextChartOption(utils.GroupAggregates, m.GroupAggregate)
+				return utils.ReloadGroupSummary(m)
+			}
+			return m, nil
+
+		case "ctrl+r":
+			// Count again
+			if !m.IsLoadingGroups {
+				return utils.ReloadGroupSummary(m)
+			}
+			return m, nil
+		}
+	}
+
+	m.GroupSummaryTable, cmd = m.GroupSummaryTable.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,34 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleTableGrowthViewUpdate handles all updates for the TableGrowthView state.
+func HandleTableGrowthViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			return m, nil
+
+		case "ctrl+r":
+			// Reload sizes and compare against the latest snapshot again
+			if !m.IsLoadingGrowth {
+				m.IsLoadingGrowth = true
+				m.Err = nil
+				return m, utils.LoadTableGrowth(m.DB, m.SelectedDB, m.ConnectionStr, m.SelectedSchema)
+			}
+			return m, nil
+		}
+	}
+
+	m.TableGrowthTable, cmd = m.TableGrowthTable.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,33 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleConnectionHealthViewUpdate handles all updates for the ConnectionHealthView state.
+func HandleConnectionHealthViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the saved connections
+			m = utils.PopView(m, models.SavedConnectionsView)
+			m.Err = nil
+			return m, nil
+
+		case "ctrl+r":
+			// Ping every saved connection again
+			if !m.IsCheckingHealth {
+				m.IsCheckingHealth = true
+				return m, utils.CheckConnectionHealth(m.SavedConnections)
+			}
+			return m, nil
+		}
+	}
+
+	m.ConnectionHealthTable, cmd = m.ConnectionHealthTable.Update(msg)
+	return m, cmd
+}
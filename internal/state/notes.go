@@ -0,0 +1,56 @@
+package state
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleNoteEditViewUpdate handles all updates for the NoteEditView state.
+func HandleNoteEditViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Leave without saving
+			m = utils.PopView(m, models.TablesView)
+			m.NoteInput.Blur()
+			m.Err = nil
+			return m, nil
+
+		case "enter":
+			updated, err := utils.SaveNote(m)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			updated = utils.PopView(updated, models.TablesView)
+			updated.NoteInput.Blur()
+			updated.Err = nil
+			if updated.NoteInput.Value() == "" {
+				updated.QueryResult = "📝 Note removed"
+			} else {
+				updated.QueryResult = "📝 Note saved"
+			}
+			return updated, utils.ClearResultAfterTimeout()
+		}
+	}
+
+	m.NoteInput, cmd = m.NoteInput.Update(msg)
+	return m, cmd
+}
+
+// startNoteEdit opens the note editor for a table, or for a saved connection when table is empty
+func startNoteEdit(m models.Model, table, connectionName, note string) models.Model {
+	m.NoteTable = table
+	m.NoteConnectionName = connectionName
+	m.NoteInput.SetValue(note)
+	m.NoteInput.CursorEnd()
+	m.NoteInput.Focus()
+	m = utils.PushView(m, models.NoteEditView, models.NavParams{Schema: m.SelectedSchema, Table: table})
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
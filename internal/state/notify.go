@@ -0,0 +1,103 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleNotifyViewUpdate handles all updates for the NotifyView state.
+func HandleNotifyViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.NotifyChannelInput.Focused() {
+			m.NotifyChannelInput, cmd = m.NotifyChannelInput.Update(msg)
+		}
+		return m, cmd
+	}
+
+	// The channel prompt takes typing until the monitor starts
+	if m.NotifyChannelInput.Focused() {
+		switch keyMsg.String() {
+		case "esc":
+			return leaveNotifyView(m), nil
+		case "enter":
+			channels, err := utils.ParseNotifyChannels(m.NotifyChannelInput.Value())
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			m.NotifyChannels = channels
+			m.NotifyChannelInput.Blur()
+			m.NotifyStream, m.NotifyStop, cmd = utils.StartNotificationMonitor(m.ConnectionStr, channels, m.NotifySeq)
+			m.IsListening = true
+			m.NotifyErr = nil
+			m.Err = nil
+			return utils.RefreshNotificationsTable(m), cmd
+		}
+		m.NotifyChannelInput, cmd = m.NotifyChannelInput.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		return leaveNotifyView(m), nil
+
+	case "e":
+		// Stop and change the channels listened on
+		m = utils.StopNotificationMonitor(m)
+		m.NotifyChannelInput.SetValue(strings.Join(m.NotifyChannels, ", "))
+		m.NotifyChannelInput.CursorEnd()
+		m.NotifyChannelInput.Focus()
+		return m, nil
+
+	case "x":
+		// Clear the received notifications
+		m.Notifications = nil
+		return utils.RefreshNotificationsTable(m), nil
+
+	case "c":
+		// Copy the selected notification's payload to the clipboard
+		if i := m.NotifyTable.Cursor(); i >= 0 && i < len(m.Notifications) {
+			if err := clipboard.WriteAll(m.Notifications[i].Payload); err != nil {
+				m.Err = fmt.Errorf("failed to copy to clipboard: %w", err)
+				return m, nil
+			}
+			m.QueryResult = "✅ Copied payload to clipboard"
+			return m, utils.ClearResultAfterTimeout()
+		}
+		return m, nil
+	}
+
+	// Let the table handle navigation keys
+	m.NotifyTable, cmd = m.NotifyTable.Update(msg)
+	return m, cmd
+}
+
+// startNotifyMonitor opens the monitor on its channel prompt, keeping the
+// notifications of an earlier run
+func startNotifyMonitor(m models.Model) models.Model {
+	m.NotifyChannelInput.SetValue(strings.Join(m.NotifyChannels, ", "))
+	m.NotifyChannelInput.CursorEnd()
+	m.NotifyChannelInput.Focus()
+	m = utils.RefreshNotificationsTable(m)
+	m = utils.PushView(m, models.NotifyView, models.NavParams{})
+	m.Err = nil
+	m.QueryResult = ""
+	return m
+}
+
+// leaveNotifyView stops the monitor and returns to the tables
+func leaveNotifyView(m models.Model) models.Model {
+	m = utils.StopNotificationMonitor(m)
+	m.NotifyChannelInput.Blur()
+	m = utils.PopView(m, models.TablesView)
+	m.Err = nil
+	return m
+}
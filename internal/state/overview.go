@@ -0,0 +1,83 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleDatabaseOverviewViewUpdate handles all updates for the DatabaseOverviewView state.
+func HandleDatabaseOverviewViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// A pending maintenance action needs an explicit confirmation
+		if m.IsConfirmingMaintenance {
+			action := m.MaintenanceAction
+			m.IsConfirmingMaintenance = false
+			if keyMsg.String() == "y" {
+				m.IsRunningMaintenance = true
+				m.MaintenanceStartedAt = time.Now()
+				m.Err = nil
+				m.QueryResult = ""
+				return m, tea.Batch(
+					utils.RunMaintenanceAction(m.DB, m.SelectedDB, action, m.MaintenanceSchema, m.MaintenanceTable),
+					utils.MaintenanceTick(),
+				)
+			}
+			if utils.ConfirmsWrites(m) {
+				m.QueryResult = fmt.Sprintf("%s cancelled (%s)", action, utils.WriteConfirmLabel(m))
+			} else {
+				m.QueryResult = fmt.Sprintf("%s cancelled", action)
+			}
+			return m, utils.ClearResultAfterTimeout()
+		}
+
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "ctrl+r":
+			// Refresh the statistics
+			if !m.IsLoadingOverview {
+				m.IsLoadingOverview = true
+				m.Err = nil
+				return m, utils.LoadDatabaseOverview(m.DB, m.SelectedDB, m.SelectedSchema)
+			}
+			return m, nil
+
+		case "a", "V":
+			// Ask for confirmation before running a maintenance action on the selected table
+			if m.IsRunningMaintenance {
+				return m, nil
+			}
+			action := utils.MaintenanceActionForKey(m.SelectedDB.Driver, keyMsg.String())
+			cursor := m.OverviewTable.Cursor()
+			if action == "" || cursor < 0 || cursor >= len(m.DatabaseOverview.Tables) {
+				return m, nil
+			}
+			if m.ReadOnly {
+				return utils.SetErrorWithTimeout(m, utils.ReadOnlyBlocked(action), 3*time.Second)
+			}
+			selected := m.DatabaseOverview.Tables[cursor]
+			m.MaintenanceAction = action
+			m.IsConfirmingMaintenance = true
+			m.MaintenanceTable = selected.Name
+			m.MaintenanceSchema = selected.Schema
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+		}
+	}
+
+	// Let the table handle navigation keys
+	m.OverviewTable, cmd = m.OverviewTable.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandlePassphraseViewUpdate handles all updates for the PassphraseView state.
+func HandlePassphraseViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.IsApplyingPassphrase {
+			return m, nil
+		}
+
+		switch keyMsg.String() {
+		case "esc":
+			if !m.IsSettingPassphrase {
+				// The saved connections cannot be read or safely rewritten while locked
+				return m, tea.Quit
+			}
+			m = utils.PopView(m, models.SavedConnectionsView)
+			m.PassphraseInput.Blur()
+			m.PassphraseConfirmInput.Blur()
+			m.Err = nil
+			return m, nil
+
+		case "tab", "shift+tab":
+			if m.IsSettingPassphrase {
+				if m.PassphraseInput.Focused() {
+					m.PassphraseInput.Blur()
+					m.PassphraseConfirmInput.Focus()
+				} else {
+					m.PassphraseConfirmInput.Blur()
+					m.PassphraseInput.Focus()
+				}
+			}
+			return m, nil
+
+		case "enter":
+			if !m.IsSettingPassphrase {
+				if m.PassphraseInput.Value() == "" {
+					return m, nil
+				}
+				m.IsApplyingPassphrase = true
+				m.Err = nil
+				return m, utils.UnlockSavedConnections(m.LockedConnections, m.PassphraseInput.Value())
+			}
+
+			// Enter on the first field moves on to the confirmation
+			if m.PassphraseInput.Focused() {
+				m.PassphraseInput.Blur()
+				m.PassphraseConfirmInput.Focus()
+				return m, nil
+			}
+			if err := utils.CheckNewPassphrase(m.PassphraseInput.Value(), m.PassphraseConfirmInput.Value()); err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			m.IsApplyingPassphrase = true
+			m.Err = nil
+			return m, utils.SetConnectionsPassphrase(m.SavedConnections, m.PassphraseInput.Value())
+		}
+	}
+
+	if m.PassphraseConfirmInput.Focused() {
+		m.PassphraseConfirmInput, cmd = m.PassphraseConfirmInput.Update(msg)
+	} else {
+		m.PassphraseInput, cmd = m.PassphraseInput.Update(msg)
+	}
+	return m, cmd
+}
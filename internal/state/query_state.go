@@ -1,7 +1,9 @@
 package state
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/models"
@@ -14,29 +16,182 @@ func HandleQueryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd)
 
 	// Handle key messages
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// In safe mode and on production a write statement needs an explicit override before it runs
+		if m.IsConfirmingSafeOverride {
+			m.IsConfirmingSafeOverride = false
+			if keyMsg.String() == "y" {
+				return utils.RunCheckedQuery(m, strings.TrimSpace(m.QueryInput.Value()))
+			}
+			m.QueryResult = fmt.Sprintf("Write cancelled (%s)", utils.WriteConfirmLabel(m))
+			return m, utils.ClearResultAfterTimeout()
+		}
+
+		// A SELECT with a large estimate runs as is, with a LIMIT added, or not at all
+		if m.IsConfirmingCost {
+			m.IsConfirmingCost = false
+			switch keyMsg.String() {
+			case "y":
+				return utils.RunCheckedQuery(m, m.CostCheckQuery)
+			case "l":
+				query := utils.AddQueryLimit(m.CostCheckQuery, utils.CostCheckLimit)
+				m.QueryInput.SetValue(query)
+				m.QueryInput.CursorEnd()
+				return utils.RunCheckedQuery(m, query)
+			}
+			m.QueryResult = "Query cancelled"
+			return m, utils.ClearResultAfterTimeout()
+		}
+
+		// The values of the query's placeholders are typed one at a time before it runs
+		if m.IsBindingParams {
+			switch keyMsg.String() {
+			case "enter":
+				updated, cmd, done := utils.NextBindParam(m)
+				if done {
+					return runEditorQuery(updated, true)
+				}
+				return updated, cmd
+			case "shift+tab", "up":
+				return utils.PreviousBindParam(m)
+			case "esc":
+				m = utils.CancelBinding(m)
+				m.QueryResult = "Query cancelled"
+				return m, utils.ClearResultAfterTimeout()
+			default:
+				m.BindInput, cmd = m.BindInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// Cancel the statement this session is running; no other session is ever touched.
+		// Esc and ctrl+c cancel too rather than leaving the editor or quitting.
+		if key := keyMsg.String(); m.IsExecutingQuery && (key == "ctrl+k" || key == "esc" || key == "ctrl+c") {
+			if !m.IsCancellingStatement {
+				m.IsCancellingStatement = true
+				return m, utils.CancelRunningStatement(m)
+			}
+			return m, nil
+		}
+
+		// The completion popup takes the keys that move through and pick its candidates
+		if m.CompletionActive {
+			switch keyMsg.String() {
+			case "tab", "down":
+				return utils.MoveCompletion(m, 1), nil
+			case "shift+tab", "up":
+				return utils.MoveCompletion(m, -1), nil
+			case "enter":
+				return utils.AcceptCompletion(m), nil
+			case "esc":
+				return utils.CloseCompletions(m), nil
+			}
+		}
+
 		switch keyMsg.String() {
 		case "esc":
-			// Go back to the data preview view
-			m.State = models.DataPreviewView
+			// Go back to where the query editor was opened
+			m = utils.PopView(m, models.TablesView)
 			m.Err = nil
 			m.QueryResult = ""
+			m.HasDraftedUpdate = false
 			return m, nil
 
 		case "enter":
-			// Execute the SQL query
-			if !m.IsExecutingQuery {
-				query := strings.TrimSpace(m.QueryInput.Value())
-				if query != "" {
-					m.IsExecutingQuery = true
-					m.Err = nil
-					m.QueryResult = ""
-					return m, utils.ExecuteQuery(m.DB, m.SelectedDB, query)
-				}
+			// Execute the SQL query; alt+enter reaches the editor as a new line
+			if !m.IsExecutingQuery && !m.IsCheckingCost {
+				return runEditorQuery(m, false)
 			}
 			return m, nil // Do nothing if already executing
 
+		case "ctrl+x":
+			// Explain SELECTs before running them and ask about large estimates
+			if !models.DriverCapabilities(m.SelectedDB.Driver).ExplainEstimates {
+				return m, nil
+			}
+			m.CostCheckEnabled = !m.CostCheckEnabled
+			m.Err = nil
+			m.QueryResult = "💰 Cost check off"
+			if m.CostCheckEnabled {
+				m.QueryResult = "💰 Cost check on: SELECTs without a LIMIT are explained first"
+			}
+			return m, utils.ClearResultAfterTimeout()
+
+		case "ctrl+t":
+			// Copy every row of the query into a temporary table and page through it in the preview
+			query := strings.TrimSpace(m.QueryInput.Value())
+			if m.IsExecutingQuery || m.IsMaterializingResult || query == "" || !models.DriverCapabilities(m.SelectedDB.Driver).TempTables {
+				return m, nil
+			}
+			if !utils.CanMaterializeQuery(query) {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("only a single SELECT can be opened as a temporary table"), 3*time.Second)
+			}
+			m.TempResultCount++
+			m.IsMaterializingResult = true
+			m.Err = nil
+			m.QueryResult = ""
+			return m, utils.MaterializeQueryResult(m.DB, m.SelectedDB, m.SelectedSchema, utils.TempResultTableName(m.TempResultCount), query)
+
+		case "ctrl+e", "ctrl+j":
+			// Export the shown result; the on_export_complete hook runs once it is written
+			if m.IsExporting || len(m.LastQueryColumns) == 0 {
+				return m, nil
+			}
+			format := "csv"
+			if keyMsg.String() == "ctrl+j" {
+				format = "json"
+			}
+			m.IsExporting = true
+			m.Err = nil
+			m.QueryResult = ""
+			return m, utils.ExportQueryResult(m.LastQueryColumns, m.LastQueryRows, m.LastQueryNulls, format)
+
+		case "ctrl+o":
+			// Show a small result's columns as rows, to compare a few records side by side
+			if len(m.LastQueryRows) == 0 {
+				return m, nil
+			}
+			updated, err := utils.ToggleQueryTranspose(m)
+			if err != nil {
+				return utils.SetErrorWithTimeout(m, err, 3*time.Second)
+			}
+			return updated, nil
+
+		case "ctrl+up":
+			// Recall the previous query from history, like shell history
+			if m.QueryInput.Focused() {
+				return utils.RecallQueryHistory(m, 1), nil
+			}
+
+		case "ctrl+down":
+			// Step back toward newer history and finally the text being typed
+			if m.QueryInput.Focused() {
+				return utils.RecallQueryHistory(m, -1), nil
+			}
+
+		case "ctrl+n":
+			// Show the next statement's result of a multi-statement script
+			return utils.SelectQueryStatement(m, 1), nil
+
+		case "ctrl+p":
+			// Show the previous statement's result
+			return utils.SelectQueryStatement(m, -1), nil
+
+		case "left", "right":
+			// With the results focused, choose the column the footer aggregates
+			if !m.QueryInput.Focused() {
+				delta := 1
+				if keyMsg.String() == "left" {
+					delta = -1
+				}
+				return utils.MoveQueryResultColumn(m, delta), nil
+			}
+
 		case "tab":
-			// Switch focus between query input and results
+			// Complete the word before the cursor; after a space, switch focus
+			// between query input and results
+			if m.QueryInput.Focused() && utils.EditorCompletionWord(m) != "" {
+				return utils.OpenCompletions(m)
+			}
 			if m.QueryInput.Focused() {
 				m.QueryInput.Blur()
 			} else {
@@ -46,14 +201,57 @@ func HandleQueryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd)
 		}
 	}
 
-	// Update the query input if it's focused
+	// Update the query input if it's focused; an open popup follows the typing
 	if m.QueryInput.Focused() {
 		m.QueryInput, cmd = m.QueryInput.Update(msg)
+		if _, ok := msg.(tea.KeyMsg); ok && m.CompletionActive {
+			var complete tea.Cmd
+			m, complete = utils.UpdateCompletions(m)
+			cmd = tea.Batch(cmd, complete)
+		}
 	}
 
 	return m, cmd
 }
 
+// runEditorQuery runs the editor's query after the syntax, read-only, safe mode,
+// and cost checks. A query with placeholders first asks for their values;
+// bound is true once they are typed.
+func runEditorQuery(m models.Model, bound bool) (models.Model, tea.Cmd) {
+	query := strings.TrimSpace(m.QueryInput.Value())
+	if query == "" {
+		return m, nil
+	}
+	m.HasDraftedUpdate = false
+	if err := utils.CheckQuerySyntax(m.SelectedDB, query); err != nil {
+		return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+	}
+	if err := utils.CheckReadOnlyQuery(m.ReadOnly, query); err != nil {
+		return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+	}
+	params, err := utils.QueryParameters(m.SelectedDB.Driver, query)
+	if err != nil {
+		return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+	}
+	if len(params) > 0 && !bound {
+		return utils.StartBinding(m, params)
+	}
+	if utils.ConfirmsWrites(m) && utils.ScriptHasWrite(query) {
+		m.IsConfirmingSafeOverride = true
+		m.Err = nil
+		m.QueryResult = ""
+		return m, nil
+	}
+	// EXPLAIN cannot estimate a statement whose placeholders have no values
+	if m.CostCheckEnabled && len(params) == 0 && models.DriverCapabilities(m.SelectedDB.Driver).ExplainEstimates && utils.NeedsCostCheck(query) {
+		m.IsCheckingCost = true
+		m.Err = nil
+		m.QueryResult = ""
+		return m, utils.EstimateQueryCost(m.DB, m.SelectedDB, query)
+	}
+	return utils.RunCheckedQuery(m, query)
+}
+
 // HandleQueryHistoryViewUpdate handles all updates for the QueryHistoryView state.
 func HandleQueryHistoryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -62,23 +260,19 @@ func HandleQueryHistoryViewUpdate(m models.Model, msg tea.Msg) (models.Model, te
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "esc":
-			// Go back to the data preview view
-			m.State = models.DataPreviewView
+			// Go back to where the history was opened
+			m = utils.PopView(m, models.TablesView)
 			m.Err = nil
 			return m, nil
 
 		case "enter":
-			// Select and use the query from history
-			if i, ok := m.QueryHistoryList.SelectedItem().(models.Item); ok {
-				// Find the corresponding history entry
-				for _, entry := range m.QueryHistory {
-					if entry.Query == i.ItemTitle {
-						// Set the query in the input and switch to query view
-						m.QueryInput.SetValue(entry.Query)
-						m.State = models.QueryView
-						return m, nil
-					}
-				}
+			// Select and use the query from history; the list shows queries on one
+			// line, so the entry is found by position rather than by its title
+			if i := m.QueryHistoryList.GlobalIndex(); m.QueryHistoryList.SelectedItem() != nil && i < len(m.QueryHistory) {
+				// Set the query in the input and switch to query view
+				m.QueryInput.SetValue(m.QueryHistory[i].Query)
+				m = utils.PushView(m, models.QueryView, models.NavParams{})
+				return m, nil
 			}
 		}
 	}
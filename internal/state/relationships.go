@@ -0,0 +1,22 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleRelationshipsViewUpdate handles all updates for the RelationshipsView state.
+func HandleRelationshipsViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
+		// Go back to the tables view
+		m = utils.PopView(m, models.TablesView)
+		m.Err = nil
+		return m, nil
+	}
+
+	m.RelationshipsTable, cmd = m.RelationshipsTable.Update(msg)
+	return m, cmd
+}
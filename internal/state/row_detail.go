@@ -1,8 +1,11 @@
 package state
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/models"
 	"github.com/dancaldera/mirador/internal/styles"
@@ -14,6 +17,17 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 	var cmd tea.Cmd
 
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// In safe mode saving an edit needs an explicit override
+		if m.IsEditingField && m.IsConfirmingSafeOverride {
+			m.IsConfirmingSafeOverride = false
+			if keyMsg.String() == "y" {
+				newValue := m.FieldTextarea.Value()
+				return m, utils.SaveFieldEdit(m.DB, m.SelectedDB, m.ConnectionStr, m.MigrationFile, m.SelectedSchema, m.SelectedTable, m.EditingFieldName, utils.PreviewTableColumns(m), m.SelectedRowData, m.EditingFieldIndex, newValue)
+			}
+			m.QueryResult = fmt.Sprintf("Edit not saved (%s)", utils.WriteConfirmLabel(m))
+			return m, utils.ClearResultAfterTimeout()
+		}
+
 		// If editing, the textarea consumes all key presses except a few special ones.
 		if m.IsEditingField {
 			switch keyMsg.String() {
@@ -27,8 +41,14 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 				return m, nil
 			case "ctrl+s":
 				// Save the edited field
+				if utils.ConfirmsWrites(m) {
+					m.IsConfirmingSafeOverride = true
+					m.Err = nil
+					m.QueryResult = ""
+					return m, nil
+				}
 				newValue := m.FieldTextarea.Value()
-				return m, utils.SaveFieldEdit(m.DB, m.SelectedDB, m.SelectedSchema, m.SelectedTable, m.EditingFieldName, m.DataPreviewAllColumns, m.SelectedRowData, m.EditingFieldIndex, newValue)
+				return m, utils.SaveFieldEdit(m.DB, m.SelectedDB, m.ConnectionStr, m.MigrationFile, m.SelectedSchema, m.SelectedTable, m.EditingFieldName, utils.PreviewTableColumns(m), m.SelectedRowData, m.EditingFieldIndex, newValue)
 			case "ctrl+k":
 				// Clear all text in the edit textarea
 				m.FieldTextarea.SetValue("")
@@ -57,15 +77,8 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 				return m, nil
 			case "down", "j":
 				// Scroll down in field detail view
-				fieldValue := ""
-				for i, col := range m.DataPreviewAllColumns {
-					if col == m.SelectedFieldForDetail && i < len(m.SelectedRowData) {
-						fieldValue = m.SelectedRowData[i]
-						break
-					}
-				}
-				// Format field value same as in view (handles JSON formatting)
-				fieldValue = utils.FormatFieldValue(fieldValue)
+				// Format field value same as in view (handles JSON, arrays, and composites)
+				fieldValue := utils.FormatFieldDetail(utils.FieldDetailColumn(m))
 
 				// Calculate max scroll based on formatted field content and dynamic height
 				// Must match calculation in query_views.go
@@ -81,6 +94,26 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 					m.FieldDetailScrollOffset++
 				}
 				return m, nil
+			case "r":
+				return refreshSelectedRow(m)
+			case "n", "ctrl+down":
+				return utils.StepRowDetail(m, 1)
+			case "p", "ctrl+up":
+				return utils.StepRowDetail(m, -1)
+			case "c":
+				// Copy the field's value as stored
+				value, _ := utils.FieldDetailColumn(m)
+				return copyFieldValue(m, value, "value")
+			case "U", "L":
+				// Copy a UUID or ULID in upper or lower case
+				value, _ := utils.FieldDetailColumn(m)
+				if _, ok := utils.DetectIdentifier(value); !ok {
+					return m, nil
+				}
+				if keyMsg.String() == "U" {
+					return copyFieldValue(m, strings.ToUpper(value), "uppercase value")
+				}
+				return copyFieldValue(m, strings.ToLower(value), "lowercase value")
 			case "left", "h":
 				// Horizontal scroll left
 				availableWidth := min(max(m.Width-10, 40), 200)
@@ -99,13 +132,46 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 			}
 		}
 
+		// The field search captures typing while active and filters as you type
+		if m.IsSearchingFields {
+			switch keyMsg.String() {
+			case "enter":
+				m.IsSearchingFields = false
+				m.SearchInput.Blur()
+				return m, nil
+			case "esc":
+				m.IsSearchingFields = false
+				m.SearchInput.Blur()
+				m.SearchInput.SetValue("")
+				m = utils.RefreshRowDetailList(m)
+				m.RowDetailList.Select(0)
+				return m, nil
+			default:
+				m.SearchInput, cmd = m.SearchInput.Update(msg)
+				m = utils.RefreshRowDetailList(m)
+				m.RowDetailList.Select(0)
+				return m, cmd
+			}
+		}
+
 		// Default mode: navigating the list of fields.
 		switch keyMsg.String() {
 		case "esc":
-			// Return to data preview
-			m.State = models.DataPreviewView
+			// Clear an applied field search first, then return to data preview
+			if m.SearchInput.Value() != "" {
+				m.SearchInput.SetValue("")
+				m = utils.RefreshRowDetailList(m)
+				m.RowDetailList.Select(0)
+				return m, nil
+			}
+			m = utils.PopView(m, models.DataPreviewView)
 			m.Err = nil
 			return m, nil
+		case "/":
+			// Search fields by name or value
+			m.IsSearchingFields = true
+			m.SearchInput.Focus()
+			return m, nil
 		case "enter":
 			// Enter field detail view
 			if selectedItem, ok := m.RowDetailList.SelectedItem().(models.FieldItem); ok {
@@ -116,9 +182,37 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 				m.FieldDetailHorizontalOffset = 0
 			}
 			return m, nil
+		case "r":
+			return refreshSelectedRow(m)
+		case "H":
+			// Show prior versions of the row from system versioning or a history table
+			if len(m.SelectedRowData) > 0 && !m.IsLoadingRowHistory {
+				m.IsLoadingRowHistory = true
+				m.Err = nil
+				return m, utils.LoadRowHistory(m.DB, m.SelectedDB, m.SelectedSchema, m.SelectedTable, m.Tables, utils.PreviewTableColumns(m), m.SelectedRowData)
+			}
+			return m, nil
+		case "w":
+			// Pin the row to the watchlist, or unpin it
+			if len(m.SelectedRowData) > 0 {
+				return utils.ToggleWatchedRow(m, m.DataPreviewAllColumns, m.SelectedRowData, m.SelectedRowNulls)
+			}
+			return m, nil
+		case "n", "ctrl+down":
+			// Show the next row of the preview in place
+			return utils.StepRowDetail(m, 1)
+		case "p", "ctrl+up":
+			// Show the previous row of the preview in place
+			return utils.StepRowDetail(m, -1)
 		case "e":
 			// Enter field edit mode
+			if m.ReadOnly {
+				return utils.SetErrorWithTimeout(m, utils.ReadOnlyBlocked("field editing"), 3*time.Second)
+			}
 			if selectedItem, ok := m.RowDetailList.SelectedItem().(models.FieldItem); ok {
+				if utils.IsJSONColumn(m, selectedItem.Name) {
+					return utils.SetErrorWithTimeout(m, fmt.Errorf("%s is a JSON path column and cannot be edited", selectedItem.Name), 3*time.Second)
+				}
 				m.EditingFieldName = selectedItem.Name
 				m.OriginalFieldValue = selectedItem.Value
 
@@ -156,6 +250,27 @@ func HandleRowDetailViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.C
 	return m, cmd
 }
 
+// copyFieldValue copies text from the field detail view to the clipboard
+func copyFieldValue(m models.Model, text, what string) (models.Model, tea.Cmd) {
+	if err := clipboard.WriteAll(text); err != nil {
+		m.Err = fmt.Errorf("failed to copy to clipboard: %w", err)
+		return m, nil
+	}
+	m.Err = nil
+	m.QueryResult = fmt.Sprintf("✅ Copied %s to clipboard", what)
+	return m, utils.ClearResultAfterTimeout()
+}
+
+// refreshSelectedRow re-fetches the row being inspected by its primary key
+func refreshSelectedRow(m models.Model) (models.Model, tea.Cmd) {
+	if m.IsRefreshingRow || len(m.SelectedRowData) == 0 {
+		return m, nil
+	}
+	m.IsRefreshingRow = true
+	m.Err = nil
+	return m, utils.RefreshRow(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, utils.PreviewTableColumns(m), m.SelectedRowData)
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -0,0 +1,39 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleRowDiffViewUpdate handles all updates for the RowDiffView state.
+func HandleRowDiffViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the data preview, keeping the mark for further diffs
+			m = utils.PopView(m, models.DataPreviewView)
+			m.Err = nil
+			return m, nil
+
+		case "d":
+			// Toggle between every field and only the differing ones
+			m.RowDiffOnlyChanges = !m.RowDiffOnlyChanges
+			m = utils.BuildRowDiffTable(m)
+			return m, nil
+
+		case "x":
+			// Swap the two rows
+			m.RowDiffLeft, m.RowDiffRight = m.RowDiffRight, m.RowDiffLeft
+			cursor := m.RowDiffTable.Cursor()
+			m = utils.BuildRowDiffTable(m)
+			m.RowDiffTable.SetCursor(cursor)
+			return m, nil
+		}
+	}
+
+	m.RowDiffTable, cmd = m.RowDiffTable.Update(msg)
+	return m, cmd
+}
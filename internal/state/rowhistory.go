@@ -0,0 +1,43 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleRowHistoryViewUpdate handles all updates for the RowHistoryView state.
+func HandleRowHistoryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the row detail view
+			m = utils.PopView(m, models.RowDetailView)
+			m.Err = nil
+			return m, nil
+
+		case "left", "h":
+			// Show older versions
+			if m.RowHistoryOffset > 0 {
+				cursor := m.RowHistoryTable.Cursor()
+				m.RowHistoryOffset--
+				m = utils.BuildRowHistoryTable(m)
+				m.RowHistoryTable.SetCursor(cursor)
+			}
+			return m, nil
+
+		case "right", "l":
+			// Show newer versions
+			cursor := m.RowHistoryTable.Cursor()
+			m.RowHistoryOffset++
+			m = utils.BuildRowHistoryTable(m)
+			m.RowHistoryTable.SetCursor(cursor)
+			return m, nil
+		}
+	}
+
+	m.RowHistoryTable, cmd = m.RowHistoryTable.Update(msg)
+	return m, cmd
+}
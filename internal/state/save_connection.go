@@ -1,9 +1,14 @@
 package state
 
 import (
+	"fmt"
+	"slices"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/config"
 	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
 )
 
 // HandleSaveConnectionViewUpdate handles all updates for the SaveConnectionView state.
@@ -14,8 +19,8 @@ func HandleSaveConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model,
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "esc":
-			// Go back to the connection view
-			m.State = models.ConnectionView
+			// Go back to where saving was started
+			m = utils.PopView(m, models.ConnectionView)
 			m.Err = nil
 			return m, nil
 
@@ -23,14 +28,22 @@ func HandleSaveConnectionViewUpdate(m models.Model, msg tea.Msg) (models.Model,
 			// Save the new connection
 			name := m.NameInput.Value()
 			if name != "" {
-				newConnection := models.SavedConnection{
-					Name:          name,
-					Driver:        m.SelectedDB.Driver,
-					ConnectionStr: m.ConnectionStr,
+				if slices.ContainsFunc(m.SavedConnections, func(conn models.SavedConnection) bool { return conn.Name == name }) {
+					return utils.SetErrorWithTimeout(m, fmt.Errorf("a connection named '%s' already exists", name), 3*time.Second)
 				}
+				newConnection, warnings := utils.NormalizeSavedConnection(models.SavedConnection{
+					Name:                 name,
+					Driver:               m.SelectedDB.Driver,
+					ConnectionStr:        m.ConnectionStr,
+					ReplicaConnectionStr: m.ReplicaConnectionStr,
+				})
 				m.SavedConnections = append(m.SavedConnections, newConnection)
 				config.SaveConnections(m.SavedConnections)
-				m.State = models.ConnectionView // Go back to connection view after saving
+				m = utils.PopView(m, models.ConnectionView) // Go back to where saving was started
+				if message := utils.ConnectionWarningsMessage(name, warnings); message != "" {
+					m.QueryResult = message
+					return m, utils.ClearResultAfterTimeout()
+				}
 				return m, nil
 			}
 		}
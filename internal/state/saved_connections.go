@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,30 +17,69 @@ func HandleSavedConnectionsViewUpdate(m models.Model, msg tea.Msg) (models.Model
 
 	// Only handle key messages, other messages are handled in the main update function
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// The search input captures typing while active and filters as you type
+		if m.IsSearchingConnections {
+			switch keyMsg.String() {
+			case "enter":
+				m.IsSearchingConnections = false
+				m.ConnectionSearchInput.Blur()
+				return m, nil
+			case "esc":
+				m.IsSearchingConnections = false
+				m.ConnectionSearchInput.Blur()
+				m.ConnectionSearchInput.SetValue("")
+				return utils.UpdateSavedConnectionsList(m), nil
+			default:
+				m.ConnectionSearchInput, cmd = m.ConnectionSearchInput.Update(msg)
+				m = utils.UpdateSavedConnectionsList(m)
+				m.SavedConnectionsList.ResetSelected()
+				return m, cmd
+			}
+		}
+
 		switch keyMsg.String() {
 		case "esc":
+			// Cancel a pending connect retry first
+			if !m.ConnectRetryAt.IsZero() {
+				m.QueryResult = "Connection retry cancelled"
+				if m.IsWakingDatabase {
+					m.QueryResult = "Stopped waiting for the database to wake up"
+				}
+				m = utils.CancelConnectRetry(m)
+				m.Err = nil
+				return m, utils.ClearResultAfterTimeout()
+			}
+			// Then clear the search and group filter
+			if m.ConnectionSearchInput.Value() != "" || m.ConnectionGroupFilter != "" {
+				m.ConnectionSearchInput.SetValue("")
+				m.ConnectionGroupFilter = ""
+				return utils.UpdateSavedConnectionsList(m), nil
+			}
 			// Go back to the DB type selection view
-			m.State = models.DBTypeView
+			m = utils.PopView(m, models.DBTypeView)
 			m.Err = nil
 			return m, nil
 
 		case "enter":
+			// Fold or unfold a group
+			if group, ok := m.SavedConnectionsList.SelectedItem().(models.GroupItem); ok {
+				collapsed := make(map[string]bool, len(m.CollapsedGroups)+1)
+				for name, folded := range m.CollapsedGroups {
+					collapsed[name] = folded
+				}
+				collapsed[group.Name] = !group.Collapsed
+				m.CollapsedGroups = collapsed
+				return utils.UpdateSavedConnectionsList(m), nil
+			}
 			// Connect to the selected saved connection
 			if i, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok && !m.IsConnecting {
 				for _, conn := range m.SavedConnections {
 					if conn.Name == i.ItemTitle {
-						// Find the corresponding DB driver info
-						for _, db := range models.SupportedDatabaseTypes {
-							if db.Driver == conn.Driver {
-								m.SelectedDB = db
-								break
-							}
+						updated, cmd, err := utils.ConnectSavedConnection(m, conn)
+						if err != nil {
+							return utils.SetErrorWithTimeout(m, err, 5*time.Second)
 						}
-						m.ConnectionStr = conn.ConnectionStr
-						m.IsConnecting = true
-						m.Err = nil
-						m.QueryResult = "" // Clear any previous messages
-						return m, utils.ConnectToDB(m.SelectedDB, m.ConnectionStr)
+						return updated, cmd
 					}
 				}
 			}
@@ -67,6 +107,150 @@ func HandleSavedConnectionsViewUpdate(m models.Model, msg tea.Msg) (models.Model
 				}
 			}
 
+		case "/":
+			// Search names, groups, and tags; #tag searches tags only
+			m.IsSearchingConnections = true
+			m.ConnectionSearchInput.Focus()
+			return m, nil
+
+		case "tab", "shift+tab":
+			// Show the next or previous group alone, then every group
+			step := 1
+			if keyMsg.String() == "shift+tab" {
+				step = -1
+			}
+			m.ConnectionGroupFilter = utils.NextConnectionGroup(utils.ConnectionGroups(m.SavedConnections), m.ConnectionGroupFilter, step)
+			m = utils.UpdateSavedConnectionsList(m)
+			m.SavedConnectionsList.ResetSelected()
+			return m, nil
+
+		case "g":
+			// File the selected connection under a group and tag it
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				for _, conn := range m.SavedConnections {
+					if conn.Name == selectedItem.ItemTitle {
+						return startGroupEdit(m, conn), nil
+					}
+				}
+			}
+
+		case "s":
+			// Set the default schema, page size, and sort of the selected connection
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				for _, conn := range m.SavedConnections {
+					if conn.Name == selectedItem.ItemTitle {
+						return startDefaultsEdit(m, conn), nil
+					}
+				}
+			}
+
+		case "r":
+			// Turn read-only mode of the selected connection on or off
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				updated, err := utils.ToggleConnectionReadOnly(m, selectedItem.ItemTitle)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				m = updated
+				m.QueryResult = fmt.Sprintf("🔓 '%s' allows writes again", selectedItem.ItemTitle)
+				for _, conn := range m.SavedConnections {
+					if conn.Name != selectedItem.ItemTitle || !conn.ReadOnly {
+						continue
+					}
+					switch {
+					case conn.Immutable:
+						m.QueryResult = fmt.Sprintf("🔒 '%s' is read-only and immutable: the file is opened without locks, for read-only media; r again allows writes", selectedItem.ItemTitle)
+					case conn.Driver == "sqlite3":
+						m.QueryResult = fmt.Sprintf("🔒 '%s' is read-only: writes are blocked and the file is opened with mode=ro; r again for immutable", selectedItem.ItemTitle)
+					default:
+						m.QueryResult = fmt.Sprintf("🔒 '%s' is read-only: writes, DDL, and field edits are blocked", selectedItem.ItemTitle)
+					}
+				}
+				return m, utils.ClearResultAfterTimeout()
+			}
+
+		case "e":
+			// Edit the selected connection's name and connection string
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				updated, err := startConnectionEdit(m, selectedItem.ItemTitle)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				return updated, nil
+			}
+
+		case "D":
+			// Save a copy of the selected connection and open it in the edit form
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				updated, name, err := utils.DuplicateSavedConnection(m, selectedItem.ItemTitle)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				for i, item := range updated.SavedConnectionsList.Items() {
+					if item, ok := item.(models.Item); ok && item.ItemTitle == name {
+						updated.SavedConnectionsList.Select(i)
+					}
+				}
+				if edited, err := startConnectionEdit(updated, name); err == nil {
+					return edited, nil
+				}
+				updated.QueryResult = fmt.Sprintf("✅ Duplicated '%s' as '%s'", selectedItem.ItemTitle, name)
+				return updated, utils.ClearResultAfterTimeout()
+			}
+
+		case "E":
+			// Label the selected connection production, staging, or dev
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				updated, env, err := utils.CycleConnectionEnvironment(m, selectedItem.ItemTitle)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+				}
+				m = updated
+				m.QueryResult = fmt.Sprintf("'%s' has no environment label", selectedItem.ItemTitle)
+				if env == models.EnvironmentProduction {
+					m.QueryResult = fmt.Sprintf("🚨 '%s' is production: writes in the query runner and field edits ask first", selectedItem.ItemTitle)
+				} else if env != models.EnvironmentNone {
+					m.QueryResult = fmt.Sprintf("'%s' is labelled %s", selectedItem.ItemTitle, utils.EnvironmentBadge(env))
+				}
+				return m, utils.ClearResultAfterTimeout()
+			}
+
+		case "o":
+			// Cycle the list through saved, most recently used, and name order
+			m.ConnectionSort = utils.NextConnectionSort(m.ConnectionSort)
+			m = utils.UpdateSavedConnectionsList(m)
+			switch m.ConnectionSort {
+			case models.ConnectionSortRecent:
+				m.QueryResult = "🕘 Saved connections sorted by last use"
+			case models.ConnectionSortName:
+				m.QueryResult = "🔤 Saved connections sorted by name"
+			default:
+				m.QueryResult = "Saved connections in saved order"
+			}
+			return m, utils.ClearResultAfterTimeout()
+
+		case "h":
+			// Ping every saved connection and show the health board
+			if len(m.SavedConnections) > 0 && !m.IsCheckingHealth {
+				m.IsCheckingHealth = true
+				m.Err = nil
+				return m, utils.CheckConnectionHealth(m.SavedConnections)
+			}
+
+		case "p":
+			// Set, change, or remove the master passphrase that encrypts saved connections
+			return utils.StartPassphraseEntry(m, true), nil
+
+		case "n":
+			// Write a note on the selected saved connection
+			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
+				for _, conn := range m.SavedConnections {
+					if conn.Name == selectedItem.ItemTitle {
+						return startNoteEdit(m, "", conn.Name, conn.Notes), nil
+					}
+				}
+			}
+
 		case "c":
 			// Copy the connection string of the selected saved connection
 			if selectedItem, ok := m.SavedConnectionsList.SelectedItem().(models.Item); ok {
@@ -74,8 +258,12 @@ func HandleSavedConnectionsViewUpdate(m models.Model, msg tea.Msg) (models.Model
 				// Find the connection string
 				for _, conn := range m.SavedConnections {
 					if conn.Name == connectionName {
+						connectionStr, err := utils.SavedConnectionString(conn)
+						if err != nil {
+							return utils.SetErrorWithTimeout(m, err, 5*time.Second)
+						}
 						// Copy to clipboard
-						err := clipboard.WriteAll(conn.ConnectionStr)
+						err = clipboard.WriteAll(connectionStr)
 						if err != nil {
 							m.Err = fmt.Errorf("failed to copy to clipboard: %w", err)
 							return m, nil
@@ -0,0 +1,34 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleSchemaViewUpdate handles all updates for the SchemaView state.
+func HandleSchemaViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Keep the current schema and go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			return m, nil
+
+		case "enter":
+			// Browse the tables of the selected schema or database
+			if i, ok := m.SchemasList.SelectedItem().(models.Item); ok && !m.IsLoadingSchemas {
+				m.IsLoadingSchemas = true
+				m.Err = nil
+				return m, utils.LoadTablesForSchema(m.DB, m.SelectedDB, i.ItemTitle)
+			}
+			return m, nil
+		}
+	}
+
+	m.SchemasList, cmd = m.SchemasList.Update(msg)
+	return m, cmd
+}
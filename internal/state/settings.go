@@ -0,0 +1,65 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleServerSettingsViewUpdate handles all updates for the ServerSettingsView state.
+func HandleServerSettingsViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// The search input captures typing while active and filters as you type
+		if m.IsSearchingSettings {
+			switch keyMsg.String() {
+			case "enter":
+				m.IsSearchingSettings = false
+				m.SearchInput.Blur()
+				return m, nil
+			case "esc":
+				m.IsSearchingSettings = false
+				m.SearchInput.Blur()
+				m.SearchInput.SetValue("")
+				return utils.RefreshServerSettingsTable(m), nil
+			default:
+				m.SearchInput, cmd = m.SearchInput.Update(msg)
+				return utils.RefreshServerSettingsTable(m), cmd
+			}
+		}
+
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			m.SearchInput.SetValue("")
+			return m, nil
+
+		case "/":
+			// Start searching settings
+			m.IsSearchingSettings = true
+			m.SearchInput.Focus()
+			return m, nil
+
+		case "m":
+			// Toggle showing only settings that differ from defaults
+			m.ServerSettingsOnlyChanged = !m.ServerSettingsOnlyChanged
+			return utils.RefreshServerSettingsTable(m), nil
+
+		case "ctrl+r":
+			// Reload settings from the server
+			if !m.IsLoadingSettings {
+				m.IsLoadingSettings = true
+				m.Err = nil
+				return m, utils.LoadServerSettings(m.DB, m.SelectedDB)
+			}
+			return m, nil
+		}
+	}
+
+	// Let the table handle navigation keys
+	m.ServerSettingsTable, cmd = m.ServerSettingsTable.Update(msg)
+	return m, cmd
+}
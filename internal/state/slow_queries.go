@@ -0,0 +1,82 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleSlowQueryViewUpdate handles all updates for the SlowQueryView state.
+func HandleSlowQueryViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "s":
+			// Toggle ordering between total and mean execution time
+			if !m.IsLoadingSlowQueries {
+				m.SlowQuerySortByMean = !m.SlowQuerySortByMean
+				m.IsLoadingSlowQueries = true
+				m.Err = nil
+				return m, utils.LoadSlowQueries(m.DB, m.SelectedDB, m.SelectedSchema, m.SlowQuerySortByMean)
+			}
+			return m, nil
+
+		case "ctrl+r":
+			// Refresh the statistics
+			if !m.IsLoadingSlowQueries {
+				m.IsLoadingSlowQueries = true
+				m.Err = nil
+				return m, utils.LoadSlowQueries(m.DB, m.SelectedDB, m.SelectedSchema, m.SlowQuerySortByMean)
+			}
+			return m, nil
+
+		case "enter":
+			// Open the selected statement in the query editor, prefixed for EXPLAIN
+			if q, ok := selectedSlowQuery(m); ok {
+				m.QueryInput.SetValue("EXPLAIN " + q.Query)
+				m.QueryInput.CursorEnd()
+				m.QueryInput.Focus()
+				m = utils.PushView(m, models.QueryView, models.NavParams{})
+				m.Err = nil
+				m.QueryResult = ""
+			}
+			return m, nil
+
+		case "c":
+			// Copy the selected statement to the clipboard
+			if q, ok := selectedSlowQuery(m); ok {
+				if err := clipboard.WriteAll(q.Query); err != nil {
+					m.Err = fmt.Errorf("failed to copy to clipboard: %w", err)
+					return m, nil
+				}
+				m.QueryResult = "✅ Copied query to clipboard"
+				return m, utils.ClearResultAfterTimeout()
+			}
+			return m, nil
+		}
+	}
+
+	// Let the table handle navigation keys
+	m.SlowQueryTable, cmd = m.SlowQueryTable.Update(msg)
+	return m, cmd
+}
+
+// selectedSlowQuery returns the statement under the table cursor
+func selectedSlowQuery(m models.Model) (models.SlowQuery, bool) {
+	cursor := m.SlowQueryTable.Cursor()
+	if cursor < 0 || cursor >= len(m.SlowQueries) {
+		return models.SlowQuery{}, false
+	}
+	return m.SlowQueries[cursor], true
+}
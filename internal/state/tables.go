@@ -1,7 +1,12 @@
 package state
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
 	"github.com/dancaldera/mirador/internal/models"
 	"github.com/dancaldera/mirador/internal/utils"
 )
@@ -12,29 +17,83 @@ func HandleTablesViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd)
 
 	// Handle key messages
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// A TRUNCATE/DROP waits until the table name is typed exactly
+		if m.IsConfirmingDestructive {
+			action := m.DestructiveAction
+			switch keyMsg.String() {
+			case "esc":
+				m.IsConfirmingDestructive = false
+				m.DestructiveConfirmInput.Blur()
+				if utils.ConfirmsWrites(m) {
+					m.QueryResult = fmt.Sprintf("%s cancelled (%s)", action, utils.WriteConfirmLabel(m))
+				} else {
+					m.QueryResult = fmt.Sprintf("%s cancelled", action)
+				}
+				return m, utils.ClearResultAfterTimeout()
+			case "enter":
+				m.IsConfirmingDestructive = false
+				m.DestructiveConfirmInput.Blur()
+				if !utils.DestructiveConfirmed(m.DestructiveConfirmInput.Value(), m.DestructiveTable) {
+					return utils.SetErrorWithTimeout(m, fmt.Errorf("table name did not match, %s cancelled", action), 3*time.Second)
+				}
+				m.IsRunningDestructive = true
+				m.Err = nil
+				m.QueryResult = ""
+				return m, utils.RunDestructiveAction(m.DB, m.SelectedDB, m.ConnectionStr, m.MigrationFile, action, m.SelectedSchema, m.DestructiveTable)
+			default:
+				m.DestructiveConfirmInput, cmd = m.DestructiveConfirmInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// Keys for features the driver lacks are dimmed in the help and do nothing
+		caps := models.DriverCapabilities(m.SelectedDB.Driver)
 		switch keyMsg.String() {
 		case "esc":
 			// Disconnect from DB, reset state, and go back to the DB type view
-			if m.DB != nil {
-				m.DB.Close()
-				m.DB = nil
-			}
-			m.State = models.DBTypeView
+			m = utils.SaveWorksheet(m) // Keep the latest edits of the query editor
+			m = utils.CloseDB(m)
+			m = utils.CloseReplica(m)
+			m = utils.ResetView(m, models.DBTypeView, models.NavParams{})
 			m.ConnectionStr = ""
+			m.ConnectionName = ""
 			m.Tables = nil
 			m.TableInfos = nil
 			m.SelectedTable = ""
+			m.ConnectionLost = false
+			m.PendingRetry = models.RetryNone
+			m.MigrationFile = "" // A migration belongs to one database
+			m.JSONColumns = nil
+			m.DurationColumns = nil
+			m.FullAggregateKey = ""
+			m.Notifications, m.NotifyChannels = nil, nil
 			m.Err = nil
 			return m, nil
 
 		case "enter", "p":
 			// Load data preview for the selected table
 			if i, ok := m.TablesList.SelectedItem().(models.Item); ok && !m.IsLoadingPreview {
+				// A foreign table's preview runs on the remote server, so it asks first
+				if server, foreign := utils.ForeignTableServer(m.TableInfos, i.ItemTitle); foreign && m.ForeignPreviewTable != i.ItemTitle {
+					m.ForeignPreviewTable = i.ItemTitle
+					m.QueryResult = fmt.Sprintf("%s is a foreign table on %s; press enter again to query the remote server", i.ItemTitle, server)
+					return m, utils.ClearResultAfterTimeout()
+				}
+				m.ForeignPreviewTable = ""
+				newTable := i.ItemTitle != m.SelectedTable
+				if newTable {
+					// Sort columns belong to one table; a new one starts from the connection's default sort
+					m = utils.SetPreviewSortKeys(m, m.DefaultSortKeys)
+				}
 				m.SelectedTable = i.ItemTitle
 				m.IsLoadingPreview = true
 				m.DataPreviewCurrentPage = 0 // Reset to first page
 				m.Err = nil
-				return m, utils.LoadDataPreview(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn)
+				routed, db := utils.RouteRead(m)
+				if newTable && len(m.DefaultSortKeys) > 0 {
+					return routed, utils.LoadDefaultSortedPreview(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DefaultSortKeys, utils.PreviewJSONColumns(m))
+				}
+				return routed, utils.LoadDataPreview(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, utils.PreviewSortKeys(m), utils.PreviewJSONColumns(m))
 			}
 
 		case "v":
@@ -51,6 +110,173 @@ func HandleTablesViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd)
 			if m.DB != nil {
 				return m, utils.LoadRelationships(m.DB, m.SelectedDB, m.SelectedSchema)
 			}
+
+		case "o":
+			// Open the database size overview dashboard
+			if m.DB != nil && !caps.SizeStats {
+				return utils.SetErrorWithTimeout(m, utils.OverviewUnavailable(m.SelectedDB), 3*time.Second)
+			}
+			if m.DB != nil && !m.IsLoadingOverview {
+				m.IsLoadingOverview = true
+				m.Err = nil
+				return m, utils.LoadDatabaseOverview(m.DB, m.SelectedDB, m.SelectedSchema)
+			}
+
+		case "C":
+			// Browse the server configuration
+			if m.DB != nil && caps.ServerSettings && !m.IsLoadingSettings {
+				m.IsLoadingSettings = true
+				m.Err = nil
+				m.SearchInput.SetValue("")
+				return m, utils.LoadServerSettings(m.DB, m.SelectedDB)
+			}
+
+		case "L":
+			// Browse the server's slow query statistics
+			if m.DB != nil && caps.SlowQueries && !m.IsLoadingSlowQueries {
+				m.IsLoadingSlowQueries = true
+				m.Err = nil
+				return m, utils.LoadSlowQueries(m.DB, m.SelectedDB, m.SelectedSchema, m.SlowQuerySortByMean)
+			}
+
+		case "T":
+			// Show table growth since the last stored snapshot
+			if m.DB != nil && caps.SizeStats && !m.IsLoadingGrowth {
+				m.IsLoadingGrowth = true
+				m.Err = nil
+				return m, utils.LoadTableGrowth(m.DB, m.SelectedDB, m.ConnectionStr, m.SelectedSchema)
+			}
+
+		case "E":
+			// Monitor PostgreSQL LISTEN/NOTIFY channels
+			if m.DB != nil && caps.ListenNotify {
+				return startNotifyMonitor(m), nil
+			}
+
+		case "S":
+			// Switch to another schema (PostgreSQL) or database (MySQL)
+			if m.DB != nil && caps.Schemas && !m.IsLoadingSchemas {
+				m.IsLoadingSchemas = true
+				m.Err = nil
+				return m, utils.LoadSchemas(m.DB, m.SelectedDB)
+			}
+
+		case "I":
+			// Generate synthetic rows for the selected table
+			if i, ok := m.TablesList.SelectedItem().(models.Item); ok {
+				return utils.StartTestData(m, i.ItemTitle)
+			}
+
+		case "Y":
+			// Copy the selected table's rows into a table on a saved connection
+			i, ok := m.TablesList.SelectedItem().(models.Item)
+			if !ok || m.DB == nil {
+				return m, nil
+			}
+			if m.IsCopyingData {
+				m = utils.PushView(m, models.CopyDataView, models.NavParams{Schema: m.SelectedSchema, Table: m.CopySourceTable})
+				return m, nil
+			}
+			if len(m.SavedConnections) == 0 {
+				return utils.SetErrorWithTimeout(m, fmt.Errorf("save a connection to copy into first"), 3*time.Second)
+			}
+			m.CopySourceTable = i.ItemTitle
+			m.CopyStep = models.CopyChooseTarget
+			if m.CopyTargetIndex >= len(m.SavedConnections) {
+				m.CopyTargetIndex = 0
+			}
+			m.CopyProgress = models.CopyProgressMsg{}
+			m = utils.PushView(m, models.CopyDataView, models.NavParams{Schema: m.SelectedSchema, Table: m.CopySourceTable})
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "K":
+			// Checksum the selected table against another table by primary key
+			i, ok := m.TablesList.SelectedItem().(models.Item)
+			if !ok || m.DB == nil {
+				return m, nil
+			}
+			if !m.IsComparingTables {
+				m.CompareSourceTable = i.ItemTitle
+				m.CompareStep = models.CompareChooseTarget
+				if m.CompareTargetIndex > len(m.SavedConnections) {
+					m.CompareTargetIndex = 0
+				}
+			}
+			m = utils.PushView(m, models.CompareTablesView, models.NavParams{Schema: m.SelectedSchema, Table: m.CompareSourceTable})
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "X", "D":
+			// Ask for the table name before truncating or dropping the selected table
+			i, ok := m.TablesList.SelectedItem().(models.Item)
+			if !ok || m.DB == nil || !caps.TableDDL || m.IsRunningDestructive {
+				return m, nil
+			}
+			action := utils.DestructiveActionForKey(keyMsg.String())
+			if m.ReadOnly {
+				return utils.SetErrorWithTimeout(m, utils.ReadOnlyBlocked(action), 3*time.Second)
+			}
+			m.DestructiveAction = action
+			m.DestructiveTable = i.ItemTitle
+			m.DestructiveConfirmInput.SetValue("")
+			m.DestructiveConfirmInput.Focus()
+			m.IsConfirmingDestructive = true
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "*":
+			// Star or unstar the selected table for this connection
+			if i, ok := m.TablesList.SelectedItem().(models.Item); ok && m.DB != nil {
+				updated, starred, err := utils.ToggleFavoriteTable(m, i.ItemTitle)
+				if err != nil {
+					return utils.SetErrorWithTimeout(m, fmt.Errorf("failed to save favorites: %w", err), 3*time.Second)
+				}
+				if starred {
+					updated.QueryResult = "★ " + i.ItemTitle + " added to favorites"
+				} else {
+					updated.QueryResult = i.ItemTitle + " removed from favorites"
+				}
+				return updated, utils.ClearResultAfterTimeout()
+			}
+
+		case "N":
+			// Write a note on the selected table
+			if i, ok := m.TablesList.SelectedItem().(models.Item); ok && m.DB != nil {
+				return startNoteEdit(m, i.ItemTitle, "", m.TableNotes[i.ItemTitle]), nil
+			}
+
+		case "M":
+			// Start or stop recording executed writes into a migration file
+			if m.MigrationFile != "" {
+				file := m.MigrationFile
+				m.MigrationFile = ""
+				if _, err := os.Stat(file); err != nil {
+					m.QueryResult = "⏹ Stopped recording; no statements were recorded"
+				} else {
+					m.QueryResult = "⏹ Stopped recording; migration saved to " + file
+				}
+				return m, utils.ClearResultAfterTimeout()
+			}
+			m.MigrationFile = config.GenerateMigrationFilename(time.Now())
+			m.Err = nil
+			m.QueryResult = "⏺ Recording writes to " + m.MigrationFile
+			return m, utils.ClearResultAfterTimeout()
+
+		case "W":
+			// Open the watchlist of pinned rows
+			return utils.OpenWatchlist(m)
+
+		case "A":
+			// Browse the audit log of executed write statements
+			if !m.IsLoadingAudit {
+				m.IsLoadingAudit = true
+				m.Err = nil
+				return m, utils.LoadAuditLog()
+			}
 		}
 	}
 
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleGenerateDataViewUpdate handles all updates for the GenerateDataView state.
+func HandleGenerateDataViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		// In safe mode and on production inserting generated rows needs an explicit override
+		if m.IsConfirmingSafeOverride {
+			m.IsConfirmingSafeOverride = false
+			if keyMsg.String() == "y" {
+				return utils.StartTestDataInsert(m)
+			}
+			m.QueryResult = fmt.Sprintf("Insert cancelled (%s)", utils.WriteConfirmLabel(m))
+			return m, utils.ClearResultAfterTimeout()
+		}
+
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to the tables view
+			m = utils.PopView(m, models.TablesView)
+			m.TestDataCountInput.Blur()
+			m.Err = nil
+			m.QueryResult = ""
+			return m, nil
+
+		case "enter":
+			return utils.RequestTestDataInsert(m)
+		}
+	}
+
+	m.TestDataCountInput, cmd = m.TestDataCountInput.Update(msg)
+	return m, cmd
+}
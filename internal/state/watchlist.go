@@ -0,0 +1,49 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// HandleWatchlistViewUpdate handles all updates for the WatchlistView state.
+func HandleWatchlistViewUpdate(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to where the watchlist was opened; auto-refresh pauses until it is reopened
+			m = utils.PopView(m, models.TablesView)
+			m.Err = nil
+			return m, nil
+
+		case "ctrl+r":
+			// Refresh every watched row now
+			return utils.RefreshWatchlist(m)
+
+		case "i":
+			// Cycle the auto-refresh interval
+			m.WatchlistInterval = utils.NextWatchlistInterval(m.WatchlistInterval)
+			m.WatchlistSeq++ // Drop the tick of the previous interval
+			if m.IsRefreshingWatchlist {
+				return m, nil // The running refresh schedules the next tick
+			}
+			return utils.ScheduleWatchlistTick(m)
+
+		case "d", "delete":
+			// Stop watching the selected row
+			cursor := m.WatchlistTable.Cursor()
+			if cursor < 0 || cursor >= len(m.Watchlist) || m.IsRefreshingWatchlist {
+				return m, nil
+			}
+			m.Watchlist = append(append([]models.WatchedRow(nil), m.Watchlist[:cursor]...), m.Watchlist[cursor+1:]...)
+			m = utils.BuildWatchlistTable(m)
+			m.WatchlistTable.SetCursor(utils.Min(cursor, utils.Max(len(m.Watchlist)-1, 0)))
+			return m, nil
+		}
+	}
+
+	m.WatchlistTable, cmd = m.WatchlistTable.Update(msg)
+	return m, cmd
+}
@@ -101,6 +101,11 @@ var (
 			Foreground(AccentBlue).
 			Bold(true)
 
+	// Key binding help for actions the driver does not support
+	DisabledKeyStyle = lipgloss.NewStyle().
+				Foreground(DarkGray).
+				Strikethrough(true)
+
 		// Error messages
 	ErrorStyle = lipgloss.NewStyle().
 			Foreground(ErrorRed).
@@ -153,6 +158,80 @@ var (
 	TypeBadgeStyle = lipgloss.NewStyle().
 			Foreground(AccentBlue).
 			Bold(true)
+
+	// Row of column types shown above a result table's headers
+	ColumnTypesStyle = lipgloss.NewStyle().
+				Foreground(LightGray).
+				Italic(true)
+
+	// Row of per-column page counts shown above the data preview's headers
+	ColumnSummaryStyle = lipgloss.NewStyle().
+				Foreground(AccentBlue)
+
+	// UUID and ULID values in row details
+	IdentifierStyle = lipgloss.NewStyle().
+			Foreground(LightBlue).
+			Italic(true)
+
+	// Title bar shown above every view while connected to a production connection
+	ProductionBannerStyle = lipgloss.NewStyle().
+				Foreground(White).
+				Background(WarningOrange).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Title bar shown above every view while connected to a staging or dev connection
+	EnvironmentBannerStyle = lipgloss.NewStyle().
+				Foreground(LightGray).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Banner shown above every view while safe mode is on
+	SafeModeBannerStyle = lipgloss.NewStyle().
+				Foreground(White).
+				Background(ErrorRed).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Banner shown above every view while writes are recorded into a migration file
+	RecordingBannerStyle = lipgloss.NewStyle().
+				Foreground(White).
+				Background(AccentBlue).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Banner shown above every view while reads are routed to a read replica
+	ReplicaBannerStyle = lipgloss.NewStyle().
+				Foreground(White).
+				Background(SuccessGreen).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Banner shown above every view while connected to a read-only connection
+	ReadOnlyBannerStyle = lipgloss.NewStyle().
+				Foreground(White).
+				Background(DarkGray).
+				Bold(true).
+				Padding(0, 1).
+				Margin(1, 2, 0, 2)
+
+	// Path of screens esc returns through, shown above the view
+	BreadcrumbStyle = lipgloss.NewStyle().
+			Foreground(LightGray).
+			Margin(1, 2, 0, 2)
+
+	// Banner shown above every view after the database connection dropped
+	ConnectionLostBannerStyle = lipgloss.NewStyle().
+					Foreground(White).
+					Background(WarningOrange).
+					Bold(true).
+					Padding(0, 1).
+					Margin(1, 2, 0, 2)
 )
 
 // GetBlueTableStyles returns table styles with blue theme
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// AggregateValues counts the numeric cells of a column and computes their
+// exact sum, average, minimum, and maximum. NULL cells and values that are not
+// numbers are skipped; ok is false when none is left. nulls may be shorter
+// than values.
+func AggregateValues(values []string, nulls []bool) (models.ColumnAggregate, bool) {
+	var agg models.ColumnAggregate
+	sum := new(big.Rat)
+	var minValue, maxValue *big.Rat
+	places := 0
+	for i, value := range values {
+		if i < len(nulls) && nulls[i] {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		n, ok := new(big.Rat).SetString(value)
+		if !ok {
+			continue
+		}
+		agg.Count++
+		sum.Add(sum, n)
+		places = max(places, decimalPlaces(value))
+		if minValue == nil || n.Cmp(minValue) < 0 {
+			minValue, agg.Min = n, value
+		}
+		if maxValue == nil || n.Cmp(maxValue) > 0 {
+			maxValue, agg.Max = n, value
+		}
+	}
+	if agg.Count == 0 {
+		return models.ColumnAggregate{}, false
+	}
+	agg.Sum = sum.FloatString(places)
+	agg.Avg = new(big.Rat).Quo(sum, new(big.Rat).SetInt64(agg.Count)).FloatString(places + 2)
+	return agg, true
+}
+
+// decimalPlaces returns the fraction digits of a plain decimal; numbers with an
+// exponent get six
+func decimalPlaces(value string) int {
+	if strings.ContainsAny(value, "eE") {
+		return 6
+	}
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		return len(value) - dot - 1
+	}
+	return 0
+}
+
+// FormatColumnAggregate renders an aggregate for a footer line, e.g.
+// "sum 1,234.5 · avg 49.38 · min 1 · max 99"
+func FormatColumnAggregate(agg models.ColumnAggregate) string {
+	if agg.Count == 0 {
+		return "no values"
+	}
+	return fmt.Sprintf("sum %s · avg %s · min %s · max %s",
+		formatAggregate(agg.Sum), formatAggregate(agg.Avg), formatAggregate(agg.Min), formatAggregate(agg.Max))
+}
+
+// isNumericType reports whether a database type holds integers or decimals
+func isNumericType(dbType string) bool {
+	family := ClassifyColumnType(dbType)
+	return family == FamilyInteger || family == FamilyDecimal
+}
+
+// PreviewAggregateColumn returns the preview's selected column, the first
+// visible one, and its index when it is numeric
+func PreviewAggregateColumn(m models.Model) (string, int, bool) {
+	column := PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)
+	for i, col := range m.DataPreviewAllColumns {
+		if col == column && i < len(m.DataPreviewColumnTypes) && isNumericType(m.DataPreviewColumnTypes[i]) {
+			return column, i, true
+		}
+	}
+	return "", 0, false
+}
+
+// aggregateKey identifies the table, column, and filter of a full aggregate
+func aggregateKey(m models.Model, column string) string {
+	return strings.Join([]string{m.SelectedSchema, m.SelectedTable, column, m.DataPreviewFilterValue}, "\x00")
+}
+
+// PreviewAggregateFooter summarizes the selected numeric column over the
+// loaded page, followed by its aggregate over the whole filtered table once
+// loaded. It is empty when the column is not numeric.
+func PreviewAggregateFooter(m models.Model) string {
+	column, index, ok := PreviewAggregateColumn(m)
+	if !ok {
+		return ""
+	}
+	values := make([]string, len(m.DataPreviewAllRows))
+	nulls := make([]bool, len(m.DataPreviewAllRows))
+	for i, row := range m.DataPreviewAllRows {
+		if index < len(row) {
+			values[i] = row[index]
+		}
+		nulls[i] = index >= len(row) || i < len(m.DataPreviewNulls) && index < len(m.DataPreviewNulls[i]) && m.DataPreviewNulls[i][index]
+	}
+	footer := fmt.Sprintf("Σ %s • page: ", column)
+	if agg, ok := AggregateValues(values, nulls); ok {
+		footer += FormatColumnAggregate(agg)
+	} else {
+		footer += "no values"
+	}
+
+	scope := "all rows"
+	if m.DataPreviewFilterValue != "" {
+		scope = "all filtered rows"
+	}
+	switch {
+	case m.IsLoadingAggregate:
+		footer += fmt.Sprintf(" • %s: ⏳", scope)
+	case m.FullAggregateKey == aggregateKey(m, column):
+		footer += fmt.Sprintf(" • %s: %s", scope, FormatColumnAggregate(m.FullAggregate))
+	}
+	return footer
+}
+
+// StartFullAggregate computes the selected numeric column's aggregate over
+// every row the preview filter matches
+func StartFullAggregate(m models.Model) (models.Model, tea.Cmd) {
+	if m.IsLoadingAggregate {
+		return m, nil
+	}
+	column, _, ok := PreviewAggregateColumn(m)
+	if !ok {
+		return SetErrorWithTimeout(m, fmt.Errorf("%s is not a numeric column", PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset)), 3*time.Second)
+	}
+	if IsJSONColumn(m, column) {
+		return SetErrorWithTimeout(m, fmt.Errorf("%s is a JSON path column and is only aggregated over the page", column), 3*time.Second)
+	}
+	updatedModel, db := RouteRead(m)
+	updatedModel.IsLoadingAggregate = true
+	updatedModel.Err = nil
+	return updatedModel, LoadColumnAggregate(db, m.SelectedDB, m.SelectedSchema, m.SelectedTable, column, m.DataPreviewFilterValue, PreviewTableColumns(m), PreviewJSONColumns(m), aggregateKey(m, column))
+}
+
+// LoadColumnAggregate aggregates a column over the rows a filter matches
+func LoadColumnAggregate(db *sql.DB, selectedDB models.DBType, schema, table, column, filterValue string, columns []string, jsonColumns []models.JSONColumn, key string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		agg, err := database.GetColumnAggregate(db, selectedDB.Driver, schema, table, column, filterValue, columns, jsonColumns)
+		return models.ColumnAggregateResult{Key: key, Aggregate: agg, Err: err}
+	})
+}
+
+// HandleColumnAggregateResult keeps a finished full aggregate for the footer
+func HandleColumnAggregateResult(m models.Model, msg models.ColumnAggregateResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingAggregate = false
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+	updatedModel.FullAggregate = msg.Aggregate
+	updatedModel.FullAggregateKey = msg.Key
+	return updatedModel, nil
+}
+
+// FirstNumericColumn returns the index of the first integer or decimal column,
+// or 0 when there is none
+func FirstNumericColumn(types []string) int {
+	for i, dbType := range types {
+		if isNumericType(dbType) {
+			return i
+		}
+	}
+	return 0
+}
+
+// MoveQueryResultColumn selects the next (1) or previous (-1) result column for
+// the footer aggregate
+func MoveQueryResultColumn(m models.Model, delta int) models.Model {
+	if len(m.LastQueryColumns) == 0 {
+		return m
+	}
+	updatedModel := m
+	updatedModel.QueryResultColumn = Min(Max(m.QueryResultColumn+delta, 0), len(m.LastQueryColumns)-1)
+	return updatedModel
+}
+
+// QueryResultAggregateFooter summarizes the selected result column over every
+// row of the result, or names the column when it is not numeric
+func QueryResultAggregateFooter(m models.Model) string {
+	col := m.QueryResultColumn
+	if m.QueryResultTransposed || len(m.LastQueryRows) == 0 || col < 0 || col >= len(m.LastQueryColumns) {
+		return ""
+	}
+	column := m.LastQueryColumns[col]
+	if col >= len(m.QueryResultColumnTypes) || !isNumericType(m.QueryResultColumnTypes[col]) {
+		return fmt.Sprintf("Σ %s • not numeric", column)
+	}
+	values := make([]string, len(m.LastQueryRows))
+	for i, row := range m.LastQueryRows {
+		if col < len(row) {
+			values[i] = row[col]
+		}
+	}
+	agg, ok := AggregateValues(values, nil)
+	if !ok {
+		return fmt.Sprintf("Σ %s • %d rows: no values", column, len(m.LastQueryRows))
+	}
+	return fmt.Sprintf("Σ %s • %d rows: %s", column, len(m.LastQueryRows), FormatColumnAggregate(agg))
+}
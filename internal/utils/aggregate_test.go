@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestAggregateValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		nulls  []bool
+		want   models.ColumnAggregate
+		wantOK bool
+	}{
+		{"integers", []string{"3", "1", "8"}, nil, models.ColumnAggregate{Count: 3, Sum: "12", Avg: "4.00", Min: "1", Max: "8"}, true},
+		{"decimals stay exact", []string{"0.1", "0.2", "0.30"}, nil, models.ColumnAggregate{Count: 3, Sum: "0.60", Avg: "0.2000", Min: "0.1", Max: "0.30"}, true},
+		{"beyond int64", []string{"9223372036854775807", "1"}, nil, models.ColumnAggregate{Count: 2, Sum: "9223372036854775808", Avg: "4611686018427387904.00", Min: "1", Max: "9223372036854775807"}, true},
+		{"NULLs and text skipped", []string{"5", "", "NULL", "-2"}, []bool{false, true}, models.ColumnAggregate{Count: 2, Sum: "3", Avg: "1.50", Min: "-2", Max: "5"}, true},
+		{"no numbers", []string{"NaN", ""}, []bool{false, true}, models.ColumnAggregate{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AggregateValues(tt.values, tt.nulls)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("AggregateValues(%q) = %+v, %v, want %+v, %v", tt.values, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPreviewAggregateFooter(t *testing.T) {
+	m := models.Model{
+		SelectedSchema:         "public",
+		SelectedTable:          "orders",
+		DataPreviewAllColumns:  []string{"total", "status"},
+		DataPreviewColumnTypes: []string{"NUMERIC", "TEXT"},
+		DataPreviewAllRows:     [][]string{{"1200.50", "paid"}, {"", "open"}, {"34", "paid"}},
+		DataPreviewNulls:       [][]bool{{false, false}, {true, false}},
+	}
+
+	want := "Σ total • page: sum 1,234.5 · avg 617.25 · min 34 · max 1,200.5"
+	if got := PreviewAggregateFooter(m); got != want {
+		t.Errorf("PreviewAggregateFooter() = %q, want %q", got, want)
+	}
+
+	full, _ := HandleColumnAggregateResult(m, models.ColumnAggregateResult{
+		Key:       aggregateKey(m, "total"),
+		Aggregate: models.ColumnAggregate{Count: 1000, Sum: "98765.4321", Avg: "98.7654321", Min: "0", Max: "5000"},
+	})
+	want += " • all rows: sum 98,765.43 · avg 98.77 · min 0 · max 5,000"
+	if got := PreviewAggregateFooter(full); got != want {
+		t.Errorf("PreviewAggregateFooter() with the full aggregate = %q, want %q", got, want)
+	}
+
+	// The full aggregate belongs to the filter it was computed for
+	full.DataPreviewFilterValue = "paid"
+	if got := PreviewAggregateFooter(full); got != "Σ total • page: sum 1,234.5 · avg 617.25 · min 34 · max 1,200.5" {
+		t.Errorf("PreviewAggregateFooter() after a new filter = %q", got)
+	}
+
+	full.DataPreviewScrollOffset = 1
+	if got := PreviewAggregateFooter(full); got != "" {
+		t.Errorf("PreviewAggregateFooter() of a text column = %q, want empty", got)
+	}
+}
+
+func TestQueryResultAggregateFooter(t *testing.T) {
+	m := ShowQueryResult(models.Model{}, models.QueryResultMsg{
+		Result:      "2 rows",
+		Columns:     []string{"name", "qty"},
+		ColumnTypes: []string{"TEXT", "INTEGER"},
+		Rows:        [][]string{{"a", "2"}, {"b", "NULL"}},
+	})
+	if got, want := QueryResultAggregateFooter(m), "Σ qty • 2 rows: sum 2 · avg 2 · min 2 · max 2"; got != want {
+		t.Errorf("QueryResultAggregateFooter() = %q, want %q", got, want)
+	}
+	m = MoveQueryResultColumn(MoveQueryResultColumn(m, -1), -1)
+	if got, want := QueryResultAggregateFooter(m), "Σ name • not numeric"; got != want {
+		t.Errorf("QueryResultAggregateFooter() after moving left = %q, want %q", got, want)
+	}
+}
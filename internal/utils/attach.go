@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// runAttachStatement attaches or detaches a SQLite database on every connection
+// of the pool rather than only the one the statement would run on
+func runAttachStatement(db *sql.DB, stmt database.AttachStatement) models.QueryResultMsg {
+	if stmt.Detach {
+		if err := database.DetachSQLite(db, stmt.Schema); err != nil {
+			return models.QueryResultMsg{Err: err}
+		}
+		return models.QueryResultMsg{Result: fmt.Sprintf("Detached %s.", stmt.Schema)}
+	}
+	if err := database.AttachSQLite(db, stmt.File, stmt.Schema); err != nil {
+		return models.QueryResultMsg{Err: err}
+	}
+	return models.QueryResultMsg{Result: attachedMessage(stmt)}
+}
+
+// attachedMessage confirms an attached database and how to reach its tables
+func attachedMessage(stmt database.AttachStatement) string {
+	return fmt.Sprintf("Attached %s as %s. Query its tables as %s, or press S in the table list to browse it.",
+		stmt.File, stmt.Schema, database.QualifiedTableName("sqlite3", stmt.Schema, "table"))
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+func TestParseAttachStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    database.AttachStatement
+		wantOK  bool
+		wantErr bool
+	}{
+		{"attach", "ATTACH DATABASE 'other.db' AS other", database.AttachStatement{File: "other.db", Schema: "other"}, true, false},
+		{"without DATABASE", "attach 'data/archive.db' as archive;", database.AttachStatement{File: "data/archive.db", Schema: "archive"}, true, false},
+		{"quoted name", `ATTACH 'a.db' AS "my db"`, database.AttachStatement{File: "a.db", Schema: "my db"}, true, false},
+		{"bracketed name", "ATTACH 'a.db' AS [logs]", database.AttachStatement{File: "a.db", Schema: "logs"}, true, false},
+		{"quote in file", "ATTACH 'o''brien.db' AS ob", database.AttachStatement{File: "o'brien.db", Schema: "ob"}, true, false},
+		{"across lines", "ATTACH DATABASE\n  'other.db'\nAS other", database.AttachStatement{File: "other.db", Schema: "other"}, true, false},
+		{"file expression", "ATTACH DATABASE :file AS other", database.AttachStatement{}, true, true},
+		{"invalid name", "ATTACH 'a.db' AS other db", database.AttachStatement{}, true, true},
+		{"detach", "DETACH DATABASE other", database.AttachStatement{Detach: true, Schema: "other"}, true, false},
+		{"detach quoted", `detach "my db";`, database.AttachStatement{Detach: true, Schema: "my db"}, true, false},
+		{"other statement", "SELECT * FROM attachments", database.AttachStatement{}, false, false},
+		{"empty", "  ", database.AttachStatement{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := database.ParseAttachStatement(tt.query)
+			if ok != tt.wantOK || (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttachStatement() ok = %v, error = %v; want ok %v, wantErr %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAttachStatement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedTableNameSQLite(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{"", `"users"`},
+		{"main", `"users"`},
+		{"other", `"other"."users"`},
+		{"temp", `"temp"."users"`},
+	}
+	for _, tt := range tests {
+		if got := database.QualifiedTableName("sqlite3", tt.schema, "users"); got != tt.want {
+			t.Errorf("QualifiedTableName(%q) = %s, want %s", tt.schema, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+var (
+	sqlLineComment  = regexp.MustCompile(`(?m)--.*$`)
+	sqlBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	sqlWord         = regexp.MustCompile(`[A-Za-z_]+`)
+)
+
+// RedactConnectionString masks the secrets of a connection string for display
+// and the audit log
+func RedactConnectionString(driver, connectionStr string) string {
+	return database.RedactSecrets(driver, connectionStr, "xxxxx")
+}
+
+// RecordAudit appends a write statement to the audit log. Failures to write the
+// log are ignored so a full disk never blocks working with the database.
+func RecordAudit(selectedDB models.DBType, connectionStr, kind, statement string, args []string, rowsAffected int64, execErr error) {
+	entry := models.AuditEntry{
+		Timestamp:    time.Now(),
+		Driver:       selectedDB.Driver,
+		Connection:   RedactConnectionString(selectedDB.Driver, connectionStr),
+		Kind:         kind,
+		Statement:    statement,
+		Args:         args,
+		RowsAffected: rowsAffected,
+	}
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+	config.AppendAuditEntry(entry)
+}
+
+// LoadAuditLog loads the audit log from disk
+func LoadAuditLog() tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		entries, err := config.LoadAuditEntries()
+		return models.AuditLogResult{Entries: entries, Err: err}
+	})
+}
+
+// BuildAuditRows converts audit entries into table rows, newest first
+func BuildAuditRows(entries []models.AuditEntry) []table.Row {
+	rows := make([]table.Row, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		status := fmt.Sprintf("%d", e.RowsAffected)
+		if e.Error != "" {
+			status = "error"
+		} else if e.RowsAffected < 0 {
+			status = "n/a"
+		}
+		rows = append(rows, table.Row{
+			e.Timestamp.Format("2006-01-02 15:04:05"),
+			e.Kind,
+			status,
+			SanitizeValueForDisplay(e.Statement),
+		})
+	}
+	return rows
+}
+
+// HandleAuditLogResult processes audit log result and updates model
+func HandleAuditLogResult(m models.Model, msg models.AuditLogResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingAudit = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	h, v := styles.DocStyle.GetFrameSize()
+	columns := []table.Column{
+		{Title: "Time", Width: 19},
+		{Title: "Kind", Width: 10},
+		{Title: "Rows", Width: 8},
+		{Title: "Statement", Width: Max(m.Width-h-47, 30)},
+	}
+
+	updatedModel.AuditEntries = msg.Entries
+	updatedModel.AuditTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildAuditRows(msg.Entries)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-18, 5)),
+	)
+	updatedModel.AuditTable.SetStyles(styles.GetBlueTableStyles())
+	updatedModel = PushView(updatedModel, models.AuditLogView, models.NavParams{})
+	return updatedModel, nil
+}
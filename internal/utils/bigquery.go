@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+// SupportsServiceAccountKey reports whether the driver signs in with a
+// service-account key file
+func SupportsServiceAccountKey(driver string) bool {
+	return driver == "bigquery"
+}
+
+// ApplyServiceAccountKey writes the connection form's service-account key file
+// into a BigQuery connection string, replacing a file it already names. An
+// empty path changes nothing.
+func ApplyServiceAccountKey(connectionStr, path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" || connectionStr == "" {
+		return connectionStr
+	}
+	base, query, _ := strings.Cut(connectionStr, "?")
+	return joinQuery(base, replaceQueryParams(query, [][2]string{{database.BigQueryCredentialsParam, path}}))
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestApplyServiceAccountKey(t *testing.T) {
+	tests := []struct {
+		name string
+		conn string
+		path string
+		want string
+	}{
+		{"no key file", "bigquery://my-project/sales", "", "bigquery://my-project/sales"},
+		{"key file", "bigquery://my-project/sales", " /keys/reader.json ", "bigquery://my-project/sales?credentials=%2Fkeys%2Freader.json"},
+		{"replaces the key file", "bigquery://my-project?location=EU&credentials=/old.json", "/new.json", "bigquery://my-project?location=EU&credentials=%2Fnew.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyServiceAccountKey(tt.conn, tt.path); got != tt.want {
+				t.Errorf("ApplyServiceAccountKey(%q, %q) = %q, want %q", tt.conn, tt.path, got, tt.want)
+			}
+		})
+	}
+}
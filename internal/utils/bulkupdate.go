@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// DraftFilteredUpdate drafts an UPDATE covering exactly the rows the preview filter
+// matches. The SET clause assigns the column to itself so the draft is a no-op
+// until it is edited.
+func DraftFilteredUpdate(driver, schema, table, filterValue string, columns []string, jsonColumns []models.JSONColumn, setColumn string) (string, error) {
+	where, err := database.FilterWhereClause(driver, filterValue, columns, jsonColumns)
+	if err != nil {
+		return "", err
+	}
+	target := database.QuoteIdentifier(driver, setColumn)
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s",
+		database.QualifiedTableName(driver, schema, table), target, target, where), nil
+}
+
+// DraftUpdateColumn picks the column placed in the drafted SET clause: the first
+// visible column that is not the row key, falling back to the first column.
+func DraftUpdateColumn(columns []string, scrollOffset int) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	key, _, _ := FindPrimaryKeyColumn(columns, columns)
+	for i := Min(Max(scrollOffset, 0), len(columns)-1); i < len(columns); i++ {
+		if columns[i] != key {
+			return columns[i]
+		}
+	}
+	return columns[0]
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestDraftFilteredUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		driver      string
+		schema      string
+		filter      string
+		jsonColumns []models.JSONColumn
+		want        string
+	}{
+		{
+			"postgres",
+			"postgres", "public", "queued", nil,
+			`UPDATE "public"."jobs" SET "status" = "status" WHERE ("id"::TEXT ILIKE '%queued%') OR ("status"::TEXT ILIKE '%queued%')`,
+		},
+		{
+			"mysql without database",
+			"mysql", "", "queued", nil,
+			"UPDATE `jobs` SET `status` = `status` WHERE (CAST(`id` AS CHAR) LIKE '%queued%') OR (CAST(`status` AS CHAR) LIKE '%queued%')",
+		},
+		{
+			"sqlite escapes quotes",
+			"sqlite3", "main", "o'brien", nil,
+			`UPDATE "jobs" SET "status" = "status" WHERE (CAST("id" AS TEXT) LIKE '%o''brien%') OR (CAST("status" AS TEXT) LIKE '%o''brien%')`,
+		},
+		{
+			"column filter matches exactly",
+			"postgres", "public", "Status = o'brien", nil,
+			`UPDATE "public"."jobs" SET "status" = "status" WHERE "status" = 'o''brien'`,
+		},
+		{
+			"unknown column searches every column",
+			"sqlite3", "main", "state = queued", nil,
+			`UPDATE "jobs" SET "status" = "status" WHERE (CAST("id" AS TEXT) LIKE '%state = queued%') OR (CAST("status" AS TEXT) LIKE '%state = queued%')`,
+		},
+		{
+			"JSON path column is searched",
+			"sqlite3", "main", "queued", []models.JSONColumn{{Name: "stage", Column: "status", Path: []models.JSONPathStep{{Key: "stage"}}}},
+			`UPDATE "jobs" SET "status" = "status" WHERE (CAST("id" AS TEXT) LIKE '%queued%') OR (CAST("status" AS TEXT) LIKE '%queued%') OR (CAST(json_extract("status", '$."stage"') AS TEXT) LIKE '%queued%')`,
+		},
+		{
+			"JSON path column filter",
+			"postgres", "public", "stage = queued", []models.JSONColumn{{Name: "stage", Column: "status", Path: []models.JSONPathStep{{Key: "stage"}}}},
+			`UPDATE "public"."jobs" SET "status" = "status" WHERE ("status"::JSONB #>> '{"stage"}') = 'queued'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DraftFilteredUpdate(tt.driver, tt.schema, "jobs", tt.filter, []string{"id", "status"}, tt.jsonColumns, "status")
+			if err != nil || got != tt.want {
+				t.Errorf("DraftFilteredUpdate() =\n%s, %v\nwant\n%s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDraftUpdateColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		offset  int
+		want    string
+	}{
+		{"skips key", []string{"id", "status", "note"}, 0, "status"},
+		{"uses scroll offset", []string{"id", "status", "note"}, 2, "note"},
+		{"only key column", []string{"id"}, 0, "id"},
+		{"offset past end", []string{"id", "status"}, 5, "status"},
+		{"no columns", nil, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DraftUpdateColumn(tt.columns, tt.offset); got != tt.want {
+				t.Errorf("DraftUpdateColumn() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// cancelTimeout bounds the cancel request, which needs a second connection
+// from the pool; when none frees up in time the query's context is cancelled
+// instead
+const cancelTimeout = 3 * time.Second
+
+// StartRunningQuery gives the model a cancellable running query for the query
+// runner to run under, ending any left over from before
+func StartRunningQuery(m models.Model) (models.Model, *models.RunningQuery) {
+	m = FinishRunningQuery(m)
+	m.RunningQuery = models.NewRunningQuery()
+	return m, m.RunningQuery
+}
+
+// FinishRunningQuery releases the running query's context once its result is in
+func FinishRunningQuery(m models.Model) models.Model {
+	if m.RunningQuery != nil {
+		m.RunningQuery.Cancel()
+		m.RunningQuery = nil
+	}
+	m.IsCancellingStatement = false
+	return m
+}
+
+// CancelRunningStatement cancels the query the model is running and nothing
+// else. PostgreSQL and MySQL statements are cancelled on the server by the
+// session id recorded when the statement started; other drivers, or a
+// server-side cancel that fails, fall back to cancelling the query's context.
+func CancelRunningStatement(m models.Model) tea.Cmd {
+	running := m.RunningQuery
+	return tea.Cmd(func() tea.Msg {
+		if running == nil || running.Context.Err() != nil {
+			return models.StatementCancelResult{Err: fmt.Errorf("no statement is running")}
+		}
+		running.MarkCancelled()
+
+		if db, driver, pid := running.Session(); pid != 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+			err := database.CancelBackendStatement(ctx, db, driver, pid)
+			cancel()
+			if err == nil {
+				return models.StatementCancelResult{}
+			}
+		}
+		running.Cancel()
+		return models.StatementCancelResult{}
+	})
+}
+
+// HandleStatementCancelResult reports a cancel that found nothing to cancel; a
+// cancelled statement reports itself when it ends
+func HandleStatementCancelResult(m models.Model, msg models.StatementCancelResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsCancellingStatement = false
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+	return updatedModel, nil
+}
+
+// cancelledStatementError replaces the driver's error for a statement the user
+// cancelled, naming the session it ran on
+func cancelledStatementError(pid int64) error {
+	if pid != 0 {
+		return fmt.Errorf("statement cancelled (our session %d)", pid)
+	}
+	return errors.New("statement cancelled")
+}
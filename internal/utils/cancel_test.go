@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCancelRunningStatement(t *testing.T) {
+	if msg := CancelRunningStatement(models.Model{})().(models.StatementCancelResult); msg.Err == nil {
+		t.Fatal("CancelRunningStatement() with nothing running should fail")
+	}
+
+	// A driver without session ids falls back to cancelling the query's context
+	m, running := StartRunningQuery(models.Model{})
+	if m.RunningQuery != running {
+		t.Fatal("StartRunningQuery() did not keep the running query on the model")
+	}
+	if msg := CancelRunningStatement(m)().(models.StatementCancelResult); msg.Err != nil {
+		t.Fatalf("CancelRunningStatement() error = %v", msg.Err)
+	}
+	if running.Context.Err() == nil {
+		t.Error("the query's context was not cancelled")
+	}
+	if !running.Cancelled() {
+		t.Error("Cancelled() = false after a cancel")
+	}
+	if got := cancelledStatementError(4242).Error(); got != "statement cancelled (our session 4242)" {
+		t.Errorf("cancelledStatementError() = %q", got)
+	}
+
+	m = FinishRunningQuery(m)
+	if m.RunningQuery != nil {
+		t.Error("a finished query is still on the model")
+	}
+	if msg := CancelRunningStatement(m)().(models.StatementCancelResult); msg.Err == nil {
+		t.Error("CancelRunningStatement() after the query finished should fail")
+	}
+}
+
+func TestCancelledQueryStopsScript(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cancel.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, running := StartRunningQuery(models.Model{})
+	CancelRunningStatement(m)()
+	msg := ExecuteQuery(db, models.DBType{Driver: "sqlite3"}, "", "", "SELECT 1; SELECT 2", false, nil, running)().(models.QueryResultMsg)
+	if msg.Err == nil || len(msg.Statements) != 2 || !msg.Statements[1].Skipped {
+		t.Errorf("ExecuteQuery() after a cancel = %v with statements %+v, want the first to fail and the second skipped", msg.Err, msg.Statements)
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// sqlStringLiteral matches a single-quoted literal; a doubled quote inside it is escaped
+var sqlStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// schemaKeywords are the leading statement keywords that change the schema
+var schemaKeywords = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+}
+
+// queryWords returns the upper-cased keywords and names of a query, skipping
+// comments, string literals, and quoted identifiers
+func queryWords(query string) []string {
+	words := sqlWord.FindAllString(stripLiterals(query), -1)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w)
+	}
+	return words
+}
+
+// CheckQuerySyntax explains syntax the driver is known not to support before the
+// query is sent, instead of leaving it to the server's syntax error
+func CheckQuerySyntax(selectedDB models.DBType, query string) error {
+	caps := models.DriverCapabilities(selectedDB.Driver)
+	for _, w := range queryWords(query) {
+		switch {
+		case w == "RETURNING" && !caps.Returning:
+			return fmt.Errorf("%s does not support RETURNING; run a SELECT after the write instead", selectedDB.Name)
+		case w == "ILIKE" && !caps.ILike:
+			return fmt.Errorf("%s does not support ILIKE; use LIKE, which ignores case under its default collation", selectedDB.Name)
+		}
+	}
+	return nil
+}
+
+// ScriptHasSchemaChange reports whether any statement of a script changes the schema
+func ScriptHasSchemaChange(script string) bool {
+	for _, stmt := range SplitStatements(script) {
+		if words := queryWords(stmt); len(words) > 0 && schemaKeywords[words[0]] {
+			return true
+		}
+	}
+	return false
+}
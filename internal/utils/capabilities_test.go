@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCheckQuerySyntax(t *testing.T) {
+	postgres := models.DBType{Name: "PostgreSQL", Driver: "postgres"}
+	mysql := models.DBType{Name: "MySQL", Driver: "mysql"}
+	tests := []struct {
+		name    string
+		db      models.DBType
+		query   string
+		wantErr bool
+	}{
+		{"returning on postgres", postgres, "DELETE FROM users WHERE id = 1 RETURNING *", false},
+		{"returning on mysql", mysql, "delete from users where id = 1 returning id", true},
+		{"ilike on postgres", postgres, "SELECT * FROM users WHERE name ILIKE 'a%'", false},
+		{"ilike on mysql", mysql, "SELECT * FROM users WHERE name ILIKE 'a%'", true},
+		{"word inside a literal", mysql, "SELECT 'returning' AS state, 'it''s ilike' AS note", false},
+		{"word inside a comment", mysql, "-- RETURNING is postgres only\nSELECT 1 /* ILIKE */", false},
+		{"longer word", mysql, "SELECT returning_customer FROM orders", false},
+		{"unknown driver", models.DBType{Name: "Other", Driver: "other"}, "SELECT 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckQuerySyntax(tt.db, tt.query); (err != nil) != tt.wantErr {
+				t.Errorf("CheckQuerySyntax(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScriptHasSchemaChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"select", "SELECT * FROM users", false},
+		{"insert", "INSERT INTO users (name) VALUES ('drop')", false},
+		{"create table", "CREATE TABLE t (id INT)", true},
+		{"alter after comment", "-- add column\nalter table t add c int", true},
+		{"truncate in script", "UPDATE t SET a = 1; TRUNCATE TABLE t", true},
+		{"empty", "  ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScriptHasSchemaChange(tt.script); got != tt.want {
+				t.Errorf("ScriptHasSchemaChange(%q) = %v, want %v", tt.script, got, tt.want)
+			}
+		})
+	}
+}
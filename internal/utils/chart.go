@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ChartBucketLimit is the number of latest time buckets a chart shows
+const ChartBucketLimit = 60
+
+// ChartBuckets and ChartAggregates are the chart's options in the order their keys cycle through them
+var (
+	ChartBuckets    = []string{"day", "week", "month", "year", "hour"}
+	ChartAggregates = []string{"avg", "sum", "min", "max", "count"}
+)
+
+// chartBlocks draw a bar in eighths of a line, from empty to full
+var chartBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// NextChartOption returns the option after current, wrapping around
+func NextChartOption(options []string, current string) string {
+	for i, option := range options {
+		if option == current {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return options[0]
+}
+
+// ChartTimeColumns returns the names of the date and time columns among column
+// definitions of name and type
+func ChartTimeColumns(definitions [][]string) []string {
+	var columns []string
+	for _, def := range definitions {
+		if len(def) < 2 {
+			continue
+		}
+		switch ClassifyColumnType(def[1]) {
+		case FamilyDateTime, FamilyDate:
+			columns = append(columns, def[0])
+		}
+	}
+	return columns
+}
+
+// StartChart opens the time-series chart of a numeric column over the first of
+// the table's date and time columns
+func StartChart(m models.Model, column, dataType string) (models.Model, tea.Cmd) {
+	if !IsNumericType(dataType) {
+		return SetErrorWithTimeout(m, fmt.Errorf("%s is not a numeric column", column), 3*time.Second)
+	}
+	timeColumns := ChartTimeColumns(m.ColumnDefinitions)
+	if len(timeColumns) == 0 {
+		return SetErrorWithTimeout(m, fmt.Errorf("%s has no date or time column to chart over", m.SelectedTable), 3*time.Second)
+	}
+
+	updatedModel := m
+	updatedModel.ChartValueColumn = column
+	updatedModel.ChartTimeColumns = timeColumns
+	updatedModel.ChartTimeIndex = 0
+	if updatedModel.ChartBucket == "" {
+		updatedModel.ChartBucket = ChartBuckets[0]
+	}
+	if updatedModel.ChartAggregate == "" {
+		updatedModel.ChartAggregate = ChartAggregates[0]
+	}
+	updatedModel.ChartPoints = nil
+	updatedModel = PushView(updatedModel, models.ChartView, TableParams(updatedModel))
+	return ReloadChart(updatedModel)
+}
+
+// ReloadChart loads the chart's buckets for its current columns and options
+func ReloadChart(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel, db := RouteRead(m)
+	updatedModel.IsLoadingChart = true
+	updatedModel.Err = nil
+	return updatedModel, LoadTimeSeries(db, m.SelectedDB, m.SelectedSchema, m.SelectedTable,
+		m.ChartTimeColumns[m.ChartTimeIndex], m.ChartValueColumn, m.ChartBucket, m.ChartAggregate)
+}
+
+// LoadTimeSeries aggregates a numeric column per time bucket
+func LoadTimeSeries(db *sql.DB, selectedDB models.DBType, schema, table, timeColumn, valueColumn, bucket, aggregate string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		points, err := database.GetTimeSeries(db, selectedDB.Driver, schema, table, timeColumn, valueColumn, bucket, aggregate, ChartBucketLimit)
+		return models.TimeSeriesResult{Points: points, Err: err}
+	})
+}
+
+// HandleTimeSeriesResult shows the loaded buckets in the chart
+func HandleTimeSeriesResult(m models.Model, msg models.TimeSeriesResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingChart = false
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+	updatedModel.ChartPoints = msg.Points
+	return updatedModel, nil
+}
+
+// ChartRange returns the lowest and highest values of points, widened to
+// include zero so bars start from a zero baseline. ok is false when no bucket
+// has a value.
+func ChartRange(points []models.ChartPoint) (lo, hi float64, ok bool) {
+	for _, p := range points {
+		if p.Null {
+			continue
+		}
+		lo, hi, ok = min(lo, p.Value), max(hi, p.Value), true
+	}
+	return lo, hi, ok
+}
+
+// BarChartRows draws points as vertical bars height lines tall and barWidth
+// characters wide, top line first. Bars rise from lo to hi in eighths of a line;
+// buckets without a value are left empty, and any value above lo shows at least
+// a sliver.
+func BarChartRows(points []models.ChartPoint, lo, hi float64, height, barWidth int) []string {
+	levels := make([]int, len(points))
+	for i, p := range points {
+		if p.Null || hi <= lo {
+			continue
+		}
+		levels[i] = int(math.Round((p.Value - lo) / (hi - lo) * float64(height*8)))
+		if levels[i] == 0 && p.Value > lo {
+			levels[i] = 1
+		}
+	}
+
+	rows := make([]string, height)
+	for r := range rows {
+		floor := (height - 1 - r) * 8
+		var b strings.Builder
+		for _, level := range levels {
+			fill := min(max(level-floor, 0), 8)
+			b.WriteString(strings.Repeat(string(chartBlocks[fill]), barWidth))
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
+
+// FormatChartValue renders an axis or summary value with grouped digits and at
+// most two decimals
+func FormatChartValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return GroupDigits(fmt.Sprintf("%.0f", v))
+	}
+	return GroupDigits(strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), "."))
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNextChartOption(t *testing.T) {
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{"", "day"},
+		{"day", "week"},
+		{"hour", "day"},
+		{"unknown", "day"},
+	}
+	for _, tt := range tests {
+		if got := NextChartOption(ChartBuckets, tt.current); got != tt.want {
+			t.Errorf("NextChartOption(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestChartTimeColumns(t *testing.T) {
+	definitions := [][]string{
+		{"id", "integer", "NO", ""},
+		{"created_at", "timestamp with time zone", "NO", "now()"},
+		{"amount", "numeric(10,2)", "YES", ""},
+		{"shipped_on", "DATE", "YES", ""},
+		{"logged", "datetime", "YES", ""},
+		{"opens_at", "time", "YES", ""},
+		{"broken"},
+	}
+	want := []string{"created_at", "shipped_on", "logged"}
+	if got := ChartTimeColumns(definitions); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChartTimeColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestChartRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []models.ChartPoint
+		lo, hi float64
+		ok     bool
+	}{
+		{"empty", nil, 0, 0, false},
+		{"only nulls", []models.ChartPoint{{Null: true}}, 0, 0, false},
+		{"positive from zero", []models.ChartPoint{{Value: 5}, {Value: 12}, {Null: true}}, 0, 12, true},
+		{"negative to zero", []models.ChartPoint{{Value: -3}, {Value: -1}}, -3, 0, true},
+		{"both signs", []models.ChartPoint{{Value: -2}, {Value: 4}}, -2, 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi, ok := ChartRange(tt.points)
+			if lo != tt.lo || hi != tt.hi || ok != tt.ok {
+				t.Errorf("ChartRange() = %v, %v, %v, want %v, %v, %v", lo, hi, ok, tt.lo, tt.hi, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBarChartRows(t *testing.T) {
+	points := []models.ChartPoint{{Value: 0}, {Value: 1}, {Value: 8}, {Value: 16}, {Null: true}, {Value: 0.01}}
+	got := BarChartRows(points, 0, 16, 2, 1)
+	want := []string{
+		"   █  ",
+		" ▁██ ▁",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BarChartRows() =\n%q\nwant\n%q", got, want)
+	}
+
+	wide := BarChartRows([]models.ChartPoint{{Value: 4}, {Value: 8}}, 0, 8, 1, 2)
+	if want := []string{"▄▄██"}; !reflect.DeepEqual(wide, want) {
+		t.Errorf("BarChartRows() with bar width 2 = %q, want %q", wide, want)
+	}
+
+	flat := BarChartRows([]models.ChartPoint{{Value: 0}, {Value: 0}}, 0, 0, 1, 1)
+	if want := []string{"  "}; !reflect.DeepEqual(flat, want) {
+		t.Errorf("BarChartRows() of zeros = %q, want %q", flat, want)
+	}
+}
+
+func TestFormatChartValue(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{0, "0"},
+		{1234567, "1,234,567"},
+		{-42, "-42"},
+		{1234.5, "1,234.5"},
+		{0.126, "0.13"},
+		{3.001, "3"},
+	}
+	for _, tt := range tests {
+		if got := FormatChartValue(tt.v); got != tt.want {
+			t.Errorf("FormatChartValue(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
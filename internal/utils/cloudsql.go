@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+// SupportsCloudSQL reports whether the driver can connect through the Cloud SQL
+// connector by instance connection name
+func SupportsCloudSQL(driver string) bool {
+	return database.SupportsCloudSQL(driver)
+}
+
+// ApplyCloudSQL writes the connection form's Cloud SQL instance and IAM setting
+// into a PostgreSQL or MySQL connection string, replacing ones it already
+// names. An empty instance changes nothing.
+func ApplyCloudSQL(driver, connectionStr, instance string, iam bool) string {
+	instance = strings.TrimSpace(instance)
+	if instance == "" || connectionStr == "" || !SupportsCloudSQL(driver) {
+		return connectionStr
+	}
+	iamValue := ""
+	if iam {
+		iamValue = "true"
+	}
+	params := [][2]string{{database.CloudSQLInstanceParam, instance}, {database.CloudSQLIAMParam, iamValue}}
+
+	if driver == "postgres" {
+		if strings.Contains(connectionStr, "://") {
+			base, query, _ := strings.Cut(connectionStr, "?")
+			return joinQuery(base, replaceQueryParams(query, params))
+		}
+		return replaceKeywordParams(connectionStr, params)
+	}
+	// Parameters of a MySQL DSN follow its last /, as the driver reads it
+	slash := strings.LastIndex(connectionStr, "/")
+	if slash < 0 {
+		return connectionStr
+	}
+	base, query, _ := strings.Cut(connectionStr[slash:], "?")
+	return joinQuery(connectionStr[:slash]+base, replaceQueryParams(query, params))
+}
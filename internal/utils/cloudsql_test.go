@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestApplyCloudSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		conn     string
+		instance string
+		iam      bool
+		want     string
+	}{
+		{"no instance", "postgres", "postgres://app@localhost:5432/shop", "", true, "postgres://app@localhost:5432/shop"},
+		{"postgres URL", "postgres", "postgres://app@localhost:5432/shop?sslmode=disable", " acme:us-east1:shop ", false, "postgres://app@localhost:5432/shop?sslmode=disable&cloudsql-instance=acme%3Aus-east1%3Ashop"},
+		{"postgres URL with a path parameter", "postgres", "postgres://app@localhost/shop?sslrootcert=/path/ca.pem", "acme:us-east1:shop", false, "postgres://app@localhost/shop?sslrootcert=/path/ca.pem&cloudsql-instance=acme%3Aus-east1%3Ashop"},
+		{"postgres key=value with IAM", "postgres", "host=localhost dbname=shop", "acme:us-east1:shop", true, "host=localhost dbname=shop cloudsql-instance=acme:us-east1:shop cloudsql-iam=true"},
+		{"turns IAM off", "mysql", "app@tcp(localhost:3306)/shop?cloudsql-iam=true", "acme:us-east1:shop", false, "app@tcp(localhost:3306)/shop?cloudsql-instance=acme%3Aus-east1%3Ashop"},
+		{"not hosted by Cloud SQL", "clickhouse", "app@tcp(localhost:9004)/default", "acme:us-east1:shop", false, "app@tcp(localhost:9004)/default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyCloudSQL(tt.driver, tt.conn, tt.instance, tt.iam); got != tt.want {
+				t.Errorf("ApplyCloudSQL(%q, %q) = %q, want %q", tt.driver, tt.conn, got, tt.want)
+			}
+		})
+	}
+}
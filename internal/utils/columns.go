@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// FilterColumnDefinitions keeps the column definitions whose name or type
+// contains the search text, ignoring case
+func FilterColumnDefinitions(definitions [][]string, search string) [][]string {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return definitions
+	}
+
+	var filtered [][]string
+	for _, def := range definitions {
+		for _, field := range def[:Min(2, len(def))] {
+			if strings.Contains(strings.ToLower(field), search) {
+				filtered = append(filtered, def)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// SortColumnDefinitions returns the definitions ordered by one field. Ties keep
+// their declaration order; SortOff leaves the order unchanged.
+func SortColumnDefinitions(definitions [][]string, field int, direction models.SortDirection) [][]string {
+	if direction == models.SortOff {
+		return definitions
+	}
+
+	sorted := append([][]string(nil), definitions...)
+	value := func(i int) string {
+		if field < len(sorted[i]) {
+			return strings.ToLower(sorted[i][field])
+		}
+		return ""
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if direction == models.SortDesc {
+			return value(i) > value(j)
+		}
+		return value(i) < value(j)
+	})
+	return sorted
+}
+
+// NextColumnsSort advances the ColumnsView sort: each field ascending in turn, then off
+func NextColumnsSort(field int, direction models.SortDirection, fieldCount int) (int, models.SortDirection) {
+	if direction == models.SortOff {
+		return 0, models.SortAsc
+	}
+	if field+1 < fieldCount {
+		return field + 1, models.SortAsc
+	}
+	return 0, models.SortOff
+}
+
+// RefreshColumnsTable rebuilds the ColumnsView rows from the current search and sort
+func RefreshColumnsTable(m models.Model) models.Model {
+	updatedModel := m
+	withCollation := len(m.ColumnDefinitions) > 0 && len(m.ColumnDefinitions[0]) == 5
+
+	definitions := FilterColumnDefinitions(m.ColumnDefinitions, m.SearchInput.Value())
+	definitions = SortColumnDefinitions(definitions, m.ColumnsSortField, m.ColumnsSortDirection)
+	rows := make([]table.Row, len(definitions))
+	for i, def := range definitions {
+		rows[i] = table.Row(def)
+	}
+
+	headers := ColumnsTableColumns(withCollation)
+	if m.ColumnsSortDirection != models.SortOff && m.ColumnsSortField < len(headers) {
+		arrow := " ▲"
+		if m.ColumnsSortDirection == models.SortDesc {
+			arrow = " ▼"
+		}
+		headers[m.ColumnsSortField].Title += arrow
+	}
+
+	// Clear rows before changing the column set so stale rows never outnumber columns
+	updatedModel.ColumnsTable.SetRows(nil)
+	updatedModel.ColumnsTable.SetColumns(headers)
+	updatedModel.ColumnsTable.SetRows(rows)
+	updatedModel.ColumnsTable.SetCursor(0)
+	updatedModel.FilteredColumnCount = len(rows)
+	return updatedModel
+}
+
+// ColumnsTableColumns returns the ColumnsView headers, optionally with charset/collation
+func ColumnsTableColumns(withCollation bool) []table.Column {
+	columns := []table.Column{
+		{Title: "Column", Width: 20},
+		{Title: "Type", Width: 15},
+		{Title: "Null", Width: 8},
+		{Title: "Default", Width: 15},
+	}
+	if withCollation {
+		columns = append(columns, table.Column{Title: "Charset / Collation", Width: 30})
+	}
+	return columns
+}
+
+// HandleColumnsResult processes columns result and updates model
+func HandleColumnsResult(m models.Model, msg models.ColumnsResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingColumns = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	// MySQL reports a charset/collation per column; other drivers have four fields
+	width := 4
+	for _, col := range msg.Columns {
+		if len(col) > 4 {
+			width = 5
+			break
+		}
+	}
+
+	// Keep the definitions (msg.Columns is [][]string) for searching and sorting, padding missing fields
+	definitions := make([][]string, len(msg.Columns))
+	for i, col := range msg.Columns {
+		def := make([]string, width)
+		copy(def, col)
+		definitions[i] = def
+	}
+
+	updatedModel.ColumnDefinitions = definitions
+	updatedModel.ColumnsSortField = 0
+	updatedModel.ColumnsSortDirection = models.SortOff
+	updatedModel.IsSearchingColumns = false
+	updatedModel.SearchInput.SetValue("")
+	updatedModel = RefreshColumnsTable(updatedModel)
+	updatedModel = PushView(updatedModel, models.ColumnsView, TableParams(updatedModel))
+	return updatedModel, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+var testColumnDefinitions = [][]string{
+	{"id", "integer", "NO", ""},
+	{"Email", "varchar(255)", "YES", ""},
+	{"created_at", "timestamp", "NO", "now()"},
+	{"age", "integer", "YES", ""},
+}
+
+func TestFilterColumnDefinitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		want   []string
+	}{
+		{"empty search keeps all", "", []string{"id", "Email", "created_at", "age"}},
+		{"name ignores case", "email", []string{"Email"}},
+		{"matches type", "INTEGER", []string{"id", "age"}},
+		{"default is not searched", "now", nil},
+		{"no match", "missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, def := range FilterColumnDefinitions(testColumnDefinitions, tt.search) {
+				names = append(names, def[0])
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("FilterColumnDefinitions(%q) = %v, want %v", tt.search, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortColumnDefinitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     int
+		direction models.SortDirection
+		want      []string
+	}{
+		{"off keeps declaration order", 0, models.SortOff, []string{"id", "Email", "created_at", "age"}},
+		{"name ascending ignores case", 0, models.SortAsc, []string{"age", "created_at", "Email", "id"}},
+		{"name descending", 0, models.SortDesc, []string{"id", "Email", "created_at", "age"}},
+		{"type ascending is stable", 1, models.SortAsc, []string{"id", "age", "created_at", "Email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, def := range SortColumnDefinitions(testColumnDefinitions, tt.field, tt.direction) {
+				names = append(names, def[0])
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("SortColumnDefinitions(%d, %v) = %v, want %v", tt.field, tt.direction, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextColumnsSort(t *testing.T) {
+	field, direction := 0, models.SortOff
+	want := []struct {
+		field     int
+		direction models.SortDirection
+	}{
+		{0, models.SortAsc},
+		{1, models.SortAsc},
+		{2, models.SortAsc},
+		{0, models.SortOff},
+	}
+
+	for i, w := range want {
+		field, direction = NextColumnsSort(field, direction, 3)
+		if field != w.field || direction != w.direction {
+			t.Fatalf("step %d: NextColumnsSort() = (%d, %v), want (%d, %v)", i, field, direction, w.field, w.direction)
+		}
+	}
+}
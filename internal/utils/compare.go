@@ -0,0 +1,352 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// CompareRangeSize is how many integer key values each checksummed range spans
+const CompareRangeSize = 1000
+
+// maxCompareRanges widens ranges on sparse keys so a comparison stays bounded
+const maxCompareRanges = 10000
+
+// maxReportedKeys caps each list of differing or missing keys in a report
+const maxReportedKeys = 100
+
+// NormalizeValue renders a scanned value so equal data compares equal across drivers
+func NormalizeValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "\x00NULL"
+	case []byte:
+		return string(t)
+	case string:
+		return t
+	case bool:
+		if t {
+			return "1"
+		}
+		return "0"
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(t), 'f', -1, 32)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// HashRow returns a checksum of a row's normalized values
+func HashRow(values []interface{}) string {
+	h := sha256.New()
+	for _, v := range values {
+		h.Write([]byte(NormalizeValue(v)))
+		h.Write([]byte{0x1f})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// RowHashesByKey maps each row's key, taken from its first keyCount values, to the row's checksum
+func RowHashesByKey(rows [][]interface{}, keyCount int) map[string]string {
+	hashes := make(map[string]string, len(rows))
+	for _, row := range rows {
+		parts := make([]string, 0, keyCount)
+		for _, v := range row[:keyCount] {
+			parts = append(parts, NormalizeValue(v))
+		}
+		hashes[strings.Join(parts, ", ")] = HashRow(row)
+	}
+	return hashes
+}
+
+// RangeChecksum combines row checksums into one checksum for a key range
+func RangeChecksum(hashes map[string]string) string {
+	keys := make([]string, 0, len(hashes))
+	for key := range hashes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key + "\x1f" + hashes[key] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// DiffRowHashes lists keys only in source, only in target, and in both with different checksums
+func DiffRowHashes(source, target map[string]string) (missingInTarget, missingInSource, differing []string) {
+	for key, hash := range source {
+		other, ok := target[key]
+		switch {
+		case !ok:
+			missingInTarget = append(missingInTarget, key)
+		case other != hash:
+			differing = append(differing, key)
+		}
+	}
+	for key := range target {
+		if _, ok := source[key]; !ok {
+			missingInSource = append(missingInSource, key)
+		}
+	}
+	sort.Strings(missingInTarget)
+	sort.Strings(missingInSource)
+	sort.Strings(differing)
+	return missingInTarget, missingInSource, differing
+}
+
+// SplitKeyRange splits [lo, hi] into consecutive ranges of at most size keys.
+// The size grows when needed to keep the number of ranges under maxCompareRanges.
+func SplitKeyRange(lo, hi, size int64) [][2]int64 {
+	if hi < lo {
+		return nil
+	}
+	if span := (hi-lo)/maxCompareRanges + 1; span > size {
+		size = span
+	}
+
+	var ranges [][2]int64
+	for start := lo; ; start += size {
+		end := start + size - 1
+		if end >= hi || end < start {
+			ranges = append(ranges, [2]int64{start, hi})
+			return ranges
+		}
+		ranges = append(ranges, [2]int64{start, end})
+	}
+}
+
+// RunTableComparison checksums a table against another table, on the current
+// connection when target is nil or on a saved connection otherwise
+func RunTableComparison(src *sql.DB, srcDB models.DBType, srcSchema, srcTable string, target *models.SavedConnection, dstSchema, dstTable string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		dst, dstDriver, targetName := src, srcDB.Driver, "current connection"
+		if target != nil {
+			db, err := openSavedConnection(*target)
+			if err != nil {
+				return models.CompareResult{Err: fmt.Errorf("failed to connect to %s: %w", target.Name, err)}
+			}
+			defer database.Close(db)
+			dst, dstDriver, targetName = db, target.Driver, target.Name
+		}
+
+		comparison, err := compareTables(src, srcDB.Driver, srcSchema, srcTable, dst, dstDriver, dstSchema, dstTable)
+		comparison.TargetName = targetName
+		return models.CompareResult{Comparison: comparison, Err: err}
+	})
+}
+
+// compareTables matches rows by primary key and checksums them range by range.
+// A single integer key is split into ranges of key values; other keys into
+// ranges of CompareRangeSize rows, paged by key on the source. When both tables
+// are on the same kind of database and it can checksum rows, each range is
+// checksummed on the server and only the ranges that differ are read.
+func compareTables(src *sql.DB, srcDriver, srcSchema, srcTable string, dst *sql.DB, dstDriver, dstSchema, dstTable string) (models.TableComparison, error) {
+	comparison := models.TableComparison{SourceTable: srcTable, TargetTable: dstTable}
+
+	specs, err := database.GetColumnSpecs(src, srcDriver, srcTable, srcSchema)
+	if err != nil {
+		return comparison, err
+	}
+	var keys, others []string
+	integerKey := false
+	for _, col := range specs {
+		if col.IsPrimaryKey {
+			keys = append(keys, col.Name)
+			integerKey = ClassifyColumnType(col.DataType) == FamilyInteger
+		} else {
+			others = append(others, col.Name)
+		}
+	}
+	if len(keys) == 0 {
+		return comparison, fmt.Errorf("%s has no primary key to compare by", srcTable)
+	}
+	comparison.KeyColumns = keys
+
+	// Key columns come first so each row's key can be read off its leading values
+	columns := append(append([]string{}, keys...), others...)
+	if err := database.CheckTargetColumns(dst, dstDriver, dstSchema, dstTable, columns); err != nil {
+		return comparison, err
+	}
+
+	serverChecksums := srcDriver == dstDriver && models.DriverCapabilities(srcDriver).RangeChecksums
+	strays := newStrayRows()
+	compareRange := func(r database.KeyRange) error {
+		comparison.Ranges++
+		if serverChecksums {
+			srcCount, srcSum, err := database.GetRangeChecksum(src, srcDriver, srcSchema, srcTable, columns, keys, r)
+			if err != nil {
+				return err
+			}
+			dstCount, dstSum, err := database.GetRangeChecksum(dst, dstDriver, dstSchema, dstTable, columns, keys, r)
+			if err != nil {
+				return fmt.Errorf("failed to read target table: %w", err)
+			}
+			if srcCount == dstCount && srcSum == dstSum {
+				comparison.SourceRows += srcCount
+				comparison.TargetRows += dstCount
+				return nil
+			}
+		}
+
+		srcRows, err := database.GetRowsInKeyRange(src, srcDriver, srcSchema, srcTable, columns, keys, r)
+		if err != nil {
+			return err
+		}
+		dstRows, err := database.GetRowsInKeyRange(dst, dstDriver, dstSchema, dstTable, columns, keys, r)
+		if err != nil {
+			return fmt.Errorf("failed to read target table: %w", err)
+		}
+		comparison.SourceRows += len(srcRows)
+		comparison.TargetRows += len(dstRows)
+
+		srcHashes := RowHashesByKey(srcRows, len(keys))
+		dstHashes := RowHashesByKey(dstRows, len(keys))
+		if RangeChecksum(srcHashes) == RangeChecksum(dstHashes) {
+			return nil
+		}
+
+		comparison.DifferingRanges++
+		missingInTarget, missingInSource, differing := DiffRowHashes(srcHashes, dstHashes)
+		comparison.DifferingN += len(differing)
+		comparison.Differing = appendCapped(comparison.Differing, differing)
+		strays.add(missingInTarget, srcHashes, missingInSource, dstHashes)
+		return nil
+	}
+
+	if len(keys) == 1 && integerKey {
+		srcLo, srcHi, srcOK, err := database.GetKeyBounds(src, srcDriver, srcSchema, srcTable, keys[0])
+		if err != nil {
+			return comparison, err
+		}
+		dstLo, dstHi, dstOK, err := database.GetKeyBounds(dst, dstDriver, dstSchema, dstTable, keys[0])
+		if err != nil {
+			return comparison, fmt.Errorf("failed to read target table: %w", err)
+		}
+		var ranges [][2]int64
+		switch {
+		case srcOK && dstOK:
+			ranges = SplitKeyRange(min(srcLo, dstLo), max(srcHi, dstHi), CompareRangeSize)
+		case srcOK:
+			ranges = SplitKeyRange(srcLo, srcHi, CompareRangeSize)
+		case dstOK:
+			ranges = SplitKeyRange(dstLo, dstHi, CompareRangeSize)
+		}
+		for _, r := range ranges {
+			if err := compareRange(database.KeyRange{Integer: true, From: r[0], To: r[1]}); err != nil {
+				return comparison, err
+			}
+		}
+	} else {
+		// Each range ends at the source's CompareRangeSize-th key after the last
+		// one; the first and last ranges are open so the target's keys beyond
+		// the source's are read too
+		var after []interface{}
+		for {
+			through, ok, err := database.GetKeyBoundary(src, srcDriver, srcSchema, srcTable, keys, after, CompareRangeSize)
+			if err != nil {
+				return comparison, err
+			}
+			if err := compareRange(database.KeyRange{After: after, Through: through}); err != nil {
+				return comparison, err
+			}
+			if !ok {
+				break
+			}
+			after = through
+		}
+	}
+
+	missingInTarget, missingInSource, differing := strays.match()
+	comparison.MissingTargetN = len(missingInTarget)
+	comparison.MissingSourceN = len(missingInSource)
+	comparison.DifferingN += len(differing)
+	comparison.MissingInTarget = appendCapped(nil, missingInTarget)
+	comparison.MissingInSource = appendCapped(nil, missingInSource)
+	comparison.Differing = appendCapped(comparison.Differing, differing)
+	return comparison, nil
+}
+
+// strayRows collects the rows found on only one side of a range. Databases
+// that order keys differently can put a row in different ranges on each side,
+// so strays are only reported as missing once every range has been read.
+type strayRows struct {
+	source, target           map[string]string
+	sourceOrder, targetOrder []string
+}
+
+func newStrayRows() *strayRows {
+	return &strayRows{source: map[string]string{}, target: map[string]string{}}
+}
+
+// add records a range's keys missing in the target and in the source along with their row checksums
+func (s *strayRows) add(missingInTarget []string, srcHashes map[string]string, missingInSource []string, dstHashes map[string]string) {
+	for _, key := range missingInTarget {
+		s.source[key] = srcHashes[key]
+		s.sourceOrder = append(s.sourceOrder, key)
+	}
+	for _, key := range missingInSource {
+		s.target[key] = dstHashes[key]
+		s.targetOrder = append(s.targetOrder, key)
+	}
+}
+
+// match pairs up strays found on both sides and returns the keys that are
+// really missing in the target, missing in the source, or differing, in the
+// order they were found
+func (s *strayRows) match() (missingInTarget, missingInSource, differing []string) {
+	for _, key := range s.sourceOrder {
+		hash, ok := s.target[key]
+		switch {
+		case !ok:
+			missingInTarget = append(missingInTarget, key)
+		case hash != s.source[key]:
+			differing = append(differing, key)
+		}
+	}
+	for _, key := range s.targetOrder {
+		if _, ok := s.source[key]; !ok {
+			missingInSource = append(missingInSource, key)
+		}
+	}
+	return missingInTarget, missingInSource, differing
+}
+
+// appendCapped appends keys until the list holds maxReportedKeys entries
+func appendCapped(list, keys []string) []string {
+	if room := maxReportedKeys - len(list); room < len(keys) {
+		keys = keys[:max(room, 0)]
+	}
+	return append(list, keys...)
+}
+
+// HandleCompareResult shows the finished comparison report
+func HandleCompareResult(m models.Model, msg models.CompareResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsComparingTables = false
+
+	if msg.Err != nil {
+		updatedModel.CompareStep = models.CompareEnterTable
+		updatedModel.CompareTableInput.Focus()
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	updatedModel.Comparison = msg.Comparison
+	return updatedModel, nil
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"nil", nil, "\x00NULL"},
+		{"bytes", []byte("abc"), "abc"},
+		{"bool true", true, "1"},
+		{"int64", int64(42), "42"},
+		{"float64", 1.5, "1.5"},
+		{"large float64 without exponent", 1e21, "1000000000000000000000"},
+		{"float32", float32(0.1), "0.1"},
+		{"time in another zone", time.Date(2025, 1, 2, 5, 4, 5, 0, time.FixedZone("", 2*3600)), "2025-01-02T03:04:05Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeValue(tt.value); got != tt.want {
+				t.Errorf("NormalizeValue(%#v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRowHashes(t *testing.T) {
+	source := RowHashesByKey([][]interface{}{
+		{int64(1), "alice"},
+		{int64(2), "bob"},
+		{int64(3), "carol"},
+	}, 1)
+	target := RowHashesByKey([][]interface{}{
+		{[]byte("1"), []byte("alice")},
+		{[]byte("3"), []byte("caroline")},
+		{[]byte("4"), []byte("dave")},
+	}, 1)
+
+	missingInTarget, missingInSource, differing := DiffRowHashes(source, target)
+	if !reflect.DeepEqual(missingInTarget, []string{"2"}) {
+		t.Errorf("missingInTarget = %v, want [2]", missingInTarget)
+	}
+	if !reflect.DeepEqual(missingInSource, []string{"4"}) {
+		t.Errorf("missingInSource = %v, want [4]", missingInSource)
+	}
+	if !reflect.DeepEqual(differing, []string{"3"}) {
+		t.Errorf("differing = %v, want [3]", differing)
+	}
+}
+
+func TestRangeChecksum(t *testing.T) {
+	a := RowHashesByKey([][]interface{}{{int64(1), "x"}, {int64(2), "y"}}, 1)
+	b := RowHashesByKey([][]interface{}{{int64(2), "y"}, {int64(1), "x"}}, 1)
+	c := RowHashesByKey([][]interface{}{{int64(1), "x"}, {int64(2), "z"}}, 1)
+
+	if RangeChecksum(a) != RangeChecksum(b) {
+		t.Error("RangeChecksum() depends on row order")
+	}
+	if RangeChecksum(a) == RangeChecksum(c) {
+		t.Error("RangeChecksum() did not change when a value changed")
+	}
+}
+
+func TestSplitKeyRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		lo, hi int64
+		size   int64
+		want   [][2]int64
+	}{
+		{"single range", 1, 10, 100, [][2]int64{{1, 10}}},
+		{"exact split", 1, 20, 10, [][2]int64{{1, 10}, {11, 20}}},
+		{"partial last range", 0, 24, 10, [][2]int64{{0, 9}, {10, 19}, {20, 24}}},
+		{"single key", 5, 5, 10, [][2]int64{{5, 5}}},
+		{"empty", 5, 4, 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitKeyRange(tt.lo, tt.hi, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitKeyRange(%d, %d, %d) = %v, want %v", tt.lo, tt.hi, tt.size, got, tt.want)
+			}
+		})
+	}
+
+	if got := len(SplitKeyRange(0, 1<<40, 10)); got > maxCompareRanges+1 {
+		t.Errorf("SplitKeyRange() on a sparse key produced %d ranges", got)
+	}
+}
+
+// openCompareDB opens a SQLite database holding a table with a composite text
+// and integer key and the given number of rows per region
+func openCompareDB(t *testing.T, name string, rows int) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	statements := []string{`CREATE TABLE stock (region TEXT NOT NULL, id INTEGER NOT NULL, qty INTEGER, PRIMARY KEY (region, id))`}
+	for _, region := range []string{"eu", "us"} {
+		statements = append(statements, fmt.Sprintf(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < %d)
+			INSERT INTO stock SELECT '%s', i, i * 2 FROM n`, rows, region))
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestCompareTablesPagesCompositeKeys(t *testing.T) {
+	src := openCompareDB(t, "source.db", 1200)
+	dst := openCompareDB(t, "target.db", 1200)
+	for _, statement := range []string{
+		`UPDATE stock SET qty = 0 WHERE region = 'us' AND id = 5`,
+		`DELETE FROM stock WHERE region = 'eu' AND id = 1100`,
+		`INSERT INTO stock VALUES ('zz', 1, 1)`,
+	} {
+		if _, err := dst.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	comparison, err := compareTables(src, "sqlite3", "main", "stock", dst, "sqlite3", "main", "stock")
+	if err != nil {
+		t.Fatalf("compareTables() error: %v", err)
+	}
+	if !reflect.DeepEqual(comparison.KeyColumns, []string{"region", "id"}) {
+		t.Errorf("KeyColumns = %v", comparison.KeyColumns)
+	}
+	// 2,400 source rows are read 1,000 at a time
+	if comparison.Ranges != 3 {
+		t.Errorf("Ranges = %d, want 3", comparison.Ranges)
+	}
+	if comparison.SourceRows != 2400 || comparison.TargetRows != 2400 {
+		t.Errorf("rows = %d and %d, want 2400 each", comparison.SourceRows, comparison.TargetRows)
+	}
+	if !reflect.DeepEqual(comparison.MissingInTarget, []string{"eu, 1100"}) {
+		t.Errorf("MissingInTarget = %v, want [eu, 1100]", comparison.MissingInTarget)
+	}
+	if !reflect.DeepEqual(comparison.MissingInSource, []string{"zz, 1"}) {
+		t.Errorf("MissingInSource = %v, want [zz, 1]", comparison.MissingInSource)
+	}
+	if !reflect.DeepEqual(comparison.Differing, []string{"us, 5"}) {
+		t.Errorf("Differing = %v, want [us, 5]", comparison.Differing)
+	}
+}
+
+func TestStrayRowsMatchAcrossRanges(t *testing.T) {
+	strays := newStrayRows()
+	// A row ordered into the first range on one side and the second on the other
+	strays.add([]string{"b"}, map[string]string{"b": "1"}, []string{"c"}, map[string]string{"c": "3"})
+	strays.add([]string{"a"}, map[string]string{"a": "1"}, []string{"b", "d"}, map[string]string{"b": "1", "d": "4"})
+
+	missingInTarget, missingInSource, differing := strays.match()
+	if !reflect.DeepEqual(missingInTarget, []string{"a"}) || !reflect.DeepEqual(missingInSource, []string{"c", "d"}) || differing != nil {
+		t.Errorf("match() = %v, %v, %v, want [a], [c d], []", missingInTarget, missingInSource, differing)
+	}
+}
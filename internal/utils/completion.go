@@ -0,0 +1,326 @@
+package utils
+
+import (
+	"database/sql"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MaxCompletionsShown caps how many candidates the completion popup lists at once
+const MaxCompletionsShown = 8
+
+// completionKeywords are the SQL keywords offered after tables and columns
+var completionKeywords = []string{
+	"SELECT", "FROM", "WHERE", "JOIN", "LEFT", "RIGHT", "INNER", "OUTER", "FULL", "CROSS", "ON", "USING",
+	"AND", "OR", "NOT", "NULL", "IS", "IN", "LIKE", "ILIKE", "BETWEEN", "EXISTS", "AS", "DISTINCT",
+	"GROUP", "ORDER", "BY", "HAVING", "LIMIT", "OFFSET", "UNION", "ALL", "ASC", "DESC",
+	"INSERT", "INTO", "VALUES", "UPDATE", "SET", "DELETE", "RETURNING", "TRUNCATE",
+	"CREATE", "ALTER", "DROP", "TABLE", "INDEX", "VIEW", "WITH", "CASE", "WHEN", "THEN", "ELSE", "END",
+	"COUNT", "SUM", "AVG", "MIN", "MAX", "COALESCE", "EXPLAIN", "ANALYZE", "BEGIN", "COMMIT", "ROLLBACK",
+}
+
+// tableReference matches a table named after FROM, JOIN, UPDATE, or INTO, with
+// its optional alias
+var tableReference = regexp.MustCompile(`(?i)\b(?:from|join|update|into)\s+([\w$."]+)(?:\s+(?:as\s+)?(\w+))?`)
+
+// isCompletionRune reports whether r is part of the word being completed; a
+// dot joins a table or alias to its column
+func isCompletionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '.'
+}
+
+// CompletionWord returns the word before the cursor, which is on line row of
+// value at rune column col
+func CompletionWord(value string, row, col int) string {
+	lines := strings.Split(value, "\n")
+	if row < 0 || row >= len(lines) {
+		return ""
+	}
+	line := []rune(lines[row])
+	col = min(max(col, 0), len(line))
+	start := col
+	for start > 0 && isCompletionRune(line[start-1]) {
+		start--
+	}
+	return string(line[start:col])
+}
+
+// EditorCompletionWord returns the word before the query editor's cursor
+func EditorCompletionWord(m models.Model) string {
+	info := m.QueryInput.LineInfo()
+	return CompletionWord(m.QueryInput.Value(), m.QueryInput.Line(), info.StartColumn+info.ColumnOffset)
+}
+
+// ReferencedTables maps the tables a query names, and their aliases, in lower
+// case to the table of tables they refer to
+func ReferencedTables(query string, tables []string) map[string]string {
+	refs := map[string]string{}
+	for _, match := range tableReference.FindAllStringSubmatch(query, -1) {
+		name := strings.ReplaceAll(match[1], `"`, "")
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		table := completionTable(name, tables)
+		if table == "" {
+			continue
+		}
+		refs[strings.ToLower(table)] = table
+		if alias := match[2]; alias != "" && !slices.Contains(completionKeywords, strings.ToUpper(alias)) {
+			refs[strings.ToLower(alias)] = table
+		}
+	}
+	return refs
+}
+
+// completionTable returns the table of tables named name, ignoring case
+func completionTable(name string, tables []string) string {
+	for _, table := range tables {
+		if strings.EqualFold(table, name) {
+			return table
+		}
+	}
+	return ""
+}
+
+// CompletionCandidates lists what word may complete to. After a table name or
+// alias and a dot they are that table's columns; otherwise the tables, then the
+// columns of the tables the query names, then keywords, in the case the word is
+// typed in. columns holds the columns fetched so far by table.
+func CompletionCandidates(word, query string, tables []string, columns map[string][]string) []string {
+	if word == "" {
+		return nil
+	}
+	refs := ReferencedTables(query, tables)
+	var candidates []string
+	add := func(candidate string) {
+		if candidate != word && strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word)) && !slices.Contains(candidates, candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if dot := strings.LastIndex(word, "."); dot >= 0 {
+		qualifier := word[:dot]
+		table := refs[strings.ToLower(qualifier)]
+		if table == "" {
+			table = completionTable(qualifier, tables)
+		}
+		for _, column := range columns[table] {
+			add(qualifier + "." + column)
+		}
+		return candidates
+	}
+
+	for _, table := range tables {
+		add(table)
+	}
+	for _, table := range sortedValues(refs) {
+		for _, column := range columns[table] {
+			add(column)
+		}
+	}
+	lower := strings.ToLower(word) == word
+	for _, keyword := range completionKeywords {
+		if lower {
+			keyword = strings.ToLower(keyword)
+		}
+		add(keyword)
+	}
+	return candidates
+}
+
+// sortedValues returns the distinct values of refs in order
+func sortedValues(refs map[string]string) []string {
+	var values []string
+	for _, value := range refs {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+	return values
+}
+
+// completionColumnsKey identifies a table in the fetched completion columns
+func completionColumnsKey(schema, table string) string {
+	return schema + "." + table
+}
+
+// completionColumns returns the fetched columns of the selected schema's tables
+func completionColumns(m models.Model) map[string][]string {
+	columns := map[string][]string{}
+	for _, table := range m.Tables {
+		if cols := m.CompletionColumns[completionColumnsKey(m.SelectedSchema, table)]; len(cols) > 0 {
+			columns[table] = cols
+		}
+	}
+	return columns
+}
+
+// LoadCompletionColumns fetches the column names of a table for completion
+func LoadCompletionColumns(db *sql.DB, selectedDB models.DBType, schema, table string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		rows, err := database.GetColumns(db, selectedDB.Driver, table, schema)
+		columns := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if len(row) > 0 {
+				columns = append(columns, row[0])
+			}
+		}
+		return models.CompletionColumnsResult{Schema: schema, Table: table, Columns: columns, Err: err}
+	})
+}
+
+// UpdateCompletions recomputes the open popup's candidates for the word before
+// the cursor, fetching the columns of the tables it needs once. The popup
+// closes when nothing matches and no fetch is running.
+func UpdateCompletions(m models.Model) (models.Model, tea.Cmd) {
+	if !m.CompletionActive {
+		return m, nil
+	}
+	updatedModel := m
+	word := EditorCompletionWord(m)
+	if word == "" {
+		return CloseCompletions(updatedModel), nil
+	}
+
+	// The tables the query names, and the one before a dot, need their columns
+	refs := ReferencedTables(m.QueryInput.Value(), m.Tables)
+	needed := sortedValues(refs)
+	if dot := strings.LastIndex(word, "."); dot >= 0 {
+		if table := refs[strings.ToLower(word[:dot])]; table != "" {
+			needed = append(needed, table)
+		} else if table := completionTable(word[:dot], m.Tables); table != "" {
+			needed = append(needed, table)
+		}
+	}
+	var cmds []tea.Cmd
+	loading := false
+	for _, table := range needed {
+		key := completionColumnsKey(m.SelectedSchema, table)
+		cols, fetched := updatedModel.CompletionColumns[key]
+		if !fetched && m.DB != nil {
+			if len(cmds) == 0 {
+				updatedModel.CompletionColumns = cloneCompletionColumns(m.CompletionColumns)
+			}
+			updatedModel.CompletionColumns[key] = nil
+			cmds = append(cmds, LoadCompletionColumns(m.DB, m.SelectedDB, m.SelectedSchema, table))
+			loading = true
+		} else if fetched && cols == nil {
+			loading = true
+		}
+	}
+
+	updatedModel.Completions = CompletionCandidates(word, m.QueryInput.Value(), m.Tables, completionColumns(updatedModel))
+	if len(updatedModel.Completions) == 0 && !loading {
+		return CloseCompletions(updatedModel), nil
+	}
+	updatedModel.CompletionIndex = min(updatedModel.CompletionIndex, max(len(updatedModel.Completions)-1, 0))
+	return updatedModel, tea.Batch(cmds...)
+}
+
+// cloneCompletionColumns copies the map so a model update never changes an earlier model
+func cloneCompletionColumns(columns map[string][]string) map[string][]string {
+	cloned := make(map[string][]string, len(columns)+1)
+	for key, cols := range columns {
+		cloned[key] = cols
+	}
+	return cloned
+}
+
+// HandleCompletionColumnsResult keeps a table's fetched columns, an empty list
+// when the fetch failed so it is not retried, and refreshes the open popup
+func HandleCompletionColumnsResult(m models.Model, msg models.CompletionColumnsResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.CompletionColumns = cloneCompletionColumns(m.CompletionColumns)
+	columns := msg.Columns
+	if msg.Err != nil || columns == nil {
+		columns = []string{}
+	}
+	updatedModel.CompletionColumns[completionColumnsKey(msg.Schema, msg.Table)] = columns
+	return UpdateCompletions(updatedModel)
+}
+
+// CompletionsLoading reports whether columns the popup waits for are being fetched
+func CompletionsLoading(m models.Model) bool {
+	for _, cols := range m.CompletionColumns {
+		if cols == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenCompletions opens the popup for the word before the cursor; a single
+// candidate is completed right away
+func OpenCompletions(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.CompletionActive = true
+	updatedModel.CompletionIndex = 0
+	updatedModel, cmd := UpdateCompletions(updatedModel)
+	if len(updatedModel.Completions) == 1 && cmd == nil && !CompletionsLoading(updatedModel) {
+		return AcceptCompletion(updatedModel), nil
+	}
+	return updatedModel, cmd
+}
+
+// MoveCompletion selects the next (1) or previous (-1) candidate, wrapping around
+func MoveCompletion(m models.Model, delta int) models.Model {
+	if len(m.Completions) == 0 {
+		return m
+	}
+	updatedModel := m
+	n := len(m.Completions)
+	updatedModel.CompletionIndex = ((m.CompletionIndex+delta)%n + n) % n
+	return updatedModel
+}
+
+// AcceptCompletion replaces the word before the cursor with the selected
+// candidate and closes the popup
+func AcceptCompletion(m models.Model) models.Model {
+	if m.CompletionIndex >= len(m.Completions) {
+		return CloseCompletions(m)
+	}
+	updatedModel := m
+	replaceWordBeforeCursor(&updatedModel.QueryInput, len([]rune(EditorCompletionWord(m))), m.Completions[m.CompletionIndex])
+	return CloseCompletions(updatedModel)
+}
+
+// replaceWordBeforeCursor deletes the n runes before the cursor and types text
+// in their place. The editor only takes keys while focused.
+func replaceWordBeforeCursor(editor *textarea.Model, n int, text string) {
+	if !editor.Focused() {
+		editor.Focus()
+		defer editor.Blur()
+	}
+	for range n {
+		*editor, _ = editor.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	editor.InsertString(text)
+}
+
+// CloseCompletions closes the completion popup
+func CloseCompletions(m models.Model) models.Model {
+	updatedModel := m
+	updatedModel.CompletionActive = false
+	updatedModel.Completions = nil
+	updatedModel.CompletionIndex = 0
+	return updatedModel
+}
+
+// VisibleCompletions returns the window of candidates the popup lists, which
+// keeps the selected one in view, and the index of the first
+func VisibleCompletions(m models.Model) ([]string, int) {
+	start := 0
+	if m.CompletionIndex >= MaxCompletionsShown {
+		start = m.CompletionIndex - MaxCompletionsShown + 1
+	}
+	end := min(start+MaxCompletionsShown, len(m.Completions))
+	return m.Completions[start:end], start
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCompletionWord(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		row   int
+		col   int
+		want  string
+	}{
+		{"word at the end", "SELECT * FROM ord", 0, 17, "ord"},
+		{"qualified column", "SELECT o.cu", 0, 11, "o.cu"},
+		{"after a space", "SELECT ", 0, 7, ""},
+		{"on a later line", "SELECT *\nFROM cust", 1, 9, "cust"},
+		{"cursor inside a word", "SELECT name", 0, 9, "na"},
+		{"after a parenthesis", "count(us", 0, 8, "us"},
+		{"row out of range", "SELECT", 3, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompletionWord(tt.value, tt.row, tt.col); got != tt.want {
+				t.Errorf("CompletionWord(%q, %d, %d) = %q, want %q", tt.value, tt.row, tt.col, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferencedTables(t *testing.T) {
+	tables := []string{"orders", "customers", "Invoices"}
+	query := `SELECT * FROM public.orders o JOIN customers AS c ON c.id = o.customer_id JOIN "Invoices" WHERE`
+	want := map[string]string{"orders": "orders", "o": "orders", "customers": "customers", "c": "customers", "invoices": "Invoices"}
+	if got := ReferencedTables(query, tables); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReferencedTables() = %v, want %v", got, want)
+	}
+}
+
+func TestCompletionCandidates(t *testing.T) {
+	tables := []string{"orders", "order_items", "customers"}
+	columns := map[string][]string{"orders": {"id", "customer_id", "ordered_at"}, "customers": {"id", "name"}}
+
+	tests := []struct {
+		name  string
+		word  string
+		query string
+		want  []string
+	}{
+		{"tables before keywords", "or", "SELECT * FROM or", []string{"orders", "order_items", "order"}},
+		{"keywords in the typed case", "SEL", "SEL", []string{"SELECT"}},
+		{"columns of named tables", "na", "SELECT na FROM customers", []string{"name"}},
+		{"columns of an alias", "o.cu", "SELECT o.cu FROM orders o", []string{"o.customer_id"}},
+		{"columns of a table", "customers.", "SELECT customers.", []string{"customers.id", "customers.name"}},
+		{"unknown qualifier", "x.i", "SELECT x.i", nil},
+		{"exact match is not offered", "orders", "SELECT * FROM orders", nil},
+		{"no word", "", "SELECT ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompletionCandidates(tt.word, tt.query, tables, columns); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompletionCandidates(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptCompletion(t *testing.T) {
+	m := models.Model{
+		Tables:            []string{"orders", "customers"},
+		SelectedSchema:    "public",
+		CompletionColumns: map[string][]string{"public.orders": {"id", "customer_id"}},
+		QueryInput:        textarea.New(),
+	}
+	m.QueryInput.SetValue("SELECT o.cu\nFROM orders o")
+	m.QueryInput.CursorUp()
+	m.QueryInput.CursorEnd()
+
+	// A single candidate is completed right away
+	got, _ := OpenCompletions(m)
+	if want := "SELECT o.customer_id\nFROM orders o"; got.QueryInput.Value() != want || got.CompletionActive {
+		t.Errorf("OpenCompletions() = %q (popup open %v), want %q", got.QueryInput.Value(), got.CompletionActive, want)
+	}
+
+	// Several candidates open the popup, where the selection wraps around
+	m.QueryInput.SetValue("SELECT * FROM c")
+	got, _ = OpenCompletions(m)
+	if len(got.Completions) < 2 || got.Completions[0] != "customers" || !got.CompletionActive {
+		t.Fatalf("OpenCompletions() candidates = %q, want customers first in an open popup", got.Completions)
+	}
+	got = AcceptCompletion(MoveCompletion(MoveCompletion(got, 1), -1))
+	if want := "SELECT * FROM customers"; got.QueryInput.Value() != want || got.CompletionActive {
+		t.Errorf("AcceptCompletion() = %q (popup open %v), want %q", got.QueryInput.Value(), got.CompletionActive, want)
+	}
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// DefaultPageSize is the data preview page size of connections without their own
+const DefaultPageSize = 40
+
+// MaxPageSize caps a connection's page size, so a typo cannot load a whole table
+const MaxPageSize = 1000
+
+// ParseSortSpec reads a default sort written as "created_at desc, id": columns
+// in priority order, each optionally followed by asc or desc
+func ParseSortSpec(s string) ([]models.SortKey, error) {
+	var keys []models.SortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid sort %q: use column [asc|desc]", strings.TrimSpace(part))
+		}
+		key := models.SortKey{Column: fields[0], Direction: models.SortAsc}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				key.Direction = models.SortDesc
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q: use asc or desc", fields[1])
+			}
+		}
+		if seen[key.Column] {
+			continue
+		}
+		seen[key.Column] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// FormatSortSpec writes sort keys in the form ParseSortSpec reads
+func FormatSortSpec(keys []models.SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.Column
+		if key.Direction == models.SortDesc {
+			parts[i] += " desc"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParsePageSize reads a page size; empty means the default
+func ParsePageSize(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(s)
+	if err != nil || size < 1 || size > MaxPageSize {
+		return 0, fmt.Errorf("page size must be a number from 1 to %d", MaxPageSize)
+	}
+	return size, nil
+}
+
+// ParseTimeoutSetting reads a connection's timeout as typed in the defaults
+// prompt and writes it the way it is saved: "" keeps the default, "0" turns the
+// timeout off, and "90" or "1m30s" becomes "1m30s"
+func ParseTimeoutSetting(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return "", nil
+	}
+	d, err := config.ParseTimeout(s)
+	if err != nil {
+		return "", err
+	}
+	return FormatTimeout(d), nil
+}
+
+// ConnectionTimeouts resolves a connection's timeouts. Unset ones, and saved
+// ones that no longer parse, use MIRADOR_CONNECT_TIMEOUT and
+// MIRADOR_STATEMENT_TIMEOUT.
+func ConnectionTimeouts(conn models.SavedConnection) models.Timeouts {
+	timeouts := models.Timeouts{Connect: config.ConnectTimeout(), Statement: config.StatementTimeout()}
+	if d, err := config.ParseTimeout(conn.ConnectTimeout); conn.ConnectTimeout != "" && err == nil {
+		timeouts.Connect = d
+	}
+	if d, err := config.ParseTimeout(conn.StatementTimeout); conn.StatementTimeout != "" && err == nil {
+		timeouts.Statement = d
+	}
+	return timeouts
+}
+
+// ApplyConnectionDefaults sets up the model for a connection's default schema,
+// page size, sort, and timeouts before connecting. A saved default sort that no
+// longer parses is ignored rather than blocking the connect.
+func ApplyConnectionDefaults(m models.Model, conn models.SavedConnection) models.Model {
+	m.DefaultSchema = strings.TrimSpace(conn.DefaultSchema)
+	m.DataPreviewItemsPerPage = DefaultPageSize
+	if conn.PageSize > 0 {
+		m.DataPreviewItemsPerPage = min(conn.PageSize, MaxPageSize)
+	}
+	m.DefaultSortKeys, _ = ParseSortSpec(conn.DefaultSort)
+	m.Timeouts = ConnectionTimeouts(conn)
+	return m
+}
+
+// SetConnectionDefaults saves a connection's default schema, page size, sort,
+// and timeouts, the latter as written by ParseTimeoutSetting
+func SetConnectionDefaults(m models.Model, name, schema string, pageSize int, sortKeys []models.SortKey, connectTimeout, statementTimeout string) (models.Model, error) {
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	for i := range updatedModel.SavedConnections {
+		if updatedModel.SavedConnections[i].Name == name {
+			updatedModel.SavedConnections[i].DefaultSchema = strings.TrimSpace(schema)
+			updatedModel.SavedConnections[i].PageSize = pageSize
+			updatedModel.SavedConnections[i].DefaultSort = FormatSortSpec(sortKeys)
+			updatedModel.SavedConnections[i].ConnectTimeout = connectTimeout
+			updatedModel.SavedConnections[i].StatementTimeout = statementTimeout
+			if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+				return m, fmt.Errorf("failed to save connections: %w", err)
+			}
+			return UpdateSavedConnectionsList(updatedModel), nil
+		}
+	}
+	return m, fmt.Errorf("connection '%s' not found", name)
+}
+
+// ConnectionDefaultsBadge summarizes a connection's defaults for the saved
+// connections list, e.g. "analytics • 100/page • created_at desc • statement 2m"
+func ConnectionDefaultsBadge(conn models.SavedConnection) string {
+	var parts []string
+	if conn.DefaultSchema != "" {
+		parts = append(parts, conn.DefaultSchema)
+	}
+	if conn.PageSize > 0 {
+		parts = append(parts, fmt.Sprintf("%d/page", conn.PageSize))
+	}
+	if conn.DefaultSort != "" {
+		parts = append(parts, conn.DefaultSort)
+	}
+	if conn.ConnectTimeout != "" {
+		parts = append(parts, "connect "+timeoutLabel(conn.ConnectTimeout))
+	}
+	if conn.StatementTimeout != "" {
+		parts = append(parts, "statement "+timeoutLabel(conn.StatementTimeout))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// timeoutLabel shows a saved timeout, naming one that is turned off
+func timeoutLabel(setting string) string {
+	if d, err := config.ParseTimeout(setting); err == nil && d == 0 {
+		return "off"
+	}
+	return setting
+}
+
+// SortKeysInColumns keeps the sort keys whose column the table has, so a default
+// sort only applies to tables with those columns
+func SortKeysInColumns(keys []models.SortKey, columns []string) []models.SortKey {
+	var kept []models.SortKey
+	for _, key := range keys {
+		for _, column := range columns {
+			if column == key.Column {
+				kept = append(kept, key)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// LoadDefaultSortedPreview loads a table's first page sorted by the
+// connection's default sort, skipping sort columns the table does not have
+func LoadDefaultSortedPreview(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage int, sortKeys []models.SortKey, jsonColumns []models.JSONColumn) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		columns, err := database.GetColumns(db, selectedDB.Driver, selectedTable, selectedSchema)
+		if err != nil {
+			return models.DataPreviewResult{Err: err}
+		}
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			if len(column) > 0 {
+				names[i] = column[0]
+			}
+		}
+		return LoadDataPreview(db, selectedDB, selectedTable, selectedSchema, itemsPerPage, SortKeysInColumns(sortKeys, names), jsonColumns)()
+	})
+}
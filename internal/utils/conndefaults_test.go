@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseSortSpec(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []models.SortKey
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"created_at desc, id", []models.SortKey{{Column: "created_at", Direction: models.SortDesc}, {Column: "id", Direction: models.SortAsc}}, false},
+		{" name ASC ,, name desc", []models.SortKey{{Column: "name", Direction: models.SortAsc}}, false},
+		{"id sideways", nil, true},
+		{"created_at desc nulls", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSortSpec(tt.input)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSortSpec(%q) = %v, %v, want %v (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil {
+			if again, _ := ParseSortSpec(FormatSortSpec(got)); !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseSortSpec(FormatSortSpec(%v)) = %v", got, again)
+			}
+		}
+	}
+}
+
+func TestParsePageSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{" 100 ", 100, false},
+		{"0", 0, true},
+		{"5000", 0, true},
+		{"many", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePageSize(tt.input)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParsePageSize(%q) = %d, %v, want %d (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyConnectionDefaults(t *testing.T) {
+	m := ApplyConnectionDefaults(models.Model{}, models.SavedConnection{DefaultSchema: " analytics ", PageSize: 100, DefaultSort: "created_at desc"})
+	if m.DefaultSchema != "analytics" || m.DataPreviewItemsPerPage != 100 || len(m.DefaultSortKeys) != 1 {
+		t.Errorf("ApplyConnectionDefaults() = (%q, %d, %v)", m.DefaultSchema, m.DataPreviewItemsPerPage, m.DefaultSortKeys)
+	}
+	m = ApplyConnectionDefaults(m, models.SavedConnection{})
+	if m.DefaultSchema != "" || m.DataPreviewItemsPerPage != DefaultPageSize || m.DefaultSortKeys != nil {
+		t.Errorf("ApplyConnectionDefaults() without defaults = (%q, %d, %v)", m.DefaultSchema, m.DataPreviewItemsPerPage, m.DefaultSortKeys)
+	}
+}
+
+func TestSortKeysInColumns(t *testing.T) {
+	keys := []models.SortKey{{Column: "created_at", Direction: models.SortDesc}, {Column: "id", Direction: models.SortAsc}}
+	want := []models.SortKey{{Column: "id", Direction: models.SortAsc}}
+	if got := SortKeysInColumns(keys, []string{"id", "name"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortKeysInColumns() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// ConnectionError is a driver error explained for someone setting up a
+// connection. The raw driver error stays available through Unwrap.
+type ConnectionError struct {
+	Summary   string
+	Hint      string
+	Transient bool // Whether trying again later may succeed
+	Waking    bool // Whether a paused serverless database is resuming
+	Err       error
+}
+
+func (e *ConnectionError) Error() string { return e.Summary }
+func (e *ConnectionError) Unwrap() error { return e.Err }
+
+// connectionErrorKind is one recognized failure: how to spot it and what to suggest
+type connectionErrorKind struct {
+	summary   string
+	hint      string
+	sslMode   string // For SSL errors, the setting to suggest instead of a fixed hint
+	passwords bool   // Whether a connection string without a password gets a credential file hint
+	transient bool
+	waking    bool
+	pqCodes   []string
+	mysqlNums []uint16
+	fragments []string
+}
+
+var connectionErrorKinds = []connectionErrorKind{
+	{
+		summary:   "Authentication failed",
+		hint:      "Check the user name and password in the connection string.",
+		passwords: true,
+		pqCodes:   []string{"28P01", "28000"},
+		mysqlNums: []uint16{1045, 1698},
+		fragments: []string{"password authentication failed", "access denied for user"},
+	},
+	{
+		summary:   "Database does not exist",
+		hint:      "Check the database name in the connection string; list the databases with psql -l or SHOW DATABASES.",
+		pqCodes:   []string{"3D000"},
+		mysqlNums: []uint16{1049},
+		fragments: []string{"unknown database"},
+	},
+	{
+		summary:   "The server does not support SSL",
+		sslMode:   "disable",
+		fragments: []string{"ssl is not enabled on the server"},
+	},
+	{
+		summary:   "The server requires an encrypted (SSL) connection",
+		sslMode:   "require",
+		mysqlNums: []uint16{3159},
+		fragments: []string{"ssl off", "ssl required", "requires ssl", "secure transport required", "require_secure_transport"},
+	},
+	{
+		summary:   "The database is waking up",
+		hint:      "Serverless databases (Neon, Aurora Serverless, PlanetScale) pause when idle and take a moment to resume.",
+		transient: true,
+		waking:    true,
+		pqCodes:   []string{"57P03"},
+		fragments: []string{
+			"the database system is starting up", "compute is starting", "endpoint is waking up",
+			"databaseresumingexception", "database is resuming", "database is paused",
+			"database is sleeping", "database is asleep",
+		},
+	},
+	{
+		summary:   "Could not reach the database server",
+		hint:      "Check the host and port, and that the server is running and accepts TCP connections.",
+		transient: true,
+		fragments: []string{"no such host", "connection refused", "network is unreachable", "no route to host", "cannot assign requested address"},
+	},
+	{
+		summary:   "The connection timed out",
+		hint:      "The server did not answer in time; check the host, firewall, or VPN.",
+		transient: true,
+		fragments: []string{"timeout", "timed out", "deadline exceeded"},
+	},
+	{
+		summary:   "Could not open the SQLite database file",
+		hint:      "Check that the file path exists and is readable and writable.",
+		fragments: []string{"unable to open database file"},
+	},
+	{
+		summary:   "This build cannot open SQLCipher files",
+		hint:      "The bundled SQLite has no SQLCipher support. Build mirador with -tags libsqlite3 against a SQLCipher library installed as the system SQLite, or open a decrypted copy exported with sqlcipher_export.",
+		fragments: []string{"has no sqlcipher support"},
+	},
+	{
+		summary:   "Could not decrypt the SQLite database file",
+		hint:      "Check the SQLCipher key in the connection form, and that the file was encrypted with SQLCipher.",
+		fragments: []string{"sqlcipher key is wrong"},
+	},
+	{
+		summary:   "The file is encrypted or is not a SQLite database",
+		hint:      "If the file is encrypted with SQLCipher, enter its key in the SQLCipher Key field of the connection form of a build with -tags libsqlite3; otherwise check that the path points to a SQLite database.",
+		fragments: []string{"file is not a database", "file is encrypted"},
+	},
+}
+
+// ExplainConnectionError maps common driver errors from connecting to a
+// ConnectionError with a hint; other errors are returned unchanged
+func ExplainConnectionError(driver, connectionStr string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var explained *ConnectionError
+	if errors.As(err, &explained) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	hasPQ := errors.As(err, &pqErr)
+	var mysqlErr *mysql.MySQLError
+	hasMySQL := errors.As(err, &mysqlErr)
+	msg := strings.ToLower(err.Error())
+
+	for _, kind := range connectionErrorKinds {
+		matched := false
+		for _, code := range kind.pqCodes {
+			matched = matched || (hasPQ && string(pqErr.Code) == code)
+		}
+		for _, num := range kind.mysqlNums {
+			matched = matched || (hasMySQL && mysqlErr.Number == num)
+		}
+		for _, fragment := range kind.fragments {
+			matched = matched || strings.Contains(msg, fragment)
+		}
+		if matched {
+			hint := kind.hint
+			if kind.sslMode != "" {
+				hint = sslHint(kind.sslMode, driver, connectionStr)
+			}
+			if kind.passwords && !database.HasPassword(driver, connectionStr) {
+				hint = credentialFileHint(driver, hint)
+			}
+			return &ConnectionError{Summary: kind.summary, Hint: hint, Transient: kind.transient, Waking: kind.waking, Err: err}
+		}
+	}
+	return err
+}
+
+// sslHint suggests the SSL setting in the syntax of the driver and connection
+// string style: a URL query parameter or a key=value pair
+func sslHint(mode, driver, connectionStr string) string {
+	setting := "sslmode=" + mode
+	if driver == "mysql" || driver == "mariadb" || driver == "clickhouse" {
+		setting = "tls=true"
+		if mode == "disable" {
+			setting = "tls=false"
+		}
+	}
+	switch {
+	case strings.Contains(connectionStr, "?"):
+		setting = "&" + setting
+	case strings.Contains(connectionStr, "://") || driver == "mysql" || driver == "mariadb" || driver == "clickhouse":
+		setting = "?" + setting
+	}
+
+	action := "Turn SSL on"
+	if mode == "disable" {
+		action = "Turn SSL off"
+	}
+	return fmt.Sprintf("%s: add %q to the connection string.", action, setting)
+}
+
+// credentialFileHint points a connection string without a password at the
+// credential file its password was looked up in
+func credentialFileHint(driver, hint string) string {
+	switch driver {
+	case "postgres", "cockroach", "redshift":
+		return "The connection string has no password and none matched in ~/.pgpass ($PGPASSFILE). Add a host:port:database:user:password line, and chmod 600 the file: it is ignored when others can read it."
+	case "mysql", "mariadb":
+		return "The connection string has no password and none was found in ~/.my.cnf. Add password=... under [client], or add the password to the connection string."
+	}
+	return hint
+}
+
+// connectionErrorTimeout keeps an explained error on screen long enough to read its hint
+func connectionErrorTimeout(err error) time.Duration {
+	var explained *ConnectionError
+	if errors.As(err, &explained) {
+		return 10 * time.Second
+	}
+	return 3 * time.Second
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+func TestExplainConnectionError(t *testing.T) {
+	tests := []struct {
+		name          string
+		driver        string
+		connectionStr string
+		err           error
+		wantSummary   string
+		wantHint      string
+	}{
+		{"postgres auth", "postgres", "postgres://u:p@h/db", &pq.Error{Code: "28P01", Message: "password authentication failed for user \"u\""}, "Authentication failed", "Check the user name and password in the connection string."},
+		{"mysql unknown database", "mysql", "u:p@tcp(h:3306)/db", &mysql.MySQLError{Number: 1049, Message: "Unknown database 'db'"}, "Database does not exist", ""},
+		{"postgres ssl required, url", "postgres", "postgres://u:p@h/db", errors.New(`pq: no pg_hba.conf entry for host "10.0.0.1", user "u", database "db", SSL off`), "The server requires an encrypted (SSL) connection", `Turn SSL on: add "?sslmode=require" to the connection string.`},
+		{"postgres ssl required, url with query", "postgres", "postgres://u:p@h/db?connect_timeout=5", errors.New("pq: SSL required"), "The server requires an encrypted (SSL) connection", `Turn SSL on: add "&sslmode=require" to the connection string.`},
+		{"postgres ssl unsupported, key=value", "postgres", "host=h dbname=db", errors.New("pq: SSL is not enabled on the server"), "The server does not support SSL", `Turn SSL off: add "sslmode=disable" to the connection string.`},
+		{"mysql secure transport", "mysql", "u:p@tcp(h:3306)/db", &mysql.MySQLError{Number: 3159, Message: "Connections using insecure transport are prohibited"}, "The server requires an encrypted (SSL) connection", `Turn SSL on: add "?tls=true" to the connection string.`},
+		{"clickhouse ssl required", "clickhouse", "default:p@tcp(h:9004)/default", errors.New("SSL required"), "The server requires an encrypted (SSL) connection", `Turn SSL on: add "?tls=true" to the connection string.`},
+		{"unreachable host", "postgres", "postgres://u:p@nowhere/db", errors.New("dial tcp: lookup nowhere: no such host"), "Could not reach the database server", ""},
+		{"timeout", "postgres", "postgres://u:p@h/db", fmt.Errorf("ping: %w", context.DeadlineExceeded), "The connection timed out", ""},
+		{"sqlite encrypted file", "sqlite3", "secret.db", errors.New("file is not a database"), "The file is encrypted or is not a SQLite database", ""},
+		{"sqlcipher wrong key", "sqlite3", "secret.db?_key=k", fmt.Errorf("%w: file is not a database", database.ErrSQLCipherKey), "Could not decrypt the SQLite database file", ""},
+		{"sqlcipher missing", "sqlite3", "secret.db?_key=k", database.ErrNoSQLCipher, "This build cannot open SQLCipher files", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExplainConnectionError(tt.driver, tt.connectionStr, tt.err)
+			var explained *ConnectionError
+			if !errors.As(got, &explained) {
+				t.Fatalf("ExplainConnectionError() = %v, want a *ConnectionError", got)
+			}
+			if explained.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", explained.Summary, tt.wantSummary)
+			}
+			if tt.wantHint != "" && explained.Hint != tt.wantHint {
+				t.Errorf("Hint = %q, want %q", explained.Hint, tt.wantHint)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Error("the raw driver error is not reachable with errors.Is")
+			}
+		})
+	}
+
+	other := errors.New("pq: relation \"users\" does not exist")
+	if got := ExplainConnectionError("postgres", "", other); got != other {
+		t.Errorf("ExplainConnectionError(unrecognized) = %v, want it unchanged", got)
+	}
+	if got := ExplainConnectionError("postgres", "", nil); got != nil {
+		t.Errorf("ExplainConnectionError(nil) = %v, want nil", got)
+	}
+}
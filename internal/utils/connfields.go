@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// SupportsConnectionFields reports whether the connection form can assemble the
+// driver's connection string from separate fields; SQLite takes a file path,
+// Trino a URL whose scheme picks HTTP or HTTPS, and BigQuery a project and dataset
+func SupportsConnectionFields(driver string) bool {
+	return driver != "sqlite3" && driver != "trino" && driver != "bigquery"
+}
+
+// DefaultPort returns the port a driver's server listens on by default
+func DefaultPort(driver string) string {
+	return database.DefaultPort(driver)
+}
+
+// BuildConnectionString assembles a connection string from the form's fields
+func BuildConnectionString(driver string, fields models.ConnectionFields) (string, error) {
+	return database.BuildConnectionString(driver, fields)
+}
+
+// ParseConnectionString splits a connection string into the form's fields, so a
+// string typed in raw mode can be edited as fields
+func ParseConnectionString(driver, connectionStr string) (models.ConnectionFields, error) {
+	return database.ParseConnectionString(driver, connectionStr)
+}
+
+// FormConnectionFields reads the builder fields of the connection form
+func FormConnectionFields(m models.Model) models.ConnectionFields {
+	return models.ConnectionFields{
+		Host:     m.BuilderHostInput.Value(),
+		Port:     m.BuilderPortInput.Value(),
+		User:     m.BuilderUserInput.Value(),
+		Password: m.BuilderPasswordInput.Value(),
+		Database: m.BuilderDatabaseInput.Value(),
+		Options:  m.BuilderOptionsInput.Value(),
+
+		Warehouse: m.BuilderWarehouseInput.Value(),
+		Role:      m.BuilderRoleInput.Value(),
+	}
+}
+
+// SetFormConnectionFields fills the builder fields of the connection form
+func SetFormConnectionFields(m models.Model, fields models.ConnectionFields) models.Model {
+	m.BuilderHostInput.SetValue(fields.Host)
+	m.BuilderPortInput.SetValue(fields.Port)
+	m.BuilderUserInput.SetValue(fields.User)
+	m.BuilderPasswordInput.SetValue(fields.Password)
+	m.BuilderDatabaseInput.SetValue(fields.Database)
+	m.BuilderOptionsInput.SetValue(fields.Options)
+	m.BuilderWarehouseInput.SetValue(fields.Warehouse)
+	m.BuilderRoleInput.SetValue(fields.Role)
+	return m
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ParseTags splits tags typed as "prod, eu #billing" into distinct tags, in the
+// order given and without a leading #. Tags differing only in case are one tag.
+func ParseTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag := strings.TrimLeft(field, "#")
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// FormatTags renders tags as "#prod #eu"
+func FormatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
+
+// ConnectionGroups returns the groups of the saved connections in name order,
+// with "" last when some connections have no group
+func ConnectionGroups(connections []models.SavedConnection) []string {
+	seen := map[string]bool{}
+	var groups []string
+	for _, conn := range connections {
+		if !seen[conn.Group] {
+			seen[conn.Group] = true
+			groups = append(groups, conn.Group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i] == "" || groups[j] == "" {
+			return groups[j] == ""
+		}
+		return strings.ToLower(groups[i]) < strings.ToLower(groups[j])
+	})
+	return groups
+}
+
+// NextConnectionGroup steps the group filter through "" (every group) and each
+// group in turn; step is 1 or -1
+func NextConnectionGroup(groups []string, current string, step int) string {
+	options := []string{""}
+	for _, group := range groups {
+		if group != "" {
+			options = append(options, group)
+		}
+	}
+	index := 0
+	for i, option := range options {
+		if option == current {
+			index = i
+		}
+	}
+	return options[(index+step+len(options))%len(options)]
+}
+
+// MatchesConnectionSearch reports whether a saved connection's name, driver,
+// group, or one of its tags contains the search text, ignoring case. A search
+// starting with # matches tags only.
+func MatchesConnectionSearch(conn models.SavedConnection, search string) bool {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return true
+	}
+	if tag, ok := strings.CutPrefix(search, "#"); ok {
+		for _, t := range conn.Tags {
+			if strings.Contains(strings.ToLower(t), tag) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, field := range append([]string{conn.Name, conn.Driver, conn.Group}, conn.Tags...) {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// SavedConnectionsItems lists the saved connections that match the group filter
+// and search. Once any connection has a group, each group gets a header and
+// collapsed groups hide their connections; a search shows matches in collapsed
+// groups too.
+func SavedConnectionsItems(connections []models.SavedConnection, collapsed map[string]bool, groupFilter, search string) []list.Item {
+	var matching []models.SavedConnection
+	for _, conn := range connections {
+		if (groupFilter == "" || conn.Group == groupFilter) && MatchesConnectionSearch(conn, search) {
+			matching = append(matching, conn)
+		}
+	}
+
+	groups := ConnectionGroups(matching)
+	if len(groups) == 0 || (len(groups) == 1 && groups[0] == "") {
+		return UpdateSavedConnectionsItems(matching)
+	}
+
+	var items []list.Item
+	for _, group := range groups {
+		var members []models.SavedConnection
+		for _, conn := range matching {
+			if conn.Group == group {
+				members = append(members, conn)
+			}
+		}
+		folded := collapsed[group] && strings.TrimSpace(search) == ""
+		items = append(items, models.GroupItem{Name: group, Count: len(members), Collapsed: folded})
+		if !folded {
+			items = append(items, UpdateSavedConnectionsItems(members)...)
+		}
+	}
+	return items
+}
+
+// SetConnectionGroup files a saved connection under a group with tags and saves
+// the connections
+func SetConnectionGroup(m models.Model, name, group string, tags []string) (models.Model, error) {
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	for i := range updatedModel.SavedConnections {
+		if updatedModel.SavedConnections[i].Name == name {
+			updatedModel.SavedConnections[i].Group = strings.TrimSpace(group)
+			updatedModel.SavedConnections[i].Tags = tags
+			if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+				return m, fmt.Errorf("failed to save connections: %w", err)
+			}
+			return UpdateSavedConnectionsList(updatedModel), nil
+		}
+	}
+	return m, fmt.Errorf("connection '%s' not found", name)
+}
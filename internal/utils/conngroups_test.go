@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"prod, eu #billing", []string{"prod", "eu", "billing"}},
+		{"  a,,b  a  A #b ##", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConnectionGroups(t *testing.T) {
+	connections := []models.SavedConnection{{Name: "a", Group: "staging"}, {Name: "b"}, {Name: "c", Group: "Local"}, {Name: "d", Group: "staging"}, {Name: "e", Group: "prod"}}
+	want := []string{"Local", "prod", "staging", ""}
+	if got := ConnectionGroups(connections); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConnectionGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestNextConnectionGroup(t *testing.T) {
+	groups := []string{"local", "prod", ""}
+	tests := []struct {
+		current string
+		step    int
+		want    string
+	}{
+		{"", 1, "local"},
+		{"local", 1, "prod"},
+		{"prod", 1, ""},
+		{"", -1, "prod"},
+		{"removed", 1, "local"},
+	}
+	for _, tt := range tests {
+		if got := NextConnectionGroup(groups, tt.current, tt.step); got != tt.want {
+			t.Errorf("NextConnectionGroup(%q, %d) = %q, want %q", tt.current, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesConnectionSearch(t *testing.T) {
+	conn := models.SavedConnection{Name: "Orders DB", Driver: "postgres", Group: "prod", Tags: []string{"eu", "billing"}}
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{"", true},
+		{"orders", true},
+		{"PROD", true},
+		{"postgres", true},
+		{"bill", true},
+		{"#eu", true},
+		{"#prod", false},
+		{"mysql", false},
+	}
+	for _, tt := range tests {
+		if got := MatchesConnectionSearch(conn, tt.search); got != tt.want {
+			t.Errorf("MatchesConnectionSearch(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestSavedConnectionsItems(t *testing.T) {
+	titles := func(items []list.Item) []string {
+		var got []string
+		for _, item := range items {
+			switch i := item.(type) {
+			case models.GroupItem:
+				got = append(got, i.Title())
+			case models.Item:
+				got = append(got, i.ItemTitle)
+			}
+		}
+		return got
+	}
+
+	flat := []models.SavedConnection{{Name: "a"}, {Name: "b"}}
+	if got, want := titles(SavedConnectionsItems(flat, nil, "", "")), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ungrouped items = %v, want %v", got, want)
+	}
+
+	connections := []models.SavedConnection{
+		{Name: "orders", Group: "prod", Tags: []string{"eu"}},
+		{Name: "scratch"},
+		{Name: "users", Group: "prod"},
+		{Name: "dev", Group: "local", Tags: []string{"eu"}},
+	}
+	tests := []struct {
+		name      string
+		collapsed map[string]bool
+		filter    string
+		search    string
+		want      []string
+	}{
+		{"grouped", nil, "", "", []string{"▾ 📁 local (1)", "dev", "▾ 📁 prod (2)", "orders", "users", "▾ 📁 Ungrouped (1)", "scratch"}},
+		{"collapsed", map[string]bool{"prod": true}, "", "", []string{"▾ 📁 local (1)", "dev", "▸ 📁 prod (2)", "▾ 📁 Ungrouped (1)", "scratch"}},
+		{"one group", nil, "prod", "", []string{"▾ 📁 prod (2)", "orders", "users"}},
+		{"search opens folded groups", map[string]bool{"prod": true}, "", "#eu", []string{"▾ 📁 local (1)", "dev", "▾ 📁 prod (1)", "orders"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(SavedConnectionsItems(connections, tt.collapsed, tt.filter, tt.search)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SavedConnectionsItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
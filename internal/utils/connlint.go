@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// deprecatedConnectionParams explains parameters that connection strings
+// written for other clients carry but the drivers mishandle
+var deprecatedConnectionParams = map[string]map[string]string{
+	"postgres": {
+		"requiressl":     "requiressl is deprecated: use sslmode=require",
+		"ssl":            "ssl is a JDBC parameter that lib/pq ignores: use sslmode",
+		"sslcompression": "sslcompression is deprecated and ignored by the server",
+	},
+	"mysql": {
+		"allowOldPasswords": "allowOldPasswords enables the insecure pre-4.1 password hashing",
+	},
+}
+
+// mysqlAddress finds the address of a MySQL-style DSN as typed
+var mysqlAddress = regexp.MustCompile(`@tcp\(([^)]*)\)`)
+
+// NormalizeConnectionString rewrites a connection string in the canonical form
+// the connection form builds: an explicit port, sorted parameters, and for
+// PostgreSQL-style drivers a postgres:// URL. It also returns warnings about
+// settings that are likely mistakes. Strings it can't read, key=value strings,
+// Unix sockets, SQLite paths, and secrets still encrypted or in the keychain are
+// returned unchanged.
+func NormalizeConnectionString(driver, connectionStr string) (string, []string) {
+	connectionStr = strings.TrimSpace(connectionStr)
+	if connectionStr == "" || driver == "sqlite3" || config.IsEncrypted(connectionStr) || config.IsKeychainReference(connectionStr) {
+		return connectionStr, nil
+	}
+
+	var warnings []string
+	mysqlStyle := driver == "mysql" || driver == "mariadb" || driver == "clickhouse"
+	if mysqlStyle {
+		// The MySQL driver panics on strict instead of returning an error
+		if base, query, ok := strings.Cut(connectionStr, "?"); ok && strings.Contains(base, "/") {
+			if _, params := sortQueryParams(query); params.Has("strict") {
+				connectionStr = joinQuery(base, removeQueryParams(query, []string{"strict"}))
+				warnings = append(warnings, "strict was removed from the MySQL driver, which refuses it: dropped it; use sql_mode instead")
+			}
+		}
+	}
+	fields, err := ParseConnectionString(driver, connectionStr)
+	if err != nil {
+		return connectionStr, warnings
+	}
+
+	if mysqlStyle {
+		// The driver fills in 3306 itself, so a missing port only shows as typed
+		if match := mysqlAddress.FindStringSubmatch(connectionStr); match != nil {
+			if _, _, err := net.SplitHostPort(match[1]); err != nil {
+				fields.Port = DefaultPort(driver)
+				if driver == "clickhouse" {
+					warnings = append(warnings, fmt.Sprintf("no port: added %s, the ClickHouse MySQL interface (the driver alone would use 3306)", fields.Port))
+				}
+			}
+		}
+	} else if fields.Port == "" && driver != "snowflake" {
+		fields.Port = DefaultPort(driver)
+		if driver != "postgres" {
+			warnings = append(warnings, fmt.Sprintf("no port: added %s, the %s default (the driver alone would use 5432)", fields.Port, driver))
+		}
+	}
+
+	options, params := sortQueryParams(fields.Options)
+	fields.Options = options
+	warnings = append(warnings, lintConnectionParams(driver, fields.Host, params)...)
+
+	normalized, err := BuildConnectionString(driver, fields)
+	if err != nil {
+		return connectionStr, warnings
+	}
+	return normalized, warnings
+}
+
+// CheckConnectionString rejects a malformed connection string before it is
+// tried. SQLite paths are left to the driver, which reports a missing file, and
+// encrypted or keychain values are checked once they are resolved.
+func CheckConnectionString(driver, connectionStr string) error {
+	if driver == "sqlite3" || config.IsEncrypted(connectionStr) || config.IsKeychainReference(connectionStr) {
+		return nil
+	}
+	return database.ValidateConnectionString(driver, connectionStr)
+}
+
+// sortQueryParams orders the parameters of a raw query string by name, keeping
+// their values as typed, and returns them decoded as well
+func sortQueryParams(query string) (string, url.Values) {
+	var parts []string
+	for _, part := range strings.Split(query, "&") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	slices.SortStableFunc(parts, func(a, b string) int {
+		keyA, _, _ := strings.Cut(a, "=")
+		keyB, _, _ := strings.Cut(b, "=")
+		return strings.Compare(keyA, keyB)
+	})
+	params, _ := url.ParseQuery(strings.Join(parts, "&"))
+	return strings.Join(parts, "&"), params
+}
+
+// lintConnectionParams warns about TLS settings and deprecated parameters.
+// Snowflake, and instances reached through the Cloud SQL connector, always
+// connect over TLS, so their TLS settings are not checked.
+func lintConnectionParams(driver, host string, params url.Values) []string {
+	if driver == "snowflake" {
+		return nil
+	}
+	var warnings []string
+	family := "postgres"
+	if driver == "mysql" || driver == "mariadb" || driver == "clickhouse" {
+		family = "mysql"
+	}
+
+	switch {
+	case params.Has(database.CloudSQLInstanceParam):
+		// The connector encrypts the connection whatever the string says
+	case family == "postgres":
+		switch mode := params.Get("sslmode"); mode {
+		case "":
+			if isLocalHost(host) {
+				warnings = append(warnings, "no sslmode: TLS is required by default, which local servers often lack; add sslmode=disable if connecting fails")
+			} else {
+				warnings = append(warnings, "no sslmode: TLS is required but the server certificate is not verified; add sslmode=verify-full to verify it")
+			}
+		case "disable", "require", "verify-ca", "verify-full":
+		default:
+			warnings = append(warnings, fmt.Sprintf("sslmode=%s is not supported by the driver: use disable, require, verify-ca, or verify-full", mode))
+		}
+	case params.Get("tls") == "skip-verify":
+		warnings = append(warnings, "tls=skip-verify does not verify the server certificate")
+	}
+
+	var names []string
+	for name := range params {
+		if _, deprecated := deprecatedConnectionParams[family][name]; deprecated {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		warnings = append(warnings, deprecatedConnectionParams[family][name])
+	}
+	return warnings
+}
+
+// isLocalHost reports whether a host is this machine
+func isLocalHost(host string) bool {
+	return host == "" || host == "localhost" || net.ParseIP(host).IsLoopback()
+}
+
+// NormalizeSavedConnection normalizes the connection strings of a connection
+// being saved, returning the warnings about both; the replica's are prefixed
+func NormalizeSavedConnection(conn models.SavedConnection) (models.SavedConnection, []string) {
+	var warnings []string
+	conn.ConnectionStr, warnings = NormalizeConnectionString(conn.Driver, conn.ConnectionStr)
+	replica, replicaWarnings := NormalizeConnectionString(conn.Driver, conn.ReplicaConnectionStr)
+	conn.ReplicaConnectionStr = replica
+	for _, warning := range replicaWarnings {
+		warnings = append(warnings, "replica: "+warning)
+	}
+	return conn, warnings
+}
+
+// ConnectionWarningsMessage joins warnings for the status line, or is empty
+// without any
+func ConnectionWarningsMessage(name string, warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ Saved '%s', but %s", name, strings.Join(warnings, "; "))
+}
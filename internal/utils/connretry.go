@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// maxConnectRetryDelay caps the backoff between automatic connect retries
+const maxConnectRetryDelay = 30 * time.Second
+
+// WakePollInterval is how often a paused serverless database is polled while it resumes
+const WakePollInterval = 3 * time.Second
+
+// maxWakeWait is how long a paused database may take to resume before the connect fails
+const maxWakeWait = 5 * time.Minute
+
+// ConnectRetryDelay is the wait before the nth automatic connect retry:
+// 2s, 4s, 8s, ... up to maxConnectRetryDelay
+func ConnectRetryDelay(attempt int) time.Duration {
+	delay := 2 * time.Second
+	for i := 1; i < attempt && delay < maxConnectRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxConnectRetryDelay)
+}
+
+// ConnectRetryCountdown returns the whole seconds left before a retry, rounded up
+func ConnectRetryCountdown(retryAt, now time.Time) int {
+	left := retryAt.Sub(now)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + time.Second - 1) / time.Second)
+}
+
+// IsTransientConnectError reports whether a failed connect may succeed when retried.
+// Wrong credentials, unknown databases, and SSL mismatches are not retried.
+func IsTransientConnectError(err error) bool {
+	var explained *ConnectionError
+	if errors.As(err, &explained) {
+		return explained.Transient
+	}
+	return IsConnectionError(err)
+}
+
+// IsWakingConnectError reports whether a connect failed because a paused
+// serverless database is still resuming
+func IsWakingConnectError(err error) bool {
+	var explained *ConnectionError
+	return errors.As(err, &explained) && explained.Waking
+}
+
+// ScheduleConnectRetry shows a failed connect with a countdown to the next
+// attempt, when it failed for a transient reason and retries remain.
+// A resuming serverless database is polled until maxWakeWait instead.
+func ScheduleConnectRetry(m models.Model, err error) (models.Model, tea.Cmd, bool) {
+	if IsWakingConnectError(err) {
+		return scheduleWakePoll(m, err)
+	}
+	if !IsTransientConnectError(err) || m.ConnectAttempt >= config.ConnectRetries() {
+		return m, nil, false
+	}
+
+	updatedModel := m
+	updatedModel.ConnectAttempt++
+	updatedModel.ConnectRetryAt = time.Now().Add(ConnectRetryDelay(updatedModel.ConnectAttempt))
+	updatedModel.Err = err // Kept on screen until the retry starts
+	updatedModel.ErrorTimeout = nil
+	return updatedModel, ConnectRetryTick(updatedModel.ConnectRetrySession), true
+}
+
+// scheduleWakePoll polls a resuming database at a steady interval; the
+// backoff retries are left for failures after it wakes up
+func scheduleWakePoll(m models.Model, err error) (models.Model, tea.Cmd, bool) {
+	now := time.Now()
+	updatedModel := m
+	if !updatedModel.IsWakingDatabase {
+		updatedModel.IsWakingDatabase = true
+		updatedModel.WakeStartedAt = now
+	}
+	if now.Sub(updatedModel.WakeStartedAt) >= maxWakeWait {
+		return m, nil, false
+	}
+
+	updatedModel.ConnectRetryAt = now.Add(WakePollInterval)
+	updatedModel.Err = err
+	updatedModel.ErrorTimeout = nil
+	return updatedModel, ConnectRetryTick(updatedModel.ConnectRetrySession), true
+}
+
+// WakeTimeoutError explains a database that did not resume within maxWakeWait
+func WakeTimeoutError(err error) error {
+	return fmt.Errorf("database did not wake up within %d minutes: %w", int(maxWakeWait.Minutes()), err)
+}
+
+// ConnectRetryTick ticks once a second while a connect retry is pending
+func ConnectRetryTick(session int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return models.ConnectRetryTickMsg{Session: session}
+	})
+}
+
+// HandleConnectRetryTick starts the pending connect once its countdown ends
+func HandleConnectRetryTick(m models.Model, msg models.ConnectRetryTickMsg) (models.Model, tea.Cmd) {
+	// Ticks from a cancelled countdown stop here
+	if msg.Session != m.ConnectRetrySession || m.ConnectRetryAt.IsZero() {
+		return m, nil
+	}
+	if time.Now().Before(m.ConnectRetryAt) {
+		return m, ConnectRetryTick(m.ConnectRetrySession)
+	}
+
+	updatedModel := m
+	updatedModel.ConnectRetryAt = time.Time{}
+	updatedModel.IsConnecting = true
+	updatedModel.Err = nil
+	return updatedModel, ConnectToDB(m.SelectedDB, ConnectionDSN(m), m.DefaultSchema, m.Timeouts)
+}
+
+// CancelConnectRetry drops a pending connect retry and resets the attempt count
+func CancelConnectRetry(m models.Model) models.Model {
+	m.ConnectRetrySession++
+	m.ConnectRetryAt = time.Time{}
+	return endConnectRetries(m)
+}
+
+// endConnectRetries forgets the retries and wake-up polling of the last connect
+func endConnectRetries(m models.Model) models.Model {
+	m.ConnectAttempt = 0
+	m.IsWakingDatabase = false
+	m.WakeStartedAt = time.Time{}
+	return m
+}
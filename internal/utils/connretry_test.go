@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/lib/pq"
+)
+
+func TestConnectRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{5, 30 * time.Second},
+		{40, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := ConnectRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("ConnectRetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestConnectRetryCountdown(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		retryAt time.Time
+		want    int
+	}{
+		{"rounds up", now.Add(2500 * time.Millisecond), 3},
+		{"whole seconds", now.Add(4 * time.Second), 4},
+		{"due", now, 0},
+		{"overdue", now.Add(-time.Second), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConnectRetryCountdown(tt.retryAt, now); got != tt.want {
+				t.Errorf("ConnectRetryCountdown() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransientConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unreachable host", ExplainConnectionError("postgres", "", errors.New("dial tcp 10.0.0.1:5432: connect: connection refused")), true},
+		{"starting up", ExplainConnectionError("postgres", "", &pq.Error{Code: "57P03", Message: "the database system is starting up"}), true},
+		{"wrong password", ExplainConnectionError("postgres", "", &pq.Error{Code: "28P01"}), false},
+		{"dropped connection", errors.New("broken pipe"), true},
+		{"other error", errors.New("syntax error"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientConnectError(tt.err); got != tt.want {
+				t.Errorf("IsTransientConnectError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleConnectRetry(t *testing.T) {
+	t.Setenv("MIRADOR_CONNECT_RETRIES", "2")
+	err := ExplainConnectionError("postgres", "", errors.New("i/o timeout"))
+
+	m := models.Model{}
+	for attempt := 1; attempt <= 2; attempt++ {
+		var ok bool
+		m, _, ok = ScheduleConnectRetry(m, err)
+		if !ok || m.ConnectAttempt != attempt || m.ConnectRetryAt.IsZero() {
+			t.Fatalf("retry %d: scheduled = %v, attempt = %d", attempt, ok, m.ConnectAttempt)
+		}
+	}
+	if _, _, ok := ScheduleConnectRetry(m, err); ok {
+		t.Error("ScheduleConnectRetry() scheduled a retry past the configured limit")
+	}
+
+	m = CancelConnectRetry(m)
+	if m.ConnectAttempt != 0 || !m.ConnectRetryAt.IsZero() {
+		t.Errorf("CancelConnectRetry() left attempt %d, retry at %v", m.ConnectAttempt, m.ConnectRetryAt)
+	}
+}
+
+func TestScheduleWakePoll(t *testing.T) {
+	t.Setenv("MIRADOR_CONNECT_RETRIES", "0")
+	err := ExplainConnectionError("postgres", "", &pq.Error{Code: "57P03", Message: "the database system is starting up"})
+	if !IsWakingConnectError(err) {
+		t.Fatalf("IsWakingConnectError(%v) = false, want true", err)
+	}
+	if IsWakingConnectError(ExplainConnectionError("mysql", "", errors.New("i/o timeout"))) {
+		t.Error("IsWakingConnectError() treated a timeout as a resuming database")
+	}
+
+	// Polling a resuming database does not use up the backoff retries
+	m, _, ok := ScheduleConnectRetry(models.Model{}, err)
+	if !ok || !m.IsWakingDatabase || m.ConnectAttempt != 0 || m.ConnectRetryAt.IsZero() {
+		t.Fatalf("ScheduleConnectRetry() scheduled = %v, waking = %v, attempt = %d", ok, m.IsWakingDatabase, m.ConnectAttempt)
+	}
+
+	m.WakeStartedAt = time.Now().Add(-maxWakeWait)
+	if _, _, ok := ScheduleConnectRetry(m, err); ok {
+		t.Error("ScheduleConnectRetry() kept polling past maxWakeWait")
+	}
+
+	if m = CancelConnectRetry(m); m.IsWakingDatabase || !m.WakeStartedAt.IsZero() {
+		t.Error("CancelConnectRetry() left the database waking")
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// RecordConnectionUse stamps a saved connection as used now and saves the
+// connections. Usage is a convenience, so a failed save is not reported.
+func RecordConnectionUse(m models.Model, name string, now time.Time) models.Model {
+	if name == "" {
+		return m
+	}
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	for i := range updatedModel.SavedConnections {
+		if updatedModel.SavedConnections[i].Name == name {
+			updatedModel.SavedConnections[i].LastUsedAt = now
+			updatedModel.SavedConnections[i].UseCount++
+			config.SaveConnections(updatedModel.SavedConnections)
+			return UpdateSavedConnectionsList(updatedModel)
+		}
+	}
+	return m
+}
+
+// LastUsedLabel describes when a connection was last used, e.g. "2d ago"; it is
+// empty for a connection that was never used
+func LastUsedLabel(lastUsed, now time.Time) string {
+	if lastUsed.IsZero() {
+		return ""
+	}
+	age := now.Sub(lastUsed)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(age/(30*24*time.Hour)))
+	}
+	return fmt.Sprintf("%dy ago", int(age/(365*24*time.Hour)))
+}
+
+// NextConnectionSort returns the order after sortOrder in the cycle saved →
+// recent → name → saved
+func NextConnectionSort(sortOrder string) string {
+	i := slices.Index(models.ConnectionSorts, sortOrder)
+	return models.ConnectionSorts[(i+1)%len(models.ConnectionSorts)]
+}
+
+// ConnectionSortLabel names an order for the saved connections title; the
+// saved order has none
+func ConnectionSortLabel(sortOrder string) string {
+	switch sortOrder {
+	case models.ConnectionSortRecent:
+		return "🕘 recent first"
+	case models.ConnectionSortName:
+		return "🔤 by name"
+	}
+	return ""
+}
+
+// SortConnections returns the connections in the given order. By recency, the
+// latest used come first, ties go to the most used, and never used
+// connections keep their saved order at the end.
+func SortConnections(connections []models.SavedConnection, sortOrder string) []models.SavedConnection {
+	sorted := append([]models.SavedConnection(nil), connections...)
+	switch sortOrder {
+	case models.ConnectionSortRecent:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if !sorted[i].LastUsedAt.Equal(sorted[j].LastUsedAt) {
+				return sorted[i].LastUsedAt.After(sorted[j].LastUsedAt)
+			}
+			return sorted[i].UseCount > sorted[j].UseCount
+		})
+	case models.ConnectionSortName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
+	}
+	return sorted
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestLastUsedLabel(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+		{45 * 24 * time.Hour, "1mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+	}
+	for _, tt := range tests {
+		if got := LastUsedLabel(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("LastUsedLabel(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+	if got := LastUsedLabel(time.Time{}, now); got != "" {
+		t.Errorf("LastUsedLabel(zero) = %q, want empty", got)
+	}
+}
+
+func TestNextConnectionSort(t *testing.T) {
+	tests := []struct {
+		sortOrder string
+		want      string
+	}{
+		{models.ConnectionSortSaved, models.ConnectionSortRecent},
+		{models.ConnectionSortRecent, models.ConnectionSortName},
+		{models.ConnectionSortName, models.ConnectionSortSaved},
+		{"size", models.ConnectionSortSaved},
+	}
+	for _, tt := range tests {
+		if got := NextConnectionSort(tt.sortOrder); got != tt.want {
+			t.Errorf("NextConnectionSort(%q) = %q, want %q", tt.sortOrder, got, tt.want)
+		}
+	}
+}
+
+func TestSortConnections(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	connections := []models.SavedConnection{
+		{Name: "staging"},
+		{Name: "Analytics", LastUsedAt: now.Add(-time.Hour), UseCount: 2},
+		{Name: "local"},
+		{Name: "prod", LastUsedAt: now, UseCount: 1},
+		{Name: "billing", LastUsedAt: now.Add(-time.Hour), UseCount: 9},
+	}
+	tests := []struct {
+		sortOrder string
+		want      []string
+	}{
+		{models.ConnectionSortSaved, []string{"staging", "Analytics", "local", "prod", "billing"}},
+		{models.ConnectionSortRecent, []string{"prod", "billing", "Analytics", "staging", "local"}},
+		{models.ConnectionSortName, []string{"Analytics", "billing", "local", "prod", "staging"}},
+	}
+	for _, tt := range tests {
+		sorted := SortConnections(connections, tt.sortOrder)
+		var got []string
+		for _, conn := range sorted {
+			got = append(got, conn.Name)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("SortConnections(%q) = %v, want %v", tt.sortOrder, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortConnections(%q) = %v, want %v", tt.sortOrder, got, tt.want)
+				break
+			}
+		}
+	}
+	if connections[0].Name != "staging" {
+		t.Error("SortConnections reordered its input")
+	}
+}
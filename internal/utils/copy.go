@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// CopyBatchSize is how many rows each insert transaction of a table copy holds
+const CopyBatchSize = 500
+
+// maxCopyErrors caps how many batch errors a copy report keeps
+const maxCopyErrors = 20
+
+// ParseTargetTable splits a "schema.table" target name. Without a schema the
+// target driver's default schema is used; SQLite names are never split.
+func ParseTargetTable(input, driver string) (schema, table string) {
+	input = strings.TrimSpace(input)
+	if driver != "sqlite3" {
+		if i := strings.Index(input, "."); i > 0 && i < len(input)-1 {
+			return input[:i], input[i+1:]
+		}
+	}
+	return GetDefaultSchema(driver), input
+}
+
+// StartTableCopy copies every row of the source table into a table on the target
+// saved connection in the background. Progress arrives on the returned channel,
+// which closes after the final message with Done set.
+func StartTableCopy(src *sql.DB, srcDB models.DBType, srcSchema, srcTable string, target models.SavedConnection, dstSchema, dstTable string) (<-chan models.CopyProgressMsg, tea.Cmd) {
+	ch := make(chan models.CopyProgressMsg, 16)
+	go runTableCopy(ch, src, srcDB, srcSchema, srcTable, target, dstSchema, dstTable)
+	return ch, WaitForCopyProgress(ch)
+}
+
+func runTableCopy(ch chan<- models.CopyProgressMsg, src *sql.DB, srcDB models.DBType, srcSchema, srcTable string, target models.SavedConnection, dstSchema, dstTable string) {
+	defer close(ch)
+	progress := models.CopyProgressMsg{Total: -1}
+
+	dst, err := openSavedConnection(target)
+	if err != nil {
+		progress.Done = true
+		progress.Err = fmt.Errorf("failed to connect to %s: %w", target.Name, err)
+		ch <- progress
+		return
+	}
+	defer dst.Close()
+
+	if n, err := database.GetTableRowCount(src, srcDB.Driver, srcTable, srcSchema); err == nil {
+		progress.Total = int64(n)
+	}
+	ch <- progress
+
+	var offset int64
+	err = database.CopyTableRows(src, srcDB.Driver, srcSchema, srcTable, dst, target.Driver, dstSchema, dstTable, CopyBatchSize, func(n int, batchErr error) {
+		if batchErr != nil {
+			progress.Failed += int64(n)
+			if len(progress.Errors) < maxCopyErrors {
+				progress.Errors = append(progress.Errors, fmt.Sprintf("rows %d-%d: %v", offset+1, offset+int64(n), batchErr))
+			}
+		} else {
+			progress.Copied += int64(n)
+		}
+		offset += int64(n)
+
+		snapshot := progress
+		snapshot.Errors = append([]string(nil), progress.Errors...)
+		ch <- snapshot
+	})
+
+	auditErr := err
+	if auditErr == nil && progress.Failed > 0 {
+		auditErr = fmt.Errorf("%d rows failed to copy", progress.Failed)
+	}
+	statement := fmt.Sprintf("copy %s into %s",
+		database.QualifiedTableName(srcDB.Driver, srcSchema, srcTable),
+		database.QualifiedTableName(target.Driver, dstSchema, dstTable))
+	RecordAudit(models.DBType{Name: target.Name, Driver: target.Driver}, target.ConnectionStr, "copy data", statement, nil, progress.Copied, auditErr)
+
+	progress.Done = true
+	progress.Err = err
+	ch <- progress
+}
+
+// openSavedConnection opens and pings a saved connection other than the active one
+func openSavedConnection(conn models.SavedConnection) (*sql.DB, error) {
+	return openConnection(conn.Driver, OpenDSN(conn.Driver, conn.ConnectionStr, conn.ReadOnly, conn.Immutable), ConnectionTimeouts(conn))
+}
+
+// WaitForCopyProgress waits for the next progress message of a running copy
+func WaitForCopyProgress(ch <-chan models.CopyProgressMsg) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	})
+}
+
+// HandleCopyProgress records copy progress and keeps listening until the copy ends
+func HandleCopyProgress(m models.Model, msg models.CopyProgressMsg) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.CopyProgress = msg
+
+	if !msg.Done {
+		return updatedModel, WaitForCopyProgress(updatedModel.CopyProgressChan)
+	}
+
+	updatedModel.IsCopyingData = false
+	updatedModel.CopyProgressChan = nil
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+	return updatedModel, nil
+}
+
+// CopyProgressBar renders a text progress bar of the given width. Without a
+// known total the bar is left empty.
+func CopyProgressBar(done, total int64, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = int(done * int64(width) / total)
+		if filled > width {
+			filled = width
+		}
+	} else if total == 0 {
+		filled = width
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// FormatCopyProgress summarizes copied and failed rows against the source total
+func FormatCopyProgress(p models.CopyProgressMsg) string {
+	processed := p.Copied + p.Failed
+	summary := fmt.Sprintf("%d copied", p.Copied)
+	if p.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", p.Failed)
+	}
+	if p.Total < 0 {
+		return fmt.Sprintf("%d rows processed (%s)", processed, summary)
+	}
+	return fmt.Sprintf("%d / %d rows (%s)", processed, p.Total, summary)
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseTargetTable(t *testing.T) {
+	tests := []struct {
+		input      string
+		driver     string
+		wantSchema string
+		wantTable  string
+	}{
+		{"users", "postgres", "public", "users"},
+		{"archive.users", "postgres", "archive", "users"},
+		{" shop.orders ", "mysql", "shop", "orders"},
+		{"orders", "mysql", "", "orders"},
+		{"my.table", "sqlite3", "main", "my.table"},
+		{".users", "postgres", "public", ".users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver+"/"+tt.input, func(t *testing.T) {
+			schema, table := ParseTargetTable(tt.input, tt.driver)
+			if schema != tt.wantSchema || table != tt.wantTable {
+				t.Errorf("ParseTargetTable(%q, %q) = %q, %q, want %q, %q", tt.input, tt.driver, schema, table, tt.wantSchema, tt.wantTable)
+			}
+		})
+	}
+}
+
+func TestCopyProgressBar(t *testing.T) {
+	tests := []struct {
+		name  string
+		done  int64
+		total int64
+		want  string
+	}{
+		{"half", 5, 10, "[█████░░░░░]"},
+		{"complete", 10, 10, "[██████████]"},
+		{"over total", 12, 10, "[██████████]"},
+		{"unknown total", 5, -1, "[░░░░░░░░░░]"},
+		{"empty table", 0, 0, "[██████████]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CopyProgressBar(tt.done, tt.total, 10); got != tt.want {
+				t.Errorf("CopyProgressBar(%d, %d) = %q, want %q", tt.done, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCopyProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		p    models.CopyProgressMsg
+		want string
+	}{
+		{"known total", models.CopyProgressMsg{Copied: 500, Total: 1200}, "500 / 1200 rows (500 copied)"},
+		{"with failures", models.CopyProgressMsg{Copied: 500, Failed: 500, Total: 1200}, "1000 / 1200 rows (500 copied, 500 failed)"},
+		{"unknown total", models.CopyProgressMsg{Copied: 40, Total: -1}, "40 rows processed (40 copied)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCopyProgress(tt.p); got != tt.want {
+				t.Errorf("FormatCopyProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
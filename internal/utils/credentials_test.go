@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+const testMyCnf = `# credentials for the local server
+[client]
+user = app
+password = "s3cr#t"
+
+[mysqld]
+password = server-only
+
+[mysql]
+password='from-mysql' ; quoted
+!includedir /etc/mysql/conf.d/
+
+[client-mariadb]
+password = from_mariadb # trailing comment
+`
+
+func TestReadMySQLOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".my.cnf")
+	if err := os.WriteFile(path, []byte(testMyCnf), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		groups       []string
+		wantUser     string
+		wantPassword string
+	}{
+		{[]string{"client"}, "app", "s3cr#t"},
+		{[]string{"client", "mysql"}, "app", "from-mysql"},
+		{[]string{"mysql", "client"}, "app", "s3cr#t"},
+		{[]string{"client", "client-mariadb"}, "app", "from_mariadb"},
+		{[]string{"mysqldump"}, "", ""},
+	}
+	for _, tt := range tests {
+		options, err := database.ReadMySQLOptions(path, tt.groups)
+		if err != nil {
+			t.Fatalf("ReadMySQLOptions(%v) error = %v", tt.groups, err)
+		}
+		if options["user"] != tt.wantUser || options["password"] != tt.wantPassword {
+			t.Errorf("ReadMySQLOptions(%v) = user %q password %q, want %q %q", tt.groups, options["user"], options["password"], tt.wantUser, tt.wantPassword)
+		}
+	}
+
+	options, err := database.ReadMySQLOptions(filepath.Join(t.TempDir(), "missing.cnf"), []string{"client"})
+	if err != nil || len(options) != 0 {
+		t.Errorf("ReadMySQLOptions(missing file) = %v, %v, want no options", options, err)
+	}
+}
+
+func TestApplyMySQLOptionFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.WriteFile(filepath.Join(home, ".my.cnf"), []byte(testMyCnf), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		driver string
+		dsn    string
+		want   string
+	}{
+		{"mysql", "@tcp(db:3306)/shop", "app:from-mysql@tcp(db:3306)/shop"},
+		{"mysql", "root@tcp(db:3306)/shop", "root:from-mysql@tcp(db:3306)/shop"},
+		{"mariadb", "@tcp(db:3306)/shop", "app:from-mysql@tcp(db:3306)/shop"},
+		{"mysql", "root:given@tcp(db:3306)/shop", "root:given@tcp(db:3306)/shop"},
+		{"postgres", "postgres://app@db/shop", "postgres://app@db/shop"},
+	}
+	for _, tt := range tests {
+		got, err := database.ApplyMySQLOptionFile(tt.driver, tt.dsn)
+		if err != nil {
+			t.Fatalf("ApplyMySQLOptionFile(%q, %q) error = %v", tt.driver, tt.dsn, err)
+		}
+		if got != tt.want {
+			t.Errorf("ApplyMySQLOptionFile(%q, %q) = %q, want %q", tt.driver, tt.dsn, got, tt.want)
+		}
+	}
+}
+
+func TestHasPassword(t *testing.T) {
+	tests := []struct {
+		driver string
+		dsn    string
+		want   bool
+	}{
+		{"postgres", "postgres://app:pw@db/shop", true},
+		{"postgres", "postgres://app@db/shop", false},
+		{"postgres", "postgres://app:@db/shop", false},
+		{"redshift", "postgres://app@db/shop?password=pw", true},
+		{"postgres", "host=db user=app password=pw", true},
+		{"postgres", "host=db user=app", false},
+		{"mysql", "app:pw@tcp(db:3306)/shop", true},
+		{"mariadb", "app@tcp(db:3306)/shop", false},
+		{"snowflake", "analyst:pw@myorg-acct1/sales", true},
+		{"snowflake", "analyst@myorg-acct1/sales", false},
+		{"sqlite3", "shop.db", false},
+	}
+	for _, tt := range tests {
+		if got := database.HasPassword(tt.driver, tt.dsn); got != tt.want {
+			t.Errorf("HasPassword(%q, %q) = %v, want %v", tt.driver, tt.dsn, got, tt.want)
+		}
+	}
+}
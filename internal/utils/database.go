@@ -1,14 +1,12 @@
 package utils
 
 import (
-	"context"
 	"database/sql"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancaldera/mirador/internal/database"
@@ -18,8 +16,14 @@ import (
 // GetDefaultSchema returns the default schema name for a database driver
 func GetDefaultSchema(driver string) string {
 	switch driver {
-	case "mysql":
-		return "mysql"
+	case "mysql", "mariadb", "clickhouse":
+		return "" // The connection's current database
+	case "trino":
+		return "" // The catalog and schema of the connection string
+	case "snowflake":
+		return "" // The session's schema
+	case "bigquery":
+		return "" // The dataset of the connection string
 	case "sqlite3":
 		return "main"
 	default: // postgres
@@ -29,32 +33,14 @@ func GetDefaultSchema(driver string) string {
 
 // BuildUpdateSQL generates database-specific UPDATE SQL statement
 func BuildUpdateSQL(driver, schema, table, field, primaryKey string) string {
-	switch driver {
-	case "postgres":
-		return fmt.Sprintf(`UPDATE "%s"."%s" SET "%s" = $1 WHERE "%s" = $2`,
-			schema, table, field, primaryKey)
-	case "mysql":
-		return fmt.Sprintf("UPDATE `%s`.`%s` SET `%s` = ? WHERE `%s` = ?",
-			schema, table, field, primaryKey)
-	case "sqlite3":
-		return fmt.Sprintf(`UPDATE "%s" SET "%s" = ? WHERE "%s" = ?`,
-			table, field, primaryKey)
-	default:
-		return fmt.Sprintf(`UPDATE "%s"."%s" SET "%s" = $1 WHERE "%s" = $2`,
-			schema, table, field, primaryKey)
-	}
-}
-
-// DetermineSortParameters converts sort direction and column to database parameters
-func DetermineSortParameters(sortDirection models.SortDirection, sortColumn string) (string, string) {
-	switch sortDirection {
-	case models.SortAsc:
-		return sortColumn, "ASC"
-	case models.SortDesc:
-		return sortColumn, "DESC"
-	default:
-		return "", ""
+	set, where := "?", "?"
+	if models.DriverCapabilities(driver).NumberedPlaceholders {
+		set, where = "$1", "$2"
 	}
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s",
+		database.QualifiedTableName(driver, schema, table),
+		database.QuoteIdentifier(driver, field), set,
+		database.QuoteIdentifier(driver, primaryKey), where)
 }
 
 // FindPrimaryKeyColumn locates primary key column and value from row data
@@ -81,36 +67,73 @@ func FindPrimaryKeyColumn(columns []string, rowData []string) (string, string, e
 	return "", "", fmt.Errorf("no primary key column found in %d columns", len(columns))
 }
 
-// ConnectToDB establishes database connection and loads tables
-func ConnectToDB(selectedDB models.DBType, connectionStr string) tea.Cmd {
+// openConnection opens a connection pool whose statements are bounded by the
+// statement timeout and pings it within the connect timeout
+func openConnection(driver, connectionStr string, timeouts models.Timeouts) (*sql.DB, error) {
+	db, err := database.Open(driver, connectionStr)
+	if err != nil {
+		return nil, err
+	}
+	database.SetStatementTimeout(db, timeouts.Statement)
+
+	ctx, cancel := database.ConnectContext(timeouts.Connect)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		database.Close(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+// CloseDB disconnects the primary connection
+func CloseDB(m models.Model) models.Model {
+	if m.DB != nil {
+		database.Close(m.DB)
+	}
+	m.DB = nil
+	return m
+}
+
+// ConnectToDB establishes database connection and loads tables. The tables are
+// listed from schema, or from the driver's default schema when it is empty.
+func ConnectToDB(selectedDB models.DBType, connectionStr, schema string, timeouts models.Timeouts) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		db, err := sql.Open(selectedDB.Driver, connectionStr)
+		db, err := openConnection(selectedDB.Driver, connectionStr, timeouts)
 		if err != nil {
-			return models.ConnectResult{Err: err}
+			return models.ConnectResult{Err: ExplainConnectionError(selectedDB.Driver, connectionStr, err)}
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		err = db.PingContext(ctx)
-		if err != nil {
-			db.Close()
-			return models.ConnectResult{Err: err}
+		if schema == "" {
+			schema = GetDefaultSchema(selectedDB.Driver)
+			if selectedDB.Driver == "mysql" || selectedDB.Driver == "mariadb" || selectedDB.Driver == "clickhouse" {
+				// Qualify MySQL and ClickHouse metadata with the database named in the DSN
+				if current, err := database.GetCurrentDatabase(db); err == nil {
+					schema = current
+				}
+			}
+			if selectedDB.Driver == "bigquery" {
+				schema = database.BigQueryDataset(connectionStr)
+			}
 		}
 
-		tables, err := database.GetTables(db, selectedDB.Driver)
+		tables, err := database.GetTables(db, selectedDB.Driver, schema)
 		if err != nil {
-			db.Close()
-			return models.ConnectResult{Err: err}
+			database.Close(db)
+			// SQLite only reads the file on the first query, so file errors surface here
+			return models.ConnectResult{Err: ExplainConnectionError(selectedDB.Driver, connectionStr, err)}
 		}
-
-		schema := GetDefaultSchema(selectedDB.Driver)
+		// Foreign and non-durable tables are only marked in the list, so a failed lookup is not fatal
+		foreign, _ := database.GetForeignTables(db, selectedDB.Driver, schema)
+		persistence, _ := database.GetTablePersistence(db, selectedDB.Driver, schema)
 
 		return models.ConnectResult{
-			DB:     db,
-			Driver: selectedDB.Driver,
-			Tables: tables,
-			Schema: schema,
+			DB:          db,
+			Driver:      selectedDB.Driver,
+			Tables:      tables,
+			Foreign:     foreign,
+			Persistence: persistence,
+			Schema:      schema,
 		}
 	})
 }
@@ -127,7 +150,7 @@ func LoadColumns(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSc
 }
 
 // LoadDataPreview loads table data preview with pagination and sorting
-func LoadDataPreview(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage int, sortDirection models.SortDirection, sortColumn string) tea.Cmd {
+func LoadDataPreview(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage int, sortKeys []models.SortKey, jsonColumns []models.JSONColumn) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		// Reset pagination and load first page
 		totalRows, err := database.GetTableRowCount(db, selectedDB.Driver, selectedTable, selectedSchema)
@@ -135,63 +158,62 @@ func LoadDataPreview(db *sql.DB, selectedDB models.DBType, selectedTable, select
 			return models.DataPreviewResult{Columns: nil, Rows: nil, Err: err}
 		}
 
-		// Determine sort parameters
-		sortCol, sortDir := DetermineSortParameters(sortDirection, sortColumn)
-
-		cols, rows, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, 0, sortCol, sortDir)
-		return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+		rs, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, 0, sortKeys, jsonColumns)
+		return models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows, TableRows: totalRows, TableRowsKnown: true}
 	})
 }
 
 // LoadDataPreviewWithPagination loads data with pagination support
-func LoadDataPreviewWithPagination(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage, currentPage int, sortDirection models.SortDirection, sortColumn, filterValue string, allColumns []string, totalRows int) tea.Cmd {
+func LoadDataPreviewWithPagination(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage, currentPage int, sortKeys []models.SortKey, filterValue string, allColumns []string, totalRows int, jsonColumns []models.JSONColumn) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		// Determine sort parameters
-		sortCol, sortDir := DetermineSortParameters(sortDirection, sortColumn)
 
 		offset := currentPage * itemsPerPage
 		if filterValue != "" {
-			cols, rows, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, filterValue, allColumns, sortCol, sortDir)
-			return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+			rs, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, filterValue, allColumns, sortKeys, jsonColumns)
+			return models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows}
 		}
-		cols, rows, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, sortCol, sortDir)
-		return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+		rs, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, sortKeys, jsonColumns)
+		return models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows}
 	})
 }
 
 // LoadDataPreviewWithFilter loads data with filter applied
-func LoadDataPreviewWithFilter(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage int, filterValue string, allColumns []string, sortDirection models.SortDirection, sortColumn string) tea.Cmd {
+func LoadDataPreviewWithFilter(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage int, filterValue string, allColumns []string, sortKeys []models.SortKey, jsonColumns []models.JSONColumn) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		// Get total rows with filter
-		totalRows, err := database.GetTableRowCountWithFilter(db, selectedDB.Driver, selectedTable, selectedSchema, filterValue, allColumns)
+		totalRows, err := database.GetTableRowCountWithFilter(db, selectedDB.Driver, selectedTable, selectedSchema, filterValue, allColumns, jsonColumns)
 		if err != nil {
 			return models.DataPreviewResult{Columns: nil, Rows: nil, Err: err}
 		}
 
-		// Determine sort parameters
-		sortCol, sortDir := DetermineSortParameters(sortDirection, sortColumn)
-
 		// Get filtered and sorted data
-		cols, rows, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, 0, filterValue, allColumns, sortCol, sortDir)
-		return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+		rs, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, 0, filterValue, allColumns, sortKeys, jsonColumns)
+		result := models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows}
+
+		// When nothing matches, count the whole table to tell an empty table from a filter that excludes everything
+		if err == nil && totalRows == 0 {
+			if tableRows, countErr := database.GetTableRowCount(db, selectedDB.Driver, selectedTable, selectedSchema); countErr == nil {
+				result.TableRows = tableRows
+				result.TableRowsKnown = true
+			}
+		}
+		return result
 	})
 }
 
 // LoadDataPreviewWithSort loads data with sorting applied
-func LoadDataPreviewWithSort(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage, currentPage int, sortDirection models.SortDirection, sortColumn, filterValue string, allColumns []string, totalRows int) tea.Cmd {
+func LoadDataPreviewWithSort(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, itemsPerPage, currentPage int, sortKeys []models.SortKey, filterValue string, allColumns []string, totalRows int, jsonColumns []models.JSONColumn) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		// Determine sort parameters
-		sortCol, sortDir := DetermineSortParameters(sortDirection, sortColumn)
 
 		offset := currentPage * itemsPerPage
 
 		// Use appropriate function based on whether filter is active
 		if filterValue != "" {
-			cols, rows, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, filterValue, allColumns, sortCol, sortDir)
-			return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+			rs, err := database.GetTablePreviewPaginatedWithFilterAndSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, filterValue, allColumns, sortKeys, jsonColumns)
+			return models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows}
 		} else {
-			cols, rows, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, sortCol, sortDir)
-			return models.DataPreviewResult{Columns: cols, Rows: rows, Err: err, TotalRows: totalRows}
+			rs, err := database.GetTablePreviewPaginatedWithSort(db, selectedDB.Driver, selectedTable, selectedSchema, itemsPerPage, offset, sortKeys, jsonColumns)
+			return models.DataPreviewResult{Columns: rs.Columns, ColumnTypes: rs.Types, Rows: rs.Rows, Nulls: rs.Nulls, Err: err, TotalRows: totalRows}
 		}
 	})
 }
@@ -205,7 +227,7 @@ func LoadRelationships(db *sql.DB, selectedDB models.DBType, selectedSchema stri
 }
 
 // SaveFieldEdit creates and executes an UPDATE statement for the edited field
-func SaveFieldEdit(db *sql.DB, selectedDB models.DBType, selectedSchema, selectedTable, editingFieldName string, allColumns, selectedRowData []string, editingFieldIndex int, newValue string) tea.Cmd {
+func SaveFieldEdit(db *sql.DB, selectedDB models.DBType, connectionStr, migrationFile, selectedSchema, selectedTable, editingFieldName string, allColumns, selectedRowData []string, editingFieldIndex int, newValue string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		// Find the primary key column and value for the WHERE clause
 		primaryKeyColumn, primaryKeyValue, err := FindPrimaryKeyColumn(allColumns, selectedRowData)
@@ -217,15 +239,22 @@ func SaveFieldEdit(db *sql.DB, selectedDB models.DBType, selectedSchema, selecte
 			}
 		}
 
+		// Only columns the preview read from the table may be named in the UPDATE
+		if err := database.ValidateIdentifier("column", editingFieldName, allColumns); err != nil {
+			return models.FieldUpdateResult{Success: false, Err: err, ExitEdit: false}
+		}
+
 		// Build UPDATE SQL statement
 		updateSQL := BuildUpdateSQL(selectedDB.Driver, selectedSchema, selectedTable, editingFieldName, primaryKeyColumn)
 
 		// Execute the UPDATE statement
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := database.StatementContext(db)
 		defer cancel()
 
 		result, err := db.ExecContext(ctx, updateSQL, newValue, primaryKeyValue)
+		auditArgs := []string{newValue, primaryKeyValue}
 		if err != nil {
+			RecordAudit(selectedDB, connectionStr, "field edit", updateSQL, auditArgs, 0, err)
 			return models.FieldUpdateResult{
 				Success:  false,
 				Err:      fmt.Errorf("failed to update field: %v", err),
@@ -235,6 +264,7 @@ func SaveFieldEdit(db *sql.DB, selectedDB models.DBType, selectedSchema, selecte
 
 		rowsAffected, err := result.RowsAffected()
 		if err != nil {
+			RecordAudit(selectedDB, connectionStr, "field edit", updateSQL, auditArgs, -1, nil)
 			return models.FieldUpdateResult{
 				Success:  false,
 				Err:      fmt.Errorf("failed to get affected rows: %v", err),
@@ -242,6 +272,11 @@ func SaveFieldEdit(db *sql.DB, selectedDB models.DBType, selectedSchema, selecte
 			}
 		}
 
+		RecordAudit(selectedDB, connectionStr, "field edit", updateSQL, auditArgs, rowsAffected, nil)
+		if rowsAffected > 0 {
+			RecordMigration(migrationFile, selectedDB.Driver, "field edit", InlineSQLArgs(selectedDB.Driver, updateSQL, []any{newValue, primaryKeyValue}))
+		}
+
 		if rowsAffected == 0 {
 			return models.FieldUpdateResult{
 				Success:  false,
@@ -265,50 +300,63 @@ func HandleConnectResult(m models.Model, msg models.ConnectResult) (models.Model
 
 	if msg.Err != nil {
 		// Ensure we stay in SavedConnectionsView to display the error
-		updatedModel.State = models.SavedConnectionsView
-		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+		updatedModel = PushView(updatedModel, models.SavedConnectionsView, models.NavParams{})
+		if retrying, cmd, ok := ScheduleConnectRetry(updatedModel, msg.Err); ok {
+			return retrying, cmd
+		}
+		err := msg.Err
+		if updatedModel.IsWakingDatabase && IsWakingConnectError(err) {
+			err = WakeTimeoutError(err)
+		}
+		updatedModel = endConnectRetries(updatedModel)
+		return SetErrorWithTimeout(updatedModel, err, connectionErrorTimeout(err))
 	}
 
+	updatedModel = endConnectRetries(updatedModel)
+	updatedModel = RecordConnectionUse(updatedModel, updatedModel.ConnectionName, time.Now())
+
 	updatedModel.DB = msg.DB
+	if msg.Driver == "bigquery" {
+		// The driver refuses anything but a query, so the connection is shown as read-only
+		updatedModel.ReadOnly = true
+	}
+	updatedModel.ConnectionLost = false
+	updatedModel.ReconnectAttempt = 0
+	updatedModel.ReconnectAt = time.Time{}
+	updatedModel.Watchlist = nil    // Watched rows belong to the previous connection
+	updatedModel.SelectedTable = "" // The first preview of a new connection starts from its default sort
 	updatedModel.Tables = msg.Tables
 	updatedModel.SelectedSchema = msg.Schema
+	updatedModel.CompletionColumns = nil // Columns fetched for completion belong to the previous connection
 
 	// Sort tables alphabetically
 	sort.Strings(updatedModel.Tables)
 
 	// Create simple table infos
-	updatedModel.TableInfos = CreateTableInfos(updatedModel.Tables, updatedModel.SelectedSchema)
+	updatedModel.TableInfos = CreateTableInfos(updatedModel.Tables, updatedModel.SelectedSchema, msg.Foreign, msg.Persistence)
 
-	// Update tables list (show only table names)
-	items := CreateTableListItems(updatedModel.TableInfos)
+	// Update tables list (show only table names), favorites first
+	updatedModel.FavoriteTables = LoadFavoriteTables(updatedModel.SelectedDB.Driver, updatedModel.ConnectionStr, updatedModel.SelectedSchema)
+	updatedModel.TableNotes = LoadTableNotes(updatedModel.SelectedDB.Driver, updatedModel.ConnectionStr, updatedModel.SelectedSchema)
+	items := CreateTableListItems(updatedModel.TableInfos, updatedModel.FavoriteTables, updatedModel.TableNotes)
 	updatedModel.TablesList.SetItems(items)
 
-	updatedModel.State = models.TablesView
-	return updatedModel, nil
-}
+	updatedModel = ResetView(updatedModel, models.TablesView, models.NavParams{Schema: updatedModel.SelectedSchema})
 
-// CreateTableInfos creates TableInfo objects from table names
-func CreateTableInfos(tables []string, schema string) []models.TableInfo {
-	infos := make([]models.TableInfo, len(tables))
-	for i, table := range tables {
-		infos[i] = models.TableInfo{
-			Name:   table,
-			Schema: schema,
-		}
+	// Start a new snapshot schedule; ticks from earlier sessions are ignored
+	updatedModel.SnapshotSession++
+	cmds := []tea.Cmd{
+		TakeTableSnapshot(updatedModel.DB, updatedModel.SelectedDB, updatedModel.ConnectionStr, updatedModel.SelectedSchema),
+		ScheduleTableSnapshot(updatedModel.SnapshotSession),
 	}
-	return infos
-}
-
-// CreateTableListItems creates list items from table infos
-func CreateTableListItems(infos []models.TableInfo) []list.Item {
-	items := make([]list.Item, len(infos))
-	for i, info := range infos {
-		items[i] = models.Item{
-			ItemTitle: info.Name,
-			ItemDesc:  fmt.Sprintf("Table in %s schema", info.Schema),
-		}
+	// A saved connection's read replica is opened once the primary is up
+	if updatedModel.ReplicaConnectionStr != "" && updatedModel.ReplicaDB == nil {
+		cmds = append(cmds, ConnectReplica(updatedModel.SelectedDB, updatedModel.ReplicaConnectionStr, updatedModel.Timeouts))
 	}
-	return items
+	updatedModel, ping := StartPings(updatedModel)
+	updatedModel, worksheet := StartWorksheet(updatedModel)
+	cmds = append(cmds, ping, worksheet, ConnectHook(updatedModel))
+	return updatedModel, tea.Batch(cmds...)
 }
 
 // HandleTestConnectionResult processes test connection result and updates model
@@ -317,62 +365,48 @@ func HandleTestConnectionResult(m models.Model, msg models.TestConnectionResult)
 	updatedModel.IsTestingConnection = false
 
 	if msg.Err != nil {
-		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+		return SetErrorWithTimeout(updatedModel, msg.Err, connectionErrorTimeout(msg.Err))
 	}
 
 	updatedModel.QueryResult = "Connection successful!"
 	return updatedModel, ClearResultAfterTimeout()
 }
 
-// HandleColumnsResult processes columns result and updates model
-func HandleColumnsResult(m models.Model, msg models.ColumnsResult) (models.Model, tea.Cmd) {
-	updatedModel := m
-	updatedModel.IsLoadingColumns = false
-
-	if msg.Err != nil {
-		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
-	}
-
-	// Convert columns to table rows (msg.Columns is [][]string)
-	rows := make([]table.Row, len(msg.Columns))
-	for i, col := range msg.Columns {
-		if len(col) >= 4 {
-			rows[i] = table.Row{col[0], col[1], col[2], col[3]}
-		} else if len(col) >= 3 {
-			rows[i] = table.Row{col[0], col[1], col[2], ""}
-		} else if len(col) >= 2 {
-			rows[i] = table.Row{col[0], col[1], "", ""}
-		} else if len(col) >= 1 {
-			rows[i] = table.Row{col[0], "", "", ""}
-		} else {
-			rows[i] = table.Row{"", "", "", ""}
-		}
-	}
-
-	// Update columns table
-	updatedModel.ColumnsTable.SetRows(rows)
-	updatedModel.State = models.ColumnsView
-	return updatedModel, nil
-}
-
 // HandleDataPreviewResult processes data preview result and updates model
 func HandleDataPreviewResult(m models.Model, msg models.DataPreviewResult) (models.Model, tea.Cmd) {
 	updatedModel := m
 	updatedModel.IsLoadingPreview = false
 
 	if msg.Err != nil {
+		// A failed step to another page leaves the row detail on its current row
+		updatedModel.DataPreviewCurrentPage -= m.RowDetailPageStep
+		updatedModel.RowDetailPageStep = 0
 		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
 	}
 
 	updatedModel.DataPreviewAllColumns = msg.Columns
+	updatedModel.DataPreviewColumnTypes = msg.ColumnTypes
+	// A default sort keeps only the columns this table has
+	if len(msg.Columns) > 0 {
+		updatedModel = SetPreviewSortKeys(updatedModel, SortKeysInColumns(PreviewSortKeys(updatedModel), msg.Columns))
+	}
 	updatedModel.DataPreviewAllRows = msg.Rows
+	updatedModel.DataPreviewNulls = msg.Nulls
 	updatedModel.DataPreviewTotalRows = msg.TotalRows
+	if msg.TableRowsKnown {
+		updatedModel.DataPreviewTableRows = msg.TableRows
+	}
 
 	// Create the data preview table
 	updatedModel = CreateDataPreviewTable(updatedModel)
 
+	// Stepping through rows in the row detail view continues on the new page
+	if stepped, ok := continueRowDetailStep(updatedModel); ok {
+		return stepped, nil
+	}
+
 	// Switch to data preview view to show the table
-	updatedModel.State = models.DataPreviewView
+	updatedModel = PushView(updatedModel, models.DataPreviewView, TableParams(updatedModel))
 	return updatedModel, nil
 }
 
@@ -402,7 +436,7 @@ func HandleRelationshipsResult(m models.Model, msg models.RelationshipsResult) (
 
 	// Update relationships table
 	updatedModel.RelationshipsTable.SetRows(rows)
-	updatedModel.State = models.RelationshipsView
+	updatedModel = PushView(updatedModel, models.RelationshipsView, models.NavParams{})
 	return updatedModel, nil
 }
 
@@ -420,125 +454,10 @@ func HandleFieldUpdateResult(m models.Model, msg models.FieldUpdateResult) (mode
 			updatedModel.IsEditingField = false
 			updatedModel.FieldTextarea.Blur()
 			updatedModel.EditingFieldName = ""
-			// Refresh data preview to show updated value
-			return updatedModel, LoadDataPreview(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewSortDirection, m.DataPreviewSortColumn)
+			// Refresh data preview to show updated value, from the primary since a replica may lag behind the write
+			return servedBy(updatedModel, EndpointPrimary), LoadDataPreview(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, PreviewSortKeys(m), PreviewJSONColumns(m))
 		}
 	}
 
 	return updatedModel, nil
 }
-
-// ExecuteQuery executes a user-provided SQL query and returns results
-func ExecuteQuery(db *sql.DB, selectedDB models.DBType, query string) tea.Cmd {
-	return tea.Cmd(func() tea.Msg {
-		// Trim whitespace from query
-		query = strings.TrimSpace(query)
-		if query == "" {
-			return models.QueryResultMsg{
-				Result: "",
-				Err:    fmt.Errorf("empty query"),
-			}
-		}
-
-		// Check if it's a SELECT query (for read-only operations)
-		isSelect := strings.HasPrefix(strings.ToUpper(query), "SELECT")
-
-		if isSelect {
-			// Execute SELECT query
-			rows, err := db.Query(query)
-			if err != nil {
-				return models.QueryResultMsg{
-					Result: "",
-					Err:    err,
-				}
-			}
-			defer rows.Close()
-
-			// Get column names
-			columns, err := rows.Columns()
-			if err != nil {
-				return models.QueryResultMsg{
-					Result: "",
-					Err:    err,
-				}
-			}
-
-			// Prepare result variables
-			values := make([]interface{}, len(columns))
-			scanArgs := make([]interface{}, len(columns))
-			for i := range values {
-				scanArgs[i] = &values[i]
-			}
-
-			// Collect all rows
-			var allRows [][]string
-			rowCount := 0
-			const maxRows = 1000 // Limit results to prevent memory issues
-
-			for rows.Next() && rowCount < maxRows {
-				err = rows.Scan(scanArgs...)
-				if err != nil {
-					return models.QueryResultMsg{
-						Result: "",
-						Err:    err,
-					}
-				}
-
-				row := make([]string, len(columns))
-				for i, val := range values {
-					if val != nil {
-						row[i] = fmt.Sprintf("%v", val)
-					} else {
-						row[i] = "NULL"
-					}
-				}
-				allRows = append(allRows, row)
-				rowCount++
-			}
-
-			if err = rows.Err(); err != nil {
-				return models.QueryResultMsg{
-					Result: "",
-					Err:    err,
-				}
-			}
-
-			// Create result message
-			var result string
-			if len(allRows) == 0 {
-				result = "Query executed successfully. No rows returned."
-			} else {
-				if rowCount >= maxRows {
-					result = fmt.Sprintf("Query executed successfully. Showing first %d rows out of more results.", maxRows)
-				} else {
-					result = fmt.Sprintf("Query executed successfully. Returned %d rows.", len(allRows))
-				}
-			}
-
-			return models.QueryResultMsg{
-				Result:  result,
-				Columns: columns,
-				Rows:    allRows,
-				Err:     nil,
-			}
-
-		} else {
-			// Execute non-SELECT query (INSERT, UPDATE, DELETE)
-			result, err := db.Exec(query)
-			if err != nil {
-				return models.QueryResultMsg{
-					Result: "",
-					Err:    err,
-				}
-			}
-
-			// Get affected rows count
-			rowsAffected, _ := result.RowsAffected()
-
-			return models.QueryResultMsg{
-				Result: fmt.Sprintf("Query executed successfully. %d rows affected.", rowsAffected),
-				Err:    nil,
-			}
-		}
-	})
-}
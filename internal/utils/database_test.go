@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		want     string
+		wantNull bool
+	}{
+		{"null", nil, "NULL", true},
+		{"numeric bytes kept exact", []byte("12345678901234567890.123456789"), "12345678901234567890.123456789", false},
+		{"large float64", 1e21, "1000000000000000000000", false},
+		{"float32", float32(1e7), "10000000", false},
+		{"unsigned beyond int64", uint64(18446744073709551615), "18446744073709551615", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isNull := database.FormatValue(tt.value)
+			if got != tt.want || isNull != tt.wantNull {
+				t.Errorf("FormatValue(%#v) = %q, %v, want %q, %v", tt.value, got, isNull, tt.want, tt.wantNull)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		schema string
+		table  string
+		field  string
+		want   string
+	}{
+		{"postgres", "postgres", "public", "users", "email", `UPDATE "public"."users" SET "email" = $1 WHERE "id" = $2`},
+		{"mysql without database", "mysql", "", "users", "email", "UPDATE `users` SET `email` = ? WHERE `id` = ?"},
+		{"mariadb", "mariadb", "shop", "users", "email", "UPDATE `shop`.`users` SET `email` = ? WHERE `id` = ?"},
+		{"sqlite", "sqlite3", "main", "users", "email", `UPDATE "users" SET "email" = ? WHERE "id" = ?`},
+		{"clickhouse binds like mysql", "clickhouse", "default", "events", "kind", "UPDATE `default`.`events` SET `kind` = ? WHERE `id` = ?"},
+		{"embedded double quote", "postgres", "public", `odd"table`, `say "hi"`, `UPDATE "public"."odd""table" SET "say ""hi""" = $1 WHERE "id" = $2`},
+		{"embedded backtick", "mysql", "shop", "users", "a`b", "UPDATE `shop`.`users` SET `a``b` = ? WHERE `id` = ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildUpdateSQL(tt.driver, tt.schema, tt.table, tt.field, "id"); got != tt.want {
+				t.Errorf("BuildUpdateSQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTrino serves Trino's statement protocol. Each statement is answered with
+// the result whose key it contains, after an empty first page as a queued
+// query would get; a result with no columns is a query error. The statements
+// received are recorded.
+func fakeTrino(t *testing.T, results map[string][][]interface{}) (string, *[]string) {
+	var mu sync.Mutex
+	var queries []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trino-User") != "analyst" {
+			t.Errorf("X-Trino-User = %q, want analyst", r.Header.Get("X-Trino-User"))
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/statement":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			queries = append(queries, string(body))
+			id := len(queries) - 1
+			mu.Unlock()
+			json.NewEncoder(w).Encode(map[string]interface{}{"nextUri": server.URL + "/v1/statement/executing/" + strconv.Itoa(id)})
+		case r.Method == http.MethodGet:
+			id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v1/statement/executing/"))
+			mu.Lock()
+			query := queries[id]
+			mu.Unlock()
+			for key, rows := range results {
+				if !strings.Contains(query, key) {
+					continue
+				}
+				if len(rows) == 0 {
+					json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "catalog is offline", "errorName": "GENERIC_INTERNAL_ERROR"}})
+					return
+				}
+				columns := make([]map[string]string, len(rows[0]))
+				for i, name := range rows[0] {
+					name, typ, _ := strings.Cut(name.(string), " ")
+					columns[i] = map[string]string{"name": name, "type": typ}
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"columns": columns, "data": rows[1:]})
+				return
+			}
+			t.Errorf("unexpected statement %q", query)
+			http.Error(w, "unexpected statement", http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.Replace(server.URL, "http://", "http://analyst@", 1), &queries
+}
+
+func TestTrino(t *testing.T) {
+	dsn, queries := fakeTrino(t, map[string][][]interface{}{
+		"SELECT 1":                             {{"_col0 integer"}, {1}},
+		"SHOW CATALOGS":                        {{"Catalog varchar(6)"}, {"hive"}, {"broken"}},
+		`"hive".information_schema.schemata`:   {{"schema_name varchar"}, {"default"}, {"sales"}},
+		`"broken".information_schema.schemata`: {},
+		"table_schema = COALESCE(NULLIF('', ''), current_schema)":      {{"table_name varchar"}, {"events"}},
+		"table_schema = COALESCE(NULLIF('sales', ''), current_schema)": {{"table_name varchar"}, {"orders"}},
+		`SELECT * FROM "hive"."sales"."orders" OFFSET 0 LIMIT 10`: {
+			{"id bigint", "total decimal(10,2)", "paid boolean", "items array(varchar)", "note varchar"},
+			{1, "12.50", true, []string{"a", "b"}, nil},
+		},
+	})
+	trino := models.DBType{Name: "Trino", Driver: "trino"}
+	if err := CheckConnectionString("trino", dsn); err != nil {
+		t.Errorf("CheckConnectionString(%q) = %v", dsn, err)
+	}
+	if err := CheckConnectionString("trino", "analyst@localhost:8080"); err == nil {
+		t.Error("CheckConnectionString() accepted a Trino string that is not a URL")
+	}
+
+	msg := ConnectToDB(trino, dsn, "", models.Timeouts{})().(models.ConnectResult)
+	if msg.Err != nil {
+		t.Fatalf("ConnectToDB() error = %v", msg.Err)
+	}
+	defer database.Close(msg.DB)
+	if !reflect.DeepEqual(msg.Tables, []string{"events"}) {
+		t.Errorf("ConnectToDB() tables = %v, want the session schema's [events]", msg.Tables)
+	}
+
+	schemas := LoadSchemas(msg.DB, trino)().(models.SchemasResult)
+	if schemas.Err != nil {
+		t.Fatalf("LoadSchemas() error = %v", schemas.Err)
+	}
+	var names []string
+	for _, schema := range schemas.Schemas {
+		names = append(names, schema.Name)
+	}
+	if want := []string{"hive.default", "hive.sales"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("LoadSchemas() = %v, want %v without the failing catalog", names, want)
+	}
+
+	tables := LoadTablesForSchema(msg.DB, trino, "hive.sales")().(models.TablesResult)
+	if tables.Err != nil || !reflect.DeepEqual(tables.Tables, []string{"orders"}) {
+		t.Errorf("LoadTablesForSchema(hive.sales) = %v, %v, want [orders]", tables.Tables, tables.Err)
+	}
+
+	result, err := database.GetTablePreviewPaginatedWithSort(msg.DB, "trino", "orders", "hive.sales", 10, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("preview error = %v", err)
+	}
+	if want := [][]string{{"1", "12.50", "true", `["a","b"]`, "NULL"}}; !reflect.DeepEqual(result.Rows, want) {
+		t.Errorf("preview rows = %v, want %v", result.Rows, want)
+	}
+	if want := []string{"bigint", "decimal", "boolean", "array", "varchar"}; !reflect.DeepEqual(result.Types, want) {
+		t.Errorf("preview types = %v, want %v", result.Types, want)
+	}
+
+	if _, err := msg.DB.Exec("SELECT ?, ?", "x"); err == nil {
+		t.Error("a statement with more placeholders than arguments ran")
+	}
+	for _, query := range *queries {
+		if strings.Contains(query, "?") {
+			t.Errorf("statement %q was sent with a placeholder", query)
+		}
+	}
+}
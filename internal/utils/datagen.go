@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Value families the generator knows how to produce
+const (
+	FamilyText        = "text"
+	FamilyInteger     = "integer"
+	FamilyDecimal     = "decimal"
+	FamilyBoolean     = "boolean"
+	FamilyDate        = "date"
+	FamilyDateTime    = "datetime"
+	FamilyTime        = "time"
+	FamilyUUID        = "uuid"
+	FamilyJSON        = "json"
+	FamilyBinary      = "binary"
+	FamilyUnsupported = "unsupported"
+)
+
+// unsupportedTypeHints are checked first because they contain other family keywords
+// (e.g. "interval" contains "int", "point" contains "int")
+var unsupportedTypeHints = []string{"interval", "point", "polygon", "geometry", "array", "[]", "range", "tsvector", "xml", "cidr", "inet", "macaddr"}
+
+// sampleWords feed generated text values
+var sampleWords = []string{"alpha", "bravo", "cedar", "delta", "ember", "falcon", "harbor", "indigo", "juniper", "lumen", "maple", "nova", "orbit", "pixel", "quartz", "river", "sierra", "tango", "umber", "violet"}
+
+// ClassifyColumnType maps a declared column type onto a value family.
+// Matching is by keyword so it also follows SQLite's loose type affinity.
+func ClassifyColumnType(dataType string) string {
+	t := strings.ToLower(strings.TrimSpace(dataType))
+	// lib/pq names array types after their element type: _int4, _text
+	if strings.HasPrefix(t, "_") {
+		return FamilyUnsupported
+	}
+	for _, hint := range unsupportedTypeHints {
+		if strings.Contains(t, hint) {
+			return FamilyUnsupported
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(t, "tinyint(1)"), strings.Contains(t, "bool"), t == "bit", t == "bit(1)":
+		return FamilyBoolean
+	case strings.Contains(t, "timestamp"), strings.Contains(t, "datetime"):
+		return FamilyDateTime
+	case strings.Contains(t, "date"):
+		return FamilyDate
+	case strings.HasPrefix(t, "time"):
+		return FamilyTime
+	case strings.Contains(t, "uuid"), strings.Contains(t, "uniqueidentifier"):
+		return FamilyUUID
+	case strings.Contains(t, "json"):
+		return FamilyJSON
+	case strings.Contains(t, "int"), strings.Contains(t, "serial"), t == "year":
+		return FamilyInteger
+	case strings.Contains(t, "numeric"), strings.Contains(t, "decimal"), strings.Contains(t, "real"),
+		strings.Contains(t, "floa"), strings.Contains(t, "doub"), strings.Contains(t, "money"):
+		return FamilyDecimal
+	case strings.Contains(t, "blob"), strings.Contains(t, "bytea"), strings.Contains(t, "binary"):
+		return FamilyBinary
+	case t == "", strings.Contains(t, "char"), strings.Contains(t, "text"), strings.Contains(t, "clob"),
+		strings.Contains(t, "string"), strings.Contains(t, "citext"):
+		return FamilyText
+	default:
+		return FamilyUnsupported
+	}
+}
+
+// ValueGenerator produces synthetic values that respect column specs
+type ValueGenerator struct {
+	rand *rand.Rand
+	now  time.Time
+	seq  int
+}
+
+// NewValueGenerator creates a generator; the same seed yields the same values
+func NewValueGenerator(seed int64, now time.Time) *ValueGenerator {
+	return &ValueGenerator{rand: rand.New(rand.NewSource(seed)), now: now}
+}
+
+// Value generates one value for a column. Foreign key columns pick one of
+// parentKeys; a nil value means NULL.
+func (g *ValueGenerator) Value(col models.ColumnSpec, parentKeys []string) (interface{}, error) {
+	g.seq++
+
+	if col.References != nil {
+		if len(parentKeys) == 0 {
+			if col.Nullable {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("column %s references %s.%s, which has no rows", col.Name, col.References.Table, col.References.Column)
+		}
+		return parentKeys[g.rand.Intn(len(parentKeys))], nil
+	}
+
+	if len(col.EnumValues) > 0 {
+		return col.EnumValues[g.rand.Intn(len(col.EnumValues))], nil
+	}
+
+	switch ClassifyColumnType(col.DataType) {
+	case FamilyText:
+		return g.text(col), nil
+	case FamilyInteger:
+		return g.integer(col), nil
+	case FamilyDecimal:
+		return float64(g.rand.Intn(1_000_000)) / 100, nil
+	case FamilyBoolean:
+		return g.rand.Intn(2) == 1, nil
+	case FamilyDate:
+		return g.recentTime().Format("2006-01-02"), nil
+	case FamilyDateTime:
+		return g.recentTime().Format("2006-01-02 15:04:05"), nil
+	case FamilyTime:
+		return g.recentTime().Format("15:04:05"), nil
+	case FamilyUUID:
+		return g.uuid(), nil
+	case FamilyJSON:
+		return fmt.Sprintf(`{"generated": true, "seq": %d, "tag": %q}`, g.seq, g.word()), nil
+	case FamilyBinary:
+		b := make([]byte, 8)
+		g.rand.Read(b)
+		return b, nil
+	}
+
+	if col.Nullable {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("column %s has unsupported type %s", col.Name, col.DataType)
+}
+
+func (g *ValueGenerator) word() string {
+	return sampleWords[g.rand.Intn(len(sampleWords))]
+}
+
+func (g *ValueGenerator) text(col models.ColumnSpec) string {
+	name := strings.ToLower(col.Name)
+	var value string
+	switch {
+	case strings.Contains(name, "email"):
+		value = fmt.Sprintf("%s%d@example.com", g.word(), g.rand.Intn(100000))
+	case strings.Contains(name, "url"):
+		value = fmt.Sprintf("https://example.com/%s/%d", g.word(), g.rand.Intn(100000))
+	case strings.Contains(name, "phone"):
+		value = fmt.Sprintf("+1555%07d", g.rand.Intn(10_000_000))
+	default:
+		// The sequence number keeps values distinct for unique columns
+		value = fmt.Sprintf("%s %s %d", g.word(), g.word(), g.seq)
+	}
+
+	if col.MaxLength > 0 && len(value) > col.MaxLength {
+		value = value[len(value)-col.MaxLength:]
+	}
+	return value
+}
+
+func (g *ValueGenerator) integer(col models.ColumnSpec) int64 {
+	t := strings.ToLower(col.DataType)
+	switch {
+	case strings.Contains(t, "tinyint"):
+		return int64(g.rand.Intn(128))
+	case strings.Contains(t, "smallint"), strings.Contains(t, "int2"), t == "year":
+		if t == "year" {
+			return int64(2000 + g.rand.Intn(g.now.Year()-1999))
+		}
+		return int64(g.rand.Intn(32768))
+	case col.IsPrimaryKey:
+		// Keys are drawn from a wide range to make collisions with existing rows unlikely
+		return int64(g.rand.Int31())
+	default:
+		return int64(g.rand.Intn(100000))
+	}
+}
+
+// recentTime picks a moment within the year before now
+func (g *ValueGenerator) recentTime() time.Time {
+	return g.now.Add(-time.Duration(g.rand.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func (g *ValueGenerator) uuid() string {
+	b := make([]byte, 16)
+	g.rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// InsertableColumns returns the columns generated rows should set. Database-assigned
+// columns are skipped, as are columns of unsupported types that have a default.
+func InsertableColumns(specs []models.ColumnSpec) []models.ColumnSpec {
+	var cols []models.ColumnSpec
+	for _, col := range specs {
+		if col.Generated {
+			continue
+		}
+		unsupported := col.References == nil && len(col.EnumValues) == 0 && ClassifyColumnType(col.DataType) == FamilyUnsupported
+		if unsupported && col.HasDefault {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// GenerateRows produces n rows for the plan's insertable columns
+func GenerateRows(plan models.TestDataPlan, n int, g *ValueGenerator) ([]string, [][]interface{}, error) {
+	cols := InsertableColumns(plan.Columns)
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.Name
+	}
+
+	rows := make([][]interface{}, n)
+	for r := 0; r < n; r++ {
+		row := make([]interface{}, len(cols))
+		for i, col := range cols {
+			value, err := g.Value(col, plan.ParentKeys[col.Name])
+			if err != nil {
+				return nil, nil, err
+			}
+			row[i] = value
+		}
+		rows[r] = row
+	}
+	return names, rows, nil
+}
+
+// DescribeColumnPlan summarizes how a column will be filled, for the preview list
+func DescribeColumnPlan(col models.ColumnSpec, parentKeys []string) string {
+	switch {
+	case col.Generated:
+		return "assigned by the database"
+	case col.References != nil:
+		if len(parentKeys) == 0 && !col.Nullable {
+			return fmt.Sprintf("⚠ %s.%s has no rows to reference", col.References.Table, col.References.Column)
+		}
+		return fmt.Sprintf("existing %s.%s (%d keys)", col.References.Table, col.References.Column, len(parentKeys))
+	case len(col.EnumValues) > 0:
+		return fmt.Sprintf("one of %d values", len(col.EnumValues))
+	}
+
+	family := ClassifyColumnType(col.DataType)
+	if family == FamilyUnsupported {
+		switch {
+		case col.HasDefault:
+			return "column default (type not generated)"
+		case col.Nullable:
+			return "NULL (type not generated)"
+		default:
+			return "⚠ unsupported type"
+		}
+	}
+	if col.MaxLength > 0 && family == FamilyText {
+		return fmt.Sprintf("%s up to %d chars", family, col.MaxLength)
+	}
+	return family
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestClassifyColumnType(t *testing.T) {
+	tests := []struct {
+		dataType string
+		want     string
+	}{
+		{"integer", FamilyInteger},
+		{"bigint unsigned", FamilyInteger},
+		{"tinyint(1)", FamilyBoolean},
+		{"boolean", FamilyBoolean},
+		{"character varying", FamilyText},
+		{"VARCHAR(50)", FamilyText},
+		{"", FamilyText},
+		{"numeric(10,2)", FamilyDecimal},
+		{"double precision", FamilyDecimal},
+		{"timestamp with time zone", FamilyDateTime},
+		{"datetime", FamilyDateTime},
+		{"date", FamilyDate},
+		{"time without time zone", FamilyTime},
+		{"uuid", FamilyUUID},
+		{"jsonb", FamilyJSON},
+		{"bytea", FamilyBinary},
+		{"interval", FamilyUnsupported},
+		{"point", FamilyUnsupported},
+		{"ARRAY", FamilyUnsupported},
+		{"_int4", FamilyUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataType, func(t *testing.T) {
+			if got := ClassifyColumnType(tt.dataType); got != tt.want {
+				t.Errorf("ClassifyColumnType(%q) = %q, want %q", tt.dataType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueGeneratorValue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	parent := &models.ForeignKeyRef{Table: "users", Column: "id"}
+
+	tests := []struct {
+		name    string
+		col     models.ColumnSpec
+		keys    []string
+		check   func(interface{}) bool
+		wantErr bool
+	}{
+		{"text respects length", models.ColumnSpec{Name: "code", DataType: "varchar(5)", MaxLength: 5},
+			nil, func(v interface{}) bool { return len(v.(string)) <= 5 }, false},
+		{"email column", models.ColumnSpec{Name: "email", DataType: "text"},
+			nil, func(v interface{}) bool { return strings.HasSuffix(v.(string), "@example.com") }, false},
+		{"enum picks a label", models.ColumnSpec{Name: "status", DataType: "job_status", EnumValues: []string{"queued", "done"}},
+			nil, func(v interface{}) bool { return v == "queued" || v == "done" }, false},
+		{"foreign key uses parent key", models.ColumnSpec{Name: "user_id", DataType: "integer", References: parent},
+			[]string{"42"}, func(v interface{}) bool { return v == "42" }, false},
+		{"nullable foreign key without parents", models.ColumnSpec{Name: "user_id", DataType: "integer", Nullable: true, References: parent},
+			nil, func(v interface{}) bool { return v == nil }, false},
+		{"required foreign key without parents", models.ColumnSpec{Name: "user_id", DataType: "integer", References: parent},
+			nil, nil, true},
+		{"date within the last year", models.ColumnSpec{Name: "due", DataType: "date"},
+			nil, func(v interface{}) bool {
+				d, err := time.Parse("2006-01-02", v.(string))
+				return err == nil && !d.After(now) && d.After(now.AddDate(-1, 0, -1))
+			}, false},
+		{"uuid format", models.ColumnSpec{Name: "ref", DataType: "uuid"},
+			nil, func(v interface{}) bool {
+				return regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(v.(string))
+			}, false},
+		{"nullable unsupported type", models.ColumnSpec{Name: "span", DataType: "interval", Nullable: true},
+			nil, func(v interface{}) bool { return v == nil }, false},
+		{"required unsupported type", models.ColumnSpec{Name: "span", DataType: "interval"},
+			nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewValueGenerator(1, now)
+			got, err := g.Value(tt.col, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Value() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !tt.check(got) {
+				t.Errorf("Value() = %#v does not satisfy the column spec", got)
+			}
+		})
+	}
+}
+
+func TestGenerateRowsSkipsDatabaseAssignedColumns(t *testing.T) {
+	plan := models.TestDataPlan{
+		Columns: []models.ColumnSpec{
+			{Name: "id", DataType: "integer", Generated: true, IsPrimaryKey: true},
+			{Name: "name", DataType: "text"},
+			{Name: "span", DataType: "interval", HasDefault: true},
+			{Name: "user_id", DataType: "integer", References: &models.ForeignKeyRef{Table: "users", Column: "id"}},
+		},
+		ParentKeys: map[string][]string{"user_id": {"1", "2"}},
+	}
+
+	columns, rows, err := GenerateRows(plan, 3, NewValueGenerator(7, time.Now()))
+	if err != nil {
+		t.Fatalf("GenerateRows() error = %v", err)
+	}
+	if strings.Join(columns, ",") != "name,user_id" {
+		t.Errorf("GenerateRows() columns = %v, want [name user_id]", columns)
+	}
+	if len(rows) != 3 || len(rows[0]) != 2 {
+		t.Errorf("GenerateRows() produced %d rows of %d values", len(rows), len(rows[0]))
+	}
+}
+
+func TestParseTestDataRowCount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"100", 100, false},
+		{" 5 ", 5, false},
+		{"0", 0, true},
+		{"-3", 0, true},
+		{"abc", 0, true},
+		{"10001", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTestDataRowCount(tt.input)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseTestDataRowCount(%q) = %d, %v, want %d, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
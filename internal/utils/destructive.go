@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Destructive table actions offered from the tables list
+const (
+	DestructiveTruncate = "TRUNCATE"
+	DestructiveDrop     = "DROP"
+)
+
+// DestructiveActionForKey maps a key press to a destructive action, or "" if none
+func DestructiveActionForKey(key string) string {
+	switch key {
+	case "X":
+		return DestructiveTruncate
+	case "D":
+		return DestructiveDrop
+	}
+	return ""
+}
+
+// BuildDestructiveSQL generates the TRUNCATE or DROP TABLE statement for a table
+func BuildDestructiveSQL(driver, action, schema, table string) (string, error) {
+	caps := models.DriverCapabilities(driver)
+	if !caps.TableDDL {
+		return "", fmt.Errorf("unsupported driver: %s", driver)
+	}
+	name := database.QualifiedTableName(driver, schema, table)
+
+	switch action {
+	case DestructiveTruncate:
+		// SQLite has no TRUNCATE; an unqualified DELETE uses its truncate optimization
+		if !caps.Truncate {
+			return "DELETE FROM " + name, nil
+		}
+		return "TRUNCATE TABLE " + name, nil
+	case DestructiveDrop:
+		return "DROP TABLE " + name, nil
+	}
+	return "", fmt.Errorf("unknown table action: %s", action)
+}
+
+// DestructiveConfirmed reports whether the typed confirmation matches the table name exactly
+func DestructiveConfirmed(typed, table string) bool {
+	return table != "" && strings.TrimSpace(typed) == table
+}
+
+// RunDestructiveAction executes a confirmed TRUNCATE or DROP TABLE and records it in the audit log
+func RunDestructiveAction(db *sql.DB, selectedDB models.DBType, connectionStr, migrationFile, action, schema, table string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		query, err := BuildDestructiveSQL(selectedDB.Driver, action, schema, table)
+		if err != nil {
+			return models.DestructiveResult{Action: action, Table: table, Schema: schema, Err: err}
+		}
+
+		kind := strings.ToLower(action) + " table"
+		ctx, cancel := database.StatementContext(db)
+		defer cancel()
+		result, err := db.ExecContext(ctx, query)
+		if err != nil {
+			RecordAudit(selectedDB, connectionStr, kind, query, nil, 0, err)
+			return models.DestructiveResult{Action: action, Table: table, Schema: schema, Err: fmt.Errorf("%s failed: %w", action, err)}
+		}
+		affected, _ := result.RowsAffected()
+		RecordAudit(selectedDB, connectionStr, kind, query, nil, affected, nil)
+		RecordMigration(migrationFile, selectedDB.Driver, kind, query)
+		return models.DestructiveResult{Action: action, Table: table, Schema: schema}
+	})
+}
+
+// HandleDestructiveResult reports the outcome and reloads the tables list after a drop
+func HandleDestructiveResult(m models.Model, msg models.DestructiveResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsRunningDestructive = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	if msg.Action == DestructiveDrop {
+		updatedModel.QueryResult = fmt.Sprintf("✅ Table %s dropped", msg.Table)
+		return updatedModel, tea.Batch(
+			LoadTablesForSchema(m.DB, m.SelectedDB, msg.Schema),
+			ClearResultAfterTimeout(),
+		)
+	}
+
+	updatedModel.QueryResult = fmt.Sprintf("✅ Table %s truncated", msg.Table)
+	return updatedModel, ClearResultAfterTimeout()
+}
@@ -0,0 +1,58 @@
+package utils
+
+import "testing"
+
+func TestBuildDestructiveSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		action  string
+		schema  string
+		want    string
+		wantErr bool
+	}{
+		{"postgres truncate", "postgres", DestructiveTruncate, "public", `TRUNCATE TABLE "public"."jobs"`, false},
+		{"postgres drop default schema", "postgres", DestructiveDrop, "", `DROP TABLE "public"."jobs"`, false},
+		{"cockroach truncate", "cockroach", DestructiveTruncate, "public", `TRUNCATE TABLE "public"."jobs"`, false},
+		{"mysql truncate", "mysql", DestructiveTruncate, "app", "TRUNCATE TABLE `app`.`jobs`", false},
+		{"mysql drop current database", "mysql", DestructiveDrop, "", "DROP TABLE `jobs`", false},
+		{"mariadb drop", "mariadb", DestructiveDrop, "app", "DROP TABLE `app`.`jobs`", false},
+		{"sqlite truncate uses delete", "sqlite3", DestructiveTruncate, "main", `DELETE FROM "jobs"`, false},
+		{"sqlite drop", "sqlite3", DestructiveDrop, "main", `DROP TABLE "jobs"`, false},
+		{"unknown action", "postgres", "VACUUM", "public", "", true},
+		{"unknown driver", "oracle", DestructiveDrop, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildDestructiveSQL(tt.driver, tt.action, tt.schema, "jobs")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("BuildDestructiveSQL() = %q, %v, want %q, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDestructiveConfirmed(t *testing.T) {
+	tests := []struct {
+		name  string
+		typed string
+		table string
+		want  bool
+	}{
+		{"exact match", "jobs", "jobs", true},
+		{"surrounding spaces", "  jobs ", "jobs", true},
+		{"different case", "Jobs", "jobs", false},
+		{"prefix only", "job", "jobs", false},
+		{"empty input", "", "jobs", false},
+		{"no table", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DestructiveConfirmed(tt.typed, tt.table); got != tt.want {
+				t.Errorf("DestructiveConfirmed(%q, %q) = %v, want %v", tt.typed, tt.table, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package utils
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// DoctorStatus grades the outcome of a doctor check
+type DoctorStatus string
+
+const (
+	DoctorOK   DoctorStatus = "ok"
+	DoctorWarn DoctorStatus = "warn"
+	DoctorFail DoctorStatus = "fail"
+	DoctorSkip DoctorStatus = "skip"
+)
+
+// DoctorCheck is one finding of "mirador doctor" and, for a problem, how to fix it
+type DoctorCheck struct {
+	Name   string
+	Status DoctorStatus
+	Detail string
+	Fix    string
+}
+
+// doctorConfigFiles are the files of the configuration directory doctor validates
+var doctorConfigFiles = append(slices.Clone(config.WorkspaceFiles), "worksheets.json", "audit.log")
+
+// ConfigDirChecks checks that the configuration directory can be written and
+// that other users cannot change it. A missing directory is created on the
+// first save, which is not a problem.
+func ConfigDirChecks(dir string) []DoctorCheck {
+	const name = "Config directory"
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return []DoctorCheck{{Name: name, Status: DoctorOK, Detail: dir + " does not exist yet; it is created on the first save"}}
+	case err != nil:
+		return []DoctorCheck{{Name: name, Status: DoctorFail, Detail: err.Error(), Fix: "check the permissions of " + filepath.Dir(dir)}}
+	case !info.IsDir():
+		return []DoctorCheck{{Name: name, Status: DoctorFail, Detail: dir + " is not a directory", Fix: fmt.Sprintf("move it aside: mv %s %s.bak", dir, dir)}}
+	}
+
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return []DoctorCheck{{Name: name, Status: DoctorFail, Detail: "cannot write to " + dir + ": connections, history, and settings are not saved",
+			Fix: fmt.Sprintf("chown -R $(id -un) %s && chmod 700 %s", dir, dir)}}
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	if perm := info.Mode().Perm(); perm&0o022 != 0 {
+		return []DoctorCheck{{Name: name, Status: DoctorWarn, Detail: fmt.Sprintf("%s can be changed by other users (mode %04o)", dir, perm), Fix: "chmod 700 " + dir}}
+	}
+	return []DoctorCheck{{Name: name, Status: DoctorOK, Detail: dir + " is writable"}}
+}
+
+// validateConfigFile reports where a configuration file stops being valid
+// JSON. The audit log holds one JSON entry per line.
+func validateConfigFile(name string, data []byte) error {
+	if strings.HasSuffix(name, ".log") {
+		for i, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 && !json.Valid(line) {
+				return fmt.Errorf("line %d is not valid JSON", i+1)
+			}
+		}
+		return nil
+	}
+	var value any
+	err := json.Unmarshal(data, &value)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("line %d: %v", 1+bytes.Count(data[:syntaxErr.Offset], []byte("\n")), err)
+	}
+	return err
+}
+
+// plainConnectionStrings counts the saved connection strings kept in the
+// clear: not encrypted with the passphrase and not in the OS keychain
+func plainConnectionStrings(data []byte) int {
+	connections, err := config.UnmarshalConnections(data)
+	if err != nil {
+		return 0
+	}
+	plain := 0
+	for _, conn := range connections {
+		for _, value := range []string{conn.ConnectionStr, conn.ReplicaConnectionStr} {
+			if value != "" && !config.IsEncrypted(value) && !config.IsKeychainReference(value) {
+				plain++
+			}
+		}
+	}
+	return plain
+}
+
+// ConfigFileChecks validates the files of the configuration directory. An
+// invalid file is otherwise ignored: mirador starts from an empty one and
+// overwrites it on the next save. Connection strings kept in the clear are
+// flagged when other users can read them.
+func ConfigFileChecks(dir string) []DoctorCheck {
+	var checks []DoctorCheck
+	var valid []string
+	for _, name := range doctorConfigFiles {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorFail, Detail: err.Error(), Fix: fmt.Sprintf("chown $(id -un) %s && chmod 600 %s", path, path)})
+			continue
+		}
+		if err := validateConfigFile(name, data); err != nil {
+			fix := fmt.Sprintf("fix the JSON, or move the file aside to start over: mv %s %s.bak", path, path)
+			if name == config.WorkspaceConnections {
+				fix = "no saved connections are shown and the next save overwrites the file; fix the JSON or restore a backup before saving"
+			}
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorFail, Detail: "invalid: " + err.Error(), Fix: fix})
+			continue
+		}
+		if name == config.WorkspaceConnections {
+			if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0o044 != 0 {
+				if plain := plainConnectionStrings(data); plain > 0 {
+					checks = append(checks, DoctorCheck{Name: name, Status: DoctorWarn,
+						Detail: fmt.Sprintf("other users can read it, and connection strings in it are in the clear (%d)", plain), Fix: "chmod 600 " + path})
+					continue
+				}
+			}
+		}
+		valid = append(valid, name)
+	}
+	if len(valid) > 0 {
+		checks = append([]DoctorCheck{{Name: "Config files", Status: DoctorOK, Detail: strings.Join(valid, ", ")}}, checks...)
+	}
+	return checks
+}
+
+// doctorSettings are the MIRADOR_* environment variables, the values they take,
+// and how a value is read. An unreadable value is silently replaced by the
+// default when mirador runs.
+var doctorSettings = []struct {
+	name     string
+	expected string
+	valid    func(string) bool
+}{
+	{"MIRADOR_STATEMENT_TIMEOUT", "a duration like 2m or a number of seconds", validTimeout},
+	{"MIRADOR_CONNECT_TIMEOUT", "a duration like 10s or a number of seconds", validTimeout},
+	{"MIRADOR_HEALTH_INTERVAL", "a duration like 1m or a number of seconds", validTimeout},
+	{"MIRADOR_AUTOSAVE_INTERVAL", "a duration like 5s or a number of seconds", validTimeout},
+	{"MIRADOR_CONNECT_RETRIES", "a whole number of retries", func(value string) bool {
+		n, err := strconv.Atoi(value)
+		return err == nil && n >= 0
+	}},
+	{"MIRADOR_EXPLAIN_ROWS", "a number of rows, or 0 to turn the check off", validLimit},
+	{"MIRADOR_EXPLAIN_COST", "a planner cost, or 0 to turn the check off", validLimit},
+	{"MIRADOR_EDITOR_HEIGHT", "a number of lines of at least 1", func(value string) bool {
+		n, err := strconv.ParseFloat(value, 64)
+		return err == nil && int(n) > 0
+	}},
+}
+
+// validTimeout reports whether a timeout setting parses
+func validTimeout(value string) bool {
+	_, err := config.ParseTimeout(value)
+	return err == nil
+}
+
+// validLimit reports whether a limit setting is a number of at least 0
+func validLimit(value string) bool {
+	n, err := strconv.ParseFloat(value, 64)
+	return err == nil && n >= 0
+}
+
+// SettingChecks flags MIRADOR_* environment variables set to a value that is
+// ignored in favor of the default
+func SettingChecks(lookup func(string) (string, bool)) []DoctorCheck {
+	var checks []DoctorCheck
+	set := 0
+	for _, setting := range doctorSettings {
+		value, ok := lookup(setting.name)
+		if !ok {
+			continue
+		}
+		set++
+		if !setting.valid(value) {
+			checks = append(checks, DoctorCheck{Name: setting.name, Status: DoctorWarn,
+				Detail: fmt.Sprintf("%q is not %s; the default is used", value, setting.expected), Fix: "set it to " + setting.expected + ", or unset it"})
+		}
+	}
+	if len(checks) > 0 {
+		return checks
+	}
+	detail := "no MIRADOR_* variables set; defaults are used"
+	if set > 0 {
+		detail = fmt.Sprintf("%d MIRADOR_* variables set", set)
+	}
+	return []DoctorCheck{{Name: "Settings", Status: DoctorOK, Detail: detail}}
+}
+
+// DriverChecks checks that every supported database has its driver built in.
+// SQLite is opened in memory as well, since its driver needs a cgo build.
+func DriverChecks() []DoctorCheck {
+	var checks []DoctorCheck
+	var available []string
+	registered := sql.Drivers()
+	for _, dbType := range models.SupportedDatabaseTypes {
+		if !slices.Contains(registered, dbType.Driver) {
+			checks = append(checks, DoctorCheck{Name: dbType.Name, Status: DoctorFail, Detail: "driver " + dbType.Driver + " is not in this build",
+				Fix: "install mirador from source: go install github.com/dancaldera/mirador@latest"})
+			continue
+		}
+		if dbType.Driver == "sqlite3" {
+			version, err := sqliteVersion()
+			if err != nil {
+				checks = append(checks, DoctorCheck{Name: dbType.Name, Status: DoctorFail, Detail: err.Error(),
+					Fix: "rebuild with CGO_ENABLED=1 and a C compiler (gcc or clang) installed"})
+				continue
+			}
+			available = append(available, dbType.Name+" "+version)
+			continue
+		}
+		available = append(available, dbType.Name)
+	}
+	if len(available) > 0 {
+		checks = append([]DoctorCheck{{Name: "Drivers", Status: DoctorOK, Detail: strings.Join(available, ", ")}}, checks...)
+	}
+	return checks
+}
+
+// sqliteVersion opens an in-memory SQLite database and returns its version
+func sqliteVersion() (string, error) {
+	db, err := database.Open("sqlite3", ":memory:")
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+	var version string
+	if err := db.QueryRow("SELECT sqlite_version()").Scan(&version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// osc52Terminals are terminals known to set the clipboard from OSC 52 escape
+// sequences, by TERM_PROGRAM or TERM
+var osc52Terminals = map[string]string{
+	"iTerm.app":      "iTerm2",
+	"WezTerm":        "WezTerm",
+	"ghostty":        "Ghostty",
+	"xterm-ghostty":  "Ghostty",
+	"xterm-kitty":    "kitty",
+	"alacritty":      "Alacritty",
+	"foot":           "foot",
+	"vscode":         "VS Code",
+	"contour":        "Contour",
+	"rio":            "Rio",
+	"Apple_Terminal": "",
+}
+
+// TerminalChecks checks that the terminal can draw the interface: a real
+// terminal with cursor movement, its color depth, and whether it copies OSC 52
+// sequences to the clipboard, which is how a copy reaches the local clipboard
+// over SSH
+func TerminalChecks(getenv func(string) string, isTerminal bool) []DoctorCheck {
+	var checks []DoctorCheck
+	term := getenv("TERM")
+	switch {
+	case !isTerminal:
+		checks = append(checks, DoctorCheck{Name: "Terminal", Status: DoctorWarn, Detail: "standard input is not a terminal", Fix: "run mirador in an interactive terminal"})
+	case term == "" || term == "dumb":
+		checks = append(checks, DoctorCheck{Name: "Terminal", Status: DoctorWarn, Detail: fmt.Sprintf("TERM is %q, which cannot move the cursor", term), Fix: "export TERM=xterm-256color"})
+	default:
+		checks = append(checks, DoctorCheck{Name: "Terminal", Status: DoctorOK, Detail: "TERM=" + term})
+	}
+
+	colorTerm := strings.ToLower(getenv("COLORTERM"))
+	switch {
+	case colorTerm == "truecolor" || colorTerm == "24bit" || strings.HasSuffix(term, "-direct"):
+		checks = append(checks, DoctorCheck{Name: "Colors", Status: DoctorOK, Detail: "24-bit color"})
+	case strings.Contains(term, "256color"):
+		checks = append(checks, DoctorCheck{Name: "Colors", Status: DoctorWarn, Detail: "256 colors; the theme's colors are approximated",
+			Fix: "if the terminal supports 24-bit color, export COLORTERM=truecolor"})
+	default:
+		checks = append(checks, DoctorCheck{Name: "Colors", Status: DoctorWarn, Detail: "16 colors or fewer; the theme's colors are approximated",
+			Fix: "export TERM=xterm-256color, and COLORTERM=truecolor if the terminal supports 24-bit color"})
+	}
+
+	terminal, known := osc52Terminals[getenv("TERM_PROGRAM")]
+	if !known {
+		terminal, known = osc52Terminals[term]
+	}
+	if !known && getenv("WT_SESSION") != "" {
+		terminal, known = "Windows Terminal", true
+	}
+	switch {
+	case known && terminal == "":
+		checks = append(checks, DoctorCheck{Name: "OSC 52", Status: DoctorWarn, Detail: "this terminal ignores OSC 52, so copies over SSH cannot reach the local clipboard",
+			Fix: "use a terminal with OSC 52 support, such as iTerm2, kitty, or WezTerm, when working over SSH"})
+	case known && getenv("TMUX") != "":
+		checks = append(checks, DoctorCheck{Name: "OSC 52", Status: DoctorOK, Detail: terminal + " supports it; inside tmux it needs set-clipboard",
+			Fix: "add set -g set-clipboard on to ~/.tmux.conf if copies do not arrive"})
+	case known:
+		checks = append(checks, DoctorCheck{Name: "OSC 52", Status: DoctorOK, Detail: "supported by " + terminal})
+	default:
+		checks = append(checks, DoctorCheck{Name: "OSC 52", Status: DoctorSkip, Detail: "unknown terminal; support cannot be detected"})
+	}
+	return checks
+}
+
+// ClipboardCheck checks that copies reach a clipboard: a copy tool must be
+// installed, and on Linux it needs a display to hand the text to
+func ClipboardCheck(unsupported bool, getenv func(string) string, goos string) DoctorCheck {
+	const name = "Clipboard"
+	linux := goos != "darwin" && goos != "windows"
+	fix := "install pbcopy"
+	if linux {
+		fix = "install wl-clipboard (Wayland) or xclip or xsel (X11)"
+	}
+	switch {
+	case unsupported:
+		return DoctorCheck{Name: name, Status: DoctorFail, Detail: "no clipboard tool found; copying values and rows fails", Fix: fix}
+	case linux && getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "":
+		detail := "no display is set, so copies fail"
+		if getenv("SSH_CONNECTION") != "" {
+			detail = "connected over SSH without a display, so copies fail"
+		}
+		return DoctorCheck{Name: name, Status: DoctorWarn, Detail: detail, Fix: "forward X11 with ssh -X, or run mirador on the machine you copy to"}
+	}
+	return DoctorCheck{Name: name, Status: DoctorOK, Detail: "copies go to the system clipboard"}
+}
+
+// DoctorConnection picks the saved connection doctor connects to: the one
+// named, or else the one used last
+func DoctorConnection(connections []models.SavedConnection, name string) (models.SavedConnection, bool) {
+	if name != "" {
+		for _, conn := range connections {
+			if conn.Name == name {
+				return conn, true
+			}
+		}
+		return models.SavedConnection{}, false
+	}
+	if len(connections) == 0 {
+		return models.SavedConnection{}, false
+	}
+	last := connections[0]
+	for _, conn := range connections[1:] {
+		if conn.LastUsedAt.After(last.LastUsedAt) {
+			last = conn
+		}
+	}
+	return last, true
+}
+
+// ConnectionCheck connects to a saved connection with its own timeouts and
+// read-only mode. A connection string encrypted with the passphrase is skipped,
+// since doctor does not ask for it.
+func ConnectionCheck(conn models.SavedConnection) DoctorCheck {
+	name := fmt.Sprintf("Connection '%s'", conn.Name)
+	if config.IsEncrypted(conn.ConnectionStr) {
+		return DoctorCheck{Name: name, Status: DoctorSkip, Detail: "the connection string is encrypted; connect from mirador and enter the passphrase"}
+	}
+	connectionStr, err := SavedConnectionString(conn)
+	if err != nil {
+		return DoctorCheck{Name: name, Status: DoctorFail, Detail: err.Error()}
+	}
+
+	start := time.Now()
+	db, err := openConnection(conn.Driver, OpenDSN(conn.Driver, connectionStr, conn.ReadOnly, conn.Immutable), ConnectionTimeouts(conn))
+	if err != nil {
+		check := DoctorCheck{Name: name, Status: DoctorFail, Detail: err.Error()}
+		var explained *ConnectionError
+		if errors.As(ExplainConnectionError(conn.Driver, connectionStr, err), &explained) {
+			check.Detail, check.Fix = explained.Summary, explained.Hint
+		}
+		return check
+	}
+	database.Close(db)
+	return DoctorCheck{Name: name, Status: DoctorOK, Detail: fmt.Sprintf("connected in %s", max(time.Since(start).Round(time.Millisecond), time.Millisecond))}
+}
+
+// FormatDoctorReport lists the checks one per line, each problem followed by
+// its fix, and ends with a count of the problems
+func FormatDoctorReport(checks []DoctorCheck) string {
+	var b strings.Builder
+	failed, warned := 0, 0
+	for _, check := range checks {
+		fmt.Fprintf(&b, "%-6s %s: %s\n", "["+string(check.Status)+"]", check.Name, check.Detail)
+		if check.Fix != "" {
+			fmt.Fprintf(&b, "       fix: %s\n", check.Fix)
+		}
+		switch check.Status {
+		case DoctorFail:
+			failed++
+		case DoctorWarn:
+			warned++
+		}
+	}
+	if failed == 0 && warned == 0 {
+		b.WriteString("\nNo problems found\n")
+	} else {
+		fmt.Fprintf(&b, "\n%d failed, %d warnings\n", failed, warned)
+	}
+	return b.String()
+}
+
+// stdinIsTerminal reports whether standard input is a terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// RunDoctorCommand runs "mirador doctor [saved connection]": it checks the
+// configuration directory and files, settings, drivers, terminal, and
+// clipboard, then connects to the named saved connection or the one used last.
+// It fails when any check fails.
+func RunDoctorCommand(args []string, out io.Writer) error {
+	if len(args) > 1 || len(args) == 1 && strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: mirador doctor [saved connection]")
+	}
+	dir, err := config.ConfigDirPath()
+	if err != nil {
+		return err
+	}
+
+	checks := ConfigDirChecks(dir)
+	checks = append(checks, ConfigFileChecks(dir)...)
+	checks = append(checks, SettingChecks(os.LookupEnv)...)
+	checks = append(checks, DriverChecks()...)
+	checks = append(checks, TerminalChecks(os.Getenv, stdinIsTerminal())...)
+	checks = append(checks, ClipboardCheck(clipboard.Unsupported, os.Getenv, runtime.GOOS))
+
+	// Loading connections creates the configuration directory, so a missing one
+	// is left alone
+	var connections []models.SavedConnection
+	if _, err := os.Stat(dir); err == nil {
+		connections, _ = config.LoadSavedConnections()
+	}
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	}
+	switch conn, ok := DoctorConnection(connections, name); {
+	case ok:
+		checks = append(checks, ConnectionCheck(conn))
+	case name != "":
+		checks = append(checks, DoctorCheck{Name: fmt.Sprintf("Connection '%s'", name), Status: DoctorFail, Detail: "no saved connection has this name",
+			Fix: "run mirador doctor without a name to check the connection used last"})
+	default:
+		checks = append(checks, DoctorCheck{Name: "Connection", Status: DoctorSkip, Detail: "no saved connections"})
+	}
+
+	fmt.Fprint(out, FormatDoctorReport(checks))
+	for _, check := range checks {
+		if check.Status == DoctorFail {
+			return fmt.Errorf("mirador doctor found problems")
+		}
+	}
+	return nil
+}
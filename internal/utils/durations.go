@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Units the values of a humanized duration column are read in
+const (
+	DurationInterval = "interval" // Interval text such as "1 day 02:13:00"
+	DurationSeconds  = "s"        // A number of seconds
+	DurationMillis   = "ms"       // A number of milliseconds
+)
+
+// durationPart is one unit of a humanized duration
+type durationPart struct {
+	n    int64
+	unit string
+}
+
+// HumanizeDuration writes a duration value in its largest unit and the next
+// one, e.g. "2h 13m". unit says how the value is read: as interval text
+// (PostgreSQL "1 year 2 mons 3 days 04:05:06", MySQL "838:59:59") or as a number
+// of seconds or milliseconds.
+func HumanizeDuration(value, unit string) (string, bool) {
+	if unit == DurationInterval {
+		return humanizeInterval(value)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return "", false
+	}
+	scale := float64(time.Second)
+	if unit == DurationMillis {
+		scale = float64(time.Millisecond)
+	}
+	nanos := math.Round(n * scale)
+	if math.Abs(nanos) >= math.MaxInt64 {
+		return "", false
+	}
+	return humanizeExact(0, time.Duration(nanos)), true
+}
+
+// humanizeInterval reads interval text in PostgreSQL's default output style,
+// whose years, months, and days are followed by a clock part, or a MySQL TIME
+func humanizeInterval(value string) (string, bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "", false
+	}
+	var months, days int64
+	var clock time.Duration
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			d, ok := parseClock(fields[i])
+			if !ok {
+				return "", false
+			}
+			clock += d
+			continue
+		}
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil || i+1 == len(fields) {
+			return "", false
+		}
+		i++
+		switch strings.TrimSuffix(fields[i], "s") {
+		case "year":
+			months += n * 12
+		case "mon":
+			months += n
+		case "day":
+			days += n
+		default:
+			return "", false
+		}
+	}
+
+	return humanizeExact(months, time.Duration(days)*24*time.Hour+clock), true
+}
+
+// parseClock reads [-]HH:MM[:SS[.ffffff]], with hours beyond 24 as MySQL TIME
+// values have
+func parseClock(s string) (time.Duration, bool) {
+	sign := time.Duration(1)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, s = -1, rest
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, false
+	}
+	hours, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || hours < 0 {
+		return 0, false
+	}
+	minutes, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	if len(fields) == 3 {
+		seconds, err := time.ParseDuration(fields[2] + "s")
+		if err != nil || seconds < 0 || seconds >= time.Minute {
+			return 0, false
+		}
+		d += seconds
+	}
+	return sign * d, true
+}
+
+// humanizeExact writes months, which have no fixed length, as years and
+// months, and d as days down to milliseconds
+func humanizeExact(months int64, d time.Duration) string {
+	if months == 0 && d != 0 && d > -time.Millisecond && d < time.Millisecond {
+		return "<1ms" // Rather than rounded to nothing
+	}
+	parts := []durationPart{{months / 12, "y"}, {months % 12, "mo"}, {int64(d / (24 * time.Hour)), "d"}}
+	d %= 24 * time.Hour
+	parts = append(parts, durationPart{int64(d / time.Hour), "h"})
+	d %= time.Hour
+	parts = append(parts, durationPart{int64(d / time.Minute), "m"})
+	d %= time.Minute
+	parts = append(parts, durationPart{int64(d / time.Second), "s"})
+	d %= time.Second
+	parts = append(parts, durationPart{int64(d / time.Millisecond), "ms"})
+	return formatDurationParts(parts)
+}
+
+// formatDurationParts writes the largest non-zero part and, when it is not
+// zero, the one after it. A negative duration is written with one sign.
+func formatDurationParts(parts []durationPart) string {
+	for i, p := range parts {
+		if p.n == 0 {
+			continue
+		}
+		text := fmt.Sprintf("%d%s", p.n, p.unit)
+		if i+1 < len(parts) && parts[i+1].n != 0 {
+			next := parts[i+1].n
+			if p.n < 0 && next < 0 {
+				next = -next
+			}
+			text += fmt.Sprintf(" %d%s", next, parts[i+1].unit)
+		}
+		return text
+	}
+	return "0s"
+}
+
+// PreviewDurationColumns returns the humanized duration columns of the table
+// being previewed, each with its unit
+func PreviewDurationColumns(m models.Model) map[string]string {
+	return m.DurationColumns[jsonColumnsKey(m.SelectedSchema, m.SelectedTable)]
+}
+
+// ToggleDurationColumn steps a preview column through its duration units: an
+// interval column (MySQL TIME included) between raw and humanized, a numeric
+// column from raw to seconds, milliseconds, and back to raw
+func ToggleDurationColumn(m models.Model, column string) (models.Model, error) {
+	dbType := ""
+	for i, col := range m.DataPreviewAllColumns {
+		if col == column && i < len(m.DataPreviewColumnTypes) {
+			dbType = strings.ToLower(m.DataPreviewColumnTypes[i])
+		}
+	}
+
+	current := PreviewDurationColumns(m)[column]
+	next := ""
+	switch family := ClassifyColumnType(dbType); {
+	case strings.Contains(dbType, "interval"),
+		dbType == "time" && (m.SelectedDB.Driver == "mysql" || m.SelectedDB.Driver == "mariadb"):
+		if current == "" {
+			next = DurationInterval
+		}
+	case family == FamilyInteger || family == FamilyDecimal:
+		switch current {
+		case "":
+			next = DurationSeconds
+		case DurationSeconds:
+			next = DurationMillis
+		}
+	default:
+		return m, fmt.Errorf("%s is not an interval or numeric column", column)
+	}
+
+	key := jsonColumnsKey(m.SelectedSchema, m.SelectedTable)
+	updatedModel := m
+	updatedModel.DurationColumns = make(map[string]map[string]string, len(m.DurationColumns)+1)
+	for table, columns := range m.DurationColumns {
+		updatedModel.DurationColumns[table] = columns
+	}
+	columns := make(map[string]string, len(m.DurationColumns[key])+1)
+	for col, unit := range m.DurationColumns[key] {
+		columns[col] = unit
+	}
+	if next == "" {
+		delete(columns, column)
+	} else {
+		columns[column] = next
+	}
+	updatedModel.DurationColumns[key] = columns
+	return updatedModel, nil
+}
+
+// ApplyDurationColumns returns a copy of rows with the values of the humanized
+// columns written as durations; values that are not durations stay as they are.
+// The original rows are returned untouched when no column is humanized.
+func ApplyDurationColumns(rows [][]string, columns []string, units map[string]string) [][]string {
+	if len(units) == 0 {
+		return rows
+	}
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = append([]string(nil), row...)
+		for j := 0; j < len(row) && j < len(columns); j++ {
+			if unit := units[columns[j]]; unit != "" {
+				if humanized, ok := HumanizeDuration(row[j], unit); ok {
+					out[i][j] = humanized
+				}
+			}
+		}
+	}
+	return out
+}
+
+// DurationColumnsLabel lists the humanized duration columns of the table being
+// previewed for the preview's header, e.g. "⏱ elapsed (s), wait (interval)"
+func DurationColumnsLabel(m models.Model) string {
+	units := PreviewDurationColumns(m)
+	var names []string
+	for _, column := range m.DataPreviewAllColumns {
+		if unit := units[column]; unit != "" {
+			names = append(names, fmt.Sprintf("%s (%s)", column, unit))
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "⏱ " + strings.Join(names, ", ")
+}
+
+// FieldDetailDuration humanizes the value shown in the field detail view when
+// its column is a humanized duration column
+func FieldDetailDuration(m models.Model) (string, bool) {
+	unit := PreviewDurationColumns(m)[m.SelectedFieldForDetail]
+	if unit == "" {
+		return "", false
+	}
+	value, _ := FieldDetailColumn(m)
+	return HumanizeDuration(value, unit)
+}
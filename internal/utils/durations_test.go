@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		unit   string
+		want   string
+		wantOK bool
+	}{
+		{"seconds", "7980", DurationSeconds, "2h 13m", true},
+		{"largest unit alone when the next is zero", "86460", DurationSeconds, "1d", true},
+		{"fractional seconds", "1.5", DurationSeconds, "1s 500ms", true},
+		{"milliseconds", "45000", DurationMillis, "45s", true},
+		{"negative", "-7980", DurationSeconds, "-2h 13m", true},
+		{"zero", "0", DurationSeconds, "0s", true},
+		{"under a millisecond", "0.0004", DurationSeconds, "<1ms", true},
+		{"not a number", "soon", DurationSeconds, "", false},
+		{"too large", "1e30", DurationSeconds, "", false},
+		{"postgres clock", "02:13:00", DurationInterval, "2h 13m", true},
+		{"postgres days and clock", "3 days 04:05:06", DurationInterval, "3d 4h", true},
+		{"postgres years and months", "1 year 2 mons 3 days", DurationInterval, "1y 2mo", true},
+		{"postgres one day", "1 day", DurationInterval, "1d", true},
+		{"postgres negative clock", "-00:00:01.5", DurationInterval, "-1s 500ms", true},
+		{"postgres mixed signs", "-1 days +02:00:00", DurationInterval, "-22h", true},
+		{"mysql time beyond a day", "838:59:59", DurationInterval, "34d 22h", true},
+		{"zero interval", "00:00:00", DurationInterval, "0s", true},
+		{"iso interval", "P1DT2H", DurationInterval, "", false},
+		{"unknown unit", "3 fortnights", DurationInterval, "", false},
+		{"minutes out of range", "01:75:00", DurationInterval, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := HumanizeDuration(tt.value, tt.unit)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("HumanizeDuration(%q, %q) = %q, %v, want %q, %v", tt.value, tt.unit, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestToggleDurationColumn(t *testing.T) {
+	m := models.Model{
+		SelectedDB:             models.DBType{Driver: "mysql"},
+		SelectedTable:          "jobs",
+		DataPreviewAllColumns:  []string{"name", "elapsed", "wait", "runtime"},
+		DataPreviewColumnTypes: []string{"VARCHAR", "BIGINT", "TIME", "DECIMAL"},
+	}
+
+	tests := []struct {
+		name    string
+		column  string
+		presses int
+		want    string
+		wantErr bool
+	}{
+		{"numeric column reads seconds first", "elapsed", 1, DurationSeconds, false},
+		{"then milliseconds", "elapsed", 2, DurationMillis, false},
+		{"then raw again", "elapsed", 3, "", false},
+		{"mysql time is an interval", "wait", 1, DurationInterval, false},
+		{"interval back to raw", "wait", 2, "", false},
+		{"text column", "name", 1, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m
+			var err error
+			for i := 0; i < tt.presses && err == nil; i++ {
+				got, err = ToggleDurationColumn(got, tt.column)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToggleDurationColumn(%q) error = %v, wantErr %v", tt.column, err, tt.wantErr)
+			}
+			if unit := PreviewDurationColumns(got)[tt.column]; unit != tt.want {
+				t.Errorf("ToggleDurationColumn(%q) x%d unit = %q, want %q", tt.column, tt.presses, unit, tt.want)
+			}
+		})
+	}
+
+	toggled, _ := ToggleDurationColumn(m, "runtime")
+	if m.DurationColumns != nil {
+		t.Errorf("ToggleDurationColumn() changed the original model: %v", m.DurationColumns)
+	}
+	if got := DurationColumnsLabel(toggled); got != "⏱ runtime (s)" {
+		t.Errorf("DurationColumnsLabel() = %q, want %q", got, "⏱ runtime (s)")
+	}
+	toggled.SelectedTable = "users"
+	if got := PreviewDurationColumns(toggled); len(got) != 0 {
+		t.Errorf("PreviewDurationColumns() of another table = %v, want none", got)
+	}
+}
+
+func TestApplyDurationColumns(t *testing.T) {
+	rows := [][]string{{"a", "7980", "NULL"}, {"b", "90", "02:00:00"}}
+	got := ApplyDurationColumns(rows, []string{"name", "elapsed", "wait"}, map[string]string{"elapsed": DurationSeconds, "wait": DurationInterval})
+	want := [][]string{{"a", "2h 13m", "NULL"}, {"b", "1m 30s", "2h"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyDurationColumns() = %v, want %v", got, want)
+	}
+	if rows[0][1] != "7980" {
+		t.Errorf("ApplyDurationColumns() modified the stored rows: %v", rows)
+	}
+}
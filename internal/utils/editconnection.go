@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// EditSavedConnection renames the saved connection at idx and replaces its
+// connection string, keeping its other settings, and saves the connections.
+// A changed string is stored normalized; the warnings about it are returned.
+func EditSavedConnection(m models.Model, idx int, name, connectionStr string) (models.Model, []string, error) {
+	name, connectionStr = strings.TrimSpace(name), strings.TrimSpace(connectionStr)
+	if idx < 0 || idx >= len(m.SavedConnections) {
+		return m, nil, fmt.Errorf("the connection being edited no longer exists")
+	}
+	if name == "" {
+		return m, nil, fmt.Errorf("connection name is required")
+	}
+	if connectionStr == "" {
+		return m, nil, fmt.Errorf("connection string is required")
+	}
+	for i, conn := range m.SavedConnections {
+		if i != idx && conn.Name == name {
+			return m, nil, fmt.Errorf("a connection named '%s' already exists", name)
+		}
+	}
+
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	previous := updatedModel.SavedConnections[idx].Name
+	// An unchanged string keeps its saved form, which favorites, notes, and
+	// snapshots are filed under
+	var warnings []string
+	if connectionStr != updatedModel.SavedConnections[idx].ConnectionStr {
+		if err := CheckConnectionString(updatedModel.SavedConnections[idx].Driver, connectionStr); err != nil {
+			return m, nil, err
+		}
+		connectionStr, warnings = NormalizeConnectionString(updatedModel.SavedConnections[idx].Driver, connectionStr)
+	}
+	updatedModel.SavedConnections[idx].Name = name
+	updatedModel.SavedConnections[idx].ConnectionStr = connectionStr
+	if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+		return m, nil, fmt.Errorf("failed to save connections: %w", err)
+	}
+	// Keep recording use of a renamed connection that is open
+	if updatedModel.ConnectionName == previous {
+		updatedModel.ConnectionName = name
+	}
+	return UpdateSavedConnectionsList(updatedModel), warnings, nil
+}
+
+// DuplicateConnectionName names the copy of a saved connection: "name copy",
+// or "name copy 2" and so on when that is taken
+func DuplicateConnectionName(connections []models.SavedConnection, name string) string {
+	taken := func(candidate string) bool {
+		return slices.ContainsFunc(connections, func(conn models.SavedConnection) bool { return conn.Name == candidate })
+	}
+	candidate := name + " copy"
+	for n := 2; taken(candidate); n++ {
+		candidate = fmt.Sprintf("%s copy %d", name, n)
+	}
+	return candidate
+}
+
+// DuplicateSavedConnection saves a copy of a saved connection right after it,
+// with every setting but its name and usage, and returns the copy's name
+func DuplicateSavedConnection(m models.Model, name string) (models.Model, string, error) {
+	idx := slices.IndexFunc(m.SavedConnections, func(conn models.SavedConnection) bool { return conn.Name == name })
+	if idx < 0 {
+		return m, "", fmt.Errorf("connection '%s' not found", name)
+	}
+	duplicate := m.SavedConnections[idx]
+	duplicate.Name = DuplicateConnectionName(m.SavedConnections, name)
+	duplicate.Tags = slices.Clone(duplicate.Tags)
+	duplicate.LastUsedAt, duplicate.UseCount = time.Time{}, 0
+
+	updatedModel := m
+	updatedModel.SavedConnections = slices.Insert(slices.Clone(m.SavedConnections), idx+1, duplicate)
+	if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+		return m, "", fmt.Errorf("failed to save connections: %w", err)
+	}
+	return UpdateSavedConnectionsList(updatedModel), duplicate.Name, nil
+}
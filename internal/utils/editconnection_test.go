@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestEditSavedConnection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MIRADOR_KEYCHAIN", "0")
+
+	saved := []models.SavedConnection{
+		{Name: "prod", Driver: "postgres", ConnectionStr: "postgres://app@db/prod", Environment: models.EnvironmentProduction, PageSize: 100},
+		{Name: "local", Driver: "sqlite3", ConnectionStr: "/tmp/app.db"},
+	}
+
+	tests := []struct {
+		name          string
+		idx           int
+		newName       string
+		connectionStr string
+		wantErr       bool
+	}{
+		{"rename and new string", 0, "production", "postgres://app@db2:5432/prod?sslmode=require", false},
+		{"same name", 1, "local", "/tmp/other.db", false},
+		{"unchanged string keeps its saved form", 0, "prod", "postgres://app@db/prod", false},
+		{"name taken", 0, "local", "postgres://app@db/prod", true},
+		{"empty name", 0, "  ", "postgres://app@db/prod", true},
+		{"empty connection string", 1, "local", "", true},
+		{"no such connection", 2, "new", "/tmp/new.db", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.Model{SavedConnections: append([]models.SavedConnection(nil), saved...), ConnectionName: saved[0].Name}
+			m.SavedConnectionsList = list.New(nil, list.NewDefaultDelegate(), 80, 20)
+			got, _, err := EditSavedConnection(m, tt.idx, tt.newName, tt.connectionStr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EditSavedConnection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !reflect.DeepEqual(m.SavedConnections[0], saved[0]) {
+					t.Errorf("a failed edit changed the connections: %+v", m.SavedConnections)
+				}
+				return
+			}
+
+			conn := got.SavedConnections[tt.idx]
+			want := saved[tt.idx]
+			want.Name, want.ConnectionStr = tt.newName, tt.connectionStr
+			if !reflect.DeepEqual(conn, want) {
+				t.Errorf("edited connection = %+v, want %+v", conn, want)
+			}
+			if !reflect.DeepEqual(m.SavedConnections[tt.idx], saved[tt.idx]) {
+				t.Errorf("the edit changed the earlier model's connections")
+			}
+			if tt.idx == 0 && got.ConnectionName != tt.newName {
+				t.Errorf("ConnectionName = %q, want the new name %q", got.ConnectionName, tt.newName)
+			}
+
+			loaded, err := config.LoadSavedConnections()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(loaded) != len(saved) || loaded[tt.idx].Name != tt.newName || loaded[tt.idx].ConnectionStr != tt.connectionStr {
+				t.Errorf("saved connections = %+v", loaded)
+			}
+		})
+	}
+}
+
+func TestDuplicateConnectionName(t *testing.T) {
+	tests := []struct {
+		name  string
+		saved []string
+		want  string
+	}{
+		{"first copy", []string{"prod"}, "prod copy"},
+		{"copy taken", []string{"prod", "prod copy"}, "prod copy 2"},
+		{"several copies taken", []string{"prod", "prod copy", "prod copy 2"}, "prod copy 3"},
+		{"copy of a copy", []string{"prod copy"}, "prod copy copy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connections []models.SavedConnection
+			for _, name := range tt.saved {
+				connections = append(connections, models.SavedConnection{Name: name})
+			}
+			if got := DuplicateConnectionName(connections, tt.saved[0]); got != tt.want {
+				t.Errorf("DuplicateConnectionName(%v, %q) = %q, want %q", tt.saved, tt.saved[0], got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateSavedConnection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MIRADOR_KEYCHAIN", "0")
+
+	m := models.Model{SavedConnections: []models.SavedConnection{
+		{Name: "prod", Driver: "postgres", ConnectionStr: "postgres://app@db/prod", Tags: []string{"eu"}, ReadOnly: true, UseCount: 4, LastUsedAt: time.Now()},
+		{Name: "local", Driver: "sqlite3", ConnectionStr: "/tmp/app.db"},
+	}}
+	m.SavedConnectionsList = list.New(nil, list.NewDefaultDelegate(), 80, 20)
+
+	got, name, err := DuplicateSavedConnection(m, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "prod copy" || len(got.SavedConnections) != 3 || got.SavedConnections[1].Name != name {
+		t.Fatalf("DuplicateSavedConnection() = %q, %+v", name, got.SavedConnections)
+	}
+	duplicate := got.SavedConnections[1]
+	if duplicate.ConnectionStr != "postgres://app@db/prod" || !duplicate.ReadOnly || !reflect.DeepEqual(duplicate.Tags, []string{"eu"}) {
+		t.Errorf("the copy lost settings: %+v", duplicate)
+	}
+	if duplicate.UseCount != 0 || !duplicate.LastUsedAt.IsZero() {
+		t.Errorf("the copy kept the usage of the original: %+v", duplicate)
+	}
+	if len(m.SavedConnections) != 2 {
+		t.Errorf("the earlier model's connections changed: %+v", m.SavedConnections)
+	}
+
+	loaded, err := config.LoadSavedConnections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 3 || loaded[1].Name != name {
+		t.Errorf("saved connections = %+v", loaded)
+	}
+
+	if _, _, err := DuplicateSavedConnection(m, "missing"); err == nil {
+		t.Error("DuplicateSavedConnection() of a missing connection succeeded")
+	}
+}
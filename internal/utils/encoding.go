@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// InvalidUTF8Badge marks values that contained bytes which are not valid UTF-8
+const InvalidUTF8Badge = "⚠ invalid UTF-8"
+
+// HasInvalidUTF8 reports whether a value contains bytes that are not valid UTF-8
+func HasInvalidUTF8(value string) bool {
+	return !utf8.ValidString(value)
+}
+
+// SafeDisplayText makes a value safe to print in the terminal. Invalid UTF-8
+// sequences and control characters (other than newlines and tabs) are replaced
+// with U+FFFD so raw bytes and escape codes cannot corrupt the screen.
+func SafeDisplayText(value string) string {
+	if utf8.ValidString(value) && strings.IndexFunc(value, isUnsafeControl) < 0 {
+		return value
+	}
+	return strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || isUnsafeControl(r) {
+			return utf8.RuneError
+		}
+		return r
+	}, strings.ToValidUTF8(value, string(utf8.RuneError)))
+}
+
+func isUnsafeControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\n' && r != '\t'
+}
+
+// SanitizeRowsForDisplay returns a copy of rows with every cell passed through SafeDisplayText
+func SanitizeRowsForDisplay(rows [][]string) [][]string {
+	sanitized := make([][]string, len(rows))
+	for i, row := range rows {
+		out := make([]string, len(row))
+		for j, cell := range row {
+			out[j] = SafeDisplayText(cell)
+		}
+		sanitized[i] = out
+	}
+	return sanitized
+}
+
+// CountInvalidUTF8 returns how many cells contain bytes that are not valid UTF-8
+func CountInvalidUTF8(rows [][]string) int {
+	count := 0
+	for _, row := range rows {
+		for _, cell := range row {
+			if HasInvalidUTF8(cell) {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "testing"
+
+func TestSafeDisplayText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain ascii", "hello", "hello"},
+		{"valid multibyte", "café ☕", "café ☕"},
+		{"newlines and tabs kept", "a\n\tb", "a\n\tb"},
+		{"invalid byte", "ab\xffcd", "ab�cd"},
+		{"truncated sequence", "caf\xc3", "caf�"},
+		{"latin1 bytes", "na\xefve", "na�ve"},
+		{"escape sequence", "\x1b[31mred", "�[31mred"},
+		{"carriage return", "a\rb", "a�b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeDisplayText(tt.value); got != tt.want {
+				t.Errorf("SafeDisplayText(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountInvalidUTF8(t *testing.T) {
+	rows := [][]string{
+		{"ok", "bad\xfe"},
+		{"\x80", "café"},
+	}
+
+	if got := CountInvalidUTF8(rows); got != 2 {
+		t.Errorf("CountInvalidUTF8() = %d, want 2", got)
+	}
+	sanitized := SanitizeRowsForDisplay(rows)
+	if CountInvalidUTF8(sanitized) != 0 {
+		t.Errorf("SanitizeRowsForDisplay() left invalid bytes: %q", sanitized)
+	}
+	if rows[0][1] != "bad\xfe" {
+		t.Errorf("original rows were modified")
+	}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// NextEnvironment returns the label after env in the cycle none → production →
+// staging → dev → none. An unknown label starts the cycle over.
+func NextEnvironment(env string) string {
+	i := slices.Index(models.Environments, env)
+	return models.Environments[(i+1)%len(models.Environments)]
+}
+
+// CycleConnectionEnvironment moves a saved connection to its next environment
+// label and saves the connections
+func CycleConnectionEnvironment(m models.Model, name string) (models.Model, string, error) {
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	for i := range updatedModel.SavedConnections {
+		if updatedModel.SavedConnections[i].Name == name {
+			env := NextEnvironment(updatedModel.SavedConnections[i].Environment)
+			updatedModel.SavedConnections[i].Environment = env
+			if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+				return m, "", fmt.Errorf("failed to save connections: %w", err)
+			}
+			return UpdateSavedConnectionsList(updatedModel), env, nil
+		}
+	}
+	return m, "", fmt.Errorf("connection '%s' not found", name)
+}
+
+// EnvironmentBadge labels an environment for the saved connections list and the
+// environment banner, e.g. "🚨 production"; it is empty without a label
+func EnvironmentBadge(env string) string {
+	switch env {
+	case models.EnvironmentProduction:
+		return "🚨 production"
+	case models.EnvironmentStaging:
+		return "🧪 staging"
+	case models.EnvironmentDevelopment:
+		return "🛠 dev"
+	}
+	return ""
+}
+
+// ConfirmsWrites reports whether a write statement or field edit asks before it
+// runs: always in safe mode, and on production connections
+func ConfirmsWrites(m models.Model) bool {
+	return m.SafeMode || m.Environment == models.EnvironmentProduction
+}
+
+// WriteConfirmLabel names why a write asks for confirmation, for its prompt and
+// cancel message. Production is named first, since it is what is at stake.
+func WriteConfirmLabel(m models.Model) string {
+	if m.Environment == models.EnvironmentProduction {
+		return "production"
+	}
+	return "safe mode"
+}
+
+// CopyConfirmsWrites reports whether copying rows into the chosen target asks
+// for confirmation first: in safe mode, or when the target is a production
+// connection, whatever the environment of the source
+func CopyConfirmsWrites(m models.Model) bool {
+	return m.SafeMode || CopyTargetEnvironment(m) == models.EnvironmentProduction
+}
+
+// CopyTargetEnvironment returns the environment label of the copy's target connection
+func CopyTargetEnvironment(m models.Model) string {
+	if m.CopyTargetIndex < 0 || m.CopyTargetIndex >= len(m.SavedConnections) {
+		return models.EnvironmentNone
+	}
+	return m.SavedConnections[m.CopyTargetIndex].Environment
+}
+
+// CopyConfirmLabel names why a copy asks for confirmation, for its prompt and
+// cancel message
+func CopyConfirmLabel(m models.Model) string {
+	if CopyTargetEnvironment(m) == models.EnvironmentProduction {
+		return "production target"
+	}
+	return "safe mode"
+}
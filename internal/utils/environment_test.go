@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNextEnvironment(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{models.EnvironmentNone, models.EnvironmentProduction},
+		{models.EnvironmentProduction, models.EnvironmentStaging},
+		{models.EnvironmentStaging, models.EnvironmentDevelopment},
+		{models.EnvironmentDevelopment, models.EnvironmentNone},
+		{"qa", models.EnvironmentNone},
+	}
+	for _, tt := range tests {
+		if got := NextEnvironment(tt.env); got != tt.want {
+			t.Errorf("NextEnvironment(%q) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmsWrites(t *testing.T) {
+	tests := []struct {
+		safeMode  bool
+		env       string
+		want      bool
+		wantLabel string
+	}{
+		{false, models.EnvironmentNone, false, "safe mode"},
+		{false, models.EnvironmentStaging, false, "safe mode"},
+		{true, models.EnvironmentDevelopment, true, "safe mode"},
+		{false, models.EnvironmentProduction, true, "production"},
+		{true, models.EnvironmentProduction, true, "production"},
+	}
+	for _, tt := range tests {
+		m := models.Model{SafeMode: tt.safeMode, Environment: tt.env}
+		if got := ConfirmsWrites(m); got != tt.want {
+			t.Errorf("ConfirmsWrites(safe mode %v, %q) = %v, want %v", tt.safeMode, tt.env, got, tt.want)
+		}
+		if got := WriteConfirmLabel(m); got != tt.wantLabel {
+			t.Errorf("WriteConfirmLabel(safe mode %v, %q) = %q, want %q", tt.safeMode, tt.env, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestCopyConfirmsWrites(t *testing.T) {
+	targets := []models.SavedConnection{
+		{Name: "scratch", Environment: models.EnvironmentDevelopment},
+		{Name: "prod", Environment: models.EnvironmentProduction},
+	}
+	tests := []struct {
+		name      string
+		safeMode  bool
+		sourceEnv string
+		target    int
+		want      bool
+		wantLabel string
+	}{
+		{"dev target", false, models.EnvironmentNone, 0, false, "safe mode"},
+		{"production source, dev target", false, models.EnvironmentProduction, 0, false, "safe mode"},
+		{"production target", false, models.EnvironmentDevelopment, 1, true, "production target"},
+		{"safe mode", true, models.EnvironmentNone, 0, true, "safe mode"},
+		{"no target chosen", false, models.EnvironmentNone, -1, false, "safe mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.Model{SafeMode: tt.safeMode, Environment: tt.sourceEnv, SavedConnections: targets, CopyTargetIndex: tt.target}
+			if got := CopyConfirmsWrites(m); got != tt.want {
+				t.Errorf("CopyConfirmsWrites() = %v, want %v", got, tt.want)
+			}
+			if got := CopyConfirmLabel(m); got != tt.wantLabel {
+				t.Errorf("CopyConfirmLabel() = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// CostCheckLimit is the LIMIT offered when the cost check warns about a query
+const CostCheckLimit = 100
+
+// planNodeEstimate matches a PostgreSQL or Redshift plan node's total cost and row estimate
+var planNodeEstimate = regexp.MustCompile(`cost=[\d.]+\.\.([\d.]+) rows=(\d+)`)
+
+// cockroachRowCount matches the row estimate of a CockroachDB plan node
+var cockroachRowCount = regexp.MustCompile(`estimated row count: ([\d,]+)`)
+
+// NeedsCostCheck reports whether a query is a single SELECT without a row limit,
+// the kind that can scan a whole table by accident
+func NeedsCostCheck(query string) bool {
+	if !CanMaterializeQuery(query) {
+		return false
+	}
+	for _, w := range queryWords(query) {
+		if w == "LIMIT" || w == "FETCH" {
+			return false
+		}
+	}
+	return true
+}
+
+// AddQueryLimit appends a LIMIT clause to a single SELECT
+func AddQueryLimit(query string, limit int) string {
+	return fmt.Sprintf("%s LIMIT %d", strings.TrimRight(strings.TrimSpace(query), "; \t\n"), limit)
+}
+
+// EstimateFromPlan reads the planner's estimate from EXPLAIN output. Rows is the
+// largest row estimate of any plan node, or for MySQL the product of the rows
+// examined per table, since joins multiply them. Cost is the top node's total
+// cost where the database reports one.
+func EstimateFromPlan(driver string, columns []string, plan [][]string) models.QueryEstimateResult {
+	var est models.QueryEstimateResult
+	switch driver {
+	case "mysql", "mariadb":
+		index := -1
+		for i, col := range columns {
+			if strings.EqualFold(col, "rows") {
+				index = i
+			}
+		}
+		if index < 0 {
+			return est
+		}
+		est.Rows = 1
+		for _, row := range plan {
+			if index >= len(row) {
+				continue
+			}
+			if n, err := strconv.ParseFloat(row[index], 64); err == nil {
+				est.Rows *= max(n, 1)
+				est.Found = true
+			}
+		}
+		if !est.Found {
+			est.Rows = 0
+		}
+
+	case "cockroach":
+		for _, row := range plan {
+			for _, cell := range row {
+				if match := cockroachRowCount.FindStringSubmatch(cell); match != nil {
+					if n, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64); err == nil {
+						est.Rows = max(est.Rows, n)
+						est.Found = true
+					}
+				}
+			}
+		}
+
+	default:
+		for _, row := range plan {
+			for _, cell := range row {
+				match := planNodeEstimate.FindStringSubmatch(cell)
+				if match == nil {
+					continue
+				}
+				cost, _ := strconv.ParseFloat(match[1], 64)
+				rows, _ := strconv.ParseFloat(match[2], 64)
+				if !est.Found {
+					est.Cost, est.HasCost = cost, true
+				}
+				est.Rows = max(est.Rows, rows)
+				est.Found = true
+			}
+		}
+	}
+	return est
+}
+
+// CostWarning describes an estimate over rowLimit rows or costLimit planner cost,
+// or returns "" when the query may run without asking. A limit of 0 is off.
+func CostWarning(est models.QueryEstimateResult, rowLimit, costLimit float64) string {
+	overRows := rowLimit > 0 && est.Rows > rowLimit
+	overCost := costLimit > 0 && est.HasCost && est.Cost > costLimit
+	if !est.Found || (!overRows && !overCost) {
+		return ""
+	}
+	unit := "rows"
+	if est.Rows == 1 {
+		unit = "row"
+	}
+	warning := fmt.Sprintf("Estimated to read %s %s", GroupDigits(fmt.Sprintf("%.0f", est.Rows)), unit)
+	if est.HasCost {
+		warning += fmt.Sprintf(" (planner cost %s)", GroupDigits(fmt.Sprintf("%.0f", est.Cost)))
+	}
+	return warning
+}
+
+// EstimateQueryCost explains a query before it runs so a large estimate can be confirmed
+func EstimateQueryCost(db *sql.DB, selectedDB models.DBType, query string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		plan, err := database.ExplainQuery(db, query)
+		if err != nil {
+			return models.QueryEstimateResult{Query: query, Err: err}
+		}
+		est := EstimateFromPlan(selectedDB.Driver, plan.Columns, plan.Rows)
+		est.Query = query
+		return est
+	})
+}
+
+// HandleQueryEstimateResult runs the query when its estimate is within the
+// limits, and otherwise asks whether to run it, add a LIMIT, or cancel. A plan
+// that cannot be read does not hold the query back; running it reports any error.
+func HandleQueryEstimateResult(m models.Model, msg models.QueryEstimateResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsCheckingCost = false
+
+	rowLimit, costLimit := config.ExplainLimits()
+	if msg.Err == nil {
+		if warning := CostWarning(msg, rowLimit, costLimit); warning != "" {
+			updatedModel.IsConfirmingCost = true
+			updatedModel.CostWarning = warning
+			updatedModel.CostCheckQuery = msg.Query
+			return updatedModel, nil
+		}
+	}
+	return RunCheckedQuery(updatedModel, msg.Query)
+}
+
+// RunCheckedQuery executes a query that has passed the cost check, on the
+// read replica when it is a single SELECT and one is connected
+func RunCheckedQuery(m models.Model, query string) (models.Model, tea.Cmd) {
+	updatedModel, db := RouteQuery(m, query)
+	updatedModel, running := StartRunningQuery(updatedModel)
+	updatedModel.IsExecutingQuery = true
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	return updatedModel, ExecuteQuery(db, m.SelectedDB, m.ConnectionStr, m.MigrationFile, query, m.ReadOnly, m.BindValues, running)
+}
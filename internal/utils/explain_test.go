@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNeedsCostCheck(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT * FROM orders", true},
+		{"select * from orders where status = 'open';", true},
+		{"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent", true},
+		{"SELECT * FROM orders LIMIT 10", false},
+		{"SELECT * FROM orders FETCH FIRST 10 ROWS ONLY", false},
+		{"SELECT * FROM orders WHERE note = 'no limit'", true},
+		{"UPDATE orders SET status = 'closed'", false},
+		{"SELECT 1; SELECT 2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := NeedsCostCheck(tt.query); got != tt.want {
+				t.Errorf("NeedsCostCheck(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddQueryLimit(t *testing.T) {
+	if got, want := AddQueryLimit("SELECT * FROM orders; ", 100), "SELECT * FROM orders LIMIT 100"; got != want {
+		t.Errorf("AddQueryLimit() = %q, want %q", got, want)
+	}
+}
+
+func TestEstimateFromPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		columns  []string
+		plan     [][]string
+		wantRows float64
+		wantCost float64
+		hasCost  bool
+		found    bool
+	}{
+		{
+			name:    "postgres uses top cost and largest rows",
+			driver:  "postgres",
+			columns: []string{"QUERY PLAN"},
+			plan: [][]string{
+				{"Aggregate  (cost=18334.00..18334.01 rows=1 width=8)"},
+				{"  ->  Seq Scan on orders  (cost=0.00..15834.00 rows=1000000 width=0)"},
+			},
+			wantRows: 1000000, wantCost: 18334.01, hasCost: true, found: true,
+		},
+		{
+			name:     "redshift",
+			driver:   "redshift",
+			columns:  []string{"QUERY PLAN"},
+			plan:     [][]string{{"XN Seq Scan on events  (cost=0.00..2500.00 rows=250000 width=12)"}},
+			wantRows: 250000, wantCost: 2500, hasCost: true, found: true,
+		},
+		{
+			name:    "mysql multiplies joined tables",
+			driver:  "mysql",
+			columns: []string{"id", "select_type", "table", "type", "rows", "Extra"},
+			plan: [][]string{
+				{"1", "SIMPLE", "o", "ALL", "5000", ""},
+				{"1", "SIMPLE", "c", "eq_ref", "1", ""},
+				{"1", "SIMPLE", "l", "ref", "20", ""},
+			},
+			wantRows: 100000, found: true,
+		},
+		{
+			name:    "mysql without tables",
+			driver:  "mariadb",
+			columns: []string{"id", "rows", "Extra"},
+			plan:    [][]string{{"1", "NULL", "No tables used"}},
+		},
+		{
+			name:    "cockroach",
+			driver:  "cockroach",
+			columns: []string{"info"},
+			plan: [][]string{
+				{"distribution: full"},
+				{"• scan"},
+				{"  estimated row count: 1,250,000 (100% of the table; stats collected 2 hours ago)"},
+			},
+			wantRows: 1250000, found: true,
+		},
+		{
+			name:    "unreadable plan",
+			driver:  "postgres",
+			columns: []string{"QUERY PLAN"},
+			plan:    [][]string{{"Result"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateFromPlan(tt.driver, tt.columns, tt.plan)
+			if got.Rows != tt.wantRows || got.Cost != tt.wantCost || got.HasCost != tt.hasCost || got.Found != tt.found {
+				t.Errorf("EstimateFromPlan() = %+v, want rows %v cost %v hasCost %v found %v", got, tt.wantRows, tt.wantCost, tt.hasCost, tt.found)
+			}
+		})
+	}
+}
+
+func TestCostWarning(t *testing.T) {
+	tests := []struct {
+		name      string
+		est       models.QueryEstimateResult
+		rowLimit  float64
+		costLimit float64
+		want      string
+	}{
+		{"under both limits", models.QueryEstimateResult{Rows: 500, Cost: 20, HasCost: true, Found: true}, 100000, 100000, ""},
+		{"over rows", models.QueryEstimateResult{Rows: 2500000, Found: true}, 100000, 100000, "Estimated to read 2,500,000 rows"},
+		{"over cost", models.QueryEstimateResult{Rows: 1, Cost: 250000, HasCost: true, Found: true}, 100000, 100000, "Estimated to read 1 row (planner cost 250,000)"},
+		{"row limit off", models.QueryEstimateResult{Rows: 2500000, Found: true}, 0, 100000, ""},
+		{"no estimate", models.QueryEstimateResult{}, 100000, 100000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CostWarning(tt.est, tt.rowLimit, tt.costLimit); got != tt.want {
+				t.Errorf("CostWarning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
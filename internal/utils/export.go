@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ExportQueryResult writes the shown query result to a timestamped csv or json
+// file in the working directory. nulls marks the cells that were SQL NULL.
+func ExportQueryResult(columns []string, rows [][]string, nulls [][]bool, format string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		filename := config.GenerateExportFilename("", format)
+		var err error
+		if format == "json" {
+			err = config.ExportToJSON(columns, rows, nulls, filename)
+		} else {
+			err = config.ExportToCSV(columns, rows, nulls, filename)
+		}
+		if err != nil {
+			return models.ExportResult{Err: err, Filename: filename, Format: format}
+		}
+		return models.ExportResult{Success: true, Filename: filename, Format: format, Rows: len(rows)}
+	})
+}
+
+// HandleExportResult reports an export and runs the on_export_complete hook
+// for one that succeeded
+func HandleExportResult(m models.Model, msg models.ExportResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsExporting = false
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, fmt.Errorf("export failed: %w", msg.Err), 5*time.Second)
+	}
+	updatedModel.Err = nil
+	updatedModel.QueryResult = fmt.Sprintf("📤 Exported %d rows to %s", msg.Rows, msg.Filename)
+
+	event := NewHookEvent(updatedModel, HookOnExportComplete)
+	event.Filename = msg.Filename
+	event.Format = msg.Format
+	event.Rows = msg.Rows
+	return updatedModel, tea.Batch(ClearResultAfterTimeout(), RunHook(event))
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestExportQueryResult(t *testing.T) {
+	columns := []string{"id", "name, full", `say "hi"`}
+	rows := [][]string{
+		{"1", "Ana", "plain"},
+		{"2", "Smith, Bo", `she said "no"`},
+		{"3", "line one\nline two", ""},
+		{"4", "NULL", "NULL"},
+	}
+	nulls := [][]bool{
+		{false, false, false},
+		{false, false, false},
+		{false, false, false},
+		{false, false, true},
+	}
+
+	tests := []struct {
+		name    string
+		columns []string
+		rows    [][]string
+		nulls   [][]bool
+		format  string
+		want    string
+	}{
+		{
+			name: "csv", columns: columns, rows: rows, nulls: nulls, format: "csv",
+			want: "id,\"name, full\",\"say \"\"hi\"\"\"\n" +
+				"1,Ana,plain\n" +
+				"2,\"Smith, Bo\",\"she said \"\"no\"\"\"\n" +
+				"3,\"line one\nline two\",\"\"\n" +
+				"4,NULL,\n",
+		},
+		{
+			name: "csv without rows", columns: columns, format: "csv",
+			want: "id,\"name, full\",\"say \"\"hi\"\"\"\n",
+		},
+		{
+			name: "json", columns: columns, rows: rows[2:], nulls: nulls[2:], format: "json",
+			want: `[
+  {
+    "id": "3",
+    "name, full": "line one\nline two",
+    "say \"hi\"": ""
+  },
+  {
+    "id": "4",
+    "name, full": "NULL",
+    "say \"hi\"": null
+  }
+]`,
+		},
+		{
+			name: "json without rows", columns: columns, format: "json",
+			want: "[]",
+		},
+		{
+			name: "json short row", columns: []string{"a", "b"}, rows: [][]string{{"x"}}, format: "json",
+			want: `[
+  {
+    "a": "x",
+    "b": ""
+  }
+]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir()) // Exports are written to the working directory
+			msg := ExportQueryResult(tt.columns, tt.rows, tt.nulls, tt.format)().(models.ExportResult)
+			if !msg.Success || msg.Err != nil {
+				t.Fatalf("ExportQueryResult() = %+v, want success", msg)
+			}
+			if msg.Rows != len(tt.rows) || msg.Format != tt.format || !strings.HasSuffix(msg.Filename, "."+tt.format) {
+				t.Errorf("ExportQueryResult() = %+v, want %d rows in a %s file", msg, len(tt.rows), tt.format)
+			}
+			data, err := os.ReadFile(msg.Filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(data); got != tt.want {
+				t.Errorf("ExportQueryResult() wrote\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleExportResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No hooks.json, so the hook does nothing
+
+	tests := []struct {
+		name       string
+		msg        models.ExportResult
+		wantResult string
+		wantErr    string
+	}{
+		{
+			name:       "success",
+			msg:        models.ExportResult{Success: true, Filename: "query_result.csv", Format: "csv", Rows: 3},
+			wantResult: "📤 Exported 3 rows to query_result.csv",
+		},
+		{
+			name:    "failure",
+			msg:     models.ExportResult{Filename: "query_result.json", Format: "json", Err: errors.New("disk full")},
+			wantErr: "export failed: disk full",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.Model{IsExporting: true, SelectedDB: models.DBType{Driver: "sqlite3"}}
+			got, cmd := HandleExportResult(m, tt.msg)
+			if got.IsExporting {
+				t.Error("HandleExportResult() left the export running")
+			}
+			var gotErr string
+			if got.Err != nil {
+				gotErr = got.Err.Error()
+			}
+			if gotErr != tt.wantErr || got.QueryResult != tt.wantResult {
+				t.Errorf("HandleExportResult() = %q, error %q, want %q, error %q", got.QueryResult, gotErr, tt.wantResult, tt.wantErr)
+			}
+			if cmd == nil {
+				t.Error("HandleExportResult() returned no command")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// LoadFavoriteTables reads the starred tables of a connection and schema.
+// A favorites file that cannot be read simply yields no favorites.
+func LoadFavoriteTables(driver, connectionStr, schema string) map[string]bool {
+	tables, _ := config.LoadFavoriteTables(connectionStoreKey(driver, connectionStr, schema))
+	favorites := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		favorites[table] = true
+	}
+	return favorites
+}
+
+// FavoriteTableNames returns the starred table names in alphabetical order
+func FavoriteTableNames(favorites map[string]bool) []string {
+	names := make([]string, 0, len(favorites))
+	for name, starred := range favorites {
+		if starred {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ToggleFavoriteTable stars or unstars a table, saves the change, and rebuilds the
+// tables list with the cursor kept on that table. It reports whether the table is now starred.
+func ToggleFavoriteTable(m models.Model, table string) (models.Model, bool, error) {
+	favorites := make(map[string]bool, len(m.FavoriteTables)+1)
+	for name, starred := range m.FavoriteTables {
+		favorites[name] = starred
+	}
+	starred := !favorites[table]
+	if starred {
+		favorites[table] = true
+	} else {
+		delete(favorites, table)
+	}
+
+	key := connectionStoreKey(m.SelectedDB.Driver, m.ConnectionStr, m.SelectedSchema)
+	if err := config.SaveFavoriteTables(key, FavoriteTableNames(favorites)); err != nil {
+		return m, !starred, err
+	}
+
+	m.FavoriteTables = favorites
+	m.TablesList.SetItems(CreateTableListItems(m.TableInfos, favorites, m.TableNotes))
+	return SelectTableByName(m, table), starred, nil
+}
+
+// SelectTableByName moves the tables list cursor onto the named table
+func SelectTableByName(m models.Model, table string) models.Model {
+	for i, item := range m.TablesList.Items() {
+		if it, ok := item.(models.Item); ok && it.ItemTitle == table {
+			m.TablesList.Select(i)
+			break
+		}
+	}
+	return m
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFavoriteTableNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		favorites map[string]bool
+		want      []string
+	}{
+		{"sorted", map[string]bool{"users": true, "orders": true}, []string{"orders", "users"}},
+		{"skips unstarred", map[string]bool{"users": true, "orders": false}, []string{"users"}},
+		{"empty", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FavoriteTableNames(tt.favorites); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FavoriteTableNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// FilterFieldItems keeps the row detail fields whose name or value contains the
+// search text, ignoring case
+func FilterFieldItems(items []list.Item, search string) []list.Item {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return items
+	}
+
+	var filtered []list.Item
+	for _, item := range items {
+		field, ok := item.(models.FieldItem)
+		if !ok {
+			continue
+		}
+		for _, text := range []string{field.Name, field.Value, field.Display} {
+			if strings.Contains(strings.ToLower(text), search) {
+				filtered = append(filtered, item)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// RefreshRowDetailList rebuilds the row detail fields from the selected row and the field search
+func RefreshRowDetailList(m models.Model) models.Model {
+	updatedModel := m
+	items := UpdateRowDetailList(m.DataPreviewAllColumns, m.DataPreviewColumnTypes, m.SelectedRowData, m.SelectedRowNulls, m.DisplayTimezone)
+	updatedModel.RowDetailList.SetItems(FilterFieldItems(items, m.SearchInput.Value()))
+	return updatedModel
+}
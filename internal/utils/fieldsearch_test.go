@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFilterFieldItems(t *testing.T) {
+	items := []list.Item{
+		models.FieldItem{Name: "id", Value: "42"},
+		models.FieldItem{Name: "email", Value: "ada@example.com"},
+		models.FieldItem{Name: "created_at", Value: "2025-01-02T03:04:05Z", Display: "2025-01-02 04:04:05 CET"},
+		models.FieldItem{Name: "billing_email", Value: "NULL"},
+	}
+
+	tests := []struct {
+		name   string
+		search string
+		want   []string
+	}{
+		{"empty search keeps all", "", []string{"id", "email", "created_at", "billing_email"}},
+		{"name ignores case", "EMAIL", []string{"email", "billing_email"}},
+		{"matches value", "example", []string{"email"}},
+		{"matches display value", "cet", []string{"created_at"}},
+		{"no match", "missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, item := range FilterFieldItems(items, tt.search) {
+				names = append(names, item.(models.FieldItem).Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("FilterFieldItems(%q) = %v, want %v", tt.search, names, tt.want)
+			}
+		})
+	}
+}
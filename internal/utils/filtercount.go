@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// FilterCountDebounce is how long typing must pause before the filter is counted
+const FilterCountDebounce = 400 * time.Millisecond
+
+// filterCountTimeout keeps the live count from loading the server on large tables
+const filterCountTimeout = 2 * time.Second
+
+// ScheduleFilterCount restarts the debounce for the filter being typed. The
+// sequence number is bumped so ticks from earlier keystrokes are ignored.
+func ScheduleFilterCount(m models.Model) (models.Model, tea.Cmd) {
+	m.FilterCountSeq++
+	seq := m.FilterCountSeq
+	return m, tea.Tick(FilterCountDebounce, func(time.Time) tea.Msg {
+		return models.FilterCountTickMsg{Seq: seq}
+	})
+}
+
+// HandleFilterCountTick counts the filter once typing has paused on it
+func HandleFilterCountTick(m models.Model, msg models.FilterCountTickMsg) (models.Model, tea.Cmd) {
+	if msg.Seq != m.FilterCountSeq || !m.DataPreviewFilterActive || m.DB == nil {
+		return m, nil
+	}
+	filter := m.DataPreviewFilterInput.Value()
+	if filter == "" || filter == m.FilterCountValue {
+		m.IsCountingFilter = false
+		return m, nil
+	}
+	// Count where the preview reads, so the count matches the rows it shows
+	routed, db := RouteRead(m)
+	routed.IsCountingFilter = true
+	return routed, CountFilterMatches(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, filter, PreviewTableColumns(m), PreviewJSONColumns(m), msg.Seq)
+}
+
+// CountFilterMatches counts the rows a filter matches, bounded by a short timeout
+func CountFilterMatches(db *sql.DB, selectedDB models.DBType, tableName, schema, filter string, columns []string, jsonColumns []models.JSONColumn, seq int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), filterCountTimeout)
+		defer cancel()
+		count, err := database.GetTableRowCountWithFilterContext(ctx, db, selectedDB.Driver, tableName, schema, filter, columns, jsonColumns)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = context.DeadlineExceeded
+		}
+		return models.FilterCountResult{Seq: seq, Filter: filter, Count: count, Err: err}
+	})
+}
+
+// HandleFilterCountResult shows the latest count; counts for older keystrokes are dropped
+func HandleFilterCountResult(m models.Model, msg models.FilterCountResult) (models.Model, tea.Cmd) {
+	if msg.Seq != m.FilterCountSeq {
+		return m, nil
+	}
+	m.IsCountingFilter = false
+	m.FilterCountValue = msg.Filter
+	m.FilterCount = msg.Count
+	m.FilterCountErr = msg.Err
+	return m, nil
+}
+
+// ResetFilterCount forgets the live count when the filter prompt closes
+func ResetFilterCount(m models.Model) models.Model {
+	m.FilterCountSeq++
+	m.FilterCountValue = ""
+	m.FilterCount = 0
+	m.FilterCountErr = nil
+	m.IsCountingFilter = false
+	return m
+}
+
+// FilterMatchBadge describes the live count next to the filter prompt. It is
+// empty until the text typed so far has been counted.
+func FilterMatchBadge(filter, countedFilter string, count int, err error, counting bool) string {
+	switch {
+	case filter == "":
+		return ""
+	case counting:
+		return "counting…"
+	case filter != countedFilter:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("too slow to count within %s", FormatTimeout(filterCountTimeout))
+	case err != nil:
+		return "count failed"
+	default:
+		return fmt.Sprintf("matches ~%s", pluralRows(count))
+	}
+}
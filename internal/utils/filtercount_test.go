@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFilterMatchBadge(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		counted  string
+		count    int
+		err      error
+		counting bool
+		want     string
+	}{
+		{"empty filter", "", "", 0, nil, false, ""},
+		{"counting", "ali", "al", 0, nil, true, "counting…"},
+		{"not counted yet", "ali", "al", 12, nil, false, ""},
+		{"counted", "ali", "ali", 12, nil, false, "matches ~12 rows"},
+		{"single row", "alice", "alice", 1, nil, false, "matches ~1 row"},
+		{"timed out", "a", "a", 0, context.DeadlineExceeded, false, "too slow to count within 2s"},
+		{"failed", "a", "a", 0, errors.New("syntax error"), false, "count failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterMatchBadge(tt.filter, tt.counted, tt.count, tt.err, tt.counting); got != tt.want {
+				t.Errorf("FilterMatchBadge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleFilterCountTick(t *testing.T) {
+	// The replica has rows the primary lacks, so the count shows where it ran
+	dbs := map[string]string{
+		"primary": `INSERT INTO events VALUES (1, '{"kind": "signup"}')`,
+		"replica": `INSERT INTO events VALUES (1, '{"kind": "signup"}'), (2, '{"kind": "signup"}'), (3, '{"kind": "login"}')`,
+	}
+	m := models.Model{
+		SelectedDB:              models.DBType{Driver: "sqlite3"},
+		SelectedSchema:          "main",
+		SelectedTable:           "events",
+		DataPreviewAllColumns:   []string{"id", "payload", "kind"},
+		DataPreviewFilterActive: true,
+		DataPreviewFilterInput:  textinput.New(),
+		JSONColumns: map[string][]models.JSONColumn{
+			"main.events": {{Name: "kind", Column: "payload", Path: []models.JSONPathStep{{Key: "kind"}}}},
+		},
+	}
+	for name, insert := range dbs {
+		db, err := database.Open("sqlite3", filepath.Join(t.TempDir(), name+".db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec("CREATE TABLE events (id INTEGER, payload TEXT); " + insert); err != nil {
+			t.Fatal(err)
+		}
+		if name == "primary" {
+			m.DB = db
+		} else {
+			m.ReplicaDB = db
+		}
+	}
+
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{"kind = signup", 2},
+		{"login", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			m.DataPreviewFilterInput.SetValue(tt.filter)
+			routed, cmd := HandleFilterCountTick(m, models.FilterCountTickMsg{Seq: m.FilterCountSeq})
+			if cmd == nil || routed.LastEndpoint != EndpointReplica {
+				t.Fatalf("HandleFilterCountTick() endpoint = %q, want the count run on the replica", routed.LastEndpoint)
+			}
+			result := cmd().(models.FilterCountResult)
+			if result.Err != nil || result.Count != tt.want {
+				t.Errorf("count of %q = %d, %v, want %d", tt.filter, result.Count, result.Err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// GroupSummaryLimit is the number of most common values a group-by summary lists
+const GroupSummaryLimit = 100
+
+// GroupAggregates are the second aggregate's functions in the order f cycles through them
+var GroupAggregates = []string{"sum", "avg", "min", "max"}
+
+// shareBarWidth is the width of the bar drawn for each value's share of the rows
+const shareBarWidth = 10
+
+// shareBlocks draw the end of a share bar in eighths of a character
+var shareBlocks = []rune(" ▏▎▍▌▋▊▉█")
+
+// NumericColumns returns the names of the numeric columns among column
+// definitions of name and type
+func NumericColumns(definitions [][]string) []string {
+	var columns []string
+	for _, def := range definitions {
+		if len(def) >= 2 && IsNumericType(def[1]) {
+			columns = append(columns, def[0])
+		}
+	}
+	return columns
+}
+
+// NextAggregateColumn returns the column after current, or "" after the last
+// one, so cycling passes through no aggregate column
+func NextAggregateColumn(columns []string, current string) string {
+	if current == "" {
+		if len(columns) == 0 {
+			return ""
+		}
+		return columns[0]
+	}
+	for i, column := range columns {
+		if column == current && i+1 < len(columns) {
+			return columns[i+1]
+		}
+	}
+	return ""
+}
+
+// StartGroupSummary opens the group-by summary of a column
+func StartGroupSummary(m models.Model, column string) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.GroupColumn = column
+	updatedModel.GroupAggregateColumn = ""
+	if updatedModel.GroupAggregate == "" {
+		updatedModel.GroupAggregate = GroupAggregates[0]
+	}
+	updatedModel.GroupSummary = nil
+	updatedModel.GroupTotal = 0
+	updatedModel.GroupSummaryTable = table.New()
+	updatedModel = PushView(updatedModel, models.GroupSummaryView, TableParams(updatedModel))
+	return ReloadGroupSummary(updatedModel)
+}
+
+// ReloadGroupSummary loads the summary for its current columns and aggregate
+func ReloadGroupSummary(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel, db := RouteRead(m)
+	updatedModel.IsLoadingGroups = true
+	updatedModel.Err = nil
+	return updatedModel, LoadGroupSummary(db, m.SelectedDB, m.SelectedSchema, m.SelectedTable,
+		m.GroupColumn, m.GroupAggregateColumn, m.GroupAggregate)
+}
+
+// LoadGroupSummary counts the rows of each value of a column
+func LoadGroupSummary(db *sql.DB, selectedDB models.DBType, schema, table, column, aggregateColumn, aggregate string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		groups, total, err := database.GetGroupSummary(db, selectedDB.Driver, schema, table, column, aggregateColumn, aggregate, GroupSummaryLimit)
+		return models.GroupSummaryResult{Groups: groups, Total: total, Err: err}
+	})
+}
+
+// HandleGroupSummaryResult shows the loaded summary in a table
+func HandleGroupSummaryResult(m models.Model, msg models.GroupSummaryResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingGroups = false
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	columns := []table.Column{
+		{Title: m.GroupColumn, Width: 30},
+		{Title: "Rows", Width: 14},
+		{Title: "Share", Width: shareBarWidth + 8},
+	}
+	if m.GroupAggregateColumn != "" {
+		columns = append(columns, table.Column{Title: fmt.Sprintf("%s(%s)", m.GroupAggregate, m.GroupAggregateColumn), Width: 20})
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+
+	updatedModel.GroupSummary = msg.Groups
+	updatedModel.GroupTotal = msg.Total
+	updatedModel.GroupSummaryTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildGroupSummaryRows(msg.Groups, msg.Total, m.GroupAggregateColumn != "")),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.GroupSummaryTable.SetStyles(styles.GetBlueTableStyles())
+	return updatedModel, nil
+}
+
+// BuildGroupSummaryRows renders each value's row count and share of total rows.
+// Rows of values beyond the listed ones are summed into a final row.
+func BuildGroupSummaryRows(groups []models.GroupCount, total int64, withAggregate bool) []table.Row {
+	rows := make([]table.Row, 0, len(groups)+1)
+	var listed int64
+	for _, g := range groups {
+		value := g.Value
+		if g.IsNull {
+			value = "NULL"
+		}
+		row := table.Row{value, GroupDigits(strconv.FormatInt(g.Count, 10)), FormatShare(g.Count, total)}
+		if withAggregate {
+			row = append(row, formatAggregate(g.Aggregate))
+		}
+		rows = append(rows, row)
+		listed += g.Count
+	}
+	if other := total - listed; other > 0 && len(groups) > 0 {
+		row := table.Row{"(other values)", GroupDigits(strconv.FormatInt(other, 10)), FormatShare(other, total)}
+		if withAggregate {
+			row = append(row, "")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// FormatShare renders count as a bar and percentage of total, e.g. "█████▌      55.0%"
+func FormatShare(count, total int64) string {
+	fraction := 0.0
+	if total > 0 {
+		fraction = float64(count) / float64(total)
+	}
+	eighths := int(math.Round(fraction * shareBarWidth * 8))
+	if eighths == 0 && count > 0 {
+		eighths = 1 // Rare values still show a sliver
+	}
+	bar := strings.Repeat("█", eighths/8)
+	if eighths%8 > 0 {
+		bar += string(shareBlocks[eighths%8])
+	}
+	return fmt.Sprintf("%-*s %5.1f%%", shareBarWidth, bar, fraction*100)
+}
+
+// formatAggregate groups the digits of a numeric aggregate and rounds it to two
+// decimals. Plain decimals are rounded as text so large sums stay exact.
+func formatAggregate(value string) string {
+	if rounded, ok := RoundDecimal(value, 2); ok {
+		return GroupDigits(rounded)
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return FormatChartValue(f)
+	}
+	return value
+}
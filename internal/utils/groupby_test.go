@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNextAggregateColumn(t *testing.T) {
+	columns := []string{"amount", "qty"}
+	tests := []struct {
+		columns []string
+		current string
+		want    string
+	}{
+		{columns, "", "amount"},
+		{columns, "amount", "qty"},
+		{columns, "qty", ""},
+		{columns, "dropped", ""},
+		{nil, "", ""},
+	}
+	for _, tt := range tests {
+		if got := NextAggregateColumn(tt.columns, tt.current); got != tt.want {
+			t.Errorf("NextAggregateColumn(%v, %q) = %q, want %q", tt.columns, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestNumericColumns(t *testing.T) {
+	definitions := [][]string{{"id", "bigint"}, {"status", "varchar(20)"}, {"total", "numeric(10,2)"}, {"paid", "boolean"}, {"x"}}
+	if got, want := NumericColumns(definitions), []string{"id", "total"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NumericColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatShare(t *testing.T) {
+	tests := []struct {
+		count, total int64
+		want         string
+	}{
+		{0, 0, "             0.0%"},
+		{1, 1, "██████████ 100.0%"},
+		{55, 100, "█████▌      55.0%"},
+		{1, 1000, "▏            0.1%"},
+		{0, 10, "             0.0%"},
+	}
+	for _, tt := range tests {
+		if got := FormatShare(tt.count, tt.total); got != tt.want {
+			t.Errorf("FormatShare(%d, %d) = %q, want %q", tt.count, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestBuildGroupSummaryRows(t *testing.T) {
+	groups := []models.GroupCount{
+		{Value: "paid", Count: 6000, Aggregate: "1234.5678"},
+		{IsNull: true, Count: 1000, Aggregate: "NULL"},
+		{Value: "wired", Count: 500, Aggregate: "98765432109876543210.129"},
+	}
+
+	got := BuildGroupSummaryRows(groups, 10000, true)
+	want := []table.Row{
+		{"paid", "6,000", FormatShare(6000, 10000), "1,234.57"},
+		{"NULL", "1,000", FormatShare(1000, 10000), "NULL"},
+		{"wired", "500", FormatShare(500, 10000), "98,765,432,109,876,543,210.13"},
+		{"(other values)", "2,500", FormatShare(2500, 10000), ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildGroupSummaryRows() = %v, want %v", got, want)
+	}
+
+	all := BuildGroupSummaryRows(groups, 7500, false)
+	if len(all) != 3 || len(all[0]) != 3 {
+		t.Errorf("BuildGroupSummaryRows() without other values or aggregate = %v", all)
+	}
+}
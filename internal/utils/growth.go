@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// SnapshotInterval is the minimum time between two stored table snapshots
+const SnapshotInterval = time.Hour
+
+// FormatSignedCount renders a delta with an explicit sign and a short suffix (e.g. "+120k")
+func FormatSignedCount(n int64) string {
+	if n == 0 {
+		return "0"
+	}
+	sign := "+"
+	abs := n
+	if n < 0 {
+		sign = "-"
+		abs = -n
+	}
+	switch {
+	case abs >= 1_000_000_000:
+		return fmt.Sprintf("%s%.1fB", sign, float64(abs)/1e9)
+	case abs >= 1_000_000:
+		return fmt.Sprintf("%s%.1fM", sign, float64(abs)/1e6)
+	case abs >= 10_000:
+		return fmt.Sprintf("%s%dk", sign, abs/1000)
+	default:
+		return fmt.Sprintf("%s%d", sign, abs)
+	}
+}
+
+// FormatSignedBytes renders a byte delta with an explicit sign (e.g. "+1.5 MB")
+func FormatSignedBytes(n int64) string {
+	if n == 0 {
+		return "0"
+	}
+	if n < 0 {
+		return "-" + FormatBytes(-n)
+	}
+	return "+" + FormatBytes(n)
+}
+
+// NewTableSnapshot converts table sizes into a snapshot taken at the given time
+func NewTableSnapshot(tables []models.TableSize, at time.Time) models.TableSnapshot {
+	snapshot := models.TableSnapshot{Timestamp: at, Tables: make([]models.SnapshotTable, len(tables))}
+	for i, t := range tables {
+		snapshot.Tables[i] = models.SnapshotTable{Name: t.Name, RowCount: t.RowCount, Bytes: t.TotalBytes()}
+	}
+	return snapshot
+}
+
+// ComputeTableGrowth compares current table sizes against a baseline snapshot.
+// Results are ordered by the largest absolute row change first.
+func ComputeTableGrowth(current []models.TableSize, baseline models.TableSnapshot) []models.TableGrowth {
+	previous := make(map[string]models.SnapshotTable, len(baseline.Tables))
+	for _, t := range baseline.Tables {
+		previous[t.Name] = t
+	}
+
+	growth := make([]models.TableGrowth, len(current))
+	for i, t := range current {
+		g := models.TableGrowth{Name: t.Name, RowCount: t.RowCount, Bytes: t.TotalBytes()}
+		if prev, ok := previous[t.Name]; ok {
+			g.RowDelta = t.RowCount - prev.RowCount
+			g.ByteDelta = g.Bytes - prev.Bytes
+		} else {
+			g.IsNew = true
+			g.RowDelta = t.RowCount
+			g.ByteDelta = g.Bytes
+		}
+		growth[i] = g
+	}
+
+	abs := func(n int64) int64 {
+		if n < 0 {
+			return -n
+		}
+		return n
+	}
+	sort.SliceStable(growth, func(i, j int) bool {
+		if abs(growth[i].RowDelta) != abs(growth[j].RowDelta) {
+			return abs(growth[i].RowDelta) > abs(growth[j].RowDelta)
+		}
+		return abs(growth[i].ByteDelta) > abs(growth[j].ByteDelta)
+	})
+	return growth
+}
+
+// recordTableSnapshot reads current table sizes and stores a snapshot when the
+// latest stored one is older than SnapshotInterval. It returns the current sizes
+// and the latest snapshot taken before this call, if any.
+func recordTableSnapshot(db *sql.DB, selectedDB models.DBType, connectionStr, schema string) ([]models.TableSize, *models.TableSnapshot, error) {
+	tables, err := database.GetTableSizes(db, selectedDB.Driver, schema, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := connectionStoreKey(selectedDB.Driver, connectionStr, schema)
+	snapshots, err := config.LoadTableSnapshots(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	var baseline *models.TableSnapshot
+	if len(snapshots) > 0 {
+		baseline = &snapshots[len(snapshots)-1]
+	}
+	if baseline == nil || now.Sub(baseline.Timestamp) >= SnapshotInterval {
+		if err := config.AppendTableSnapshot(key, NewTableSnapshot(tables, now)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tables, baseline, nil
+}
+
+// LoadTableGrowth loads table size changes since the last stored snapshot
+func LoadTableGrowth(db *sql.DB, selectedDB models.DBType, connectionStr, schema string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		tables, baseline, err := recordTableSnapshot(db, selectedDB, connectionStr, schema)
+		if err != nil {
+			return models.TableGrowthResult{Err: err}
+		}
+		if baseline == nil {
+			return models.TableGrowthResult{Growth: ComputeTableGrowth(tables, models.TableSnapshot{})}
+		}
+		return models.TableGrowthResult{
+			Growth:      ComputeTableGrowth(tables, *baseline),
+			Since:       baseline.Timestamp,
+			HasBaseline: true,
+		}
+	})
+}
+
+// ScheduleTableSnapshot schedules the next background snapshot for a connection session
+func ScheduleTableSnapshot(session int) tea.Cmd {
+	return tea.Tick(SnapshotInterval, func(time.Time) tea.Msg {
+		return models.TableSnapshotTickMsg{Session: session}
+	})
+}
+
+// TakeTableSnapshot records a snapshot in the background; failures are ignored
+// because snapshots are best-effort and must not interrupt browsing.
+func TakeTableSnapshot(db *sql.DB, selectedDB models.DBType, connectionStr, schema string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		recordTableSnapshot(db, selectedDB, connectionStr, schema)
+		return nil
+	})
+}
+
+// HandleTableSnapshotTick takes a snapshot for the active session and schedules the next one
+func HandleTableSnapshotTick(m models.Model, msg models.TableSnapshotTickMsg) (models.Model, tea.Cmd) {
+	// Ticks from earlier connections stop here so only one schedule is active
+	if m.DB == nil || msg.Session != m.SnapshotSession {
+		return m, nil
+	}
+	return m, tea.Batch(
+		TakeTableSnapshot(m.DB, m.SelectedDB, m.ConnectionStr, m.SelectedSchema),
+		ScheduleTableSnapshot(m.SnapshotSession),
+	)
+}
+
+// BuildTableGrowthRows converts table growth into table rows
+func BuildTableGrowthRows(growth []models.TableGrowth, hasBaseline bool) []table.Row {
+	rows := make([]table.Row, len(growth))
+	for i, g := range growth {
+		rowDelta, byteDelta := "-", "-"
+		if hasBaseline {
+			rowDelta = FormatSignedCount(g.RowDelta)
+			byteDelta = FormatSignedBytes(g.ByteDelta)
+			if g.IsNew {
+				rowDelta = "new"
+			}
+		}
+		rows[i] = table.Row{
+			g.Name,
+			fmt.Sprintf("%d", g.RowCount),
+			rowDelta,
+			FormatBytes(g.Bytes),
+			byteDelta,
+		}
+	}
+	return rows
+}
+
+// HandleTableGrowthResult processes table growth result and updates model
+func HandleTableGrowthResult(m models.Model, msg models.TableGrowthResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingGrowth = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	columns := []table.Column{
+		{Title: "Table", Width: 30},
+		{Title: "Rows", Width: 14},
+		{Title: "Δ Rows", Width: 10},
+		{Title: "Size", Width: 10},
+		{Title: "Δ Size", Width: 12},
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+
+	updatedModel.TableGrowth = msg.Growth
+	updatedModel.GrowthSince = msg.Since
+	updatedModel.HasGrowthBaseline = msg.HasBaseline
+	updatedModel.TableGrowthTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildTableGrowthRows(msg.Growth, msg.HasBaseline)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-14, 5)),
+	)
+	updatedModel.TableGrowthTable.SetStyles(styles.GetBlueTableStyles())
+	updatedModel = PushView(updatedModel, models.TableGrowthView, models.NavParams{})
+	return updatedModel, nil
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFormatSignedCount(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int64
+		want string
+	}{
+		{"zero", 0, "0"},
+		{"small positive", 42, "+42"},
+		{"small negative", -7, "-7"},
+		{"thousands", 120_500, "+120k"},
+		{"millions", -2_500_000, "-2.5M"},
+		{"billions", 3_000_000_000, "+3.0B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSignedCount(tt.n); got != tt.want {
+				t.Errorf("FormatSignedCount(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSignedBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int64
+		want string
+	}{
+		{"zero", 0, "0"},
+		{"growth", 1536, "+1.5 KB"},
+		{"shrink", -512, "-512 B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSignedBytes(tt.n); got != tt.want {
+				t.Errorf("FormatSignedBytes(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeTableGrowth(t *testing.T) {
+	baseline := models.TableSnapshot{
+		Timestamp: time.Now().Add(-24 * time.Hour),
+		Tables: []models.SnapshotTable{
+			{Name: "orders", RowCount: 1000, Bytes: 4096},
+			{Name: "users", RowCount: 50, Bytes: 1024},
+		},
+	}
+	current := []models.TableSize{
+		{Name: "users", RowCount: 45, DataBytes: 1024},
+		{Name: "orders", RowCount: 121000, DataBytes: 8192, IndexBytes: 1024},
+		{Name: "events", RowCount: 10, DataBytes: 512},
+	}
+
+	growth := ComputeTableGrowth(current, baseline)
+	if len(growth) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(growth))
+	}
+
+	if growth[0].Name != "orders" || growth[0].RowDelta != 120000 || growth[0].ByteDelta != 5120 {
+		t.Errorf("unexpected orders growth: %+v", growth[0])
+	}
+	if growth[1].Name != "events" || !growth[1].IsNew || growth[1].RowDelta != 10 {
+		t.Errorf("unexpected events growth: %+v", growth[1])
+	}
+	if growth[2].Name != "users" || growth[2].RowDelta != -5 || growth[2].IsNew {
+		t.Errorf("unexpected users growth: %+v", growth[2])
+	}
+}
+
+func TestBuildTableGrowthRows(t *testing.T) {
+	growth := []models.TableGrowth{
+		{Name: "orders", RowCount: 121000, RowDelta: 120000, Bytes: 2048, ByteDelta: 1024},
+		{Name: "events", RowCount: 10, RowDelta: 10, Bytes: 512, ByteDelta: 512, IsNew: true},
+	}
+
+	rows := BuildTableGrowthRows(growth, true)
+	if rows[0][2] != "+120k" || rows[0][4] != "+1.0 KB" {
+		t.Errorf("unexpected orders row: %v", rows[0])
+	}
+	if rows[1][2] != "new" {
+		t.Errorf("expected new table marker, got %q", rows[1][2])
+	}
+
+	rows = BuildTableGrowthRows(growth, false)
+	if rows[0][2] != "-" || rows[0][4] != "-" {
+		t.Errorf("expected no deltas without baseline, got %v", rows[0])
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// CheckConnectionHealth pings every saved connection concurrently. Each ping
+// has its own timeout, so one unreachable server does not hold up the board
+// for longer than that timeout.
+func CheckConnectionHealth(connections []models.SavedConnection) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		health := make([]models.ConnectionHealth, len(connections))
+		var wg sync.WaitGroup
+		for i, conn := range connections {
+			wg.Add(1)
+			go func(i int, conn models.SavedConnection) {
+				defer wg.Done()
+				start := time.Now()
+				result := database.TestConnectionWithTimeout(conn.Driver, conn.ConnectionStr, ConnectionTimeouts(conn).Connect)
+				health[i] = models.ConnectionHealth{
+					Name:    conn.Name,
+					Driver:  conn.Driver,
+					Latency: time.Since(start),
+					Err:     ExplainConnectionError(conn.Driver, conn.ConnectionStr, result.Err),
+				}
+			}(i, conn)
+		}
+		wg.Wait()
+		return models.ConnectionHealthResult{Health: health, CheckedAt: time.Now()}
+	})
+}
+
+// CountUnreachable returns how many connections failed their ping
+func CountUnreachable(health []models.ConnectionHealth) int {
+	failed := 0
+	for _, h := range health {
+		if h.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BuildConnectionHealthRows converts ping results into table rows
+func BuildConnectionHealthRows(health []models.ConnectionHealth) []table.Row {
+	rows := make([]table.Row, len(health))
+	for i, h := range health {
+		status, latency, detail := "✅ ok", formatStatementDuration(h.Latency), ""
+		if h.Err != nil {
+			status, latency, detail = "❌ error", "-", h.Err.Error()
+		}
+		rows[i] = table.Row{h.Name, h.Driver, status, latency, detail}
+	}
+	return rows
+}
+
+// HealthBadge sums up the last ping of a connection for the saved connections
+// list, e.g. "✅ 42ms" or "❌ failed"
+func HealthBadge(h models.ConnectionHealth) string {
+	if h.Err != nil {
+		return "❌ failed"
+	}
+	return "✅ " + formatStatementDuration(h.Latency)
+}
+
+// AnnotateConnectionHealth starts the description of each connection item
+// with its health badge from the last check; items of connections that were
+// not checked are left as they are
+func AnnotateConnectionHealth(items []list.Item, health []models.ConnectionHealth) []list.Item {
+	if len(health) == 0 {
+		return items
+	}
+	byName := make(map[string]models.ConnectionHealth, len(health))
+	for _, h := range health {
+		byName[h.Name] = h
+	}
+	annotated := make([]list.Item, len(items))
+	for i, item := range items {
+		if conn, ok := item.(models.Item); ok {
+			if h, checked := byName[conn.ItemTitle]; checked {
+				conn.ItemDesc = HealthBadge(h) + " • " + conn.ItemDesc
+				item = conn
+			}
+		}
+		annotated[i] = item
+	}
+	return annotated
+}
+
+// HandleConnectionHealthResult processes the ping results and updates model
+func HandleConnectionHealthResult(m models.Model, msg models.ConnectionHealthResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsCheckingHealth = false
+
+	columns := []table.Column{
+		{Title: "Connection", Width: 24},
+		{Title: "Driver", Width: 10},
+		{Title: "Status", Width: 10},
+		{Title: "Latency", Width: 10},
+		{Title: "Error", Width: Max(m.Width-70, 30)},
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+
+	updatedModel.ConnectionHealth = msg.Health
+	updatedModel.HealthCheckedAt = msg.CheckedAt
+	updatedModel = UpdateSavedConnectionsList(updatedModel)
+	updatedModel.ConnectionHealthTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildConnectionHealthRows(msg.Health)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.ConnectionHealthTable.SetStyles(styles.GetBlueTableStyles())
+	updatedModel = PushView(updatedModel, models.ConnectionHealthView, models.NavParams{})
+	return updatedModel, nil
+}
+
+// HealthSummary describes the board in one line, e.g. "3 of 4 reachable"
+func HealthSummary(health []models.ConnectionHealth) string {
+	return fmt.Sprintf("%d of %d reachable", len(health)-CountUnreachable(health), len(health))
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestBuildConnectionHealthRows(t *testing.T) {
+	health := []models.ConnectionHealth{
+		{Name: "prod", Driver: "postgres", Latency: 42 * time.Millisecond},
+		{Name: "staging", Driver: "mysql", Latency: 10 * time.Second, Err: errors.New("connection refused")},
+	}
+
+	want := []table.Row{
+		{"prod", "postgres", "✅ ok", "42ms", ""},
+		{"staging", "mysql", "❌ error", "-", "connection refused"},
+	}
+	if got := BuildConnectionHealthRows(health); !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildConnectionHealthRows() = %v, want %v", got, want)
+	}
+	if got := HealthSummary(health); got != "1 of 2 reachable" {
+		t.Errorf("HealthSummary() = %q, want %q", got, "1 of 2 reachable")
+	}
+}
+
+func TestAnnotateConnectionHealth(t *testing.T) {
+	items := []list.Item{
+		models.GroupItem{Name: "prod", Count: 2},
+		models.Item{ItemTitle: "prod", ItemDesc: "postgres - db/prod"},
+		models.Item{ItemTitle: "replica", ItemDesc: "postgres - db/replica"},
+		models.Item{ItemTitle: "new", ItemDesc: "sqlite3 - app.db"},
+	}
+	health := []models.ConnectionHealth{
+		{Name: "prod", Latency: 42 * time.Millisecond},
+		{Name: "replica", Latency: 5 * time.Second, Err: errors.New("password authentication failed")},
+	}
+
+	want := []list.Item{
+		models.GroupItem{Name: "prod", Count: 2},
+		models.Item{ItemTitle: "prod", ItemDesc: "✅ 42ms • postgres - db/prod"},
+		models.Item{ItemTitle: "replica", ItemDesc: "❌ failed • postgres - db/replica"},
+		models.Item{ItemTitle: "new", ItemDesc: "sqlite3 - app.db"},
+	}
+	if got := AnnotateConnectionHealth(items, health); !reflect.DeepEqual(got, want) {
+		t.Errorf("AnnotateConnectionHealth() = %v, want %v", got, want)
+	}
+	if got := AnnotateConnectionHealth(items, nil); !reflect.DeepEqual(got, items) {
+		t.Errorf("AnnotateConnectionHealth() without a check = %v, want the items unchanged", got)
+	}
+}
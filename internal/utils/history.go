@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MaxQueryHistory caps how many queries are kept in the history file
+const MaxQueryHistory = 200
+
+// QueryHistoryItems creates the history list items, newest first as stored
+func QueryHistoryItems(history []models.QueryHistoryEntry) []list.Item {
+	items := make([]list.Item, len(history))
+	for i, entry := range history {
+		// Create description with timestamp, success status and row count
+		desc := fmt.Sprintf("%s • %s", entry.Timestamp.Format("2006-01-02 15:04:05"), entry.Database)
+		if entry.Success && entry.RowCount > 0 {
+			desc += fmt.Sprintf(" • %d rows", entry.RowCount)
+		} else if !entry.Success {
+			desc += " • Failed"
+		}
+		// Multi-line queries are listed on one line
+		items[i] = models.Item{ItemTitle: strings.Join(strings.Fields(entry.Query), " "), ItemDesc: desc}
+	}
+	return items
+}
+
+// AddQueryHistoryEntry puts entry first. Running the newest query again only
+// refreshes it, so repeated runs do not crowd out older queries.
+func AddQueryHistoryEntry(history []models.QueryHistoryEntry, entry models.QueryHistoryEntry) []models.QueryHistoryEntry {
+	if len(history) > 0 && history[0].Query == entry.Query {
+		history = history[1:]
+	}
+	updated := append([]models.QueryHistoryEntry{entry}, history...)
+	if len(updated) > MaxQueryHistory {
+		updated = updated[:MaxQueryHistory]
+	}
+	return updated
+}
+
+// RecordQueryHistory adds an executed query to the history and saves it.
+// Saving is best-effort; a failure must not hide the query's result.
+func RecordQueryHistory(m models.Model, msg models.QueryResultMsg) models.Model {
+	if msg.Query == "" {
+		return m
+	}
+	m.QueryHistory = AddQueryHistoryEntry(m.QueryHistory, models.QueryHistoryEntry{
+		Query:     msg.Query,
+		Timestamp: time.Now(),
+		Database:  m.SelectedDB.Name,
+		Success:   msg.Err == nil,
+		RowCount:  len(msg.Rows),
+	})
+	config.SaveQueryHistory(m.QueryHistory)
+	m.QueryHistoryList.SetItems(QueryHistoryItems(m.QueryHistory))
+	m.QueryRecallDepth = 0
+	return m
+}
+
+// NextRecallDepth moves through history like a shell: a positive delta goes to
+// older entries, a negative one to newer. Depth n recalls the nth newest entry
+// and depth 0 is the unsent draft.
+func NextRecallDepth(current, delta, length int) int {
+	return Max(0, Min(current+delta, length))
+}
+
+// RecallQueryHistory replaces the editor text with an older (delta 1) or newer
+// (delta -1) history entry. The text being typed is kept and comes back when
+// moving past the newest entry.
+func RecallQueryHistory(m models.Model, delta int) models.Model {
+	depth := NextRecallDepth(m.QueryRecallDepth, delta, len(m.QueryHistory))
+	if depth == m.QueryRecallDepth {
+		return m
+	}
+	if m.QueryRecallDepth == 0 {
+		m.QueryRecallDraft = m.QueryInput.Value()
+	}
+	m.QueryRecallDepth = depth
+	if depth == 0 {
+		m.QueryInput.SetValue(m.QueryRecallDraft)
+	} else {
+		m.QueryInput.SetValue(m.QueryHistory[depth-1].Query)
+	}
+	m.QueryInput.CursorEnd()
+	return m
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNextRecallDepth(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		delta   int
+		length  int
+		want    int
+	}{
+		{"first older entry", 0, 1, 3, 1},
+		{"stops at oldest", 3, 1, 3, 3},
+		{"back to draft", 1, -1, 3, 0},
+		{"stays on draft", 0, -1, 3, 0},
+		{"empty history", 0, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextRecallDepth(tt.current, tt.delta, tt.length); got != tt.want {
+				t.Errorf("NextRecallDepth(%d, %d, %d) = %d, want %d", tt.current, tt.delta, tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddQueryHistoryEntry(t *testing.T) {
+	queries := func(history []models.QueryHistoryEntry) []string {
+		var out []string
+		for _, e := range history {
+			out = append(out, e.Query)
+		}
+		return out
+	}
+
+	history := []models.QueryHistoryEntry{{Query: "SELECT 2"}, {Query: "SELECT 1"}}
+	history = AddQueryHistoryEntry(history, models.QueryHistoryEntry{Query: "SELECT 3"})
+	if got, want := queries(history), []string{"SELECT 3", "SELECT 2", "SELECT 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after new query = %v, want %v", got, want)
+	}
+
+	history = AddQueryHistoryEntry(history, models.QueryHistoryEntry{Query: "SELECT 3"})
+	if got, want := queries(history), []string{"SELECT 3", "SELECT 2", "SELECT 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after repeated query = %v, want %v", got, want)
+	}
+
+	for i := 0; i < MaxQueryHistory; i++ {
+		history = AddQueryHistoryEntry(history, models.QueryHistoryEntry{Query: string(rune('a' + i%26))})
+	}
+	if len(history) != MaxQueryHistory {
+		t.Errorf("history length = %d, want %d", len(history), MaxQueryHistory)
+	}
+}
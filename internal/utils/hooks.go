@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Hook events, named as in the hooks file
+const (
+	HookOnConnect        = "on_connect"
+	HookOnQueryError     = "on_query_error"
+	HookOnExportComplete = "on_export_complete"
+)
+
+// hookTimeout bounds a hook command, so a stuck notifier does not pile up
+// processes
+const hookTimeout = 10 * time.Second
+
+// HookEvent is the JSON a hook command reads on stdin. It describes the
+// connection by driver, environment, and schema; connection strings are never
+// included.
+type HookEvent struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Driver      string    `json:"driver,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Schema      string    `json:"schema,omitempty"`
+	Tables      int       `json:"tables,omitempty"`
+	Query       string    `json:"query,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Filename    string    `json:"filename,omitempty"`
+	Format      string    `json:"format,omitempty"`
+	Rows        int       `json:"rows,omitempty"`
+}
+
+// NewHookEvent starts an event with the current connection's details
+func NewHookEvent(m models.Model, event string) HookEvent {
+	return HookEvent{
+		Event:       event,
+		Time:        time.Now(),
+		Driver:      m.SelectedDB.Driver,
+		Environment: m.Environment,
+		Schema:      m.SelectedSchema,
+	}
+}
+
+// HookCommand returns the command configured for an event
+func HookCommand(hooks config.Hooks, event string) string {
+	switch event {
+	case HookOnConnect:
+		return hooks.OnConnect
+	case HookOnQueryError:
+		return hooks.OnQueryError
+	case HookOnExportComplete:
+		return hooks.OnExportComplete
+	}
+	return ""
+}
+
+// RunHook runs the command configured for the event, if any, with the event
+// on stdin. The hooks file is read on every event, so edits apply without a
+// restart.
+func RunHook(event HookEvent) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		hooks, err := config.LoadHooks()
+		if err != nil {
+			return models.HookResult{Event: event.Event, Err: err}
+		}
+		command := strings.TrimSpace(HookCommand(hooks, event.Event))
+		if command == "" {
+			return nil
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return models.HookResult{Event: event.Event, Err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		defer cancel()
+		cmd := shellCommand(ctx, command)
+		cmd.Stdin = bytes.NewReader(payload)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("timed out after %s", hookTimeout)
+			}
+			if detail := firstLine(stderr.String()); detail != "" {
+				err = fmt.Errorf("%w: %s", err, detail)
+			}
+			return models.HookResult{Event: event.Event, Err: fmt.Errorf("%s hook failed: %w", event.Event, err)}
+		}
+		return models.HookResult{Event: event.Event}
+	})
+}
+
+// shellCommand runs a hook command through the platform shell, so hooks can
+// use pipes and arguments
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// firstLine returns the first non-empty line of a command's output
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// ConnectHook runs the on_connect hook for a new connection
+func ConnectHook(m models.Model) tea.Cmd {
+	event := NewHookEvent(m, HookOnConnect)
+	event.Tables = len(m.Tables)
+	return RunHook(event)
+}
+
+// QueryErrorHook runs the on_query_error hook when a query failed. For a
+// script it reports the statement that failed.
+func QueryErrorHook(m models.Model, msg models.QueryResultMsg) tea.Cmd {
+	if msg.Err == nil {
+		return nil
+	}
+	event := NewHookEvent(m, HookOnQueryError)
+	event.Query = msg.Query
+	event.Error = msg.Err.Error()
+	for _, st := range msg.Statements {
+		if !st.Skipped && st.Result.Err != nil {
+			event.Query = st.Query
+			break
+		}
+	}
+	return RunHook(event)
+}
+
+// HandleHookResult reports a hook that failed; a hook that ran says nothing
+func HandleHookResult(m models.Model, msg models.HookResult) (models.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return SetErrorWithTimeout(m, msg.Err, 5*time.Second)
+	}
+	return m, nil
+}
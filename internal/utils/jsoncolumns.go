@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// jsonColumnsKey identifies a table in the JSON path columns of the session
+func jsonColumnsKey(schema, table string) string {
+	return schema + "." + table
+}
+
+// PreviewJSONColumns returns the JSON path columns of the table being previewed
+func PreviewJSONColumns(m models.Model) []models.JSONColumn {
+	return m.JSONColumns[jsonColumnsKey(m.SelectedSchema, m.SelectedTable)]
+}
+
+// PreviewTableColumns returns the preview's columns without its JSON path
+// columns: the ones the table has, which filters, edits, and row lookups may name
+func PreviewTableColumns(m models.Model) []string {
+	jsonColumns := PreviewJSONColumns(m)
+	if len(jsonColumns) == 0 {
+		return m.DataPreviewAllColumns
+	}
+	var columns []string
+	for _, column := range m.DataPreviewAllColumns {
+		if !slices.ContainsFunc(jsonColumns, func(col models.JSONColumn) bool { return col.Name == column }) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// IsJSONColumn reports whether a preview column is a JSON path column
+func IsJSONColumn(m models.Model, column string) bool {
+	return slices.ContainsFunc(PreviewJSONColumns(m), func(col models.JSONColumn) bool { return col.Name == column })
+}
+
+// AddJSONColumn adds a JSON path column, written as ParseJSONColumn reads it, to
+// the table being previewed. A column of the same name is replaced.
+func AddJSONColumn(m models.Model, spec string) (models.Model, models.JSONColumn, error) {
+	if !models.DriverCapabilities(m.SelectedDB.Driver).JSONPaths {
+		return m, models.JSONColumn{}, fmt.Errorf("JSON path columns are not supported for %s", m.SelectedDB.Driver)
+	}
+	tableColumns := PreviewTableColumns(m)
+	col, err := database.ParseJSONColumn(spec, tableColumns)
+	if err != nil {
+		return m, models.JSONColumn{}, err
+	}
+	if _, err := database.JSONPathExpr(m.SelectedDB.Driver, col); err != nil {
+		return m, models.JSONColumn{}, err
+	}
+	for _, column := range tableColumns {
+		if strings.EqualFold(column, col.Name) {
+			return m, models.JSONColumn{}, fmt.Errorf("%q is already a column of %s: name the path with \"as name\"", col.Name, m.SelectedTable)
+		}
+	}
+
+	key := jsonColumnsKey(m.SelectedSchema, m.SelectedTable)
+	jsonColumns := slices.DeleteFunc(slices.Clone(m.JSONColumns[key]), func(existing models.JSONColumn) bool {
+		return existing.Name == col.Name
+	})
+	updatedModel := m
+	updatedModel.JSONColumns = cloneJSONColumns(m.JSONColumns)
+	updatedModel.JSONColumns[key] = append(jsonColumns, col)
+	return updatedModel, col, nil
+}
+
+// ClearJSONColumns removes the JSON path columns of the table being previewed,
+// along with the sort keys on them
+func ClearJSONColumns(m models.Model) models.Model {
+	updatedModel := m
+	var sortKeys []models.SortKey
+	for _, key := range PreviewSortKeys(m) {
+		if !IsJSONColumn(m, key.Column) {
+			sortKeys = append(sortKeys, key)
+		}
+	}
+	updatedModel = SetPreviewSortKeys(updatedModel, sortKeys)
+	updatedModel.JSONColumns = cloneJSONColumns(m.JSONColumns)
+	delete(updatedModel.JSONColumns, jsonColumnsKey(m.SelectedSchema, m.SelectedTable))
+	return updatedModel
+}
+
+// cloneJSONColumns copies the map so a model update never changes an earlier model
+func cloneJSONColumns(jsonColumns map[string][]models.JSONColumn) map[string][]models.JSONColumn {
+	cloned := make(map[string][]models.JSONColumn, len(jsonColumns)+1)
+	for key, cols := range jsonColumns {
+		cloned[key] = cols
+	}
+	return cloned
+}
+
+// ReloadPreviewColumns reloads the current page after the JSON path columns
+// changed. tableColumns are the table's own columns, read before the change.
+func ReloadPreviewColumns(m models.Model, tableColumns []string) (models.Model, tea.Cmd) {
+	routed, db := RouteRead(m)
+	return routed, LoadDataPreviewWithPagination(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, PreviewSortKeys(m), m.DataPreviewFilterValue, tableColumns, m.DataPreviewTotalRows, PreviewJSONColumns(m))
+}
+
+// JSONColumnsLabel lists the JSON path columns of the table being previewed for
+// the preview's header, e.g. "{} payload.status, payload.user.id"
+func JSONColumnsLabel(m models.Model) string {
+	jsonColumns := PreviewJSONColumns(m)
+	if len(jsonColumns) == 0 {
+		return ""
+	}
+	names := make([]string, len(jsonColumns))
+	for i, col := range jsonColumns {
+		names[i] = col.Name
+	}
+	return "{} " + strings.Join(names, ", ")
+}
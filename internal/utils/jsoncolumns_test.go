@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseJSONColumn(t *testing.T) {
+	columns := []string{"id", "Payload"}
+	tests := []struct {
+		spec     string
+		wantName string
+		wantPath string
+		wantErr  bool
+	}{
+		{"payload->>'status'", "Payload.status", "status", false},
+		{`"Payload"->'items'->0->>'id'`, "Payload.items.0.id", "items.0.id", false},
+		{"payload->'it''s'", "Payload.it's", "it's", false},
+		{"payload.user.id as user_id", "user_id", "user.id", false},
+		{"payload.tags.1 AS \"first tag\"", "first tag", "tags.1", false},
+		{"payload", "", "", true},
+		{"payload->>'status", "", "", true},
+		{"payload->x", "", "", true},
+		{"payload..id", "", "", true},
+		{"body->>'status'", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := database.ParseJSONColumn(tt.spec, columns)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseJSONColumn(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.Name != tt.wantName || got.Column != "Payload" || database.FormatJSONPath(got.Path) != tt.wantPath {
+			t.Errorf("ParseJSONColumn(%q) = %+v, want name %q path %q", tt.spec, got, tt.wantName, tt.wantPath)
+		}
+	}
+}
+
+func TestJSONPathExpr(t *testing.T) {
+	col := models.JSONColumn{Name: "x", Column: "payload", Path: []models.JSONPathStep{
+		{Key: "user's"}, {Index: 0, Array: true}, {Key: `a"b`},
+	}}
+	tests := []struct {
+		driver  string
+		want    string
+		wantErr bool
+	}{
+		{"postgres", `("payload"::JSONB #>> '{"user''s",0,"a\"b"}')`, false},
+		{"mysql", "JSON_UNQUOTE(JSON_EXTRACT(`payload`, '$.\"user''s\"[0].\"a\\\\\"b\"'))", false},
+		{"sqlite3", `json_extract("payload", '$."user''s"[0]."a\"b"')`, false},
+		{"redshift", "", true},
+		{"clickhouse", "", true},
+	}
+	for _, tt := range tests {
+		got, err := database.JSONPathExpr(tt.driver, col)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("JSONPathExpr(%s) = %q, %v; want %q", tt.driver, got, err, tt.want)
+		}
+	}
+}
+
+func TestAddJSONColumn(t *testing.T) {
+	m := models.Model{
+		SelectedDB:            models.DBType{Driver: "postgres"},
+		SelectedSchema:        "public",
+		SelectedTable:         "events",
+		DataPreviewAllColumns: []string{"id", "payload", "status"},
+	}
+
+	added, col, err := AddJSONColumn(m, "payload->>'kind'")
+	if err != nil || col.Name != "payload.kind" {
+		t.Fatalf("AddJSONColumn = %+v, %v", col, err)
+	}
+	if len(PreviewJSONColumns(m)) != 0 {
+		t.Error("AddJSONColumn changed the model it was given")
+	}
+	if _, _, err := AddJSONColumn(m, "payload->>'status'"); err != nil {
+		t.Errorf("a path named after its own key is allowed: %v", err)
+	}
+	if _, _, err := AddJSONColumn(m, "payload->>'status' as status"); err == nil {
+		t.Error("AddJSONColumn allowed a name the table already has")
+	}
+
+	// The reloaded preview lists the path column after the table's own
+	added.DataPreviewAllColumns = append(added.DataPreviewAllColumns, "payload.kind")
+	if got := PreviewTableColumns(added); !reflect.DeepEqual(got, []string{"id", "payload", "status"}) {
+		t.Errorf("PreviewTableColumns = %v", got)
+	}
+	if !IsJSONColumn(added, "payload.kind") || IsJSONColumn(added, "payload") {
+		t.Error("IsJSONColumn misreports the columns")
+	}
+
+	other := added
+	other.SelectedTable = "orders"
+	if len(PreviewJSONColumns(other)) != 0 {
+		t.Error("JSON path columns leaked to another table")
+	}
+
+	cleared := ClearJSONColumns(added)
+	if len(PreviewJSONColumns(cleared)) != 0 || len(PreviewJSONColumns(added)) != 1 {
+		t.Error("ClearJSONColumns did not clear only the new model")
+	}
+
+	clickhouse := m
+	clickhouse.SelectedDB.Driver = "clickhouse"
+	if _, _, err := AddJSONColumn(clickhouse, "payload.kind"); err == nil {
+		t.Error("AddJSONColumn allowed a driver without JSON paths")
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// SavedConnectionString returns the connection string of a saved connection, or
+// explains why its secret could not be read from the keychain
+func SavedConnectionString(conn models.SavedConnection) (string, error) {
+	if config.IsKeychainReference(conn.ConnectionStr) {
+		if !config.KeychainEnabled() {
+			return "", fmt.Errorf("the connection string of '%s' is in the OS keychain, which is not available", conn.Name)
+		}
+		return "", fmt.Errorf("the connection string of '%s' was not found in the OS keychain; save it again from the connection form", conn.Name)
+	}
+	return conn.ConnectionStr, nil
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestSavedConnectionString(t *testing.T) {
+	t.Setenv("MIRADOR_KEYCHAIN", "0")
+
+	tests := []struct {
+		name    string
+		conn    models.SavedConnection
+		want    string
+		wantErr bool
+	}{
+		{"plain connection string", models.SavedConnection{Name: "prod", ConnectionStr: "postgres://app@db/prod"}, "postgres://app@db/prod", false},
+		{"unresolved keychain reference", models.SavedConnection{Name: "prod", ConnectionStr: "keychain:prod"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SavedConnectionString(tt.conn)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("SavedConnectionString() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
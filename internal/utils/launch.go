@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// LaunchOptions is the connection named on the command line: either a driver
+// and connection string, or the name of a saved connection
+type LaunchOptions struct {
+	Driver     string
+	DSN        string
+	Connection string
+	ReadOnly   bool // Connect the connection string read-only
+}
+
+// ParseLaunchArgs reads "mirador --driver <driver> --dsn <string>" or
+// "mirador <saved connection>". No arguments is the zero LaunchOptions, which
+// starts at the database type list as before.
+func ParseLaunchArgs(args []string, out io.Writer) (LaunchOptions, error) {
+	var opts LaunchOptions
+	drivers := make([]string, len(models.SupportedDatabaseTypes))
+	for i, db := range models.SupportedDatabaseTypes {
+		drivers[i] = db.Driver
+	}
+
+	fs := flag.NewFlagSet("mirador", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.StringVar(&opts.Driver, "driver", "", "database driver: "+strings.Join(drivers, ", "))
+	fs.StringVar(&opts.DSN, "dsn", "", "connection string to connect with")
+	fs.BoolVar(&opts.ReadOnly, "read-only", false, "block writes on the --dsn connection; a SQLite file is opened with mode=ro")
+	fs.Usage = func() {
+		fmt.Fprintln(out, "usage: mirador [--driver <driver> --dsn <connection string> [--read-only] | <saved connection>]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	switch {
+	case fs.NArg() > 1:
+		return opts, fmt.Errorf("only one saved connection can be named; quote a name with spaces")
+	case fs.NArg() == 1 && (opts.Driver != "" || opts.DSN != ""):
+		return opts, fmt.Errorf("name a saved connection or give --driver and --dsn, not both")
+	case opts.ReadOnly && opts.DSN == "":
+		return opts, fmt.Errorf("--read-only needs --dsn; press r in the saved connections list to make a saved connection read-only")
+	case fs.NArg() == 1:
+		opts.Connection = fs.Arg(0)
+	case opts.Driver != "" && opts.DSN == "":
+		return opts, fmt.Errorf("--driver needs --dsn")
+	case opts.DSN != "" && opts.Driver == "":
+		return opts, fmt.Errorf("--dsn needs --driver: one of %s", strings.Join(drivers, ", "))
+	}
+	if opts.Driver != "" {
+		if _, ok := launchDatabaseType(opts.Driver); !ok {
+			return opts, fmt.Errorf("unsupported database driver: %s (use one of %s)", opts.Driver, strings.Join(drivers, ", "))
+		}
+		if err := CheckConnectionString(opts.Driver, opts.DSN); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// StartLaunchConnection starts connecting to the connection named on the
+// command line, showing the saved connections list while it connects so a
+// failure is reported there. A saved connection that is still encrypted is
+// connected once the passphrase unlocks it.
+func StartLaunchConnection(m models.Model, opts LaunchOptions) (models.Model, tea.Cmd, error) {
+	switch {
+	case opts.DSN != "":
+		db, _ := launchDatabaseType(opts.Driver)
+		m.SelectedDB = db
+		m.ConnectionStr = opts.DSN
+		m.ConnectionName = ""
+		m.ReplicaConnectionStr = ""
+		m.ReadOnly, m.Immutable = opts.ReadOnly, false
+		m = ApplyConnectionDefaults(m, models.SavedConnection{})
+		m = PushView(m, models.SavedConnectionsView, models.NavParams{})
+		m = CancelConnectRetry(m)
+		m.IsConnecting = true
+		return m, ConnectToDB(m.SelectedDB, ConnectionDSN(m), m.DefaultSchema, m.Timeouts), nil
+
+	case opts.Connection != "":
+		for _, conn := range m.LockedConnections {
+			if conn.Name == opts.Connection {
+				m.LaunchConnection = conn.Name
+				return m, nil, nil
+			}
+		}
+		for _, conn := range m.SavedConnections {
+			if conn.Name == opts.Connection {
+				m = PushView(m, models.SavedConnectionsView, models.NavParams{})
+				return ConnectSavedConnection(m, conn)
+			}
+		}
+		return m, nil, fmt.Errorf("no saved connection is named '%s'", opts.Connection)
+	}
+	return m, nil, nil
+}
+
+// ConnectSavedConnection starts connecting to a saved connection with its
+// read replica, read-only mode, environment, and defaults
+func ConnectSavedConnection(m models.Model, conn models.SavedConnection) (models.Model, tea.Cmd, error) {
+	connectionStr, err := SavedConnectionString(conn)
+	if err != nil {
+		return m, nil, err
+	}
+	if db, ok := launchDatabaseType(conn.Driver); ok {
+		m.SelectedDB = db
+	}
+	m.ConnectionStr = connectionStr
+	m.ConnectionName = conn.Name
+	m.ReplicaConnectionStr = conn.ReplicaConnectionStr
+	m.ReadOnly, m.Immutable = conn.ReadOnly, conn.Immutable
+	m.Environment = conn.Environment
+	m = ApplyConnectionDefaults(m, conn)
+	m = CancelConnectRetry(m) // A new connect starts its own retries
+	m.IsConnecting = true
+	m.Err = nil
+	m.QueryResult = "" // Clear any previous messages
+	return m, ConnectToDB(m.SelectedDB, ConnectionDSN(m), m.DefaultSchema, m.Timeouts), nil
+}
+
+// launchDatabaseType finds the supported database type of a driver
+func launchDatabaseType(driver string) (models.DBType, bool) {
+	for _, db := range models.SupportedDatabaseTypes {
+		if db.Driver == driver {
+			return db, true
+		}
+	}
+	return models.DBType{}, false
+}
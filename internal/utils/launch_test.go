@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"io"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseLaunchArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    LaunchOptions
+		wantErr bool
+	}{
+		{"no arguments", nil, LaunchOptions{}, false},
+		{"driver and dsn", []string{"--driver", "postgres", "--dsn", "postgres://app@db/shop"}, LaunchOptions{Driver: "postgres", DSN: "postgres://app@db/shop"}, false},
+		{"saved connection", []string{"prod billing"}, LaunchOptions{Connection: "prod billing"}, false},
+		{"read-only dsn", []string{"--driver", "sqlite3", "--dsn", "copy.db", "--read-only"}, LaunchOptions{Driver: "sqlite3", DSN: "copy.db", ReadOnly: true}, false},
+		{"read-only saved connection", []string{"--read-only", "prod"}, LaunchOptions{}, true},
+		{"dsn without driver", []string{"--dsn", "postgres://app@db/shop"}, LaunchOptions{}, true},
+		{"driver without dsn", []string{"--driver", "mysql"}, LaunchOptions{}, true},
+		{"unknown driver", []string{"--driver", "oracle", "--dsn", "x"}, LaunchOptions{}, true},
+		{"invalid dsn", []string{"--driver", "mysql", "--dsn", "mysql://app@db/shop"}, LaunchOptions{}, true},
+		{"name and dsn", []string{"--driver", "sqlite3", "--dsn", "app.db", "prod"}, LaunchOptions{}, true},
+		{"two names", []string{"prod", "billing"}, LaunchOptions{}, true},
+		{"unknown flag", []string{"--host", "db"}, LaunchOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLaunchArgs(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLaunchArgs(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseLaunchArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartLaunchConnection(t *testing.T) {
+	saved := []models.SavedConnection{{Name: "local", Driver: "sqlite3", ConnectionStr: "/tmp/app.db", ReadOnly: true}}
+
+	tests := []struct {
+		name           string
+		model          models.Model
+		opts           LaunchOptions
+		wantConnecting bool
+		wantPending    string
+		wantErr        bool
+	}{
+		{"nothing named", models.Model{}, LaunchOptions{}, false, "", false},
+		{"dsn", models.Model{}, LaunchOptions{Driver: "postgres", DSN: "postgres://app@db/shop"}, true, "", false},
+		{"saved connection", models.Model{SavedConnections: saved}, LaunchOptions{Connection: "local"}, true, "", false},
+		{"locked saved connection waits for the passphrase", models.Model{LockedConnections: saved}, LaunchOptions{Connection: "local"}, false, "local", false},
+		{"unknown saved connection", models.Model{SavedConnections: saved}, LaunchOptions{Connection: "prod"}, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cmd, err := StartLaunchConnection(tt.model, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StartLaunchConnection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.IsConnecting != tt.wantConnecting || (cmd != nil) != tt.wantConnecting {
+				t.Errorf("StartLaunchConnection() connecting = %v with cmd %v, want %v", got.IsConnecting, cmd != nil, tt.wantConnecting)
+			}
+			if got.LaunchConnection != tt.wantPending {
+				t.Errorf("StartLaunchConnection() LaunchConnection = %q, want %q", got.LaunchConnection, tt.wantPending)
+			}
+			if tt.wantConnecting && got.State != models.SavedConnectionsView {
+				t.Errorf("StartLaunchConnection() State = %v, want SavedConnectionsView", got.State)
+			}
+		})
+	}
+
+	got, _, _ := StartLaunchConnection(models.Model{SavedConnections: saved}, LaunchOptions{Connection: "local"})
+	if got.SelectedDB.Driver != "sqlite3" || got.ConnectionName != "local" || !got.ReadOnly {
+		t.Errorf("StartLaunchConnection() did not apply the saved connection: %+v %q %v", got.SelectedDB, got.ConnectionName, got.ReadOnly)
+	}
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MaintenanceActionForKey maps a key press to the driver's maintenance action, or "" if unsupported
+func MaintenanceActionForKey(driver, key string) string {
+	switch key {
+	case "a":
+		return "ANALYZE"
+	case "V":
+		if driver == "mysql" || driver == "mariadb" {
+			return "OPTIMIZE"
+		}
+		return "VACUUM"
+	}
+	return ""
+}
+
+// BuildMaintenanceSQL generates the maintenance statement for a table
+func BuildMaintenanceSQL(driver, action, schema, table string) (string, error) {
+	switch driver {
+	case "postgres", "redshift":
+		if action != "VACUUM" && action != "ANALYZE" {
+			break
+		}
+		return action + " " + database.QualifiedTableName(driver, schema, table), nil
+	case "cockroach":
+		// CockroachDB has no VACUUM; ANALYZE refreshes the table statistics
+		if action != "ANALYZE" {
+			break
+		}
+		return "ANALYZE " + database.QualifiedTableName(driver, schema, table), nil
+	case "mysql", "mariadb":
+		if action != "OPTIMIZE" && action != "ANALYZE" {
+			break
+		}
+		return action + " TABLE " + database.QualifiedTableName(driver, schema, table), nil
+	case "sqlite3":
+		switch action {
+		case "VACUUM":
+			// SQLite can only vacuum the whole database file
+			return "VACUUM", nil
+		case "ANALYZE":
+			return "ANALYZE " + database.QualifiedTableName(driver, schema, table), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported driver: %s", driver)
+	}
+	return "", fmt.Errorf("%s is not supported for %s", action, driver)
+}
+
+// RunMaintenanceAction runs a maintenance statement asynchronously
+func RunMaintenanceAction(db *sql.DB, selectedDB models.DBType, action, schema, table string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		query, err := BuildMaintenanceSQL(selectedDB.Driver, action, schema, table)
+		if err != nil {
+			return models.MaintenanceResult{Table: table, Action: action, Err: err}
+		}
+
+		start := time.Now()
+		message, err := database.RunMaintenance(db, query)
+		if err != nil {
+			err = fmt.Errorf("%s failed: %w", action, err)
+		}
+		return models.MaintenanceResult{
+			Table:    table,
+			Action:   action,
+			Message:  message,
+			Duration: time.Since(start),
+			Err:      err,
+		}
+	})
+}
+
+// MaintenanceTick schedules the next elapsed-time refresh while maintenance runs
+func MaintenanceTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return models.MaintenanceTickMsg{}
+	})
+}
+
+// HandleMaintenanceResult processes a maintenance result and refreshes the overview
+func HandleMaintenanceResult(m models.Model, msg models.MaintenanceResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsRunningMaintenance = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	updatedModel.QueryResult = fmt.Sprintf("✅ %s %s finished in %s: %s",
+		msg.Action, msg.Table, msg.Duration.Round(time.Millisecond), msg.Message)
+	updatedModel.IsLoadingOverview = true
+	return updatedModel, tea.Batch(
+		LoadDatabaseOverview(m.DB, m.SelectedDB, m.SelectedSchema),
+		ClearResultAfterTimeout(),
+	)
+}
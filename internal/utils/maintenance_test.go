@@ -0,0 +1,65 @@
+package utils
+
+import "testing"
+
+func TestMaintenanceActionForKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		key    string
+		want   string
+	}{
+		{"postgres analyze", "postgres", "a", "ANALYZE"},
+		{"postgres vacuum", "postgres", "V", "VACUUM"},
+		{"mysql optimize", "mysql", "V", "OPTIMIZE"},
+		{"sqlite vacuum", "sqlite3", "V", "VACUUM"},
+		{"unknown key", "postgres", "x", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaintenanceActionForKey(tt.driver, tt.key); got != tt.want {
+				t.Errorf("MaintenanceActionForKey(%q, %q) = %q, want %q", tt.driver, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMaintenanceSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		action  string
+		schema  string
+		table   string
+		want    string
+		wantErr bool
+	}{
+		{"postgres vacuum", "postgres", "VACUUM", "sales", "orders", `VACUUM "sales"."orders"`, false},
+		{"postgres default schema", "postgres", "ANALYZE", "", "orders", `ANALYZE "public"."orders"`, false},
+		{"postgres optimize", "postgres", "OPTIMIZE", "public", "orders", "", true},
+		{"cockroach analyze", "cockroach", "ANALYZE", "", "orders", `ANALYZE "public"."orders"`, false},
+		{"cockroach vacuum", "cockroach", "VACUUM", "public", "orders", "", true},
+		{"redshift vacuum", "redshift", "VACUUM", "sales", "orders", `VACUUM "sales"."orders"`, false},
+		{"mysql optimize", "mysql", "OPTIMIZE", "shop", "orders", "OPTIMIZE TABLE `shop`.`orders`", false},
+		{"mysql analyze", "mysql", "ANALYZE", "", "orders", "ANALYZE TABLE `orders`", false},
+		{"mysql vacuum", "mysql", "VACUUM", "shop", "orders", "", true},
+		{"sqlite vacuum", "sqlite3", "VACUUM", "main", "orders", "VACUUM", false},
+		{"sqlite analyze", "sqlite3", "ANALYZE", "main", "orders", `ANALYZE "orders"`, false},
+		{"quotes in names", "postgres", "ANALYZE", `my"schema`, `odd"table`, `ANALYZE "my""schema"."odd""table"`, false},
+		{"backtick in name", "mysql", "ANALYZE", "shop", "odd`table", "ANALYZE TABLE `shop`.`odd``table`", false},
+		{"unknown driver", "oracle", "ANALYZE", "", "orders", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildMaintenanceSQL(tt.driver, tt.action, tt.schema, tt.table)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildMaintenanceSQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BuildMaintenanceSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
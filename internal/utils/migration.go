@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// SQLLiteral quotes a value as a string literal for the driver
+func SQLLiteral(driver, value string) string {
+	if driver == "mysql" || driver == "mariadb" {
+		// MySQL treats backslashes in string literals as escapes by default
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// InlineSQLArgs replaces the placeholders of a parameterized statement ($1, $2 where
+// the driver numbers them, ? otherwise) with literals so the statement can be
+// replayed; a nil argument becomes NULL. Placeholders inside quoted literals and
+// identifiers, comments, and dollar-quoted bodies are left alone.
+func InlineSQLArgs(driver, statement string, args []any) string {
+	var b strings.Builder
+	last, next := 0, 0
+	for _, p := range findPlaceholders(models.DriverCapabilities(driver).NumberedPlaceholders, statement) {
+		n := 0
+		switch {
+		case p.text == "?":
+			next++
+			n = next
+		case p.text[0] == '$':
+			n, _ = strconv.Atoi(p.text[1:])
+		}
+		if n < 1 || n > len(args) {
+			continue
+		}
+		b.WriteString(statement[last:p.start])
+		if args[n-1] == nil {
+			b.WriteString("NULL")
+		} else {
+			b.WriteString(SQLLiteral(driver, fmt.Sprint(args[n-1])))
+		}
+		last = p.end
+	}
+	b.WriteString(statement[last:])
+	return b.String()
+}
+
+// FormatMigrationStatement renders one recorded statement for a migration file
+func FormatMigrationStatement(kind, statement string, at time.Time) string {
+	statement = strings.TrimRight(strings.TrimSpace(statement), "; \t\n")
+	return fmt.Sprintf("-- %s at %s\n%s;\n\n", kind, at.Format("2006-01-02 15:04:05"), statement)
+}
+
+// MigrationHeader is written at the top of a new migration file
+func MigrationHeader(driver string, at time.Time) string {
+	return fmt.Sprintf("-- Migration recorded by mirador on %s (%s)\n-- Up statements, in the order they were executed\n\n",
+		at.Format("2006-01-02 15:04:05"), driver)
+}
+
+// RecordMigration appends a successfully executed statement to the migration file
+// being recorded. It does nothing when no recording is active.
+func RecordMigration(migrationFile, driver, kind, statement string) {
+	if migrationFile == "" {
+		return
+	}
+	now := time.Now()
+	config.AppendMigrationStatement(migrationFile, MigrationHeader(driver, now), FormatMigrationStatement(kind, statement, now))
+}
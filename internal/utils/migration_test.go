@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInlineSQLArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		driver    string
+		statement string
+		args      []any
+		want      string
+	}{
+		{"postgres placeholders", "postgres", `UPDATE "public"."users" SET "name" = $1 WHERE "id" = $2`, []any{"O'Brien", "7"},
+			`UPDATE "public"."users" SET "name" = 'O''Brien' WHERE "id" = '7'`},
+		{"postgres out of order", "postgres", "SELECT $2, $1", []any{"a", "b"}, "SELECT 'b', 'a'"},
+		{"cockroach placeholders", "cockroach", "SELECT $1", []any{"a"}, "SELECT 'a'"},
+		{"redshift placeholders", "redshift", "SELECT $1", []any{"a"}, "SELECT 'a'"},
+		{"question marks", "sqlite3", `UPDATE "users" SET "name" = ? WHERE "id" = ?`, []any{"ann", "1"},
+			`UPDATE "users" SET "name" = 'ann' WHERE "id" = '1'`},
+		{"mysql escapes backslashes", "mysql", "UPDATE `t` SET `p` = ? WHERE `id` = ?", []any{`C:\tmp`, "2"},
+			"UPDATE `t` SET `p` = 'C:\\\\tmp' WHERE `id` = '2'"},
+		{"mariadb escapes backslashes", "mariadb", "UPDATE `t` SET `p` = ? WHERE `id` = ?", []any{`C:\tmp`, "2"},
+			"UPDATE `t` SET `p` = 'C:\\\\tmp' WHERE `id` = '2'"},
+		{"placeholder inside a literal", "sqlite3", `UPDATE "t" SET "q" = 'why?' WHERE "id" = ?`, []any{"3"},
+			`UPDATE "t" SET "q" = 'why?' WHERE "id" = '3'`},
+		{"NULL argument", "postgres", "UPDATE t SET deleted_at = $1 WHERE id = $2", []any{nil, "4"},
+			"UPDATE t SET deleted_at = NULL WHERE id = '4'"},
+		{"placeholders in comments and dollar quotes", "postgres", "-- set $1\nSELECT $$ $1 $$, $1 /* $2 */", []any{"x"},
+			"-- set $1\nSELECT $$ $1 $$, 'x' /* $2 */"},
+		{"more placeholders than arguments", "mysql", "SELECT ?, ?", []any{"a"}, "SELECT 'a', ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InlineSQLArgs(tt.driver, tt.statement, tt.args); got != tt.want {
+				t.Errorf("InlineSQLArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMigrationStatement(t *testing.T) {
+	at := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{"ALTER TABLE users ADD COLUMN age int", "-- query at 2025-03-04 05:06:07\nALTER TABLE users ADD COLUMN age int;\n\n"},
+		{"  DROP TABLE old;  \n", "-- query at 2025-03-04 05:06:07\nDROP TABLE old;\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			if got := FormatMigrationStatement("query", tt.statement, at); got != tt.want {
+				t.Errorf("FormatMigrationStatement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
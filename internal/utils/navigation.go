@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// viewLabels name the screens in the breadcrumbs
+var viewLabels = map[models.ViewState]string{
+	models.DBTypeView:             "Databases",
+	models.SavedConnectionsView:   "Saved connections",
+	models.ConnectionView:         "Connect",
+	models.SaveConnectionView:     "Save connection",
+	models.EditConnectionView:     "Edit connection",
+	models.SchemaView:             "Schemas",
+	models.TablesView:             "Tables",
+	models.ColumnsView:            "Columns",
+	models.QueryView:              "Query",
+	models.QueryHistoryView:       "History",
+	models.DataPreviewView:        "Preview",
+	models.RowDetailView:          "Row",
+	models.RelationshipsView:      "Relationships",
+	models.DatabaseOverviewView:   "Overview",
+	models.SlowQueryView:          "Slow queries",
+	models.ServerSettingsView:     "Settings",
+	models.TableGrowthView:        "Growth",
+	models.AuditLogView:           "Audit log",
+	models.GenerateDataView:       "Test data",
+	models.CopyDataView:           "Copy",
+	models.CompareTablesView:      "Compare",
+	models.NoteEditView:           "Note",
+	models.ConnectionHealthView:   "Health",
+	models.RowHistoryView:         "History",
+	models.PassphraseView:         "Passphrase",
+	models.ChartView:              "Chart",
+	models.GroupSummaryView:       "Group by",
+	models.ConnectionGroupView:    "Group and tags",
+	models.RowDiffView:            "Row diff",
+	models.WatchlistView:          "Watchlist",
+	models.ConnectionDefaultsView: "Defaults",
+	models.NotifyView:             "Notifications",
+}
+
+// PushView opens a screen on top of the current one, which esc returns to.
+// Opening the current screen again only updates its params, and opening a
+// screen already on the stack returns to it, dropping the screens above it, so
+// the stack never holds a screen twice.
+func PushView(m models.Model, state models.ViewState, params models.NavParams) models.Model {
+	if state == m.State {
+		m.NavParams = params
+		return m
+	}
+	for i, entry := range m.NavStack {
+		if entry.State == state {
+			m.NavStack = m.NavStack[:i:i]
+			m.State = state
+			m.NavParams = params
+			return m
+		}
+	}
+	m.NavStack = append(m.NavStack[:len(m.NavStack):len(m.NavStack)], models.NavEntry{State: m.State, Params: m.NavParams})
+	m.State = state
+	m.NavParams = params
+	return m
+}
+
+// PopView returns to the screen below the current one, or to fallback when the
+// current screen was opened without one
+func PopView(m models.Model, fallback models.ViewState) models.Model {
+	if len(m.NavStack) == 0 {
+		m.State = fallback
+		m.NavParams = models.NavParams{}
+		return m
+	}
+	top := m.NavStack[len(m.NavStack)-1]
+	m.NavStack = m.NavStack[: len(m.NavStack)-1 : len(m.NavStack)-1]
+	m.State = top.State
+	m.NavParams = top.Params
+	return m
+}
+
+// ResetView starts the stack over at a root screen: the database list, the
+// saved connections, or the tables of a new connection
+func ResetView(m models.Model, state models.ViewState, params models.NavParams) models.Model {
+	m.NavStack = nil
+	m.State = state
+	m.NavParams = params
+	return m
+}
+
+// TableParams are the params of a screen opened on the selected table
+func TableParams(m models.Model) models.NavParams {
+	return models.NavParams{Schema: m.SelectedSchema, Table: m.SelectedTable}
+}
+
+// Breadcrumbs describes the path to the current screen, e.g.
+// "Tables › Preview users › Row". It is empty on a root screen.
+func Breadcrumbs(m models.Model) string {
+	if len(m.NavStack) == 0 {
+		return ""
+	}
+	crumbs := make([]string, 0, len(m.NavStack)+1)
+	for _, entry := range append(m.NavStack[:len(m.NavStack):len(m.NavStack)], models.NavEntry{State: m.State, Params: m.NavParams}) {
+		crumbs = append(crumbs, breadcrumb(entry))
+	}
+	return strings.Join(crumbs, " › ")
+}
+
+// breadcrumb names one screen, with the table it was opened on
+func breadcrumb(entry models.NavEntry) string {
+	label, ok := viewLabels[entry.State]
+	if !ok {
+		label = "?"
+	}
+	if entry.Params.Table != "" {
+		return label + " " + entry.Params.Table
+	}
+	return label
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestPushAndPopView(t *testing.T) {
+	m := ResetView(models.Model{}, models.TablesView, models.NavParams{Schema: "public"})
+	m = PushView(m, models.DataPreviewView, models.NavParams{Schema: "public", Table: "users"})
+	m = PushView(m, models.RowDetailView, models.NavParams{Schema: "public", Table: "users"})
+	if got, want := Breadcrumbs(m), "Tables › Preview users › Row users"; got != want {
+		t.Errorf("Breadcrumbs() = %q, want %q", got, want)
+	}
+
+	// Opening a screen already on the stack returns to it
+	m = PushView(m, models.DataPreviewView, models.NavParams{Schema: "public", Table: "orders"})
+	if m.State != models.DataPreviewView || len(m.NavStack) != 1 || m.NavParams.Table != "orders" {
+		t.Errorf("PushView() onto the stack = (%v, %v, %v)", m.State, m.NavStack, m.NavParams)
+	}
+
+	// Opening the current screen again only updates its params
+	m = PushView(m, models.DataPreviewView, models.NavParams{Table: "items"})
+	if len(m.NavStack) != 1 || m.NavParams.Table != "items" {
+		t.Errorf("PushView() of the current screen = (%v, %v)", m.NavStack, m.NavParams)
+	}
+
+	m = PopView(m, models.DBTypeView)
+	if m.State != models.TablesView || len(m.NavStack) != 0 || m.NavParams.Schema != "public" {
+		t.Errorf("PopView() = (%v, %v, %v), want the tables view", m.State, m.NavStack, m.NavParams)
+	}
+	if got := Breadcrumbs(m); got != "" {
+		t.Errorf("Breadcrumbs() on a root screen = %q, want empty", got)
+	}
+
+	// An empty stack falls back
+	if m = PopView(m, models.DBTypeView); m.State != models.DBTypeView {
+		t.Errorf("PopView() with an empty stack = %v, want the fallback", m.State)
+	}
+}
+
+func TestPushViewDoesNotShareStacks(t *testing.T) {
+	base := ResetView(models.Model{}, models.TablesView, models.NavParams{})
+	base = PushView(base, models.DataPreviewView, models.NavParams{})
+	a := PushView(base, models.QueryView, models.NavParams{})
+	b := PushView(base, models.ColumnsView, models.NavParams{})
+	if a.NavStack[1].State != models.DataPreviewView || b.NavStack[1].State != models.DataPreviewView {
+		t.Errorf("stacks share entries: %v and %v", a.NavStack, b.NavStack)
+	}
+	if PopView(a, models.TablesView).State != models.DataPreviewView {
+		t.Errorf("PopView() after pushing onto a copy did not return to the preview")
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// NotePreview shortens a note to its first line, cut to width runes
+func NotePreview(note string, width int) string {
+	note = strings.TrimSpace(note)
+	if i := strings.IndexByte(note, '\n'); i >= 0 {
+		note = strings.TrimSpace(note[:i]) + " …"
+	}
+	runes := []rune(note)
+	if width > 1 && len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return note
+}
+
+// LoadTableNotes reads the table notes of a connection and schema.
+// A notes file that cannot be read simply yields no notes.
+func LoadTableNotes(driver, connectionStr, schema string) map[string]string {
+	notes, _ := config.LoadTableNotes(connectionStoreKey(driver, connectionStr, schema))
+	if notes == nil {
+		notes = map[string]string{}
+	}
+	return notes
+}
+
+// SaveNote stores the note being edited on its table or saved connection and
+// refreshes the list that previews it. An empty note removes it.
+func SaveNote(m models.Model) (models.Model, error) {
+	note := strings.TrimSpace(m.NoteInput.Value())
+
+	if m.NoteTable != "" {
+		key := connectionStoreKey(m.SelectedDB.Driver, m.ConnectionStr, m.SelectedSchema)
+		if err := config.SaveTableNote(key, m.NoteTable, note); err != nil {
+			return m, err
+		}
+		notes := make(map[string]string, len(m.TableNotes)+1)
+		for table, text := range m.TableNotes {
+			notes[table] = text
+		}
+		if note == "" {
+			delete(notes, m.NoteTable)
+		} else {
+			notes[m.NoteTable] = note
+		}
+		m.TableNotes = notes
+		m.TablesList.SetItems(CreateTableListItems(m.TableInfos, m.FavoriteTables, notes))
+		return SelectTableByName(m, m.NoteTable), nil
+	}
+
+	for i, conn := range m.SavedConnections {
+		if conn.Name == m.NoteConnectionName {
+			connections := append([]models.SavedConnection(nil), m.SavedConnections...)
+			connections[i].Notes = note
+			if err := config.SaveConnections(connections); err != nil {
+				return m, err
+			}
+			m.SavedConnections = connections
+			return UpdateSavedConnectionsList(m), nil
+		}
+	}
+	return m, fmt.Errorf("connection '%s' not found", m.NoteConnectionName)
+}
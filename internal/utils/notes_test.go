@@ -0,0 +1,26 @@
+package utils
+
+import "testing"
+
+func TestNotePreview(t *testing.T) {
+	tests := []struct {
+		name  string
+		note  string
+		width int
+		want  string
+	}{
+		{"short", "append-only; never update", 40, "append-only; never update"},
+		{"trimmed", "  owned by billing  ", 40, "owned by billing"},
+		{"cut to width", "this table is append-only", 10, "this tabl…"},
+		{"first line only", "append-only\nsee the runbook", 40, "append-only …"},
+		{"multibyte", "ñandú ñandú ñandú", 6, "ñandú…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotePreview(tt.note, tt.width); got != tt.want {
+				t.Errorf("NotePreview(%q, %d) = %q, want %q", tt.note, tt.width, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// MaxNotifications caps how many notifications the monitor keeps; the oldest
+// are dropped first
+const MaxNotifications = 1000
+
+// ParseNotifyChannels splits the channels typed into the monitor, separated by
+// commas or spaces, dropping repeats. Channel names are case-sensitive.
+func ParseNotifyChannels(input string) ([]string, error) {
+	var channels []string
+	for _, channel := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("enter at least one channel to listen on")
+	}
+	return channels, nil
+}
+
+// StartNotificationMonitor LISTENs on the channels over a connection of its own
+// and returns the stream of notifications, the channel that stops the monitor
+// when closed, and the command waiting for the first message
+func StartNotificationMonitor(connectionStr string, channels []string, seq int) (<-chan models.NotificationMsg, chan struct{}, tea.Cmd) {
+	ch := make(chan models.NotificationMsg, 64)
+	stop := make(chan struct{})
+	go func() {
+		defer close(ch)
+		database.ListenForNotifications(connectionStr, channels, stop, func(msg models.NotificationMsg) {
+			msg.Seq = seq
+			select {
+			case ch <- msg:
+			case <-stop:
+			}
+		})
+	}()
+	return ch, stop, WaitForNotification(ch)
+}
+
+// WaitForNotification waits for the next message of a running monitor
+func WaitForNotification(ch <-chan models.NotificationMsg) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	})
+}
+
+// StopNotificationMonitor stops a running monitor; its pending messages are
+// ignored from then on
+func StopNotificationMonitor(m models.Model) models.Model {
+	updatedModel := m
+	if m.NotifyStop != nil {
+		close(m.NotifyStop)
+	}
+	updatedModel.NotifyStop = nil
+	updatedModel.NotifyStream = nil
+	updatedModel.IsListening = false
+	updatedModel.NotifySeq++
+	return updatedModel
+}
+
+// HandleNotification records a notification or connection change of the
+// running monitor and keeps listening
+func HandleNotification(m models.Model, msg models.NotificationMsg) (models.Model, tea.Cmd) {
+	if msg.Seq != m.NotifySeq || m.NotifyStream == nil {
+		return m, nil
+	}
+	updatedModel := m
+	switch {
+	case msg.Notification != nil:
+		updatedModel.Notifications = append(slices.Clone(m.Notifications), *msg.Notification)
+		if len(updatedModel.Notifications) > MaxNotifications {
+			updatedModel.Notifications = updatedModel.Notifications[len(updatedModel.Notifications)-MaxNotifications:]
+		}
+		updatedModel = RefreshNotificationsTable(updatedModel)
+	case msg.Err != nil:
+		updatedModel.NotifyErr = msg.Err
+	case msg.Connected:
+		updatedModel.NotifyErr = nil
+	}
+	return updatedModel, WaitForNotification(m.NotifyStream)
+}
+
+// BuildNotificationRows converts notifications into table rows, oldest first
+func BuildNotificationRows(notifications []models.Notification) []table.Row {
+	rows := make([]table.Row, len(notifications))
+	for i, n := range notifications {
+		payload := strings.Join(strings.Fields(n.Payload), " ") // One line per notification
+		rows[i] = table.Row{n.ReceivedAt.Format("15:04:05.000"), n.Channel, strconv.Itoa(n.PID), payload}
+	}
+	return rows
+}
+
+// RefreshNotificationsTable rebuilds the monitor's table. A cursor on the last
+// row follows new notifications; one moved up stays where it is.
+func RefreshNotificationsTable(m models.Model) models.Model {
+	updatedModel := m
+	rows := BuildNotificationRows(m.Notifications)
+	follow := len(m.NotifyTable.Rows()) == 0 || m.NotifyTable.Cursor() >= len(m.NotifyTable.Rows())-1
+	cursor := m.NotifyTable.Cursor()
+
+	h, v := styles.DocStyle.GetFrameSize()
+	columns := []table.Column{
+		{Title: "Received", Width: 12},
+		{Title: "Channel", Width: 20},
+		{Title: "PID", Width: 8},
+		{Title: "Payload", Width: Max(m.Width-h-56, 30)},
+	}
+	updatedModel.NotifyTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.NotifyTable.SetStyles(styles.GetBlueTableStyles())
+	if follow {
+		cursor = len(rows) - 1
+	}
+	updatedModel.NotifyTable.SetCursor(Max(Min(cursor, len(rows)-1), 0))
+	return updatedModel
+}
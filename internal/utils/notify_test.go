@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestParseNotifyChannels(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"one channel", "orders", []string{"orders"}, false},
+		{"commas and spaces", " orders, jobs_done  audit ", []string{"orders", "jobs_done", "audit"}, false},
+		{"repeats dropped", "orders,orders, Orders", []string{"orders", "Orders"}, false},
+		{"empty", " , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNotifyChannels(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNotifyChannels(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNotifyChannels(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildNotificationRows(t *testing.T) {
+	at := time.Date(2026, 3, 17, 9, 15, 0, 250_000_000, time.UTC)
+	notifications := []models.Notification{
+		{Channel: "orders", Payload: `{"id": 7}`, PID: 4242, ReceivedAt: at},
+		{Channel: "jobs", Payload: "line one\nline two", PID: 17, ReceivedAt: at},
+	}
+
+	want := []table.Row{
+		{"09:15:00.250", "orders", "4242", `{"id": 7}`},
+		{"09:15:00.250", "jobs", "17", "line one line two"},
+	}
+	if got := BuildNotificationRows(notifications); !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildNotificationRows() = %v, want %v", got, want)
+	}
+}
+
+func TestHandleNotification(t *testing.T) {
+	stream := make(chan models.NotificationMsg)
+	m := models.Model{NotifySeq: 3, NotifyStream: stream, Width: 120, Height: 40}
+	notification := func(payload string) models.NotificationMsg {
+		return models.NotificationMsg{Seq: 3, Notification: &models.Notification{Channel: "orders", Payload: payload}}
+	}
+
+	got, cmd := HandleNotification(m, notification("first"))
+	if len(got.Notifications) != 1 || got.Notifications[0].Payload != "first" || cmd == nil {
+		t.Fatalf("HandleNotification() = %+v, cmd %v", got.Notifications, cmd)
+	}
+
+	stale := notification("earlier monitor")
+	stale.Seq = 2
+	if ignored, cmd := HandleNotification(got, stale); len(ignored.Notifications) != 1 || cmd != nil {
+		t.Errorf("a message of an earlier monitor was recorded: %+v", ignored.Notifications)
+	}
+
+	lost, _ := HandleNotification(got, models.NotificationMsg{Seq: 3, Err: errors.New("connection reset")})
+	if lost.NotifyErr == nil {
+		t.Error("a connection error was not recorded")
+	}
+	if back, _ := HandleNotification(lost, models.NotificationMsg{Seq: 3, Connected: true}); back.NotifyErr != nil {
+		t.Errorf("a reconnect kept the error %v", back.NotifyErr)
+	}
+
+	full := got
+	full.Notifications = make([]models.Notification, MaxNotifications)
+	full, _ = HandleNotification(full, notification("newest"))
+	if len(full.Notifications) != MaxNotifications || full.Notifications[MaxNotifications-1].Payload != "newest" {
+		t.Errorf("the monitor kept %d notifications ending with %q", len(full.Notifications), full.Notifications[len(full.Notifications)-1].Payload)
+	}
+}
@@ -0,0 +1,121 @@
+package utils
+
+import "strings"
+
+// GroupDigits inserts thousands separators into the integer part of a plain
+// decimal number, e.g. "-1234567.50" becomes "-1,234,567.50". Anything that is
+// not a plain decimal number, such as "NULL" or "1e10", is returned unchanged.
+func GroupDigits(value string) string {
+	sign := ""
+	digits := value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	intPart, fraction := digits, ""
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		intPart, fraction = digits[:dot], digits[dot:]
+	}
+	if intPart == "" || !allDigits(intPart) || (len(fraction) > 1 && !allDigits(fraction[1:])) {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(fraction)
+	return b.String()
+}
+
+// RoundDecimal rounds a plain decimal number to at most places fraction digits,
+// half away from zero, and drops trailing zeros. It works on the digits
+// themselves, so NUMERIC values and integers beyond int64 keep every digit a
+// float would lose. ok is false when value is not a plain decimal.
+func RoundDecimal(value string, places int) (string, bool) {
+	sign, digits := "", value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	intPart, fraction := digits, ""
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		intPart, fraction = digits[:dot], digits[dot+1:]
+	}
+	if intPart == "" && fraction == "" || !allDigits(intPart) || !allDigits(fraction) {
+		return value, false
+	}
+	if sign == "+" {
+		sign = ""
+	}
+
+	roundUp := false
+	if len(fraction) > places {
+		roundUp = fraction[places] >= '5'
+		fraction = fraction[:places]
+	}
+	kept := []byte(intPart + fraction)
+	if roundUp {
+		i := len(kept) - 1
+		for ; i >= 0 && kept[i] == '9'; i-- {
+			kept[i] = '0'
+		}
+		if i >= 0 {
+			kept[i]++
+		} else {
+			kept = append([]byte{'1'}, kept...)
+		}
+	}
+
+	intPart = strings.TrimLeft(string(kept[:len(kept)-len(fraction)]), "0")
+	fraction = strings.TrimRight(string(kept[len(kept)-len(fraction):]), "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if intPart == "0" && fraction == "" {
+		sign = ""
+	}
+	if fraction != "" {
+		return sign + intPart + "." + fraction, true
+	}
+	return sign + intPart, true
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyNumberSeparators returns a copy of rows with the cells of numeric columns
+// grouped by GroupDigits. types holds the database type of each column. The
+// original rows are returned untouched when no column is numeric.
+func ApplyNumberSeparators(rows [][]string, types []string) [][]string {
+	numeric := make([]bool, len(types))
+	hasNumeric := false
+	for i, t := range types {
+		numeric[i] = IsNumericType(t)
+		hasNumeric = hasNumeric || numeric[i]
+	}
+	if !hasNumeric {
+		return rows
+	}
+
+	grouped := make([][]string, len(rows))
+	for i, row := range rows {
+		out := make([]string, len(row))
+		for j, cell := range row {
+			if j < len(numeric) && numeric[j] {
+				cell = GroupDigits(cell)
+			}
+			out[j] = cell
+		}
+		grouped[i] = out
+	}
+	return grouped
+}
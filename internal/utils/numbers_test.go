@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"short number", "999", "999"},
+		{"thousands", "1234", "1,234"},
+		{"millions", "1234567", "1,234,567"},
+		{"negative", "-1234567", "-1,234,567"},
+		{"decimal", "1234567.891", "1,234,567.891"},
+		{"leading dot left alone", ".5", ".5"},
+		{"exponent left alone", "1.5e10", "1.5e10"},
+		{"null left alone", "NULL", "NULL"},
+		{"empty left alone", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupDigits(tt.value); got != tt.want {
+				t.Errorf("GroupDigits(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{"integer", "42", "42", true},
+		{"beyond float precision", "123456789012345678901.235", "123456789012345678901.24", true},
+		{"rounds half away from zero", "-2.345", "-2.35", true},
+		{"carries into integer", "999.995", "1000", true},
+		{"trailing zeros dropped", "10.500", "10.5", true},
+		{"rounds to zero", "-0.001", "0", true},
+		{"leading dot", ".5", "0.5", true},
+		{"plus sign", "+7.1", "7.1", true},
+		{"exponent rejected", "1.5e10", "1.5e10", false},
+		{"text rejected", "abc", "abc", false},
+		{"empty rejected", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RoundDecimal(tt.value, 2)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RoundDecimal(%q, 2) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestApplyNumberSeparators(t *testing.T) {
+	rows := [][]string{{"1000", "20000", "30000"}}
+	got := ApplyNumberSeparators(rows, []string{"int8", "varchar", "numeric"})
+	if want := [][]string{{"1,000", "20000", "30,000"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyNumberSeparators() = %v, want %v", got, want)
+	}
+	if rows[0][0] != "1000" {
+		t.Errorf("ApplyNumberSeparators() modified the stored rows: %v", rows)
+	}
+}
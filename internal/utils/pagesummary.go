@@ -0,0 +1,38 @@
+package utils
+
+import "fmt"
+
+// ColumnSummary counts the NULLs and the distinct non-NULL values of one
+// column over the rows of the loaded page
+type ColumnSummary struct {
+	Nulls    int
+	Distinct int
+}
+
+// SummarizePage counts NULLs and distinct values per column of a page. nulls
+// marks the NULL cells and may be shorter than rows; a missing cell counts as NULL.
+func SummarizePage(columns int, rows [][]string, nulls [][]bool) []ColumnSummary {
+	summary := make([]ColumnSummary, columns)
+	for col := range summary {
+		seen := map[string]bool{}
+		for i, row := range rows {
+			if col >= len(row) || i < len(nulls) && col < len(nulls[i]) && nulls[i][col] {
+				summary[col].Nulls++
+				continue
+			}
+			seen[row[col]] = true
+		}
+		summary[col].Distinct = len(seen)
+	}
+	return summary
+}
+
+// PageSummaryLabels labels each column's summary for the row above the
+// headers, e.g. "∅ 3 · 12 distinct"
+func PageSummaryLabels(summary []ColumnSummary) []string {
+	labels := make([]string, len(summary))
+	for i, s := range summary {
+		labels[i] = fmt.Sprintf("∅ %d · %d distinct", s.Nulls, s.Distinct)
+	}
+	return labels
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizePage(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns int
+		rows    [][]string
+		nulls   [][]bool
+		want    []ColumnSummary
+	}{
+		{
+			name:    "nulls and repeats",
+			columns: 2,
+			rows:    [][]string{{"1", "a"}, {"2", ""}, {"3", "a"}, {"4", "b"}},
+			nulls:   [][]bool{{false, false}, {false, true}, {false, false}, {false, false}},
+			want:    []ColumnSummary{{Nulls: 0, Distinct: 4}, {Nulls: 1, Distinct: 2}},
+		},
+		{
+			name:    "empty string is a value, not a NULL",
+			columns: 1,
+			rows:    [][]string{{""}, {""}, {"x"}},
+			nulls:   [][]bool{{false}, {true}, {false}},
+			want:    []ColumnSummary{{Nulls: 1, Distinct: 2}},
+		},
+		{
+			name:    "without null flags",
+			columns: 1,
+			rows:    [][]string{{"x"}, {"x"}},
+			want:    []ColumnSummary{{Nulls: 0, Distinct: 1}},
+		},
+		{
+			name:    "short row counts as NULL",
+			columns: 2,
+			rows:    [][]string{{"1", "a"}, {"2"}},
+			want:    []ColumnSummary{{Nulls: 0, Distinct: 2}, {Nulls: 1, Distinct: 1}},
+		},
+		{
+			name:    "empty page",
+			columns: 2,
+			want:    []ColumnSummary{{}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SummarizePage(tt.columns, tt.rows, tt.nulls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SummarizePage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSummaryLabels(t *testing.T) {
+	got := PageSummaryLabels([]ColumnSummary{{Nulls: 3, Distinct: 12}, {}})
+	want := []string{"∅ 3 · 12 distinct", "∅ 0 · 0 distinct"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PageSummaryLabels() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// NullBindValue is typed as a bind value to bind NULL
+const NullBindValue = "NULL"
+
+// placeholder is a bind placeholder in a query: $1, ?, or :name, at query[start:end]
+type placeholder struct {
+	text       string
+	start, end int
+}
+
+// isIdentifierByte reports whether c can be part of an unquoted identifier
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// findPlaceholders finds the bind placeholders of a query outside literals,
+// quoted identifiers, comments, and dollar-quoted bodies: $1 with numbered
+// placeholders (the PostgreSQL family), ? otherwise, and :name with both. A
+// :: cast and a slice such as arr[lo:hi] are not placeholders.
+func findPlaceholders(numbered bool, query string) []placeholder {
+	var found []placeholder
+	for i := 0; i < len(query); i++ {
+		if end := skipLiteral(query, i); end != i {
+			i = end
+			continue
+		}
+		switch c := query[i]; {
+		case c == '$' && numbered:
+			end := i + 1
+			for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+				end++
+			}
+			if end > i+1 {
+				found = append(found, placeholder{query[i:end], i, end})
+				i = end - 1
+			}
+		case c == '?' && !numbered:
+			found = append(found, placeholder{"?", i, i + 1})
+		case c == ':':
+			if i+1 < len(query) && query[i+1] == ':' {
+				i++
+				continue
+			}
+			if i > 0 && (isIdentifierByte(query[i-1]) || query[i-1] == ']' || query[i-1] == ')') {
+				continue
+			}
+			end := i + 1
+			if end < len(query) && (query[end] < '0' || query[end] > '9') {
+				for end < len(query) && isIdentifierByte(query[end]) {
+					end++
+				}
+			}
+			if end > i+1 {
+				found = append(found, placeholder{query[i:end], i, end})
+				i = end - 1
+			}
+		}
+	}
+	return found
+}
+
+// QueryParameters lists the placeholders of a query in the order their values
+// are asked for: $1 up to the highest number used, each ? by position as ?1,
+// ?2, ..., and each :name once. A script cannot have placeholders, and a
+// query cannot mix :name with positional ones.
+func QueryParameters(driver, query string) ([]string, error) {
+	found := findPlaceholders(models.DriverCapabilities(driver).NumberedPlaceholders, query)
+	if len(found) == 0 {
+		return nil, nil
+	}
+	if len(SplitStatements(query)) > 1 {
+		return nil, fmt.Errorf("bind values can only be given to a single statement; run the statement with placeholders on its own")
+	}
+
+	var params []string
+	positional, named, highest := 0, false, 0
+	for _, p := range found {
+		switch {
+		case p.text == "?":
+			positional++
+			params = append(params, "?"+strconv.Itoa(positional))
+		case p.text[0] == '$':
+			n, err := strconv.Atoi(p.text[1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid placeholder %s", p.text)
+			}
+			positional++
+			highest = max(highest, n)
+		default:
+			named = true
+			if !slices.Contains(params, p.text) {
+				params = append(params, p.text)
+			}
+		}
+	}
+	if named && positional > 0 {
+		return nil, fmt.Errorf("use either :name or positional placeholders in a query, not both")
+	}
+	for n := 1; n <= highest; n++ {
+		params = append(params, "$"+strconv.Itoa(n))
+	}
+	return params, nil
+}
+
+// BindQuery rewrites a query's placeholders for the driver and returns the
+// values to bind, in order. :name becomes $n, or ? repeated for every use. The
+// value NULL binds NULL; any other value is bound as text for the database to
+// convert to the column's type. A query without placeholders is returned as is.
+func BindQuery(driver, query string, values map[string]string) (string, []any, error) {
+	params, err := QueryParameters(driver, query)
+	if err != nil || len(params) == 0 {
+		return query, nil, err
+	}
+	for _, param := range params {
+		if _, ok := values[param]; !ok {
+			return "", nil, fmt.Errorf("no value for %s", param)
+		}
+	}
+	arg := func(param string) any {
+		if value := values[param]; !strings.EqualFold(value, NullBindValue) {
+			return value
+		}
+		return nil
+	}
+
+	numbered := models.DriverCapabilities(driver).NumberedPlaceholders
+	var b strings.Builder
+	var args []any
+	last, positional := 0, 0
+	for _, p := range findPlaceholders(numbered, query) {
+		b.WriteString(query[last:p.start])
+		last = p.end
+		switch {
+		case p.text == "?":
+			positional++
+			b.WriteString("?")
+			args = append(args, arg("?"+strconv.Itoa(positional)))
+		case p.text[0] == '$':
+			b.WriteString(p.text)
+		case numbered:
+			// Named placeholders are numbered in the order they are asked for
+			for i, param := range params {
+				if param == p.text {
+					b.WriteString("$" + strconv.Itoa(i+1))
+				}
+			}
+		default:
+			b.WriteString("?")
+			args = append(args, arg(p.text))
+		}
+	}
+	b.WriteString(query[last:])
+	if numbered {
+		for _, param := range params {
+			args = append(args, arg(param))
+		}
+	}
+	return b.String(), args, nil
+}
+
+// BindArgsText renders bound values for the audit log, NULL as NULL
+func BindArgsText(args []any) []string {
+	texts := make([]string, len(args))
+	for i, a := range args {
+		texts[i] = NullBindValue
+		if s, ok := a.(string); ok {
+			texts[i] = s
+		}
+	}
+	return texts
+}
+
+// StartBinding asks for the values of a query's placeholders one at a time,
+// starting from the values typed for them last time
+func StartBinding(m models.Model, params []string) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsBindingParams = true
+	updatedModel.BindParams = params
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	return selectBindParam(updatedModel, 0)
+}
+
+// selectBindParam moves the value prompt to the i-th placeholder
+func selectBindParam(m models.Model, i int) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.BindIndex = i
+	updatedModel.BindInput = textinput.New()
+	updatedModel.BindInput.Placeholder = "value, or NULL"
+	updatedModel.BindInput.SetValue(m.BindValues[m.BindParams[i]])
+	updatedModel.BindInput.CursorEnd()
+	return updatedModel, updatedModel.BindInput.Focus()
+}
+
+// keepBindValue stores the typed value of the prompted placeholder
+func keepBindValue(m models.Model) models.Model {
+	updatedModel := m
+	updatedModel.BindValues = make(map[string]string, len(m.BindValues)+1)
+	for param, value := range m.BindValues {
+		updatedModel.BindValues[param] = value
+	}
+	updatedModel.BindValues[m.BindParams[m.BindIndex]] = m.BindInput.Value()
+	return updatedModel
+}
+
+// NextBindParam keeps the typed value and moves to the next placeholder; done
+// is true once every placeholder has a value
+func NextBindParam(m models.Model) (updated models.Model, cmd tea.Cmd, done bool) {
+	updatedModel := keepBindValue(m)
+	if m.BindIndex+1 < len(m.BindParams) {
+		updatedModel, cmd = selectBindParam(updatedModel, m.BindIndex+1)
+		return updatedModel, cmd, false
+	}
+	updatedModel.IsBindingParams = false
+	return updatedModel, nil, true
+}
+
+// PreviousBindParam keeps the typed value and goes back one placeholder
+func PreviousBindParam(m models.Model) (models.Model, tea.Cmd) {
+	if m.BindIndex == 0 {
+		return m, nil
+	}
+	return selectBindParam(keepBindValue(m), m.BindIndex-1)
+}
+
+// CancelBinding closes the value prompt without running the query; the values
+// typed so far are offered again next time
+func CancelBinding(m models.Model) models.Model {
+	updatedModel := keepBindValue(m)
+	updatedModel.IsBindingParams = false
+	return updatedModel
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestQueryParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{"none", "postgres", "SELECT * FROM users", nil, false},
+		{"numbered up to the highest", "postgres", "SELECT * FROM users WHERE id = $2 OR owner = $1 OR parent = $2", []string{"$1", "$2"}, false},
+		{"question marks by position", "mysql", "SELECT * FROM users WHERE id = ? AND status = ?", []string{"?1", "?2"}, false},
+		{"jsonb ? operator on postgres", "postgres", "SELECT * FROM docs WHERE body ? 'key'", nil, false},
+		{"named once each", "sqlite3", "SELECT * FROM t WHERE a = :id OR b = :id OR c = :name", []string{":id", ":name"}, false},
+		{"cast is not a name", "postgres", "SELECT created_at::date FROM t WHERE id = :id", []string{":id"}, false},
+		{"array slice is not a name", "postgres", "SELECT tags[lo:hi] FROM t", nil, false},
+		{"mysql assignment is not a name", "mysql", "SELECT @n:=1", nil, false},
+		{"inside literals and comments", "mysql", "SELECT '?', \"a:b\" -- ?\nFROM t /* :x */", nil, false},
+		{"dollar-quoted body", "postgres", "DO $$ BEGIN PERFORM $1; END $$", nil, false},
+		{"mixed styles", "mysql", "SELECT * FROM t WHERE a = ? AND b = :b", nil, true},
+		{"script with placeholders", "postgres", "SELECT $1; SELECT 2", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryParameters(tt.driver, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryParameters(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryParameters(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		query    string
+		values   map[string]string
+		wantSQL  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{"numbered", "postgres", "SELECT * FROM t WHERE a = $2 AND b = $1", map[string]string{"$1": "x", "$2": "7"}, "SELECT * FROM t WHERE a = $2 AND b = $1", []any{"x", "7"}, false},
+		{"named on postgres", "postgres", "SELECT * FROM t WHERE a = :id OR b = :id AND c = :name", map[string]string{":id": "7", ":name": "ann"}, "SELECT * FROM t WHERE a = $1 OR b = $1 AND c = $2", []any{"7", "ann"}, false},
+		{"named on mysql repeats the value", "mysql", "SELECT * FROM t WHERE a = :id OR b = :id", map[string]string{":id": "7"}, "SELECT * FROM t WHERE a = ? OR b = ?", []any{"7", "7"}, false},
+		{"NULL binds NULL", "mysql", "UPDATE t SET a = ? WHERE id = ?", map[string]string{"?1": "null", "?2": "3"}, "UPDATE t SET a = ? WHERE id = ?", []any{nil, "3"}, false},
+		{"no placeholders", "postgres", "SELECT 1", nil, "SELECT 1", nil, false},
+		{"missing value", "postgres", "SELECT $1", map[string]string{}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := BindQuery(tt.driver, tt.query, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindQuery(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if gotSQL != tt.wantSQL || !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("BindQuery(%q) = %q, %v, want %q, %v", tt.query, gotSQL, gotArgs, tt.wantSQL, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExecuteBoundQuery(t *testing.T) {
+	db, err := database.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER, name TEXT); INSERT INTO users VALUES (1, 'ann'), (2, 'O''Brien')"); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlite := models.DBType{Driver: "sqlite3"}
+	query := "SELECT id FROM users WHERE name = :name OR id = :id"
+	msg := ExecuteQuery(db, sqlite, "", "", query, false, map[string]string{":name": "O'Brien", ":id": "NULL"}, nil)().(models.QueryResultMsg)
+	if msg.Err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", msg.Err)
+	}
+	if !reflect.DeepEqual(msg.Rows, [][]string{{"2"}}) || msg.Query != query {
+		t.Errorf("ExecuteQuery() = %v for %q, want [[2]] for the query as written", msg.Rows, msg.Query)
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MinPassphraseLength is the shortest master passphrase accepted
+const MinPassphraseLength = 8
+
+// CheckNewPassphrase validates a new master passphrase and its confirmation. Both
+// empty turns encryption off.
+func CheckNewPassphrase(passphrase, confirm string) error {
+	if passphrase != confirm {
+		return errors.New("the passphrases do not match")
+	}
+	if passphrase != "" && len(passphrase) < MinPassphraseLength {
+		return fmt.Errorf("use a passphrase of at least %d characters", MinPassphraseLength)
+	}
+	return nil
+}
+
+// UnlockSavedConnections decrypts the saved connections with the passphrase
+// entered at startup
+func UnlockSavedConnections(locked []models.SavedConnection, passphrase string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		connections, err := config.UnlockConnections(locked, passphrase)
+		return models.UnlockConnectionsResult{Connections: connections, Err: err}
+	})
+}
+
+// SetConnectionsPassphrase rewrites the saved connections encrypted under a new
+// passphrase, or in plaintext when it is empty
+func SetConnectionsPassphrase(connections []models.SavedConnection, passphrase string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if err := config.SetPassphrase(passphrase); err != nil {
+			return models.PassphraseSetResult{Err: err}
+		}
+		if err := config.SaveConnections(connections); err != nil {
+			return models.PassphraseSetResult{Err: fmt.Errorf("failed to save connections: %w", err)}
+		}
+		return models.PassphraseSetResult{Encrypted: passphrase != ""}
+	})
+}
+
+// StartPassphraseEntry opens the passphrase prompt, either to unlock the saved
+// connections or to set a new passphrase for them
+func StartPassphraseEntry(m models.Model, setting bool) models.Model {
+	updatedModel := m
+	updatedModel = PushView(updatedModel, models.PassphraseView, models.NavParams{})
+	updatedModel.IsSettingPassphrase = setting
+	updatedModel.PassphraseInput.Reset()
+	updatedModel.PassphraseConfirmInput.Reset()
+	updatedModel.PassphraseConfirmInput.Blur()
+	updatedModel.PassphraseInput.Focus()
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	return updatedModel
+}
+
+// HandleUnlockConnectionsResult shows the decrypted saved connections, or asks
+// for the passphrase again
+func HandleUnlockConnectionsResult(m models.Model, msg models.UnlockConnectionsResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsApplyingPassphrase = false
+	updatedModel.PassphraseInput.Reset()
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	updatedModel.LockedConnections = nil
+	updatedModel.SavedConnections = msg.Connections
+	updatedModel = UpdateSavedConnectionsList(updatedModel)
+	updatedModel.PassphraseInput.Blur()
+	updatedModel = ResetView(updatedModel, models.DBTypeView, models.NavParams{})
+	updatedModel.Err = nil
+	updatedModel.QueryResult = fmt.Sprintf("🔓 Unlocked %d saved connections", len(msg.Connections))
+
+	// Connect to the saved connection named on the command line
+	if name := updatedModel.LaunchConnection; name != "" {
+		updatedModel.LaunchConnection = ""
+		for _, conn := range updatedModel.SavedConnections {
+			if conn.Name == name {
+				updatedModel = PushView(updatedModel, models.SavedConnectionsView, models.NavParams{})
+				connecting, cmd, err := ConnectSavedConnection(updatedModel, conn)
+				if err != nil {
+					return SetErrorWithTimeout(updatedModel, err, 5*time.Second)
+				}
+				return connecting, cmd
+			}
+		}
+	}
+	return updatedModel, ClearResultAfterTimeout()
+}
+
+// HandlePassphraseSetResult returns to the saved connections once they are rewritten
+func HandlePassphraseSetResult(m models.Model, msg models.PassphraseSetResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsApplyingPassphrase = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	updatedModel.PassphraseInput.Reset()
+	updatedModel.PassphraseConfirmInput.Reset()
+	updatedModel.PassphraseInput.Blur()
+	updatedModel.PassphraseConfirmInput.Blur()
+	updatedModel = PopView(updatedModel, models.SavedConnectionsView)
+	updatedModel.Err = nil
+	updatedModel.QueryResult = "🔐 Saved connections are now encrypted with your passphrase"
+	if !msg.Encrypted {
+		updatedModel.QueryResult = "🔓 Saved connections are now stored in plaintext"
+	}
+	return updatedModel, ClearResultAfterTimeout()
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCheckNewPassphrase(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		confirm    string
+		wantErr    bool
+	}{
+		{"matching", "correct horse", "correct horse", false},
+		{"mismatch", "correct horse", "correct hose", true},
+		{"too short", "short", "short", true},
+		{"both empty turns encryption off", "", "", false},
+		{"confirmation missing", "correct horse", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckNewPassphrase(tt.passphrase, tt.confirm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckNewPassphrase(%q, %q) error = %v, wantErr %v", tt.passphrase, tt.confirm, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConnectionsPassphraseRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MIRADOR_KEYCHAIN", "0")
+	defer config.SetPassphrase("")
+
+	connections := []models.SavedConnection{
+		{Name: "prod", Driver: "postgres", ConnectionStr: "postgres://app:s3cret@db/prod"},
+		{Name: "local", Driver: "sqlite3", ConnectionStr: "/tmp/app.db"},
+	}
+	if msg := SetConnectionsPassphrase(connections, "correct horse")().(models.PassphraseSetResult); msg.Err != nil || !msg.Encrypted {
+		t.Fatalf("SetConnectionsPassphrase() = %+v", msg)
+	}
+
+	file, err := config.GetConnectionsFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Fatalf("connections file holds the password in plaintext:\n%s", data)
+	}
+
+	// A fresh start sees only ciphertext until the passphrase is entered
+	config.SetPassphrase("")
+	locked, err := config.LoadSavedConnections()
+	if err != nil || !config.HasEncryptedConnections(locked) {
+		t.Fatalf("LoadSavedConnections() = %v, %v; want encrypted connections", locked, err)
+	}
+
+	wrong := UnlockSavedConnections(locked, "wrong horse")().(models.UnlockConnectionsResult)
+	if !errors.Is(wrong.Err, config.ErrWrongPassphrase) {
+		t.Errorf("unlock with wrong passphrase error = %v, want %v", wrong.Err, config.ErrWrongPassphrase)
+	}
+
+	unlocked := UnlockSavedConnections(locked, "correct horse")().(models.UnlockConnectionsResult)
+	if unlocked.Err != nil {
+		t.Fatalf("unlock error = %v", unlocked.Err)
+	}
+	for i, conn := range unlocked.Connections {
+		if conn.ConnectionStr != connections[i].ConnectionStr {
+			t.Errorf("connection %q = %q, want %q", conn.Name, conn.ConnectionStr, connections[i].ConnectionStr)
+		}
+	}
+
+	// Once unlocked, loading again decrypts transparently
+	reloaded, err := config.LoadSavedConnections()
+	if err != nil || len(reloaded) != 2 || reloaded[0].ConnectionStr != connections[0].ConnectionStr {
+		t.Errorf("LoadSavedConnections() after unlock = %v, %v", reloaded, err)
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// PastedFilterValue cleans clipboard text for an exact-match filter: surrounding
+// whitespace and one pair of matching quotes, as around an ID copied from a JSON
+// log line, are removed. Empty text and text spanning several lines are rejected.
+func PastedFilterValue(text string) (string, error) {
+	value := strings.TrimSpace(text)
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'' || first == '`') {
+			value = strings.TrimSpace(value[1 : len(value)-1])
+		}
+	}
+	switch {
+	case value == "":
+		return "", fmt.Errorf("the clipboard is empty")
+	case strings.ContainsAny(value, "\r\n"):
+		return "", fmt.Errorf("the clipboard holds several lines; copy a single value to filter by")
+	}
+	return value, nil
+}
+
+// ColumnFilterText writes the preview filter that matches column exactly
+func ColumnFilterText(column, value string) string {
+	return column + " = " + value
+}
+
+// ApplyColumnFilter filters the preview to the rows whose column equals value
+// and reloads it from the first page
+func ApplyColumnFilter(m models.Model, column, value string) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.DataPreviewFilterValue = ColumnFilterText(column, value)
+	updatedModel.DataPreviewFilterInput.SetValue(updatedModel.DataPreviewFilterValue)
+	updatedModel.DataPreviewCurrentPage = 0
+	updatedModel = ResetFilterCount(updatedModel)
+	updatedModel.Err = nil
+	routed, db := RouteRead(updatedModel)
+	return routed, LoadDataPreviewWithFilter(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, routed.DataPreviewFilterValue, PreviewTableColumns(m), PreviewSortKeys(m), PreviewJSONColumns(m))
+}
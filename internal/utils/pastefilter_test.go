@@ -0,0 +1,39 @@
+package utils
+
+import "testing"
+
+func TestPastedFilterValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "5b2c9d1e-0f3a-4c55-9a2e-7d1c0b6f8e21", "5b2c9d1e-0f3a-4c55-9a2e-7d1c0b6f8e21", false},
+		{"surrounding whitespace", "  42\n", "42", false},
+		{"double quotes", `"abc-123"`, "abc-123", false},
+		{"single quotes", "'abc'", "abc", false},
+		{"unmatched quote kept", `"abc`, `"abc`, false},
+		{"lone quote kept", `"`, `"`, false},
+		{"empty", "   ", "", true},
+		{"empty quotes", `""`, "", true},
+		{"several lines", "a\nb", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PastedFilterValue(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PastedFilterValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PastedFilterValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnFilterText(t *testing.T) {
+	if got := ColumnFilterText("id", "42"); got != "id = 42" {
+		t.Errorf("ColumnFilterText() = %q, want %q", got, "id = 42")
+	}
+}
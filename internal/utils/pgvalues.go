@@ -0,0 +1,291 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Kinds of structured value
+const (
+	StructuredArray     = "array"
+	StructuredComposite = "composite"
+)
+
+// StructuredValue is a PostgreSQL array or composite value split into its
+// elements, or one scalar element of it
+type StructuredValue struct {
+	Kind  string            // StructuredArray, StructuredComposite, or empty for a scalar
+	Text  string            // A scalar's text, unquoted
+	Null  bool              // The scalar is NULL
+	Items []StructuredValue // Elements of an array or fields of a composite
+}
+
+// ParseStructuredValue reads a PostgreSQL array ({a,b,"c d"}) or composite
+// ((1,"Main St",)) value. Arrays are recognized by their type, which lib/pq
+// names after the element type with a leading underscore (_int4). Composites
+// are recognized by the record type, or by their shape when the type is one the
+// driver does not name, as it does not for user-defined types.
+func ParseStructuredValue(value, dbType string) (StructuredValue, bool) {
+	dbType = strings.ToLower(dbType)
+	switch {
+	case strings.HasPrefix(dbType, "_") || strings.HasSuffix(dbType, "[]"):
+		return parseArrayLiteral(value)
+	case dbType == "record":
+		return parseCompositeLiteral(value)
+	case dbType == "":
+		// Without a type, one field in parentheses is more likely text
+		if sv, ok := parseCompositeLiteral(value); ok && len(sv.Items) > 1 {
+			return sv, true
+		}
+	}
+	return StructuredValue{}, false
+}
+
+// parseArrayLiteral parses a whole array literal, with its optional
+// dimensions prefix such as [0:2]=
+func parseArrayLiteral(value string) (StructuredValue, bool) {
+	s := strings.TrimSpace(value)
+	if strings.HasPrefix(s, "[") {
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			return StructuredValue{}, false
+		}
+		s = s[eq+1:]
+	}
+	sv, rest, ok := parseArray(s)
+	if !ok || strings.TrimSpace(rest) != "" {
+		return StructuredValue{}, false
+	}
+	return sv, true
+}
+
+// parseArray parses one {…} level and returns the text after it
+func parseArray(s string) (StructuredValue, string, bool) {
+	if !strings.HasPrefix(s, "{") {
+		return StructuredValue{}, s, false
+	}
+	sv := StructuredValue{Kind: StructuredArray, Items: []StructuredValue{}}
+	s = s[1:]
+	if strings.HasPrefix(s, "}") {
+		return sv, s[1:], true
+	}
+	for {
+		var item StructuredValue
+		var ok bool
+		switch {
+		case strings.HasPrefix(s, "{"):
+			item, s, ok = parseArray(s)
+		case strings.HasPrefix(s, `"`):
+			var text string
+			text, s, ok = parseQuoted(s, false)
+			item = structuredElement(text)
+		default:
+			end := strings.IndexAny(s, ",}")
+			if end < 0 {
+				return StructuredValue{}, s, false
+			}
+			text := strings.TrimSpace(s[:end])
+			item, s, ok = StructuredValue{Text: text, Null: strings.EqualFold(text, "NULL")}, s[end:], text != ""
+		}
+		if !ok {
+			return StructuredValue{}, s, false
+		}
+		sv.Items = append(sv.Items, item)
+		switch {
+		case strings.HasPrefix(s, ","):
+			s = s[1:]
+		case strings.HasPrefix(s, "}"):
+			return sv, s[1:], true
+		default:
+			return StructuredValue{}, s, false
+		}
+	}
+}
+
+// parseCompositeLiteral parses a (…) composite value, in which an empty field
+// is NULL
+func parseCompositeLiteral(value string) (StructuredValue, bool) {
+	if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		return StructuredValue{}, false
+	}
+	sv := StructuredValue{Kind: StructuredComposite}
+	s := value[1:]
+	for {
+		var item StructuredValue
+		switch {
+		case strings.HasPrefix(s, `"`):
+			text, rest, ok := parseQuoted(s, true)
+			if !ok {
+				return StructuredValue{}, false
+			}
+			item, s = structuredElement(text), rest
+		default:
+			end := strings.IndexAny(s, ",)")
+			if end < 0 {
+				return StructuredValue{}, false
+			}
+			item, s = StructuredValue{Text: s[:end], Null: end == 0}, s[end:]
+		}
+		sv.Items = append(sv.Items, item)
+		switch {
+		case strings.HasPrefix(s, ","):
+			s = s[1:]
+		case s == ")":
+			return sv, true
+		default:
+			return StructuredValue{}, false
+		}
+	}
+}
+
+// parseQuoted reads a double-quoted element. Backslash escapes the next
+// character; composites also write a quote inside quotes as "".
+func parseQuoted(s string, doubledQuotes bool) (string, string, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"' && doubledQuotes && i+1 < len(s) && s[i+1] == '"':
+			i++
+			b.WriteByte('"')
+		case c == '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", s, false
+}
+
+// structuredElement is a quoted element, expanded when it is itself a
+// composite or array, as the elements of an array of composites are
+func structuredElement(text string) StructuredValue {
+	if sv, ok := parseCompositeLiteral(text); ok {
+		return sv
+	}
+	if sv, ok := parseArrayLiteral(text); ok && len(sv.Items) > 0 {
+		return sv
+	}
+	return StructuredValue{Text: text}
+}
+
+// StructuredInlineText renders a structured value on one line for the grid and
+// field list, arrays with their element count: [3] a, b, NULL
+func StructuredInlineText(sv StructuredValue) string {
+	if sv.Kind == StructuredArray {
+		return fmt.Sprintf("[%d] %s", len(sv.Items), structuredInline(sv.Items))
+	}
+	return inlineElement(sv)
+}
+
+func structuredInline(items []StructuredValue) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = inlineElement(item)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func inlineElement(sv StructuredValue) string {
+	switch {
+	case sv.Kind == StructuredArray:
+		return "[" + structuredInline(sv.Items) + "]"
+	case sv.Kind == StructuredComposite:
+		return "(" + structuredInline(sv.Items) + ")"
+	case sv.Null:
+		return "NULL"
+	}
+	return sv.Text
+}
+
+// FormatStructuredValue lays a structured value out one element per line,
+// nested elements indented under their parent; array elements are numbered
+// from 1 as PostgreSQL numbers them, composite fields by position
+func FormatStructuredValue(sv StructuredValue) string {
+	var b strings.Builder
+	writeStructured(&b, sv, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeStructured(b *strings.Builder, sv StructuredValue, indent string) {
+	switch sv.Kind {
+	case StructuredArray:
+		fmt.Fprintf(b, "array (%d %s)\n", len(sv.Items), pluralize(len(sv.Items), "element", "elements"))
+	case StructuredComposite:
+		fmt.Fprintf(b, "composite (%d %s)\n", len(sv.Items), pluralize(len(sv.Items), "field", "fields"))
+	default:
+		b.WriteString(inlineElement(sv) + "\n")
+		return
+	}
+	for i, item := range sv.Items {
+		label := fmt.Sprintf("[%d] ", i+1)
+		if sv.Kind == StructuredComposite {
+			label = fmt.Sprintf("#%d ", i+1)
+		}
+		b.WriteString(indent + "  " + label)
+		writeStructured(b, item, indent+"  ")
+	}
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// ApplyStructuredValues returns a copy of rows with array and composite cells
+// in their inline form. types holds the database type of each column. The
+// original rows are returned untouched when no cell is structured.
+func ApplyStructuredValues(rows [][]string, types []string) [][]string {
+	var out [][]string
+	for i, row := range rows {
+		copied := false
+		for j := 0; j < len(row) && j < len(types); j++ {
+			sv, ok := ParseStructuredValue(row[j], types[j])
+			if !ok {
+				continue
+			}
+			if out == nil {
+				out = append([][]string(nil), rows...)
+			}
+			if !copied {
+				out[i] = append([]string(nil), row...)
+				copied = true
+			}
+			out[i][j] = StructuredInlineText(sv)
+		}
+	}
+	if out == nil {
+		return rows
+	}
+	return out
+}
+
+// FieldDetailColumn returns the value and database type of the field shown in
+// the field detail view
+func FieldDetailColumn(m models.Model) (string, string) {
+	for i, col := range m.DataPreviewAllColumns {
+		if col == m.SelectedFieldForDetail && i < len(m.SelectedRowData) {
+			dbType := ""
+			if i < len(m.DataPreviewColumnTypes) {
+				dbType = m.DataPreviewColumnTypes[i]
+			}
+			return m.SelectedRowData[i], dbType
+		}
+	}
+	return "", ""
+}
+
+// FormatFieldDetail formats a value for the field detail view: arrays and
+// composites element by element, JSON pretty-printed, anything else as is
+func FormatFieldDetail(value, dbType string) string {
+	if sv, ok := ParseStructuredValue(value, dbType); ok {
+		return FormatStructuredValue(sv)
+	}
+	return FormatFieldValue(value)
+}
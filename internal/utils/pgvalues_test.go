@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructuredValue(t *testing.T) {
+	scalar := func(text string) StructuredValue { return StructuredValue{Text: text} }
+	null := StructuredValue{Text: "NULL", Null: true}
+
+	tests := []struct {
+		name   string
+		value  string
+		dbType string
+		want   StructuredValue
+		wantOK bool
+	}{
+		{"int array", "{1,2,3}", "_int4", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{scalar("1"), scalar("2"), scalar("3")}}, true},
+		{"empty array", "{}", "_text", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{}}, true},
+		{"quoted elements and NULL", `{"a b","say \"hi\"",NULL,"NULL"}`, "_text", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{scalar("a b"), scalar(`say "hi"`), null, scalar("NULL")}}, true},
+		{"nested array", "{{1,2},{3,4}}", "_int4", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{
+			{Kind: StructuredArray, Items: []StructuredValue{scalar("1"), scalar("2")}},
+			{Kind: StructuredArray, Items: []StructuredValue{scalar("3"), scalar("4")}},
+		}}, true},
+		{"dimensions prefix", "[0:1]={7,8}", "_int4", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{scalar("7"), scalar("8")}}, true},
+		{"array of composites", `{"(1,a)","(2,\"b c\")"}`, "_address", StructuredValue{Kind: StructuredArray, Items: []StructuredValue{
+			{Kind: StructuredComposite, Items: []StructuredValue{scalar("1"), scalar("a")}},
+			{Kind: StructuredComposite, Items: []StructuredValue{scalar("2"), scalar("b c")}},
+		}}, true},
+		{"record with empty field as NULL", `(42,"Main St, 1",)`, "record", StructuredValue{Kind: StructuredComposite, Items: []StructuredValue{scalar("42"), scalar("Main St, 1"), {Null: true}}}, true},
+		{"composite with doubled quote", `(1,"a ""quoted"" word")`, "", StructuredValue{Kind: StructuredComposite, Items: []StructuredValue{scalar("1"), scalar(`a "quoted" word`)}}, true},
+		{"untyped single field is text", "(note)", "", StructuredValue{}, false},
+		{"phone number is text", "(555) 123-4567", "", StructuredValue{}, false},
+		{"json is not an array", `{"a":1}`, "jsonb", StructuredValue{}, false},
+		{"malformed array", "{1,2", "_int4", StructuredValue{}, false},
+		{"typed text in braces", "{1,2}", "text", StructuredValue{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseStructuredValue(tt.value, tt.dbType)
+			if ok != tt.wantOK || (ok && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("ParseStructuredValue(%q, %q) = %+v, %v, want %+v, %v", tt.value, tt.dbType, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStructuredValueText(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		dbType     string
+		wantInline string
+		wantDetail string
+	}{
+		{"flat array", `{a,NULL,"b c"}`, "_text", "[3] a, NULL, b c", "array (3 elements)\n  [1] a\n  [2] NULL\n  [3] b c"},
+		{"nested array", "{{1,2},{3}}", "_int4", "[2] [1, 2], [3]", "array (2 elements)\n  [1] array (2 elements)\n    [1] 1\n    [2] 2\n  [2] array (1 element)\n    [1] 3"},
+		{"composite", "(42,Main St,)", "record", "(42, Main St, NULL)", "composite (3 fields)\n  #1 42\n  #2 Main St\n  #3 NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, ok := ParseStructuredValue(tt.value, tt.dbType)
+			if !ok {
+				t.Fatalf("ParseStructuredValue(%q, %q) failed", tt.value, tt.dbType)
+			}
+			if got := StructuredInlineText(sv); got != tt.wantInline {
+				t.Errorf("StructuredInlineText() = %q, want %q", got, tt.wantInline)
+			}
+			if got := FormatFieldDetail(tt.value, tt.dbType); got != tt.wantDetail {
+				t.Errorf("FormatFieldDetail() = %q, want %q", got, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestApplyStructuredValues(t *testing.T) {
+	rows := [][]string{{"1", "{a,b}"}, {"2", "NULL"}}
+	got := ApplyStructuredValues(rows, []string{"int4", "_text"})
+	if want := [][]string{{"1", "[2] a, b"}, {"2", "NULL"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyStructuredValues() = %v, want %v", got, want)
+	}
+	if rows[0][1] != "{a,b}" {
+		t.Errorf("ApplyStructuredValues() modified the stored rows: %v", rows)
+	}
+}
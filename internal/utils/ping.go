@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// SlowPingThreshold is the round trip above which the connection shows as degraded
+const SlowPingThreshold = time.Second
+
+// pingErrorWidth caps how much of a failed ping's error the indicator shows
+const pingErrorWidth = 48
+
+// PingTimeout bounds one health ping, so a hung server shows as degraded
+const PingTimeout = 5 * time.Second
+
+// ClassifyPing turns a ping's round trip and error into the connection's health.
+// A dropped connection is disconnected; a slow, timed-out, or otherwise failed
+// ping is degraded.
+func ClassifyPing(latency time.Duration, err error) models.SessionHealth {
+	switch {
+	case IsConnectionError(err):
+		return models.SessionHealthDisconnected
+	case err != nil, latency > SlowPingThreshold:
+		return models.SessionHealthDegraded
+	}
+	return models.SessionHealthConnected
+}
+
+// StartPings pings a new connection right away and then every health check
+// interval (MIRADOR_HEALTH_INTERVAL). Ticks of earlier connections are ignored.
+func StartPings(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.PingSeq++
+	updatedModel.SessionHealth = models.SessionHealthUnknown
+	updatedModel.PingError = nil
+	if m.DB == nil || config.HealthCheckInterval() <= 0 {
+		updatedModel.IsPinging = false
+		return updatedModel, nil
+	}
+	updatedModel.IsPinging = true
+	return updatedModel, PingDB(m.DB, updatedModel.PingSeq)
+}
+
+// PingDB pings the connection in the background
+func PingDB(db *sql.DB, seq int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), PingTimeout)
+		defer cancel()
+
+		start := time.Now()
+		err := db.PingContext(ctx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("ping timed out")
+		}
+		return models.PingResult{Seq: seq, Latency: time.Since(start), Err: err, At: time.Now()}
+	})
+}
+
+// SchedulePing waits one health check interval before the next ping
+func SchedulePing(seq int) tea.Cmd {
+	interval := config.HealthCheckInterval()
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return models.PingTickMsg{Seq: seq}
+	})
+}
+
+// HandlePingTick pings the connection unless it is gone or busy
+func HandlePingTick(m models.Model, msg models.PingTickMsg) (models.Model, tea.Cmd) {
+	// Pings stop with the connection; reconnecting starts them again
+	if msg.Seq != m.PingSeq || m.DB == nil || m.ConnectionLost || m.IsPinging {
+		return m, nil
+	}
+	// A running query can hold the only connection, which would read as a timeout
+	if m.IsExecutingQuery {
+		return m, SchedulePing(m.PingSeq)
+	}
+	updatedModel := m
+	updatedModel.IsPinging = true
+	return updatedModel, PingDB(m.DB, m.PingSeq)
+}
+
+// HandlePingResult records the connection's health and schedules the next
+// ping. A ping that finds the connection dropped starts reconnecting, as a
+// failed query would.
+func HandlePingResult(m models.Model, msg models.PingResult) (models.Model, tea.Cmd) {
+	if msg.Seq != m.PingSeq {
+		return m, nil
+	}
+	updatedModel := m
+	updatedModel.IsPinging = false
+	updatedModel.SessionHealth = ClassifyPing(msg.Latency, msg.Err)
+	updatedModel.PingError = msg.Err
+	updatedModel.PingedAt = msg.At
+	if msg.Err == nil {
+		updatedModel.PingLatency = msg.Latency
+	}
+
+	if updatedModel.SessionHealth == models.SessionHealthDisconnected && !m.ConnectionLost {
+		updatedModel.ConnectionLost = true
+		updatedModel.ConnectionError = msg.Err
+		updatedModel.PendingRetry = models.RetryNone
+		return ScheduleReconnect(updatedModel)
+	}
+	return updatedModel, SchedulePing(m.PingSeq)
+}
+
+// SessionHealthIndicator describes the connection's health for the tables
+// header, e.g. "● connected • 12ms". It is empty before the first ping.
+func SessionHealthIndicator(m models.Model) (string, models.SessionHealth) {
+	if m.ConnectionLost {
+		return "● disconnected", models.SessionHealthDisconnected
+	}
+	switch m.SessionHealth {
+	case models.SessionHealthConnected:
+		return "● connected • " + formatStatementDuration(m.PingLatency), m.SessionHealth
+	case models.SessionHealthDegraded:
+		if m.PingError != nil {
+			return "● degraded • " + TruncateWithEllipsis(SanitizeValueForDisplay(m.PingError.Error()), pingErrorWidth, "…"), m.SessionHealth
+		}
+		return "● degraded • slow ping " + formatStatementDuration(m.PingLatency), m.SessionHealth
+	case models.SessionHealthDisconnected:
+		return "● disconnected", m.SessionHealth
+	}
+	return "", m.SessionHealth
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestClassifyPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		err     error
+		want    models.SessionHealth
+	}{
+		{"prompt", 12 * time.Millisecond, nil, models.SessionHealthConnected},
+		{"at threshold", SlowPingThreshold, nil, models.SessionHealthConnected},
+		{"slow", 2 * time.Second, nil, models.SessionHealthDegraded},
+		{"timed out", PingTimeout, errors.New("ping timed out"), models.SessionHealthDegraded},
+		{"dropped", 3 * time.Millisecond, errors.New("write tcp 10.0.0.1:5432: broken pipe"), models.SessionHealthDisconnected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyPing(tt.latency, tt.err); got != tt.want {
+				t.Errorf("ClassifyPing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePingResult(t *testing.T) {
+	m := models.Model{PingSeq: 2, IsPinging: true}
+
+	stale, _ := HandlePingResult(m, models.PingResult{Seq: 1, Latency: time.Millisecond})
+	if stale.SessionHealth != models.SessionHealthUnknown || !stale.IsPinging {
+		t.Errorf("a ping of an earlier connection was applied: %+v", stale.SessionHealth)
+	}
+
+	ok, _ := HandlePingResult(m, models.PingResult{Seq: 2, Latency: 8 * time.Millisecond})
+	if ok.SessionHealth != models.SessionHealthConnected || ok.IsPinging || ok.PingLatency != 8*time.Millisecond {
+		t.Errorf("HandlePingResult() health = %v, pinging = %v, latency = %v", ok.SessionHealth, ok.IsPinging, ok.PingLatency)
+	}
+	if text, _ := SessionHealthIndicator(ok); text != "● connected • 8ms" {
+		t.Errorf("SessionHealthIndicator() = %q", text)
+	}
+
+	dropped, _ := HandlePingResult(m, models.PingResult{Seq: 2, Err: errors.New("connection reset by peer")})
+	if !dropped.ConnectionLost || dropped.PendingRetry != models.RetryNone {
+		t.Errorf("a dropped connection was not marked lost")
+	}
+	if text, health := SessionHealthIndicator(dropped); text != "● disconnected" || health != models.SessionHealthDisconnected {
+		t.Errorf("SessionHealthIndicator() = %q, %v", text, health)
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// PreviewCursorColumn returns the column a one-key sort applies to: the first
+// visible column, which h/l move through
+func PreviewCursorColumn(columns []string, scrollOffset int) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	return columns[Min(Max(scrollOffset, 0), len(columns)-1)]
+}
+
+// CycleColumnSort sorts by column, cycling ascending → descending → off when it
+// is already the sort column and starting ascending otherwise
+func CycleColumnSort(sortColumn string, sortDirection models.SortDirection, column string) (string, models.SortDirection) {
+	if column != sortColumn || sortDirection == models.SortOff {
+		return column, models.SortAsc
+	}
+	if sortDirection == models.SortAsc {
+		return column, models.SortDesc
+	}
+	return "", models.SortOff
+}
+
+// PreviewSortKeys lists the preview's sort keys in priority order: the sort
+// column, then the secondary keys. Keys that are off or repeat a column are skipped.
+func PreviewSortKeys(m models.Model) []models.SortKey {
+	candidates := append([]models.SortKey{{Column: m.DataPreviewSortColumn, Direction: m.DataPreviewSortDirection}}, m.DataPreviewThenBy...)
+	var keys []models.SortKey
+	seen := make(map[string]bool)
+	for _, key := range candidates {
+		if key.Column == "" || key.Direction == models.SortOff || seen[key.Column] {
+			continue
+		}
+		seen[key.Column] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SetPreviewSortKeys stores a sort stack: the first key becomes the sort column
+// and the rest are kept as secondary keys
+func SetPreviewSortKeys(m models.Model, keys []models.SortKey) models.Model {
+	m.DataPreviewSortColumn = ""
+	m.DataPreviewSortDirection = models.SortOff
+	m.DataPreviewThenBy = nil
+	if len(keys) > 0 {
+		m.DataPreviewSortColumn = keys[0].Column
+		m.DataPreviewSortDirection = keys[0].Direction
+		m.DataPreviewThenBy = append([]models.SortKey(nil), keys[1:]...)
+	}
+	return m
+}
+
+// CycleSortKey adds a column to the end of the sort stack ascending, or flips it
+// to descending, or removes it when it is already descending
+func CycleSortKey(keys []models.SortKey, column string) []models.SortKey {
+	cycled := make([]models.SortKey, 0, len(keys)+1)
+	found := false
+	for _, key := range keys {
+		if key.Column != column {
+			cycled = append(cycled, key)
+			continue
+		}
+		found = true
+		if key.Direction == models.SortAsc {
+			cycled = append(cycled, models.SortKey{Column: column, Direction: models.SortDesc})
+		}
+	}
+	if !found {
+		cycled = append(cycled, models.SortKey{Column: column, Direction: models.SortAsc})
+	}
+	return cycled
+}
+
+// FormatSortKeys renders a sort stack for the status line, e.g. "🔼 status, 🔽 created_at"
+func FormatSortKeys(keys []models.SortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		icon := "🔼"
+		if key.Direction == models.SortDesc {
+			icon = "🔽"
+		}
+		parts = append(parts, icon+" "+key.Column)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SortKeyIndicator returns the header suffix for a sorted column: its arrow, and
+// its priority when more than one column is sorted
+func SortKeyIndicator(keys []models.SortKey, column string) string {
+	for i, key := range keys {
+		if key.Column != column {
+			continue
+		}
+		arrow := " ↑"
+		if key.Direction == models.SortDesc {
+			arrow = " ↓"
+		}
+		if len(keys) > 1 {
+			return fmt.Sprintf("%s%d", arrow, i+1)
+		}
+		return arrow
+	}
+	return ""
+}
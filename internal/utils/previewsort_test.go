@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestPreviewCursorColumn(t *testing.T) {
+	columns := []string{"id", "name", "email"}
+	tests := []struct {
+		name   string
+		offset int
+		want   string
+	}{
+		{"first column", 0, "id"},
+		{"scrolled", 2, "email"},
+		{"past the end", 5, "email"},
+		{"negative", -1, "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreviewCursorColumn(columns, tt.offset); got != tt.want {
+				t.Errorf("PreviewCursorColumn(%d) = %q, want %q", tt.offset, got, tt.want)
+			}
+		})
+	}
+
+	if got := PreviewCursorColumn(nil, 0); got != "" {
+		t.Errorf("PreviewCursorColumn(nil) = %q, want empty", got)
+	}
+}
+
+func TestCycleColumnSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		sortColumn    string
+		sortDirection models.SortDirection
+		column        string
+		wantColumn    string
+		wantDirection models.SortDirection
+	}{
+		{"unsorted starts ascending", "", models.SortOff, "name", "name", models.SortAsc},
+		{"ascending becomes descending", "name", models.SortAsc, "name", "name", models.SortDesc},
+		{"descending clears the sort", "name", models.SortDesc, "name", "", models.SortOff},
+		{"another column starts ascending", "id", models.SortDesc, "name", "name", models.SortAsc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, direction := CycleColumnSort(tt.sortColumn, tt.sortDirection, tt.column)
+			if column != tt.wantColumn || direction != tt.wantDirection {
+				t.Errorf("CycleColumnSort(%q, %v, %q) = (%q, %v), want (%q, %v)", tt.sortColumn, tt.sortDirection, tt.column, column, direction, tt.wantColumn, tt.wantDirection)
+			}
+		})
+	}
+}
+
+func TestPreviewSortKeys(t *testing.T) {
+	m := models.Model{
+		DataPreviewSortColumn:    "status",
+		DataPreviewSortDirection: models.SortAsc,
+		DataPreviewThenBy: []models.SortKey{
+			{Column: "created_at", Direction: models.SortDesc},
+			{Column: "status", Direction: models.SortDesc},
+			{Column: "id", Direction: models.SortOff},
+		},
+	}
+	want := []models.SortKey{
+		{Column: "status", Direction: models.SortAsc},
+		{Column: "created_at", Direction: models.SortDesc},
+	}
+	if got := PreviewSortKeys(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("PreviewSortKeys() = %v, want %v", got, want)
+	}
+
+	// Clearing the sort column promotes the first secondary key
+	m.DataPreviewSortDirection = models.SortOff
+	m = SetPreviewSortKeys(m, PreviewSortKeys(m))
+	wantThenBy := []models.SortKey{{Column: "status", Direction: models.SortDesc}}
+	if m.DataPreviewSortColumn != "created_at" || m.DataPreviewSortDirection != models.SortDesc || !reflect.DeepEqual(m.DataPreviewThenBy, wantThenBy) {
+		t.Errorf("SetPreviewSortKeys() = (%q, %v, %v), want (created_at, desc, %v)", m.DataPreviewSortColumn, m.DataPreviewSortDirection, m.DataPreviewThenBy, wantThenBy)
+	}
+}
+
+func TestCycleSortKey(t *testing.T) {
+	status := models.SortKey{Column: "status", Direction: models.SortAsc}
+	tests := []struct {
+		name   string
+		keys   []models.SortKey
+		column string
+		want   []models.SortKey
+	}{
+		{"first key", nil, "status", []models.SortKey{status}},
+		{"appends a secondary key", []models.SortKey{status}, "created_at", []models.SortKey{status, {Column: "created_at", Direction: models.SortAsc}}},
+		{"flips to descending in place", []models.SortKey{status, {Column: "created_at", Direction: models.SortAsc}}, "status", []models.SortKey{{Column: "status", Direction: models.SortDesc}, {Column: "created_at", Direction: models.SortAsc}}},
+		{"removes a descending key", []models.SortKey{status, {Column: "created_at", Direction: models.SortDesc}}, "created_at", []models.SortKey{status}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CycleSortKey(tt.keys, tt.column); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CycleSortKey(%v, %q) = %v, want %v", tt.keys, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortKeyIndicator(t *testing.T) {
+	single := []models.SortKey{{Column: "status", Direction: models.SortAsc}}
+	stack := []models.SortKey{{Column: "status", Direction: models.SortAsc}, {Column: "created_at", Direction: models.SortDesc}}
+
+	if got := SortKeyIndicator(single, "status"); got != " ↑" {
+		t.Errorf("SortKeyIndicator(single, status) = %q, want \" ↑\"", got)
+	}
+	if got := SortKeyIndicator(stack, "created_at"); got != " ↓2" {
+		t.Errorf("SortKeyIndicator(stack, created_at) = %q, want \" ↓2\"", got)
+	}
+	if got := SortKeyIndicator(stack, "id"); got != "" {
+		t.Errorf("SortKeyIndicator(stack, id) = %q, want empty", got)
+	}
+	if got := FormatSortKeys(stack); got != "🔼 status, 🔽 created_at" {
+		t.Errorf("FormatSortKeys(stack) = %q", got)
+	}
+}
@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ExecuteQuery executes a user-provided SQL query and returns results. A script
+// with several statements runs them in order and reports each one's result. On a
+// read-only connection a script containing a write is rejected before any of it runs.
+// A statement with placeholders runs as a prepared statement with bindValues.
+// Its statements run under the running query's context, so cancelling it stops them.
+func ExecuteQuery(db *sql.DB, selectedDB models.DBType, connectionStr, migrationFile, query string, readOnly bool, bindValues map[string]string, running *models.RunningQuery) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		// Trim whitespace from query
+		query = strings.TrimSpace(query)
+		if query == "" {
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    fmt.Errorf("empty query"),
+			}
+		}
+		if err := CheckReadOnlyQuery(readOnly, query); err != nil {
+			return models.QueryResultMsg{Query: query, Err: err}
+		}
+
+		bound, args, err := BindQuery(selectedDB.Driver, query, bindValues)
+		if err != nil {
+			return models.QueryResultMsg{Query: query, Err: err}
+		}
+
+		var result models.QueryResultMsg
+		if statements := SplitStatements(query); len(statements) > 1 {
+			result = runScript(db, running, selectedDB, connectionStr, migrationFile, statements)
+		} else {
+			result = runStatement(db, running, selectedDB, connectionStr, migrationFile, bound, args...)
+		}
+		result.Query = query
+		return result
+	})
+}
+
+// runScript executes the statements of a script in order. Statements after the
+// first failure are skipped, and the failing or else the last statement's result
+// is the one shown.
+func runScript(db *sql.DB, running *models.RunningQuery, selectedDB models.DBType, connectionStr, migrationFile string, statements []string) models.QueryResultMsg {
+	results := make([]models.StatementResult, len(statements))
+	failed := -1
+	for i, stmt := range statements {
+		results[i].Query = stmt
+		if failed >= 0 {
+			results[i].Skipped = true
+			continue
+		}
+		started := time.Now()
+		results[i].Result = runStatement(db, running, selectedDB, connectionStr, migrationFile, stmt)
+		results[i].Duration = time.Since(started)
+		if results[i].Result.Err != nil {
+			failed = i
+		}
+	}
+
+	shown := len(results) - 1
+	if failed >= 0 {
+		shown = failed
+	}
+	msg := results[shown].Result
+	msg.Statements = results
+	if failed >= 0 {
+		msg.Err = fmt.Errorf("statement %d of %d failed: %w", failed+1, len(results), msg.Err)
+	}
+	return msg
+}
+
+// runStatement executes a single statement, binding args to its placeholders
+func runStatement(db *sql.DB, running *models.RunningQuery, selectedDB models.DBType, connectionStr, migrationFile, query string, args ...any) models.QueryResultMsg {
+	// A SQLite ATTACH would otherwise only hold on the pool connection it ran on
+	if selectedDB.Driver == "sqlite3" {
+		if stmt, ok, err := database.ParseAttachStatement(query); ok {
+			if err != nil {
+				return models.QueryResultMsg{Err: err}
+			}
+			return runAttachStatement(db, stmt)
+		}
+	}
+
+	// Check if it's a SELECT query (for read-only operations)
+	isSelect := strings.HasPrefix(strings.ToUpper(query), "SELECT")
+
+	// A runaway statement is cancelled once the statement timeout passes, and
+	// any statement once the user cancels the running query
+	ctx, cancel, timeout := RunningStatementContext(running, db)
+	defer cancel()
+
+	// The statement runs on one pinned connection whose session id is recorded,
+	// so cancelling it can only ever reach our own session
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return models.QueryResultMsg{Err: err}
+	}
+	defer conn.Close()
+	pid, _ := database.BackendPID(ctx, conn, selectedDB.Driver)
+	if running != nil {
+		running.SetSession(db, selectedDB.Driver, pid)
+		defer running.SetSession(nil, "", 0)
+	}
+	cancelled := func() bool { return running != nil && running.Cancelled() }
+
+	if isSelect {
+		// Execute SELECT query
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			if StatementTimedOut(ctx) {
+				err = statementTimeoutError(timeout)
+			} else if cancelled() {
+				err = cancelledStatementError(pid)
+			}
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    err,
+			}
+		}
+		defer rows.Close()
+
+		// Get column names
+		columns, err := rows.Columns()
+		if err != nil {
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    err,
+			}
+		}
+		columnTypes := database.ColumnTypeNames(rows)
+
+		// Prepare result variables
+		values := make([]interface{}, len(columns))
+		scanArgs := make([]interface{}, len(columns))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+
+		// Collect all rows
+		var allRows [][]string
+		var allNulls [][]bool
+		rowCount := 0
+		const maxRows = 1000 // Limit results to prevent memory issues
+
+		for rows.Next() && rowCount < maxRows {
+			err = rows.Scan(scanArgs...)
+			if err != nil {
+				return models.QueryResultMsg{
+					Result: "",
+					Err:    err,
+				}
+			}
+
+			row := make([]string, len(columns))
+			nulls := make([]bool, len(columns))
+			for i, val := range values {
+				row[i], nulls[i] = database.FormatValue(val)
+			}
+			allRows = append(allRows, row)
+			allNulls = append(allNulls, nulls)
+			rowCount++
+		}
+
+		// Rows received before a timeout are still shown
+		partial := StatementTimedOut(ctx)
+		if partial && len(allRows) == 0 {
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    statementTimeoutError(timeout),
+			}
+		}
+		if err = rows.Err(); err != nil && !partial {
+			if cancelled() {
+				err = cancelledStatementError(pid)
+			}
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    err,
+			}
+		}
+
+		// Create result message
+		var result string
+		if partial {
+			result = fmt.Sprintf("Partial result: the query timed out after %s and was cancelled. Showing the %d rows received before then.", FormatTimeout(timeout), len(allRows))
+		} else if len(allRows) == 0 {
+			result = "Query executed successfully. No rows returned."
+		} else {
+			if rowCount >= maxRows && models.DriverCapabilities(selectedDB.Driver).TempTables {
+				result = fmt.Sprintf("Query executed successfully. Showing first %d rows out of more results; press ctrl+t to page through all of them.", maxRows)
+			} else if rowCount >= maxRows {
+				result = fmt.Sprintf("Query executed successfully. Showing first %d rows out of more results.", maxRows)
+			} else {
+				result = fmt.Sprintf("Query executed successfully. Returned %d rows.", len(allRows))
+			}
+		}
+
+		return models.QueryResultMsg{
+			Result:      result,
+			Columns:     columns,
+			ColumnTypes: columnTypes,
+			Rows:        allRows,
+			Nulls:       allNulls,
+			Partial:     partial,
+			Err:         nil,
+		}
+
+	} else {
+		// Execute non-SELECT query (INSERT, UPDATE, DELETE)
+		result, err := conn.ExecContext(ctx, query, args...)
+		isWrite := IsWriteStatement(query)
+		if err != nil && StatementTimedOut(ctx) {
+			err = statementTimeoutError(timeout)
+		} else if err != nil && cancelled() {
+			err = cancelledStatementError(pid)
+		}
+		if err != nil {
+			if isWrite {
+				RecordAudit(selectedDB, connectionStr, "query", query, BindArgsText(args), 0, err)
+			}
+			return models.QueryResultMsg{
+				Result: "",
+				Err:    err,
+			}
+		}
+
+		// Get affected rows count
+		rowsAffected, _ := result.RowsAffected()
+		if isWrite {
+			RecordAudit(selectedDB, connectionStr, "query", query, BindArgsText(args), rowsAffected, nil)
+			RecordMigration(migrationFile, selectedDB.Driver, "query", InlineSQLArgs(selectedDB.Driver, query, args))
+		}
+
+		return models.QueryResultMsg{
+			Result:       fmt.Sprintf("Query executed successfully. %d rows affected.", rowsAffected),
+			RowsAffected: rowsAffected,
+			Err:          nil,
+		}
+	}
+}
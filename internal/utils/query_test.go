@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// openQueryTestDB opens a SQLite file with an orders table of three rows
+func openQueryTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	// Writes are appended to the audit log under the home directory
+	t.Setenv("HOME", t.TempDir())
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "query.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec("CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT); INSERT INTO orders (status) VALUES ('paid'), (NULL), ('open')"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func runQuery(db *sql.DB, query string, readOnly bool, bindValues map[string]string) models.QueryResultMsg {
+	return ExecuteQuery(db, models.DBType{Driver: "sqlite3"}, "", "", query, readOnly, bindValues, nil)().(models.QueryResultMsg)
+}
+
+func TestExecuteQuerySelect(t *testing.T) {
+	db := openQueryTestDB(t)
+
+	msg := runQuery(db, "  SELECT id, status FROM orders ORDER BY id  ", false, nil)
+	if msg.Err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", msg.Err)
+	}
+	if msg.Query != "SELECT id, status FROM orders ORDER BY id" {
+		t.Errorf("Query = %q, want it trimmed", msg.Query)
+	}
+	if len(msg.Columns) != 2 || msg.Columns[1] != "status" || len(msg.Rows) != 3 {
+		t.Fatalf("ExecuteQuery() = columns %v, rows %v", msg.Columns, msg.Rows)
+	}
+	if !msg.Nulls[1][1] || msg.Nulls[0][1] || msg.Rows[0][1] != "paid" {
+		t.Errorf("rows %v with nulls %v, want the second status NULL", msg.Rows, msg.Nulls)
+	}
+	if msg.Result != "Query executed successfully. Returned 3 rows." {
+		t.Errorf("Result = %q", msg.Result)
+	}
+
+	msg = runQuery(db, "SELECT * FROM orders WHERE id > 10", false, nil)
+	if msg.Err != nil || msg.Result != "Query executed successfully. No rows returned." {
+		t.Errorf("empty SELECT = %q, %v", msg.Result, msg.Err)
+	}
+}
+
+func TestExecuteQueryWrite(t *testing.T) {
+	db := openQueryTestDB(t)
+
+	msg := runQuery(db, "UPDATE orders SET status = 'shipped' WHERE status = :status", false, map[string]string{":status": "paid"})
+	if msg.Err != nil || msg.RowsAffected != 1 {
+		t.Fatalf("ExecuteQuery() = %d rows affected, %v; want 1", msg.RowsAffected, msg.Err)
+	}
+	var status string
+	if err := db.QueryRow("SELECT status FROM orders WHERE id = 1").Scan(&status); err != nil || status != "shipped" {
+		t.Errorf("status after the update = %q, %v", status, err)
+	}
+}
+
+func TestExecuteQueryRejects(t *testing.T) {
+	db := openQueryTestDB(t)
+
+	if msg := runQuery(db, "   ", false, nil); msg.Err == nil {
+		t.Error("ExecuteQuery() ran an empty query")
+	}
+	msg := runQuery(db, "SELECT 1; DELETE FROM orders", true, nil)
+	if msg.Err == nil || !strings.Contains(msg.Err.Error(), "read-only") {
+		t.Errorf("ExecuteQuery() on a read-only connection = %v, want the script rejected", msg.Err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM orders").Scan(&count); err != nil || count != 3 {
+		t.Errorf("%d orders left, %v; want nothing of the rejected script to run", count, err)
+	}
+}
+
+func TestExecuteQueryScript(t *testing.T) {
+	db := openQueryTestDB(t)
+
+	msg := runQuery(db, "DELETE FROM orders WHERE id = 3; SELECT count(*) FROM orders", false, nil)
+	if msg.Err != nil || len(msg.Statements) != 2 {
+		t.Fatalf("ExecuteQuery() = %v with %d statements", msg.Err, len(msg.Statements))
+	}
+	if msg.Statements[0].Result.RowsAffected != 1 || len(msg.Rows) != 1 || msg.Rows[0][0] != "2" {
+		t.Errorf("script results %+v, want the last statement's count shown", msg)
+	}
+
+	// Statements after a failure are skipped and the failing one is shown
+	msg = runQuery(db, "SELECT 1; SELECT * FROM missing; DELETE FROM orders", false, nil)
+	if msg.Err == nil || !strings.HasPrefix(msg.Err.Error(), "statement 2 of 3 failed") {
+		t.Fatalf("ExecuteQuery() error = %v, want the second statement to fail", msg.Err)
+	}
+	if msg.Statements[0].Skipped || !msg.Statements[2].Skipped {
+		t.Errorf("statements %+v, want only the one after the failure skipped", msg.Statements)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM orders").Scan(&count); err != nil || count != 2 {
+		t.Errorf("%d orders left, %v; want the skipped DELETE not to run", count, err)
+	}
+}
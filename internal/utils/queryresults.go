@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// ShowQueryResult puts a statement's result into the query runner's result area
+func ShowQueryResult(m models.Model, msg models.QueryResultMsg) models.Model {
+	updatedModel := m
+	updatedModel.QueryResultPartial = msg.Partial
+	updatedModel.QueryResultColumnTypes = msg.ColumnTypes
+	updatedModel.QueryResultsTable = table.New()
+
+	updatedModel.LastQueryColumns = nil
+	updatedModel.LastQueryRows = nil
+	updatedModel.LastQueryNulls = nil
+	updatedModel.QueryResultColumn = FirstNumericColumn(msg.ColumnTypes)
+
+	if msg.Err != nil {
+		updatedModel.Err = msg.Err
+		updatedModel.QueryResult = ""
+		return updatedModel
+	}
+	updatedModel.Err = nil
+	updatedModel.QueryResult = msg.Result
+	if len(msg.Columns) > 0 {
+		// The raw values, which the table formats and exports write as is
+		updatedModel.LastQueryColumns = msg.Columns
+		updatedModel.LastQueryRows = msg.Rows
+		updatedModel.LastQueryNulls = msg.Nulls
+	}
+
+	// A transposed view stays on for the next result while it is small enough
+	if len(msg.Rows) > TransposeMaxRows {
+		updatedModel.QueryResultTransposed = false
+	}
+	updatedModel.QueryResultsTable = QueryResultsTable(updatedModel)
+	return updatedModel
+}
+
+// QueryResultsTable builds the query runner's result table from the shown
+// result, one row per result row or, transposed, one row per column
+func QueryResultsTable(m models.Model) table.Model {
+	// Results without rows have no table
+	if len(m.LastQueryColumns) == 0 || len(m.LastQueryRows) == 0 {
+		return table.New()
+	}
+
+	resultColumns := m.LastQueryColumns
+	resultRows := ApplyStructuredValues(m.LastQueryRows, m.QueryResultColumnTypes)
+	if m.GroupNumberDigits {
+		resultRows = ApplyNumberSeparators(resultRows, m.QueryResultColumnTypes)
+	}
+	if m.QueryResultTransposed {
+		resultColumns, resultRows = TransposeRows(resultColumns, m.QueryResultColumnTypes, resultRows)
+	}
+
+	// Create table columns
+	columns := make([]table.Column, len(resultColumns))
+	for i, col := range resultColumns {
+		columns[i] = table.Column{Title: col, Width: 20}
+	}
+
+	// Create table rows
+	rows := make([]table.Row, len(resultRows))
+	for i, row := range resultRows {
+		tableRow := make(table.Row, len(row))
+		for j, cell := range row {
+			tableRow[j] = SafeDisplayText(cell)
+		}
+		rows[i] = tableRow
+	}
+	if !m.QueryResultTransposed {
+		AlignNumericCells(rows, columns, m.QueryResultColumnTypes, 0)
+	}
+
+	// Update the table
+	resultsTable := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+	resultsTable.SetStyles(styles.GetBlueTableStyles())
+	return resultsTable
+}
+
+// HandleQueryResult shows a finished query. For a script, the statement the
+// result belongs to is selected in the statement navigator.
+func HandleQueryResult(m models.Model, msg models.QueryResultMsg) models.Model {
+	updatedModel := ShowQueryResult(m, msg)
+	updatedModel.IsExecutingQuery = false
+	updatedModel = FinishRunningQuery(updatedModel)
+	updatedModel = RecordQueryHistory(updatedModel, msg)
+	updatedModel.QueryStatements = msg.Statements
+	updatedModel.QueryStatementIndex = 0
+	for i, st := range msg.Statements {
+		if !st.Skipped {
+			updatedModel.QueryStatementIndex = i
+		}
+	}
+	return updatedModel
+}
+
+// SelectQueryStatement moves the statement navigator by delta and shows the
+// chosen statement's result. Skipped statements can be selected but have no result.
+func SelectQueryStatement(m models.Model, delta int) models.Model {
+	if len(m.QueryStatements) == 0 {
+		return m
+	}
+	index := Max(0, Min(m.QueryStatementIndex+delta, len(m.QueryStatements)-1))
+	st := m.QueryStatements[index]
+
+	updatedModel := m
+	if st.Skipped {
+		updatedModel = ShowQueryResult(m, models.QueryResultMsg{Result: "Not run: an earlier statement failed."})
+	} else {
+		updatedModel = ShowQueryResult(m, st.Result)
+	}
+	updatedModel.QueryStatementIndex = index
+	return updatedModel
+}
+
+// StatementSummary describes a script statement for the navigator:
+// its status, the start of its text, how long it took, and its row count
+func StatementSummary(st models.StatementResult, width int) string {
+	query := strings.Join(strings.Fields(st.Query), " ")
+	switch {
+	case st.Skipped:
+		return "⏭ " + TruncateWithEllipsis(query, width, "…") + " • skipped"
+	case st.Result.Err != nil:
+		return fmt.Sprintf("❌ %s • %s • error", TruncateWithEllipsis(query, width, "…"), formatStatementDuration(st.Duration))
+	}
+
+	rows := fmt.Sprintf("%d rows affected", st.Result.RowsAffected)
+	if len(st.Result.Columns) > 0 {
+		rows = fmt.Sprintf("%d rows", len(st.Result.Rows))
+	}
+	status := "✅"
+	if st.Result.Partial {
+		status = "⏱"
+	}
+	return fmt.Sprintf("%s %s • %s • %s", status, TruncateWithEllipsis(query, width, "…"), formatStatementDuration(st.Duration), rows)
+}
+
+// formatStatementDuration shows sub-second timings in milliseconds
+func formatStatementDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
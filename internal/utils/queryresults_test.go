@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestStatementSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		st    models.StatementResult
+		width int
+		want  string
+	}{
+		{"select", models.StatementResult{Query: "SELECT *\n  FROM users", Duration: 12 * time.Millisecond,
+			Result: models.QueryResultMsg{Columns: []string{"id"}, Rows: [][]string{{"1"}, {"2"}}}},
+			40, "✅ SELECT * FROM users • 12ms • 2 rows"},
+		{"write", models.StatementResult{Query: "DELETE FROM users", Duration: 1500 * time.Millisecond,
+			Result: models.QueryResultMsg{RowsAffected: 3}},
+			40, "✅ DELETE FROM users • 1.5s • 3 rows affected"},
+		{"failed", models.StatementResult{Query: "SELECT nope", Duration: time.Millisecond,
+			Result: models.QueryResultMsg{Err: errors.New("column does not exist")}},
+			40, "❌ SELECT nope • 1ms • error"},
+		{"skipped", models.StatementResult{Query: "SELECT 2", Skipped: true}, 40, "⏭ SELECT 2 • skipped"},
+		{"truncated", models.StatementResult{Query: "SELECT a, b, c FROM t", Skipped: true}, 9, "⏭ SELECT a… • skipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatementSummary(tt.st, tt.width); got != tt.want {
+				t.Errorf("StatementSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
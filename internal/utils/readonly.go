@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// ReadOnlyBlocked reports an action that a read-only connection does not allow
+func ReadOnlyBlocked(action string) error {
+	return fmt.Errorf("read-only connection: %s is disabled", action)
+}
+
+// CheckReadOnlyQuery rejects a query or script that writes data or changes the
+// schema while the connection is read-only, naming the first such statement
+func CheckReadOnlyQuery(readOnly bool, query string) error {
+	if !readOnly {
+		return nil
+	}
+	statements := SplitStatements(query)
+	for i, stmt := range statements {
+		if !IsWriteStatement(stmt) {
+			continue
+		}
+		kind := statementKeyword(stmt)
+		if len(statements) > 1 {
+			return fmt.Errorf("read-only connection: statement %d (%s) is blocked", i+1, kind)
+		}
+		return fmt.Errorf("read-only connection: %s statements are blocked", kind)
+	}
+	return nil
+}
+
+// statementKeyword names what makes a statement a write, such as DELETE, CALL,
+// SELECT INTO, EXPLAIN ANALYZE, or a set_config call, looking past a leading WITH
+func statementKeyword(stmt string) string {
+	words := queryWords(stmt)
+	if len(words) == 0 {
+		return ""
+	}
+	switch {
+	case (words[0] == "SELECT" || words[0] == "WITH") && containsWord(words, "SET_CONFIG"):
+		return "set_config"
+	case words[0] == "WITH":
+		for _, w := range words[1:] {
+			if containsWord(dataModifyingKeywords, w) {
+				return w
+			}
+		}
+	case words[0] == "SELECT" && containsWord(words, "INTO"):
+		return "SELECT INTO"
+	case words[0] == "EXPLAIN" && containsWord(words, "ANALYZE", "ANALYSE"):
+		return "EXPLAIN ANALYZE"
+	}
+	return words[0]
+}
+
+// OpenDSN returns the connection string a connection is opened with. A
+// read-only SQLite file is opened read-only by SQLite as well, and the sessions
+// of a read-only PostgreSQL- or MySQL-family connection are made read-only on
+// the server, so even a statement Mirador does not recognize as a write cannot
+// change anything.
+func OpenDSN(driver, connectionStr string, readOnly, immutable bool) string {
+	switch {
+	case !readOnly:
+		return connectionStr
+	case driver == "sqlite3":
+		return database.SQLiteReadOnlyDSN(connectionStr, immutable)
+	}
+	return database.ReadOnlySessionDSN(driver, connectionStr)
+}
+
+// ConnectionDSN returns the connection string the current connection is opened with
+func ConnectionDSN(m models.Model) string {
+	return OpenDSN(m.SelectedDB.Driver, m.ConnectionStr, m.ReadOnly, m.Immutable)
+}
+
+// ToggleConnectionReadOnly turns read-only mode of a saved connection on or off
+// and saves the connections. A SQLite connection steps through read-only and
+// read-only immutable, for files on read-only media, before turning it off.
+func ToggleConnectionReadOnly(m models.Model, name string) (models.Model, error) {
+	updatedModel := m
+	updatedModel.SavedConnections = append([]models.SavedConnection(nil), m.SavedConnections...)
+	for i := range updatedModel.SavedConnections {
+		if conn := &updatedModel.SavedConnections[i]; conn.Name == name {
+			switch {
+			case !conn.ReadOnly:
+				conn.ReadOnly = true
+			case conn.Driver == "sqlite3" && !conn.Immutable:
+				conn.Immutable = true
+			default:
+				conn.ReadOnly, conn.Immutable = false, false
+			}
+			if err := config.SaveConnections(updatedModel.SavedConnections); err != nil {
+				return m, fmt.Errorf("failed to save connections: %w", err)
+			}
+			return UpdateSavedConnectionsList(updatedModel), nil
+		}
+	}
+	return m, fmt.Errorf("connection '%s' not found", name)
+}
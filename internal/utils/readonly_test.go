@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCheckReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		query    string
+		wantErr  string
+	}{
+		{"writes allowed when not read-only", false, "DELETE FROM users", ""},
+		{"select", true, "SELECT * FROM users", ""},
+		{"reads only script", true, "SELECT 1; SELECT 2", ""},
+		{"delete", true, "delete from users", "read-only connection: DELETE statements are blocked"},
+		{"ddl after comment", true, "-- cleanup\nDROP TABLE users", "read-only connection: DROP statements are blocked"},
+		{"cte update", true, "WITH t AS (SELECT 1) UPDATE users SET a = 1", "read-only connection: UPDATE statements are blocked"},
+		{"write in a script", true, "SELECT 1; INSERT INTO t VALUES (1)", "read-only connection: statement 2 (INSERT) is blocked"},
+		{"write in a literal", true, "SELECT 'x; DELETE FROM t'", ""},
+		{"procedure call", true, "CALL purge_orders()", "read-only connection: CALL statements are blocked"},
+		{"select into", true, "SELECT * INTO backup FROM users", "read-only connection: SELECT INTO statements are blocked"},
+		{"set list lifting read-only", true, "SET @x=1, @@session.transaction_read_only=0", "read-only connection: SET statements are blocked"},
+		{"set_config", true, "SELECT set_config('default_transaction_read_only', 'off', false)", "read-only connection: set_config statements are blocked"},
+		{"explain analyze", true, "EXPLAIN ANALYZE DELETE FROM users", "read-only connection: EXPLAIN ANALYZE statements are blocked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReadOnlyQuery(tt.readOnly, tt.query)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("CheckReadOnlyQuery() = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenDSN(t *testing.T) {
+	tests := []struct {
+		name      string
+		driver    string
+		dsn       string
+		readOnly  bool
+		immutable bool
+		want      string
+	}{
+		{"writable", "sqlite3", "/data/app.db", false, false, "/data/app.db"},
+		{"writable postgres", "postgres", "postgres://app@db/shop", false, false, "postgres://app@db/shop"},
+		{"postgres url", "postgres", "postgres://app@db/shop", true, false, "postgres://app@db/shop?_read_only=1"},
+		{"postgres keywords", "postgres", "host=db dbname=shop ", true, false, "host=db dbname=shop _read_only=1"},
+		{"mysql parameters kept", "mysql", "app@tcp(db)/shop?parseTime=true", true, false, "app@tcp(db)/shop?parseTime=true&_read_only=1"},
+		{"no read-only session", "clickhouse", "clickhouse://db/shop", true, false, "clickhouse://db/shop"},
+		{"read-only path", "sqlite3", "/data/app.db", true, false, "file:/data/app.db?mode=ro"},
+		{"immutable", "sqlite3", "/mnt/cdrom/app.db", true, true, "file:/mnt/cdrom/app.db?immutable=1&mode=ro"},
+		{"driver parameters kept", "sqlite3", "app.db?_busy_timeout=5000", true, false, "file:app.db?_busy_timeout=5000&mode=ro"},
+		{"uri mode replaced", "sqlite3", "file:app.db?mode=rwc", true, false, "file:app.db?mode=ro"},
+		{"uri characters escaped", "sqlite3", "backups/50%#1.db", true, false, "file:backups/50%25%231.db?mode=ro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OpenDSN(tt.driver, tt.dsn, tt.readOnly, tt.immutable); got != tt.want {
+				t.Errorf("OpenDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlySQLiteFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "50% #1.db")
+	db, err := database.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	for _, immutable := range []bool{false, true} {
+		ro, err := database.Open("sqlite3", OpenDSN("sqlite3", file, true, immutable))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		if err := ro.QueryRow("SELECT COUNT(*) FROM t").Scan(&count); err != nil {
+			t.Errorf("read with immutable=%v: %v", immutable, err)
+		}
+		if _, err := ro.Exec("INSERT INTO t VALUES (1)"); err == nil {
+			t.Errorf("write with immutable=%v succeeded on a read-only file", immutable)
+		}
+		ro.Close()
+	}
+}
+
+func TestToggleConnectionReadOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := models.Model{SavedConnections: []models.SavedConnection{
+		{Name: "local", Driver: "sqlite3", ConnectionStr: "/tmp/app.db"},
+		{Name: "prod", Driver: "postgres", ConnectionStr: "postgres://app@db/shop"},
+	}}
+	m.SavedConnectionsList = list.New(nil, list.NewDefaultDelegate(), 80, 20)
+
+	steps := []struct {
+		name          string
+		wantReadOnly  bool
+		wantImmutable bool
+	}{
+		{"local", true, false},
+		{"local", true, true},
+		{"local", false, false},
+		{"prod", true, false},
+		{"prod", false, false},
+	}
+	for i, step := range steps {
+		updated, err := ToggleConnectionReadOnly(m, step.name)
+		if err != nil {
+			t.Fatalf("step %d: ToggleConnectionReadOnly(%q) error = %v", i, step.name, err)
+		}
+		m = updated
+		for _, conn := range m.SavedConnections {
+			if conn.Name == step.name && (conn.ReadOnly != step.wantReadOnly || conn.Immutable != step.wantImmutable) {
+				t.Errorf("step %d: %s read-only %v immutable %v, want %v %v", i, step.name, conn.ReadOnly, conn.Immutable, step.wantReadOnly, step.wantImmutable)
+			}
+		}
+	}
+}
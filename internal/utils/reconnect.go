@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// connectionErrorFragments match driver messages for dropped connections when
+// the original error type has been lost through wrapping with %v
+var connectionErrorFragments = []string{
+	"broken pipe",
+	"connection reset",
+	"connection refused",
+	"bad connection",
+	"invalid connection",
+	"server closed the connection",
+	"terminating connection",
+	"no connection to the server",
+	"database is closed",
+	"unexpected eof",
+}
+
+// IsConnectionError reports whether an error means the database connection was dropped
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range connectionErrorFragments {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return msg == "eof"
+}
+
+// DetectConnectionLoss checks a result message for a dropped connection. When found,
+// it records which operation to replay and replaces the raw error with a reconnect prompt.
+func DetectConnectionLoss(m models.Model, msg tea.Msg) (models.Model, bool) {
+	var err error
+	retry := models.RetryNone
+
+	switch msg := msg.(type) {
+	case models.TablesResult:
+		err, retry = msg.Err, models.RetryTables
+	case models.DataPreviewResult:
+		err, retry = msg.Err, models.RetryPreview
+	case models.ColumnsResult:
+		err, retry = msg.Err, models.RetryColumns
+	case models.RelationshipsResult:
+		err, retry = msg.Err, models.RetryRelationships
+	case models.DatabaseOverviewResult:
+		err, retry = msg.Err, models.RetryOverview
+	case models.SlowQueriesResult:
+		err, retry = msg.Err, models.RetrySlowQueries
+	case models.ServerSettingsResult:
+		err, retry = msg.Err, models.RetrySettings
+	case models.TableGrowthResult:
+		err, retry = msg.Err, models.RetryGrowth
+	case models.SchemasResult:
+		err, retry = msg.Err, models.RetrySchemas
+	case models.QueryResultMsg:
+		err, retry = msg.Err, models.RetryQuery
+	case models.FieldUpdateResult:
+		// Writes are never replayed automatically
+		err = msg.Err
+	case models.MaintenanceResult:
+		err = msg.Err
+	default:
+		return m, false
+	}
+
+	if !IsConnectionError(err) {
+		return m, false
+	}
+
+	updatedModel := clearLoadingFlags(m)
+	updatedModel.ConnectionLost = true
+	updatedModel.ConnectionError = err
+	updatedModel.PendingRetry = retry
+	updatedModel.Err = nil
+	return updatedModel, true
+}
+
+// clearLoadingFlags resets every in-flight indicator after an operation failed
+func clearLoadingFlags(m models.Model) models.Model {
+	m.IsLoadingTables = false
+	m.IsLoadingColumns = false
+	m.IsLoadingPreview = false
+	m.IsExecutingQuery = false
+	m = FinishRunningQuery(m)
+	m.IsLoadingSchemas = false
+	m.IsLoadingOverview = false
+	m.IsRunningMaintenance = false
+	m.IsLoadingSlowQueries = false
+	m.IsLoadingSettings = false
+	m.IsLoadingGrowth = false
+	m.IsRefreshingRow = false
+	m.IsLoadingTestData = false
+	m.IsInsertingTestData = false
+	m.IsRunningDestructive = false
+	return m
+}
+
+// Reconnect opens a fresh connection pool with the current connection settings
+func Reconnect(selectedDB models.DBType, connectionStr string, timeouts models.Timeouts) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		db, err := openConnection(selectedDB.Driver, connectionStr, timeouts)
+		if err != nil {
+			return models.ReconnectResult{Err: err}
+		}
+		return models.ReconnectResult{DB: db}
+	})
+}
+
+// ScheduleReconnect starts the next automatic reconnect after a dropped
+// connection: the first right away, later ones after a backoff of 2s, 4s, ...
+// Once the connect retries (MIRADOR_CONNECT_RETRIES) are used up, reconnecting
+// is left to ctrl+r.
+func ScheduleReconnect(m models.Model) (models.Model, tea.Cmd) {
+	if !m.ConnectionLost || m.IsReconnecting || !m.ReconnectAt.IsZero() || m.ReconnectAttempt >= config.ConnectRetries() {
+		return m, nil
+	}
+
+	updatedModel := m
+	updatedModel.ReconnectAttempt++
+	if updatedModel.ReconnectAttempt == 1 {
+		updatedModel.IsReconnecting = true
+		return updatedModel, Reconnect(m.SelectedDB, ConnectionDSN(m), m.Timeouts)
+	}
+	updatedModel.ReconnectSession++
+	updatedModel.ReconnectAt = time.Now().Add(ConnectRetryDelay(updatedModel.ReconnectAttempt - 1))
+	return updatedModel, ReconnectTick(updatedModel.ReconnectSession)
+}
+
+// ReconnectTick ticks once a second while an automatic reconnect is pending
+func ReconnectTick(session int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return models.ReconnectTickMsg{Session: session}
+	})
+}
+
+// HandleReconnectTick starts the pending reconnect once its countdown ends
+func HandleReconnectTick(m models.Model, msg models.ReconnectTickMsg) (models.Model, tea.Cmd) {
+	// Ticks from a replaced countdown stop here
+	if msg.Session != m.ReconnectSession || m.ReconnectAt.IsZero() || !m.ConnectionLost {
+		return m, nil
+	}
+	if time.Now().Before(m.ReconnectAt) {
+		return m, ReconnectTick(m.ReconnectSession)
+	}
+	return ReconnectNow(m)
+}
+
+// ReconnectNow drops a pending countdown and reconnects immediately
+func ReconnectNow(m models.Model) (models.Model, tea.Cmd) {
+	if m.IsReconnecting {
+		return m, nil
+	}
+	updatedModel := m
+	updatedModel.ReconnectAt = time.Time{}
+	updatedModel.ReconnectSession++
+	updatedModel.IsReconnecting = true
+	return updatedModel, Reconnect(m.SelectedDB, ConnectionDSN(m), m.Timeouts)
+}
+
+// HandleReconnectResult swaps in the new connection and replays the operation
+// that failed, or schedules the next automatic attempt
+func HandleReconnectResult(m models.Model, msg models.ReconnectResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsReconnecting = false
+
+	if msg.Err != nil {
+		updatedModel.ConnectionError = msg.Err
+		return ScheduleReconnect(updatedModel)
+	}
+	updatedModel.ReconnectAttempt = 0
+
+	if updatedModel.DB != nil {
+		database.Close(updatedModel.DB)
+	}
+	updatedModel.DB = msg.DB
+	updatedModel.ConnectionLost = false
+	updatedModel.ConnectionError = nil
+	updatedModel.QueryResult = "✅ Reconnected"
+
+	retry := updatedModel.PendingRetry
+	updatedModel.PendingRetry = models.RetryNone
+	updatedModel, replay := ReplayOperation(updatedModel, retry)
+	updatedModel, ping := StartPings(updatedModel)
+	return updatedModel, tea.Batch(replay, ping, ClearResultAfterTimeout())
+}
+
+// ReplayOperation re-runs a read operation that failed because the connection dropped
+func ReplayOperation(m models.Model, retry models.RetryOperation) (models.Model, tea.Cmd) {
+	switch retry {
+	case models.RetryTables:
+		m.IsLoadingSchemas = true
+		return m, LoadTablesForSchema(m.DB, m.SelectedDB, m.SelectedSchema)
+	case models.RetryPreview:
+		m.IsLoadingPreview = true
+		return m, LoadDataPreview(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, PreviewSortKeys(m), PreviewJSONColumns(m))
+	case models.RetryColumns:
+		m.IsLoadingColumns = true
+		return m, LoadColumns(m.DB, m.SelectedDB, m.SelectedTable, m.SelectedSchema)
+	case models.RetryRelationships:
+		return m, LoadRelationships(m.DB, m.SelectedDB, m.SelectedSchema)
+	case models.RetryOverview:
+		m.IsLoadingOverview = true
+		return m, LoadDatabaseOverview(m.DB, m.SelectedDB, m.SelectedSchema)
+	case models.RetrySlowQueries:
+		m.IsLoadingSlowQueries = true
+		return m, LoadSlowQueries(m.DB, m.SelectedDB, m.SelectedSchema, m.SlowQuerySortByMean)
+	case models.RetrySettings:
+		m.IsLoadingSettings = true
+		return m, LoadServerSettings(m.DB, m.SelectedDB)
+	case models.RetryGrowth:
+		m.IsLoadingGrowth = true
+		return m, LoadTableGrowth(m.DB, m.SelectedDB, m.ConnectionStr, m.SelectedSchema)
+	case models.RetrySchemas:
+		m.IsLoadingSchemas = true
+		return m, LoadSchemas(m.DB, m.SelectedDB)
+	case models.RetryQuery:
+		query := strings.TrimSpace(m.QueryInput.Value())
+		if query == "" || IsWriteStatement(query) {
+			// Re-running a write could apply it twice, so leave that to the user
+			m.QueryResult = "✅ Reconnected. The write was not replayed; run it again if needed"
+			return m, nil
+		}
+		m, running := StartRunningQuery(m)
+		m.IsExecutingQuery = true
+		return m, ExecuteQuery(m.DB, m.SelectedDB, m.ConnectionStr, m.MigrationFile, query, m.ReadOnly, m.BindValues, running)
+	}
+	return m, nil
+}
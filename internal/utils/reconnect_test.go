@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad conn", driver.ErrBadConn, true},
+		{"conn done", sql.ErrConnDone, true},
+		{"wrapped eof", fmt.Errorf("query failed: %w", io.EOF), true},
+		{"net op error", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("timeout")}, true},
+		{"broken pipe text", fmt.Errorf("failed to load: %v", "write tcp 127.0.0.1:5432: broken pipe"), true},
+		{"mysql invalid connection", errors.New("invalid connection"), true},
+		{"syntax error", errors.New(`pq: syntax error at or near "SELEC"`), false},
+		{"missing table", errors.New("no such table: users"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnectionError(tt.err); got != tt.want {
+				t.Errorf("IsConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectConnectionLoss(t *testing.T) {
+	m := models.Model{IsLoadingPreview: true}
+
+	updated, lost := DetectConnectionLoss(m, models.DataPreviewResult{Err: driver.ErrBadConn})
+	if !lost {
+		t.Fatal("expected connection loss to be detected")
+	}
+	if !updated.ConnectionLost || updated.PendingRetry != models.RetryPreview || updated.IsLoadingPreview {
+		t.Errorf("unexpected model after loss: lost=%v retry=%v loading=%v",
+			updated.ConnectionLost, updated.PendingRetry, updated.IsLoadingPreview)
+	}
+
+	if _, lost := DetectConnectionLoss(m, models.DataPreviewResult{Err: errors.New("permission denied")}); lost {
+		t.Error("expected ordinary errors to pass through")
+	}
+	if _, lost := DetectConnectionLoss(m, models.ClearResultMsg{}); lost {
+		t.Error("expected unrelated messages to pass through")
+	}
+
+	updated, _ = DetectConnectionLoss(m, models.FieldUpdateResult{Err: io.EOF})
+	if updated.PendingRetry != models.RetryNone {
+		t.Error("expected writes not to be scheduled for replay")
+	}
+}
+
+func TestScheduleReconnect(t *testing.T) {
+	t.Setenv("MIRADOR_CONNECT_RETRIES", "3")
+	lost := models.Model{ConnectionLost: true}
+
+	first, cmd := ScheduleReconnect(lost)
+	if !first.IsReconnecting || first.ReconnectAttempt != 1 || !first.ReconnectAt.IsZero() || cmd == nil {
+		t.Errorf("first attempt: reconnecting=%v attempt=%d at=%v, want an immediate reconnect",
+			first.IsReconnecting, first.ReconnectAttempt, first.ReconnectAt)
+	}
+
+	first.IsReconnecting = false
+	second, cmd := ScheduleReconnect(first)
+	if wait := time.Until(second.ReconnectAt); second.ReconnectAttempt != 2 || wait <= time.Second || wait > 2*time.Second || cmd == nil {
+		t.Errorf("second attempt: attempt=%d wait=%v, want attempt 2 after about 2s", second.ReconnectAttempt, wait)
+	}
+	if again, _ := ScheduleReconnect(second); again.ReconnectAttempt != 2 {
+		t.Error("expected a pending countdown not to be scheduled twice")
+	}
+
+	exhausted := models.Model{ConnectionLost: true, ReconnectAttempt: 3}
+	if updated, cmd := ScheduleReconnect(exhausted); updated.ReconnectAttempt != 3 || cmd != nil {
+		t.Error("expected no reconnect once the retries are used up")
+	}
+	if _, cmd := ScheduleReconnect(models.Model{}); cmd != nil {
+		t.Error("expected no reconnect while the connection is up")
+	}
+}
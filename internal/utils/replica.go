@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// Endpoints that can serve an operation of a connection with a read replica
+const (
+	EndpointPrimary = "primary"
+	EndpointReplica = "replica"
+)
+
+// SupportsReadReplica reports whether connections of a driver can have a read
+// replica; SQLite files have none, and BigQuery serves every query the same way
+func SupportsReadReplica(driver string) bool {
+	return driver != "sqlite3" && driver != "bigquery"
+}
+
+// ConnectReplica opens the read replica of a saved connection
+func ConnectReplica(selectedDB models.DBType, connectionStr string, timeouts models.Timeouts) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if config.IsKeychainReference(connectionStr) {
+			return models.ReplicaConnectResult{Err: errors.New("the replica connection string was not found in the OS keychain")}
+		}
+		db, err := openConnection(selectedDB.Driver, connectionStr, timeouts)
+		if err != nil {
+			return models.ReplicaConnectResult{Err: ExplainConnectionError(selectedDB.Driver, connectionStr, err)}
+		}
+		return models.ReplicaConnectResult{DB: db}
+	})
+}
+
+// HandleReplicaConnectResult starts routing reads to the replica, or reports
+// that reads stay on the primary
+func HandleReplicaConnectResult(m models.Model, msg models.ReplicaConnectResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	if msg.Err != nil {
+		updatedModel.QueryResult = fmt.Sprintf("⚠️ Read replica unavailable, reads go to the primary: %v", msg.Err)
+		return updatedModel, ClearResultAfterTimeout()
+	}
+	// The primary was disconnected while the replica was connecting
+	if updatedModel.DB == nil {
+		database.Close(msg.DB)
+		return updatedModel, nil
+	}
+	updatedModel.ReplicaDB = msg.DB
+	updatedModel.QueryResult = "📡 Connected to the read replica: previews and SELECTs are served by it"
+	return updatedModel, ClearResultAfterTimeout()
+}
+
+// CloseReplica disconnects the read replica
+func CloseReplica(m models.Model) models.Model {
+	updatedModel := m
+	if updatedModel.ReplicaDB != nil {
+		database.Close(updatedModel.ReplicaDB)
+	}
+	updatedModel.ReplicaDB = nil
+	updatedModel.ReplicaConnectionStr = ""
+	updatedModel.LastEndpoint = ""
+	return updatedModel
+}
+
+// ReadEndpoint picks the endpoint for a read. Temporary result tables exist only
+// in the primary's session, so their previews stay on the primary.
+func ReadEndpoint(hasReplica bool, tempResultTable string) string {
+	if hasReplica && tempResultTable == "" {
+		return EndpointReplica
+	}
+	return EndpointPrimary
+}
+
+// tempResultReference matches the name of a temporary result table in a query
+var tempResultReference = regexp.MustCompile(`(?i)\bmirador_result_(\d+)\b`)
+
+// QueryEndpoint picks the endpoint for a query from the editor: a single SELECT
+// goes to the replica, and writes and scripts go to the primary. So do SELECTs
+// on one of the tempResults temporary result tables created in the session.
+func QueryEndpoint(hasReplica bool, query string, tempResults int) string {
+	if hasReplica && CanMaterializeQuery(query) && !referencesTempResult(query, tempResults) {
+		return EndpointReplica
+	}
+	return EndpointPrimary
+}
+
+// referencesTempResult reports whether a query names one of the first n
+// temporary result tables
+func referencesTempResult(query string, n int) bool {
+	for _, match := range tempResultReference.FindAllStringSubmatch(query, -1) {
+		if k, err := strconv.Atoi(match[1]); err == nil && k >= 1 && k <= n {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteRead returns the connection that serves a preview and records its endpoint
+func RouteRead(m models.Model) (models.Model, *sql.DB) {
+	return routeTo(m, ReadEndpoint(m.ReplicaDB != nil, m.TempResultTable))
+}
+
+// RouteQuery returns the connection that runs a query from the editor and records its endpoint
+func RouteQuery(m models.Model, query string) (models.Model, *sql.DB) {
+	return routeTo(m, QueryEndpoint(m.ReplicaDB != nil, query, m.TempResultCount))
+}
+
+// routeTo records the endpoint that serves an operation and returns its connection
+func routeTo(m models.Model, endpoint string) (models.Model, *sql.DB) {
+	m = servedBy(m, endpoint)
+	if m.ReplicaDB != nil && endpoint == EndpointReplica {
+		return m, m.ReplicaDB
+	}
+	return m, m.DB
+}
+
+// servedBy records the endpoint that served the last operation; without a
+// replica there is only one and nothing is recorded
+func servedBy(m models.Model, endpoint string) models.Model {
+	if m.ReplicaDB != nil {
+		m.LastEndpoint = endpoint
+	}
+	return m
+}
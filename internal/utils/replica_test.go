@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestReadEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		hasReplica bool
+		tempTable  string
+		want       string
+	}{
+		{"no replica", false, "", EndpointPrimary},
+		{"replica", true, "", EndpointReplica},
+		{"temporary result lives on the primary", true, "mirador_result_1", EndpointPrimary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadEndpoint(tt.hasReplica, tt.tempTable); got != tt.want {
+				t.Errorf("ReadEndpoint(%v, %q) = %q, want %q", tt.hasReplica, tt.tempTable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryEndpoint(t *testing.T) {
+	tests := []struct {
+		query       string
+		hasReplica  bool
+		tempResults int
+		want        string
+	}{
+		{"SELECT * FROM orders", true, 0, EndpointReplica},
+		{"WITH o AS (SELECT 1) SELECT * FROM o", true, 0, EndpointReplica},
+		{"SELECT * FROM orders", false, 0, EndpointPrimary},
+		{"UPDATE orders SET status = 'closed'", true, 0, EndpointPrimary},
+		{"SELECT 1; DELETE FROM orders", true, 0, EndpointPrimary},
+		{"CREATE TABLE t (id int)", true, 0, EndpointPrimary},
+		{"SELECT * FROM mirador_result_2", true, 2, EndpointPrimary},
+		{`SELECT * FROM orders o JOIN "MIRADOR_RESULT_1" r ON r.id = o.id`, true, 2, EndpointPrimary},
+		{"SELECT * FROM mirador_result_3", true, 2, EndpointReplica},
+		{"SELECT * FROM mirador_result_10", true, 1, EndpointReplica},
+		{"SELECT * FROM mirador_result_1_old", true, 1, EndpointReplica},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := QueryEndpoint(tt.hasReplica, tt.query, tt.tempResults); got != tt.want {
+				t.Errorf("QueryEndpoint(%v, %q, %d) = %q, want %q", tt.hasReplica, tt.query, tt.tempResults, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteQuery(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+
+	m, db := RouteQuery(models.Model{DB: primary}, "SELECT 1")
+	if db != primary || m.LastEndpoint != "" {
+		t.Errorf("without a replica got db %p and endpoint %q, want the primary and no endpoint", db, m.LastEndpoint)
+	}
+
+	m, db = RouteQuery(models.Model{DB: primary, ReplicaDB: replica}, "SELECT 1")
+	if db != replica || m.LastEndpoint != EndpointReplica {
+		t.Errorf("SELECT got endpoint %q, want %q", m.LastEndpoint, EndpointReplica)
+	}
+
+	m, db = RouteQuery(m, "DELETE FROM orders")
+	if db != primary || m.LastEndpoint != EndpointPrimary {
+		t.Errorf("DELETE got endpoint %q, want %q", m.LastEndpoint, EndpointPrimary)
+	}
+
+	m.TempResultCount = 1
+	m, db = RouteQuery(m, "SELECT * FROM mirador_result_1")
+	if db != primary || m.LastEndpoint != EndpointPrimary {
+		t.Errorf("SELECT on a temporary result got endpoint %q, want %q", m.LastEndpoint, EndpointPrimary)
+	}
+}
@@ -0,0 +1,41 @@
+package utils
+
+import "fmt"
+
+// pluralRows formats a row count with the right noun
+func pluralRows(n int) string {
+	if n == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", n)
+}
+
+// PreviewRowCountBadge describes how many rows a preview covers. With a filter the
+// matching count is shown against the whole table when the table size is known.
+func PreviewRowCountBadge(totalRows, tableRows int, filterValue string) string {
+	if filterValue == "" {
+		if totalRows == 0 {
+			return "empty"
+		}
+		return pluralRows(totalRows)
+	}
+	if tableRows > 0 {
+		return fmt.Sprintf("%d of %s match", totalRows, pluralRows(tableRows))
+	}
+	return fmt.Sprintf("%d matching", totalRows)
+}
+
+// PreviewEmptyMessage explains why a preview has no rows: the table is empty, or
+// the filter matches nothing in a table that has rows.
+func PreviewEmptyMessage(tableRows int, filterValue string) string {
+	switch {
+	case tableRows == 0:
+		return "📭 This table is empty"
+	case filterValue != "" && tableRows > 0:
+		return fmt.Sprintf("🔍 No rows match filter '%s' (the table has %s) • press c to clear the filter", filterValue, pluralRows(tableRows))
+	case filterValue != "":
+		return fmt.Sprintf("🔍 No rows match filter '%s' • press c to clear the filter", filterValue)
+	default:
+		return "📭 No rows to display"
+	}
+}
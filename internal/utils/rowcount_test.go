@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+func TestPreviewRowCountBadge(t *testing.T) {
+	tests := []struct {
+		name      string
+		totalRows int
+		tableRows int
+		filter    string
+		want      string
+	}{
+		{"empty table", 0, 0, "", "empty"},
+		{"one row", 1, 1, "", "1 row"},
+		{"many rows", 250, 250, "", "250 rows"},
+		{"filtered with table size", 3, 100, "alice", "3 of 100 rows match"},
+		{"filtered without table size", 3, -1, "alice", "3 matching"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreviewRowCountBadge(tt.totalRows, tt.tableRows, tt.filter); got != tt.want {
+				t.Errorf("PreviewRowCountBadge(%d, %d, %q) = %q, want %q", tt.totalRows, tt.tableRows, tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewEmptyMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		tableRows int
+		filter    string
+		want      string
+	}{
+		{"empty table", 0, "", "📭 This table is empty"},
+		{"empty table with a filter", 0, "alice", "📭 This table is empty"},
+		{"filter matches nothing", 42, "alice", "🔍 No rows match filter 'alice' (the table has 42 rows) • press c to clear the filter"},
+		{"filter with unknown table size", -1, "alice", "🔍 No rows match filter 'alice' • press c to clear the filter"},
+		{"unknown cause", -1, "", "📭 No rows to display"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreviewEmptyMessage(tt.tableRows, tt.filter); got != tt.want {
+				t.Errorf("PreviewEmptyMessage(%d, %q) = %q, want %q", tt.tableRows, tt.filter, got, tt.want)
+			}
+		})
+	}
+}
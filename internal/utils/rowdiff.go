@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// rowDiffFieldWidth and rowDiffNoteWidth size the fixed columns of the diff table
+const (
+	rowDiffFieldWidth = 22
+	rowDiffNoteWidth  = 26
+)
+
+// RowDiffLabel names a preview row by its primary key, or by its position when
+// the table has no key column
+func RowDiffLabel(columns, row []string, index int) string {
+	if keyColumn, keyValue, err := FindPrimaryKeyColumn(columns, row); err == nil {
+		return keyColumn + "=" + keyValue
+	}
+	return fmt.Sprintf("row %d", index+1)
+}
+
+// PreviewCursorRow returns the preview row under the cursor as a side of a row diff
+func PreviewCursorRow(m models.Model) (models.RowDiffSide, bool) {
+	cursor := m.DataPreviewTable.Cursor()
+	if cursor < 0 || cursor >= len(m.DataPreviewAllRows) {
+		return models.RowDiffSide{}, false
+	}
+	row := m.DataPreviewAllRows[cursor]
+	var nulls []bool
+	if cursor < len(m.DataPreviewNulls) {
+		nulls = append(nulls, m.DataPreviewNulls[cursor]...)
+	}
+	return models.RowDiffSide{
+		Label:   RowDiffLabel(m.DataPreviewAllColumns, row, m.DataPreviewCurrentPage*m.DataPreviewItemsPerPage+cursor),
+		Table:   m.SelectedTable,
+		Columns: append([]string(nil), m.DataPreviewAllColumns...),
+		Values:  append([]string(nil), row...),
+		Nulls:   nulls,
+	}, true
+}
+
+// ToggleDiffMark marks the preview row under the cursor for a diff, or clears
+// the mark when that row is already marked
+func ToggleDiffMark(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel := m
+	row, ok := PreviewCursorRow(m)
+	if !ok {
+		return m, nil
+	}
+	if m.RowDiffMarked.Label == row.Label && m.RowDiffMarked.Table == row.Table {
+		updatedModel.RowDiffMarked = models.RowDiffSide{}
+		updatedModel.QueryResult = "📌 Diff mark cleared"
+		return updatedModel, ClearResultAfterTimeout()
+	}
+	updatedModel.RowDiffMarked = row
+	updatedModel.QueryResult = fmt.Sprintf("📌 Marked %s • move to another row and press = to diff", row.Label)
+	return updatedModel, ClearResultAfterTimeout()
+}
+
+// StartRowDiff compares the marked row with the preview row under the cursor
+func StartRowDiff(m models.Model) (models.Model, tea.Cmd) {
+	if m.RowDiffMarked.Label == "" {
+		return SetErrorWithTimeout(m, fmt.Errorf("mark a row with m first, then press = on the row to compare it with"), 3*time.Second)
+	}
+	if m.RowDiffMarked.Table != m.SelectedTable {
+		return SetErrorWithTimeout(m, fmt.Errorf("the marked row is from %s; mark a row of %s to diff", m.RowDiffMarked.Table, m.SelectedTable), 3*time.Second)
+	}
+	right, ok := PreviewCursorRow(m)
+	if !ok {
+		return m, nil
+	}
+
+	// The marked row may be from before a reload, so line its values up by column name
+	left := m.RowDiffMarked
+	left.Values = AlignRow(right.Columns, m.RowDiffMarked.Columns, m.RowDiffMarked.Values)
+	left.Nulls = AlignNulls(right.Columns, m.RowDiffMarked.Columns, m.RowDiffMarked.Nulls)
+	left.Columns = right.Columns
+
+	updatedModel := m
+	updatedModel.RowDiffLeft = left
+	updatedModel.RowDiffRight = right
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	updatedModel = BuildRowDiffTable(updatedModel)
+	updatedModel = PushView(updatedModel, models.RowDiffView, TableParams(updatedModel))
+	return updatedModel, nil
+}
+
+// RowValuesDiffer reports whether two values of a field differ. NULL differs
+// from every value, including empty text and the text "NULL".
+func RowValuesDiffer(a, b string, aNull, bNull bool) bool {
+	if aNull || bNull {
+		return aNull != bNull
+	}
+	return a != b
+}
+
+// JSONKeyChanges lists the top-level keys added, removed, or changed between two
+// JSON objects in name order; ok is false when either value is not an object
+func JSONKeyChanges(a, b string) (keys []string, ok bool) {
+	var left, right map[string]any
+	if json.Unmarshal([]byte(a), &left) != nil || json.Unmarshal([]byte(b), &right) != nil || left == nil || right == nil {
+		return nil, false
+	}
+	for key, value := range left {
+		if other, found := right[key]; !found || !reflect.DeepEqual(value, other) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range right {
+		if _, found := left[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
+// RowDiffNote explains a difference that is hard to see in the values
+// themselves: NULL against a value, the keys that differ in JSON objects, or
+// values that differ only in whitespace or case
+func RowDiffNote(a, b string, aNull, bNull bool) string {
+	switch {
+	case aNull != bNull:
+		return "NULL on one side"
+	case a == b:
+		return ""
+	}
+	if keys, ok := JSONKeyChanges(a, b); ok {
+		if len(keys) == 0 {
+			return "same JSON, other formatting"
+		}
+		return "keys: " + strings.Join(keys, ", ")
+	}
+	switch {
+	case strings.TrimSpace(a) == strings.TrimSpace(b):
+		return "whitespace only"
+	case strings.EqualFold(a, b):
+		return "case only"
+	}
+	return ""
+}
+
+// BuildRowDiffRows lays two rows side by side, one table row per column. Fields
+// whose values differ are marked with ≠ and explained in a note where that
+// helps; with onlyChanges the fields both rows agree on are left out. It also
+// returns how many fields differ.
+func BuildRowDiffRows(left, right models.RowDiffSide, onlyChanges bool) ([]table.Row, int) {
+	var rows []table.Row
+	differing := 0
+	for i, col := range left.Columns {
+		a, aNull := diffValue(left, i)
+		b, bNull := diffValue(right, i)
+		differs := RowValuesDiffer(a, b, aNull, bNull)
+		if differs {
+			differing++
+		} else if onlyChanges {
+			continue
+		}
+
+		field, note := "  "+col, ""
+		if differs {
+			field, note = "≠ "+col, RowDiffNote(a, b, aNull, bNull)
+		}
+		rows = append(rows, table.Row{field, displayDiffValue(a, aNull), displayDiffValue(b, bNull), note})
+	}
+	return rows, differing
+}
+
+// diffValue returns a row's value of the i-th column and whether it is NULL
+func diffValue(side models.RowDiffSide, i int) (string, bool) {
+	value, null := "", false
+	if i < len(side.Values) {
+		value = side.Values[i]
+	}
+	if i < len(side.Nulls) {
+		null = side.Nulls[i]
+	}
+	return value, null
+}
+
+// displayDiffValue keeps NULL and empty text apart, as the row detail view does
+func displayDiffValue(value string, null bool) string {
+	switch {
+	case null:
+		return "(NULL)"
+	case value == "":
+		return `""`
+	}
+	return SanitizeValueForDisplay(SafeDisplayText(value))
+}
+
+// BuildRowDiffTable shows the diff of the two rows in a table sized to the screen
+func BuildRowDiffTable(m models.Model) models.Model {
+	updatedModel := m
+	valueWidth := Max((m.Width-rowDiffFieldWidth-rowDiffNoteWidth-14)/2, 12)
+	columns := []table.Column{
+		{Title: "Field", Width: rowDiffFieldWidth},
+		{Title: m.RowDiffLeft.Label, Width: valueWidth},
+		{Title: m.RowDiffRight.Label, Width: valueWidth},
+		{Title: "Note", Width: rowDiffNoteWidth},
+	}
+	rows, _ := BuildRowDiffRows(m.RowDiffLeft, m.RowDiffRight, m.RowDiffOnlyChanges)
+
+	_, v := styles.DocStyle.GetFrameSize()
+	updatedModel.RowDiffTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.RowDiffTable.SetStyles(styles.GetBlueTableStyles())
+	return updatedModel
+}
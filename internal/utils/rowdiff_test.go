@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestRowDiffLabel(t *testing.T) {
+	if got := RowDiffLabel([]string{"id", "name"}, []string{"42", "a"}, 3); got != "id=42" {
+		t.Errorf("RowDiffLabel() with a key = %q, want %q", got, "id=42")
+	}
+	if got := RowDiffLabel([]string{"name"}, []string{"a"}, 3); got != "row 4" {
+		t.Errorf("RowDiffLabel() without a key = %q, want %q", got, "row 4")
+	}
+}
+
+func TestRowValuesDiffer(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         string
+		aNull, bNull bool
+		want         bool
+	}{
+		{"equal", "x", "x", false, false, false},
+		{"different", "x", "y", false, false, true},
+		{"both NULL", "", "", true, true, false},
+		{"NULL and empty", "", "", true, false, true},
+		{"NULL and text NULL", "", "NULL", true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RowValuesDiffer(tt.a, tt.b, tt.aNull, tt.bNull); got != tt.want {
+				t.Errorf("RowValuesDiffer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONKeyChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   string
+		want   []string
+		wantOK bool
+	}{
+		{"changed added removed", `{"status":"ok","retries":1,"old":true}`, `{"status":"failed","retries":1,"new":2}`, []string{"new", "old", "status"}, true},
+		{"nested change", `{"meta":{"a":1}}`, `{"meta":{"a":2}}`, []string{"meta"}, true},
+		{"same content", `{"a":1,"b":2}`, `{"b": 2, "a": 1}`, nil, true},
+		{"not JSON", `plain`, `{"a":1}`, nil, false},
+		{"array", `[1]`, `[2]`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := JSONKeyChanges(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.wantOK {
+				t.Errorf("JSONKeyChanges() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRowDiffNote(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         string
+		aNull, bNull bool
+		want         string
+	}{
+		{"equal", "x", "x", false, false, ""},
+		{"NULL", "", "", true, false, "NULL on one side"},
+		{"json keys", `{"a":1,"b":2}`, `{"a":1,"b":3}`, false, false, "keys: b"},
+		{"json formatting", `{"a":1}`, `{ "a": 1 }`, false, false, "same JSON, other formatting"},
+		{"whitespace", "abc", "abc ", false, false, "whitespace only"},
+		{"case", "Pending", "pending", false, false, "case only"},
+		{"plain difference", "pending", "failed", false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RowDiffNote(tt.a, tt.b, tt.aNull, tt.bNull); got != tt.want {
+				t.Errorf("RowDiffNote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRowDiffRows(t *testing.T) {
+	left := models.RowDiffSide{
+		Columns: []string{"id", "status", "note", "region"},
+		Values:  []string{"1", "ok", "", "eu"},
+		Nulls:   []bool{false, false, true, false},
+	}
+	right := models.RowDiffSide{
+		Columns: []string{"id", "status", "note", "region"},
+		Values:  []string{"2", "failed", "", "eu"},
+		Nulls:   []bool{false, false, false, false},
+	}
+
+	all, differing := BuildRowDiffRows(left, right, false)
+	want := []table.Row{
+		{"≠ id", "1", "2", ""},
+		{"≠ status", "ok", "failed", ""},
+		{"≠ note", "(NULL)", `""`, "NULL on one side"},
+		{"  region", "eu", "eu", ""},
+	}
+	if !reflect.DeepEqual(all, want) || differing != 3 {
+		t.Errorf("BuildRowDiffRows() = %q, %d, want %q, 3", all, differing, want)
+	}
+
+	only, differing := BuildRowDiffRows(left, right, true)
+	if !reflect.DeepEqual(only, want[:3]) || differing != 3 {
+		t.Errorf("BuildRowDiffRows() with only changes = %q, %d, want %q, 3", only, differing, want[:3])
+	}
+}
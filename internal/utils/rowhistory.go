@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// RowHistoryLimit caps how many versions of a row are read
+const RowHistoryLimit = 20
+
+// historyTableSuffixes name the companion tables that audit triggers commonly write to
+var historyTableSuffixes = []string{"_history", "_audit", "_versions"}
+
+// historyOrderColumns are audit columns that order a history table's rows, in order of preference
+var historyOrderColumns = []string{
+	"valid_from", "changed_at", "modified_at", "updated_at", "audit_timestamp", "action_tstamp", "created_at", "timestamp",
+}
+
+// rowHistoryFieldWidth and rowHistoryVersionWidth size the side-by-side table
+const (
+	rowHistoryFieldWidth   = 20
+	rowHistoryVersionWidth = 22
+)
+
+// FindHistoryTable returns the companion history table of a table, such as
+// orders_history for orders, or "" when the schema has none
+func FindHistoryTable(table string, tables []string) string {
+	for _, suffix := range historyTableSuffixes {
+		for _, t := range tables {
+			if strings.EqualFold(t, table+suffix) {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// HistoryOrderColumn picks the audit column that orders a history table's rows, or "" if none
+func HistoryOrderColumn(columns []string) string {
+	for _, want := range historyOrderColumns {
+		for _, col := range columns {
+			if strings.EqualFold(col, want) {
+				return col
+			}
+		}
+	}
+	return ""
+}
+
+// LoadRowHistory reads the prior versions of a row by its primary key: from
+// MariaDB system versioning when the table is system-versioned, otherwise from a
+// companion history table. The row's current values close a history table's list.
+func LoadRowHistory(db *sql.DB, selectedDB models.DBType, schema, table string, tables, columns, rowData []string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		keyColumn, keyValue, err := FindPrimaryKeyColumn(columns, rowData)
+		if err != nil {
+			return models.RowHistoryResult{Err: err}
+		}
+
+		start, end, invisible, err := database.GetSystemVersionColumns(db, selectedDB.Driver, schema, table)
+		if err != nil {
+			return models.RowHistoryResult{Err: err}
+		}
+		if start != "" {
+			rs, err := database.GetSystemVersions(db, selectedDB.Driver, schema, table, start, end, invisible, keyColumn, keyValue, RowHistoryLimit)
+			if err != nil {
+				return models.RowHistoryResult{Err: fmt.Errorf("failed to read row versions: %w", err)}
+			}
+			return models.RowHistoryResult{
+				Source:   "system versioning",
+				Columns:  rs.Columns,
+				Versions: rs.Rows,
+				Labels:   versionLabels(rs.Columns, rs.Rows, start),
+			}
+		}
+
+		historyTable := FindHistoryTable(table, tables)
+		if historyTable == "" {
+			return models.RowHistoryResult{Err: fmt.Errorf("%s keeps no history: it is not system-versioned and has no %s_history or %s_audit table", table, table, table)}
+		}
+		historyColumns, err := database.GetTableColumnNames(db, selectedDB.Driver, schema, historyTable)
+		if err != nil {
+			return models.RowHistoryResult{Err: err}
+		}
+		if err := database.ValidateIdentifier("column", keyColumn, historyColumns); err != nil {
+			return models.RowHistoryResult{Err: fmt.Errorf("%s has no %s column to match rows by", historyTable, keyColumn)}
+		}
+
+		orderColumn := HistoryOrderColumn(historyColumns)
+		rs, err := database.GetHistoryTableVersions(db, selectedDB.Driver, schema, historyTable, keyColumn, keyValue, orderColumn, RowHistoryLimit)
+		if err != nil {
+			return models.RowHistoryResult{Err: fmt.Errorf("failed to read %s: %w", historyTable, err)}
+		}
+		versions := append(rs.Rows, AlignRow(rs.Columns, columns, rowData))
+		labels := append(versionLabels(rs.Columns, rs.Rows, orderColumn), "current")
+		return models.RowHistoryResult{Source: historyTable, Columns: rs.Columns, Versions: versions, Labels: labels}
+	})
+}
+
+// versionLabels heads each version with its value in labelColumn, or v1, v2, ... without one
+func versionLabels(columns []string, versions [][]string, labelColumn string) []string {
+	index := -1
+	for i, col := range columns {
+		if labelColumn != "" && col == labelColumn {
+			index = i
+		}
+	}
+	labels := make([]string, len(versions))
+	for i, version := range versions {
+		labels[i] = fmt.Sprintf("v%d", i+1)
+		if index >= 0 && index < len(version) && version[index] != "" {
+			labels[i] = version[index]
+		}
+	}
+	return labels
+}
+
+// BuildRowHistoryRows lays the versions side by side: one row per column and one
+// cell per version. A value that differs from the version before it is marked with ✎.
+func BuildRowHistoryRows(columns []string, versions [][]string) []table.Row {
+	rows := make([]table.Row, len(columns))
+	for c, col := range columns {
+		row := table.Row{col}
+		for v, version := range versions {
+			value := ""
+			if c < len(version) {
+				value = version[c]
+			}
+			if v > 0 && c < len(versions[v-1]) && versions[v-1][c] != value {
+				value = "✎ " + value
+			}
+			row = append(row, value)
+		}
+		rows[c] = row
+	}
+	return rows
+}
+
+// RowHistoryVisibleVersions returns how many version columns fit the screen width
+func RowHistoryVisibleVersions(width int) int {
+	return Max((width-rowHistoryFieldWidth-8)/(rowHistoryVersionWidth+2), 1)
+}
+
+// BuildRowHistoryTable shows the versions that fit the screen, starting at RowHistoryOffset
+func BuildRowHistoryTable(m models.Model) models.Model {
+	updatedModel := m
+	h := m.RowHistory
+	visible := RowHistoryVisibleVersions(m.Width)
+	offset := Max(0, Min(m.RowHistoryOffset, len(h.Versions)-visible))
+	end := Min(offset+visible, len(h.Versions))
+
+	columns := []table.Column{{Title: "Field", Width: rowHistoryFieldWidth}}
+	for _, label := range h.Labels[offset:end] {
+		columns = append(columns, table.Column{Title: label, Width: rowHistoryVersionWidth})
+	}
+
+	// Marks are computed over every version so the first visible column still shows its changes
+	rows := BuildRowHistoryRows(h.Columns, h.Versions)
+	for i, row := range rows {
+		rows[i] = append(table.Row{row[0]}, row[1+offset:1+end]...)
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+	updatedModel.RowHistoryOffset = offset
+	updatedModel.RowHistoryTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.RowHistoryTable.SetStyles(styles.GetBlueTableStyles())
+	return updatedModel
+}
+
+// HandleRowHistoryResult shows the versions, newest on screen, or reports why there are none
+func HandleRowHistoryResult(m models.Model, msg models.RowHistoryResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingRowHistory = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+	if len(msg.Versions) == 0 {
+		return SetErrorWithTimeout(updatedModel, fmt.Errorf("no versions of this row found in %s", msg.Source), 3*time.Second)
+	}
+
+	updatedModel.RowHistory = msg
+	updatedModel.RowHistoryOffset = len(msg.Versions)
+	updatedModel = BuildRowHistoryTable(updatedModel)
+	updatedModel = PushView(updatedModel, models.RowHistoryView, TableParams(updatedModel))
+	return updatedModel, nil
+}
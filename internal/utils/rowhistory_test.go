@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestFindHistoryTable(t *testing.T) {
+	tables := []string{"orders", "orders_audit", "users", "Users_History", "items_versions", "items_history"}
+	tests := []struct {
+		table string
+		want  string
+	}{
+		{"orders", "orders_audit"},
+		{"users", "Users_History"},
+		{"items", "items_history"},
+		{"payments", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if got := FindHistoryTable(tt.table, tables); got != tt.want {
+				t.Errorf("FindHistoryTable(%q) = %q, want %q", tt.table, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryOrderColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+	}{
+		{"prefers valid_from", []string{"id", "created_at", "valid_from"}, "valid_from"},
+		{"case-insensitive", []string{"id", "Changed_At"}, "Changed_At"},
+		{"none", []string{"id", "status"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HistoryOrderColumn(tt.columns); got != tt.want {
+				t.Errorf("HistoryOrderColumn(%v) = %q, want %q", tt.columns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRowHistoryRows(t *testing.T) {
+	columns := []string{"id", "status", "total"}
+	versions := [][]string{
+		{"7", "new", "10"},
+		{"7", "paid", "10"},
+		{"7", "paid", "12"},
+	}
+	want := []table.Row{
+		{"id", "7", "7", "7"},
+		{"status", "new", "✎ paid", "paid"},
+		{"total", "10", "10", "✎ 12"},
+	}
+	if got := BuildRowHistoryRows(columns, versions); !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildRowHistoryRows() = %v, want %v", got, want)
+	}
+}
+
+func TestVersionLabels(t *testing.T) {
+	columns := []string{"id", "row_start"}
+	versions := [][]string{{"1", "2024-01-01 10:00:00"}, {"1", ""}}
+	want := []string{"2024-01-01 10:00:00", "v2"}
+	if got := versionLabels(columns, versions, "row_start"); !reflect.DeepEqual(got, want) {
+		t.Errorf("versionLabels() = %v, want %v", got, want)
+	}
+	if got := versionLabels(columns, versions, ""); !reflect.DeepEqual(got, []string{"v1", "v2"}) {
+		t.Errorf("versionLabels() without a label column = %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// StepRowPosition moves delta rows from cursor on the current preview page. When
+// the step leaves the page it returns the page to load instead (pageStep 1 or
+// -1); ok is false at the first or last row of the whole result.
+func StepRowPosition(cursor, delta, pageRows, page, totalPages int) (next, pageStep int, ok bool) {
+	next = cursor + delta
+	switch {
+	case next >= 0 && next < pageRows:
+		return next, 0, true
+	case next < 0 && page > 0:
+		return cursor, -1, true
+	case next >= pageRows && page < totalPages-1:
+		return cursor, 1, true
+	}
+	return cursor, 0, false
+}
+
+// SelectPreviewRow shows row cursor of the current preview page in the row
+// detail view. The field list keeps its search and selected position.
+func SelectPreviewRow(m models.Model, cursor int) models.Model {
+	updatedModel := m
+	updatedModel.DataPreviewTable.SetCursor(cursor)
+	updatedModel.SelectedRowData = m.DataPreviewAllRows[cursor]
+	updatedModel.SelectedRowIndex = m.DataPreviewCurrentPage*m.DataPreviewItemsPerPage + cursor
+	updatedModel.SelectedRowNulls = nil
+	if cursor < len(m.DataPreviewNulls) {
+		updatedModel.SelectedRowNulls = m.DataPreviewNulls[cursor]
+	}
+	updatedModel.RowRefreshedAt = time.Time{}
+	updatedModel.FieldDetailScrollOffset = 0
+	updatedModel.FieldDetailHorizontalOffset = 0
+
+	selected := m.RowDetailList.Index()
+	updatedModel = RefreshRowDetailList(updatedModel)
+	updatedModel.RowDetailList.Select(selected)
+	return updatedModel
+}
+
+// StepRowDetail moves the row detail view to the next (delta 1) or previous
+// (delta -1) row of the preview, loading the neighbouring page when needed
+func StepRowDetail(m models.Model, delta int) (models.Model, tea.Cmd) {
+	if m.IsLoadingPreview || len(m.DataPreviewAllRows) == 0 {
+		return m, nil
+	}
+	totalPages := CalculateTotalPages(m.DataPreviewTotalRows, m.DataPreviewItemsPerPage)
+	next, pageStep, ok := StepRowPosition(m.DataPreviewTable.Cursor(), delta, len(m.DataPreviewAllRows), m.DataPreviewCurrentPage, totalPages)
+	if !ok {
+		m.QueryResult = "Already at the last row"
+		if delta < 0 {
+			m.QueryResult = "Already at the first row"
+		}
+		return m, ClearResultAfterTimeout()
+	}
+	if pageStep == 0 {
+		m.Err = nil
+		return SelectPreviewRow(m, next), nil
+	}
+
+	m.DataPreviewCurrentPage += pageStep
+	m.RowDetailPageStep = pageStep
+	m.IsLoadingPreview = true
+	m.Err = nil
+	routed, db := RouteRead(m)
+	return routed, LoadDataPreviewWithPagination(db, m.SelectedDB, m.SelectedTable, m.SelectedSchema, m.DataPreviewItemsPerPage, m.DataPreviewCurrentPage, PreviewSortKeys(m), m.DataPreviewFilterValue, PreviewTableColumns(m), m.DataPreviewTotalRows, PreviewJSONColumns(m))
+}
+
+// continueRowDetailStep selects the first row of a page loaded by stepping
+// forward, or the last row when stepping back, and stays in the row detail view
+func continueRowDetailStep(m models.Model) (models.Model, bool) {
+	step := m.RowDetailPageStep
+	m.RowDetailPageStep = 0
+	if step == 0 || m.State != models.RowDetailView || len(m.DataPreviewAllRows) == 0 {
+		return m, false
+	}
+	cursor := 0
+	if step < 0 {
+		cursor = len(m.DataPreviewAllRows) - 1
+	}
+	return SelectPreviewRow(m, cursor), true
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "testing"
+
+func TestStepRowPosition(t *testing.T) {
+	tests := []struct {
+		name         string
+		cursor       int
+		delta        int
+		pageRows     int
+		page         int
+		totalPages   int
+		wantNext     int
+		wantPageStep int
+		wantOK       bool
+	}{
+		{"next on page", 2, 1, 10, 0, 3, 3, 0, true},
+		{"previous on page", 2, -1, 10, 0, 3, 1, 0, true},
+		{"next crosses page", 9, 1, 10, 0, 3, 9, 1, true},
+		{"previous crosses page", 0, -1, 10, 1, 3, 0, -1, true},
+		{"first row of result", 0, -1, 10, 0, 3, 0, 0, false},
+		{"last row of result", 4, 1, 5, 2, 3, 4, 0, false},
+		{"single page", 9, 1, 10, 0, 1, 9, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, pageStep, ok := StepRowPosition(tt.cursor, tt.delta, tt.pageRows, tt.page, tt.totalPages)
+			if next != tt.wantNext || pageStep != tt.wantPageStep || ok != tt.wantOK {
+				t.Errorf("StepRowPosition() = (%d, %d, %v), want (%d, %d, %v)", next, pageStep, ok, tt.wantNext, tt.wantPageStep, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// RefreshRow re-fetches the selected row by its primary key
+func RefreshRow(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string, columns, rowData []string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		keyColumn, keyValue, err := FindPrimaryKeyColumn(columns, rowData)
+		if err != nil {
+			return models.RowRefreshResult{Err: fmt.Errorf("cannot refresh row: %w", err)}
+		}
+
+		cols, row, nulls, err := database.GetRowByKey(db, selectedDB.Driver, selectedTable, selectedSchema, keyColumn, keyValue)
+		return models.RowRefreshResult{Columns: cols, Row: row, Nulls: nulls, Err: err}
+	})
+}
+
+// AlignRow orders fetched values to match the given column list by name.
+// Columns missing from the fetched row are left empty.
+func AlignRow(columns, fetchedColumns, row []string) []string {
+	index := make(map[string]int, len(fetchedColumns))
+	for i, col := range fetchedColumns {
+		index[col] = i
+	}
+
+	aligned := make([]string, len(columns))
+	for i, col := range columns {
+		if j, ok := index[col]; ok && j < len(row) {
+			aligned[i] = row[j]
+		}
+	}
+	return aligned
+}
+
+// AlignNulls orders a fetched row's NULL marks the same way AlignRow orders its values
+func AlignNulls(columns, fetchedColumns []string, nulls []bool) []bool {
+	index := make(map[string]int, len(fetchedColumns))
+	for i, col := range fetchedColumns {
+		index[col] = i
+	}
+
+	aligned := make([]bool, len(columns))
+	for i, col := range columns {
+		if j, ok := index[col]; ok && j < len(nulls) {
+			aligned[i] = nulls[j]
+		}
+	}
+	return aligned
+}
+
+// CountChangedFields returns how many positions differ between two rows
+func CountChangedFields(before, after []string) int {
+	changed := 0
+	for i := 0; i < len(before) || i < len(after); i++ {
+		if i >= len(before) || i >= len(after) || before[i] != after[i] {
+			changed++
+		}
+	}
+	return changed
+}
+
+// HandleRowRefreshResult replaces the selected row with its freshly fetched values
+func HandleRowRefreshResult(m models.Model, msg models.RowRefreshResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsRefreshingRow = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+	if msg.Row == nil {
+		return SetErrorWithTimeout(updatedModel, fmt.Errorf("row no longer exists (deleted or key changed)"), 3*time.Second)
+	}
+
+	row := AlignRow(m.DataPreviewAllColumns, msg.Columns, msg.Row)
+	nulls := AlignNulls(m.DataPreviewAllColumns, msg.Columns, msg.Nulls)
+	// A refresh reads the table's columns only; JSON path columns keep their values
+	for i, column := range m.DataPreviewAllColumns {
+		if IsJSONColumn(m, column) && i < len(m.SelectedRowData) {
+			row[i] = m.SelectedRowData[i]
+			if i < len(m.SelectedRowNulls) && i < len(nulls) {
+				nulls[i] = m.SelectedRowNulls[i]
+			}
+		}
+	}
+	changed := CountChangedFields(m.SelectedRowData, row)
+	updatedModel.SelectedRowData = row
+	updatedModel.SelectedRowNulls = nulls
+	updatedModel.RowRefreshedAt = time.Now()
+
+	// Keep the preview page in sync without losing the cursor position
+	cursor := m.DataPreviewTable.Cursor()
+	if cursor >= 0 && cursor < len(m.DataPreviewAllRows) {
+		updatedModel.DataPreviewAllRows[cursor] = row
+		if cursor < len(m.DataPreviewNulls) {
+			updatedModel.DataPreviewNulls[cursor] = nulls
+		}
+		updatedModel = CreateDataPreviewTable(updatedModel)
+		updatedModel.DataPreviewTable.SetCursor(cursor)
+	}
+
+	selected := m.RowDetailList.Index()
+	updatedModel = RefreshRowDetailList(updatedModel)
+	updatedModel.RowDetailList.Select(selected)
+
+	updatedModel.QueryResult = fmt.Sprintf("🔄 Row refreshed at %s • %d field(s) changed",
+		updatedModel.RowRefreshedAt.Format("15:04:05"), changed)
+	return updatedModel, ClearResultAfterTimeout()
+}
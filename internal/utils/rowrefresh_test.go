@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlignRow(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		fetched []string
+		row     []string
+		want    []string
+	}{
+		{"same order", []string{"id", "status"}, []string{"id", "status"}, []string{"7", "done"}, []string{"7", "done"}},
+		{"reordered", []string{"id", "status"}, []string{"status", "id"}, []string{"done", "7"}, []string{"7", "done"}},
+		{"column dropped", []string{"id", "status", "note"}, []string{"id", "status"}, []string{"7", "done"}, []string{"7", "done", ""}},
+		{"extra column ignored", []string{"id"}, []string{"id", "added"}, []string{"7", "x"}, []string{"7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AlignRow(tt.columns, tt.fetched, tt.row); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AlignRow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlignNulls(t *testing.T) {
+	got := AlignNulls([]string{"id", "note", "gone"}, []string{"note", "id"}, []bool{true, false})
+	if want := []bool{false, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AlignNulls() = %v, want %v", got, want)
+	}
+}
+
+func TestCountChangedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+		want   int
+	}{
+		{"unchanged", []string{"1", "queued"}, []string{"1", "queued"}, 0},
+		{"status flipped", []string{"1", "queued"}, []string{"1", "running"}, 1},
+		{"length differs", []string{"1"}, []string{"1", "x"}, 1},
+		{"both empty", nil, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountChangedFields(tt.before, tt.after); got != tt.want {
+				t.Errorf("CountChangedFields() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
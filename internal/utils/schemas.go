@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// LoadSchemas loads the schemas (PostgreSQL) or databases (MySQL) available to browse
+func LoadSchemas(db *sql.DB, selectedDB models.DBType) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		schemas, err := database.GetSchemas(db, selectedDB.Driver)
+		return models.SchemasResult{Schemas: schemas, Err: err}
+	})
+}
+
+// LoadTablesForSchema loads the table list of another schema or database
+func LoadTablesForSchema(db *sql.DB, selectedDB models.DBType, schema string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		tables, err := database.GetTables(db, selectedDB.Driver, schema)
+		if err != nil {
+			return models.TablesResult{Schema: schema, Err: err}
+		}
+		foreign, _ := database.GetForeignTables(db, selectedDB.Driver, schema)
+		persistence, _ := database.GetTablePersistence(db, selectedDB.Driver, schema)
+		return models.TablesResult{Tables: tables, Foreign: foreign, Persistence: persistence, Schema: schema}
+	})
+}
+
+// HandleSchemasResult processes schemas result and updates model
+func HandleSchemasResult(m models.Model, msg models.SchemasResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingSchemas = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	updatedModel.Schemas = msg.Schemas
+	items := make([]list.Item, len(msg.Schemas))
+	for i, schema := range msg.Schemas {
+		desc := schema.Description
+		if schema.Name == m.SelectedSchema {
+			desc = fmt.Sprintf("%s • selected", desc)
+		}
+		items[i] = models.Item{ItemTitle: schema.Name, ItemDesc: desc}
+	}
+	updatedModel.SchemasList.SetItems(items)
+	updatedModel = PushView(updatedModel, models.SchemaView, models.NavParams{})
+	return updatedModel, nil
+}
+
+// HandleTablesResult processes the table list of a newly selected schema and updates model
+func HandleTablesResult(m models.Model, msg models.TablesResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingSchemas = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	updatedModel.SelectedSchema = msg.Schema
+	updatedModel.Tables = msg.Tables
+	sort.Strings(updatedModel.Tables)
+	updatedModel.TableInfos = CreateTableInfos(updatedModel.Tables, updatedModel.SelectedSchema, msg.Foreign, msg.Persistence)
+	updatedModel.FavoriteTables = LoadFavoriteTables(updatedModel.SelectedDB.Driver, updatedModel.ConnectionStr, updatedModel.SelectedSchema)
+	updatedModel.TableNotes = LoadTableNotes(updatedModel.SelectedDB.Driver, updatedModel.ConnectionStr, updatedModel.SelectedSchema)
+	updatedModel.TablesList.SetItems(CreateTableListItems(updatedModel.TableInfos, updatedModel.FavoriteTables, updatedModel.TableNotes))
+	updatedModel.TablesList.ResetSelected()
+	updatedModel.SelectedTable = ""
+	updatedModel = ResetView(updatedModel, models.TablesView, models.NavParams{Schema: updatedModel.SelectedSchema})
+	return updatedModel, nil
+}
@@ -0,0 +1,294 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// scriptedResult is what a scripted connection answers a statement with
+type scriptedResult struct {
+	rows [][]driver.Value
+	err  error
+}
+
+// scriptedConnector opens connections that answer each statement with the
+// result of the first key, in order, that the statement contains, and record
+// every statement with its arguments. Metadata queries for drivers that cannot
+// run here are checked through it.
+type scriptedConnector struct {
+	keys    []string
+	results map[string]scriptedResult
+
+	mu         sync.Mutex
+	statements []string
+}
+
+// scriptedDB opens a pool on a scripted connector; script alternates keys and
+// their results
+func scriptedDB(t *testing.T, script ...interface{}) (*sql.DB, *scriptedConnector) {
+	c := &scriptedConnector{results: map[string]scriptedResult{}}
+	for i := 0; i < len(script); i += 2 {
+		key := script[i].(string)
+		c.keys = append(c.keys, key)
+		c.results[key] = script[i+1].(scriptedResult)
+	}
+	db := sql.OpenDB(c)
+	t.Cleanup(func() { db.Close() })
+	return db, c
+}
+
+// sent returns the statements received so far
+func (c *scriptedConnector) sent() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.statements...)
+}
+
+func (c *scriptedConnector) Connect(context.Context) (driver.Conn, error) {
+	return scriptedConn{c}, nil
+}
+
+func (c *scriptedConnector) Driver() driver.Driver { return nil }
+
+type scriptedConn struct{ c *scriptedConnector }
+
+func (conn scriptedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("scripted connections do not prepare statements")
+}
+
+func (conn scriptedConn) Close() error { return nil }
+
+func (conn scriptedConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("scripted connections have no transactions")
+}
+
+func (conn scriptedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	statement := query
+	for _, arg := range args {
+		statement += fmt.Sprintf(" [%v]", arg.Value)
+	}
+	conn.c.mu.Lock()
+	conn.c.statements = append(conn.c.statements, statement)
+	conn.c.mu.Unlock()
+
+	for _, key := range conn.c.keys {
+		if strings.Contains(query, key) {
+			result := conn.c.results[key]
+			if result.err != nil {
+				return nil, result.err
+			}
+			return &scriptedRows{rows: result.rows}, nil
+		}
+	}
+	return nil, fmt.Errorf("unscripted statement %q", query)
+}
+
+type scriptedRows struct {
+	rows [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"c1", "c2", "c3"}
+	}
+	columns := make([]string, len(r.rows[0]))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("c%d", i+1)
+	}
+	return columns
+}
+
+func (r *scriptedRows) Close() error { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// schemaRows scripts name and description rows
+func schemaRows(pairs ...string) scriptedResult {
+	var rows [][]driver.Value
+	for i := 0; i < len(pairs); i += 2 {
+		rows = append(rows, []driver.Value{pairs[i], pairs[i+1]})
+	}
+	return scriptedResult{rows: rows}
+}
+
+func TestLoadSchemas(t *testing.T) {
+	failed := scriptedResult{err: errors.New("permission denied")}
+
+	tests := []struct {
+		name    string
+		driver  string
+		script  []interface{}
+		wantSQL []string // fragments of the schema query
+		want    []string // name • description of each schema
+		wantErr bool
+	}{
+		{"postgres", "postgres",
+			[]interface{}{"information_schema.schemata", schemaRows("public", "Default public schema", "billing", "User schema")},
+			[]string{"FROM information_schema.schemata", "NOT IN ('information_schema', 'pg_catalog', 'pg_toast')"},
+			[]string{"public • Default public schema", "billing • User schema"}, false},
+		{"postgres falls back to public on error", "postgres",
+			[]interface{}{"information_schema.schemata", failed},
+			nil, []string{"public • Default public schema"}, false},
+		{"postgres with no visible schemas", "postgres",
+			[]interface{}{"information_schema.schemata", schemaRows()},
+			nil, []string{"public • Default public schema"}, false},
+		{"cockroach hides its virtual schemas", "cockroach",
+			[]interface{}{"information_schema.schemata", schemaRows("public", "Default public schema")},
+			[]string{"'pg_extension', 'crdb_internal'"}, []string{"public • Default public schema"}, false},
+		{"redshift hides its system schemas", "redshift",
+			[]interface{}{"information_schema.schemata", schemaRows("public", "Default public schema")},
+			[]string{"'pg_internal', 'pg_automv', 'catalog_history'"}, []string{"public • Default public schema"}, false},
+		{"mysql lists databases, current first", "mysql",
+			[]interface{}{"INFORMATION_SCHEMA.SCHEMATA", schemaRows("shop", "Current database", "archive", "Database")},
+			[]string{"NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')", "ORDER BY CASE WHEN SCHEMA_NAME = DATABASE() THEN 0"},
+			[]string{"shop • Current database", "archive • Database"}, false},
+		{"mariadb shares the mysql query", "mariadb",
+			[]interface{}{"INFORMATION_SCHEMA.SCHEMATA", schemaRows("shop", "Current database")},
+			[]string{"FROM INFORMATION_SCHEMA.SCHEMATA"}, []string{"shop • Current database"}, false},
+		{"mysql error", "mysql",
+			[]interface{}{"INFORMATION_SCHEMA.SCHEMATA", failed}, nil, nil, true},
+		{"clickhouse lists databases", "clickhouse",
+			[]interface{}{"system.databases", schemaRows("default", "Current database")},
+			[]string{"FROM system.databases", "currentDatabase()"}, []string{"default • Current database"}, false},
+		{"sqlite lists main and attached databases", "sqlite3",
+			[]interface{}{"PRAGMA database_list", scriptedResult{rows: [][]driver.Value{
+				{int64(0), "main", "/data/app.db"}, {int64(1), "temp", ""}, {int64(2), "scratch", ""},
+			}}},
+			[]string{"PRAGMA database_list"},
+			[]string{"main • Main database • /data/app.db", "scratch • Attached database • in memory"}, false},
+		{"trino lists the schemas of each catalog", "trino",
+			[]interface{}{
+				"SHOW CATALOGS", scriptedResult{rows: [][]driver.Value{{"hive"}, {"offline"}}},
+				`"hive".information_schema.schemata`, scriptedResult{rows: [][]driver.Value{{"web"}}},
+				`"offline".information_schema.schemata`, failed,
+			},
+			[]string{"SHOW CATALOGS", `FROM "hive".information_schema.schemata WHERE schema_name <> 'information_schema'`},
+			[]string{"hive.web • Schema • hive catalog"}, false},
+		{"snowflake lists the schemas of the database", "snowflake",
+			[]interface{}{"INFORMATION_SCHEMA.SCHEMATA", schemaRows("PUBLIC", "Current schema", "STAGING", "Schema")},
+			[]string{"WHERE SCHEMA_NAME <> 'INFORMATION_SCHEMA'", "CURRENT_SCHEMA()"},
+			[]string{"PUBLIC • Current schema", "STAGING • Schema"}, false},
+		{"drivers without schemas", "oracle", nil, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, conn := scriptedDB(t, tt.script...)
+			msg := LoadSchemas(db, models.DBType{Driver: tt.driver})().(models.SchemasResult)
+			if (msg.Err != nil) != tt.wantErr {
+				t.Fatalf("LoadSchemas() error = %v, wantErr %v", msg.Err, tt.wantErr)
+			}
+			var got []string
+			for _, schema := range msg.Schemas {
+				got = append(got, schema.Name+" • "+schema.Description)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadSchemas() = %q, want %q", got, tt.want)
+			}
+			sent := strings.Join(conn.sent(), "\n")
+			for _, fragment := range tt.wantSQL {
+				if !strings.Contains(sent, fragment) {
+					t.Errorf("schema query does not contain %q:\n%s", fragment, sent)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTablesForSchema(t *testing.T) {
+	tables := scriptedResult{rows: [][]driver.Value{{"orders"}, {"customers"}}}
+
+	tests := []struct {
+		driver  string
+		schema  string
+		wantSQL string // the table query, followed by its arguments
+	}{
+		{"postgres", "billing", "WHERE schemaname = $1"},
+		{"mysql", "archive", "WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) [archive]"},
+		{"clickhouse", "logs", "COALESCE(NULLIF(?, ''), currentDatabase()) AND NOT is_temporary ORDER BY name [logs]"},
+		{"sqlite3", "scratch", `FROM "scratch".sqlite_master WHERE type='table'`},
+		{"trino", "hive.web", `FROM "hive".information_schema.tables WHERE table_schema = COALESCE(NULLIF(?, ''), current_schema) ORDER BY table_name [web]`},
+		{"bigquery", "sales", "SELECT table_name FROM `sales`.INFORMATION_SCHEMA.TABLES ORDER BY table_name"},
+		{"snowflake", "STAGING", "FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), CURRENT_SCHEMA()) ORDER BY TABLE_NAME [STAGING]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			db, conn := scriptedDB(t, "SELECT", tables)
+			msg := LoadTablesForSchema(db, models.DBType{Driver: tt.driver}, tt.schema)().(models.TablesResult)
+			if msg.Err != nil || msg.Schema != tt.schema || !reflect.DeepEqual(msg.Tables, []string{"orders", "customers"}) {
+				t.Errorf("LoadTablesForSchema() = %+v, want orders and customers of %s", msg, tt.schema)
+			}
+			if sent := conn.sent(); len(sent) == 0 || !strings.Contains(sent[0], tt.wantSQL) {
+				t.Errorf("table query = %q, want it to contain %q", sent, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestHandleSchemasResult(t *testing.T) {
+	m := models.Model{State: models.TablesView, SelectedSchema: "billing"}
+	m.SchemasList = list.New(nil, list.NewDefaultDelegate(), 80, 20)
+	m.IsLoadingSchemas = true
+
+	got, _ := HandleSchemasResult(m, models.SchemasResult{Schemas: []models.SchemaInfo{
+		{Name: "public", Description: "Default public schema"},
+		{Name: "billing", Description: "User schema"},
+	}})
+	if got.IsLoadingSchemas || got.State != models.SchemaView {
+		t.Errorf("HandleSchemasResult() loading = %v, state = %v, want the schema view", got.IsLoadingSchemas, got.State)
+	}
+	var descriptions []string
+	for _, item := range got.SchemasList.Items() {
+		descriptions = append(descriptions, item.(models.Item).ItemDesc)
+	}
+	if want := []string{"Default public schema", "User schema • selected"}; !reflect.DeepEqual(descriptions, want) {
+		t.Errorf("HandleSchemasResult() descriptions = %q, want %q", descriptions, want)
+	}
+
+	got, _ = HandleSchemasResult(m, models.SchemasResult{Err: errors.New("permission denied")})
+	if got.Err == nil || got.State != models.TablesView {
+		t.Errorf("HandleSchemasResult(error) = %v in %v, want the error in the tables view", got.Err, got.State)
+	}
+}
+
+func TestHandleTablesResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // Favorites and notes are read for the new schema
+	m := models.Model{State: models.SchemaView, SelectedDB: models.DBType{Driver: "mysql"}, SelectedSchema: "shop", SelectedTable: "orders"}
+	m.TablesList = list.New(nil, list.NewDefaultDelegate(), 80, 20)
+	m.IsLoadingSchemas = true
+
+	got, _ := HandleTablesResult(m, models.TablesResult{Schema: "archive", Tables: []string{"orders_2023", "customers"}})
+	if got.SelectedSchema != "archive" || got.SelectedTable != "" || got.State != models.TablesView || got.IsLoadingSchemas {
+		t.Errorf("HandleTablesResult() schema = %q, table = %q, state = %v, want archive's tables view", got.SelectedSchema, got.SelectedTable, got.State)
+	}
+	if want := []string{"customers", "orders_2023"}; !reflect.DeepEqual(got.Tables, want) {
+		t.Errorf("HandleTablesResult() tables = %v, want %v", got.Tables, want)
+	}
+	if got.NavParams.Schema != "archive" {
+		t.Errorf("HandleTablesResult() nav schema = %q, want archive", got.NavParams.Schema)
+	}
+
+	got, _ = HandleTablesResult(m, models.TablesResult{Schema: "archive", Err: errors.New("access denied")})
+	if got.Err == nil || got.SelectedSchema != "shop" {
+		t.Errorf("HandleTablesResult(error) = %v with schema %q, want the error and shop kept", got.Err, got.SelectedSchema)
+	}
+}
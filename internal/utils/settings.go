@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// FilterServerSettings returns settings whose name, value, or description contain term
+// (case-insensitive), optionally restricted to settings that differ from their defaults
+func FilterServerSettings(settings []models.ServerSetting, term string, onlyChanged bool) []models.ServerSetting {
+	term = strings.ToLower(strings.TrimSpace(term))
+	var filtered []models.ServerSetting
+	for _, s := range settings {
+		if onlyChanged && !s.Modified {
+			continue
+		}
+		if term != "" &&
+			!strings.Contains(strings.ToLower(s.Name), term) &&
+			!strings.Contains(strings.ToLower(s.Value), term) &&
+			!strings.Contains(strings.ToLower(s.Description), term) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+// BuildServerSettingRows converts server settings into table rows
+func BuildServerSettingRows(settings []models.ServerSetting) []table.Row {
+	rows := make([]table.Row, len(settings))
+	for i, s := range settings {
+		changed := ""
+		if s.Modified {
+			changed = "●"
+		}
+		rows[i] = table.Row{changed, s.Name, s.Value, s.Unit, s.Default, s.Description}
+	}
+	return rows
+}
+
+// LoadServerSettings loads the server configuration variables
+func LoadServerSettings(db *sql.DB, selectedDB models.DBType) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		settings, err := database.GetServerSettings(db, selectedDB.Driver)
+		return models.ServerSettingsResult{Settings: settings, Err: err}
+	})
+}
+
+// RefreshServerSettingsTable rebuilds the settings table from the current search and toggle
+func RefreshServerSettingsTable(m models.Model) models.Model {
+	updatedModel := m
+	updatedModel.FilteredServerSettings = FilterServerSettings(m.ServerSettings, m.SearchInput.Value(), m.ServerSettingsOnlyChanged)
+
+	h, v := styles.DocStyle.GetFrameSize()
+	descWidth := Max(m.Width-h-100, 20)
+	columns := []table.Column{
+		{Title: "Δ", Width: 2},
+		{Title: "Name", Width: 32},
+		{Title: "Value", Width: 24},
+		{Title: "Unit", Width: 6},
+		{Title: "Default", Width: 20},
+		{Title: "Description", Width: descWidth},
+	}
+
+	updatedModel.ServerSettingsTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildServerSettingRows(updatedModel.FilteredServerSettings)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.ServerSettingsTable.SetStyles(styles.GetBlueTableStyles())
+	return updatedModel
+}
+
+// HandleServerSettingsResult processes server settings result and updates model
+func HandleServerSettingsResult(m models.Model, msg models.ServerSettingsResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingSettings = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	updatedModel.ServerSettings = msg.Settings
+	updatedModel = RefreshServerSettingsTable(updatedModel)
+	updatedModel = PushView(updatedModel, models.ServerSettingsView, models.NavParams{})
+	return updatedModel, nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFilterServerSettings(t *testing.T) {
+	settings := []models.ServerSetting{
+		{Name: "work_mem", Value: "4096", Unit: "kB", Default: "4096"},
+		{Name: "shared_buffers", Value: "16384", Unit: "8kB", Default: "1024", Modified: true},
+		{Name: "sql_mode", Value: "STRICT_TRANS_TABLES", Description: "SQL modes"},
+	}
+
+	tests := []struct {
+		name        string
+		term        string
+		onlyChanged bool
+		want        []string
+	}{
+		{"no filter", "", false, []string{"work_mem", "shared_buffers", "sql_mode"}},
+		{"by name", "MEM", false, []string{"work_mem"}},
+		{"by value", "strict", false, []string{"sql_mode"}},
+		{"by description", "modes", false, []string{"sql_mode"}},
+		{"only changed", "", true, []string{"shared_buffers"}},
+		{"changed and term", "work", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterServerSettings(settings, tt.term, tt.onlyChanged)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FilterServerSettings() returned %d settings, want %d", len(got), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if got[i].Name != name {
+					t.Errorf("setting %d = %q, want %q", i, got[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildServerSettingRows(t *testing.T) {
+	rows := BuildServerSettingRows([]models.ServerSetting{
+		{Name: "work_mem", Value: "8192", Unit: "kB", Default: "4096", Modified: true},
+	})
+	want := []string{"●", "work_mem", "8192", "kB", "4096", ""}
+	for i, cell := range want {
+		if rows[0][i] != cell {
+			t.Errorf("cell %d = %q, want %q", i, rows[0][i], cell)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+// SupportsSQLCipher reports whether the driver opens files that can be
+// encrypted with SQLCipher and this build can be given their key, so the
+// connection form only asks for one when it can be used
+func SupportsSQLCipher(driver string) bool {
+	return driver == "sqlite3" && database.SQLCipherAvailable
+}
+
+// ApplySQLCipherKey writes the connection form's SQLCipher key into a SQLite
+// connection string, replacing a key it already has. An empty key changes nothing.
+func ApplySQLCipherKey(connectionStr, key string) string {
+	if key == "" || connectionStr == "" {
+		return connectionStr
+	}
+	path, query, _ := strings.Cut(connectionStr, "?")
+	return joinQuery(path, replaceQueryParams(query, [][2]string{{database.SQLCipherKeyParam, key}}))
+}
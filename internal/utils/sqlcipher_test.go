@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+func TestSupportsSQLCipher(t *testing.T) {
+	// The form only offers the key in a build linked against a system SQLite
+	if got := SupportsSQLCipher("sqlite3"); got != database.SQLCipherAvailable {
+		t.Errorf("SupportsSQLCipher(sqlite3) = %v, want %v", got, database.SQLCipherAvailable)
+	}
+	if SupportsSQLCipher("postgres") {
+		t.Error("SupportsSQLCipher(postgres) = true")
+	}
+}
+
+func TestApplySQLCipherKey(t *testing.T) {
+	tests := []struct {
+		name string
+		conn string
+		key  string
+		want string
+	}{
+		{"no key", "/tmp/app.db", "", "/tmp/app.db"},
+		{"path", "/tmp/app.db", "s3cret", "/tmp/app.db?_key=s3cret"},
+		{"keeps parameters", "file:/tmp/app.db?mode=ro", "s3cret", "file:/tmp/app.db?mode=ro&_key=s3cret"},
+		{"replaces the key", "/tmp/app.db?_key=old&_busy_timeout=5000", "new", "/tmp/app.db?_busy_timeout=5000&_key=new"},
+		{"escapes the key", "/tmp/app.db", "a&b=c d", "/tmp/app.db?_key=a%26b%3Dc+d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplySQLCipherKey(tt.conn, tt.key); got != tt.want {
+				t.Errorf("ApplySQLCipherKey(%q, %q) = %q, want %q", tt.conn, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSQLCipherKey(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		wantDSN string
+		wantKey string
+	}{
+		{"/tmp/app.db", "/tmp/app.db", ""},
+		{"/tmp/app.db?_key=s3cret", "/tmp/app.db", "s3cret"},
+		{"/tmp/app.db?_key=a%26b%3Dc+d", "/tmp/app.db", "a&b=c d"},
+		{"file:/tmp/app.db?_key=s3cret&mode=ro", "file:/tmp/app.db?mode=ro", "s3cret"},
+		{database.SQLiteReadOnlyDSN(ApplySQLCipherKey("/tmp/app.db", "s3cret"), false), "file:/tmp/app.db?mode=ro", "s3cret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			gotDSN, gotKey := database.SplitSQLCipherKey(tt.dsn)
+			if gotDSN != tt.wantDSN || gotKey != tt.wantKey {
+				t.Errorf("SplitSQLCipherKey(%q) = %q, %q, want %q, %q", tt.dsn, gotDSN, gotKey, tt.wantDSN, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestOpenSQLCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.db")
+	db, err := database.Open("sqlite3", ApplySQLCipherKey(path, "s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The bundled SQLite has no SQLCipher; a build linked against it encrypts the new file
+	err = db.Ping()
+	if errors.Is(err, database.ErrNoSQLCipher) {
+		if got := ExplainConnectionError("sqlite3", path, err).(*ConnectionError); got.Summary != "This build cannot open SQLCipher files" {
+			t.Errorf("ExplainConnectionError() = %q", got.Summary)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Ping() with a SQLCipher build = %v", err)
+	}
+	if _, err := db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	wrong, err := database.Open("sqlite3", ApplySQLCipherKey(path, "wrong"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wrong.Close()
+	if err := wrong.Ping(); !errors.Is(err, database.ErrSQLCipherKey) {
+		t.Errorf("Ping() with a wrong key = %v, want %v", err, database.ErrSQLCipherKey)
+	}
+}
@@ -0,0 +1,125 @@
+package utils
+
+import "strings"
+
+// SplitStatements splits a script into its statements at semicolons. Semicolons
+// inside quoted literals and identifiers, comments, and PostgreSQL dollar-quoted
+// bodies do not end a statement. Empty statements are dropped.
+func SplitStatements(script string) []string {
+	var statements []string
+	start := 0
+	add := func(end int) {
+		if stmt := strings.TrimSpace(script[start:end]); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	for i := 0; i < len(script); i++ {
+		if end := skipLiteral(script, i); end != i {
+			i = end
+			continue
+		}
+		if script[i] == ';' {
+			add(i)
+			start = i + 1
+		}
+	}
+	if start < len(script) {
+		add(len(script))
+	}
+	return statements
+}
+
+// skipLiteral returns the index of the last byte of the quoted literal or
+// identifier, comment, or dollar-quoted body that starts at i, or i when none
+// starts there. An unterminated one runs to the end.
+func skipLiteral(script string, i int) int {
+	switch c := script[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		return skipQuoted(script, i, c)
+	case c == '-' && strings.HasPrefix(script[i:], "--"):
+		if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(script)
+	case c == '/' && strings.HasPrefix(script[i:], "/*"):
+		if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+			return i + end + 3
+		}
+		return len(script)
+	case c == '$':
+		if tag := dollarQuoteTag(script[i:]); tag != "" {
+			if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+				return i + len(tag) + end + len(tag) - 1
+			}
+			return len(script)
+		}
+	}
+	return i
+}
+
+// stripLiterals replaces the quoted literals and identifiers, comments, and
+// dollar-quoted bodies of a query with spaces. The query is read once from the
+// start, so a quote inside a comment or -- inside a literal is read as such.
+func stripLiterals(query string) string {
+	return blankLiterals(query, true)
+}
+
+// stripComments replaces the comments of a query with spaces, keeping its
+// quoted literals and identifiers as they are
+func stripComments(query string) string {
+	return blankLiterals(query, false)
+}
+
+// blankLiterals replaces each comment of a query, and each quoted literal,
+// identifier, or dollar-quoted body when literals is set, with a space
+func blankLiterals(query string, literals bool) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		end := skipLiteral(query, i)
+		if end == i {
+			b.WriteByte(query[i])
+			continue
+		}
+		if comment := strings.HasPrefix(query[i:], "--") || strings.HasPrefix(query[i:], "/*"); comment || literals {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(query[i:min(end+1, len(query))])
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// skipQuoted returns the index of the quote that closes the literal opened at
+// start. A doubled quote is an escaped quote; an unterminated literal runs to the end.
+func skipQuoted(script string, start int, quote byte) int {
+	for i := start + 1; i < len(script); i++ {
+		if script[i] != quote {
+			continue
+		}
+		if i+1 < len(script) && script[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(script)
+}
+
+// dollarQuoteTag returns the opening tag of a dollar-quoted string such as $$ or
+// $body$ at the start of s, or "" when s does not start one ($1 is a placeholder)
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9'):
+			continue
+		default:
+			return ""
+		}
+	}
+	return ""
+}
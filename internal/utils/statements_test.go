@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"single without semicolon", "SELECT 1", []string{"SELECT 1"}},
+		{"single with semicolon", "SELECT 1;", []string{"SELECT 1"}},
+		{"two statements", "SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"empty statements dropped", " ; SELECT 1;;", []string{"SELECT 1"}},
+		{"semicolon in literal", "INSERT INTO t VALUES ('a;b'); SELECT 2", []string{"INSERT INTO t VALUES ('a;b')", "SELECT 2"}},
+		{"escaped quote", "SELECT 'it''s;'; SELECT 2", []string{"SELECT 'it''s;'", "SELECT 2"}},
+		{"quoted identifier", `SELECT "a;b" FROM t; SELECT 2`, []string{`SELECT "a;b" FROM t`, "SELECT 2"}},
+		{"backtick identifier", "SELECT `a;b` FROM t; SELECT 2", []string{"SELECT `a;b` FROM t", "SELECT 2"}},
+		{"line comment", "SELECT 1 -- one; two\n; SELECT 2", []string{"SELECT 1 -- one; two", "SELECT 2"}},
+		{"block comment", "SELECT /* ; */ 1; SELECT 2", []string{"SELECT /* ; */ 1", "SELECT 2"}},
+		{"dollar quoted body", "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql; SELECT f()",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", "SELECT f()"}},
+		{"tagged dollar quote", "DO $body$ BEGIN; END $body$; SELECT 2", []string{"DO $body$ BEGIN; END $body$", "SELECT 2"}},
+		{"placeholder is not a dollar quote", "SELECT $1; SELECT $2", []string{"SELECT $1", "SELECT $2"}},
+		{"only whitespace", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptHasWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"reads only", "SELECT 1; SELECT 2", false},
+		{"write after a read", "SELECT 1; DELETE FROM t", true},
+		{"write in a literal", "SELECT 'x; DELETE FROM t'", false},
+		{"comment marker in a literal", "SELECT '--' INTO t FROM x", true},
+		{"quote in a comment", "SELECT 1; -- ' \n DELETE FROM t", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScriptHasWrite(tt.script); got != tt.want {
+				t.Errorf("ScriptHasWrite(%q) = %v, want %v", tt.script, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// FormatBytes renders a byte count using binary units (e.g. "1.5 MB")
+func FormatBytes(n int64) string {
+	if n < 0 {
+		return "n/a"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatRatio renders part/total as a percentage, or "n/a" when total is zero
+func FormatRatio(part, total int64) string {
+	if total <= 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// Connection usage thresholds, as a fraction of max_connections
+const (
+	ConnectionWarningRatio  = 0.80
+	ConnectionCriticalRatio = 0.95
+)
+
+// ConnectionPressure classifies connection usage against the server limit.
+// It returns "critical", "warning", or "" when usage is fine or unknown.
+func ConnectionPressure(connections, maxConnections int) string {
+	if connections < 0 || maxConnections <= 0 {
+		return ""
+	}
+	ratio := float64(connections) / float64(maxConnections)
+	switch {
+	case ratio >= ConnectionCriticalRatio:
+		return "critical"
+	case ratio >= ConnectionWarningRatio:
+		return "warning"
+	}
+	return ""
+}
+
+// FormatConnectionBreakdown renders per-database connection counts, largest first,
+// listing at most limit databases (e.g. "app: 42 • analytics: 7 • +3 more")
+func FormatConnectionBreakdown(counts []models.DatabaseConnections, limit int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, limit+1)
+	for i, c := range counts {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("+%d more", len(counts)-limit))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", c.Database, c.Count))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// LoadDatabaseOverview loads size and connection statistics for the dashboard
+func LoadDatabaseOverview(db *sql.DB, selectedDB models.DBType, selectedSchema string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		overview, err := database.GetDatabaseOverview(db, selectedDB.Driver, selectedSchema)
+		return models.DatabaseOverviewResult{Overview: overview, Err: err}
+	})
+}
+
+// OverviewUnavailable explains that the database overview cannot be opened on
+// the selected database, naming the databases it supports
+func OverviewUnavailable(selectedDB models.DBType) error {
+	var supported []string
+	for _, db := range models.SupportedDatabaseTypes {
+		if models.DriverCapabilities(db.Driver).SizeStats {
+			supported = append(supported, db.Name)
+		}
+	}
+	return fmt.Errorf("the database overview is not available for %s; it supports %s", selectedDB.Name, strings.Join(supported, ", "))
+}
+
+// BuildOverviewRows converts table size statistics into table rows
+func BuildOverviewRows(tables []models.TableSize) []table.Row {
+	rows := make([]table.Row, len(tables))
+	for i, t := range tables {
+		rows[i] = table.Row{
+			t.Name,
+			fmt.Sprintf("%d", t.RowCount),
+			FormatBytes(t.DataBytes),
+			FormatBytes(t.IndexBytes),
+			FormatBytes(t.TotalBytes()),
+			FormatRatio(t.IndexBytes, t.TotalBytes()),
+		}
+	}
+	return rows
+}
+
+// HandleDatabaseOverviewResult processes overview result and updates model
+func HandleDatabaseOverviewResult(m models.Model, msg models.DatabaseOverviewResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingOverview = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	columns := []table.Column{
+		{Title: "Table", Width: 30},
+		{Title: "Rows", Width: 12},
+		{Title: "Data", Width: 10},
+		{Title: "Indexes", Width: 10},
+		{Title: "Total", Width: 10},
+		{Title: "Index %", Width: 8},
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+	height := Max(m.Height-v-14, 5)
+
+	updatedModel.DatabaseOverview = msg.Overview
+	updatedModel.OverviewTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildOverviewRows(msg.Overview.Tables)),
+		table.WithFocused(true),
+		table.WithHeight(height),
+	)
+	updatedModel.OverviewTable.SetStyles(styles.GetBlueTableStyles())
+	updatedModel = PushView(updatedModel, models.DatabaseOverviewView, models.NavParams{})
+	return updatedModel, nil
+}
+
+// LoadSlowQueries loads the slow query log for the selected database
+func LoadSlowQueries(db *sql.DB, selectedDB models.DBType, schema string, byMean bool) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		queries, err := database.GetSlowQueries(db, selectedDB.Driver, schema, byMean)
+		return models.SlowQueriesResult{Queries: queries, Err: err}
+	})
+}
+
+// BuildSlowQueryRows converts slow query statistics into table rows
+func BuildSlowQueryRows(queries []models.SlowQuery) []table.Row {
+	rows := make([]table.Row, len(queries))
+	for i, q := range queries {
+		rows[i] = table.Row{
+			fmt.Sprintf("%.1f", q.TotalMs),
+			fmt.Sprintf("%.2f", q.MeanMs),
+			fmt.Sprintf("%d", q.Calls),
+			fmt.Sprintf("%d", q.Rows),
+			SanitizeValueForDisplay(q.Query),
+		}
+	}
+	return rows
+}
+
+// HandleSlowQueriesResult processes slow query log result and updates model
+func HandleSlowQueriesResult(m models.Model, msg models.SlowQueriesResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingSlowQueries = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	h, v := styles.DocStyle.GetFrameSize()
+	queryWidth := Max(m.Width-h-60, 30)
+	columns := []table.Column{
+		{Title: "Total ms", Width: 12},
+		{Title: "Mean ms", Width: 10},
+		{Title: "Calls", Width: 10},
+		{Title: "Rows", Width: 10},
+		{Title: "Query", Width: queryWidth},
+	}
+
+	updatedModel.SlowQueries = msg.Queries
+	updatedModel.SlowQueryTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildSlowQueryRows(msg.Queries)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-16, 5)),
+	)
+	updatedModel.SlowQueryTable.SetStyles(styles.GetBlueTableStyles())
+	updatedModel = PushView(updatedModel, models.SlowQueryView, models.NavParams{})
+	return updatedModel, nil
+}
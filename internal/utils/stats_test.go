@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/lib/pq"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int64
+		want string
+	}{
+		{"negative", -1, "n/a"},
+		{"zero", 0, "0 B"},
+		{"bytes", 512, "512 B"},
+		{"kilobytes", 1536, "1.5 KB"},
+		{"megabytes", 5 * 1024 * 1024, "5.0 MB"},
+		{"gigabytes", 3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatBytes(tt.n); got != tt.want {
+				t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		part  int64
+		total int64
+		want  string
+	}{
+		{"zero total", 5, 0, "n/a"},
+		{"half", 50, 100, "50.0%"},
+		{"none", 0, 100, "0.0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatRatio(tt.part, tt.total); got != tt.want {
+				t.Errorf("FormatRatio(%d, %d) = %q, want %q", tt.part, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildOverviewRows(t *testing.T) {
+	rows := BuildOverviewRows([]models.TableSize{
+		{Name: "orders", RowCount: 42, DataBytes: 3072, IndexBytes: 1024},
+	})
+	if len(rows) != 1 {
+		t.Fatalf("BuildOverviewRows returned %d rows, want 1", len(rows))
+	}
+	want := []string{"orders", "42", "3.0 KB", "1.0 KB", "4.0 KB", "25.0%"}
+	for i, cell := range want {
+		if rows[0][i] != cell {
+			t.Errorf("cell %d = %q, want %q", i, rows[0][i], cell)
+		}
+	}
+}
+
+func TestOverviewUnavailable(t *testing.T) {
+	for _, db := range models.SupportedDatabaseTypes {
+		if models.DriverCapabilities(db.Driver).SizeStats {
+			continue
+		}
+		msg := OverviewUnavailable(db).Error()
+		if !strings.Contains(msg, "not available for "+db.Name) || !strings.Contains(msg, "PostgreSQL") || strings.Contains(msg, db.Name+",") {
+			t.Errorf("OverviewUnavailable(%s) = %q", db.Name, msg)
+		}
+	}
+}
+
+func TestBuildSlowQueryRows(t *testing.T) {
+	rows := BuildSlowQueryRows([]models.SlowQuery{
+		{Query: "SELECT *\n  FROM orders", Calls: 10, TotalMs: 1234.56, MeanMs: 123.456, Rows: 500},
+	})
+	if len(rows) != 1 {
+		t.Fatalf("BuildSlowQueryRows returned %d rows, want 1", len(rows))
+	}
+	want := []string{"1234.6", "123.46", "10", "500", "SELECT * FROM orders"}
+	for i, cell := range want {
+		if rows[0][i] != cell {
+			t.Errorf("cell %d = %q, want %q", i, rows[0][i], cell)
+		}
+	}
+}
+
+func TestConnectionPressure(t *testing.T) {
+	tests := []struct {
+		name  string
+		conns int
+		max   int
+		want  string
+	}{
+		{"unknown connections", -1, 100, ""},
+		{"unknown max", 10, -1, ""},
+		{"low", 10, 100, ""},
+		{"warning", 80, 100, "warning"},
+		{"critical", 96, 100, "critical"},
+		{"over limit", 120, 100, "critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConnectionPressure(tt.conns, tt.max); got != tt.want {
+				t.Errorf("ConnectionPressure(%d, %d) = %q, want %q", tt.conns, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatConnectionBreakdown(t *testing.T) {
+	counts := []models.DatabaseConnections{
+		{Database: "app", Count: 42},
+		{Database: "analytics", Count: 7},
+		{Database: "postgres", Count: 1},
+	}
+
+	tests := []struct {
+		name   string
+		counts []models.DatabaseConnections
+		limit  int
+		want   string
+	}{
+		{"empty", nil, 5, ""},
+		{"all shown", counts, 5, "app: 42 • analytics: 7 • postgres: 1"},
+		{"truncated", counts, 2, "app: 42 • analytics: 7 • +1 more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatConnectionBreakdown(tt.counts, tt.limit); got != tt.want {
+				t.Errorf("FormatConnectionBreakdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSlowQueriesFallback(t *testing.T) {
+	legacy := scriptedResult{rows: [][]driver.Value{{"SELECT 1", int64(3), 1.5, 0.5, int64(3)}}}
+	tests := []struct {
+		name      string
+		first     scriptedResult
+		wantErr   string
+		wantCalls int
+	}{
+		{"older column names", scriptedResult{err: &pq.Error{Code: "42703", Message: `column "total_exec_time" does not exist`}}, "", 2},
+		{"permission denied is kept", scriptedResult{err: &pq.Error{Code: "42501", Message: "permission denied for view pg_stat_statements"}}, "permission denied", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, conn := scriptedDB(t, "total_exec_time", tt.first, "total_time", legacy)
+			got, err := database.GetSlowQueries(db, "postgres", "", false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GetSlowQueries() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+			} else if err != nil || len(got) != 1 {
+				t.Errorf("GetSlowQueries() = %v, %v; want the legacy query's row", got, err)
+			}
+			if n := len(conn.sent()); n != tt.wantCalls {
+				t.Errorf("GetSlowQueries() sent %d statements, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
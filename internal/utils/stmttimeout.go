@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// StatementContext bounds a query runner statement by the statement timeout of
+// its connection. The returned timeout is 0 when statements may run without limit.
+func StatementContext(db *sql.DB) (context.Context, context.CancelFunc, time.Duration) {
+	ctx, cancel := database.StatementContext(db)
+	return ctx, cancel, database.StatementTimeout(db)
+}
+
+// RunningStatementContext bounds a query runner statement by the statement
+// timeout, under the running query's context so a cancel reaches it too
+func RunningStatementContext(running *models.RunningQuery, db *sql.DB) (context.Context, context.CancelFunc, time.Duration) {
+	if running == nil {
+		return StatementContext(db)
+	}
+	timeout := database.StatementTimeout(db)
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(running.Context, timeout)
+		return ctx, cancel, timeout
+	}
+	ctx, cancel := context.WithCancel(running.Context)
+	return ctx, cancel, timeout
+}
+
+// StatementTimedOut reports whether a statement's context ran out of time
+func StatementTimedOut(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// FormatTimeout renders a timeout without trailing zero units: 30s, 2m, 1m30s
+func FormatTimeout(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// statementTimeoutError replaces the driver's cancellation error, which can
+// otherwise look like a dropped connection
+func statementTimeoutError(timeout time.Duration) error {
+	return fmt.Errorf("statement timed out after %s and was cancelled", FormatTimeout(timeout))
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestFormatTimeout(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "30s"},
+		{2 * time.Minute, "2m"},
+		{90 * time.Second, "1m30s"},
+		{time.Hour, "1h"},
+		{1500 * time.Millisecond, "1.5s"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTimeout(tt.d); got != tt.want {
+			t.Errorf("FormatTimeout(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestStatementContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		setting string
+		want    time.Duration
+	}{
+		{"seconds", "90", "", 90 * time.Second},
+		{"duration", "2m", "", 2 * time.Minute},
+		{"disabled", "0", "", 0},
+		{"invalid falls back to the default", "soon", "", 30 * time.Second},
+		{"negative falls back to the default", "-5", "", 30 * time.Second},
+		{"connection setting wins", "90", "5s", 5 * time.Second},
+		{"connection turns it off", "90", "0s", 0},
+		{"unreadable setting uses the default", "90", "soon", 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MIRADOR_STATEMENT_TIMEOUT", tt.value)
+			db, err := database.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer database.Close(db)
+			database.SetStatementTimeout(db, ConnectionTimeouts(models.SavedConnection{StatementTimeout: tt.setting}).Statement)
+
+			ctx, cancel, timeout := StatementContext(db)
+			defer cancel()
+			if timeout != tt.want {
+				t.Errorf("StatementContext() timeout = %v, want %v", timeout, tt.want)
+			}
+			if _, hasDeadline := ctx.Deadline(); hasDeadline != (tt.want > 0) {
+				t.Errorf("StatementContext() deadline set = %v, want %v", hasDeadline, tt.want > 0)
+			}
+		})
+	}
+}
+
+func TestCloseDB(t *testing.T) {
+	db, err := database.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.SetStatementTimeout(db, time.Minute)
+
+	m := CloseDB(models.Model{DB: db})
+	if m.DB != nil {
+		t.Error("CloseDB() kept the connection")
+	}
+	if got := database.StatementTimeout(db); got != 0 {
+		t.Errorf("StatementTimeout() after close = %v, want the pool forgotten", got)
+	}
+	if err := db.Ping(); err == nil {
+		t.Error("CloseDB() left the pool open")
+	}
+}
+
+func TestParseTimeoutSetting(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"  ", "", false},
+		{"0", "0s", false},
+		{"90", "1m30s", false},
+		{"2m", "2m", false},
+		{"-1s", "", true},
+		{"soon", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeoutSetting(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTimeoutSetting(%q) = %q, %v, want %q (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStatementTimedOut(t *testing.T) {
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+	if !StatementTimedOut(expired) {
+		t.Error("StatementTimedOut() = false for an expired context")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if StatementTimedOut(cancelled) {
+		t.Error("StatementTimedOut() = true for a cancelled context")
+	}
+}
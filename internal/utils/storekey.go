@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+)
+
+// migratedStoreKeys records the older store keys already moved this run
+var migratedStoreKeys sync.Map
+
+// connectionStoreKey identifies a connection and schema in the per-connection
+// stores. The key is built from the connection string in canonical form with
+// its secrets removed, so a password change, a normalized saved string, or a
+// workspace imported without passwords finds the same favorites, notes,
+// snapshots, and worksheet. Entries stored under the key of earlier versions,
+// which hashed the whole string, are moved to it the first time it is used.
+func connectionStoreKey(driver, connectionStr, schema string) string {
+	canonical, _ := NormalizeConnectionString(driver, connectionStr)
+	key := config.StoreKey(driver, database.RedactSecrets(driver, canonical, ""), schema)
+
+	legacy := config.StoreKey(driver, connectionStr, schema)
+	if legacy != key {
+		if _, done := migratedStoreKeys.Load(legacy); !done && config.RenameStoreKey(legacy, key) == nil {
+			migratedStoreKeys.Store(legacy, true)
+		}
+	}
+	return key
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/config"
+)
+
+func TestConnectionStoreKeyIgnoresSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	key := connectionStoreKey("postgres", "postgres://app:secret@db:5432/shop", "public")
+	same := []string{
+		"postgres://app:changed@db:5432/shop", // A new password
+		"postgres://app@db:5432/shop",         // Exported without its password
+		"postgres://app:secret@db/shop",       // Before the default port was added
+	}
+	for _, connectionStr := range same {
+		if got := connectionStoreKey("postgres", connectionStr, "public"); got != key {
+			t.Errorf("connectionStoreKey(%q) = %s, want %s", connectionStr, got, key)
+		}
+	}
+
+	different := map[string]string{
+		"another database": "postgres://app:secret@db:5432/billing",
+		"another user":     "postgres://admin:secret@db:5432/shop",
+	}
+	for name, connectionStr := range different {
+		if got := connectionStoreKey("postgres", connectionStr, "public"); got == key {
+			t.Errorf("%s got the same key", name)
+		}
+	}
+	if connectionStoreKey("postgres", "postgres://app:secret@db:5432/shop", "audit") == key {
+		t.Errorf("another schema got the same key")
+	}
+	if connectionStoreKey("mysql", "app:secret@tcp(db:3306)/shop", "") != connectionStoreKey("mysql", "app:other@tcp(db:3306)/shop", "") {
+		t.Errorf("MySQL DSNs with different passwords got different keys")
+	}
+}
+
+func TestConnectionStoreKeyMovesLegacyEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	connectionStr := "postgres://app:secret@db:5432/legacy"
+	legacy := config.StoreKey("postgres", connectionStr, "public")
+	if err := config.SaveFavoriteTables(legacy, []string{"orders"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTableNote(legacy, "orders", "one row per checkout"); err != nil {
+		t.Fatal(err)
+	}
+
+	favorites := LoadFavoriteTables("postgres", connectionStr, "public")
+	if !favorites["orders"] {
+		t.Errorf("favorites under the old key were not moved: %v", favorites)
+	}
+	notes := LoadTableNotes("postgres", connectionStr, "public")
+	if notes["orders"] != "one row per checkout" {
+		t.Errorf("notes under the old key were not moved: %v", notes)
+	}
+	if tables, _ := config.LoadFavoriteTables(legacy); tables != nil {
+		t.Errorf("favorites are still stored under the old key: %v", tables)
+	}
+
+	// The entries now follow the connection through a password change
+	if !LoadFavoriteTables("postgres", "postgres://app:rotated@db:5432/legacy", "public")["orders"] {
+		t.Errorf("favorites were lost after a password change")
+	}
+}
+
+func TestRenameStoreKeyKeepsNewerEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := config.SaveFavoriteTables("old", []string{"orders"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveFavoriteTables("new", []string{"customers"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.RenameStoreKey("old", "new"); err != nil {
+		t.Fatalf("RenameStoreKey: %v", err)
+	}
+
+	tables, _ := config.LoadFavoriteTables("new")
+	if len(tables) != 1 || tables[0] != "customers" {
+		t.Errorf("favorites under the new key = %v, want [customers]", tables)
+	}
+	if tables, _ := config.LoadFavoriteTables("old"); tables != nil {
+		t.Errorf("favorites under the old key = %v, want none", tables)
+	}
+}
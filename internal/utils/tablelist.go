@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// CreateTableInfos creates TableInfo objects from table names. Tables found in
+// foreign are typed FOREIGN and keep their server as the description; tables
+// found in persistence keep their persistence kind.
+func CreateTableInfos(tables []string, schema string, foreign, persistence map[string]string) []models.TableInfo {
+	infos := make([]models.TableInfo, len(tables))
+	for i, table := range tables {
+		infos[i] = models.TableInfo{
+			Name:   table,
+			Schema: schema,
+		}
+		if server, ok := foreign[table]; ok {
+			infos[i].TableType = "FOREIGN"
+			infos[i].Description = server
+		}
+		infos[i].Persistence = persistence[table]
+	}
+	return infos
+}
+
+// PersistenceWarning explains what a non-durable table loses, or "" for durable tables
+func PersistenceWarning(persistence string) string {
+	switch persistence {
+	case database.PersistenceUnlogged:
+		return "Unlogged: emptied after a crash and not replicated"
+	case database.PersistenceTemporary:
+		return "Temporary: dropped when its session ends"
+	case database.PersistenceInMemory:
+		return "In-memory: emptied when the server restarts"
+	}
+	return ""
+}
+
+// TablePersistence returns the persistence kind of the named table, or "" when it is durable
+func TablePersistence(infos []models.TableInfo, table string) string {
+	for _, info := range infos {
+		if info.Name == table {
+			return info.Persistence
+		}
+	}
+	return ""
+}
+
+// ForeignTableServer returns the server of the named table when it is a foreign table
+func ForeignTableServer(infos []models.TableInfo, table string) (string, bool) {
+	for _, info := range infos {
+		if info.Name == table && info.TableType == "FOREIGN" {
+			return info.Description, true
+		}
+	}
+	return "", false
+}
+
+// CreateTableListItems creates list items from table infos. Favorite tables are
+// listed first, each group keeping the order of infos; table notes are previewed
+// in the description.
+func CreateTableListItems(infos []models.TableInfo, favorites map[string]bool, notes map[string]string) []list.Item {
+	items := make([]list.Item, 0, len(infos))
+	for _, starred := range []bool{true, false} {
+		for _, info := range infos {
+			if favorites[info.Name] != starred {
+				continue
+			}
+			desc := fmt.Sprintf("Table in %s schema", info.Schema)
+			if info.TableType == "FOREIGN" {
+				desc = fmt.Sprintf("⇄ Foreign table on %s in %s schema", info.Description, info.Schema)
+			}
+			if warning := PersistenceWarning(info.Persistence); warning != "" {
+				desc += " • ⚠ " + warning
+			}
+			if starred {
+				desc = "★ Favorite • " + desc
+			}
+			if note := notes[info.Name]; note != "" {
+				desc += " • 📝 " + NotePreview(note, 60)
+			}
+			items = append(items, models.Item{ItemTitle: info.Name, ItemDesc: desc})
+		}
+	}
+	return items
+}
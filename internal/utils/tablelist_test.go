@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestCreateTableListItemsListsFavoritesFirst(t *testing.T) {
+	infos := CreateTableInfos([]string{"accounts", "events", "orders", "users"}, "public", nil, nil)
+	favorites := map[string]bool{"users": true, "events": true}
+
+	var got []string
+	for _, item := range CreateTableListItems(infos, favorites, nil) {
+		got = append(got, item.(models.Item).ItemTitle)
+	}
+	want := []string{"events", "users", "accounts", "orders"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateTableListItems() order = %v, want %v", got, want)
+	}
+}
+
+func TestCreateTableListItemsMarksForeignTables(t *testing.T) {
+	infos := CreateTableInfos([]string{"orders", "remote_orders"}, "public", map[string]string{"remote_orders": "billing (postgres_fdw)"}, nil)
+
+	if _, ok := ForeignTableServer(infos, "orders"); ok {
+		t.Errorf("ForeignTableServer(orders) reported a local table as foreign")
+	}
+	if server, ok := ForeignTableServer(infos, "remote_orders"); !ok || server != "billing (postgres_fdw)" {
+		t.Errorf("ForeignTableServer(remote_orders) = %q, %v", server, ok)
+	}
+
+	items := CreateTableListItems(infos, nil, nil)
+	want := "⇄ Foreign table on billing (postgres_fdw) in public schema"
+	if got := items[1].(models.Item).ItemDesc; got != want {
+		t.Errorf("foreign table description = %q, want %q", got, want)
+	}
+}
+
+func TestCreateTableListItemsWarnsAboutNonDurableTables(t *testing.T) {
+	infos := CreateTableInfos([]string{"orders", "sessions"}, "public", nil, map[string]string{"sessions": "unlogged"})
+
+	if got := TablePersistence(infos, "orders"); got != "" {
+		t.Errorf("TablePersistence(orders) = %q, want durable", got)
+	}
+	if got := TablePersistence(infos, "sessions"); got != "unlogged" {
+		t.Errorf("TablePersistence(sessions) = %q, want unlogged", got)
+	}
+
+	items := CreateTableListItems(infos, nil, nil)
+	want := "Table in public schema • ⚠ Unlogged: emptied after a crash and not replicated"
+	if got := items[1].(models.Item).ItemDesc; got != want {
+		t.Errorf("unlogged table description = %q, want %q", got, want)
+	}
+}
+
+func TestCreateTableListItemsPreviewsNotes(t *testing.T) {
+	infos := CreateTableInfos([]string{"orders"}, "sales", nil, nil)
+
+	items := CreateTableListItems(infos, map[string]bool{"orders": true}, map[string]string{"orders": "one row per checkout"})
+	want := "★ Favorite • Table in sales schema • 📝 one row per checkout"
+	if got := items[0].(models.Item).ItemDesc; got != want {
+		t.Errorf("noted favorite description = %q, want %q", got, want)
+	}
+}
+
+func TestPersistenceWarning(t *testing.T) {
+	tests := []struct {
+		persistence string
+		want        string
+	}{
+		{"", ""},
+		{database.PersistenceUnlogged, "Unlogged: emptied after a crash and not replicated"},
+		{database.PersistenceTemporary, "Temporary: dropped when its session ends"},
+		{database.PersistenceInMemory, "In-memory: emptied when the server restarts"},
+	}
+
+	for _, tt := range tests {
+		if got := PersistenceWarning(tt.persistence); got != tt.want {
+			t.Errorf("PersistenceWarning(%q) = %q, want %q", tt.persistence, got, tt.want)
+		}
+	}
+}
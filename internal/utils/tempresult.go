@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// TempResultTableName names the nth temporary table created from a query result
+func TempResultTableName(n int) string {
+	return fmt.Sprintf("mirador_result_%d", n)
+}
+
+// CanMaterializeQuery reports whether a query is a single read statement whose
+// rows can be copied into a temporary table
+func CanMaterializeQuery(query string) bool {
+	query = sqlBlockComment.ReplaceAllString(query, " ")
+	query = sqlLineComment.ReplaceAllString(query, " ")
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	if strings.Contains(query, ";") {
+		return false
+	}
+
+	words := sqlWord.FindAllString(query, 1)
+	if len(words) == 0 {
+		return false
+	}
+	first := strings.ToUpper(words[0])
+	return (first == "SELECT" || first == "WITH") && !IsWriteStatement(query)
+}
+
+// MaterializeQueryResult creates a temporary table holding every row of the query.
+// Temporary tables belong to one database session, so the pool is pinned to a
+// single connection to keep the table visible to the data preview afterwards.
+func MaterializeQueryResult(db *sql.DB, selectedDB models.DBType, schema, table, query string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		db.SetMaxOpenConns(1)
+		if err := database.CreateTempTableAs(db, selectedDB.Driver, schema, table, query); err != nil {
+			return models.TempResultMsg{Err: fmt.Errorf("failed to create temporary table: %w", err)}
+		}
+		return models.TempResultMsg{Table: table, Schema: database.TempTableSchema(selectedDB.Driver, schema)}
+	})
+}
+
+// HandleTempResult opens a materialized query result in the data preview
+func HandleTempResult(m models.Model, msg models.TempResultMsg) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsMaterializingResult = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	// Remember the browsed schema once, even when a result is opened from another result
+	if updatedModel.TempResultTable == "" {
+		updatedModel.TempResultReturnSchema = m.SelectedSchema
+	}
+	updatedModel.TempResultTable = msg.Table
+	updatedModel.SelectedTable = msg.Table
+	updatedModel.SelectedSchema = msg.Schema
+	updatedModel.DataPreviewCurrentPage = 0
+	updatedModel.DataPreviewScrollOffset = 0
+	updatedModel.DataPreviewFilterValue = ""
+	updatedModel.DataPreviewFilterInput.SetValue("")
+	updatedModel.DataPreviewSortColumn = ""
+	updatedModel.DataPreviewSortDirection = models.SortOff
+	updatedModel.DataPreviewThenBy = nil
+	updatedModel.IsLoadingPreview = true
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	routed, db := RouteRead(updatedModel)
+	return routed, LoadDataPreview(db, m.SelectedDB, msg.Table, msg.Schema, m.DataPreviewItemsPerPage, nil, nil)
+}
+
+// LeaveTempResult restores the schema that was browsed before a temporary table was previewed
+func LeaveTempResult(m models.Model) models.Model {
+	if m.TempResultTable == "" {
+		return m
+	}
+	m.SelectedSchema = m.TempResultReturnSchema
+	m.TempResultTable = ""
+	m.TempResultReturnSchema = ""
+	return m
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestCanMaterializeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"select", "SELECT * FROM users", true},
+		{"select with trailing semicolon", "select id from users;  ", true},
+		{"read-only cte", "WITH t AS (SELECT 1) SELECT * FROM t", true},
+		{"leading comment", "-- recent\nSELECT * FROM orders", true},
+		{"insert", "INSERT INTO users (name) VALUES ('a')", false},
+		{"writing cte", "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", false},
+		{"two statements", "SELECT 1; SELECT 2", false},
+		{"show", "SHOW TABLES", false},
+		{"empty", "  ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanMaterializeQuery(tt.query); got != tt.want {
+				t.Errorf("CanMaterializeQuery(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTempResultTableName(t *testing.T) {
+	if got := TempResultTableName(3); got != "mirador_result_3" {
+		t.Errorf("TempResultTableName(3) = %q, want mirador_result_3", got)
+	}
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// MaxTestDataRows caps how many rows a single generate action inserts
+const MaxTestDataRows = 10000
+
+// parentKeySample is how many existing parent keys are loaded per foreign key
+const parentKeySample = 1000
+
+// ParseTestDataRowCount validates the requested number of rows to generate
+func ParseTestDataRowCount(input string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("enter a positive number of rows")
+	}
+	if n > MaxTestDataRows {
+		return 0, fmt.Errorf("at most %d rows can be generated at once", MaxTestDataRows)
+	}
+	return n, nil
+}
+
+// LoadTestDataPlan reads the column specs and parent keys needed to generate rows
+func LoadTestDataPlan(db *sql.DB, selectedDB models.DBType, selectedTable, selectedSchema string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		specs, err := database.GetColumnSpecs(db, selectedDB.Driver, selectedTable, selectedSchema)
+		if err != nil {
+			return models.TestDataPlanResult{Err: err}
+		}
+
+		plan := models.TestDataPlan{
+			Table:      selectedTable,
+			Schema:     selectedSchema,
+			Columns:    specs,
+			ParentKeys: map[string][]string{},
+		}
+		for _, col := range specs {
+			if col.References == nil || col.Generated {
+				continue
+			}
+			keys, err := database.GetParentKeys(db, selectedDB.Driver, selectedSchema, *col.References, parentKeySample)
+			if err != nil {
+				return models.TestDataPlanResult{Err: fmt.Errorf("failed to read keys of %s: %w", col.References.Table, err)}
+			}
+			plan.ParentKeys[col.Name] = keys
+		}
+		return models.TestDataPlanResult{Plan: plan}
+	})
+}
+
+// InsertTestData generates n rows for the plan and inserts them in one transaction
+func InsertTestData(db *sql.DB, selectedDB models.DBType, connectionStr string, plan models.TestDataPlan, n int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		generator := NewValueGenerator(time.Now().UnixNano(), time.Now())
+		columns, rows, err := GenerateRows(plan, n, generator)
+		if err != nil {
+			return models.TestDataInsertResult{Table: plan.Table, Err: err}
+		}
+
+		insertSQL := database.BuildInsertSQL(selectedDB.Driver, plan.Schema, plan.Table, columns)
+		inserted, err := database.InsertRows(db, insertSQL, rows)
+		RecordAudit(selectedDB, connectionStr, "generate data", insertSQL, []string{fmt.Sprintf("%d generated rows", n)}, inserted, err)
+		if err != nil {
+			return models.TestDataInsertResult{Table: plan.Table, Err: fmt.Errorf("failed to insert generated rows: %w", err)}
+		}
+		return models.TestDataInsertResult{Table: plan.Table, Inserted: inserted}
+	})
+}
+
+// StartTestData loads the plan for generating rows into a table. A read-only
+// connection refuses, and drivers that cannot describe their columns do nothing.
+func StartTestData(m models.Model, table string) (models.Model, tea.Cmd) {
+	if m.ReadOnly {
+		return SetErrorWithTimeout(m, ReadOnlyBlocked("generating test data"), 3*time.Second)
+	}
+	if m.DB == nil || !models.DriverCapabilities(m.SelectedDB.Driver).TestData || m.IsLoadingTestData {
+		return m, nil
+	}
+	updatedModel := m
+	updatedModel.SelectedTable = table
+	updatedModel.IsLoadingTestData = true
+	updatedModel.Err = nil
+	return updatedModel, LoadTestDataPlan(m.DB, m.SelectedDB, table, m.SelectedSchema)
+}
+
+// RequestTestDataInsert checks the requested row count and inserts the rows. In
+// safe mode and on a production connection it asks for confirmation first.
+func RequestTestDataInsert(m models.Model) (models.Model, tea.Cmd) {
+	if m.IsInsertingTestData {
+		return m, nil
+	}
+	if _, err := ParseTestDataRowCount(m.TestDataCountInput.Value()); err != nil {
+		return SetErrorWithTimeout(m, err, 3*time.Second)
+	}
+	if ConfirmsWrites(m) {
+		updatedModel := m
+		updatedModel.IsConfirmingSafeOverride = true
+		updatedModel.Err = nil
+		updatedModel.QueryResult = ""
+		return updatedModel, nil
+	}
+	return StartTestDataInsert(m)
+}
+
+// StartTestDataInsert kicks off generating and inserting the requested rows
+func StartTestDataInsert(m models.Model) (models.Model, tea.Cmd) {
+	n, err := ParseTestDataRowCount(m.TestDataCountInput.Value())
+	if err != nil {
+		return SetErrorWithTimeout(m, err, 3*time.Second)
+	}
+	updatedModel := m
+	updatedModel.IsInsertingTestData = true
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	return updatedModel, InsertTestData(m.DB, m.SelectedDB, m.ConnectionStr, m.TestDataPlan, n)
+}
+
+// HandleTestDataPlanResult opens the generator view for the loaded plan
+func HandleTestDataPlanResult(m models.Model, msg models.TestDataPlanResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsLoadingTestData = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 3*time.Second)
+	}
+
+	updatedModel.TestDataPlan = msg.Plan
+	updatedModel.TestDataCountInput.SetValue("")
+	updatedModel.TestDataCountInput.Focus()
+	updatedModel.QueryResult = ""
+	updatedModel = PushView(updatedModel, models.GenerateDataView, TableParams(updatedModel))
+	return updatedModel, nil
+}
+
+// HandleTestDataInsertResult reports how many generated rows were inserted
+func HandleTestDataInsertResult(m models.Model, msg models.TestDataInsertResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsInsertingTestData = false
+
+	if msg.Err != nil {
+		return SetErrorWithTimeout(updatedModel, msg.Err, 5*time.Second)
+	}
+
+	updatedModel.QueryResult = fmt.Sprintf("✅ Inserted %d generated rows into %s", msg.Inserted, msg.Table)
+	return updatedModel, ClearResultAfterTimeout()
+}
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// testDataDB creates a SQLite file with an items table of one column per
+// generated type, referencing an owners table with two rows
+func testDataDB(t *testing.T) (string, *sql.DB) {
+	t.Setenv("HOME", t.TempDir()) // Inserts are recorded in the audit log
+	path := filepath.Join(t.TempDir(), "shop.db")
+	db, err := database.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+	for _, stmt := range []string{
+		"CREATE TABLE owners (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO owners (id, name) VALUES (1, 'ana'), (2, 'bo')",
+		`CREATE TABLE items (
+			id INTEGER PRIMARY KEY,
+			code VARCHAR(6) NOT NULL,
+			email TEXT NOT NULL,
+			price DECIMAL(10,2) NOT NULL,
+			active BOOLEAN NOT NULL,
+			due DATE NOT NULL,
+			updated_at DATETIME NOT NULL,
+			ref UUID NOT NULL,
+			payload JSON NOT NULL,
+			note TEXT,
+			owner_id INTEGER NOT NULL REFERENCES owners(id)
+		)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, db
+}
+
+func TestInsertTestData(t *testing.T) {
+	_, db := testDataDB(t)
+	sqlite := models.DBType{Driver: "sqlite3"}
+
+	planMsg := LoadTestDataPlan(db, sqlite, "items", "main")().(models.TestDataPlanResult)
+	if planMsg.Err != nil {
+		t.Fatalf("LoadTestDataPlan() error = %v", planMsg.Err)
+	}
+	plan := planMsg.Plan
+	if len(plan.Columns) == 0 || !plan.Columns[0].Generated {
+		t.Errorf("LoadTestDataPlan() id = %+v, want it left to the database", plan.Columns)
+	}
+	if got := plan.ParentKeys["owner_id"]; !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("LoadTestDataPlan() owner keys = %v, want [1 2]", got)
+	}
+
+	const n = 25
+	insertMsg := InsertTestData(db, sqlite, "", plan, n)().(models.TestDataInsertResult)
+	if insertMsg.Err != nil || insertMsg.Inserted != n {
+		t.Fatalf("InsertTestData() = %d, %v, want %d rows", insertMsg.Inserted, insertMsg.Err, n)
+	}
+
+	// Each check must hold for every generated row
+	tests := []struct {
+		column string
+		check  string
+	}{
+		{"id", "id IS NOT NULL"},
+		{"code", "length(code) BETWEEN 1 AND 6"},
+		{"email", "email LIKE '%@example.com'"},
+		{"price", "typeof(price) IN ('real', 'integer') AND price >= 0"},
+		{"active", "active IN (0, 1)"},
+		{"due", "due = date(due)"},
+		{"updated_at", "updated_at = datetime(updated_at)"},
+		{"ref", "length(ref) = 36 AND substr(ref, 15, 1) = '4'"},
+		{"payload", "json_valid(payload) AND json_extract(payload, '$.generated') = 1"},
+		{"owner_id", "owner_id IN (SELECT id FROM owners)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			var bad int
+			if err := db.QueryRow("SELECT COUNT(*) FROM items WHERE NOT (" + tt.check + ")").Scan(&bad); err != nil {
+				t.Fatal(err)
+			}
+			if bad > 0 {
+				t.Errorf("%d generated rows fail %s", bad, tt.check)
+			}
+		})
+	}
+
+	// A required foreign key with no parent rows cannot be generated
+	if _, err := db.Exec("DELETE FROM owners"); err != nil {
+		t.Fatal(err)
+	}
+	planMsg = LoadTestDataPlan(db, sqlite, "items", "main")().(models.TestDataPlanResult)
+	if msg := InsertTestData(db, sqlite, "", planMsg.Plan, 1)().(models.TestDataInsertResult); msg.Err == nil {
+		t.Error("InsertTestData() without owners succeeded, want an error for owner_id")
+	}
+}
+
+func TestStartTestData(t *testing.T) {
+	_, db := testDataDB(t)
+
+	tests := []struct {
+		name        string
+		driver      string
+		readOnly    bool
+		loading     bool
+		wantErr     string
+		wantLoading bool
+	}{
+		{"loads the plan", "sqlite3", false, false, "", true},
+		{"read-only refuses", "sqlite3", true, false, "read-only connection: generating test data is disabled", false},
+		{"driver without column specs", "clickhouse", false, false, "", false},
+		{"already loading", "sqlite3", false, true, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.Model{DB: db, SelectedDB: models.DBType{Driver: tt.driver}, SelectedSchema: "main", ReadOnly: tt.readOnly, IsLoadingTestData: tt.loading}
+			got, cmd := StartTestData(m, "items")
+			var gotErr string
+			if got.Err != nil {
+				gotErr = got.Err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("StartTestData() error = %q, want %q", gotErr, tt.wantErr)
+			}
+			if got.IsLoadingTestData != tt.wantLoading {
+				t.Errorf("StartTestData() loading = %v, want %v", got.IsLoadingTestData, tt.wantLoading)
+			}
+			started := tt.wantLoading && !tt.loading
+			if started && (cmd == nil || got.SelectedTable != "items") {
+				t.Errorf("StartTestData() did not start loading items")
+			}
+			if !started && tt.wantErr == "" && cmd != nil {
+				t.Errorf("StartTestData() returned a command, want none")
+			}
+		})
+	}
+}
+
+func TestRequestTestDataInsert(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         string
+		safeMode      bool
+		env           string
+		wantErr       bool
+		wantConfirm   bool
+		wantInserting bool
+	}{
+		{"inserts", "10", false, "", false, false, true},
+		{"safe mode asks first", "10", true, "", false, true, false},
+		{"production asks first", "10", false, models.EnvironmentProduction, false, true, false},
+		{"staging inserts", "10", false, models.EnvironmentStaging, false, false, true},
+		{"bad count", "none", false, "", true, false, false},
+		{"bad count in safe mode", "0", true, "", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := textinput.New()
+			input.SetValue(tt.count)
+			m := models.Model{SelectedDB: models.DBType{Driver: "sqlite3"}, SafeMode: tt.safeMode, Environment: tt.env, TestDataCountInput: input}
+			got, cmd := RequestTestDataInsert(m)
+			if (got.Err != nil) != tt.wantErr {
+				t.Errorf("RequestTestDataInsert() error = %v, wantErr %v", got.Err, tt.wantErr)
+			}
+			if got.IsConfirmingSafeOverride != tt.wantConfirm {
+				t.Errorf("RequestTestDataInsert() confirming = %v, want %v", got.IsConfirmingSafeOverride, tt.wantConfirm)
+			}
+			if got.IsInsertingTestData != tt.wantInserting || (cmd != nil) != (tt.wantInserting || tt.wantErr) {
+				t.Errorf("RequestTestDataInsert() inserting = %v with command %v, want %v", got.IsInsertingTestData, cmd != nil, tt.wantInserting)
+			}
+		})
+	}
+}
+
+func TestInsertTestDataReadOnlyFile(t *testing.T) {
+	path, db := testDataDB(t)
+	sqlite := models.DBType{Driver: "sqlite3"}
+	plan := LoadTestDataPlan(db, sqlite, "items", "main")().(models.TestDataPlanResult).Plan
+
+	// A read-only file refuses the rows even if the insert is started
+	readOnly, err := database.Open("sqlite3", OpenDSN("sqlite3", path, true, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close(readOnly)
+	msg := InsertTestData(readOnly, sqlite, "", plan, 5)().(models.TestDataInsertResult)
+	if msg.Err == nil || !strings.Contains(msg.Err.Error(), "readonly") {
+		t.Errorf("InsertTestData(read-only file) error = %v, want a read-only error", msg.Err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM items").Scan(&count); err != nil || count != 0 {
+		t.Errorf("items has %d rows (%v) after a refused insert, want 0", count, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
 	"github.com/dancaldera/mirador/internal/database"
 	"github.com/dancaldera/mirador/internal/models"
 )
@@ -18,6 +19,8 @@ func ClearResultAfterTimeout() tea.Cmd {
 // TestConnection performs a database connection test with timeout
 func TestConnection(driver, connectionStr string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		return database.TestConnectionWithTimeout(driver, connectionStr)
+		result := database.TestConnectionWithTimeout(driver, connectionStr, config.ConnectTimeout())
+		result.Err = ExplainConnectionError(driver, connectionStr, result.Err)
+		return result
 	})
 }
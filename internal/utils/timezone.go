@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// displayTimeFormat is how converted timestamps are shown in the preview
+const displayTimeFormat = "2006-01-02 15:04:05.999999999 -07:00"
+
+// zonedTimestampLayouts carry an explicit offset, so the instant is unambiguous
+var zonedTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",   // time.Time.String() from drivers
+	"2006-01-02 15:04:05.999999999 -0700 -0700", // unnamed fixed zones
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+}
+
+// zonelessTimestampLayouts are DATETIME-style values stored without an offset
+var zonelessTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// ConvertTimestampForDisplay renders a timestamp value in the given location.
+// Values without an offset are treated as UTC. It reports false when the value
+// is not a timestamp or no location is set, leaving the value unchanged.
+func ConvertTimestampForDisplay(value string, loc *time.Location) (string, bool) {
+	if loc == nil {
+		return value, false
+	}
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) < len("2006-01-02 15:04") {
+		return value, false
+	}
+
+	for _, layout := range zonedTimestampLayouts {
+		if t, err := time.Parse(layout, trimmed); err == nil {
+			return t.In(loc).Format(displayTimeFormat), true
+		}
+	}
+	for _, layout := range zonelessTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, time.UTC); err == nil {
+			return t.In(loc).Format(displayTimeFormat), true
+		}
+	}
+	return value, false
+}
+
+// ApplyDisplayTimezone returns a copy of rows with timestamp cells converted to loc.
+// The original rows are returned untouched when no location is set.
+func ApplyDisplayTimezone(rows [][]string, loc *time.Location) [][]string {
+	if loc == nil {
+		return rows
+	}
+	converted := make([][]string, len(rows))
+	for i, row := range rows {
+		out := make([]string, len(row))
+		for j, cell := range row {
+			out[j], _ = ConvertTimestampForDisplay(cell, loc)
+		}
+		converted[i] = out
+	}
+	return converted
+}
+
+// NextDisplayTimezone cycles the session timezone: as stored → local → UTC
+func NextDisplayTimezone(current *time.Location) *time.Location {
+	switch current {
+	case nil:
+		return time.Local
+	case time.Local:
+		return time.UTC
+	default:
+		return nil
+	}
+}
+
+// TimezoneLabel names the applied display timezone for status lines
+func TimezoneLabel(loc *time.Location) string {
+	if loc == nil {
+		return "as stored"
+	}
+	if loc == time.Local {
+		return "Local (" + time.Now().In(loc).Format("MST") + ")"
+	}
+	return loc.String()
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConvertTimestampForDisplay(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name   string
+		value  string
+		loc    *time.Location
+		want   string
+		wantOK bool
+	}{
+		{"no location", "2024-01-01 12:00:00 +0000 UTC", nil, "2024-01-01 12:00:00 +0000 UTC", false},
+		{"driver time string", "2024-01-01 12:00:00 +0000 UTC", tokyo, "2024-01-01 21:00:00 +09:00", true},
+		{"unnamed offset", "2024-01-01 12:00:00.5 +0200 +0200", time.UTC, "2024-01-01 10:00:00.5 +00:00", true},
+		{"rfc3339", "2024-06-30T23:30:00Z", tokyo, "2024-07-01 08:30:00 +09:00", true},
+		{"postgres text", "2024-01-01 12:00:00-05", time.UTC, "2024-01-01 17:00:00 +00:00", true},
+		{"zoneless treated as utc", "2024-01-01 12:00:00", tokyo, "2024-01-01 21:00:00 +09:00", true},
+		{"date only", "2024-01-01", tokyo, "2024-01-01", false},
+		{"not a timestamp", "hello world, again", tokyo, "hello world, again", false},
+		{"null", "NULL", tokyo, "NULL", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ConvertTimestampForDisplay(tt.value, tt.loc)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ConvertTimestampForDisplay(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestApplyDisplayTimezone(t *testing.T) {
+	rows := [][]string{{"1", "2024-01-01 12:00:00 +0000 UTC"}}
+
+	converted := ApplyDisplayTimezone(rows, time.FixedZone("X", 3600))
+	if converted[0][1] != "2024-01-01 13:00:00 +01:00" {
+		t.Errorf("converted cell = %q", converted[0][1])
+	}
+	if rows[0][1] != "2024-01-01 12:00:00 +0000 UTC" {
+		t.Errorf("original row was modified: %q", rows[0][1])
+	}
+}
+
+func TestNextDisplayTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		current *time.Location
+		want    *time.Location
+	}{
+		{"as stored to local", nil, time.Local},
+		{"local to utc", time.Local, time.UTC},
+		{"utc to as stored", time.UTC, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextDisplayTimezone(tt.current); got != tt.want {
+				t.Errorf("NextDisplayTimezone(%v) = %v, want %v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
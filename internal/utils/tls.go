@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// TLSModes are the connection form's TLS modes in the order ctrl+t cycles
+// through them. The empty mode leaves the connection string's own setting alone.
+var TLSModes = []string{"", "disable", "require", "verify-ca", "verify-full"}
+
+// NextTLSMode returns the mode after mode in TLSModes, wrapping around
+func NextTLSMode(mode string) string {
+	for i, m := range TLSModes {
+		if m == mode {
+			return TLSModes[(i+1)%len(TLSModes)]
+		}
+	}
+	return TLSModes[0]
+}
+
+// SupportsTLSOptions reports whether the driver connects over the network and so
+// takes TLS options. Trino connects over TLS when its URL is https://, and
+// Snowflake and BigQuery always do.
+func SupportsTLSOptions(driver string) bool {
+	return driver != "sqlite3" && driver != "trino" && driver != "snowflake" && driver != "bigquery"
+}
+
+// ApplyTLSOptions writes the form's TLS options into the connection string:
+// sslmode, sslrootcert, sslcert, and sslkey for PostgreSQL-style drivers, and
+// the tls parameter for MySQL-style drivers. Certificates for MySQL-style
+// drivers go in tls-verify, tls-ca, tls-cert, and tls-key, which are turned into
+// a registered TLS config when the connection opens. Empty options change nothing.
+func ApplyTLSOptions(driver, connectionStr string, opts models.TLSOptions) (string, error) {
+	if opts == (models.TLSOptions{}) || !SupportsTLSOptions(driver) {
+		return connectionStr, nil
+	}
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return "", fmt.Errorf("a client certificate needs both the certificate and the key file")
+	}
+	hasFiles := opts.CAFile != "" || opts.CertFile != ""
+	if opts.Mode == "disable" && hasFiles {
+		return "", fmt.Errorf("certificates cannot be used with TLS disabled")
+	}
+
+	mode := opts.Mode
+	if mode == "" {
+		// Certificates alone imply the strictest mode they allow
+		mode = "require"
+		if opts.CAFile != "" {
+			mode = "verify-full"
+		}
+	}
+
+	switch driver {
+	case "mysql", "mariadb", "clickhouse":
+		return applyMySQLTLS(connectionStr, mode, opts, hasFiles)
+	default:
+		params := [][2]string{{"sslmode", mode}, {"sslrootcert", opts.CAFile}, {"sslcert", opts.CertFile}, {"sslkey", opts.KeyFile}}
+		if strings.Contains(connectionStr, "://") {
+			base, query, _ := strings.Cut(connectionStr, "?")
+			return joinQuery(base, replaceQueryParams(query, params)), nil
+		}
+		return replaceKeywordParams(connectionStr, params), nil
+	}
+}
+
+// applyMySQLTLS sets the tls parameter of a MySQL DSN, or the certificate
+// parameters when files or chain-only verification are wanted
+func applyMySQLTLS(dsn, mode string, opts models.TLSOptions, hasFiles bool) (string, error) {
+	slash := strings.LastIndex(dsn, "/")
+	if slash < 0 {
+		return "", fmt.Errorf("connection string needs a /database part before TLS options can be added")
+	}
+	base, query, _ := strings.Cut(dsn[slash:], "?")
+	base = dsn[:slash] + base
+
+	drop := []string{"tls", "tls-verify", "tls-ca", "tls-cert", "tls-key"}
+	var params [][2]string
+	if hasFiles || mode == "verify-ca" {
+		verify := map[string]string{"require": "none", "verify-ca": "ca", "verify-full": "full"}[mode]
+		params = [][2]string{{"tls-verify", verify}, {"tls-ca", opts.CAFile}, {"tls-cert", opts.CertFile}, {"tls-key", opts.KeyFile}}
+	} else {
+		value := map[string]string{"disable": "false", "require": "skip-verify", "verify-full": "true"}[mode]
+		params = [][2]string{{"tls", value}}
+	}
+	return joinQuery(base, replaceQueryParams(removeQueryParams(query, drop), params)), nil
+}
+
+// joinQuery puts a query string back on a connection string
+func joinQuery(base, query string) string {
+	if query == "" {
+		return base
+	}
+	return base + "?" + query
+}
+
+// removeQueryParams drops the named parameters from a raw query string
+func removeQueryParams(query string, names []string) string {
+	var kept []string
+	for _, part := range strings.Split(query, "&") {
+		key, _, _ := strings.Cut(part, "=")
+		drop := part == ""
+		for _, name := range names {
+			drop = drop || key == name
+		}
+		if !drop {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+// replaceQueryParams sets parameters in a raw query string, keeping the others
+// as written. Parameters with empty values are removed.
+func replaceQueryParams(query string, params [][2]string) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p[0]
+	}
+	query = removeQueryParams(query, names)
+	for _, p := range params {
+		if p[1] != "" {
+			query = joinParams(query, p[0]+"="+url.QueryEscape(p[1]))
+		}
+	}
+	return query
+}
+
+// joinParams appends one key=value pair to a raw query string
+func joinParams(query, pair string) string {
+	if query == "" {
+		return pair
+	}
+	return query + "&" + pair
+}
+
+// replaceKeywordParams sets parameters in a key=value connection string such as
+// "host=db user=app". Values with spaces or quotes are quoted; empty values are removed.
+func replaceKeywordParams(connectionStr string, params [][2]string) string {
+	var fields []string
+	for _, field := range database.KeywordFields(connectionStr) {
+		key, _, _ := strings.Cut(field, "=")
+		replaced := false
+		for _, p := range params {
+			replaced = replaced || key == p[0]
+		}
+		if !replaced {
+			fields = append(fields, field)
+		}
+	}
+	for _, p := range params {
+		if p[1] == "" {
+			continue
+		}
+		value := p[1]
+		if strings.ContainsAny(value, ` '\`) {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		fields = append(fields, p[0]+"="+value)
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestApplyTLSOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		conn    string
+		opts    models.TLSOptions
+		want    string
+		wantErr bool
+	}{
+		{"no options", "postgres", "postgres://u@h/db?sslmode=disable", models.TLSOptions{}, "postgres://u@h/db?sslmode=disable", false},
+		{"postgres url mode", "postgres", "postgres://u@h/db?sslmode=disable&connect_timeout=5", models.TLSOptions{Mode: "require"}, "postgres://u@h/db?connect_timeout=5&sslmode=require", false},
+		{"postgres url certs", "cockroach", "postgresql://root@h:26257/defaultdb", models.TLSOptions{CAFile: "/certs/ca.crt", CertFile: "/certs/c.crt", KeyFile: "/certs/c.key"}, "postgresql://root@h:26257/defaultdb?sslmode=verify-full&sslrootcert=%2Fcerts%2Fca.crt&sslcert=%2Fcerts%2Fc.crt&sslkey=%2Fcerts%2Fc.key", false},
+		{"postgres keywords", "postgres", "host=h user=u password='a b' sslmode=disable", models.TLSOptions{Mode: "verify-ca", CAFile: "/my certs/ca.pem"}, `host=h user=u password='a b' sslmode=verify-ca sslrootcert='/my certs/ca.pem'`, false},
+		{"mysql native mode", "mysql", "u:p@tcp(h:3306)/db?parseTime=true&tls=false", models.TLSOptions{Mode: "verify-full"}, "u:p@tcp(h:3306)/db?parseTime=true&tls=true", false},
+		{"mysql skip verify", "mariadb", "u:p@tcp(h:3306)/db", models.TLSOptions{Mode: "require"}, "u:p@tcp(h:3306)/db?tls=skip-verify", false},
+		{"mysql certs", "mysql", "u:p@tcp(h:3306)/db?tls=true", models.TLSOptions{CAFile: "/ca.pem"}, "u:p@tcp(h:3306)/db?tls-verify=full&tls-ca=%2Fca.pem", false},
+		{"mysql chain only", "clickhouse", "default:p@tcp(h:9004)/default", models.TLSOptions{Mode: "verify-ca"}, "default:p@tcp(h:9004)/default?tls-verify=ca", false},
+		{"mysql password with slash and question mark", "mysql", "u:p/?x@tcp(h:3306)/db", models.TLSOptions{Mode: "disable"}, "u:p/?x@tcp(h:3306)/db?tls=false", false},
+		{"sqlite ignored", "sqlite3", "./app.db", models.TLSOptions{Mode: "require"}, "./app.db", false},
+		{"cert without key", "postgres", "postgres://u@h/db", models.TLSOptions{CertFile: "/c.crt"}, "", true},
+		{"certs with tls disabled", "mysql", "u@tcp(h)/db", models.TLSOptions{Mode: "disable", CAFile: "/ca.pem"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyTLSOptions(tt.driver, tt.conn, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyTLSOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyTLSOptions() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTLSMode(t *testing.T) {
+	mode := ""
+	for _, want := range []string{"disable", "require", "verify-ca", "verify-full", ""} {
+		mode = NextTLSMode(mode)
+		if mode != want {
+			t.Fatalf("NextTLSMode() = %q, want %q", mode, want)
+		}
+	}
+}
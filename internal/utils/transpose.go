@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// TransposeMaxRows is the most result rows a transposed view shows; each row
+// becomes a column, so more would not fit side by side
+const TransposeMaxRows = 20
+
+// TransposeRows turns result columns into rows: the first column names each
+// result column with its type, and each following column is one result row
+func TransposeRows(columns, types []string, rows [][]string) ([]string, [][]string) {
+	header := make([]string, len(rows)+1)
+	header[0] = "column"
+	for i := range rows {
+		header[i+1] = fmt.Sprintf("#%d", i+1)
+	}
+
+	transposed := make([][]string, len(columns))
+	for i, column := range columns {
+		row := make([]string, len(rows)+1)
+		row[0] = column
+		if i < len(types) && types[i] != "" {
+			row[0] = fmt.Sprintf("%s (%s)", column, types[i])
+		}
+		for j, resultRow := range rows {
+			if i < len(resultRow) {
+				row[j+1] = resultRow[i]
+			}
+		}
+		transposed[i] = row
+	}
+	return header, transposed
+}
+
+// ToggleQueryTranspose switches the query runner's result between rows and
+// columns. Only results of up to TransposeMaxRows rows can be transposed.
+func ToggleQueryTranspose(m models.Model) (models.Model, error) {
+	if !m.QueryResultTransposed && len(m.LastQueryRows) > TransposeMaxRows {
+		return m, fmt.Errorf("only results of up to %d rows can be transposed (this one has %d)", TransposeMaxRows, len(m.LastQueryRows))
+	}
+	updatedModel := m
+	updatedModel.QueryResultTransposed = !m.QueryResultTransposed
+	updatedModel.QueryResultsTable = QueryResultsTable(updatedModel)
+	return updatedModel, nil
+}
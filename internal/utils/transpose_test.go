@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestTransposeRows(t *testing.T) {
+	columns, rows := TransposeRows(
+		[]string{"id", "email", "note"},
+		[]string{"int4", "text"},
+		[][]string{{"1", "a@x.io", "first"}, {"2", "b@x.io"}},
+	)
+	wantColumns := []string{"column", "#1", "#2"}
+	wantRows := [][]string{
+		{"id (int4)", "1", "2"},
+		{"email (text)", "a@x.io", "b@x.io"},
+		{"note", "first", ""},
+	}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("TransposeRows columns = %v, want %v", columns, wantColumns)
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("TransposeRows rows = %v, want %v", rows, wantRows)
+	}
+}
+
+func TestToggleQueryTranspose(t *testing.T) {
+	rows := func(n int) [][]string {
+		out := make([][]string, n)
+		for i := range out {
+			out[i] = []string{fmt.Sprint(i), "x"}
+		}
+		return out
+	}
+	tests := []struct {
+		name       string
+		transposed bool
+		rows       int
+		want       bool
+		wantErr    bool
+	}{
+		{"small result", false, 3, true, false},
+		{"at the limit", false, TransposeMaxRows, true, false},
+		{"too many rows", false, TransposeMaxRows + 1, false, true},
+		{"back to rows", true, 3, false, false},
+	}
+	for _, tt := range tests {
+		m := models.Model{LastQueryColumns: []string{"id", "name"}, LastQueryRows: rows(tt.rows), QueryResultTransposed: tt.transposed}
+		got, err := ToggleQueryTranspose(m)
+		if (err != nil) != tt.wantErr || got.QueryResultTransposed != tt.want {
+			t.Errorf("%s: ToggleQueryTranspose = %v, %v, want %v (error %v)", tt.name, got.QueryResultTransposed, err, tt.want, tt.wantErr)
+		}
+		if tt.want && len(got.QueryResultsTable.Rows()) != 2 {
+			t.Errorf("%s: transposed table has %d rows, want one per column", tt.name, len(got.QueryResultsTable.Rows()))
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/x/ansi"
 )
 
@@ -24,6 +25,9 @@ func InferFieldType(v string) string {
 	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
 		return "Int"
 	}
+	if digits := strings.TrimPrefix(v, "-"); allDigits(digits) {
+		return "Int" // Beyond int64, such as NUMERIC(30) or BIGINT UNSIGNED
+	}
 	if _, err := strconv.ParseFloat(v, 64); err == nil {
 		return "Float"
 	}
@@ -39,6 +43,66 @@ func InferFieldType(v string) string {
 	return "Text"
 }
 
+// FieldTypeLabel names a field's type for display: the database type when the
+// driver reported one, otherwise a guess from the value. Only a real NULL is
+// labelled NULL; the text "NULL" is text.
+func FieldTypeLabel(value, dbType string, isNull bool) string {
+	if dbType != "" {
+		return dbType
+	}
+	if value == "NULL" && !isNull {
+		return "Text"
+	}
+	return InferFieldType(value)
+}
+
+// IsNumericType reports whether a database column type holds numbers, which
+// are right-aligned in result tables
+func IsNumericType(dbType string) bool {
+	family := ClassifyColumnType(dbType)
+	return family == FamilyInteger || family == FamilyDecimal
+}
+
+// AlignNumericCells right-aligns the cells of numeric columns within their
+// widths. types holds the database type of every column and offset is the
+// index of the first column in columns.
+func AlignNumericCells(rows []table.Row, columns []table.Column, types []string, offset int) {
+	for j, col := range columns {
+		if offset+j >= len(types) || !IsNumericType(types[offset+j]) {
+			continue
+		}
+		for _, row := range rows {
+			if j < len(row) {
+				if pad := col.Width - ansi.StringWidth(row[j]); pad > 0 {
+					row[j] = strings.Repeat(" ", pad) + row[j]
+				}
+			}
+		}
+	}
+}
+
+// ColumnTypesRow lays out column types under table headers of the given widths,
+// centered like the headers and allowing for the one-space cell padding on each
+// side. It is empty when no type is known.
+func ColumnTypesRow(types []string, widths []int) string {
+	known := false
+	var row strings.Builder
+	for i, w := range widths {
+		t := ""
+		if i < len(types) {
+			t = TruncateWithEllipsis(types[i], w, "…")
+		}
+		known = known || t != ""
+		left := (w - ansi.StringWidth(t)) / 2
+		right := w - ansi.StringWidth(t) - left
+		row.WriteString(" " + strings.Repeat(" ", max(left, 0)) + t + strings.Repeat(" ", max(right, 0)) + " ")
+	}
+	if !known {
+		return ""
+	}
+	return strings.TrimRight(row.String(), " ")
+}
+
 // LooksLikeDateTime attempts to detect datetime format
 func LooksLikeDateTime(s string) bool {
 	if s == "" {
@@ -1,6 +1,11 @@
 package utils
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
 
 func TestInferFieldType(t *testing.T) {
 	tests := []struct {
@@ -16,6 +21,7 @@ func TestInferFieldType(t *testing.T) {
 		{"boolean FALSE", "FALSE", "Bool"},
 		{"integer", "42", "Int"},
 		{"negative integer", "-123", "Int"},
+		{"integer beyond int64", "123456789012345678901", "Int"},
 		{"float", "3.14", "Float"},
 		{"negative float", "-2.5", "Float"},
 		{"json object", "{\"key\": \"value\"}", "JSON"},
@@ -36,6 +42,54 @@ func TestInferFieldType(t *testing.T) {
 	}
 }
 
+func TestFieldTypeLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		dbType string
+		isNull bool
+		want   string
+	}{
+		{"zip code stored as text", "02134", "varchar", false, "varchar"},
+		{"version string", "1.10", "text", false, "text"},
+		{"null keeps the column type", "NULL", "int4", true, "int4"},
+		{"unknown type falls back to inference", "42", "", false, "Int"},
+		{"real null without a type", "NULL", "", true, "NULL"},
+		{"the text NULL", "NULL", "", false, "Text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldTypeLabel(tt.value, tt.dbType, tt.isNull); got != tt.want {
+				t.Errorf("FieldTypeLabel(%q, %q, %v) = %q, want %q", tt.value, tt.dbType, tt.isNull, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnTypesRow(t *testing.T) {
+	tests := []struct {
+		name   string
+		types  []string
+		widths []int
+		want   string
+	}{
+		{"centered under headers", []string{"int4", "text"}, []int{8, 6}, "   int4     text"},
+		{"truncated to the width", []string{"timestamptz"}, []int{6}, " times…"},
+		{"fewer types than columns", []string{"int4"}, []int{4, 4}, " int4"},
+		{"no known types", []string{"", ""}, []int{4, 4}, ""},
+		{"no types", nil, []int{4}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ColumnTypesRow(tt.types, tt.widths); got != tt.want {
+				t.Errorf("ColumnTypesRow(%q, %v) = %q, want %q", tt.types, tt.widths, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLooksLikeDateTime(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -113,3 +167,19 @@ func TestTruncateWithEllipsis(t *testing.T) {
 		})
 	}
 }
+
+func TestAlignNumericCells(t *testing.T) {
+	columns := []table.Column{{Title: "id", Width: 5}, {Title: "name", Width: 5}}
+	rows := []table.Row{{"7", "ann"}, {"NULL", ""}, {"123456", "bo"}}
+	AlignNumericCells(rows, columns, []string{"int8", "varchar", "text"}, 1)
+	want := []table.Row{{"7", "ann"}, {"NULL", ""}, {"123456", "bo"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("offset columns: got %q, want %q", rows, want)
+	}
+
+	AlignNumericCells(rows, columns, []string{"int8", "text"}, 0)
+	want = []table.Row{{"    7", "ann"}, {" NULL", ""}, {"123456", "bo"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %q, want %q", rows, want)
+	}
+}
@@ -126,7 +126,7 @@ func IsBooleanLike(s string) bool {
 }
 
 // CreateVisibleColumnsAndRows handles horizontal scrolling for tables with enhanced UX
-func CreateVisibleColumnsAndRows(columns []string, rows [][]string, scrollOffset, visibleCols int, colWidths []int, sortColumn string, sortDirection models.SortDirection) ([]table.Column, []table.Row) {
+func CreateVisibleColumnsAndRows(columns []string, rows [][]string, scrollOffset, visibleCols int, colWidths []int, sortKeys []models.SortKey) ([]table.Column, []table.Row) {
 	if len(columns) == 0 || scrollOffset >= len(columns) {
 		return []table.Column{}, []table.Row{}
 	}
@@ -144,14 +144,7 @@ func CreateVisibleColumnsAndRows(columns []string, rows [][]string, scrollOffset
 		columnTitle := c
 
 		// Add sorting indicators to column headers
-		if c == sortColumn {
-			switch sortDirection {
-			case models.SortAsc:
-				columnTitle = c + " ↑"
-			case models.SortDesc:
-				columnTitle = c + " ↓"
-			}
-		}
+		columnTitle += SortKeyIndicator(sortKeys, c)
 
 		cols[i] = table.Column{Title: columnTitle, Width: colWidths[scrollOffset+i]}
 	}
@@ -195,6 +188,8 @@ func CreateVisibleColumnsAndRows(columns []string, rows [][]string, scrollOffset
 				} else {
 					visibleCells[j] = cell
 				}
+				// Byte-based truncation can split a multibyte character; drop the partial bytes
+				visibleCells[j] = strings.ToValidUTF8(visibleCells[j], "")
 			} else {
 				visibleCells[j] = ""
 			}
@@ -216,8 +211,19 @@ func CreateDataPreviewTable(m models.Model) models.Model {
 	availableWidth := m.Width - h - 4
 	availableWidth = max(availableWidth, 20)
 
+	// Timestamps are shown in the session timezone, undecodable bytes are
+	// replaced for the terminal, arrays and composites are shown by element,
+	// duration columns may be humanized, and numbers may be grouped; the stored
+	// rows stay untouched
+	displayRows := SanitizeRowsForDisplay(ApplyDisplayTimezone(m.DataPreviewAllRows, m.DisplayTimezone))
+	displayRows = ApplyStructuredValues(displayRows, m.DataPreviewColumnTypes)
+	displayRows = ApplyDurationColumns(displayRows, m.DataPreviewAllColumns, PreviewDurationColumns(m))
+	if m.GroupNumberDigits {
+		displayRows = ApplyNumberSeparators(displayRows, m.DataPreviewColumnTypes)
+	}
+
 	// Calculate column widths
-	colWidths := CalculateColumnWidths(m.DataPreviewAllColumns, m.DataPreviewAllRows)
+	colWidths := CalculateColumnWidths(m.DataPreviewAllColumns, displayRows)
 
 	// Compute how many columns fit starting from the current scroll offset
 	startCol := m.DataPreviewScrollOffset
@@ -240,10 +246,17 @@ func CreateDataPreviewTable(m models.Model) models.Model {
 	visibleCount = max(visibleCount, 0)
 
 	// Create visible columns and rows with sorting indicators
-	cols, rows := CreateVisibleColumnsAndRows(m.DataPreviewAllColumns, m.DataPreviewAllRows, startCol, visibleCount, colWidths, m.DataPreviewSortColumn, m.DataPreviewSortDirection)
+	cols, rows := CreateVisibleColumnsAndRows(m.DataPreviewAllColumns, displayRows, startCol, visibleCount, colWidths, PreviewSortKeys(m))
+	AlignNumericCells(rows, cols, m.DataPreviewColumnTypes, startCol)
 
 	// Compute dynamic height to use remaining vertical space
 	reserved := 10 // Title + info + help, approximate
+	if len(m.DataPreviewColumnTypes) > 0 {
+		reserved++ // Column types row
+	}
+	if m.DataPreviewShowSummary {
+		reserved++ // Page summary row
+	}
 	availableHeight := m.Height - v - reserved
 	availableHeight = max(availableHeight, 5)
 
@@ -261,23 +274,39 @@ func CreateDataPreviewTable(m models.Model) models.Model {
 	return updatedModel
 }
 
-// UpdateSavedConnectionsList refreshes the saved connections list items
+// UpdateSavedConnectionsList refreshes the saved connections list items, keeping
+// the list's group filter, folded groups, search, and health badges
 func UpdateSavedConnectionsList(m models.Model) models.Model {
-	savedItems := UpdateSavedConnectionsItems(m.SavedConnections)
+	savedItems := SavedConnectionsItems(SortConnections(m.SavedConnections, m.ConnectionSort), m.CollapsedGroups, m.ConnectionGroupFilter, m.ConnectionSearchInput.Value())
+	savedItems = AnnotateConnectionHealth(savedItems, m.ConnectionHealth)
 	updatedModel := m
 	updatedModel.SavedConnectionsList.SetItems(savedItems)
 	return updatedModel
 }
 
-// UpdateRowDetailList creates field items for row detail view
-func UpdateRowDetailList(columns []string, rowData []string) []list.Item {
+// UpdateRowDetailList creates field items for row detail view. types holds the
+// database type of each column and nulls marks NULL values; both may be shorter
+// than columns.
+func UpdateRowDetailList(columns, types []string, rowData []string, nulls []bool, loc *time.Location) []list.Item {
 	items := make([]list.Item, len(columns))
 	for i, col := range columns {
 		if i < len(rowData) {
-			items[i] = models.FieldItem{
+			item := models.FieldItem{
 				Name:  col,
 				Value: rowData[i],
 			}
+			if i < len(types) {
+				item.Type = types[i]
+			}
+			if i < len(nulls) {
+				item.IsNull = nulls[i]
+			}
+			if converted, ok := ConvertTimestampForDisplay(rowData[i], loc); ok {
+				item.Display = converted
+			} else if sv, ok := ParseStructuredValue(rowData[i], item.Type); ok && !item.IsNull {
+				item.Display = StructuredInlineText(sv)
+			}
+			items[i] = item
 		} else {
 			items[i] = models.FieldItem{
 				Name:  col,
@@ -314,7 +343,7 @@ func ClearErrorTimeout(m models.Model) models.Model {
 	return updatedModel
 }
 
-// UpdateSavedConnectionsItems creates list items from saved connections
+// UpdateSavedConnectionsItems creates list items from saved connections, without group headers
 func UpdateSavedConnectionsItems(connections []models.SavedConnection) []list.Item {
 	items := make([]list.Item, len(connections))
 	for i, conn := range connections {
@@ -322,9 +351,33 @@ func UpdateSavedConnectionsItems(connections []models.SavedConnection) []list.It
 		if len(connStr) > 50 {
 			connStr = connStr[:50] + "..."
 		}
+		desc := fmt.Sprintf("%s - %s", conn.Driver, connStr)
+		if env := EnvironmentBadge(conn.Environment); env != "" {
+			desc += " • " + env
+		}
+		if conn.ReadOnly && conn.Immutable {
+			desc += " • 🔒 read-only, immutable"
+		} else if conn.ReadOnly {
+			desc += " • 🔒 read-only"
+		}
+		if conn.ReplicaConnectionStr != "" {
+			desc += " • 📡 replica"
+		}
+		if defaults := ConnectionDefaultsBadge(conn); defaults != "" {
+			desc += " • ⚙️ " + defaults
+		}
+		if len(conn.Tags) > 0 {
+			desc += " • 🏷 " + FormatTags(conn.Tags)
+		}
+		if conn.Notes != "" {
+			desc += " • 📝 " + NotePreview(conn.Notes, 40)
+		}
+		if used := LastUsedLabel(conn.LastUsedAt, time.Now()); used != "" {
+			desc += fmt.Sprintf(" • 🕘 last used %s (%d×)", used, conn.UseCount)
+		}
 		items[i] = models.Item{
 			ItemTitle: conn.Name,
-			ItemDesc:  fmt.Sprintf("%s - %s", conn.Driver, connStr),
+			ItemDesc:  desc,
 		}
 	}
 	return items
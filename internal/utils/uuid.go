@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of identifier DetectIdentifier recognizes
+const (
+	IdentifierUUID = "UUID"
+	IdentifierULID = "ULID"
+)
+
+// crockfordBase32 is the alphabet of ULIDs, which skips I, L, O, and U
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// IdentifierInfo describes a UUID or ULID value
+type IdentifierInfo struct {
+	Kind    string    // IdentifierUUID or IdentifierULID
+	Version int       // UUID version, 0 for ULIDs
+	Time    time.Time // Creation time embedded in ULIDs and version 7 UUIDs; zero otherwise
+}
+
+// DetectIdentifier recognizes a canonical UUID (8-4-4-4-12 hex digits) or a
+// ULID (26 Crockford base32 characters) and decodes the creation time that
+// ULIDs and version 7 UUIDs carry in their first 48 bits
+func DetectIdentifier(value string) (IdentifierInfo, bool) {
+	if info, ok := detectUUID(value); ok {
+		return info, true
+	}
+	return detectULID(value)
+}
+
+func detectUUID(value string) (IdentifierInfo, bool) {
+	if len(value) != 36 {
+		return IdentifierInfo{}, false
+	}
+	for i, r := range value {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if r != '-' {
+				return IdentifierInfo{}, false
+			}
+		} else if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return IdentifierInfo{}, false
+		}
+	}
+
+	version, _ := strconv.ParseUint(value[14:15], 16, 8)
+	info := IdentifierInfo{Kind: IdentifierUUID, Version: int(version)}
+	// Version 7 starts with the Unix time in milliseconds
+	if info.Version == 7 {
+		ms, _ := strconv.ParseInt(value[:8]+value[9:13], 16, 64)
+		info.Time = time.UnixMilli(ms).UTC()
+	}
+	return info, true
+}
+
+func detectULID(value string) (IdentifierInfo, bool) {
+	// The first character is at most 7, since 26 characters hold 130 bits and a
+	// ULID has 128; all digits is more likely a number than a ULID
+	if len(value) != 26 || value[0] > '7' || allDigits(value) {
+		return IdentifierInfo{}, false
+	}
+	var ms int64
+	for i, r := range strings.ToUpper(value) {
+		digit := strings.IndexRune(crockfordBase32, r)
+		if digit < 0 {
+			return IdentifierInfo{}, false
+		}
+		// The first 10 characters are the Unix time in milliseconds
+		if i < 10 {
+			ms = ms<<5 | int64(digit)
+		}
+	}
+	return IdentifierInfo{Kind: IdentifierULID, Time: time.UnixMilli(ms).UTC()}, true
+}
+
+// DescribeIdentifier names an identifier's kind and version, with its creation
+// time in loc (UTC when loc is nil), e.g. "UUID v7 • created 2024-05-01 12:00:00.123 +00:00"
+func DescribeIdentifier(info IdentifierInfo, loc *time.Location) string {
+	label := info.Kind
+	if info.Kind == IdentifierUUID && info.Version > 0 {
+		label = fmt.Sprintf("UUID v%d", info.Version)
+	}
+	if info.Time.IsZero() {
+		return label
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return label + " • created " + info.Time.In(loc).Format("2006-01-02 15:04:05.000 -07:00")
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectIdentifier(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   IdentifierInfo
+		wantOK bool
+	}{
+		{"uuid v4", "f47ac10b-58cc-4372-a567-0e02b2c3d479", IdentifierInfo{Kind: IdentifierUUID, Version: 4}, true},
+		{"uuid v7 carries its time", "017F22E2-79B0-7CC3-98C4-DC0C0C07398F", IdentifierInfo{Kind: IdentifierUUID, Version: 7, Time: time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)}, true},
+		{"nil uuid", "00000000-0000-0000-0000-000000000000", IdentifierInfo{Kind: IdentifierUUID}, true},
+		{"ulid carries its time", "01ARZ3NDEKTSV4RRFFQ69G5FAV", IdentifierInfo{Kind: IdentifierULID, Time: time.Date(2016, 7, 30, 23, 54, 10, 259e6, time.UTC)}, true},
+		{"lowercase ulid", "01arz3ndektsv4rrffq69g5fav", IdentifierInfo{Kind: IdentifierULID, Time: time.Date(2016, 7, 30, 23, 54, 10, 259e6, time.UTC)}, true},
+		{"uuid without dashes", "f47ac10b58cc4372a5670e02b2c3d479", IdentifierInfo{}, false},
+		{"uuid with a non-hex digit", "g47ac10b-58cc-4372-a567-0e02b2c3d479", IdentifierInfo{}, false},
+		{"ulid overflowing 128 bits", "81ARZ3NDEKTSV4RRFFQ69G5FAV", IdentifierInfo{}, false},
+		{"ulid with a letter outside the alphabet", "01ARZ3NDEKTSV4RRFFQ69G5FAU", IdentifierInfo{}, false},
+		{"26 digit number", "12345678901234567890123456", IdentifierInfo{}, false},
+		{"text", "hello", IdentifierInfo{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectIdentifier(tt.value)
+			if ok != tt.wantOK || got.Kind != tt.want.Kind || got.Version != tt.want.Version || !got.Time.Equal(tt.want.Time) {
+				t.Errorf("DetectIdentifier(%q) = %+v, %v, want %+v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDescribeIdentifier(t *testing.T) {
+	created := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	tests := []struct {
+		name string
+		info IdentifierInfo
+		loc  *time.Location
+		want string
+	}{
+		{"uuid v4", IdentifierInfo{Kind: IdentifierUUID, Version: 4}, nil, "UUID v4"},
+		{"nil uuid", IdentifierInfo{Kind: IdentifierUUID}, nil, "UUID"},
+		{"uuid v7 in UTC", IdentifierInfo{Kind: IdentifierUUID, Version: 7, Time: created}, nil, "UUID v7 • created 2022-02-22 19:22:22.000 +00:00"},
+		{"ulid in the display timezone", IdentifierInfo{Kind: IdentifierULID, Time: created}, time.FixedZone("", 2*3600), "ULID • created 2022-02-22 21:22:22.000 +02:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeIdentifier(tt.info, tt.loc); got != tt.want {
+				t.Errorf("DescribeIdentifier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
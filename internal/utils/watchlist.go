@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// WatchlistIntervals are the auto-refresh intervals i cycles through; 0 is off
+var WatchlistIntervals = []time.Duration{0, 5 * time.Second, 15 * time.Second, time.Minute}
+
+// NextWatchlistInterval returns the interval after current, wrapping to off
+func NextWatchlistInterval(current time.Duration) time.Duration {
+	for i, interval := range WatchlistIntervals {
+		if interval == current {
+			return WatchlistIntervals[(i+1)%len(WatchlistIntervals)]
+		}
+	}
+	return WatchlistIntervals[0]
+}
+
+// FindWatchedRow returns the position of a row in the watchlist, or -1
+func FindWatchedRow(watchlist []models.WatchedRow, schema, table, keyValue string) int {
+	for i, row := range watchlist {
+		if row.Schema == schema && row.Table == table && row.KeyValue == keyValue {
+			return i
+		}
+	}
+	return -1
+}
+
+// ToggleWatchedRow pins a row of the selected table to the watchlist by its
+// primary key, or unpins it when it is already watched
+func ToggleWatchedRow(m models.Model, columns, row []string, nulls []bool) (models.Model, tea.Cmd) {
+	keyColumn, keyValue, err := FindPrimaryKeyColumn(columns, row)
+	if err != nil {
+		return SetErrorWithTimeout(m, fmt.Errorf("cannot watch this row: %w", err), 3*time.Second)
+	}
+
+	updatedModel := m
+	label := fmt.Sprintf("%s %s=%s", m.SelectedTable, keyColumn, keyValue)
+	if i := FindWatchedRow(m.Watchlist, m.SelectedSchema, m.SelectedTable, keyValue); i >= 0 {
+		updatedModel.Watchlist = append(append([]models.WatchedRow(nil), m.Watchlist[:i]...), m.Watchlist[i+1:]...)
+		updatedModel.QueryResult = "👁 Stopped watching " + label
+		return updatedModel, ClearResultAfterTimeout()
+	}
+
+	updatedModel.Watchlist = append(append([]models.WatchedRow(nil), m.Watchlist...), models.WatchedRow{
+		Table:       m.SelectedTable,
+		Schema:      m.SelectedSchema,
+		KeyColumn:   keyColumn,
+		KeyValue:    keyValue,
+		Columns:     append([]string(nil), columns...),
+		Values:      append([]string(nil), row...),
+		Nulls:       append([]bool(nil), nulls...),
+		RefreshedAt: time.Now(),
+	})
+	updatedModel.QueryResult = fmt.Sprintf("👁 Watching %s • W opens the watchlist (%d rows)", label, len(updatedModel.Watchlist))
+	return updatedModel, ClearResultAfterTimeout()
+}
+
+// OpenWatchlist shows the watchlist and refreshes its rows
+func OpenWatchlist(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel := PushView(m, models.WatchlistView, models.NavParams{})
+	updatedModel.Err = nil
+	updatedModel.QueryResult = ""
+	updatedModel = BuildWatchlistTable(updatedModel)
+	return RefreshWatchlist(updatedModel)
+}
+
+// RefreshWatchlist re-reads every watched row
+func RefreshWatchlist(m models.Model) (models.Model, tea.Cmd) {
+	if len(m.Watchlist) == 0 || m.DB == nil || m.IsRefreshingWatchlist {
+		return m, nil
+	}
+	updatedModel := m
+	updatedModel.IsRefreshingWatchlist = true
+	// Watched rows are followed on the primary, which a lagging replica could show stale
+	return updatedModel, LoadWatchlist(m.DB, m.SelectedDB, m.Watchlist)
+}
+
+// LoadWatchlist reads each watched row again by its primary key
+func LoadWatchlist(db *sql.DB, selectedDB models.DBType, watchlist []models.WatchedRow) tea.Cmd {
+	rows := append([]models.WatchedRow(nil), watchlist...)
+	return tea.Cmd(func() tea.Msg {
+		result := models.WatchlistRefreshResult{Rows: make([]models.WatchedRowValues, len(rows))}
+		for i, row := range rows {
+			cols, values, nulls, err := database.GetRowByKey(db, selectedDB.Driver, row.Table, row.Schema, row.KeyColumn, row.KeyValue)
+			result.Rows[i] = models.WatchedRowValues{Columns: cols, Values: values, Nulls: nulls, Err: err}
+		}
+		result.At = time.Now()
+		return result
+	})
+}
+
+// HandleWatchlistRefreshResult stores the re-read rows with the fields that
+// changed since the previous refresh, and schedules the next auto-refresh
+func HandleWatchlistRefreshResult(m models.Model, msg models.WatchlistRefreshResult) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.IsRefreshingWatchlist = false
+	updatedModel.Watchlist = append([]models.WatchedRow(nil), m.Watchlist...)
+
+	// Rows unpinned while the refresh ran leave the results misaligned, so they wait for the next one
+	if len(msg.Rows) == len(m.Watchlist) {
+		for i, fresh := range msg.Rows {
+			updatedModel.Watchlist[i] = ApplyWatchedRowRefresh(m.Watchlist[i], fresh, msg.At)
+		}
+	}
+
+	cursor := m.WatchlistTable.Cursor()
+	updatedModel = BuildWatchlistTable(updatedModel)
+	updatedModel.WatchlistTable.SetCursor(cursor)
+	return ScheduleWatchlistTick(updatedModel)
+}
+
+// ApplyWatchedRowRefresh updates a watched row with its re-read values. Changed
+// fields are the ones that differ from the previous refresh; a row that is gone
+// keeps its last values.
+func ApplyWatchedRowRefresh(row models.WatchedRow, fresh models.WatchedRowValues, at time.Time) models.WatchedRow {
+	updated := row
+	updated.Err = fresh.Err
+	if fresh.Err != nil {
+		return updated
+	}
+	updated.RefreshedAt = at
+	updated.Missing = fresh.Values == nil
+	if updated.Missing {
+		updated.Changed = nil
+		return updated
+	}
+
+	before := AlignRow(fresh.Columns, row.Columns, row.Values)
+	beforeNulls := AlignNulls(fresh.Columns, row.Columns, row.Nulls)
+	updated.Changed = nil
+	for i, col := range fresh.Columns {
+		a, aNull := before[i], beforeNulls[i]
+		b, bNull := diffValue(models.RowDiffSide{Values: fresh.Values, Nulls: fresh.Nulls}, i)
+		if RowValuesDiffer(a, b, aNull, bNull) {
+			updated.Changed = append(updated.Changed, models.FieldChange{
+				Column: col,
+				Before: displayDiffValue(a, aNull),
+				After:  displayDiffValue(b, bNull),
+			})
+		}
+	}
+	updated.Columns = fresh.Columns
+	updated.Values = fresh.Values
+	updated.Nulls = fresh.Nulls
+	return updated
+}
+
+// ScheduleWatchlistTick starts the wait for the next auto-refresh. The sequence
+// number is bumped so ticks scheduled earlier are ignored.
+func ScheduleWatchlistTick(m models.Model) (models.Model, tea.Cmd) {
+	if m.WatchlistInterval <= 0 || m.State != models.WatchlistView {
+		return m, nil
+	}
+	m.WatchlistSeq++
+	seq := m.WatchlistSeq
+	return m, tea.Tick(m.WatchlistInterval, func(time.Time) tea.Msg {
+		return models.WatchlistTickMsg{Seq: seq}
+	})
+}
+
+// HandleWatchlistTick refreshes the watchlist while it is on screen with auto-refresh on
+func HandleWatchlistTick(m models.Model, msg models.WatchlistTickMsg) (models.Model, tea.Cmd) {
+	if msg.Seq != m.WatchlistSeq || m.WatchlistInterval <= 0 || m.State != models.WatchlistView {
+		return m, nil
+	}
+	return RefreshWatchlist(m)
+}
+
+// WatchedRowChanges describes what happened to a watched row at its last
+// refresh, e.g. "✎ status: queued → running"
+func WatchedRowChanges(row models.WatchedRow) string {
+	switch {
+	case row.Err != nil:
+		return "⚠ " + row.Err.Error()
+	case row.Missing:
+		return "✖ no row matches the key anymore"
+	case len(row.Changed) == 0:
+		return "no changes"
+	}
+	changes := make([]string, len(row.Changed))
+	for i, change := range row.Changed {
+		changes[i] = fmt.Sprintf("%s: %s → %s", change.Column, change.Before, change.After)
+	}
+	return "✎ " + strings.Join(changes, ", ")
+}
+
+// WatchedRowValues lists a watched row's fields as "column=value", leaving out
+// its key, which the watchlist shows separately
+func WatchedRowValues(row models.WatchedRow) string {
+	var fields []string
+	for i, col := range row.Columns {
+		if col == row.KeyColumn {
+			continue
+		}
+		value, null := diffValue(models.RowDiffSide{Values: row.Values, Nulls: row.Nulls}, i)
+		fields = append(fields, col+"="+displayDiffValue(value, null))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// BuildWatchlistRows lists each watched row with its changes and current values
+func BuildWatchlistRows(watchlist []models.WatchedRow) []table.Row {
+	rows := make([]table.Row, len(watchlist))
+	for i, row := range watchlist {
+		rows[i] = table.Row{
+			row.Table,
+			row.KeyColumn + "=" + row.KeyValue,
+			WatchedRowChanges(row),
+			WatchedRowValues(row),
+			row.RefreshedAt.Format("15:04:05"),
+		}
+	}
+	return rows
+}
+
+// BuildWatchlistTable shows the watchlist in a table sized to the screen
+func BuildWatchlistTable(m models.Model) models.Model {
+	updatedModel := m
+	flexible := Max(m.Width-20-16-10-16, 40)
+	columns := []table.Column{
+		{Title: "Table", Width: 20},
+		{Title: "Key", Width: 16},
+		{Title: "Changes", Width: flexible / 2},
+		{Title: "Values", Width: flexible - flexible/2},
+		{Title: "Refreshed", Width: 10},
+	}
+
+	_, v := styles.DocStyle.GetFrameSize()
+	updatedModel.WatchlistTable = table.New(
+		table.WithColumns(columns),
+		table.WithRows(BuildWatchlistRows(m.Watchlist)),
+		table.WithFocused(true),
+		table.WithHeight(Max(m.Height-v-12, 5)),
+	)
+	updatedModel.WatchlistTable.SetStyles(styles.GetBlueTableStyles())
+	return updatedModel
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestNextWatchlistInterval(t *testing.T) {
+	tests := []struct {
+		current time.Duration
+		want    time.Duration
+	}{
+		{0, 5 * time.Second},
+		{15 * time.Second, time.Minute},
+		{time.Minute, 0},
+		{7 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		if got := NextWatchlistInterval(tt.current); got != tt.want {
+			t.Errorf("NextWatchlistInterval(%v) = %v, want %v", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestFindWatchedRow(t *testing.T) {
+	watchlist := []models.WatchedRow{
+		{Schema: "public", Table: "orders", KeyValue: "1"},
+		{Schema: "public", Table: "jobs", KeyValue: "1"},
+	}
+	if got := FindWatchedRow(watchlist, "public", "jobs", "1"); got != 1 {
+		t.Errorf("FindWatchedRow() = %d, want 1", got)
+	}
+	if got := FindWatchedRow(watchlist, "archive", "jobs", "1"); got != -1 {
+		t.Errorf("FindWatchedRow() in another schema = %d, want -1", got)
+	}
+}
+
+func TestApplyWatchedRowRefresh(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	row := models.WatchedRow{
+		KeyColumn: "id",
+		KeyValue:  "7",
+		Columns:   []string{"id", "status", "error"},
+		Values:    []string{"7", "queued", ""},
+		Nulls:     []bool{false, false, true},
+	}
+
+	changed := ApplyWatchedRowRefresh(row, models.WatchedRowValues{
+		Columns: []string{"id", "status", "error"},
+		Values:  []string{"7", "failed", "timeout"},
+		Nulls:   []bool{false, false, false},
+	}, at)
+	want := []models.FieldChange{
+		{Column: "status", Before: "queued", After: "failed"},
+		{Column: "error", Before: "(NULL)", After: "timeout"},
+	}
+	if !reflect.DeepEqual(changed.Changed, want) || !changed.RefreshedAt.Equal(at) || changed.Values[1] != "failed" {
+		t.Errorf("ApplyWatchedRowRefresh() changes = %+v, want %+v", changed.Changed, want)
+	}
+	if got := WatchedRowChanges(changed); got != "✎ status: queued → failed, error: (NULL) → timeout" {
+		t.Errorf("WatchedRowChanges() = %q", got)
+	}
+
+	unchanged := ApplyWatchedRowRefresh(changed, models.WatchedRowValues{Columns: changed.Columns, Values: changed.Values, Nulls: changed.Nulls}, at)
+	if len(unchanged.Changed) != 0 || WatchedRowChanges(unchanged) != "no changes" {
+		t.Errorf("ApplyWatchedRowRefresh() of the same values = %+v, want no changes", unchanged.Changed)
+	}
+
+	gone := ApplyWatchedRowRefresh(changed, models.WatchedRowValues{Columns: changed.Columns}, at)
+	if !gone.Missing || gone.Values[1] != "failed" {
+		t.Errorf("ApplyWatchedRowRefresh() of a deleted row = %+v, want missing with last values kept", gone)
+	}
+
+	failed := ApplyWatchedRowRefresh(changed, models.WatchedRowValues{Err: errors.New("timeout")}, at.Add(time.Minute))
+	if failed.Err == nil || !failed.RefreshedAt.Equal(at) || WatchedRowChanges(failed) != "⚠ timeout" {
+		t.Errorf("ApplyWatchedRowRefresh() with an error = %+v", failed)
+	}
+}
+
+func TestWatchedRowValues(t *testing.T) {
+	row := models.WatchedRow{
+		KeyColumn: "id",
+		Columns:   []string{"id", "status", "note"},
+		Values:    []string{"7", "running", ""},
+		Nulls:     []bool{false, false, true},
+	}
+	if got, want := WatchedRowValues(row), "status=running, note=(NULL)"; got != want {
+		t.Errorf("WatchedRowValues() = %q, want %q", got, want)
+	}
+}
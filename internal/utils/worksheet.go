@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// worksheetKey identifies the open connection in the worksheet store
+func worksheetKey(m models.Model) string {
+	return connectionStoreKey(m.SelectedDB.Driver, m.ConnectionStr, "")
+}
+
+// StartWorksheet restores the query editor of a new connection from its
+// autosaved worksheet, empty when it has none, and starts the autosave
+// schedule. Ticks of earlier connections are ignored.
+func StartWorksheet(m models.Model) (models.Model, tea.Cmd) {
+	updatedModel := m
+	updatedModel.WorksheetSession++
+	worksheet, _ := config.LoadWorksheet(worksheetKey(m))
+	updatedModel.QueryInput.SetValue(worksheet.Query)
+	updatedModel.WorksheetSaved = worksheet.Query
+	updatedModel.WorksheetRestored = worksheet.SavedAt
+	return updatedModel, ScheduleWorksheetSave(updatedModel.WorksheetSession)
+}
+
+// ScheduleWorksheetSave waits one autosave interval (MIRADOR_AUTOSAVE_INTERVAL)
+// before the next save
+func ScheduleWorksheetSave(session int) tea.Cmd {
+	interval := config.WorksheetInterval()
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return models.WorksheetTickMsg{Session: session}
+	})
+}
+
+// HandleWorksheetTick saves the query editor when it changed since the last
+// save and schedules the next one
+func HandleWorksheetTick(m models.Model, msg models.WorksheetTickMsg) (models.Model, tea.Cmd) {
+	if msg.Session != m.WorksheetSession || m.ConnectionStr == "" {
+		return m, nil
+	}
+	return SaveWorksheet(m), ScheduleWorksheetSave(m.WorksheetSession)
+}
+
+// SaveWorksheet writes the query editor to the worksheet of the open
+// connection when it changed since the last save. A failed save is tried again
+// on the next tick. Nothing is saved while autosave is off.
+func SaveWorksheet(m models.Model) models.Model {
+	query := m.QueryInput.Value()
+	if m.ConnectionStr == "" || query == m.WorksheetSaved || config.WorksheetInterval() <= 0 {
+		return m
+	}
+	if err := config.SaveWorksheet(worksheetKey(m), query, time.Now()); err != nil {
+		return m
+	}
+	updatedModel := m
+	updatedModel.WorksheetSaved = query
+	updatedModel.WorksheetRestored = time.Time{}
+	return updatedModel
+}
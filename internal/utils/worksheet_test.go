@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestWorksheetAutosave(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MIRADOR_AUTOSAVE_INTERVAL", "5s")
+
+	connect := func(connectionStr string) models.Model {
+		m := models.Model{SelectedDB: models.DBType{Driver: "postgres"}, ConnectionStr: connectionStr, QueryInput: textarea.New()}
+		m, cmd := StartWorksheet(m)
+		if cmd == nil {
+			t.Fatalf("StartWorksheet() scheduled no autosave")
+		}
+		return m
+	}
+
+	m := connect("postgres://app@db/shop")
+	if got := m.QueryInput.Value(); got != "" {
+		t.Fatalf("StartWorksheet() without a saved worksheet = %q, want empty", got)
+	}
+	query := "SELECT *\nFROM orders o\nJOIN customers c ON c.id = o.customer_id"
+	m.QueryInput.SetValue(query)
+	m, cmd := HandleWorksheetTick(m, models.WorksheetTickMsg{Session: m.WorksheetSession})
+	if cmd == nil || m.WorksheetSaved != query {
+		t.Fatalf("HandleWorksheetTick() saved %q with next tick %v, want %q scheduled", m.WorksheetSaved, cmd != nil, query)
+	}
+	if _, cmd := HandleWorksheetTick(m, models.WorksheetTickMsg{Session: m.WorksheetSession - 1}); cmd != nil {
+		t.Errorf("HandleWorksheetTick() of an earlier connection scheduled another tick")
+	}
+
+	restored := connect("postgres://app@db/shop")
+	if got := restored.QueryInput.Value(); got != query || restored.WorksheetRestored.IsZero() {
+		t.Errorf("StartWorksheet() restored %q (at %v), want %q", got, restored.WorksheetRestored, query)
+	}
+	if other := connect("postgres://app@db/billing"); other.QueryInput.Value() != "" {
+		t.Errorf("StartWorksheet() of another connection restored %q", other.QueryInput.Value())
+	}
+
+	restored.QueryInput.SetValue("  ")
+	restored = SaveWorksheet(restored)
+	if !restored.WorksheetRestored.IsZero() {
+		t.Errorf("SaveWorksheet() kept the restored notice after an edit")
+	}
+	if got := connect("postgres://app@db/shop").QueryInput.Value(); got != "" {
+		t.Errorf("StartWorksheet() after a blank save restored %q, want empty", got)
+	}
+}
@@ -0,0 +1,285 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/database"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+// WorkspaceReport counts what a workspace export or import carried
+type WorkspaceReport struct {
+	Connections int      // Saved connections exported, or added by an import
+	Skipped     int      // Connections left out: encrypted ones on export, existing names on import
+	Files       []string // Other files exported, or merged by an import
+	Hooks       []string // Hook commands an import adds, as "event: command"
+}
+
+// WorkspaceFilename names an export archive, e.g. mirador-workspace_20260317_091500.zip
+func WorkspaceFilename(now time.Time) string {
+	return "mirador-workspace_" + now.Format("20060102_150405") + ".zip"
+}
+
+// WorkspaceConnections prepares saved connections for an archive: passwords
+// are removed from their connection strings, and connections whose strings
+// are still encrypted (no passphrase entered) are left out altogether. It
+// returns the connections to export and how many were left out.
+func WorkspaceConnections(connections []models.SavedConnection) ([]models.SavedConnection, int) {
+	exported := make([]models.SavedConnection, 0, len(connections))
+	encrypted := 0
+	for _, conn := range connections {
+		if config.IsEncrypted(conn.ConnectionStr) || config.IsEncrypted(conn.ReplicaConnectionStr) {
+			encrypted++
+			continue
+		}
+		conn.ConnectionStr = workspaceConnectionString(conn.Driver, conn.ConnectionStr)
+		conn.ReplicaConnectionStr = workspaceConnectionString(conn.Driver, conn.ReplicaConnectionStr)
+		exported = append(exported, conn)
+	}
+	return exported, encrypted
+}
+
+// workspaceConnectionString is a connection string without its secrets
+func workspaceConnectionString(driver, connectionStr string) string {
+	if config.IsKeychainReference(connectionStr) {
+		return ""
+	}
+	return database.RedactSecrets(driver, connectionStr, "")
+}
+
+// ExportWorkspace writes the saved connections, without passwords, and the
+// query history, favorites, notes, table snapshots, and hooks to one archive
+func ExportWorkspace(path string, now time.Time) (WorkspaceReport, error) {
+	var report WorkspaceReport
+	files := map[string][]byte{}
+
+	connections, err := config.LoadSavedConnections()
+	if err != nil {
+		return report, fmt.Errorf("failed to load connections: %w", err)
+	}
+	exported, encrypted := WorkspaceConnections(connections)
+	if files[config.WorkspaceConnections], err = config.MarshalConnections(exported); err != nil {
+		return report, err
+	}
+	report.Connections, report.Skipped = len(exported), encrypted
+
+	for _, name := range config.WorkspaceFiles {
+		if name == config.WorkspaceConnections {
+			continue
+		}
+		data, err := config.ReadWorkspaceFile(name)
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if data != nil {
+			files[name] = data
+			report.Files = append(report.Files, name)
+		}
+	}
+	return report, config.WriteWorkspaceArchive(path, files, now)
+}
+
+// MergeWorkspaceConnections adds the imported connections whose names are not
+// saved yet; a saved connection is never replaced. It returns the merged list
+// and how many were added.
+func MergeWorkspaceConnections(local, imported []models.SavedConnection) ([]models.SavedConnection, int) {
+	merged := append([]models.SavedConnection(nil), local...)
+	added := 0
+	for _, conn := range imported {
+		if conn.Name == "" || slices.ContainsFunc(merged, func(existing models.SavedConnection) bool { return existing.Name == conn.Name }) {
+			continue
+		}
+		merged = append(merged, conn)
+		added++
+	}
+	return merged, added
+}
+
+// MergeQueryHistory combines two histories newest first, dropping entries
+// both have and keeping at most MaxQueryHistory
+func MergeQueryHistory(local, imported []models.QueryHistoryEntry) []models.QueryHistoryEntry {
+	merged := append([]models.QueryHistoryEntry(nil), local...)
+	for _, entry := range imported {
+		if !slices.ContainsFunc(local, func(existing models.QueryHistoryEntry) bool {
+			return existing.Query == entry.Query && existing.Timestamp.Equal(entry.Timestamp)
+		}) {
+			merged = append(merged, entry)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Timestamp.After(merged[j].Timestamp) })
+	if len(merged) > MaxQueryHistory {
+		merged = merged[:MaxQueryHistory]
+	}
+	return merged
+}
+
+// WorkspaceHookCommands lists the hooks of an archive's hooks.json that the
+// local one lacks, as "event: command", in event order
+func WorkspaceHookCommands(local, imported []byte) ([]string, error) {
+	var have, incoming map[string]json.RawMessage
+	if len(local) > 0 {
+		if err := json.Unmarshal(local, &have); err != nil {
+			return nil, fmt.Errorf("the local file is not a JSON object: %w", err)
+		}
+	}
+	if err := json.Unmarshal(imported, &incoming); err != nil {
+		return nil, fmt.Errorf("the imported file is not a JSON object: %w", err)
+	}
+	var commands []string
+	for event, value := range incoming {
+		if _, ok := have[event]; ok {
+			continue
+		}
+		var command string
+		if json.Unmarshal(value, &command) != nil {
+			command = string(value)
+		}
+		if command != "" {
+			commands = append(commands, event+": "+command)
+		}
+	}
+	sort.Strings(commands)
+	return commands, nil
+}
+
+// ImportWorkspace merges an archive written by ExportWorkspace into the local
+// state. Nothing already here is overwritten: connections are added by name,
+// history entries are merged, and the other stores gain only keys they lack.
+// Hooks run shell commands, so they are only installed with withHooks; the
+// report lists them either way.
+func ImportWorkspace(path string, withHooks bool) (WorkspaceReport, error) {
+	var report WorkspaceReport
+	files, err := config.ReadWorkspaceArchive(path)
+	if err != nil {
+		return report, err
+	}
+
+	if data, ok := files[config.WorkspaceConnections]; ok {
+		imported, err := config.UnmarshalConnections(data)
+		if err != nil {
+			return report, fmt.Errorf("invalid %s in the archive: %w", config.WorkspaceConnections, err)
+		}
+		local, err := config.LoadSavedConnections()
+		if err != nil {
+			return report, fmt.Errorf("failed to load connections: %w", err)
+		}
+		merged, added := MergeWorkspaceConnections(local, imported)
+		report.Connections, report.Skipped = added, len(imported)-added
+		if added > 0 {
+			if err := config.SaveConnections(merged); err != nil {
+				return report, fmt.Errorf("failed to save connections: %w", err)
+			}
+		}
+	}
+
+	if data, ok := files[config.WorkspaceQueryHistory]; ok {
+		var imported []models.QueryHistoryEntry
+		if err := json.Unmarshal(data, &imported); err != nil {
+			return report, fmt.Errorf("invalid %s in the archive: %w", config.WorkspaceQueryHistory, err)
+		}
+		local, err := config.LoadQueryHistory()
+		if err != nil {
+			return report, fmt.Errorf("failed to load the query history: %w", err)
+		}
+		if err := config.SaveQueryHistory(MergeQueryHistory(local, imported)); err != nil {
+			return report, fmt.Errorf("failed to save the query history: %w", err)
+		}
+		report.Files = append(report.Files, config.WorkspaceQueryHistory)
+	}
+
+	for _, name := range config.WorkspaceFiles {
+		data, ok := files[name]
+		if !ok || name == config.WorkspaceConnections || name == config.WorkspaceQueryHistory {
+			continue
+		}
+		local, err := config.ReadWorkspaceFile(name)
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if name == config.WorkspaceHooks {
+			if report.Hooks, err = WorkspaceHookCommands(local, data); err != nil {
+				return report, fmt.Errorf("%s: %w", name, err)
+			}
+			if !withHooks {
+				continue
+			}
+		}
+		merged, added, err := config.MergeJSONObjects(local, data)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", name, err)
+		}
+		if added > 0 {
+			if err := config.WriteWorkspaceFile(name, merged); err != nil {
+				return report, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+		report.Files = append(report.Files, name)
+	}
+	return report, nil
+}
+
+// RunWorkspaceCommand runs "mirador workspace export [file]" or
+// "mirador workspace import [--with-hooks] <file>", writing what it did to out
+func RunWorkspaceCommand(args []string, out io.Writer) error {
+	usage := fmt.Errorf("usage: mirador workspace export [file] | mirador workspace import [--with-hooks] <file>")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "export":
+		if len(args) > 2 {
+			return usage
+		}
+		path := WorkspaceFilename(time.Now())
+		if len(args) == 2 {
+			path = args[1]
+		}
+		report, err := ExportWorkspace(path, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Exported %d saved connections (without passwords) and %d other files to %s\n", report.Connections, len(report.Files), path)
+		if report.Skipped > 0 {
+			fmt.Fprintf(out, "%d connections encrypted with a master passphrase were left out\n", report.Skipped)
+		}
+		return nil
+	case "import":
+		withHooks := len(args) == 3 && args[1] == "--with-hooks"
+		if len(args) != 2 && !withHooks {
+			return usage
+		}
+		path := args[len(args)-1]
+		report, err := ImportWorkspace(path, withHooks)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Imported %d saved connections and merged %d other files from %s\n", report.Connections, len(report.Files), path)
+		if len(report.Hooks) > 0 {
+			if withHooks {
+				fmt.Fprintln(out, "Installed these hook commands, which run on their events:")
+			} else {
+				fmt.Fprintln(out, "The archive has hook commands, which were not installed:")
+			}
+			for _, command := range report.Hooks {
+				fmt.Fprintln(out, "  "+command)
+			}
+			if !withHooks {
+				fmt.Fprintln(out, "Review them, then import again with --with-hooks to install them")
+			}
+		}
+		if report.Skipped > 0 {
+			fmt.Fprintf(out, "%d connections were skipped because a connection of the same name is already saved\n", report.Skipped)
+		}
+		if report.Connections > 0 {
+			fmt.Fprintln(out, "Passwords are not part of the archive: add them to the connections, ~/.pgpass, or ~/.my.cnf")
+		}
+		return nil
+	}
+	return usage
+}
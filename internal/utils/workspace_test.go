@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+)
+
+func TestWorkspaceConnections(t *testing.T) {
+	connections := []models.SavedConnection{
+		{Name: "shop", Driver: "postgres", ConnectionStr: "postgres://app:s3cret@db/shop"},
+		{Name: "vault", Driver: "postgres", ConnectionStr: "enc:v1:c2VjcmV0"},
+		{Name: "replica", Driver: "postgres", ConnectionStr: "postgres://app@db/shop", ReplicaConnectionStr: "enc:v1:c2VjcmV0"},
+	}
+	exported, skipped := WorkspaceConnections(connections)
+	if skipped != 2 || len(exported) != 1 || exported[0].Name != "shop" || exported[0].ConnectionStr != "postgres://app@db/shop" {
+		t.Errorf("WorkspaceConnections() = %+v, %d skipped, want only shop without its password and 2 skipped", exported, skipped)
+	}
+}
+
+func TestMergeQueryHistory(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	local := []models.QueryHistoryEntry{{Query: "SELECT 3", Timestamp: t0.Add(3 * time.Hour)}, {Query: "SELECT 1", Timestamp: t0}}
+	imported := []models.QueryHistoryEntry{{Query: "SELECT 2", Timestamp: t0.Add(time.Hour)}, {Query: "SELECT 1", Timestamp: t0}}
+	merged := MergeQueryHistory(local, imported)
+	var got []string
+	for _, entry := range merged {
+		got = append(got, entry.Query)
+	}
+	if len(got) != 3 || got[0] != "SELECT 3" || got[1] != "SELECT 2" || got[2] != "SELECT 1" {
+		t.Errorf("MergeQueryHistory = %v, want [SELECT 3 SELECT 2 SELECT 1]", got)
+	}
+}
+
+func TestWorkspaceRoundTrip(t *testing.T) {
+	t.Setenv("MIRADOR_KEYCHAIN", "0")
+	source := t.TempDir()
+	t.Setenv("HOME", source)
+	t.Setenv("USERPROFILE", source)
+	if err := config.SaveConnections([]models.SavedConnection{
+		{Name: "shop", Driver: "postgres", ConnectionStr: "postgres://app:s3cret@db/shop", Group: "prod"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.WriteWorkspaceFile(config.WorkspaceFavorites, []byte(`{"k1": ["orders"], "k2": ["items"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.WriteWorkspaceFile(config.WorkspaceHooks, []byte(`{"on_connect": "notify-send connected"}`)); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "workspace.zip")
+	report, err := ExportWorkspace(archive, time.Now())
+	if err != nil || report.Connections != 1 || len(report.Files) != 2 {
+		t.Fatalf("ExportWorkspace = %+v, %v", report, err)
+	}
+
+	target := t.TempDir()
+	t.Setenv("HOME", target)
+	t.Setenv("USERPROFILE", target)
+	if err := config.SaveConnections([]models.SavedConnection{{Name: "local", Driver: "sqlite3", ConnectionStr: "app.db"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.WriteWorkspaceFile(config.WorkspaceFavorites, []byte(`{"k1": ["users"], "k0": []}`)); err != nil {
+		t.Fatal(err)
+	}
+	report, err = ImportWorkspace(archive, false)
+	if err != nil || report.Connections != 1 {
+		t.Fatalf("ImportWorkspace = %+v, %v", report, err)
+	}
+
+	connections, err := config.LoadSavedConnections()
+	if err != nil || len(connections) != 2 {
+		t.Fatalf("connections after import = %+v, %v", connections, err)
+	}
+	if shop := connections[1]; shop.Name != "shop" || shop.ConnectionStr != "postgres://app@db/shop" || shop.Group != "prod" {
+		t.Errorf("imported connection = %+v, want it without its password", shop)
+	}
+	data, _ := os.ReadFile(filepath.Join(target, ".mirador", config.WorkspaceFavorites))
+	var favorites map[string][]string
+	if err := json.Unmarshal(data, &favorites); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(favorites, map[string][]string{"k0": {}, "k1": {"users"}, "k2": {"items"}}) {
+		t.Errorf("favorites after import = %v, want the local keys kept and k2 added", favorites)
+	}
+
+	// Hooks run commands, so they are listed but only installed when asked for
+	if want := []string{"on_connect: notify-send connected"}; !reflect.DeepEqual(report.Hooks, want) {
+		t.Errorf("ImportWorkspace hooks = %q, want %q", report.Hooks, want)
+	}
+	if _, err := os.Stat(filepath.Join(target, ".mirador", config.WorkspaceHooks)); !os.IsNotExist(err) {
+		t.Errorf("hooks.json was written without --with-hooks (stat error %v)", err)
+	}
+
+	// Importing again adds nothing but the hooks, now that they are asked for
+	report, err = ImportWorkspace(archive, true)
+	if err != nil || report.Connections != 0 || report.Skipped != 1 {
+		t.Errorf("second ImportWorkspace = %+v, %v", report, err)
+	}
+	if hooks, err := config.LoadHooks(); err != nil || hooks.OnConnect != "notify-send connected" {
+		t.Errorf("hooks after import with hooks = %+v, %v", hooks, err)
+	}
+}
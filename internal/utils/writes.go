@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"strings"
+)
+
+// Statements are classified deny-by-default: only statements known to read are
+// reads, and anything else (CALL, DO, COPY, LOAD DATA, VACUUM, SET GLOBAL, ...)
+// counts as a write. Read-only connections block writes, and safe mode and
+// production connections ask before running them.
+
+// readKeywords are the leading keywords of statements that can only read, each
+// checked further by readsOnly
+var readKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "VALUES": true, "TABLE": true,
+	"SHOW": true, "DESCRIBE": true, "DESC": true, "EXPLAIN": true, "USE": true,
+	"PRAGMA": true, "SET": true, "DETACH": true,
+	"BEGIN": true, "START": true, "COMMIT": true, "END": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true,
+}
+
+// dataModifyingKeywords make a WITH query or an analyzed EXPLAIN a write
+var dataModifyingKeywords = []string{"INSERT", "UPDATE", "DELETE", "MERGE", "INTO"}
+
+// explainOptions are the words between EXPLAIN and the statement it explains
+var explainOptions = map[string]bool{
+	"ANALYZE": true, "ANALYSE": true, "VERBOSE": true, "COSTS": true, "BUFFERS": true, "TIMING": true,
+	"SUMMARY": true, "SETTINGS": true, "WAL": true, "MEMORY": true, "SERIALIZE": true, "GENERIC_PLAN": true,
+	"FORMAT": true, "TEXT": true, "JSON": true, "XML": true, "YAML": true, "TREE": true, "TRADITIONAL": true,
+	"EXTENDED": true, "PARTITIONS": true, "QUERY": true, "PLAN": true, "TRUE": true, "FALSE": true,
+	"ON": true, "OFF": true, "NONE": true, "BINARY": true,
+}
+
+// sessionSettings are the settings a SET may change on a read-only connection;
+// they only affect how the session reads
+var sessionSettings = map[string]bool{
+	"SEARCH_PATH": true, "SCHEMA": true, "TIME": true, "TIMEZONE": true, "TIME_ZONE": true,
+	"DATESTYLE": true, "INTERVALSTYLE": true, "EXTRA_FLOAT_DIGITS": true, "NAMES": true,
+	"CHARACTER": true, "CHARSET": true, "CLIENT_ENCODING": true, "APPLICATION_NAME": true,
+	"STATEMENT_TIMEOUT": true, "LOCK_TIMEOUT": true, "WORK_MEM": true,
+}
+
+// readPragmas are the SQLite pragmas that only report, asked without a value;
+// readTablePragmas only report on the table or index named in parentheses
+var (
+	readPragmas = map[string]bool{
+		"table_list": true, "database_list": true, "collation_list": true, "function_list": true,
+		"module_list": true, "pragma_list": true, "compile_options": true, "page_count": true,
+		"page_size": true, "freelist_count": true, "encoding": true, "user_version": true,
+		"schema_version": true, "application_id": true, "data_version": true, "journal_mode": true,
+		"foreign_keys": true, "foreign_key_check": true, "integrity_check": true, "quick_check": true,
+		"cipher_version": true,
+	}
+	readTablePragmas = map[string]bool{
+		"table_info": true, "table_xinfo": true, "index_list": true, "index_info": true,
+		"index_xinfo": true, "foreign_key_list": true, "foreign_key_check": true,
+		"integrity_check": true, "quick_check": true,
+	}
+)
+
+// IsWriteStatement reports whether a statement may modify data, the schema, or
+// the server: any statement that is not known to only read. Common table
+// expressions count as writes when they wrap a data-modifying statement.
+func IsWriteStatement(query string) bool {
+	words := queryWords(query)
+	return len(words) > 0 && !readsOnly(query, words)
+}
+
+// readsOnly reports whether a statement, given as text and as its upper-cased
+// words, is known to only read
+func readsOnly(query string, words []string) bool {
+	if !readKeywords[words[0]] {
+		return false
+	}
+	switch words[0] {
+	case "SELECT", "WITH", "VALUES", "TABLE":
+		// SELECT ... INTO creates a table or writes a file, FOR UPDATE locks rows,
+		// and set_config changes settings, such as default_transaction_read_only
+		return !containsWord(words, dataModifyingKeywords...) && !containsWord(words, "SET_CONFIG") && !locksRows(words)
+	case "EXPLAIN":
+		// EXPLAIN ANALYZE runs the statement it explains
+		if !containsWord(words, "ANALYZE", "ANALYSE") {
+			return true
+		}
+		rest := words[1:]
+		for len(rest) > 0 && explainOptions[rest[0]] {
+			rest = rest[1:]
+		}
+		return len(rest) > 0 && rest[0] != "EXPLAIN" && readsOnly(strings.Join(rest, " "), rest)
+	case "SET":
+		return readsOnlySet(query)
+	case "PRAGMA":
+		return readsOnlyPragma(query)
+	case "START":
+		return len(words) > 1 && words[1] == "TRANSACTION" && !containsWord(words, "WRITE")
+	case "BEGIN":
+		// BEGIN READ WRITE would lift a read-only session
+		return !containsWord(words, "WRITE")
+	}
+	return true
+}
+
+// readsOnlySet reports whether a SET only changes how the session reads: every
+// assignment of the list sets a user variable or one of the session settings,
+// not a global, system, or transaction one
+func readsOnlySet(query string) bool {
+	text := strings.TrimSpace(stripComments(query))
+	text = strings.TrimSuffix(strings.TrimSpace(text[len("SET"):]), ";")
+	targets := 0
+	for _, item := range splitTopLevel(text, ',') {
+		item = strings.TrimSpace(item)
+		if targets > 0 && isListValue(item) {
+			// A further value of a list setting, as in SET search_path TO a, b
+			continue
+		}
+		if !readsOnlySetTarget(item) {
+			return false
+		}
+		targets++
+	}
+	return targets > 0
+}
+
+// readsOnlySetTarget reports whether one assignment of a SET list sets a user
+// variable or a session setting
+func readsOnlySetTarget(item string) bool {
+	upper := strings.ToUpper(item)
+	switch {
+	case strings.HasPrefix(upper, "@@"):
+		// @@name and @@session.name change the session, @@global.name the server
+		upper = strings.TrimPrefix(upper, "@@")
+		for _, scope := range []string{"SESSION.", "LOCAL."} {
+			upper = strings.TrimPrefix(upper, scope)
+		}
+	case strings.HasPrefix(upper, "@"):
+		return true
+	default:
+		words := sqlWord.FindAllString(upper, 2)
+		if len(words) > 0 && (words[0] == "SESSION" || words[0] == "LOCAL") {
+			upper = strings.TrimSpace(upper[len(words[0]):])
+		}
+	}
+	name := sqlWord.FindString(upper)
+	return name != "" && strings.HasPrefix(upper, name) && sessionSettings[name]
+}
+
+// isListValue reports whether a SET list item is a lone value, a name, number,
+// or quoted string, rather than an assignment
+func isListValue(item string) bool {
+	if item != "" && sqlStringLiteral.FindString(item) == item {
+		return true
+	}
+	return item != "" && !strings.ContainsAny(item, " \t\n=@:(")
+}
+
+// splitTopLevel splits text on sep outside parentheses and quoted strings
+func splitTopLevel(text string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// readsOnlyPragma reports whether a SQLite PRAGMA only reports: one of the
+// reporting pragmas without a value, or a table pragma given a table name
+func readsOnlyPragma(query string) bool {
+	text := strings.TrimSpace(stripComments(query))
+	text = strings.TrimSuffix(strings.TrimSpace(text[len("PRAGMA"):]), ";")
+	if strings.Contains(text, "=") {
+		return false
+	}
+	name, arg, hasArg := strings.Cut(text, "(")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if !hasArg {
+		return readPragmas[name]
+	}
+	return readTablePragmas[name] && strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(arg), ")")) != ""
+}
+
+// locksRows reports whether a query takes row locks, with FOR UPDATE, FOR
+// SHARE, or LOCK IN SHARE MODE
+func locksRows(words []string) bool {
+	for i := 0; i+1 < len(words); i++ {
+		switch {
+		case words[i] == "FOR" && (words[i+1] == "UPDATE" || words[i+1] == "SHARE" || words[i+1] == "NO" || words[i+1] == "KEY"):
+			return true
+		case words[i] == "LOCK" && words[i+1] == "IN":
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether any of the keywords is one of the words
+func containsWord(words []string, keywords ...string) bool {
+	for _, w := range words {
+		for _, k := range keywords {
+			if w == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ScriptHasWrite reports whether any statement of a script may modify data,
+// the schema, or the server
+func ScriptHasWrite(script string) bool {
+	for _, stmt := range SplitStatements(script) {
+		if IsWriteStatement(stmt) {
+			return true
+		}
+	}
+	return false
+}
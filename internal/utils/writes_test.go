@@ -0,0 +1,62 @@
+package utils
+
+import "testing"
+
+func TestIsWriteStatement(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"select", "SELECT * FROM users", false},
+		{"explain", "EXPLAIN SELECT 1", false},
+		{"show", "SHOW TABLES", false},
+		{"insert", "insert into users (name) values ('a')", true},
+		{"update", "UPDATE users SET name = 'b'", true},
+		{"delete with comment", "-- cleanup\nDELETE FROM users", true},
+		{"block comment", "/* note */ DROP TABLE users", true},
+		{"ddl", "ALTER TABLE users ADD COLUMN age int", true},
+		{"cte select", "WITH t AS (SELECT 1) SELECT * FROM t", false},
+		{"cte delete", "WITH old AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM old)", true},
+		{"empty", "   ", false},
+		{"call", "CALL purge_orders()", true},
+		{"do block", "DO $$ BEGIN DELETE FROM t; END $$", true},
+		{"copy", "COPY users FROM '/tmp/users.csv'", true},
+		{"load data", "LOAD DATA INFILE 'users.csv' INTO TABLE users", true},
+		{"vacuum", "VACUUM", true},
+		{"select into", "SELECT * INTO backup FROM users", true},
+		{"select for update", "SELECT * FROM users FOR UPDATE", true},
+		{"into in a literal", "SELECT 'insert into' FROM users", false},
+		{"comment marker in a literal", "SELECT '--' INTO t FROM x", true},
+		{"quote in a comment", "SELECT 1; -- ' \n DELETE FROM t", true},
+		{"set with a comment marker in a literal", "SET application_name = 'a--b', @id = 1", false},
+		{"into in a quoted identifier", "SELECT `into`, \"delete\" FROM users", false},
+		{"explain analyze select", "EXPLAIN ANALYZE SELECT 1", false},
+		{"explain analyze delete", "EXPLAIN (ANALYZE, FORMAT JSON) DELETE FROM users", true},
+		{"set search path", "SET search_path TO app", false},
+		{"set user variable", "SET @id = 1", false},
+		{"set global", "SET GLOBAL max_connections = 10", true},
+		{"set search path list", "SET search_path TO app, public", false},
+		{"set session variables", "SET NAMES utf8mb4, @@session.time_zone = '+00:00', @id = 1", false},
+		{"user variable then system variable", "SET @x=1, @@session.transaction_read_only=0", true},
+		{"names then transaction", "SET NAMES utf8, SESSION TRANSACTION READ WRITE", true},
+		{"global system variable", "SET @@global.read_only = 0", true},
+		{"set_config", "SELECT set_config('default_transaction_read_only', 'off', false)", true},
+		{"qualified set_config", "SELECT pg_catalog.set_config('default_transaction_read_only', 'off', false)", true},
+		{"set_config in a literal", "SELECT 'set_config(' AS note", false},
+		{"set transaction read write", "SET TRANSACTION READ WRITE", true},
+		{"begin", "BEGIN", false},
+		{"start read write", "START TRANSACTION READ WRITE", true},
+		{"pragma table info", "PRAGMA table_info(users)", false},
+		{"pragma assignment", "PRAGMA journal_mode = WAL", true},
+		{"pragma unknown", "PRAGMA optimize", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWriteStatement(tt.query); got != tt.want {
+				t.Errorf("IsWriteStatement(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
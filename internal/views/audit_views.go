@@ -0,0 +1,54 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// AuditLogView renders the log of executed write statements
+func AuditLogView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🧾 Audit Log (%d statements)", len(m.AuditEntries)))
+
+	if m.IsLoadingAudit {
+		builder.WithStatus("⏳ Loading audit log...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	}
+
+	if len(m.AuditEntries) == 0 {
+		builder.WithContent(RenderEmptyState("🧾", "No write statements recorded yet."))
+	} else {
+		builder.WithContent(m.AuditTable.View())
+
+		// Show the full details of the selected entry below the table
+		idx := len(m.AuditEntries) - 1 - m.AuditTable.Cursor()
+		if idx >= 0 && idx < len(m.AuditEntries) {
+			e := m.AuditEntries[idx]
+			details := []string{
+				fmt.Sprintf("Connection: %s (%s)", e.Connection, e.Driver),
+				"Statement: " + e.Statement,
+			}
+			if len(e.Args) > 0 {
+				details = append(details, "Arguments: "+strings.Join(e.Args, ", "))
+			}
+			if e.Error != "" {
+				details = append(details, "Error: "+e.Error)
+			}
+			builder.WithContent(RenderInfoBox(strings.Join(details, "\n")))
+		}
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("c") + ": copy statement • " +
+			styles.KeyStyle.Render("ctrl+r") + ": reload • " +
+			styles.KeyStyle.Render("esc") + ": back to tables",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
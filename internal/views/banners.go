@@ -0,0 +1,90 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// RenderStatusBanners places session-wide banners (environment, safe mode, read-only, lost connection, migration recording) and the breadcrumbs above a rendered view
+func RenderStatusBanners(m models.Model, view string) string {
+	var banners []string
+
+	if m.DB != nil {
+		switch m.Environment {
+		case models.EnvironmentProduction:
+			banners = append(banners, styles.ProductionBannerStyle.Render("🚨 PRODUCTION • writes in the query runner and field edits ask for confirmation"))
+		case models.EnvironmentStaging, models.EnvironmentDevelopment:
+			banners = append(banners, styles.EnvironmentBannerStyle.Render(strings.ToUpper(utils.EnvironmentBadge(m.Environment))))
+		}
+	}
+
+	if m.ConnectionLost {
+		text := "🔌 Connection lost • ctrl+r: reconnect and retry"
+		if m.IsReconnecting {
+			text = fmt.Sprintf("⏳ Reconnecting (attempt %d of %d)...", max(m.ReconnectAttempt, 1), max(config.ConnectRetries(), 1))
+		} else if !m.ReconnectAt.IsZero() {
+			text = fmt.Sprintf("🔌 Connection lost • reconnecting in %ds (attempt %d of %d) • ctrl+r: reconnect now",
+				utils.ConnectRetryCountdown(m.ReconnectAt, time.Now()), m.ReconnectAttempt, config.ConnectRetries())
+		} else if m.ConnectionError != nil {
+			text = "🔌 Connection lost (" + m.ConnectionError.Error() + ") • ctrl+r: reconnect and retry"
+		}
+		banners = append(banners, styles.ConnectionLostBannerStyle.Render(text))
+	}
+
+	if m.ReadOnly && m.DB != nil {
+		text := "🔒 READ-ONLY • writes, DDL, and field edits are blocked for this connection"
+		if m.SelectedDB.Driver == "sqlite3" && m.Immutable {
+			text += " • file opened immutable"
+		} else if m.SelectedDB.Driver == "sqlite3" {
+			text += " • file opened with mode=ro"
+		}
+		banners = append(banners, styles.ReadOnlyBannerStyle.Render(text))
+	}
+
+	if m.SafeMode {
+		banners = append(banners, styles.SafeModeBannerStyle.Render("🛡️ SAFE MODE • writes require confirmation • ctrl+g to disable"))
+	}
+
+	if m.ReplicaDB != nil {
+		served := "nothing yet"
+		if m.LastEndpoint != "" {
+			served = m.LastEndpoint
+		}
+		banners = append(banners, styles.ReplicaBannerStyle.Render("📡 READ REPLICA • previews and SELECTs use the replica • last served by: "+served))
+	}
+
+	if m.MigrationFile != "" {
+		banners = append(banners, styles.RecordingBannerStyle.Render("⏺ RECORDING MIGRATION • "+m.MigrationFile+" • M in tables to stop"))
+	}
+
+	if crumbs := utils.Breadcrumbs(m); crumbs != "" {
+		banners = append(banners, styles.BreadcrumbStyle.Render(crumbs))
+	}
+
+	if len(banners) == 0 {
+		return view
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(banners, view)...)
+}
+
+// RenderSessionHealth colors the connection's health indicator: green when
+// connected, orange when degraded, red when disconnected
+func RenderSessionHealth(m models.Model) string {
+	text, health := utils.SessionHealthIndicator(m)
+	switch {
+	case text == "":
+		return ""
+	case health == models.SessionHealthConnected:
+		return styles.SuccessStyle.Render(text)
+	case health == models.SessionHealthDegraded:
+		return styles.WarningStyle.Render(text)
+	}
+	return styles.ErrorStyle.Render(text)
+}
@@ -8,6 +8,7 @@ import (
 // ViewBuilder provides a consistent way to build views with standard spacing and layout
 type ViewBuilder struct {
 	title      string
+	indicator  string
 	status     string
 	statusType StatusType
 	content    []string
@@ -40,6 +41,12 @@ func (vb *ViewBuilder) WithTitle(title string) *ViewBuilder {
 	return vb
 }
 
+// WithIndicator sets a rendered indicator shown next to the title, before any status
+func (vb *ViewBuilder) WithIndicator(indicator string) *ViewBuilder {
+	vb.indicator = indicator
+	return vb
+}
+
 // WithStatus sets a status message with the specified type
 func (vb *ViewBuilder) WithStatus(message string, statusType StatusType) *ViewBuilder {
 	vb.status = message
@@ -65,7 +72,7 @@ func (vb *ViewBuilder) Render() string {
 
 	// Add title with inline status if present
 	if vb.title != "" {
-		if vb.status != "" {
+		if vb.status != "" || vb.indicator != "" {
 			titleLine := vb.renderTitleWithStatus()
 			elements = append(elements, titleLine)
 		} else {
@@ -93,11 +100,16 @@ func (vb *ViewBuilder) Render() string {
 	return styles.DocStyle.Render(content)
 }
 
-// renderTitleWithStatus creates a title with inline status message
+// renderTitleWithStatus creates a title with its indicator and inline status message
 func (vb *ViewBuilder) renderTitleWithStatus() string {
-	titlePart := styles.TitleStyle.Render(vb.title)
-	statusPart := vb.renderStatus()
-	return lipgloss.JoinHorizontal(lipgloss.Left, titlePart, "  ", statusPart)
+	parts := []string{styles.TitleStyle.Render(vb.title)}
+	if vb.indicator != "" {
+		parts = append(parts, vb.indicator)
+	}
+	if vb.status != "" {
+		parts = append(parts, "  ", vb.renderStatus())
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, parts...)
 }
 
 // renderStatus renders the status message with appropriate styling
@@ -157,3 +169,13 @@ func RenderContextualHelp(baseHelp, fullHelp string, showFull bool) string {
 	}
 	return styles.HelpStyle.Render(baseHelp)
 }
+
+// RenderKeyHelp renders one "key: action" help entry. Actions the connected
+// driver cannot perform are dimmed and struck through rather than hidden, so
+// the key still reads as known but unavailable.
+func RenderKeyHelp(key, action string, enabled bool) string {
+	if !enabled {
+		return styles.DisabledKeyStyle.Render(key + ": " + action)
+	}
+	return styles.KeyStyle.Render(key) + ": " + action
+}
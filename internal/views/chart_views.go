@@ -0,0 +1,89 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// ChartView renders the time-series chart of a numeric column
+func ChartView(m models.Model) string {
+	timeColumn := ""
+	if m.ChartTimeIndex < len(m.ChartTimeColumns) {
+		timeColumn = m.ChartTimeColumns[m.ChartTimeIndex]
+	}
+	title := fmt.Sprintf("📊 %s.%s over %s", m.SelectedTable, m.ChartValueColumn, timeColumn)
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.IsLoadingChart {
+		builder.WithStatus("⏳ Loading chart...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	builder.WithContent(styles.SubtitleStyle.Render(fmt.Sprintf("%s per %s • latest %d %ss",
+		m.ChartAggregate, m.ChartBucket, utils.ChartBucketLimit, m.ChartBucket)))
+
+	lo, hi, ok := utils.ChartRange(m.ChartPoints)
+	if !ok {
+		if !m.IsLoadingChart && m.Err == nil {
+			builder.WithContent(RenderEmptyState("📭", "No rows with a "+timeColumn+" and a value to chart."))
+		}
+	} else {
+		builder.WithContent(renderChart(m, lo, hi))
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("t") + ": time column • " +
+			styles.KeyStyle.Render("b") + ": bucket • " +
+			styles.KeyStyle.Render("a") + ": aggregate • " +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("esc") + ": back to columns",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
+
+// renderChart draws the bars with the value range on the left, the first and
+// last bucket below, and a summary of the range and latest value
+func renderChart(m models.Model, lo, hi float64) string {
+	points := m.ChartPoints
+	top, bottom := utils.FormatChartValue(hi), utils.FormatChartValue(lo)
+	axisWidth := max(len(top), len(bottom))
+
+	height := utils.Max(5, utils.Min(16, m.Height-18))
+	barWidth := utils.Max(1, utils.Min(4, (m.Width-axisWidth-10)/len(points)))
+	bars := utils.BarChartRows(points, lo, hi, height, barWidth)
+
+	lines := make([]string, 0, len(bars)+2)
+	for i, bar := range bars {
+		label := ""
+		switch i {
+		case 0:
+			label = top
+		case len(bars) - 1:
+			label = bottom
+		}
+		lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf("%*s ┤", axisWidth, label))+styles.KeyStyle.Render(bar))
+	}
+
+	chartWidth := len(points) * barWidth
+	first, last := points[0].Bucket, points[len(points)-1].Bucket
+	axis := first
+	if gap := chartWidth - len(first) - len(last); len(points) > 1 && gap > 0 {
+		axis += strings.Repeat(" ", gap) + last
+	}
+	lines = append(lines, styles.HelpStyle.Render(strings.Repeat(" ", axisWidth+2)+axis))
+
+	latest := points[len(points)-1]
+	latestValue := "no value"
+	if !latest.Null {
+		latestValue = utils.FormatChartValue(latest.Value)
+	}
+	lines = append(lines, "", styles.SubtitleStyle.Render(fmt.Sprintf("%d buckets • latest %s: %s",
+		len(points), latest.Bucket, latestValue)))
+	return strings.Join(lines, "\n")
+}
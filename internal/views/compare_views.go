@@ -0,0 +1,92 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// CompareTablesView renders the table comparison flow and its checksum report
+func CompareTablesView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🔍 Compare Table: %s", m.CompareSourceTable))
+	c := m.Comparison
+	differences := c.MissingTargetN + c.MissingSourceN + c.DifferingN
+
+	if m.IsComparingTables {
+		builder.WithStatus("⏳ Checksumming both tables by primary key...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.CompareStep == models.CompareReport && differences > 0 {
+		builder.WithStatus(fmt.Sprintf("⚠️ Tables differ: %d rows do not match", differences), StatusWarning)
+	} else if m.CompareStep == models.CompareReport {
+		builder.WithStatus(fmt.Sprintf("✅ Tables match (%d rows)", c.SourceRows), StatusSuccess)
+	}
+
+	var helpText string
+	switch m.CompareStep {
+	case models.CompareChooseTarget:
+		lines := []string{"current connection"}
+		for _, conn := range m.SavedConnections {
+			lines = append(lines, fmt.Sprintf("%s (%s)", conn.Name, conn.Driver))
+		}
+		for i := range lines {
+			if i == m.CompareTargetIndex {
+				lines[i] = styles.FocusedStyle.Render("▶ " + lines[i])
+			} else {
+				lines[i] = "  " + lines[i]
+			}
+		}
+		builder.WithContent(RenderSectionTitle("Compare with a table on:"), styles.CardStyle.Render(strings.Join(lines, "\n")))
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑/↓") + ": choose connection • " +
+				styles.KeyStyle.Render("enter") + ": next • " +
+				styles.KeyStyle.Render("esc") + ": back to tables",
+		)
+
+	case models.CompareEnterTable:
+		builder.WithContent(RenderInputField("Table to compare with (schema.table allowed):", m.CompareTableInput.View(), m.CompareTableInput.Focused()))
+		builder.WithContent(RenderInfoBox(fmt.Sprintf("Rows are matched by the source table's primary key. An integer key is checksummed in ranges of %d keys; only ranges whose checksums differ are listed row by row.", utils.CompareRangeSize)))
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": compare • " +
+				styles.KeyStyle.Render("esc") + ": change connection",
+		)
+
+	default:
+		if !m.IsComparingTables && m.Err == nil {
+			summary := []string{
+				fmt.Sprintf("Target:          %s on %s", c.TargetTable, c.TargetName),
+				fmt.Sprintf("Primary key:     %s", strings.Join(c.KeyColumns, ", ")),
+				fmt.Sprintf("Rows:            %d source, %d target", c.SourceRows, c.TargetRows),
+				fmt.Sprintf("Key ranges:      %d checksummed, %d differing", c.Ranges, c.DifferingRanges),
+			}
+			builder.WithContent(styles.CardStyle.Render(strings.Join(summary, "\n")))
+			if c.MissingTargetN > 0 {
+				builder.WithContent(renderKeyList("Missing in target", c.MissingInTarget, c.MissingTargetN))
+			}
+			if c.MissingSourceN > 0 {
+				builder.WithContent(renderKeyList("Missing in source", c.MissingInSource, c.MissingSourceN))
+			}
+			if c.DifferingN > 0 {
+				builder.WithContent(renderKeyList("Different values", c.Differing, c.DifferingN))
+			}
+		}
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("ctrl+r") + ": compare again • " +
+				styles.KeyStyle.Render("esc") + ": back to tables",
+		)
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
+
+// renderKeyList renders a capped list of primary keys under a counted heading
+func renderKeyList(title string, keys []string, total int) string {
+	heading := fmt.Sprintf("%s (%d):", title, total)
+	if total > len(keys) {
+		heading = fmt.Sprintf("%s (%d, first %d shown):", title, total, len(keys))
+	}
+	return RenderSectionTitle(heading) + "\n" + styles.WarningStyle.Render(strings.Join(keys, ", "))
+}
@@ -0,0 +1,86 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// CopyDataView renders the copy table data flow: target, table name, then progress
+func CopyDataView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("📤 Copy Table Data: %s", m.CopySourceTable))
+	progress := m.CopyProgress
+
+	if m.IsConfirmingSafeOverride {
+		builder.WithStatus(copyConfirmPrompt(m), StatusWarning)
+	} else if m.IsCopyingData {
+		builder.WithStatus("⏳ Copying rows...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.CopyStep == models.CopyRunning && progress.Done && progress.Failed > 0 {
+		builder.WithStatus(fmt.Sprintf("⚠️ Copy finished with %d failed rows", progress.Failed), StatusWarning)
+	} else if m.CopyStep == models.CopyRunning && progress.Done {
+		builder.WithStatus(fmt.Sprintf("✅ Copied %d rows", progress.Copied), StatusSuccess)
+	}
+
+	var helpText string
+	switch m.CopyStep {
+	case models.CopyChooseTarget:
+		var lines []string
+		for i, conn := range m.SavedConnections {
+			line := fmt.Sprintf("  %s (%s)", conn.Name, conn.Driver)
+			if conn.ReadOnly {
+				line += " 🔒 read-only"
+			}
+			if conn.Environment == models.EnvironmentProduction {
+				line += " 🚨 production"
+			}
+			if i == m.CopyTargetIndex {
+				line = styles.FocusedStyle.Render("▶ " + line[2:])
+			}
+			lines = append(lines, line)
+		}
+		builder.WithContent(RenderSectionTitle("Copy into saved connection:"), styles.CardStyle.Render(strings.Join(lines, "\n")))
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑/↓") + ": choose connection • " +
+				styles.KeyStyle.Render("enter") + ": next • " +
+				styles.KeyStyle.Render("esc") + ": back to tables",
+		)
+
+	case models.CopyEnterTable:
+		target := m.SavedConnections[m.CopyTargetIndex]
+		builder.WithContent(RenderSectionTitle(fmt.Sprintf("Target: %s (%s)", target.Name, target.Driver)))
+		builder.WithContent(RenderInputField("Target table (schema.table allowed):", m.CopyTableInput.View(), m.CopyTableInput.Focused()))
+		builder.WithContent(RenderInfoBox(fmt.Sprintf("The target table must already exist with the same columns. Rows are inserted in batches of %d; a failing batch is skipped and reported.", utils.CopyBatchSize)))
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": start copy • " +
+				styles.KeyStyle.Render("esc") + ": change connection",
+		)
+
+	default:
+		content := utils.CopyProgressBar(progress.Copied+progress.Failed, progress.Total, 40) + "\n" + utils.FormatCopyProgress(progress)
+		builder.WithContent(styles.CardStyle.Render(content))
+		if len(progress.Errors) > 0 {
+			builder.WithContent(RenderSectionTitle("Failed batches:"), styles.ErrorStyle.Render(strings.Join(progress.Errors, "\n")))
+		}
+		if m.IsCopyingData {
+			helpText = styles.HelpStyle.Render("Copy in progress...")
+		} else {
+			helpText = styles.HelpStyle.Render(styles.KeyStyle.Render("esc") + ": back to tables")
+		}
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
+
+// copyConfirmPrompt asks to copy rows anyway, naming a production target first
+func copyConfirmPrompt(m models.Model) string {
+	if utils.CopyTargetEnvironment(m) == models.EnvironmentProduction {
+		target := m.SavedConnections[m.CopyTargetIndex].Name
+		return fmt.Sprintf("🚨 Production: copy rows into '%s' anyway? (y/n)", target)
+	}
+	return "🛡️ Safe mode: copy rows into the target table anyway? (y/n)"
+}
@@ -0,0 +1,51 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// GroupSummaryView renders the row count and share of each value of a column
+func GroupSummaryView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🧮 %s grouped by %s", m.SelectedTable, m.GroupColumn))
+
+	if m.IsLoadingGroups {
+		builder.WithStatus("⏳ Counting values...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	if !m.IsLoadingGroups && m.Err == nil {
+		summary := fmt.Sprintf("%d values over %s rows", len(m.GroupSummary), utils.GroupDigits(strconv.FormatInt(m.GroupTotal, 10)))
+		if len(m.GroupSummary) == utils.GroupSummaryLimit {
+			summary = fmt.Sprintf("The %d most common values over %s rows", utils.GroupSummaryLimit, utils.GroupDigits(strconv.FormatInt(m.GroupTotal, 10)))
+		}
+		builder.WithContent(styles.SubtitleStyle.Render(summary))
+	}
+
+	if len(m.GroupSummary) == 0 {
+		if !m.IsLoadingGroups && m.Err == nil {
+			builder.WithContent(RenderEmptyState("📭", "The table has no rows."))
+		}
+	} else {
+		builder.WithContent(m.GroupSummaryTable.View())
+	}
+
+	aggregateHelp := ""
+	if m.GroupAggregateColumn != "" {
+		aggregateHelp = styles.KeyStyle.Render("f") + ": " + utils.NextChartOption(utils.GroupAggregates, m.GroupAggregate) + " • "
+	}
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("a") + ": aggregate column • " +
+			aggregateHelp +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("esc") + ": back to columns",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,42 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// TableGrowthView renders table size changes since the last stored snapshot
+func TableGrowthView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle("📈 Table Growth")
+
+	if m.IsLoadingGrowth {
+		builder.WithStatus("⏳ Refreshing table sizes...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	if m.HasGrowthBaseline {
+		ago := time.Since(m.GrowthSince).Round(time.Minute)
+		builder.WithContent(styles.SubtitleStyle.Render(fmt.Sprintf("Changes since snapshot of %s (%s ago)",
+			m.GrowthSince.Format("2006-01-02 15:04"), ago)))
+	} else {
+		builder.WithContent(RenderInfoBox("First snapshot recorded for this connection. Changes will show on later visits."))
+	}
+
+	if len(m.TableGrowth) == 0 {
+		builder.WithContent(RenderEmptyState("📭", "No tables found."))
+	} else {
+		builder.WithContent(m.TableGrowthTable.View())
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("esc") + ": back to tables",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,38 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// ConnectionHealthView renders the reachability board of all saved connections
+func ConnectionHealthView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle("🩺 Connection Health")
+
+	if m.IsCheckingHealth {
+		builder.WithStatus(fmt.Sprintf("⏳ Pinging %d connections...", len(m.SavedConnections)), StatusLoading)
+	} else if failed := utils.CountUnreachable(m.ConnectionHealth); failed > 0 {
+		builder.WithStatus(fmt.Sprintf("❌ %d unreachable", failed), StatusError)
+	} else if len(m.ConnectionHealth) > 0 {
+		builder.WithStatus("✅ All connections reachable", StatusSuccess)
+	}
+
+	if len(m.ConnectionHealth) == 0 {
+		builder.WithContent(RenderEmptyState("📭", "No saved connections to check."))
+	} else {
+		builder.WithContent(styles.SubtitleStyle.Render(fmt.Sprintf("%s • checked at %s",
+			utils.HealthSummary(m.ConnectionHealth), m.HealthCheckedAt.Format("15:04:05"))))
+		builder.WithContent(m.ConnectionHealthTable.View())
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("ctrl+r") + ": check again • " +
+			styles.KeyStyle.Render("esc") + ": back to saved connections",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
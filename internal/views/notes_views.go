@@ -0,0 +1,31 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// NoteEditView renders the editor for a table or saved connection note
+func NoteEditView(m models.Model) string {
+	title := fmt.Sprintf("📝 Note on connection: %s", m.NoteConnectionName)
+	if m.NoteTable != "" {
+		title = fmt.Sprintf("📝 Note on table: %s", m.NoteTable)
+	}
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	builder.WithContent(RenderInputField("Note:", m.NoteInput.View(), m.NoteInput.Focused()))
+	builder.WithContent(RenderInfoBox("Notes are stored locally in ~/.mirador and never written to the database. Save an empty note to remove it."))
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("enter") + ": save • " +
+			styles.KeyStyle.Render("esc") + ": cancel",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
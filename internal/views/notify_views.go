@@ -0,0 +1,55 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// NotifyView renders the LISTEN/NOTIFY monitor: the channel prompt, then the
+// notifications as they arrive
+func NotifyView(m models.Model) string {
+	title := fmt.Sprintf("📣 LISTEN/NOTIFY (%d received)", len(m.Notifications))
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.NotifyErr != nil {
+		builder.WithStatus("⚠️ Connection lost, reconnecting: "+m.NotifyErr.Error(), StatusWarning)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	}
+
+	if m.NotifyChannelInput.Focused() {
+		label := styles.SubtitleStyle.Render("👂 Channels:")
+		builder.WithContent(label + " " + styles.InputFocusedStyle.Render(m.NotifyChannelInput.View()))
+	} else if m.IsListening {
+		builder.WithContent(styles.SubtitleStyle.Render("👂 Listening on " + strings.Join(m.NotifyChannels, ", ")))
+	}
+
+	if len(m.Notifications) == 0 {
+		if m.IsListening {
+			builder.WithContent(RenderEmptyState("📭", "No notifications yet. Send one with NOTIFY channel, 'payload'."))
+		}
+	} else {
+		builder.WithContent(m.NotifyTable.View())
+	}
+
+	var helpText string
+	if m.NotifyChannelInput.Focused() {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": listen (separate channels with commas) • " +
+				styles.KeyStyle.Render("esc") + ": back to tables")
+	} else {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+				styles.KeyStyle.Render("c") + ": copy payload • " +
+				styles.KeyStyle.Render("x") + ": clear • " +
+				styles.KeyStyle.Render("e") + ": change channels • " +
+				styles.KeyStyle.Render("esc") + ": stop and back to tables")
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,127 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// DatabaseOverviewView renders the database size overview dashboard
+func DatabaseOverviewView(m models.Model) string {
+	o := m.DatabaseOverview
+	title := "📊 Database Overview"
+	if o.Database != "" {
+		title = fmt.Sprintf("📊 Database Overview: %s", o.Database)
+	}
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.IsRunningMaintenance {
+		elapsed := time.Since(m.MaintenanceStartedAt).Round(time.Second)
+		builder.WithStatus(fmt.Sprintf("⏳ Running %s on %s... %s", m.MaintenanceAction, m.MaintenanceTable, elapsed), StatusLoading)
+	} else if m.IsConfirmingMaintenance && utils.ConfirmsWrites(m) {
+		builder.WithStatus(writeConfirmPrompt(m, fmt.Sprintf("run %s on %s?", m.MaintenanceAction, m.MaintenanceTable)), StatusWarning)
+	} else if m.IsConfirmingMaintenance {
+		builder.WithStatus(fmt.Sprintf("⚠️ Run %s on %s? (y/n)", m.MaintenanceAction, m.MaintenanceTable), StatusWarning)
+	} else if m.IsLoadingOverview {
+		builder.WithStatus("⏳ Refreshing statistics...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	} else if pressure := utils.ConnectionPressure(o.Connections, o.MaxConnections); pressure != "" {
+		status := fmt.Sprintf("⚠️ Connections at %s of max_connections", utils.FormatRatio(int64(o.Connections), int64(o.MaxConnections)))
+		if pressure == "critical" {
+			builder.WithStatus(status, StatusError)
+		} else {
+			builder.WithStatus(status, StatusWarning)
+		}
+	}
+
+	summary := fmt.Sprintf("Total size: %s • Data: %s • Indexes: %s • Index ratio: %s",
+		utils.FormatBytes(o.TotalBytes),
+		utils.FormatBytes(o.DataBytes),
+		utils.FormatBytes(o.IndexBytes),
+		utils.FormatRatio(o.IndexBytes, o.DataBytes+o.IndexBytes))
+
+	connections := "Connections: n/a"
+	if o.Connections >= 0 && o.MaxConnections > 0 {
+		connections = fmt.Sprintf("Connections: %d of %d (%s)", o.Connections, o.MaxConnections,
+			utils.FormatRatio(int64(o.Connections), int64(o.MaxConnections)))
+	} else if o.Connections >= 0 {
+		connections = fmt.Sprintf("Connections: %d", o.Connections)
+	}
+
+	if breakdown := utils.FormatConnectionBreakdown(o.ConnectionsByDatabase, 5); breakdown != "" {
+		connections += "\nBy database: " + breakdown
+	}
+
+	builder.WithContent(
+		styles.SubtitleStyle.Render(summary+"\n"+connections),
+		RenderSectionTitle(fmt.Sprintf("Top %d tables by size", len(o.Tables))),
+	)
+
+	if len(o.Tables) == 0 {
+		builder.WithContent(RenderEmptyState("📭", "No tables found."))
+	} else {
+		builder.WithContent(m.OverviewTable.View())
+	}
+
+	vacuumLabel := "vacuum"
+	if m.SelectedDB.Driver == "mysql" || m.SelectedDB.Driver == "mariadb" {
+		vacuumLabel = "optimize"
+	}
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("a") + ": analyze • " +
+			styles.KeyStyle.Render("V") + ": " + vacuumLabel + " • " +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("esc") + ": back to tables",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
+
+// SlowQueryView renders the slow query log browser
+func SlowQueryView(m models.Model) string {
+	order := "total time"
+	if m.SlowQuerySortByMean {
+		order = "mean time"
+	}
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🐢 Slow Queries (by %s)", order))
+
+	if m.IsLoadingSlowQueries {
+		builder.WithStatus("⏳ Loading statement statistics...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	}
+
+	if len(m.SlowQueries) == 0 {
+		builder.WithContent(RenderEmptyState("📭", "No statement statistics recorded yet."))
+	} else {
+		builder.WithContent(m.SlowQueryTable.View())
+
+		// Show the full text of the selected statement below the table
+		cursor := m.SlowQueryTable.Cursor()
+		if cursor >= 0 && cursor < len(m.SlowQueries) {
+			query := utils.SanitizeValueForDisplay(m.SlowQueries[cursor].Query)
+			width := max(m.Width-8, 40)
+			builder.WithContent(RenderInfoBox(utils.TruncateWithEllipsis(query, width*3, "...")))
+		}
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("enter") + ": explain in editor • " +
+			styles.KeyStyle.Render("c") + ": copy • " +
+			styles.KeyStyle.Render("s") + ": sort total/mean • " +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("esc") + ": back",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
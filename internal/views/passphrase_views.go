@@ -0,0 +1,46 @@
+package views
+
+import (
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// PassphraseView renders the master passphrase prompt, which unlocks the saved
+// connections at startup or sets the passphrase they are encrypted with
+func PassphraseView(m models.Model) string {
+	title := "🔐 Unlock Saved Connections"
+	if m.IsSettingPassphrase {
+		title = "🔐 Encrypt Saved Connections"
+	}
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.IsApplyingPassphrase {
+		builder.WithStatus("⏳ Deriving key...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	builder.WithContent(RenderInputField("Passphrase:", m.PassphraseInput.View(), m.PassphraseInput.Focused()))
+	if m.IsSettingPassphrase {
+		builder.WithContent(RenderInputField("Confirm passphrase:", m.PassphraseConfirmInput.View(), m.PassphraseConfirmInput.Focused()))
+		builder.WithContent(RenderInfoBox("Connection strings in ~/.mirador/connections.json are encrypted with AES-GCM under a key derived from this passphrase, which is asked for on every start. It cannot be recovered if lost. Leave both fields empty to store them in plaintext again."))
+	} else {
+		builder.WithContent(RenderInfoBox("Your saved connections are encrypted. Enter the master passphrase to read them."))
+	}
+
+	var helpText string
+	if m.IsSettingPassphrase {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": next/save • " +
+				styles.KeyStyle.Render("tab") + ": switch field • " +
+				styles.KeyStyle.Render("esc") + ": cancel",
+		)
+	} else {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": unlock • " +
+				styles.KeyStyle.Render("esc") + ": quit",
+		)
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,277 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// DataPreviewView renders the enhanced table data preview screen
+func DataPreviewView(m models.Model) string {
+	// Enhanced title with table name and row count
+	title := fmt.Sprintf("📋 %s (%s)", m.SelectedTable, utils.PreviewRowCountBadge(m.DataPreviewTotalRows, m.DataPreviewTableRows, m.DataPreviewFilterValue))
+	if m.TempResultTable != "" {
+		title = fmt.Sprintf("📋 %s (temporary, %s)", m.SelectedTable, utils.PreviewRowCountBadge(m.DataPreviewTotalRows, m.DataPreviewTableRows, m.DataPreviewFilterValue))
+	}
+	builder := NewViewBuilder().WithTitle(title)
+
+	// Show status messages with improved styling
+	if m.IsExporting {
+		builder.WithStatus("⏳ Exporting data...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ Error: "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	}
+
+	// Build content sections
+	var contentElements []string
+
+	// Only show the table if it has both columns and rows
+	if len(m.DataPreviewTable.Columns()) > 0 && len(m.DataPreviewTable.Rows()) > 0 {
+		// Calculate pagination info with better formatting
+		totalPages := (m.DataPreviewTotalRows + m.DataPreviewItemsPerPage - 1) / m.DataPreviewItemsPerPage
+		if totalPages == 0 {
+			totalPages = 1
+		}
+		currentPage := m.DataPreviewCurrentPage + 1
+
+		// Calculate current row range
+		startRow := (m.DataPreviewCurrentPage * m.DataPreviewItemsPerPage) + 1
+		endRow := startRow + len(m.DataPreviewTable.Rows()) - 1
+
+		// Build compact metadata block
+		var metadata strings.Builder
+
+		// Row range information
+		metadata.WriteString(fmt.Sprintf("Rows %d-%d of %d", startRow, endRow, m.DataPreviewTotalRows))
+
+		// Page navigation
+		if totalPages > 1 {
+			metadata.WriteString(fmt.Sprintf(" • Page %d/%d", currentPage, totalPages))
+		}
+
+		// Column scroll indicator
+		totalCols := len(m.DataPreviewAllColumns)
+		startCol := m.DataPreviewScrollOffset + 1
+		endCol := m.DataPreviewScrollOffset + m.DataPreviewVisibleCols
+		if endCol > totalCols {
+			endCol = totalCols
+		}
+		metadata.WriteString(fmt.Sprintf(" • Columns %d-%d of %d", startCol, endCol, totalCols))
+
+		// Table note
+		if note := m.TableNotes[m.SelectedTable]; note != "" {
+			metadata.WriteString(" • 📝 " + utils.NotePreview(note, 40))
+		}
+
+		// Sort indicator, listing every key in priority order
+		if sortKeys := utils.PreviewSortKeys(m); len(sortKeys) > 0 {
+			metadata.WriteString(" • " + utils.FormatSortKeys(sortKeys))
+		}
+
+		// JSON path columns added to the SELECT
+		if label := utils.JSONColumnsLabel(m); label != "" {
+			metadata.WriteString(" • " + label)
+		}
+
+		// Columns shown as humanized durations
+		if label := utils.DurationColumnsLabel(m); label != "" {
+			metadata.WriteString(" • " + label)
+		}
+
+		// Filter indicator
+		if m.DataPreviewFilterValue != "" {
+			metadata.WriteString(fmt.Sprintf(" • Filtered: '%s'", m.DataPreviewFilterValue))
+		}
+
+		// Undecodable text indicator
+		if invalid := utils.CountInvalidUTF8(m.DataPreviewAllRows); invalid > 0 {
+			metadata.WriteString(fmt.Sprintf(" • %s in %d values", utils.InvalidUTF8Badge, invalid))
+		}
+
+		// Row marked for a diff
+		if m.RowDiffMarked.Label != "" && m.RowDiffMarked.Table == m.SelectedTable {
+			metadata.WriteString(" • 📌 " + m.RowDiffMarked.Label)
+		}
+
+		// Display timezone indicator
+		if m.DisplayTimezone != nil {
+			metadata.WriteString(" • 🕒 " + utils.TimezoneLabel(m.DisplayTimezone))
+		}
+
+		// Add metadata as single compact line
+		contentElements = append(contentElements, styles.SubtitleStyle.Render(metadata.String()))
+
+		// Enhanced filter input with better styling
+		if m.DataPreviewFilterActive {
+			filterLabel := styles.SubtitleStyle.Render("🔍 Filter:")
+			var filterField string
+			if m.DataPreviewFilterInput.Focused() {
+				filterField = styles.InputFocusedStyle.Render(m.DataPreviewFilterInput.View())
+			} else {
+				filterField = styles.InputStyle.Render(m.DataPreviewFilterInput.View())
+			}
+			contentElements = append(contentElements, filterLabel+" "+filterField+renderFilterMatchBadge(m))
+		}
+		if m.JSONColumnActive {
+			contentElements = append(contentElements, renderJSONColumnPrompt(m))
+		}
+
+		// Enhanced sort mode indicator with clear navigation and state messaging
+		if m.DataPreviewSortMode {
+			var sortModeInfo string
+			if m.DataPreviewSortColumn != "" {
+				// A column is selected - show its current state and next action
+				switch m.DataPreviewSortDirection {
+				case models.SortOff:
+					// Column selected but not sorted yet
+					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' → Press ENTER to sort ascending (↑/↓ to change column)",
+						m.DataPreviewSortColumn)
+				case models.SortAsc:
+					// Currently sorted ascending
+					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' 🔼 ascending → Press ENTER for descending (↑/↓ to change column)",
+						m.DataPreviewSortColumn)
+				case models.SortDesc:
+					// Currently sorted descending
+					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' 🔽 descending → Press ENTER to clear sort (↑/↓ to change column)",
+						m.DataPreviewSortColumn)
+				}
+			} else {
+				// No column selected yet - emphasize navigation
+				sortModeInfo = "🎯 Sort Mode: Use ↑/↓ to select column, then ENTER to sort"
+			}
+			contentElements = append(contentElements, styles.WarningStyle.Render(sortModeInfo))
+		}
+
+		// Database type of each visible column, laid out over its header
+		if typesRow := renderColumnTypesRow(m.DataPreviewColumnTypes, m.DataPreviewScrollOffset, m.DataPreviewTable.Columns()); typesRow != "" {
+			contentElements = append(contentElements, typesRow)
+		}
+
+		// NULL and distinct counts of the loaded page, laid out over each header
+		if m.DataPreviewShowSummary {
+			summary := utils.PageSummaryLabels(utils.SummarizePage(len(m.DataPreviewAllColumns), m.DataPreviewAllRows, m.DataPreviewNulls))
+			if summaryRow := renderHeaderRow(summary, m.DataPreviewScrollOffset, m.DataPreviewTable.Columns(), styles.ColumnSummaryStyle); summaryRow != "" {
+				contentElements = append(contentElements, summaryRow)
+			}
+		}
+
+		// Add table directly without separators (table has its own borders)
+		contentElements = append(contentElements, m.DataPreviewTable.View())
+
+		// Sum, average, minimum, and maximum of the first visible numeric column
+		if footer := utils.PreviewAggregateFooter(m); footer != "" {
+			contentElements = append(contentElements, styles.InfoStyle.Render(footer))
+		}
+
+	} else {
+		// Keep the filter input reachable so a filter can be changed from an empty result
+		if m.DataPreviewFilterActive {
+			filterLabel := styles.SubtitleStyle.Render("🔍 Filter:")
+			contentElements = append(contentElements, filterLabel+" "+styles.InputFocusedStyle.Render(m.DataPreviewFilterInput.View())+renderFilterMatchBadge(m))
+		}
+		if m.JSONColumnActive {
+			contentElements = append(contentElements, renderJSONColumnPrompt(m))
+		}
+		if m.Err == nil && m.QueryResult == "" && !m.IsExporting {
+			contentElements = append(contentElements, styles.InfoStyle.Render(utils.PreviewEmptyMessage(m.DataPreviewTableRows, m.DataPreviewFilterValue)))
+		}
+	}
+
+	// Enhanced help text with better grouping and visual hierarchy
+	var helpText string
+	if m.DataPreviewFilterActive {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("ENTER") + ": apply filter • " +
+				styles.KeyStyle.Render("ESC") + ": cancel filter")
+	} else if m.JSONColumnActive {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("ENTER") + ": add column (empty removes this table's path columns) • " +
+				styles.KeyStyle.Render("ESC") + ": cancel")
+	} else if m.DataPreviewSortMode {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑↓") + ": select column • " +
+				styles.KeyStyle.Render("ENTER") + ": cycle sort (off→asc→desc) • " +
+				styles.KeyStyle.Render("ESC") + ": exit sort")
+	} else {
+		// Compact help for normal mode
+		baseHelp := styles.KeyStyle.Render("?") + ": help • " +
+			styles.KeyStyle.Render("↑↓←→") + ": navigate • " +
+			styles.KeyStyle.Render("ENTER") + ": details • " +
+			styles.KeyStyle.Render("/") + ": filter • " +
+			styles.KeyStyle.Render("o") + ": sort " + utils.PreviewCursorColumn(m.DataPreviewAllColumns, m.DataPreviewScrollOffset) + " • " +
+			styles.KeyStyle.Render("ESC") + ": back"
+
+		// Full help with all options
+		fullHelp := styles.KeyStyle.Render("hjkl/↑↓←→") + ": navigate • " +
+			styles.KeyStyle.Render("ENTER") + ": row details • " +
+			styles.KeyStyle.Render("←→") + ": pages • " +
+			styles.KeyStyle.Render("/") + ": filter • " +
+			styles.KeyStyle.Render("c") + ": clear filter • " +
+			styles.KeyStyle.Render("p") + ": filter first visible column to clipboard value • " +
+			styles.KeyStyle.Render("o") + ": sort by first visible column (asc→desc→off) • " +
+			styles.KeyStyle.Render("O") + ": add it as the next sort key • " +
+			styles.KeyStyle.Render("s") + ": sort mode • " +
+			styles.KeyStyle.Render("U") + ": draft UPDATE for filter • " +
+			styles.KeyStyle.Render("J") + ": add JSON path column • " +
+			styles.KeyStyle.Render("w") + ": watch row • " +
+			styles.KeyStyle.Render("W") + ": watchlist • " +
+			styles.KeyStyle.Render("m") + ": mark row for diff • " +
+			styles.KeyStyle.Render("=") + ": diff marked row with this one • " +
+			styles.KeyStyle.Render("Z") + ": cycle timezone • " +
+			styles.KeyStyle.Render("H") + ": durations in first visible column • " +
+			styles.KeyStyle.Render(",") + ": number separators • " +
+			styles.KeyStyle.Render("N") + ": page NULL/distinct counts • " +
+			styles.KeyStyle.Render("A") + ": aggregate first visible column over all rows • " +
+			styles.KeyStyle.Render("ctrl+r") + ": reload • " +
+			styles.KeyStyle.Render("ESC") + ": back • " +
+			styles.KeyStyle.Render("?") + ": hide help"
+
+		helpText = RenderContextualHelp(baseHelp, fullHelp, m.ShowFullHelp)
+	}
+
+	return builder.WithContent(contentElements...).WithHelp(helpText).Render()
+}
+
+// renderFilterMatchBadge shows the live match count beside the filter prompt
+func renderFilterMatchBadge(m models.Model) string {
+	badge := utils.FilterMatchBadge(m.DataPreviewFilterInput.Value(), m.FilterCountValue, m.FilterCount, m.FilterCountErr, m.IsCountingFilter)
+	if badge == "" {
+		return ""
+	}
+	return " " + styles.HelpStyle.Render(badge)
+}
+
+// renderJSONColumnPrompt renders the prompt for a JSON path column
+func renderJSONColumnPrompt(m models.Model) string {
+	return styles.SubtitleStyle.Render("{} JSON path:") + " " + styles.InputFocusedStyle.Render(m.JSONColumnInput.View())
+}
+
+// renderColumnTypesRow renders the database types of the table's columns, which
+// start at offset within types, as a dimmed row aligned with the headers
+func renderColumnTypesRow(types []string, offset int, columns []table.Column) string {
+	return renderHeaderRow(types, offset, columns, styles.ColumnTypesStyle)
+}
+
+// renderHeaderRow renders a label per column, starting at offset within labels,
+// as a row aligned with the headers
+func renderHeaderRow(labels []string, offset int, columns []table.Column, style lipgloss.Style) string {
+	if offset >= len(labels) {
+		return ""
+	}
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = col.Width
+	}
+	row := utils.ColumnTypesRow(labels[offset:], widths)
+	if row == "" {
+		return ""
+	}
+	return style.Render(row)
+}
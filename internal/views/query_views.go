@@ -5,23 +5,51 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-
 	"github.com/dancaldera/mirador/internal/models"
 	"github.com/dancaldera/mirador/internal/styles"
 	"github.com/dancaldera/mirador/internal/utils"
 )
 
+// writeConfirmPrompt asks to confirm a write, naming production or safe mode as the reason
+func writeConfirmPrompt(m models.Model, question string) string {
+	if m.Environment == models.EnvironmentProduction {
+		return "🚨 Production: " + question + " (y/n)"
+	}
+	return "🛡️ Safe mode: " + question + " (y/n)"
+}
+
 // QueryView renders the SQL query execution screen
 func QueryView(m models.Model) string {
-	builder := NewViewBuilder().WithTitle("⚡  SQL Query Runner")
+	caps := models.DriverCapabilities(m.SelectedDB.Driver)
+	title := "⚡  SQL Query Runner"
+	if m.CostCheckEnabled && caps.ExplainEstimates {
+		title += " • 💰 cost check"
+	}
+	builder := NewViewBuilder().WithTitle(title)
 
 	// Add status messages
-	if m.IsExecutingQuery {
-		builder.WithStatus("⏳ Executing query...", StatusLoading)
+	if m.IsConfirmingSafeOverride && !caps.TransactionalDDL && utils.ScriptHasSchemaChange(m.QueryInput.Value()) {
+		builder.WithStatus(writeConfirmPrompt(m, m.SelectedDB.Name+" commits schema changes immediately and cannot roll them back. Run it anyway?"), StatusWarning)
+	} else if m.IsConfirmingSafeOverride {
+		builder.WithStatus(writeConfirmPrompt(m, "this statement writes data. Run it anyway?"), StatusWarning)
+	} else if m.IsConfirmingCost {
+		builder.WithStatus(fmt.Sprintf("💰 %s. l: add LIMIT %d and run • y: run anyway • n: cancel", m.CostWarning, utils.CostCheckLimit), StatusWarning)
+	} else if m.IsCheckingCost {
+		builder.WithStatus("⏳ Estimating query cost...", StatusLoading)
+	} else if m.IsExecutingQuery && m.IsCancellingStatement {
+		builder.WithStatus("⏳ Cancelling our statement... • ctrl+c: quit", StatusLoading)
+	} else if m.IsExecutingQuery {
+		builder.WithStatus("⏳ Executing query... • esc/ctrl+c: cancel my statement", StatusLoading)
+	} else if m.IsMaterializingResult {
+		builder.WithStatus("⏳ Copying the result into a temporary table...", StatusLoading)
 	} else if m.IsExporting {
 		builder.WithStatus("⏳ Exporting data...", StatusLoading)
 	} else if m.Err != nil {
 		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.HasDraftedUpdate {
+		builder.WithStatus(fmt.Sprintf("✏️ Drafted UPDATE matches %d rows from the preview filter; edit the SET clause before running", m.DraftedUpdateRows), StatusWarning)
+	} else if !m.WorksheetRestored.IsZero() && m.QueryResult == "" {
+		builder.WithStatus("📝 Restored the worksheet autosaved "+m.WorksheetRestored.Local().Format("2006-01-02 15:04"), StatusInfo)
 	}
 
 	// Query input field
@@ -31,15 +59,44 @@ func QueryView(m models.Model) string {
 	var contentElements []string
 	contentElements = append(contentElements, queryField)
 
+	// Completion candidates for the word before the cursor
+	if m.CompletionActive {
+		contentElements = append(contentElements, renderCompletionPopup(m))
+	}
+
+	// Values of the query's placeholders, asked for one at a time
+	if m.IsBindingParams {
+		contentElements = append(contentElements, renderBindPrompt(m))
+	}
+
+	// Statement navigator for multi-statement scripts
+	if len(m.QueryStatements) > 1 && !m.IsExecutingQuery {
+		contentElements = append(contentElements, renderStatementNavigator(m))
+	}
+
 	// Add query results if present
 	if m.QueryResult != "" {
 		resultLabel := RenderSectionTitle("Query Result:")
 		resultText := styles.SuccessStyle.Render(m.QueryResult)
+		if m.QueryResultPartial {
+			resultLabel = RenderSectionTitle("Query Result:") + " " + styles.WarningStyle.Render("⏱ partial result (timed out)")
+			resultText = styles.WarningStyle.Render(m.QueryResult)
+		}
 
 		// Only show the table if it has both columns and rows
 		if len(m.QueryResultsTable.Columns()) > 0 && len(m.QueryResultsTable.Rows()) > 0 {
-			tableContent := styles.CardStyle.Render(m.QueryResultsTable.View())
+			tableView := m.QueryResultsTable.View()
+			if m.QueryResultTransposed {
+				// Types are shown next to the column names instead
+				resultLabel += " " + styles.InfoStyle.Render("↔ transposed")
+			} else if typesRow := renderColumnTypesRow(m.QueryResultColumnTypes, 0, m.QueryResultsTable.Columns()); typesRow != "" {
+				tableView = lipgloss.JoinVertical(lipgloss.Left, typesRow, tableView)
+			}
+			tableContent := styles.CardStyle.Render(tableView)
 			resultContent := lipgloss.JoinVertical(lipgloss.Left, resultLabel, resultText, tableContent)
+			if footer := utils.QueryResultAggregateFooter(m); footer != "" {
+				resultContent = lipgloss.JoinVertical(lipgloss.Left, resultContent, styles.InfoStyle.Render(footer))
+			}
 			contentElements = append(contentElements, resultContent)
 		} else {
 			resultContent := lipgloss.JoinVertical(lipgloss.Left, resultLabel, resultText)
@@ -57,16 +114,42 @@ func QueryView(m models.Model) string {
 	)
 	contentElements = append(contentElements, examples)
 
-	baseHelp := styles.KeyStyle.Render("?") + ": help • " +
+	if m.IsBindingParams {
+		helpText := styles.HelpStyle.Render(
+			styles.KeyStyle.Render("Enter") + ": next value, run after the last • " +
+				styles.KeyStyle.Render("Shift+Tab/↑") + ": previous value • " +
+				styles.KeyStyle.Render("Esc") + ": cancel")
+		return builder.WithContent(contentElements...).WithHelp(helpText).Render()
+	}
+
+	if m.CompletionActive {
+		helpText := styles.HelpStyle.Render(
+			styles.KeyStyle.Render("Tab/↑↓") + ": choose • " +
+				styles.KeyStyle.Render("Enter") + ": complete • " +
+				styles.KeyStyle.Render("Esc") + ": close")
+		return builder.WithContent(contentElements...).WithHelp(helpText).Render()
+	}
+
+	baseHelp := styles.KeyStyle.Render("?") + ": help (results focused) • " +
 		styles.KeyStyle.Render("Enter") + ": execute • " +
-		styles.KeyStyle.Render("Tab") + ": switch focus • " +
+		styles.KeyStyle.Render("Alt+Enter") + ": new line • " +
+		styles.KeyStyle.Render("Tab") + ": complete • " +
 		styles.KeyStyle.Render("Esc") + ": back"
 
-	fullHelp := styles.KeyStyle.Render("Enter") + ": execute query • " +
-		styles.KeyStyle.Render("Tab") + ": switch focus • " +
+	fullHelp := styles.KeyStyle.Render("Enter") + ": execute query, asking for the values of $1, ?, or :name placeholders • " +
+		styles.KeyStyle.Render("Alt+Enter") + ": new line • " +
+		styles.KeyStyle.Render("Tab") + ": complete table, column, or keyword; after a space, switch focus • " +
 		styles.KeyStyle.Render("↑/↓") + ": navigate results • " +
+		styles.KeyStyle.Render("←/→") + ": aggregated result column (results focused) • " +
+		styles.KeyStyle.Render("Ctrl+↑/↓") + ": recall history • " +
+		styles.KeyStyle.Render("Ctrl+N/P") + ": next/previous statement • " +
+		styles.KeyStyle.Render("Ctrl+O") + ": transpose small results • " +
+		RenderKeyHelp("Ctrl+T", "open result as temporary table", caps.TempTables) + " • " +
+		RenderKeyHelp("Ctrl+X", "toggle cost check", caps.ExplainEstimates) + " • " +
+		styles.KeyStyle.Render("Esc/Ctrl+C/Ctrl+K") + ": cancel my running statement • " +
 		styles.KeyStyle.Render("Ctrl+E") + ": export CSV • " +
 		styles.KeyStyle.Render("Ctrl+J") + ": export JSON • " +
+		styles.KeyStyle.Render("Ctrl+G") + ": toggle safe mode • " +
 		styles.KeyStyle.Render("Esc") + ": back to tables • " +
 		styles.KeyStyle.Render("?") + ": hide help"
 
@@ -78,321 +161,78 @@ func QueryView(m models.Model) string {
 		Render()
 }
 
-// QueryHistoryView renders the query history screen
-func QueryHistoryView(m models.Model) string {
-	builder := NewViewBuilder().WithTitle("📝 Query History")
-
-	if len(m.QueryHistory) == 0 {
-		emptyState := RenderEmptyState("📝", "No query history yet.\n\nExecute some queries to see them here!")
-		builder.WithContent(m.QueryHistoryList.View(), emptyState)
-	} else {
-		builder.WithContent(m.QueryHistoryList.View())
+// renderBindPrompt lists the query's placeholders with the values typed so
+// far and the input of the one being typed
+func renderBindPrompt(m models.Model) string {
+	lines := []string{styles.SubtitleStyle.Render(fmt.Sprintf("🔣 Bind values (%d of %d) • NULL binds NULL", m.BindIndex+1, len(m.BindParams)))}
+	for i, param := range m.BindParams {
+		switch {
+		case i == m.BindIndex:
+			lines = append(lines, styles.KeyStyle.Render("▶ "+param+" = ")+m.BindInput.View())
+		case i < m.BindIndex:
+			lines = append(lines, styles.HelpStyle.Render("  "+param+" = "+utils.SafeDisplayText(m.BindValues[param])))
+		default:
+			lines = append(lines, styles.HelpStyle.Render("  "+param))
+		}
 	}
-
-	helpText := styles.HelpStyle.Render(
-		styles.KeyStyle.Render("enter") + ": use query • " +
-			styles.KeyStyle.Render("d") + ": delete • " +
-			styles.KeyStyle.Render("esc") + ": back",
-	)
-
-	return builder.WithHelp(helpText).Render()
+	return styles.CardStyle.Render(strings.Join(lines, "\n"))
 }
 
-// DataPreviewView renders the enhanced table data preview screen
-func DataPreviewView(m models.Model) string {
-	// Enhanced title with table name and row count
-	title := fmt.Sprintf("📋 %s (%d rows)", m.SelectedTable, m.DataPreviewTotalRows)
-	builder := NewViewBuilder().WithTitle(title)
-
-	// Show status messages with improved styling
-	if m.IsExporting {
-		builder.WithStatus("⏳ Exporting data...", StatusLoading)
-	} else if m.Err != nil {
-		builder.WithStatus("❌ Error: "+m.Err.Error(), StatusError)
-	} else if m.QueryResult != "" {
-		builder.WithStatus(m.QueryResult, StatusSuccess)
+// renderCompletionPopup lists the completion candidates in view, marking the
+// selected one
+func renderCompletionPopup(m models.Model) string {
+	if len(m.Completions) == 0 {
+		return styles.CardStyle.Render(styles.LoadingStyle.Render("⏳ Loading columns..."))
 	}
-
-	// Build content sections
-	var contentElements []string
-
-	// Only show the table if it has both columns and rows
-	if len(m.DataPreviewTable.Columns()) > 0 && len(m.DataPreviewTable.Rows()) > 0 {
-		// Calculate pagination info with better formatting
-		totalPages := (m.DataPreviewTotalRows + m.DataPreviewItemsPerPage - 1) / m.DataPreviewItemsPerPage
-		if totalPages == 0 {
-			totalPages = 1
-		}
-		currentPage := m.DataPreviewCurrentPage + 1
-
-		// Calculate current row range
-		startRow := (m.DataPreviewCurrentPage * m.DataPreviewItemsPerPage) + 1
-		endRow := startRow + len(m.DataPreviewTable.Rows()) - 1
-
-		// Build compact metadata block
-		var metadata strings.Builder
-
-		// Row range information
-		metadata.WriteString(fmt.Sprintf("Rows %d-%d of %d", startRow, endRow, m.DataPreviewTotalRows))
-
-		// Page navigation
-		if totalPages > 1 {
-			metadata.WriteString(fmt.Sprintf(" • Page %d/%d", currentPage, totalPages))
-		}
-
-		// Column scroll indicator
-		totalCols := len(m.DataPreviewAllColumns)
-		startCol := m.DataPreviewScrollOffset + 1
-		endCol := m.DataPreviewScrollOffset + m.DataPreviewVisibleCols
-		if endCol > totalCols {
-			endCol = totalCols
-		}
-		metadata.WriteString(fmt.Sprintf(" • Columns %d-%d of %d", startCol, endCol, totalCols))
-
-		// Sort indicator
-		if m.DataPreviewSortColumn != "" {
-			var sortIcon string
-			switch m.DataPreviewSortDirection {
-			case models.SortAsc:
-				sortIcon = "🔼"
-			case models.SortDesc:
-				sortIcon = "🔽"
-			}
-			metadata.WriteString(fmt.Sprintf(" • %s %s", sortIcon, m.DataPreviewSortColumn))
-		}
-
-		// Filter indicator
-		if m.DataPreviewFilterValue != "" {
-			metadata.WriteString(fmt.Sprintf(" • Filtered: '%s'", m.DataPreviewFilterValue))
-		}
-
-		// Add metadata as single compact line
-		contentElements = append(contentElements, styles.SubtitleStyle.Render(metadata.String()))
-
-		// Enhanced filter input with better styling
-		if m.DataPreviewFilterActive {
-			filterLabel := styles.SubtitleStyle.Render("🔍 Filter:")
-			var filterField string
-			if m.DataPreviewFilterInput.Focused() {
-				filterField = styles.InputFocusedStyle.Render(m.DataPreviewFilterInput.View())
-			} else {
-				filterField = styles.InputStyle.Render(m.DataPreviewFilterInput.View())
-			}
-			contentElements = append(contentElements, filterLabel+" "+filterField)
-		}
-
-		// Enhanced sort mode indicator with clear navigation and state messaging
-		if m.DataPreviewSortMode {
-			var sortModeInfo string
-			if m.DataPreviewSortColumn != "" {
-				// A column is selected - show its current state and next action
-				switch m.DataPreviewSortDirection {
-				case models.SortOff:
-					// Column selected but not sorted yet
-					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' → Press ENTER to sort ascending (↑/↓ to change column)",
-						m.DataPreviewSortColumn)
-				case models.SortAsc:
-					// Currently sorted ascending
-					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' 🔼 ascending → Press ENTER for descending (↑/↓ to change column)",
-						m.DataPreviewSortColumn)
-				case models.SortDesc:
-					// Currently sorted descending
-					sortModeInfo = fmt.Sprintf("🎯 Sort Mode: '%s' 🔽 descending → Press ENTER to clear sort (↑/↓ to change column)",
-						m.DataPreviewSortColumn)
-				}
-			} else {
-				// No column selected yet - emphasize navigation
-				sortModeInfo = "🎯 Sort Mode: Use ↑/↓ to select column, then ENTER to sort"
-			}
-			contentElements = append(contentElements, styles.WarningStyle.Render(sortModeInfo))
+	visible, start := utils.VisibleCompletions(m)
+	lines := make([]string, len(visible))
+	for i, candidate := range visible {
+		if start+i == m.CompletionIndex {
+			lines[i] = styles.KeyStyle.Render("▶ " + candidate)
+		} else {
+			lines[i] = "  " + candidate
 		}
-
-		// Add table directly without separators (table has its own borders)
-		contentElements = append(contentElements, m.DataPreviewTable.View())
-
-	} else if m.Err == nil && m.QueryResult == "" && !m.IsExporting {
-		contentElements = append(contentElements, styles.InfoStyle.Render("📭 No data to display"))
 	}
-
-	// Enhanced help text with better grouping and visual hierarchy
-	var helpText string
-	if m.DataPreviewFilterActive {
-		helpText = styles.HelpStyle.Render(
-			styles.KeyStyle.Render("ENTER") + ": apply filter • " +
-				styles.KeyStyle.Render("ESC") + ": cancel filter")
-	} else if m.DataPreviewSortMode {
-		helpText = styles.HelpStyle.Render(
-			styles.KeyStyle.Render("↑↓") + ": select column • " +
-				styles.KeyStyle.Render("ENTER") + ": cycle sort (off→asc→desc) • " +
-				styles.KeyStyle.Render("ESC") + ": exit sort")
-	} else {
-		// Compact help for normal mode
-		baseHelp := styles.KeyStyle.Render("?") + ": help • " +
-			styles.KeyStyle.Render("↑↓←→") + ": navigate • " +
-			styles.KeyStyle.Render("ENTER") + ": details • " +
-			styles.KeyStyle.Render("/") + ": filter • " +
-			styles.KeyStyle.Render("s") + ": sort • " +
-			styles.KeyStyle.Render("ESC") + ": back"
-
-		// Full help with all options
-		fullHelp := styles.KeyStyle.Render("hjkl/↑↓←→") + ": navigate • " +
-			styles.KeyStyle.Render("ENTER") + ": row details • " +
-			styles.KeyStyle.Render("←→") + ": pages • " +
-			styles.KeyStyle.Render("/") + ": filter • " +
-			styles.KeyStyle.Render("s") + ": sort • " +
-			styles.KeyStyle.Render("ctrl+r") + ": reload • " +
-			styles.KeyStyle.Render("ESC") + ": back • " +
-			styles.KeyStyle.Render("?") + ": hide help"
-
-		helpText = RenderContextualHelp(baseHelp, fullHelp, m.ShowFullHelp)
+	if len(m.Completions) > len(visible) {
+		lines = append(lines, styles.HelpStyle.Render(fmt.Sprintf("%d of %d", m.CompletionIndex+1, len(m.Completions))))
 	}
-
-	return builder.WithContent(contentElements...).WithHelp(helpText).Render()
+	return styles.CardStyle.Render(strings.Join(lines, "\n"))
 }
 
-// RowDetailView renders the detailed view of a selected row using a simple list
-func RowDetailView(m models.Model) string {
-	if m.IsViewingFieldDetail {
-		// Show full field detail view with scrolling
-		title := fmt.Sprintf("Field: %s", m.SelectedFieldForDetail)
-
-		// Find the selected field value
-		var fieldValue string
-		for i, col := range m.DataPreviewAllColumns {
-			if col == m.SelectedFieldForDetail && i < len(m.SelectedRowData) {
-				fieldValue = m.SelectedRowData[i]
-				break
-			}
-		}
-
-		// Format field value (handles JSON pretty-printing)
-		fieldValue = utils.FormatFieldValue(fieldValue)
-
-		// Split content into lines for scrolling
-		lines := strings.Split(fieldValue, "\n")
-
-		// Calculate dynamic height accounting for ViewBuilder elements
-		// Title (2-3 lines), status (1-2 lines), help (1 line), margins
-		h, v := styles.DocStyle.GetFrameSize()
-		availableHeight := m.Height - v - 12 // Account for all UI elements
-		if availableHeight < 5 {
-			availableHeight = 5
-		}
-
-		// Calculate visible range
-		startLine := m.FieldDetailScrollOffset
-		endLine := min(startLine+availableHeight, len(lines))
-
-		// Calculate dynamic width (use window width minus padding)
-		availableWidth := m.Width - h - 8 // Account for frame and padding
-		if availableWidth < 40 {
-			availableWidth = 40
-		}
-		if availableWidth > 200 {
-			availableWidth = 200
-		}
-
-		// Build visible content with horizontal scrolling
-		var visibleLines []string
-		for i := startLine; i < endLine; i++ {
-			line := lines[i]
-			// Apply horizontal scrolling
-			if m.FieldDetailHorizontalOffset < len(line) {
-				endChar := min(m.FieldDetailHorizontalOffset+availableWidth, len(line))
-				line = line[m.FieldDetailHorizontalOffset:endChar]
-			} else {
-				line = ""
-			}
-			visibleLines = append(visibleLines, line)
-		}
-
-		// Join the visible lines
-		displayContent := strings.Join(visibleLines, "\n")
-
-		// Create scroll indicators
-		scrollInfo := ""
-
-		// Show line information
-		startDisplayLine := m.FieldDetailScrollOffset + 1
-		endDisplayLine := min(m.FieldDetailScrollOffset+len(visibleLines), len(lines))
-
-		if len(lines) > 1 {
-			scrollInfo = fmt.Sprintf(" • Lines %d-%d of %d", startDisplayLine, endDisplayLine, len(lines))
-		}
-
-		if m.FieldDetailHorizontalOffset > 0 {
-			scrollInfo += fmt.Sprintf(" • Column offset: %d", m.FieldDetailHorizontalOffset)
-		}
-
-		// Build with ViewBuilder
-		builder := NewViewBuilder().WithTitle(title)
-
-		if scrollInfo != "" {
-			builder.WithStatus(scrollInfo, StatusInfo)
-		}
-
-		// Render with dynamic dimensions
-		contentBox := styles.InputStyle.Width(availableWidth).Height(availableHeight).Render(displayContent)
-
-		helpText := styles.HelpStyle.Render(
-			styles.KeyStyle.Render("↑↓/jk") + ": scroll vertical • " +
-				styles.KeyStyle.Render("←→/hl") + ": scroll horizontal • " +
-				styles.KeyStyle.Render("esc") + ": back to field list",
-		)
-
-		return builder.WithContent(contentBox).WithHelp(helpText).Render()
-	}
-
-	// Show field list view or edit mode
-	if m.IsEditingField {
-		// Show simplified field editing interface
-		title := fmt.Sprintf("Edit Field: %s", m.EditingFieldName)
-		builder := NewViewBuilder().WithTitle(title)
-
-		// Show status messages
-		if m.Err != nil {
-			builder.WithStatus("❌ "+m.Err.Error(), StatusError)
-		} else if m.QueryResult != "" {
-			builder.WithStatus(m.QueryResult, StatusSuccess)
+// renderStatementNavigator lists each statement of the last script with its
+// status, timing, and row count, marking the one whose result is shown
+func renderStatementNavigator(m models.Model) string {
+	width := max(m.Width-40, 20)
+	lines := []string{RenderSectionTitle(fmt.Sprintf("Statements (%d/%d):", m.QueryStatementIndex+1, len(m.QueryStatements)))}
+	for i, st := range m.QueryStatements {
+		line := fmt.Sprintf("%d. %s", i+1, utils.StatementSummary(st, width))
+		if i == m.QueryStatementIndex {
+			lines = append(lines, styles.KeyStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
 		}
-
-		// Help text
-		helpText := styles.HelpStyle.Render(
-			styles.KeyStyle.Render("Ctrl+S") + ": save changes • " +
-				styles.KeyStyle.Render("Ctrl+K") + ": clear • " +
-				styles.KeyStyle.Render("Esc") + ": cancel",
-		)
-
-		return builder.WithContent(m.FieldTextarea.View()).WithHelp(helpText).Render()
 	}
+	return strings.Join(lines, "\n")
+}
 
-	// Default view: field list
-	fieldCount := len(m.DataPreviewAllColumns)
-	title := fmt.Sprintf("Row Details - %s (%d fields)", m.SelectedTable, fieldCount)
-	builder := NewViewBuilder().WithTitle(title)
-
-	if len(m.SelectedRowData) == 0 || len(m.DataPreviewAllColumns) == 0 {
-		builder.WithStatus("❌ No row data available", StatusError)
-		helpText := styles.HelpStyle.Render(styles.KeyStyle.Render("esc") + ": back to table")
-		return builder.WithHelp(helpText).Render()
-	}
+// QueryHistoryView renders the query history screen
+func QueryHistoryView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle("📝 Query History")
 
-	// Show status messages
-	if m.Err != nil {
-		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
-	} else if m.QueryResult != "" {
-		builder.WithStatus(m.QueryResult, StatusSuccess)
+	if len(m.QueryHistory) == 0 {
+		emptyState := RenderEmptyState("📝", "No query history yet.\n\nExecute some queries to see them here!")
+		builder.WithContent(m.QueryHistoryList.View(), emptyState)
+	} else {
+		builder.WithContent(m.QueryHistoryList.View())
 	}
 
-	// Add help text
 	helpText := styles.HelpStyle.Render(
-		styles.KeyStyle.Render("↑↓") + ": navigate fields • " +
-			styles.KeyStyle.Render("enter") + ": view field detail • " +
-			styles.KeyStyle.Render("e") + ": edit field • " +
-			styles.KeyStyle.Render("esc") + ": back to table",
+		styles.KeyStyle.Render("enter") + ": use query • " +
+			styles.KeyStyle.Render("d") + ": delete • " +
+			styles.KeyStyle.Render("esc") + ": back",
 	)
 
-	return builder.WithContent(m.RowDetailList.View()).WithHelp(helpText).Render()
+	return builder.WithHelp(helpText).Render()
 }
 
 func max(a, b int) int {
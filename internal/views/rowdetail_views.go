@@ -0,0 +1,233 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// RowDetailView renders the detailed view of a selected row using a simple list
+func RowDetailView(m models.Model) string {
+	if m.IsViewingFieldDetail {
+		// Show full field detail view with scrolling
+		title := fmt.Sprintf("Field: %s", m.SelectedFieldForDetail)
+		if m.DataPreviewTotalRows > 0 {
+			title += fmt.Sprintf(" • row %d of %d", m.SelectedRowIndex+1, m.DataPreviewTotalRows)
+		}
+
+		// Find the selected field value
+		fieldValue, fieldType := utils.FieldDetailColumn(m)
+
+		// Format field value (JSON pretty-printed, arrays and composites by element)
+		identifier, isIdentifier := utils.DetectIdentifier(fieldValue)
+		invalidUTF8 := utils.HasInvalidUTF8(fieldValue)
+		fieldValue = utils.FormatFieldDetail(utils.SafeDisplayText(fieldValue), fieldType)
+
+		// Split content into lines for scrolling
+		lines := strings.Split(fieldValue, "\n")
+
+		// Calculate dynamic height accounting for ViewBuilder elements
+		// Title (2-3 lines), status (1-2 lines), help (1 line), margins
+		h, v := styles.DocStyle.GetFrameSize()
+		availableHeight := m.Height - v - 12 // Account for all UI elements
+		if availableHeight < 5 {
+			availableHeight = 5
+		}
+
+		// Calculate visible range
+		startLine := m.FieldDetailScrollOffset
+		endLine := min(startLine+availableHeight, len(lines))
+
+		// Calculate dynamic width (use window width minus padding)
+		availableWidth := m.Width - h - 8 // Account for frame and padding
+		if availableWidth < 40 {
+			availableWidth = 40
+		}
+		if availableWidth > 200 {
+			availableWidth = 200
+		}
+
+		// Build visible content with horizontal scrolling
+		var visibleLines []string
+		for i := startLine; i < endLine; i++ {
+			line := lines[i]
+			// Apply horizontal scrolling
+			if m.FieldDetailHorizontalOffset < len(line) {
+				endChar := min(m.FieldDetailHorizontalOffset+availableWidth, len(line))
+				line = line[m.FieldDetailHorizontalOffset:endChar]
+			} else {
+				line = ""
+			}
+			visibleLines = append(visibleLines, line)
+		}
+
+		// Join the visible lines
+		displayContent := strings.Join(visibleLines, "\n")
+		if isIdentifier {
+			displayContent = styles.IdentifierStyle.Render(displayContent)
+		}
+
+		// Create scroll indicators
+		scrollInfo := ""
+
+		// Show line information
+		startDisplayLine := m.FieldDetailScrollOffset + 1
+		endDisplayLine := min(m.FieldDetailScrollOffset+len(visibleLines), len(lines))
+
+		if len(lines) > 1 {
+			scrollInfo = fmt.Sprintf(" • Lines %d-%d of %d", startDisplayLine, endDisplayLine, len(lines))
+		}
+
+		if m.FieldDetailHorizontalOffset > 0 {
+			scrollInfo += fmt.Sprintf(" • Column offset: %d", m.FieldDetailHorizontalOffset)
+		}
+
+		// Build with ViewBuilder
+		builder := NewViewBuilder().WithTitle(title)
+
+		if m.IsRefreshingRow {
+			builder.WithStatus("⏳ Refreshing row...", StatusLoading)
+		} else if m.IsLoadingPreview {
+			builder.WithStatus("⏳ Loading more rows...", StatusLoading)
+		} else if m.Err != nil {
+			builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+		} else if m.QueryResult != "" {
+			builder.WithStatus(m.QueryResult, StatusSuccess)
+		} else if invalidUTF8 {
+			builder.WithStatus("⚠️ Value contains bytes that are not valid UTF-8; they are shown as �", StatusWarning)
+		} else if converted, ok := utils.ConvertTimestampForDisplay(fieldValue, m.DisplayTimezone); ok {
+			builder.WithStatus(fmt.Sprintf("🕒 %s: %s (original shown below)", utils.TimezoneLabel(m.DisplayTimezone), converted), StatusInfo)
+		} else if humanized, ok := utils.FieldDetailDuration(m); ok {
+			builder.WithStatus(fmt.Sprintf("⏱ %s (original shown below)", humanized), StatusInfo)
+		} else if isIdentifier {
+			builder.WithStatus("🆔 "+utils.DescribeIdentifier(identifier, m.DisplayTimezone), StatusInfo)
+		} else if scrollInfo != "" {
+			builder.WithStatus(scrollInfo, StatusInfo)
+		}
+
+		// Render with dynamic dimensions
+		contentBox := styles.InputStyle.Width(availableWidth).Height(availableHeight).Render(displayContent)
+
+		identifierHelp := ""
+		if isIdentifier {
+			identifierHelp = styles.KeyStyle.Render("U/L") + ": copy upper/lowercase • "
+		}
+		helpText := styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑↓/jk") + ": scroll vertical • " +
+				styles.KeyStyle.Render("←→/hl") + ": scroll horizontal • " +
+				styles.KeyStyle.Render("c") + ": copy value • " +
+				identifierHelp +
+				styles.KeyStyle.Render("r") + ": refresh row • " +
+				styles.KeyStyle.Render("n/p") + ": next/previous row • " +
+				styles.KeyStyle.Render("esc") + ": back to field list",
+		)
+
+		return builder.WithContent(contentBox).WithHelp(helpText).Render()
+	}
+
+	// Show field list view or edit mode
+	if m.IsEditingField {
+		// Show simplified field editing interface
+		title := fmt.Sprintf("Edit Field: %s", m.EditingFieldName)
+		builder := NewViewBuilder().WithTitle(title)
+
+		// Show status messages
+		if m.IsConfirmingSafeOverride {
+			builder.WithStatus(writeConfirmPrompt(m, "save this change anyway?"), StatusWarning)
+		} else if m.Err != nil {
+			builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+		} else if m.QueryResult != "" {
+			builder.WithStatus(m.QueryResult, StatusSuccess)
+		}
+
+		// Help text
+		helpText := styles.HelpStyle.Render(
+			styles.KeyStyle.Render("Ctrl+S") + ": save changes • " +
+				styles.KeyStyle.Render("Ctrl+K") + ": clear • " +
+				styles.KeyStyle.Render("Esc") + ": cancel",
+		)
+
+		return builder.WithContent(m.FieldTextarea.View()).WithHelp(helpText).Render()
+	}
+
+	// Default view: field list
+	fieldCount := len(m.DataPreviewAllColumns)
+	title := fmt.Sprintf("Row Details - %s (%d fields)", m.SelectedTable, fieldCount)
+	search := m.SearchInput.Value()
+	if search != "" {
+		title = fmt.Sprintf("Row Details - %s (%d of %d fields)", m.SelectedTable, len(m.RowDetailList.Items()), fieldCount)
+	}
+	if m.DataPreviewTotalRows > 0 {
+		title += fmt.Sprintf(" • row %d of %d", m.SelectedRowIndex+1, m.DataPreviewTotalRows)
+	}
+	builder := NewViewBuilder().WithTitle(title)
+
+	if len(m.SelectedRowData) == 0 || len(m.DataPreviewAllColumns) == 0 {
+		builder.WithStatus("❌ No row data available", StatusError)
+		helpText := styles.HelpStyle.Render(styles.KeyStyle.Render("esc") + ": back to table")
+		return builder.WithHelp(helpText).Render()
+	}
+
+	// Show status messages
+	if m.IsRefreshingRow {
+		builder.WithStatus("⏳ Refreshing row...", StatusLoading)
+	} else if m.IsLoadingPreview {
+		builder.WithStatus("⏳ Loading more rows...", StatusLoading)
+	} else if m.IsLoadingRowHistory {
+		builder.WithStatus("⏳ Reading prior versions of the row...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	} else if m.DisplayTimezone != nil {
+		builder.WithStatus("🕒 Timestamps shown in "+utils.TimezoneLabel(m.DisplayTimezone), StatusInfo)
+	}
+
+	// Add help text; a read-only connection has no editing to offer
+	editHelp := styles.KeyStyle.Render("e") + ": edit field • "
+	if m.ReadOnly {
+		editHelp = ""
+	}
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑↓") + ": navigate fields • " +
+			styles.KeyStyle.Render("enter") + ": view field detail • " +
+			styles.KeyStyle.Render("/") + ": search fields • " +
+			editHelp +
+			styles.KeyStyle.Render("r") + ": refresh row • " +
+			styles.KeyStyle.Render("n/p") + ": next/previous row • " +
+			styles.KeyStyle.Render("H") + ": history • " +
+			styles.KeyStyle.Render("w") + ": watch • " +
+			styles.KeyStyle.Render("esc") + ": back to table",
+	)
+	if m.IsSearchingFields {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": keep search • " +
+				styles.KeyStyle.Render("esc") + ": clear search")
+	} else if search != "" {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑↓") + ": navigate fields • " +
+				styles.KeyStyle.Render("enter") + ": view field detail • " +
+				styles.KeyStyle.Render("/") + ": change search • " +
+				editHelp +
+				styles.KeyStyle.Render("esc") + ": clear search")
+	}
+
+	// Search input while typing, or the applied search term
+	if m.IsSearchingFields {
+		searchLabel := styles.SubtitleStyle.Render("🔍 Search:")
+		builder.WithContent(searchLabel + " " + styles.InputFocusedStyle.Render(m.SearchInput.View()))
+	} else if search != "" {
+		builder.WithContent(styles.SubtitleStyle.Render(fmt.Sprintf("Search: '%s'", search)))
+	}
+
+	if len(m.RowDetailList.Items()) == 0 {
+		builder.WithContent(RenderEmptyState("🔍", "No fields match the search."))
+	} else {
+		builder.WithContent(m.RowDetailList.View())
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,51 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// RowDiffView renders two rows of a table side by side, marking the fields that differ
+func RowDiffView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🔀 Row Diff - %s", m.SelectedTable))
+
+	_, differing := utils.BuildRowDiffRows(m.RowDiffLeft, m.RowDiffRight, false)
+	switch {
+	case m.Err != nil:
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	case differing == 0:
+		builder.WithStatus("✅ The rows are identical", StatusSuccess)
+	}
+
+	subtitle := fmt.Sprintf("%s ↔ %s • %d of %d fields differ",
+		m.RowDiffLeft.Label, m.RowDiffRight.Label, differing, len(m.RowDiffLeft.Columns))
+	if m.RowDiffOnlyChanges {
+		subtitle += " • showing differences only"
+	}
+	builder.WithContent(styles.SubtitleStyle.Render(subtitle))
+
+	if m.RowDiffOnlyChanges && differing == 0 {
+		builder.WithContent(RenderEmptyState("🟰", "No fields differ."))
+	} else {
+		builder.WithContent(
+			m.RowDiffTable.View(),
+			RenderInfoBox("≠ marks a field whose values differ; the note points out NULLs, changed JSON keys, and whitespace or case differences."),
+		)
+	}
+
+	toggle := "only differences"
+	if m.RowDiffOnlyChanges {
+		toggle = "all fields"
+	}
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate fields • " +
+			styles.KeyStyle.Render("d") + ": " + toggle + " • " +
+			styles.KeyStyle.Render("x") + ": swap sides • " +
+			styles.KeyStyle.Render("esc") + ": back to preview",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,38 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// RowHistoryView renders the prior versions of a row side by side, oldest on the left
+func RowHistoryView(m models.Model) string {
+	h := m.RowHistory
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🕰 Row History - %s", m.SelectedTable))
+
+	if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	visible := min(utils.RowHistoryVisibleVersions(m.Width), len(h.Versions))
+	subtitle := fmt.Sprintf("%d versions from %s", len(h.Versions), h.Source)
+	if visible < len(h.Versions) {
+		subtitle += fmt.Sprintf(" • showing %d-%d", m.RowHistoryOffset+1, m.RowHistoryOffset+visible)
+	}
+	builder.WithContent(
+		styles.SubtitleStyle.Render(subtitle),
+		m.RowHistoryTable.View(),
+		RenderInfoBox("✎ marks a value that changed from the version to its left."),
+	)
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate fields • " +
+			styles.KeyStyle.Render("←/→") + ": older/newer versions • " +
+			styles.KeyStyle.Render("esc") + ": back to row",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
@@ -0,0 +1,59 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// ServerSettingsView renders the searchable server configuration browser
+func ServerSettingsView(m models.Model) string {
+	title := fmt.Sprintf("⚙️  Server Settings (%d of %d)", len(m.FilteredServerSettings), len(m.ServerSettings))
+	builder := NewViewBuilder().WithTitle(title)
+
+	if m.IsLoadingSettings {
+		builder.WithStatus("⏳ Loading settings...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	var info string
+	if m.ServerSettingsOnlyChanged {
+		info = "Showing only settings that differ from defaults (●)"
+	} else {
+		info = "● marks settings that differ from defaults"
+	}
+	if search := m.SearchInput.Value(); search != "" && !m.IsSearchingSettings {
+		info += fmt.Sprintf(" • Search: '%s'", search)
+	}
+	builder.WithContent(styles.SubtitleStyle.Render(info))
+
+	if m.IsSearchingSettings {
+		searchLabel := styles.SubtitleStyle.Render("🔍 Search:")
+		searchField := styles.InputFocusedStyle.Render(m.SearchInput.View())
+		builder.WithContent(searchLabel + " " + searchField)
+	}
+
+	if len(m.FilteredServerSettings) == 0 {
+		builder.WithContent(RenderEmptyState("📭", "No matching settings."))
+	} else {
+		builder.WithContent(m.ServerSettingsTable.View())
+	}
+
+	var helpText string
+	if m.IsSearchingSettings {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": keep search • " +
+				styles.KeyStyle.Render("esc") + ": clear search")
+	} else {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+				styles.KeyStyle.Render("/") + ": search • " +
+				styles.KeyStyle.Render("m") + ": only modified • " +
+				styles.KeyStyle.Render("ctrl+r") + ": reload • " +
+				styles.KeyStyle.Render("esc") + ": back to tables")
+	}
+
+	return builder.WithHelp(helpText).Render()
+}
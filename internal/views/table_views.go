@@ -5,6 +5,7 @@ import (
 
 	"github.com/dancaldera/mirador/internal/models"
 	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
 )
 
 // SchemaView renders the schema selection screen
@@ -13,10 +14,17 @@ func SchemaView(m models.Model) string {
 
 	// Add loading or empty state
 	if m.IsLoadingSchemas {
-		builder.WithStatus("⏳ Loading schemas...", StatusLoading)
+		builder.WithStatus("⏳ Loading tables...", StatusLoading).
+			WithContent(m.SchemasList.View())
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError).
+			WithContent(m.SchemasList.View())
 	} else if len(m.Schemas) == 0 {
 		emptyState := RenderEmptyState("🗂️", "No additional schemas found.\n\nUsing default schema.")
 		builder.WithContent(m.SchemasList.View(), emptyState)
+	} else if m.SelectedDB.Driver == "sqlite3" {
+		hint := RenderInfoBox("Attach another file from the query runner: ATTACH DATABASE 'other.db' AS other")
+		builder.WithContent(m.SchemasList.View(), hint)
 	} else {
 		builder.WithContent(m.SchemasList.View())
 	}
@@ -31,11 +39,57 @@ func SchemaView(m models.Model) string {
 
 // TablesView renders the tables listing screen
 func TablesView(m models.Model) string {
-	builder := NewViewBuilder().WithTitle("📋 Available Tables")
-
-	if m.IsLoadingColumns {
+	title := "📋 Available Tables"
+	caps := models.DriverCapabilities(m.SelectedDB.Driver)
+	if m.SelectedSchema != "" && caps.Schemas {
+		title = fmt.Sprintf("📋 Available Tables: %s", m.SelectedSchema)
+	}
+	builder := NewViewBuilder().WithTitle(title).WithIndicator(RenderSessionHealth(m))
+
+	if m.IsConfirmingDestructive {
+		statement := "TRUNCATE TABLE"
+		if m.DestructiveAction == utils.DestructiveDrop {
+			statement = "DROP TABLE"
+		}
+		status := fmt.Sprintf("⚠️ %s %s cannot be undone. Type the table name to confirm.", statement, m.DestructiveTable)
+		if utils.ConfirmsWrites(m) {
+			status = fmt.Sprintf("⚠️ %s %s cannot be undone (%s). Type the table name to confirm.", statement, m.DestructiveTable, utils.WriteConfirmLabel(m))
+		}
+		builder.WithStatus(status, StatusWarning).
+			WithContent(RenderInputField("Table name:", m.DestructiveConfirmInput.View(), true), m.TablesList.View())
+	} else if m.IsRunningDestructive {
+		builder.WithStatus(fmt.Sprintf("⏳ Running %s on %s...", m.DestructiveAction, m.DestructiveTable), StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingColumns {
 		builder.WithStatus("⏳ Loading table columns...", StatusLoading).
 			WithContent(m.TablesList.View())
+	} else if m.IsLoadingOverview {
+		builder.WithStatus("⏳ Loading database overview...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingSettings {
+		builder.WithStatus("⏳ Loading server settings...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingSlowQueries {
+		builder.WithStatus("⏳ Loading slow queries...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingSchemas {
+		builder.WithStatus("⏳ Loading schemas...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingGrowth {
+		builder.WithStatus("⏳ Loading table growth...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingAudit {
+		builder.WithStatus("⏳ Loading audit log...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.IsLoadingTestData {
+		builder.WithStatus("⏳ Reading column constraints...", StatusLoading).
+			WithContent(m.TablesList.View())
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError).
+			WithContent(m.TablesList.View())
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess).
+			WithContent(m.TablesList.View())
 	} else if len(m.Tables) == 0 {
 		emptyState := RenderEmptyState("📋", "No tables found in this database.")
 		builder.WithContent(m.TablesList.View(), emptyState)
@@ -53,6 +107,22 @@ func TablesView(m models.Model) string {
 	fullHelp := styles.KeyStyle.Render("enter") + ": preview data • " +
 		styles.KeyStyle.Render("v") + ": view columns • " +
 		styles.KeyStyle.Render("f") + ": relationships • " +
+		RenderKeyHelp("S", "switch schema/database", caps.Schemas) + " • " +
+		RenderKeyHelp("o", "database overview", caps.SizeStats) + " • " +
+		RenderKeyHelp("L", "slow queries", caps.SlowQueries) + " • " +
+		RenderKeyHelp("C", "server settings", caps.ServerSettings) + " • " +
+		RenderKeyHelp("T", "table growth", caps.SizeStats) + " • " +
+		RenderKeyHelp("E", "LISTEN/NOTIFY monitor", caps.ListenNotify) + " • " +
+		styles.KeyStyle.Render("A") + ": audit log • " +
+		styles.KeyStyle.Render("W") + ": watchlist • " +
+		RenderKeyHelp("I", "generate test data", caps.TestData) + " • " +
+		styles.KeyStyle.Render("Y") + ": copy data to another connection • " +
+		styles.KeyStyle.Render("K") + ": compare with another table • " +
+		styles.KeyStyle.Render("M") + ": record migration • " +
+		styles.KeyStyle.Render("*") + ": star/unstar table • " +
+		styles.KeyStyle.Render("N") + ": table note • " +
+		RenderKeyHelp("X", "truncate", caps.TableDDL) + " • " +
+		RenderKeyHelp("D", "drop table", caps.TableDDL) + " • " +
 		styles.KeyStyle.Render("r") + ": run SQL queries • " +
 		styles.KeyStyle.Render("ctrl+h") + ": view query history • " +
 		styles.KeyStyle.Render("esc") + ": disconnect • " +
@@ -65,18 +135,48 @@ func TablesView(m models.Model) string {
 
 // ColumnsView renders the table columns display screen
 func ColumnsView(m models.Model) string {
-	title := fmt.Sprintf("Columns of table: %s", m.SelectedTable)
+	title := fmt.Sprintf("Columns of table: %s (%d of %d)", m.SelectedTable, m.FilteredColumnCount, len(m.ColumnDefinitions))
 
-	helpText := styles.HelpStyle.Render(
-		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
-			styles.KeyStyle.Render("esc") + ": back to tables",
-	)
+	var helpText string
+	if m.IsSearchingColumns {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("enter") + ": keep search • " +
+				styles.KeyStyle.Render("esc") + ": clear search")
+	} else {
+		helpText = styles.HelpStyle.Render(
+			styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+				styles.KeyStyle.Render("/") + ": search name/type • " +
+				styles.KeyStyle.Render("o") + ": sort by next field • " +
+				styles.KeyStyle.Render("O") + ": reverse sort • " +
+				styles.KeyStyle.Render("g") + ": group by • " +
+				styles.KeyStyle.Render("c") + ": chart over time • " +
+				styles.KeyStyle.Render("esc") + ": back to tables",
+		)
+	}
 
-	return NewViewBuilder().
-		WithTitle(title).
-		WithContent(m.ColumnsTable.View()).
-		WithHelp(helpText).
-		Render()
+	builder := NewViewBuilder().WithTitle(title)
+	if warning := utils.PersistenceWarning(utils.TablePersistence(m.TableInfos, m.SelectedTable)); warning != "" {
+		builder.WithStatus("⚠ "+warning, StatusWarning)
+	}
+	if note := m.TableNotes[m.SelectedTable]; note != "" {
+		builder.WithContent(RenderInfoBox("📝 " + note))
+	}
+
+	if m.IsSearchingColumns {
+		searchLabel := styles.SubtitleStyle.Render("🔍 Search:")
+		searchField := styles.InputFocusedStyle.Render(m.SearchInput.View())
+		builder.WithContent(searchLabel + " " + searchField)
+	} else if search := m.SearchInput.Value(); search != "" {
+		builder.WithContent(styles.SubtitleStyle.Render(fmt.Sprintf("Search: '%s'", search)))
+	}
+
+	if m.FilteredColumnCount == 0 && len(m.ColumnDefinitions) > 0 {
+		builder.WithContent(RenderEmptyState("📭", "No columns match the search."))
+	} else {
+		builder.WithContent(m.ColumnsTable.View())
+	}
+
+	return builder.WithHelp(helpText).Render()
 }
 
 // IndexesView renders the table indexes and constraints screen
@@ -0,0 +1,50 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// GenerateDataView renders the synthetic row generator for a table
+func GenerateDataView(m models.Model) string {
+	plan := m.TestDataPlan
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("🧪 Generate Test Data: %s", plan.Table))
+
+	if m.IsConfirmingSafeOverride {
+		builder.WithStatus(writeConfirmPrompt(m, "insert generated rows anyway?"), StatusWarning)
+	} else if m.IsInsertingTestData {
+		builder.WithStatus("⏳ Generating and inserting rows...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	} else if m.QueryResult != "" {
+		builder.WithStatus(m.QueryResult, StatusSuccess)
+	}
+
+	// Column plan: how each column will be filled
+	nameWidth, typeWidth := 0, 0
+	for _, col := range plan.Columns {
+		nameWidth = max(nameWidth, len(col.Name))
+		typeWidth = max(typeWidth, len(col.DataType))
+	}
+	var lines []string
+	for _, col := range plan.Columns {
+		lines = append(lines, fmt.Sprintf("%-*s  %-*s  → %s",
+			nameWidth, col.Name, typeWidth, col.DataType, utils.DescribeColumnPlan(col, plan.ParentKeys[col.Name])))
+	}
+	builder.WithContent(RenderSectionTitle("Column plan:"), styles.CardStyle.Render(strings.Join(lines, "\n")))
+
+	builder.WithContent(RenderInputField(fmt.Sprintf("Rows to insert (max %d):", utils.MaxTestDataRows),
+		m.TestDataCountInput.View(), m.TestDataCountInput.Focused()))
+	builder.WithContent(RenderInfoBox("All rows are inserted in one transaction and recorded in the audit log."))
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("enter") + ": insert rows • " +
+			styles.KeyStyle.Render("esc") + ": back to tables",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
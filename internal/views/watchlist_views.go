@@ -0,0 +1,43 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+)
+
+// WatchlistView renders the pinned rows with the fields that changed at the last refresh
+func WatchlistView(m models.Model) string {
+	builder := NewViewBuilder().WithTitle(fmt.Sprintf("👁 Watchlist (%d rows)", len(m.Watchlist)))
+
+	if m.IsRefreshingWatchlist {
+		builder.WithStatus("⏳ Refreshing watched rows...", StatusLoading)
+	} else if m.Err != nil {
+		builder.WithStatus("❌ "+m.Err.Error(), StatusError)
+	}
+
+	if len(m.Watchlist) == 0 {
+		builder.WithContent(RenderEmptyState("👁", "No rows watched yet. Press w on a row in the data preview or row details to pin it here."))
+	} else {
+		refresh := "refreshes on ctrl+r"
+		if m.WatchlistInterval > 0 {
+			refresh = "auto-refresh every " + m.WatchlistInterval.String()
+		}
+		builder.WithContent(
+			styles.SubtitleStyle.Render(refresh),
+			m.WatchlistTable.View(),
+			RenderInfoBox("✎ lists the fields that changed since the previous refresh."),
+		)
+	}
+
+	helpText := styles.HelpStyle.Render(
+		styles.KeyStyle.Render("↑/↓") + ": navigate • " +
+			styles.KeyStyle.Render("ctrl+r") + ": refresh • " +
+			styles.KeyStyle.Render("i") + ": auto-refresh interval • " +
+			styles.KeyStyle.Render("d") + ": stop watching • " +
+			styles.KeyStyle.Render("esc") + ": back",
+	)
+
+	return builder.WithHelp(helpText).Render()
+}
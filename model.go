@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dancaldera/mirador/internal/config"
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+func initialModel() models.Model {
+	// Database types list
+	items := make([]list.Item, len(models.SupportedDatabaseTypes))
+	for i, db := range models.SupportedDatabaseTypes {
+		items[i] = models.Item{
+			ItemTitle: db.Name,
+			ItemDesc:  fmt.Sprintf("Connect to %s database", db.Name),
+		}
+	}
+
+	dbList := list.New(items, styles.GetBlueListDelegate(), 0, 0)
+	dbList.Title = fmt.Sprintf("DBX — Database Explorer %s", version)
+	// Remove any default title background and apply our title style
+	ls := list.DefaultStyles()
+	ls.Title = styles.ListTitleStyle
+	ls.TitleBar = lipgloss.NewStyle()
+	dbList.Styles = ls
+	dbList.SetShowTitle(false) // Hide internal title, use ViewBuilder title instead
+	dbList.SetShowStatusBar(false)
+	dbList.SetFilteringEnabled(false)
+	dbList.SetShowHelp(false)
+
+	// Load saved connections
+	savedConnections, _ := config.LoadSavedConnections()
+
+	// Load query history
+	queryHistory, _ := config.LoadQueryHistory()
+
+	// Saved connections list
+	savedConnectionsList := list.New([]list.Item{}, styles.GetBlueListDelegate(), 0, 0)
+	savedConnectionsList.Title = "Saved Connections"
+	scLS := list.DefaultStyles()
+	scLS.Title = styles.ListTitleStyle
+	scLS.TitleBar = lipgloss.NewStyle()
+	savedConnectionsList.Styles = scLS
+	savedConnectionsList.SetShowTitle(false) // Hide internal title, use ViewBuilder title instead
+	savedConnectionsList.SetShowStatusBar(false)
+	savedConnectionsList.SetFilteringEnabled(false)
+	savedConnectionsList.SetShowHelp(false)
+
+	// Populate the list with saved connections
+	savedConnectionsList.SetItems(utils.SavedConnectionsItems(savedConnections, nil, "", ""))
+
+	// Connection input
+	ti := textinput.New()
+	ti.Placeholder = "Enter connection string..."
+	ti.Focus()
+	ti.CharLimit = 500
+	ti.Width = 80
+
+	// Connection name input
+	ni := textinput.New()
+	ni.Placeholder = "Name for this connection..."
+	ni.CharLimit = 100
+	ni.Width = 80
+
+	// Optional inputs of the connection form: read replica, TLS certificate files, and Cloud SQL instance
+	optionalInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.CharLimit = 500
+		input.Width = 80
+		return input
+	}
+
+	// Read replica input of the connection form
+	ri := optionalInput("Read replica connection string (optional)...")
+
+	// SQLCipher key input of the connection form
+	ki := optionalInput("key of a SQLCipher-encrypted file (optional)")
+	ki.EchoMode = textinput.EchoPassword
+	ki.EchoCharacter = '•'
+
+	// Builder fields of the connection form
+	builderInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.CharLimit = 200
+		input.Width = 40
+		return input
+	}
+	bpi := builderInput("password")
+	bpi.EchoMode = textinput.EchoPassword
+	bpi.EchoCharacter = '•'
+
+	// Query editor; enter runs the query, so alt+enter starts a new line
+	qi := textarea.New()
+	qi.Placeholder = "Enter SQL query (e.g., SELECT * FROM table_name LIMIT 10)..."
+	qi.SetWidth(80) // Will be dynamically resized
+	qi.MaxHeight = config.MaxEditorHeight
+	qi.SetHeight(config.EditorHeight())
+	qi.KeyMap.InsertNewline.SetKeys("alt+enter")
+
+	// Search input
+	si := textinput.New()
+	si.Placeholder = "Type to search..."
+	si.CharLimit = 100
+	si.Width = 80
+
+	// Tables list (compact: names only, no extra spacing)
+	tblDelegate := styles.GetBlueListDelegate()
+	tblDelegate.ShowDescription = false
+	tblDelegate.SetSpacing(0)
+	tablesList := list.New([]list.Item{}, tblDelegate, 0, 0)
+	tablesList.Title = "Available Tables"
+	tblLS := list.DefaultStyles()
+	tblLS.Title = styles.ListTitleStyle
+	tblLS.TitleBar = lipgloss.NewStyle()
+	tablesList.Styles = tblLS
+	tablesList.SetShowTitle(false) // Hide internal title, use ViewBuilder title instead
+	tablesList.SetShowStatusBar(false)
+	tablesList.SetFilteringEnabled(false)
+	tablesList.SetShowHelp(false)
+
+	// Schemas list
+	schemasList := list.New([]list.Item{}, styles.GetBlueListDelegate(), 0, 0)
+	schemasList.Title = "Schemas"
+	schLS := list.DefaultStyles()
+	schLS.Title = styles.ListTitleStyle
+	schLS.TitleBar = lipgloss.NewStyle()
+	schemasList.Styles = schLS
+	schemasList.SetShowTitle(false) // Hide internal title, use ViewBuilder title instead
+	schemasList.SetShowStatusBar(false)
+	schemasList.SetFilteringEnabled(false)
+	schemasList.SetShowHelp(false)
+
+	// Query history list
+	queryHistoryList := list.New([]list.Item{}, styles.GetBlueListDelegate(), 0, 0)
+	queryHistoryList.Title = "Query History"
+	qhLS := list.DefaultStyles()
+	qhLS.Title = styles.ListTitleStyle
+	qhLS.TitleBar = lipgloss.NewStyle()
+	queryHistoryList.Styles = qhLS
+	queryHistoryList.SetShowTitle(false) // Hide internal title, use ViewBuilder title instead
+	queryHistoryList.SetShowStatusBar(false)
+	queryHistoryList.SetFilteringEnabled(false)
+	queryHistoryList.SetShowHelp(false)
+
+	// Populate query history list items
+	queryHistoryList.SetItems(utils.QueryHistoryItems(queryHistory))
+
+	// Columns table
+	t := table.New(
+		table.WithColumns(utils.ColumnsTableColumns(false)),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	t.SetStyles(styles.GetBlueTableStyles())
+
+	// Query results table
+	queryResultsTable := table.New(
+		table.WithColumns([]table.Column{}),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+	queryResultsTable.SetStyles(styles.GetBlueTableStyles())
+
+	// Initialize textarea for field editing
+	ta := textarea.New()
+	ta.Placeholder = "Enter field content..."
+	ta.SetWidth(100) // Will be dynamically resized
+	ta.SetHeight(20) // Will be dynamically resized
+	ta.ShowLineNumbers = true
+
+	// Initialize filter input
+	filterInput := textinput.New()
+	filterInput.Placeholder = "Type to filter all columns..."
+	filterInput.Width = 60
+
+	// Initialize generated row count input
+	countInput := textinput.New()
+	countInput.Placeholder = "100"
+	countInput.CharLimit = 5
+	countInput.Width = 10
+
+	// Initialize typed confirmation input for TRUNCATE/DROP
+	ci := textinput.New()
+	ci.Placeholder = "table name"
+	ci.Width = 40
+
+	// Initialize target table input for copying table data
+	cti := textinput.New()
+	cti.Placeholder = "table or schema.table"
+	cti.Width = 40
+
+	// Initialize the note editor input
+	nti := textinput.New()
+	nti.Placeholder = "e.g. append-only; never update"
+	nti.CharLimit = 500
+	nti.Width = 60
+
+	// Initialize the master passphrase inputs
+	passphraseInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '•'
+		input.CharLimit = 200
+		input.Width = 40
+		return input
+	}
+
+	// Initialize the saved connections search and the group and tags inputs
+	plainInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.CharLimit = 100
+		input.Width = 40
+		return input
+	}
+
+	// Initialize the table input for checksum comparisons
+	cmi := textinput.New()
+	cmi.Placeholder = "table or schema.table"
+	cmi.Width = 40
+
+	m := models.Model{
+		Version:                 version,
+		State:                   models.DBTypeView,
+		DBTypeList:              dbList,
+		SavedConnectionsList:    savedConnectionsList,
+		TextInput:               ti,
+		NameInput:               ni,
+		ReplicaInput:            ri,
+		TLSCAInput:              optionalInput("/path/to/ca.pem (optional)"),
+		TLSCertInput:            optionalInput("/path/to/client-cert.pem (optional)"),
+		TLSKeyInput:             optionalInput("/path/to/client-key.pem (optional)"),
+		SQLCipherKeyInput:       ki,
+		ServiceAccountKeyInput:  optionalInput("/path/to/service-account-key.json (optional, default credentials otherwise)"),
+		CloudSQLInstanceInput:   optionalInput("project:region:instance (optional, connects through the Cloud SQL connector)"),
+		BuilderHostInput:        builderInput("localhost"),
+		BuilderPortInput:        builderInput("5432"),
+		BuilderUserInput:        builderInput("user"),
+		BuilderPasswordInput:    bpi,
+		BuilderDatabaseInput:    builderInput("dbname"),
+		BuilderOptionsInput:     builderInput("key=value&key=value (optional)"),
+		BuilderWarehouseInput:   builderInput("COMPUTE_WH (optional)"),
+		BuilderRoleInput:        builderInput("role (optional)"),
+		QueryInput:              qi,
+		SearchInput:             si,
+		TablesList:              tablesList,
+		SchemasList:             schemasList,
+		ColumnsTable:            t,
+		QueryResultsTable:       queryResultsTable,
+		SelectedSchema:          "public", // Default to public schema for PostgreSQL
+		SavedConnections:        savedConnections,
+		QueryHistory:            queryHistory,
+		QueryHistoryList:        queryHistoryList,
+		EditingConnectionIdx:    -1,
+		FullTextItemsPerPage:    5,           // Show 5 fields per page in full text view
+		FieldDetailLinesPerPage: 25,          // Show 25 lines per page in field detail view
+		FieldDetailCharsPerLine: 120,         // Show 120 characters per line in field detail view
+		FieldTextarea:           ta,          // Initialize textarea for field editing
+		DataPreviewCurrentPage:  0,           // Start at first page
+		DataPreviewItemsPerPage: 40,          // Show 40 items per page
+		DataPreviewTotalRows:    0,           // Will be set when loading data
+		DataPreviewScrollOffset: 0,           // Start at first column
+		DataPreviewVisibleCols:  6,           // Show 6 columns at once
+		DataPreviewFilterActive: false,       // Start without filter
+		DataPreviewFilterValue:  "",          // No initial filter
+		DataPreviewFilterInput:  filterInput, // Filter input component
+		TestDataCountInput:      countInput,  // Generated row count input
+		DestructiveConfirmInput: ci,          // Typed TRUNCATE/DROP confirmation
+		CopyTableInput:          cti,         // Target table for copying table data
+		CompareTableInput:       cmi,         // Table to checksum against
+		NoteInput:               nti,         // Table and connection notes
+		PassphraseInput:         passphraseInput("master passphrase"),
+		PassphraseConfirmInput:  passphraseInput("repeat the passphrase"),
+		ConnectionSearchInput:   plainInput("name, group, or #tag"),
+		ConnectionGroupInput:    plainInput("e.g. prod (empty for no group)"),
+		ConnectionTagsInput:     plainInput("e.g. eu, billing"),
+		DefaultSchemaInput:      plainInput("empty for the driver's default"),
+		DefaultPageSizeInput:    plainInput("40"),
+		DefaultSortInput:        plainInput("e.g. created_at desc, id"),
+		ConnectTimeoutInput:     plainInput("e.g. 5s (empty for the default, 0 for none)"),
+		StatementTimeoutInput:   plainInput("e.g. 2m (empty for the default, 0 for none)"),
+		JSONColumnInput:         plainInput("e.g. payload->>'status' or payload.user.id as user_id"),
+		NotifyChannelInput:      plainInput("e.g. orders, jobs_done"),
+	}
+
+	// Encrypted saved connections are unlocked before anything else is shown
+	if config.HasEncryptedConnections(savedConnections) {
+		m.LockedConnections = savedConnections
+		m.SavedConnections = nil
+		m.SavedConnectionsList.SetItems(nil)
+		m = utils.StartPassphraseEntry(m, false)
+	}
+
+	return m
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/utils"
+)
+
+// updateResult hands the result of a finished command to its handler,
+// reporting whether msg was one
+func (m appModel) updateResult(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case models.ConnectResult:
+		updatedModel, cmd := utils.HandleConnectResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ReplicaConnectResult:
+		updatedModel, cmd := utils.HandleReplicaConnectResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TestConnectionResult:
+		updatedModel, cmd := utils.HandleTestConnectionResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ConnectRetryTickMsg:
+		updatedModel, cmd := utils.HandleConnectRetryTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ReconnectResult:
+		updatedModel, cmd := utils.HandleReconnectResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ReconnectTickMsg:
+		updatedModel, cmd := utils.HandleReconnectTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.PingTickMsg:
+		updatedModel, cmd := utils.HandlePingTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.PingResult:
+		updatedModel, cmd := utils.HandlePingResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.CompletionColumnsResult:
+		updatedModel, cmd := utils.HandleCompletionColumnsResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.WorksheetTickMsg:
+		updatedModel, cmd := utils.HandleWorksheetTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.SchemasResult:
+		updatedModel, cmd := utils.HandleSchemasResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TablesResult:
+		updatedModel, cmd := utils.HandleTablesResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ColumnsResult:
+		updatedModel, cmd := utils.HandleColumnsResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.DataPreviewResult:
+		updatedModel, cmd := utils.HandleDataPreviewResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.RelationshipsResult:
+		updatedModel, cmd := utils.HandleRelationshipsResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.FieldUpdateResult:
+		updatedModel, cmd := utils.HandleFieldUpdateResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.RowRefreshResult:
+		updatedModel, cmd := utils.HandleRowRefreshResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.DatabaseOverviewResult:
+		updatedModel, cmd := utils.HandleDatabaseOverviewResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.SlowQueriesResult:
+		updatedModel, cmd := utils.HandleSlowQueriesResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ServerSettingsResult:
+		updatedModel, cmd := utils.HandleServerSettingsResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TableGrowthResult:
+		updatedModel, cmd := utils.HandleTableGrowthResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.FilterCountTickMsg:
+		updatedModel, cmd := utils.HandleFilterCountTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.FilterCountResult:
+		updatedModel, cmd := utils.HandleFilterCountResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ConnectionHealthResult:
+		updatedModel, cmd := utils.HandleConnectionHealthResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.UnlockConnectionsResult:
+		updatedModel, cmd := utils.HandleUnlockConnectionsResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.PassphraseSetResult:
+		updatedModel, cmd := utils.HandlePassphraseSetResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.QueryEstimateResult:
+		updatedModel, cmd := utils.HandleQueryEstimateResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.WatchlistRefreshResult:
+		updatedModel, cmd := utils.HandleWatchlistRefreshResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.WatchlistTickMsg:
+		updatedModel, cmd := utils.HandleWatchlistTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.RowHistoryResult:
+		updatedModel, cmd := utils.HandleRowHistoryResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TimeSeriesResult:
+		updatedModel, cmd := utils.HandleTimeSeriesResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.GroupSummaryResult:
+		updatedModel, cmd := utils.HandleGroupSummaryResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ColumnAggregateResult:
+		updatedModel, cmd := utils.HandleColumnAggregateResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.AuditLogResult:
+		updatedModel, cmd := utils.HandleAuditLogResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.DestructiveResult:
+		updatedModel, cmd := utils.HandleDestructiveResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.CompareResult:
+		updatedModel, cmd := utils.HandleCompareResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.NotificationMsg:
+		updatedModel, cmd := utils.HandleNotification(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.CopyProgressMsg:
+		updatedModel, cmd := utils.HandleCopyProgress(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TestDataPlanResult:
+		updatedModel, cmd := utils.HandleTestDataPlanResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TestDataInsertResult:
+		updatedModel, cmd := utils.HandleTestDataInsertResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.TableSnapshotTickMsg:
+		updatedModel, cmd := utils.HandleTableSnapshotTick(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.MaintenanceResult:
+		updatedModel, cmd := utils.HandleMaintenanceResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.MaintenanceTickMsg:
+		// Keep ticking so the elapsed time stays current while the action runs
+		if m.IsRunningMaintenance {
+			return m, utils.MaintenanceTick(), true
+		}
+		return m, nil, true
+	case models.TempResultMsg:
+		updatedModel, cmd := utils.HandleTempResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.QueryResultMsg:
+		m.Model = utils.HandleQueryResult(m.Model, msg)
+		return m, utils.QueryErrorHook(m.Model, msg), true
+	case models.ExportResult:
+		updatedModel, cmd := utils.HandleExportResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.HookResult:
+		updatedModel, cmd := utils.HandleHookResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.StatementCancelResult:
+		updatedModel, cmd := utils.HandleStatementCancelResult(m.Model, msg)
+		m.Model = updatedModel
+		return m, cmd, true
+	case models.ClearResultMsg:
+		m.QueryResult = ""
+		return m, nil, true
+	case models.ClearErrorMsg:
+		m.Err = nil
+		m.ErrorTimeout = nil
+		return m, nil, true
+	case models.ErrorTimeoutMsg:
+		updatedModel := utils.ClearErrorTimeout(m.Model)
+		m.Model = updatedModel
+		return m, nil, true
+	}
+	return m, nil, false
+}
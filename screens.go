@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dancaldera/mirador/internal/models"
+	"github.com/dancaldera/mirador/internal/state"
+	"github.com/dancaldera/mirador/internal/styles"
+	"github.com/dancaldera/mirador/internal/utils"
+	"github.com/dancaldera/mirador/internal/views"
+)
+
+// screen is how one view state is updated and rendered. A new view is added by
+// registering it in screens; opening and leaving it goes through utils.PushView and
+// utils.PopView.
+type screen struct {
+	update func(models.Model, tea.Msg) (models.Model, tea.Cmd)
+	view   func(models.Model) string
+	// typing reports whether the screen is taking free text, in which case every
+	// key but ctrl+c goes to it instead of the global shortcuts
+	typing func(models.Model) bool
+}
+
+// always is a typing func for screens that only take free text
+func always(models.Model) bool { return true }
+
+var screens = map[models.ViewState]screen{
+	models.DBTypeView:           {update: state.HandleDBTypeViewUpdate, view: views.DBTypeView},
+	models.SavedConnectionsView: {update: state.HandleSavedConnectionsViewUpdate, view: views.SavedConnectionsView, typing: func(m models.Model) bool { return m.IsSearchingConnections }},
+	models.ConnectionView:       {update: state.HandleConnectionViewUpdate, view: views.ConnectionView},
+	models.SaveConnectionView:   {update: state.HandleSaveConnectionViewUpdate, view: views.SaveConnectionView},
+	models.EditConnectionView:   {update: state.HandleEditConnectionViewUpdate, view: views.EditConnectionView, typing: always},
+	models.SchemaView:           {update: state.HandleSchemaViewUpdate, view: views.SchemaView},
+	models.TablesView:           {update: state.HandleTablesViewUpdate, view: views.TablesView},
+	models.ColumnsView:          {update: state.HandleColumnsViewUpdate, view: views.ColumnsView, typing: func(m models.Model) bool { return m.IsSearchingColumns }},
+	models.QueryView:            {update: state.HandleQueryViewUpdate, view: views.QueryView},
+	models.QueryHistoryView:     {update: state.HandleQueryHistoryViewUpdate, view: views.QueryHistoryView},
+	models.DataPreviewView:      {update: updateDataPreview, view: views.DataPreviewView, typing: func(m models.Model) bool { return m.JSONColumnActive }},
+	models.RowDetailView:        {update: state.HandleRowDetailViewUpdate, view: views.RowDetailView, typing: func(m models.Model) bool { return m.IsSearchingFields }},
+	models.RelationshipsView:    {update: state.HandleRelationshipsViewUpdate, view: views.RelationshipsView},
+	models.DatabaseOverviewView: {update: state.HandleDatabaseOverviewViewUpdate, view: views.DatabaseOverviewView},
+	models.SlowQueryView:        {update: state.HandleSlowQueryViewUpdate, view: views.SlowQueryView},
+	models.ServerSettingsView:   {update: state.HandleServerSettingsViewUpdate, view: views.ServerSettingsView},
+	models.TableGrowthView:      {update: state.HandleTableGrowthViewUpdate, view: views.TableGrowthView},
+	models.AuditLogView:         {update: state.HandleAuditLogViewUpdate, view: views.AuditLogView},
+	models.GenerateDataView:     {update: state.HandleGenerateDataViewUpdate, view: views.GenerateDataView},
+	models.CopyDataView:         {update: state.HandleCopyDataViewUpdate, view: views.CopyDataView},
+	models.CompareTablesView:    {update: state.HandleCompareTablesViewUpdate, view: views.CompareTablesView},
+	models.NoteEditView:         {update: state.HandleNoteEditViewUpdate, view: views.NoteEditView, typing: always},
+	models.ConnectionHealthView: {update: state.HandleConnectionHealthViewUpdate, view: views.ConnectionHealthView},
+	models.RowHistoryView:       {update: state.HandleRowHistoryViewUpdate, view: views.RowHistoryView},
+	// At startup nothing but the passphrase prompt is reachable
+	models.PassphraseView:         {update: state.HandlePassphraseViewUpdate, view: views.PassphraseView, typing: always},
+	models.ChartView:              {update: state.HandleChartViewUpdate, view: views.ChartView},
+	models.GroupSummaryView:       {update: state.HandleGroupSummaryViewUpdate, view: views.GroupSummaryView},
+	models.ConnectionGroupView:    {update: state.HandleConnectionGroupViewUpdate, view: views.ConnectionGroupView, typing: always},
+	models.RowDiffView:            {update: state.HandleRowDiffViewUpdate, view: views.RowDiffView},
+	models.WatchlistView:          {update: state.HandleWatchlistViewUpdate, view: views.WatchlistView},
+	models.ConnectionDefaultsView: {update: state.HandleConnectionDefaultsViewUpdate, view: views.ConnectionDefaultsView, typing: always},
+	models.NotifyView:             {update: state.HandleNotifyViewUpdate, view: views.NotifyView, typing: func(m models.Model) bool { return m.NotifyChannelInput.Focused() }},
+}
+
+// updateDataPreview opens the row detail view on enter and leaves everything
+// else to the data preview handler. Entering the row detail lives here to avoid
+// a dependency cycle with the state package's field delegate.
+func updateDataPreview(m models.Model, msg tea.Msg) (models.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	// In sort mode, filter mode, or the JSON path prompt, enter belongs to the state handler
+	if !ok || keyMsg.String() != "enter" || m.DataPreviewSortMode || m.DataPreviewFilterActive || m.JSONColumnActive {
+		return state.HandleDataPreviewViewUpdate(m, msg)
+	}
+
+	// Enter row detail view
+	selectedRow := m.DataPreviewTable.Cursor()
+	if len(m.DataPreviewAllRows) == 0 || selectedRow < 0 || selectedRow >= len(m.DataPreviewAllRows) {
+		return m, nil
+	}
+	// Calculate the actual row index based on current page and table position
+	actualRowIndex := (m.DataPreviewCurrentPage * m.DataPreviewItemsPerPage) + selectedRow
+	if actualRowIndex >= len(m.DataPreviewAllRows) {
+		return m, nil
+	}
+	m.SelectedRowData = m.DataPreviewAllRows[selectedRow] // Use the displayed row
+	m.SelectedRowIndex = actualRowIndex                   // Track the actual position in the dataset
+	m.SelectedRowNulls = nil
+	if selectedRow < len(m.DataPreviewNulls) {
+		m.SelectedRowNulls = m.DataPreviewNulls[selectedRow]
+	}
+
+	// Create list items for each field
+	items := utils.UpdateRowDetailList(m.DataPreviewAllColumns, m.DataPreviewColumnTypes, m.SelectedRowData, m.SelectedRowNulls, m.DisplayTimezone)
+
+	// Initialize the row detail list (full-width/height)
+	// Use custom delegate to show type badges aligned right
+	m.RowDetailList = list.New(items, state.FieldItemDelegate{}, 0, 0)
+	// Keep the outer view title; hide internal list title for cleaner look
+	m.RowDetailList.Title = ""
+	m.RowDetailList.SetShowTitle(false)
+	m.RowDetailList.SetShowStatusBar(false)
+	m.RowDetailList.SetFilteringEnabled(false)
+	// Hide built-in help to avoid duplicate help sections
+	m.RowDetailList.SetShowHelp(false)
+	// Size the list to available viewport using consistent height calculation
+	h, _ := styles.DocStyle.GetFrameSize()
+	listHeight := utils.CalculateListViewportHeight(m.Height, true, m.Err != nil || m.QueryResult != "")
+	m.RowDetailList.SetSize(m.Width-h, listHeight)
+	m.IsViewingFieldDetail = false
+	m.IsSearchingFields = false
+	m.SearchInput.SetValue("")
+
+	return utils.PushView(m, models.RowDetailView, utils.TableParams(m)), nil
+}